@@ -695,6 +695,8 @@ func makeAlterBackupScheduleSpec(
 		case *tree.AlterBackupScheduleSetWith:
 			if typedCmd.With.Detached != nil {
 				err = errors.Newf("DETACHED is required for scheduled backups and cannot be altered")
+			} else if typedCmd.With.Continuous != nil {
+				err = errors.Newf("the continuous option cannot be used with backup schedules")
 			} else {
 				err = spec.backupOptions.CombineWith(typedCmd.With)
 			}
@@ -555,6 +555,13 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 	if initialDetails.Compact {
 		return b.ResumeCompaction(ctx, initialDetails, p, &kmsEnv)
 	}
+	// Once the first layer of a continuous backup has been written, resuming
+	// the job resumes streaming changes on top of it.
+	if initialDetails.Continuous {
+		if progress := b.job.Progress(); !progress.GetBackup().ContinuousCheckpoint.IsEmpty() {
+			return b.runContinuousBackup(ctx, p, initialDetails, &kmsEnv)
+		}
+	}
 	// Resolve the backup destination. We can skip this step if we
 	// have already resolved and persisted the destination either
 	// during a previous resumption of this job.
@@ -799,7 +806,10 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		return err
 	}
 
-	if details.ProtectedTimestampRecord != nil && !b.testingKnobs.ignoreProtectedTimestamps {
+	// A continuous backup keeps its protected timestamp record, advancing it as
+	// it streams changes, until the job is canceled.
+	if details.ProtectedTimestampRecord != nil && !b.testingKnobs.ignoreProtectedTimestamps &&
+		!details.Continuous {
 		if err := p.ExecCfg().InternalDB.Txn(ctx, func(
 			ctx context.Context, txn isql.Txn,
 		) error {
@@ -844,6 +854,21 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 
 	b.backupStats = res
 
+	if details.Continuous {
+		if err := b.job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			if err := md.CheckRunningOrReverting(); err != nil {
+				return err
+			}
+			md.Progress.GetBackup().ContinuousCheckpoint = backupManifest.EndTime
+			md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &backupManifest.EndTime}
+			ju.UpdateProgress(md.Progress)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return b.runContinuousBackup(ctx, p, details, &kmsEnv)
+	}
+
 	// Collect telemetry.
 	{
 		telemetry.Count("backup.total.succeeded")
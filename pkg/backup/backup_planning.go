@@ -76,6 +76,7 @@ func resolveOptionsForBackupJobDescription(
 		Detached:                        opts.Detached,
 		ExecutionLocality:               opts.ExecutionLocality,
 		UpdatesClusterMonitoringMetrics: opts.UpdatesClusterMonitoringMetrics,
+		Continuous:                      opts.Continuous,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -417,6 +418,7 @@ func backupPlanHook(
 	}
 
 	detached := backupStmt.Options.Detached == tree.DBoolTrue
+	continuous := backupStmt.Options.Continuous == tree.DBoolTrue
 
 	exprEval := p.ExprEvaluator("BACKUP")
 
@@ -533,6 +535,12 @@ func backupPlanHook(
 			return errors.New("the include_all_virtual_clusters option is only supported for full cluster backups")
 		}
 
+		if continuous {
+			if err := checkContinuousBackupOptions(p, backupStmt, detached, to); err != nil {
+				return err
+			}
+		}
+
 		var asOfInterval int64
 		endTime := p.ExecCfg().Clock.Now()
 		if backupStmt.AsOf.Expr != nil {
@@ -608,6 +616,7 @@ func backupPlanHook(
 			ApplicationName:                 p.SessionData().ApplicationName,
 			ExecutionLocality:               executionLocality,
 			UpdatesClusterMonitoringMetrics: updatesClusterMonitoringMetrics,
+			Continuous:                      continuous,
 		}
 		if backupStmt.CreatedByInfo != nil {
 			initialDetails.ScheduleID = backupStmt.CreatedByInfo.ScheduleID()
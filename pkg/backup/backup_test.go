@@ -50,6 +50,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
 	"github.com/cockroachdb/cockroach/pkg/cloud/gcp"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/impl" // register cloud storage providers
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip/kmiptestutils"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -4432,6 +4433,49 @@ func TestRegionalKMSEncryptedBackup(t *testing.T) {
 	})
 }
 
+// TestKMIPEncryptedBackup performs encrypted BACKUPs using two keys held by a
+// KMIP server, one of which is rotated between the full and the incremental
+// BACKUP, and then RESTOREs using each key separately.
+func TestKMIPEncryptedBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv := kmiptestutils.NewTestServer(t)
+	defer srv.Close()
+	srv.CreateKey("backup-key-a")
+	srv.CreateKey("backup-key-b")
+	kmsURIs := []string{srv.URI("backup-key-a"), srv.URI("backup-key-b")}
+
+	_, sqlDB, rawDir, cleanupFn := backupRestoreTestSetup(t, singleNode, 3, InitManualReplication)
+	defer cleanupFn()
+
+	setupBackupEncryptedTest(ctx, t, sqlDB)
+
+	backupLoc := localFoo + "/kmip"
+	sqlDB.Exec(t, fmt.Sprintf(`BACKUP INTO $1 WITH %s`, concatMultiRegionKMSURIs(kmsURIs)), backupLoc)
+
+	// Rotating a key on the KMIP server does not prevent it from being used for
+	// subsequent incremental backups, or for restoring existing ones.
+	srv.Rotate("backup-key-a")
+	sqlDB.Exec(t, `UPDATE neverappears.neverappears SET other = 'neverappears'`)
+	sqlDB.Exec(t, fmt.Sprintf(`BACKUP INTO LATEST IN $1 WITH %s`, concatMultiRegionKMSURIs(kmsURIs)), backupLoc)
+
+	checkBackupFilesEncrypted(t, rawDir)
+	before := sqlDB.QueryStr(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE neverappears.neverappears`)
+
+	for _, uri := range kmsURIs {
+		sqlDB.Exec(t, `DROP DATABASE neverappears CASCADE`)
+		sqlDB.Exec(t, fmt.Sprintf(`RESTORE DATABASE neverappears FROM LATEST IN $1 WITH KMS='%s'`, uri),
+			backupLoc)
+		sqlDB.CheckQueryResults(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE neverappears.neverappears`, before)
+	}
+
+	srv.CreateKey("unrelated-key")
+	sqlDB.ExpectErr(t, `one of the provided URIs was not used when encrypting the base BACKUP`,
+		fmt.Sprintf(`SHOW BACKUP FROM LATEST IN $1 WITH KMS='%s'`, srv.URI("unrelated-key")), backupLoc)
+}
+
 type testKMSEnv struct {
 	settings         *cluster.Settings
 	externalIOConfig *base.ExternalIODirConfig
@@ -0,0 +1,275 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backupinfo
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupsink"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// scanBatchSize is the number of KVs read from the backup by ScanBackupTable
+// before they are decoded into rows.
+const scanBatchSize = 1024
+
+// BackupTableColumns returns the columns of a table that ScanBackupTable
+// returns: the public columns that can be decoded from the primary index of
+// the table.
+func BackupTableColumns(table catalog.TableDescriptor) []catalog.Column {
+	var cols []catalog.Column
+	for _, col := range table.PublicColumns() {
+		if col.IsInaccessible() {
+			continue
+		}
+		if col.IsVirtual() && !table.GetPrimaryIndex().CollectKeyColumnIDs().Contains(col.GetID()) {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// ScanBackupTable reads the primary index of the table from the files of every
+// layer of the backup chain, merging the layers as a RESTORE would, and calls
+// fn with each row as of readTime, in primary key order. The columns of the
+// rows are those returned by BackupTableColumns.
+//
+// If spans is set, only the rows within those spans of the primary index are
+// read, and only the files of the backup that overlap them are opened. The
+// spans must be sorted, non-overlapping and within the primary index of the
+// table.
+func ScanBackupTable(
+	ctx context.Context,
+	makeStore cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+	manifests []backuppb.BackupManifest,
+	layerToIterFactory LayerToBackupManifestFileIterFactory,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	encryption *jobspb.BackupEncryptionOptions,
+	kmsEnv cloud.KMSEnv,
+	table catalog.TableDescriptor,
+	spans roachpb.Spans,
+	readTime hlc.Timestamp,
+	fn func(tree.Datums) error,
+) error {
+	codec, err := MakeBackupCodec(manifests)
+	if err != nil {
+		return err
+	}
+	indexSpan := table.PrimaryIndexSpan(codec)
+	if len(spans) == 0 {
+		spans = roachpb.Spans{indexSpan}
+	}
+	for _, sp := range spans {
+		if !indexSpan.Contains(sp) {
+			return errors.AssertionFailedf("span %s is not within the primary index %s", sp, indexSpan)
+		}
+	}
+
+	var stores []cloud.ExternalStorage
+	defer func() {
+		for _, store := range stores {
+			if err := store.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	var storeFiles []storageccl.StoreFile
+	addLayerFiles := func(layer int) error {
+		// The files of a layer are read from the directory of the layer, or from
+		// the directory of their locality if the backup is locality aware.
+		storesByLocality := make(map[string]cloud.ExternalStorage)
+		openStore := func(localityKV string) (cloud.ExternalStorage, error) {
+			if store, ok := storesByLocality[localityKV]; ok {
+				return store, nil
+			}
+			dir := manifests[layer].Dir
+			if uri, ok := localityInfo[layer].URIsByOriginalLocalityKV[localityKV]; ok {
+				conf, err := cloud.ExternalStorageConfFromURI(uri, user)
+				if err != nil {
+					return nil, errors.Wrap(err, "creating locality external storage configuration")
+				}
+				conf.URI = uri
+				dir = conf
+			}
+			store, err := makeStore(ctx, dir)
+			if err != nil {
+				return nil, err
+			}
+			stores = append(stores, store)
+			storesByLocality[localityKV] = store
+			return store, nil
+		}
+
+		// The files of a layer are not assumed to be sorted by start key, so
+		// every entry of the file index is checked for overlap.
+		it, err := layerToIterFactory[layer].NewFileIter(ctx)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+		for ; ; it.Next() {
+			if ok, err := it.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			f := it.Value()
+			if !slices.ContainsFunc(spans, f.Span.Overlaps) {
+				continue
+			}
+			store, err := openStore(f.LocalityKV)
+			if err != nil {
+				return err
+			}
+			storeFiles = append(storeFiles, storageccl.StoreFile{Store: store, FilePath: f.Path})
+		}
+	}
+	for layer := range manifests {
+		if err := addLayerFiles(layer); err != nil {
+			return err
+		}
+	}
+	if len(storeFiles) == 0 {
+		return nil
+	}
+
+	var encOpts *kvpb.FileEncryptionOptions
+	if encryption != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, encryption, kmsEnv)
+		if err != nil {
+			return err
+		}
+		encOpts = &kvpb.FileEncryptionOptions{Key: key}
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, encOpts, storage.IterOptions{
+		RangeKeyMaskingBelow: readTime,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	})
+	if err != nil {
+		return err
+	}
+	readAsOfIter := storage.NewReadAsOfIterator(iter, readTime)
+	defer readAsOfIter.Close()
+
+	elidedPrefix, err := backupsink.ElidedPrefix(indexSpan.Key, manifests[0].ElidedPrefix)
+	if err != nil {
+		return err
+	}
+
+	cols := BackupTableColumns(table)
+	colIDs := make([]descpb.ColumnID, len(cols))
+	for i, col := range cols {
+		colIDs[i] = col.GetID()
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, codec, table, table.GetPrimaryIndex(), colIDs); err != nil {
+		return err
+	}
+	var fetcher row.Fetcher
+	if err := fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &tree.DatumAlloc{},
+		Spec:              &spec,
+	}); err != nil {
+		return err
+	}
+	defer fetcher.Close(ctx)
+
+	var kvs []roachpb.KeyValue
+	flush := func() error {
+		if len(kvs) == 0 {
+			return nil
+		}
+		if err := fetcher.ConsumeKVProvider(ctx, &row.KVProvider{KVs: kvs}); err != nil {
+			return err
+		}
+		for {
+			datums, _, err := fetcher.NextRowDecoded(ctx)
+			if err != nil {
+				return err
+			}
+			if datums == nil {
+				break
+			}
+			if err := fn(append(tree.Datums(nil), datums...)); err != nil {
+				return err
+			}
+		}
+		kvs = nil
+		return nil
+	}
+
+	var lastRowPrefix roachpb.Key
+	scanSpan := func(sp roachpb.Span) error {
+		startKey := storage.MVCCKey{Key: sp.Key}
+		if elidedPrefix != nil {
+			startKey.Key = bytes.TrimPrefix(startKey.Key, elidedPrefix)
+		}
+		for readAsOfIter.SeekGE(startKey); ; readAsOfIter.NextKey() {
+			if ok, err := readAsOfIter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			key := append(append(roachpb.Key(nil), elidedPrefix...), readAsOfIter.UnsafeKey().Key...)
+			if key.Compare(sp.EndKey) >= 0 {
+				return nil
+			}
+			v, err := readAsOfIter.UnsafeValue()
+			if err != nil {
+				return err
+			}
+			value, err := storage.DecodeValueFromMVCCValue(append([]byte(nil), v...))
+			if err != nil {
+				return err
+			}
+
+			// Rows are only decoded once all of their KVs have been read, so the
+			// batch is flushed at a row boundary.
+			prefixLen, err := keys.GetRowPrefixLength(key)
+			if err != nil {
+				return err
+			}
+			if len(kvs) >= scanBatchSize && !bytes.Equal(lastRowPrefix, key[:prefixLen]) {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			lastRowPrefix = key[:prefixLen]
+			kvs = append(kvs, roachpb.KeyValue{Key: key, Value: value})
+		}
+	}
+	for _, sp := range spans {
+		if err := scanSpan(sp); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
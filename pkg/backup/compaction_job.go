@@ -192,6 +192,31 @@ func StartCompactionJob(
 	if !ok {
 		return 0, errors.New("missing job execution context")
 	}
+	jobRecord, err := makeCompactionJobRecord(
+		scheduleID, collectionURI, incrLoc, fullBackupPath, encryptionOpts, start, end, planHook.User(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	jobID := planHook.ExecCfg().JobRegistry.MakeJobID()
+	if _, err := planHook.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+		ctx, jobRecord, jobID, planHook.InternalSQLTxn(),
+	); err != nil {
+		return 0, err
+	}
+	return jobID, nil
+}
+
+// makeCompactionJobRecord returns the record of a job that compacts the backups
+// at the collection URI within the start and end timestamps.
+func makeCompactionJobRecord(
+	scheduleID jobspb.ScheduleID,
+	collectionURI, incrLoc []string,
+	fullBackupPath string,
+	encryptionOpts jobspb.BackupEncryptionOptions,
+	start, end hlc.Timestamp,
+	user username.SQLUsername,
+) (jobs.Record, error) {
 	details := jobspb.BackupDetails{
 		ScheduleID: scheduleID,
 		StartTime:  start,
@@ -205,10 +230,9 @@ func StartCompactionJob(
 		EncryptionOptions: &encryptionOpts,
 		Compact:           true,
 	}
-	jobID := planHook.ExecCfg().JobRegistry.MakeJobID()
 	description, err := compactionJobDescription(details)
 	if err != nil {
-		return 0, err
+		return jobs.Record{}, err
 	}
 	// Note: We do not set the `CreatedBy` field in the job record to the schedule
 	// that created it because doing so creates a dependency between the record in
@@ -216,18 +240,12 @@ func StartCompactionJob(
 	// that until the compaction job completes, the `system.scheduled_jobs` record
 	// for backup would not be marked ready, which would block all future
 	// scheduled backups until the compaction completes.
-	jobRecord := jobs.Record{
+	return jobs.Record{
 		Description: description,
 		Details:     details,
 		Progress:    jobspb.BackupProgress{},
-		Username:    planHook.User(),
-	}
-	if _, err := planHook.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
-		ctx, jobRecord, jobID, planHook.InternalSQLTxn(),
-	); err != nil {
-		return 0, err
-	}
-	return jobID, nil
+		Username:    user,
+	}, nil
 }
 
 func (b *backupResumer) ResumeCompaction(
@@ -625,9 +643,13 @@ func (c compactionChain) createCompactionManifest(
 	// HasExternalManifestSSTs to false and allow it to be set to true later when
 	// it is written to storage.
 	cManifest.HasExternalManifestSSTs = false
-	// While we do not compaction on revision history backups, we still need to
-	// nil out DescriptorChanges to avoid the placeholder descriptor from the
-	// manifest of the last incremental.
+	// The compacted backup only retains the latest revision of each key as of
+	// its end time, so any revision history captured by the backups it compacts,
+	// such as the layers written by a continuous backup, is not carried over.
+	// We also need to nil out DescriptorChanges to avoid the placeholder
+	// descriptor from the manifest of the last incremental.
+	cManifest.MVCCFilter = backuppb.MVCCFilter_Latest
+	cManifest.RevisionStartTime = hlc.Timestamp{}
 	cManifest.DescriptorChanges = nil
 	cManifest.Files = nil
 	cManifest.EntryCounts = roachpb.RowCount{}
@@ -0,0 +1,853 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"sort"
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupresolver"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupsink"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/types"
+)
+
+var (
+	continuousBackupFlushInterval = settings.RegisterDurationSetting(
+		settings.ApplicationLevel,
+		"backup.continuous.flush_interval",
+		"the interval at which a continuous backup writes the changes it has streamed into a new backup layer",
+		10*time.Second,
+		settings.DurationWithMinimum(time.Second),
+	)
+
+	continuousBackupCompactionInterval = settings.RegisterDurationSetting(
+		settings.ApplicationLevel,
+		"backup.continuous.compaction_interval",
+		"the span of time covered by the layers of a continuous backup after which they are "+
+			"compacted into a single incremental backup (0 to disable compactions)",
+		time.Hour,
+		settings.DurationWithMinimumOrZeroDisable(time.Minute),
+	)
+
+	continuousBackupMaxBufferedBytes = settings.RegisterByteSizeSetting(
+		settings.ApplicationLevel,
+		"backup.continuous.max_buffered_bytes",
+		"the maximum size of the changes a continuous backup may buffer in memory before they "+
+			"are written to a backup layer; the job fails if it is exceeded",
+		256<<20, // 256 MiB
+		settings.PositiveInt,
+	)
+)
+
+// maxContinuousBackupFlushRetries is the number of consecutive times a
+// continuous backup may fail to write a layer before the job fails.
+const maxContinuousBackupFlushRetries = 10
+
+// checkContinuousBackupOptions validates that a BACKUP statement with the
+// continuous option only uses options supported by continuous backups.
+func checkContinuousBackupOptions(
+	p sql.PlanHookState, backupStmt *annotatedBackupStatement, detached bool, to []string,
+) error {
+	switch {
+	case !detached:
+		return errors.New("the continuous option requires the detached option")
+	case backupStmt.Coverage() == tree.AllDescriptors:
+		return errors.New("the continuous option is not supported for cluster backups")
+	case len(to) > 1:
+		return errors.New("the continuous option is not supported for locality aware backups")
+	case len(backupStmt.Options.IncrementalStorage) != 0:
+		return errors.New("the continuous option is not supported with incremental_location")
+	case !kvserver.RangefeedEnabled.Get(&p.ExecCfg().Settings.SV):
+		return errors.Errorf("continuous backups require that the %s cluster setting is enabled",
+			kvserver.RangefeedEnabled.Name())
+	}
+	return nil
+}
+
+// runContinuousBackup streams the changes made to the targets of a continuous
+// backup job into a new revision history layer of its backup chain every
+// backup.continuous.flush_interval, until the job is paused or canceled.
+//
+// Each layer starts at the end time of the last layer in the chain, so the
+// stream picks up where it left off when the job is resumed. After a layer is
+// written, its end time is recorded as the job's checkpoint and the job's
+// protected timestamp is advanced to it.
+func (b *backupResumer) runContinuousBackup(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	details jobspb.BackupDetails,
+	kmsEnv cloud.KMSEnv,
+) error {
+	s, err := newContinuousBackupStream(ctx, b.job, execCtx, details, kmsEnv)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+	log.Infof(ctx, "continuous backup streaming changes from %s", s.lastEnd())
+
+	var timer timeutil.Timer
+	defer timer.Stop()
+	var failures int
+	for {
+		timer.Reset(continuousBackupFlushInterval.Get(&s.execCfg.Settings.SV))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-s.errCh:
+			return errors.Wrap(err, "continuous backup rangefeed failed")
+		case <-timer.C:
+			timer.Read = true
+		}
+		if err := s.flush(ctx); err != nil {
+			if jobs.IsPermanentJobError(err) || ctx.Err() != nil {
+				return err
+			}
+			failures++
+			if failures >= maxContinuousBackupFlushRetries {
+				return errors.Wrap(err, "exhausted retries writing continuous backup layer")
+			}
+			log.Warningf(ctx, "failed to write continuous backup layer (attempt %d): %v", failures, err)
+			continue
+		}
+		failures = 0
+		if err := s.maybeCompact(ctx); err != nil {
+			log.Warningf(ctx, "failed to start compaction of continuous backup layers: %v", err)
+		}
+	}
+}
+
+// continuousBackupStream buffers the rangefeed events for the spans of a
+// continuous backup and writes them out as backup layers.
+type continuousBackupStream struct {
+	job     *jobs.Job
+	execCtx sql.JobExecContext
+	execCfg *sql.ExecutorConfig
+	details jobspb.BackupDetails
+	kmsEnv  cloud.KMSEnv
+
+	// encryption is the resolved encryption of the backup chain, and
+	// fileEncryption the options used to encrypt the data files of new layers.
+	encryption     *jobspb.BackupEncryptionOptions
+	fileEncryption *kvpb.FileEncryptionOptions
+
+	// prevBackups is the backup chain the stream appends to, stripped of its
+	// files and, except for the last layer, its descriptors.
+	prevBackups []backuppb.BackupManifest
+	lastIters   backupinfo.LayerToBackupManifestFileIterFactory
+
+	// spans are the spans watched by the rangefeed.
+	spans roachpb.SpanGroup
+	feed  *rangefeed.RangeFeed
+	errCh chan error
+
+	// mon limits the memory used to buffer rangefeed events to
+	// backup.continuous.max_buffered_bytes.
+	mon *mon.BytesMonitor
+
+	// compactionJobID is the last compaction job started by the stream.
+	compactionJobID jobspb.JobID
+
+	mu struct {
+		syncutil.Mutex
+		// gen is incremented every time the rangefeed is restarted, so events
+		// delivered by a previous rangefeed can be ignored.
+		gen       int
+		frontier  hlc.Timestamp
+		points    []storage.MVCCKeyValue
+		rangeKeys []storage.MVCCRangeKey
+		// mem accounts for the buffered points and range keys.
+		mem mon.BoundAccount
+	}
+}
+
+func newContinuousBackupStream(
+	ctx context.Context,
+	job *jobs.Job,
+	execCtx sql.JobExecContext,
+	details jobspb.BackupDetails,
+	kmsEnv cloud.KMSEnv,
+) (*continuousBackupStream, error) {
+	execCfg := execCtx.ExecCfg()
+	s := &continuousBackupStream{
+		job:     job,
+		execCtx: execCtx,
+		execCfg: execCfg,
+		details: details,
+		kmsEnv:  kmsEnv,
+		errCh:   make(chan error, 1),
+	}
+	// The destination was resolved when the first layer was written, so the
+	// stream always appends to the chain in the resolved subdir.
+	s.details.Destination.Exists = true
+	s.mon = mon.NewMonitorInheritWithLimit(
+		mon.MakeName("continuous-backup"), continuousBackupMaxBufferedBytes.Get(&execCfg.Settings.SV),
+		execCfg.RootMemoryMonitor, false, /* longLiving */
+	)
+	s.mon.StartNoReserved(ctx, execCfg.RootMemoryMonitor)
+	s.mu.mem = s.mon.MakeBoundAccount()
+
+	// We load the chain from storage rather than relying on the job's
+	// checkpoint, as a layer may have been written before the job failed to
+	// record it.
+	manifests, _, encryption, iters, err := getBackupChain(
+		ctx, execCfg, execCtx.User(), s.details.Destination, details.EncryptionOptions,
+		hlc.Timestamp{}, kmsEnv,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, errors.AssertionFailedf("no backups found to stream changes onto")
+	}
+	s.encryption = encryption
+	if encryption != nil && encryption.Mode != jobspb.EncryptionMode_None {
+		key, err := backupencryption.GetEncryptionKey(ctx, encryption, kmsEnv)
+		if err != nil {
+			return nil, err
+		}
+		s.fileEncryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+	s.prevBackups = make([]backuppb.BackupManifest, len(manifests))
+	for i := range manifests {
+		s.prevBackups[i] = slimContinuousBackupManifest(manifests[i], i == len(manifests)-1)
+	}
+	s.lastIters = backupinfo.LayerToBackupManifestFileIterFactory{
+		len(manifests) - 1: iters[len(manifests)-1],
+	}
+
+	s.spans.Add(manifests[len(manifests)-1].Spans...)
+	if err := s.startFeed(ctx); err != nil {
+		s.close(ctx)
+		return nil, err
+	}
+	return s, nil
+}
+
+// slimContinuousBackupManifest returns a copy of a manifest with just the
+// fields needed to plan the next layer of a continuous backup.
+func slimContinuousBackupManifest(
+	m backuppb.BackupManifest, keepDescs bool,
+) backuppb.BackupManifest {
+	m.Files = nil
+	if !keepDescs {
+		m.Descriptors = nil
+		m.DescriptorChanges = nil
+	}
+	return m
+}
+
+func (s *continuousBackupStream) lastEnd() hlc.Timestamp {
+	return s.prevBackups[len(s.prevBackups)-1].EndTime
+}
+
+// startFeed (re)starts the rangefeed over the spans of the stream from the end
+// of the last layer, dropping any buffered events.
+func (s *continuousBackupStream) startFeed(ctx context.Context) error {
+	if s.feed != nil {
+		s.feed.Close()
+		s.feed = nil
+	}
+	lastEnd := s.lastEnd()
+
+	s.mu.Lock()
+	s.mu.gen++
+	gen := s.mu.gen
+	s.mu.frontier = lastEnd
+	s.mu.points = nil
+	s.mu.rangeKeys = nil
+	s.mu.mem.Clear(ctx)
+	s.mu.Unlock()
+
+	onValue := func(ctx context.Context, v *kvpb.RangeFeedValue) {
+		if v.Value.Timestamp.LessEq(lastEnd) {
+			return
+		}
+		value, err := storage.EncodeMVCCValue(storage.MVCCValue{Value: v.Value})
+		if err != nil {
+			s.sendErr(err)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.gen != gen {
+			return
+		}
+		p := storage.MVCCKeyValue{
+			Key:   storage.MVCCKey{Key: v.Key, Timestamp: v.Value.Timestamp},
+			Value: value,
+		}
+		if err := s.mu.mem.Grow(ctx, pointSize(p)); err != nil {
+			s.sendErr(bufferLimitError(err))
+			return
+		}
+		s.mu.points = append(s.mu.points, p)
+	}
+	onDeleteRange := func(ctx context.Context, d *kvpb.RangeFeedDeleteRange) {
+		if d.Timestamp.LessEq(lastEnd) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.gen != gen {
+			return
+		}
+		rk := storage.MVCCRangeKey{
+			StartKey:  d.Span.Key,
+			EndKey:    d.Span.EndKey,
+			Timestamp: d.Timestamp,
+		}
+		if err := s.mu.mem.Grow(ctx, rangeKeySize(rk)); err != nil {
+			s.sendErr(bufferLimitError(err))
+			return
+		}
+		s.mu.rangeKeys = append(s.mu.rangeKeys, rk)
+	}
+	onFrontierAdvance := func(ctx context.Context, ts hlc.Timestamp) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.gen != gen {
+			return
+		}
+		s.mu.frontier.Forward(ts)
+	}
+
+	feed, err := s.execCfg.RangeFeedFactory.RangeFeed(
+		ctx, "continuous-backup", s.spans.Slice(), lastEnd, onValue,
+		rangefeed.WithOnDeleteRange(onDeleteRange),
+		rangefeed.WithOnFrontierAdvance(onFrontierAdvance),
+		rangefeed.WithOnInternalError(func(ctx context.Context, err error) {
+			s.sendErr(err)
+		}),
+		rangefeed.WithConsumerID(int64(s.job.ID())),
+	)
+	if err != nil {
+		return err
+	}
+	s.feed = feed
+	return nil
+}
+
+func (s *continuousBackupStream) sendErr(err error) {
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
+
+// bufferLimitError wraps an error growing the memory account of the buffered
+// rangefeed events.
+func bufferLimitError(err error) error {
+	return errors.WithHintf(
+		errors.Wrap(err, "buffering continuous backup changes"),
+		"the changes are buffered until they are written to a layer; check for errors writing "+
+			"layers or raise the %s cluster setting", continuousBackupMaxBufferedBytes.Name(),
+	)
+}
+
+// pointSize and rangeKeySize return the memory accounted for a buffered
+// rangefeed event.
+func pointSize(p storage.MVCCKeyValue) int64 {
+	return int64(unsafe.Sizeof(p)) + int64(len(p.Key.Key)+len(p.Value))
+}
+
+func rangeKeySize(rk storage.MVCCRangeKey) int64 {
+	return int64(unsafe.Sizeof(rk)) + int64(len(rk.StartKey)+len(rk.EndKey))
+}
+
+func (s *continuousBackupStream) close(ctx context.Context) {
+	if s.feed != nil {
+		s.feed.Close()
+	}
+	s.mu.Lock()
+	s.mu.mem.Close(ctx)
+	s.mu.Unlock()
+	s.mon.Stop(ctx)
+}
+
+// flush writes the changes streamed up to the current rangefeed frontier into
+// a new layer of the backup chain.
+func (s *continuousBackupStream) flush(ctx context.Context) (retErr error) {
+	lastEnd := s.lastEnd()
+	s.mu.Lock()
+	end := s.mu.frontier
+	s.mu.Unlock()
+	if end.LessEq(lastEnd) {
+		return nil
+	}
+
+	layerDetails := s.details
+	layerDetails.StartTime = lastEnd
+	layerDetails.EndTime = end
+	layerDetails.RevisionHistory = true
+	targets, err := s.resolveTargets(ctx, end)
+	if err != nil {
+		return err
+	}
+	layerDetails.ResolvedTargets = targets
+	manifest, err := createBackupManifest(
+		ctx, s.execCfg, nil /* tenantSpans */, nil, /* tenantInfos */
+		layerDetails, s.prevBackups, s.lastIters,
+	)
+	if err != nil {
+		return err
+	}
+
+	dest, err := backupdest.ResolveDest(
+		ctx, s.execCtx.User(), layerDetails.Destination, lastEnd, end,
+		s.execCfg, s.encryption, s.kmsEnv,
+	)
+	if err != nil {
+		return err
+	}
+	store, err := s.execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, dest.DefaultURI, s.execCtx.User())
+	if err != nil {
+		return err
+	}
+	defer logClose(ctx, store, "external storage")
+
+	// If the targets now include spans the rangefeed is not watching, such as
+	// those of a table created since the last layer, the layer is exported by
+	// the backup processors like a regular incremental backup, which reads the
+	// introduced spans in full. The rangefeed is then restarted from the end of
+	// the layer to include them, dropping the events it buffered for the layer.
+	var uncovered roachpb.SpanGroup
+	uncovered.Add(manifest.Spans...)
+	uncovered.Sub(s.spans.Slice()...)
+	var files []backuppb.BackupManifest_File
+	if uncovered.Len() > 0 {
+		if files, err = s.exportLayer(ctx, dest.DefaultURI, &manifest); err != nil {
+			return err
+		}
+	} else {
+		points, rangeKeys := s.takeEvents(ctx, end)
+		// If we fail after taking the buffered events, restart the rangefeed so
+		// that the events are delivered again and written by a later flush.
+		defer func() {
+			if retErr != nil {
+				if err := s.startFeed(ctx); err != nil {
+					retErr = errors.CombineErrors(retErr, err)
+				}
+			}
+		}()
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Key.Less(points[j].Key)
+		})
+		points = dedupMVCCKeyValues(points)
+		sort.Slice(rangeKeys, func(i, j int) bool {
+			return rangeKeys[i].Compare(rangeKeys[j]) < 0
+		})
+		if files, err = s.writeFiles(ctx, store, manifest, points, rangeKeys); err != nil {
+			return err
+		}
+	}
+	manifest.Files = files
+	for _, f := range files {
+		manifest.EntryCounts.Add(f.EntryCounts)
+	}
+	if err := concludeBackupCompaction(
+		ctx, s.execCtx, store, s.encryption, s.kmsEnv, &manifest,
+	); err != nil {
+		return err
+	}
+
+	if err := s.job.NoTxn().Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		if err := md.CheckRunningOrReverting(); err != nil {
+			return err
+		}
+		if s.details.ProtectedTimestampRecord != nil {
+			pts := s.execCfg.ProtectedTimestampProvider.WithTxn(txn)
+			if err := pts.UpdateTimestamp(ctx, *s.details.ProtectedTimestampRecord, end); err != nil {
+				return err
+			}
+		}
+		md.Progress.GetBackup().ContinuousCheckpoint = end
+		md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &end}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}); err != nil {
+		return err
+	}
+	log.VEventf(ctx, 1, "continuous backup wrote layer [%s, %s] with %d files", lastEnd, end, len(files))
+
+	s.prevBackups[len(s.prevBackups)-1] = slimContinuousBackupManifest(
+		s.prevBackups[len(s.prevBackups)-1], false, /* keepDescs */
+	)
+	s.prevBackups = append(s.prevBackups, slimContinuousBackupManifest(manifest, true /* keepDescs */))
+	// The descriptors of the new layer are held in memory, so its iterators do
+	// not need to read from the store.
+	last := len(s.prevBackups) - 1
+	s.lastIters = backupinfo.LayerToBackupManifestFileIterFactory{
+		last: backupinfo.NewIterFactory(&s.prevBackups[last], nil /* store */, s.encryption, s.kmsEnv),
+	}
+	if uncovered.Len() > 0 {
+		s.spans.Add(uncovered.Slice()...)
+		return s.startFeed(ctx)
+	}
+	return nil
+}
+
+// resolveTargets returns the descriptors of the targets of the backup as of
+// the given time. Objects created in the backed up databases since the backup
+// started are included, while dropped objects are not.
+func (s *continuousBackupStream) resolveTargets(
+	ctx context.Context, asOf hlc.Timestamp,
+) ([]descpb.Descriptor, error) {
+	targetIDs := make(map[descpb.ID]struct{}, len(s.details.ResolvedTargets))
+	for i := range s.details.ResolvedTargets {
+		id, _, _, _, _ := descpb.GetDescriptorMetadata(&s.details.ResolvedTargets[i])
+		targetIDs[id] = struct{}{}
+	}
+	completeDBs := make(map[descpb.ID]struct{}, len(s.details.ResolvedCompleteDbs))
+	for _, id := range s.details.ResolvedCompleteDbs {
+		completeDBs[id] = struct{}{}
+	}
+
+	allDescs, err := backupresolver.LoadAllDescs(ctx, s.execCfg, asOf)
+	if err != nil {
+		return nil, err
+	}
+	var targets []descpb.Descriptor
+	for _, desc := range allDescs {
+		if desc.Dropped() {
+			continue
+		}
+		_, isTarget := targetIDs[desc.GetID()]
+		_, inCompleteDB := completeDBs[desc.GetParentID()]
+		if isTarget || inCompleteDB {
+			targets = append(targets, *desc.DescriptorProto())
+		}
+	}
+	return targets, nil
+}
+
+// exportLayer writes the data of a layer to the store at defaultURI through
+// the distributed backup processors and returns the written files.
+func (s *continuousBackupStream) exportLayer(
+	ctx context.Context, defaultURI string, manifest *backuppb.BackupManifest,
+) ([]backuppb.BackupManifest_File, error) {
+	pkIDs := make(map[uint64]bool)
+	for i := range manifest.Descriptors {
+		if t, _, _, _, _ := descpb.GetDescriptors(&manifest.Descriptors[i]); t != nil {
+			pkIDs[kvpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+
+	evalCtx := s.execCtx.ExtendedEvalContext()
+	dsp := s.execCtx.DistSQLPlanner()
+	planCtx, _, err := dsp.SetupAllNodesPlanningWithOracle(
+		ctx, evalCtx, s.execCfg, physicalplan.DefaultReplicaChooser, s.details.ExecutionLocality,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine nodes on which to run")
+	}
+	backupSpecs, err := distBackupPlanSpecs(
+		ctx, planCtx, s.execCtx, dsp, int64(s.job.ID()),
+		manifest.Spans, manifest.IntroducedSpans, pkIDs,
+		defaultURI, nil /* urisByLocalityKV */, s.encryption, s.kmsEnv,
+		kvpb.MVCCFilter(manifest.MVCCFilter), manifest.StartTime, manifest.EndTime,
+		manifest.ElidedPrefix,
+		manifest.ClusterVersion.AtLeast(clusterversion.V24_1.Version()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	progCh := make(chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+	tracingAggCh := make(chan *execinfrapb.TracingAggregatorEvents)
+	var files []backuppb.BackupManifest_File
+	collectFiles := func(ctx context.Context) error {
+		for prog := range progCh {
+			var progDetails backuppb.BackupManifest_Progress
+			if err := types.UnmarshalAny(&prog.ProgressDetails, &progDetails); err != nil {
+				return err
+			}
+			if manifest.RevisionStartTime.Less(progDetails.RevStartTime) {
+				manifest.RevisionStartTime = progDetails.RevStartTime
+			}
+			files = append(files, progDetails.Files...)
+		}
+		return nil
+	}
+	drainTracingAgg := func(ctx context.Context) error {
+		for range tracingAggCh {
+		}
+		return nil
+	}
+	runBackup := func(ctx context.Context) error {
+		return distBackup(ctx, s.execCtx, planCtx, dsp, progCh, tracingAggCh, backupSpecs)
+	}
+	if err := ctxgroup.GoAndWait(ctx, collectFiles, drainTracingAgg, runBackup); err != nil {
+		return nil, errors.Wrap(err, "exporting continuous backup layer")
+	}
+	return files, nil
+}
+
+// takeEvents removes and returns the buffered events at or below the given
+// timestamp, releasing the memory accounted for them.
+func (s *continuousBackupStream) takeEvents(
+	ctx context.Context, end hlc.Timestamp,
+) ([]storage.MVCCKeyValue, []storage.MVCCRangeKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var taken int64
+	var points, remainingPoints []storage.MVCCKeyValue
+	for _, p := range s.mu.points {
+		if p.Key.Timestamp.LessEq(end) {
+			points = append(points, p)
+			taken += pointSize(p)
+		} else {
+			remainingPoints = append(remainingPoints, p)
+		}
+	}
+	var rangeKeys, remainingRangeKeys []storage.MVCCRangeKey
+	for _, rk := range s.mu.rangeKeys {
+		if rk.Timestamp.LessEq(end) {
+			rangeKeys = append(rangeKeys, rk)
+			taken += rangeKeySize(rk)
+		} else {
+			remainingRangeKeys = append(remainingRangeKeys, rk)
+		}
+	}
+	s.mu.points, s.mu.rangeKeys = remainingPoints, remainingRangeKeys
+	s.mu.mem.Shrink(ctx, taken)
+	return points, rangeKeys
+}
+
+// dedupMVCCKeyValues removes repeated versions from a sorted slice of keys, as
+// rangefeeds may deliver the same version more than once.
+func dedupMVCCKeyValues(kvs []storage.MVCCKeyValue) []storage.MVCCKeyValue {
+	if len(kvs) == 0 {
+		return kvs
+	}
+	out := kvs[:1]
+	for _, v := range kvs[1:] {
+		if !v.Key.Equal(out[len(out)-1].Key) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// writeFiles writes the given keys, which must be sorted, into data files in
+// the store for the spans of the manifest, and returns the written files.
+func (s *continuousBackupStream) writeFiles(
+	ctx context.Context,
+	store cloud.ExternalStorage,
+	manifest backuppb.BackupManifest,
+	points []storage.MVCCKeyValue,
+	rangeKeys []storage.MVCCRangeKey,
+) ([]backuppb.BackupManifest_File, error) {
+	progCh := make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+	var files []backuppb.BackupManifest_File
+
+	grp := ctxgroup.WithContext(ctx)
+	grp.GoCtx(func(ctx context.Context) error {
+		for prog := range progCh {
+			var progDetails backuppb.BackupManifest_Progress
+			if err := types.UnmarshalAny(&prog.ProgressDetails, &progDetails); err != nil {
+				return err
+			}
+			files = append(files, progDetails.Files...)
+		}
+		return nil
+	})
+	grp.GoCtx(func(ctx context.Context) error {
+		defer close(progCh)
+		sink := backupsink.MakeFileSSTSink(backupsink.SSTSinkConf{
+			ProgCh:    progCh,
+			Enc:       s.fileEncryption,
+			ID:        s.execCfg.NodeInfo.NodeID.SQLInstanceID(),
+			Settings:  &s.execCfg.Settings.SV,
+			ElideMode: manifest.ElidedPrefix,
+		}, store, nil /* pacer */)
+		defer logClose(ctx, sink, "SST sink")
+
+		var spans roachpb.SpanGroup
+		spans.Add(manifest.Spans...)
+		for _, sp := range spans.Slice() {
+			// The sink strips the elided prefix of a file's span from its keys, so
+			// a file may not span more than one prefix.
+			for sp.Valid() {
+				segment := sp
+				prefix, err := backupsink.ElidedPrefix(sp.Key, manifest.ElidedPrefix)
+				if err != nil {
+					return err
+				}
+				if len(prefix) > 0 {
+					if prefixEnd := roachpb.Key(prefix).PrefixEnd(); prefixEnd.Compare(segment.EndKey) < 0 {
+						segment.EndKey = prefixEnd
+					}
+				}
+				sp.Key = segment.EndKey
+
+				var segmentPoints []storage.MVCCKeyValue
+				for len(points) > 0 && points[0].Key.Key.Compare(segment.Key) < 0 {
+					points = points[1:]
+				}
+				for len(points) > 0 && points[0].Key.Key.Compare(segment.EndKey) < 0 {
+					segmentPoints = append(segmentPoints, points[0])
+					points = points[1:]
+				}
+				var segmentRangeKeys []storage.MVCCRangeKey
+				for _, rk := range rangeKeys {
+					clipped := rk.Clone()
+					if clipped.StartKey.Compare(segment.Key) < 0 {
+						clipped.StartKey = segment.Key
+					}
+					if clipped.EndKey.Compare(segment.EndKey) > 0 {
+						clipped.EndKey = segment.EndKey
+					}
+					if clipped.StartKey.Compare(clipped.EndKey) < 0 {
+						segmentRangeKeys = append(segmentRangeKeys, clipped)
+					}
+				}
+				if len(segmentPoints) == 0 && len(segmentRangeKeys) == 0 {
+					continue
+				}
+
+				sst, counts, err := s.makeSST(ctx, segmentPoints, segmentRangeKeys)
+				if err != nil {
+					return err
+				}
+				if _, err := sink.Write(ctx, backupsink.ExportedSpan{
+					Metadata: backuppb.BackupManifest_File{
+						Span:                    segment,
+						EntryCounts:             counts,
+						ApproximatePhysicalSize: uint64(len(sst)),
+					},
+					DataSST: sst,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return sink.Flush(ctx)
+	})
+	if err := grp.Wait(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// makeSST returns an in-memory SST containing the given keys.
+func (s *continuousBackupStream) makeSST(
+	ctx context.Context, points []storage.MVCCKeyValue, rangeKeys []storage.MVCCRangeKey,
+) ([]byte, roachpb.RowCount, error) {
+	var counts roachpb.RowCount
+	sstFile := &storage.MemObject{}
+	w := storage.MakeIngestionSSTWriter(ctx, s.execCfg.Settings, sstFile)
+	defer w.Close()
+	for _, p := range points {
+		if err := w.PutRawMVCC(p.Key, p.Value); err != nil {
+			return nil, counts, err
+		}
+		counts.DataSize += int64(len(p.Key.Key) + len(p.Value))
+	}
+	for _, rk := range rangeKeys {
+		if err := w.PutMVCCRangeKey(rk, storage.MVCCValue{}); err != nil {
+			return nil, counts, err
+		}
+		counts.DataSize += int64(len(rk.StartKey) + len(rk.EndKey))
+	}
+	if err := w.Finish(); err != nil {
+		return nil, counts, err
+	}
+	return sstFile.Data(), counts, nil
+}
+
+// maybeCompact starts a job compacting the layers written by the stream into a
+// single incremental backup once they cover
+// backup.continuous.compaction_interval. The layers are kept, so restores to
+// any time they cover remain possible.
+func (s *continuousBackupStream) maybeCompact(ctx context.Context) error {
+	interval := continuousBackupCompactionInterval.Get(&s.execCfg.Settings.SV)
+	if interval == 0 {
+		return nil
+	}
+	if s.compactionJobID != 0 {
+		job, err := s.execCfg.JobRegistry.LoadJob(ctx, s.compactionJobID)
+		if err != nil && !jobs.HasJobNotFoundError(err) {
+			return err
+		}
+		if err == nil && !job.State().Terminal() {
+			return nil
+		}
+	}
+
+	// Compacted layers are written with the latest revisions only, so the
+	// window starts after the last layer that does not have revision history.
+	start := -1
+	for i := 1; i < len(s.prevBackups); i++ {
+		m := s.prevBackups[i]
+		if m.MVCCFilter != backuppb.MVCCFilter_All || m.StartTime.Less(s.details.EndTime) {
+			start = -1
+		} else if start == -1 {
+			start = i
+		}
+	}
+	last := len(s.prevBackups) - 1
+	if start == -1 || last-start < 1 {
+		return nil
+	}
+	startTS, endTS := s.prevBackups[start].StartTime, s.prevBackups[last].EndTime
+	if endTS.GoTime().Sub(startTS.GoTime()) < interval {
+		return nil
+	}
+
+	encryption := jobspb.BackupEncryptionOptions{Mode: jobspb.EncryptionMode_None}
+	if s.details.EncryptionOptions != nil {
+		encryption = *s.details.EncryptionOptions
+	}
+	record, err := makeCompactionJobRecord(
+		0 /* scheduleID */, s.details.Destination.To, s.details.Destination.IncrementalStorage,
+		s.details.Destination.Subdir, encryption, startTS, endTS, s.execCtx.User(),
+	)
+	if err != nil {
+		return err
+	}
+	jobID := s.execCfg.JobRegistry.MakeJobID()
+	if err := s.execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := s.execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, record, jobID, txn)
+		return err
+	}); err != nil {
+		return err
+	}
+	s.compactionJobID = jobID
+	log.Infof(ctx, "started job %d compacting continuous backup layers from %s to %s",
+		jobID, startTS, endTS)
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContinuousBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`) // speeds up test
+	sqlDB.Exec(t, `SET CLUSTER SETTING backup.continuous.flush_interval = '1s'`)
+
+	const collection = `'nodelocal://1/continuous'`
+	var jobID jobspb.JobID
+	sqlDB.QueryRow(t,
+		`BACKUP DATABASE data INTO `+collection+` WITH detached, continuous`,
+	).Scan(&jobID)
+
+	now := func() string {
+		var ts string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts)
+		return ts
+	}
+	waitForCheckpoint := func(ts string) {
+		testutils.SucceedsSoon(t, func() error {
+			var caughtUp bool
+			sqlDB.QueryRow(t,
+				`SELECT coalesce(high_water_timestamp > $1::DECIMAL, false) FROM crdb_internal.jobs WHERE job_id = $2`,
+				ts, jobID,
+			).Scan(&caughtUp)
+			if !caughtUp {
+				return errors.Newf("continuous backup has not checkpointed past %s", ts)
+			}
+			return nil
+		})
+	}
+
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = 1 WHERE id = 1`)
+	ts1 := now()
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = 2 WHERE id = 1`)
+	sqlDB.Exec(t, `CREATE TABLE data.created (k INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `INSERT INTO data.created VALUES (1), (2)`)
+	ts2 := now()
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id = 1`)
+	ts3 := now()
+	waitForCheckpoint(ts3)
+
+	restoreAt := func(ts string, newDB string) {
+		sqlDB.Exec(t, fmt.Sprintf(
+			`RESTORE DATABASE data FROM LATEST IN %s AS OF SYSTEM TIME %s WITH new_db_name = '%s'`,
+			collection, ts, newDB,
+		))
+	}
+
+	restoreAt(ts1, "data1")
+	sqlDB.CheckQueryResults(t, `SELECT balance FROM data1.bank WHERE id = 1`, [][]string{{"1"}})
+	sqlDB.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW TABLES FROM data1] WHERE table_name = 'created'`, [][]string{{"0"}},
+	)
+
+	restoreAt(ts2, "data2")
+	sqlDB.CheckQueryResults(t, `SELECT balance FROM data2.bank WHERE id = 1`, [][]string{{"2"}})
+	sqlDB.CheckQueryResults(t, `SELECT k FROM data2.created ORDER BY k`, [][]string{{"1"}, {"2"}})
+
+	restoreAt(ts3, "data3")
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM data3.bank WHERE id = 1`, [][]string{{"0"}})
+	sqlDB.CheckQueryResults(t,
+		`SELECT count(*) FROM data3.bank`, [][]string{{fmt.Sprint(numAccounts - 1)}},
+	)
+
+	// Pausing and resuming the job resumes the stream from the last layer.
+	sqlDB.Exec(t, `PAUSE JOB $1`, jobID)
+	jobutils.WaitForJobToPause(t, sqlDB, jobID)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = 4 WHERE id = 2`)
+	sqlDB.Exec(t, `RESUME JOB $1`, jobID)
+	ts4 := now()
+	waitForCheckpoint(ts4)
+	restoreAt(ts4, "data4")
+	sqlDB.CheckQueryResults(t, `SELECT balance FROM data4.bank WHERE id = 2`, [][]string{{"4"}})
+
+	sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+	jobutils.WaitForJobToCancel(t, sqlDB, jobID)
+}
+
+// TestContinuousBackupBufferLimit checks that a continuous backup fails
+// rather than buffering more changes than backup.continuous.max_buffered_bytes
+// while it cannot write them to a layer.
+func TestContinuousBackupBufferLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, 0 /* numAccounts */, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING backup.continuous.flush_interval = '1h'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING backup.continuous.max_buffered_bytes = '64KiB'`)
+
+	var jobID jobspb.JobID
+	sqlDB.QueryRow(t,
+		`BACKUP DATABASE data INTO 'nodelocal://1/continuous' WITH detached, continuous`,
+	).Scan(&jobID)
+	jobutils.WaitForJobToRun(t, sqlDB, jobID)
+
+	sqlDB.Exec(t, `INSERT INTO data.bank SELECT i, 0, repeat('x', 1024) FROM generate_series(1, 1000) AS g(i)`)
+	jobutils.WaitForJobToFail(t, sqlDB, jobID)
+	var errMsg string
+	sqlDB.QueryRow(t, `SELECT error FROM [SHOW JOB $1]`, jobID).Scan(&errMsg)
+	require.Contains(t, errMsg, "buffering continuous backup changes")
+}
+
+func TestContinuousBackupPlanning(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, 1, InitManualReplication)
+	defer cleanupFn()
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+
+	for _, tc := range []struct {
+		stmt string
+		err  string
+	}{
+		{
+			stmt: `BACKUP DATABASE data INTO 'nodelocal://1/a' WITH continuous`,
+			err:  "the continuous option requires the detached option",
+		},
+		{
+			stmt: `BACKUP INTO 'nodelocal://1/a' WITH detached, continuous`,
+			err:  "the continuous option is not supported for cluster backups",
+		},
+		{
+			stmt: `BACKUP DATABASE data INTO 'nodelocal://1/a' WITH detached, continuous, incremental_location = 'nodelocal://1/inc'`,
+			err:  "the continuous option is not supported with incremental_location",
+		},
+		{
+			stmt: `CREATE SCHEDULE FOR BACKUP DATABASE data INTO 'nodelocal://1/a' WITH continuous RECURRING '@hourly'`,
+			err:  "the continuous option cannot be used with backup schedules",
+		},
+	} {
+		t.Run(tc.stmt, func(t *testing.T) {
+			sqlDB.ExpectErr(t, tc.err, tc.stmt)
+		})
+	}
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = false`)
+	sqlDB.ExpectErr(t, "continuous backups require that the kv.rangefeed.enabled cluster setting is enabled",
+		`BACKUP DATABASE data INTO 'nodelocal://1/a' WITH detached, continuous`)
+}
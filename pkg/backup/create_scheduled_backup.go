@@ -644,6 +644,10 @@ func makeScheduledBackupSpec(
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to evaluate backup destination paths")
 	}
+	if schedule.BackupOptions.Continuous == tree.DBoolTrue {
+		return nil, errors.New("the continuous option cannot be used with backup schedules; " +
+			"run a detached continuous BACKUP instead")
+	}
 	if schedule.BackupOptions.EncryptionPassphrase != nil {
 		passphrase, err := exprEval.String(
 			ctx, schedule.BackupOptions.EncryptionPassphrase,
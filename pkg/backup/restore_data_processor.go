@@ -407,6 +407,14 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 			return errors.Wrap(err, "creating key rewriter from rekeys")
 		}
 
+		var rowFilter *restoreRowFilter
+		if rd.spec.RowFilter != "" {
+			rowFilter, err = makeRestoreRowFilter(ctx, rd.FlowCtx, kr, rd.spec.RowFilter)
+			if err != nil {
+				return errors.Wrap(err, "creating row filter")
+			}
+		}
+
 		var sstIter mergedSST
 		for {
 			done, err := func() (done bool, _ error) {
@@ -429,7 +437,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 						return done, errors.Wrap(err, "opening SSTs")
 					}
 
-					summary, err := rd.processRestoreSpanEntry(ctx, kr, sstIter)
+					summary, err := rd.processRestoreSpanEntry(ctx, kr, rowFilter, sstIter)
 					if err != nil {
 						return done, errors.Wrap(err, "processing restore span entry")
 					}
@@ -457,7 +465,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 }
 
 func (rd *restoreDataProcessor) processRestoreSpanEntry(
-	ctx context.Context, kr *KeyRewriter, sst mergedSST,
+	ctx context.Context, kr *KeyRewriter, rowFilter *restoreRowFilter, sst mergedSST,
 ) (kvpb.BulkOpSummary, error) {
 	db := rd.FlowCtx.Cfg.DB
 	var summary kvpb.BulkOpSummary
@@ -522,6 +530,16 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 	}
 	defer batcher.Close(ctx)
 
+	if rd.spec.ValidateOnly {
+		rowFilter = nil
+	}
+	if rowFilter != nil {
+		if err := rowFilter.startEntry(ctx, rd.FlowCtx); err != nil {
+			return summary, err
+		}
+		defer rowFilter.closeEntry(ctx)
+	}
+
 	// Read log.V once first to avoid the vmodule mutex in the tight loop below.
 	verbose := log.V(5)
 
@@ -602,10 +620,22 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 		// were given. We expect that value.ClearChecksum and
 		// value.InitChecksum calls above have modified
 		// valueScratch.
+		if rowFilter != nil {
+			if err := rowFilter.add(ctx, batcher, key, valueScratch); err != nil {
+				return summary, errors.Wrapf(err, "filtering row: %s -> %s", key, value.PrettyPrint())
+			}
+			continue
+		}
 		if err := batcher.AddMVCCKey(ctx, key, valueScratch); err != nil {
 			return summary, errors.Wrapf(err, "adding to batch: %s -> %s", key, value.PrettyPrint())
 		}
 	}
+	var indexSummary kvpb.BulkOpSummary
+	if rowFilter != nil {
+		if indexSummary, err = rowFilter.finishEntry(ctx, batcher); err != nil {
+			return summary, err
+		}
+	}
 	// Flush out the last batch.
 	if err := batcher.Flush(ctx); err != nil {
 		return summary, err
@@ -619,7 +649,9 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 		}
 	}
 
-	return batcher.GetSummary(), nil
+	summary = batcher.GetSummary()
+	summary.Add(indexSummary)
+	return summary, nil
 }
 
 func makeProgressUpdate(
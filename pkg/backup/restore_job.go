@@ -558,6 +558,7 @@ func restore(
 			execLocality:         details.ExecutionLocality,
 			exclusiveEndKeys:     fsc.isExclusive(),
 			resumeClusterVersion: resumeClusterVersion,
+			rowFilter:            details.RowFilter,
 		}
 		return errors.Wrap(distRestore(
 			ctx,
@@ -1067,6 +1068,22 @@ func createImportingDescriptors(
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	if details.RowFilter != "" {
+		// A row_filter RESTORE only reads the part of the primary index that can
+		// contain matching rows; the secondary index entries of the restored rows
+		// are encoded by the restore data processors.
+		if len(postRestoreTables) != 1 {
+			return nil, nil, nil, errors.AssertionFailedf(
+				"expected a single table in a %s restore, found %d", restoreOptRowFilter, len(postRestoreTables))
+		}
+		span, err := rowFilterPrimaryIndexSpan(
+			ctx, backupCodec, postRestoreTables[0], details.RowFilter, &p.ExtendedEvalContext().Context,
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		postRestoreSpans = []roachpb.Span{span}
+	}
 	var verifySpans []roachpb.Span
 	if details.VerifyData {
 		// verifySpans contains the spans that should be read and checksum'd during a
@@ -2051,6 +2068,12 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		}
 	}
 
+	if details.RowFilterIntoTableID != descpb.InvalidID {
+		if err := r.mergeRestoredRows(ctx, p.ExecCfg(), details); err != nil {
+			return err
+		}
+	}
+
 	// Reload the details as we may have updated the job.
 	details = r.job.Details().(jobspb.RestoreDetails)
 	p.ExecCfg().JobRegistry.NotifyToAdoptJobs()
@@ -77,6 +77,8 @@ const (
 	restoreOptSkipLocalitiesCheck       = "skip_localities_check"
 	restoreOptAsTenant                  = "virtual_cluster_name"
 	restoreOptForceTenantID             = "virtual_cluster"
+	restoreOptRowFilter                 = "row_filter"
+	restoreOptIntoExistingTable         = "into_existing_table"
 
 	// The temporary database system tables will be restored into for full
 	// cluster backups.
@@ -1064,6 +1066,7 @@ func resolveOptionsForRestoreJobDescription(
 	opts tree.RestoreOptions,
 	intoDB string,
 	newDBName string,
+	rowFilter string,
 	kmsURIs []string,
 	incFrom []string,
 ) (tree.RestoreOptions, error) {
@@ -1088,6 +1091,7 @@ func resolveOptionsForRestoreJobDescription(
 		ExperimentalOnline:               opts.ExperimentalOnline,
 		ExperimentalCopy:                 opts.ExperimentalCopy,
 		RemoveRegions:                    opts.RemoveRegions,
+		IntoExistingTable:                opts.IntoExistingTable,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -1102,6 +1106,10 @@ func resolveOptionsForRestoreJobDescription(
 		newOpts.NewDBName = tree.NewDString(newDBName)
 	}
 
+	if opts.RowFilter != nil {
+		newOpts.RowFilter = tree.NewDString(rowFilter)
+	}
+
 	for _, uri := range kmsURIs {
 		redactedURI, err := cloud.RedactKMSURI(uri)
 		if err != nil {
@@ -1134,6 +1142,7 @@ func restoreJobDescription(
 	opts tree.RestoreOptions,
 	intoDB string,
 	newDBName string,
+	rowFilter string,
 	kmsURIs []string,
 	resolvedSubdir string,
 ) (string, error) {
@@ -1147,7 +1156,7 @@ func restoreJobDescription(
 	var options tree.RestoreOptions
 	var err error
 	if options, err = resolveOptionsForRestoreJobDescription(ctx, opts, intoDB, newDBName,
-		kmsURIs, incFrom); err != nil {
+		rowFilter, kmsURIs, incFrom); err != nil {
 		return "", err
 	}
 	r.Options = options
@@ -1188,6 +1197,7 @@ func restoreTypeCheck(
 			restoreStmt.Options.ForceTenantID,
 			restoreStmt.Options.AsTenant,
 			restoreStmt.Options.ExecutionLocality,
+			restoreStmt.Options.RowFilter,
 		},
 	); err != nil {
 		return false, nil, err
@@ -1326,6 +1336,32 @@ func restorePlanHook(
 		return nil, nil, false, errors.New("cannot run online restore with verify_backup_table_data")
 	}
 
+	var rowFilter string
+	if restoreStmt.Options.RowFilter != nil {
+		if restoreStmt.DescriptorCoverage != tree.RequestedDescriptors ||
+			len(restoreStmt.Targets.Tables.TablePatterns) != 1 {
+			return nil, nil, false, errors.Newf("%s can only be used when restoring a single table",
+				restoreOptRowFilter)
+		}
+		if restoreStmt.Options.SchemaOnly {
+			return nil, nil, false, errors.Newf("cannot use %s with schema_only", restoreOptRowFilter)
+		}
+		if restoreStmt.Options.OnlineImpl() {
+			return nil, nil, false, errors.Newf("cannot run online restore with %s", restoreOptRowFilter)
+		}
+		if restoreStmt.Options.VerifyData {
+			return nil, nil, false, errors.Newf("cannot use %s with verify_backup_table_data", restoreOptRowFilter)
+		}
+		var err error
+		rowFilter, err = exprEval.String(ctx, restoreStmt.Options.RowFilter)
+		if err != nil {
+			return nil, nil, false, err
+		}
+	} else if restoreStmt.Options.IntoExistingTable {
+		return nil, nil, false, errors.Newf("%s can only be used with the %s option",
+			restoreOptIntoExistingTable, restoreOptRowFilter)
+	}
+
 	var newTenantID *roachpb.TenantID
 	var newTenantName *roachpb.TenantName
 	if restoreStmt.Options.AsTenant != nil || restoreStmt.Options.ForceTenantID != nil {
@@ -1413,7 +1449,7 @@ func restorePlanHook(
 
 		return doRestorePlan(
 			ctx, restoreStmt, &exprEval, p, from, incStorage, pw, kms, intoDB,
-			newDBName, newTenantID, newTenantName, endTime, resultsCh, subdir, execLocality,
+			newDBName, rowFilter, newTenantID, newTenantName, endTime, resultsCh, subdir, execLocality,
 		)
 	}
 
@@ -1651,6 +1687,7 @@ func doRestorePlan(
 	kms []string,
 	intoDB string,
 	newDBName string,
+	rowFilter string,
 	newTenantID *roachpb.TenantID,
 	newTenantName *roachpb.TenantName,
 	endTime hlc.Timestamp,
@@ -1958,6 +1995,16 @@ func doRestorePlan(
 		return err
 	}
 
+	var rowFilterIntoTableID descpb.ID
+	if rowFilter != "" {
+		rowFilter, rowFilterIntoTableID, err = planRestoreRowFilter(
+			ctx, p, restoreStmt.Options, databasesByID, schemasByID, filteredTablesByID, intoDB, rowFilter,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	// When running a full cluster restore, we drop the defaultdb and postgres
 	// databases that are present in a new cluster.
 	// This is done so that they can be restored the same way any other user
@@ -2009,6 +2056,7 @@ func doRestorePlan(
 		restoreStmt.Options,
 		intoDB,
 		newDBName,
+		rowFilter,
 		kms,
 		fullyResolvedSubdir)
 	if err != nil {
@@ -2095,6 +2143,8 @@ func doRestorePlan(
 		ExperimentalCopy:                 restoreStmt.Options.ExperimentalCopy,
 		RemoveRegions:                    restoreStmt.Options.RemoveRegions,
 		UnsafeRestoreIncompatibleVersion: restoreStmt.Options.UnsafeRestoreIncompatibleVersion,
+		RowFilter:                        rowFilter,
+		RowFilterIntoTableID:             rowFilterIntoTableID,
 	}
 
 	jr := jobs.Record{
@@ -61,12 +61,14 @@ func TestRestoreResolveOptionsForJobDescription(t *testing.T) {
 		ExperimentalOnline:               true,
 		ExperimentalCopy:                 true,
 		RemoveRegions:                    true,
+		IntoExistingTable:                true,
 
 		IntoDB:               tree.NewDString("test expr"),
 		NewDBName:            tree.NewDString("test expr"),
 		IncrementalStorage:   []tree.Expr{tree.NewDString("http://example.com")},
 		DecryptionKMSURI:     []tree.Expr{tree.NewDString("http://example.com")},
 		EncryptionPassphrase: tree.NewDString("test expr"),
+		RowFilter:            tree.NewDString("test expr"),
 	}
 
 	ensureAllStructFieldsSet := func(s tree.RestoreOptions, name string) {
@@ -88,6 +90,7 @@ func TestRestoreResolveOptionsForJobDescription(t *testing.T) {
 		input,
 		"into_db",
 		"newDBName",
+		"rowFilter",
 		[]string{"http://example.com"},
 		[]string{"http://example.com"})
 	require.NoError(t, err)
@@ -43,6 +43,7 @@ type restoreJobMetadata struct {
 	execLocality         roachpb.Locality
 	exclusiveEndKeys     bool
 	resumeClusterVersion roachpb.Version
+	rowFilter            string
 }
 
 // distRestore plans a 2 stage distSQL flow for a distributed restore. It
@@ -118,6 +119,7 @@ func distRestore(
 			PKIDs:                md.dataToRestore.getPKIDs(),
 			ValidateOnly:         md.dataToRestore.isValidateOnly(),
 			ResumeClusterVersion: md.resumeClusterVersion,
+			RowFilter:            md.rowFilter,
 		}
 
 		// Plan SplitAndScatter on the coordinator node.
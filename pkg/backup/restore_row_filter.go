@@ -0,0 +1,589 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/idxtype"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// restoredRowsSuffix is appended to the name of the table restored by a
+// row_filter RESTORE with into_existing_table. The table holds the restored
+// rows until they are merged into the existing table, after which it is
+// dropped.
+const restoredRowsSuffix = "_restored_rows"
+
+// planRestoreRowFilter validates the row_filter of a RESTORE against the table
+// being restored, returning the filter with its column references dequalified.
+// If into_existing_table is set, it also resolves the table the filtered rows
+// will be merged into, returning its ID, and renames the restored table so it
+// does not collide with the existing one.
+func planRestoreRowFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	opts tree.RestoreOptions,
+	databasesByID map[descpb.ID]*dbdesc.Mutable,
+	schemasByID map[descpb.ID]*schemadesc.Mutable,
+	tablesByID map[descpb.ID]*tabledesc.Mutable,
+	intoDB string,
+	filter string,
+) (string, descpb.ID, error) {
+	if len(tablesByID) != 1 {
+		return "", descpb.InvalidID, errors.Newf(
+			"%s can only be used when restoring a single table", restoreOptRowFilter)
+	}
+	var table *tabledesc.Mutable
+	for _, t := range tablesByID {
+		table = t
+	}
+	if !table.IsTable() {
+		return "", descpb.InvalidID, errors.Newf(
+			"%s can only be used when restoring a table, and %q is not a table",
+			restoreOptRowFilter, table.GetName())
+	}
+	if err := checkRowFilterSupported(table); err != nil {
+		return "", descpb.InvalidID, err
+	}
+
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return "", descpb.InvalidID, errors.Wrapf(err, "parsing %s", restoreOptRowFilter)
+	}
+	version := p.ExecCfg().Settings.Version.ActiveVersion(ctx)
+	tn := tree.MakeUnqualifiedTableName(tree.Name(table.GetName()))
+	filter, cols, err := schemaexpr.ValidateRowFilter(ctx, table, expr, &tn, p.SemaCtx(), version)
+	if err != nil {
+		return "", descpb.InvalidID, errors.Wrapf(err, "invalid %s", restoreOptRowFilter)
+	}
+	for _, colID := range cols.Ordered() {
+		col, err := catalog.MustFindColumnByID(table, colID)
+		if err != nil {
+			return "", descpb.InvalidID, err
+		}
+		if !isRowFilterDecodable(table, col) {
+			return "", descpb.InvalidID, errors.Newf(
+				"%s cannot reference virtual column %q", restoreOptRowFilter, col.GetName())
+		}
+	}
+
+	if !opts.IntoExistingTable {
+		return filter, descpb.InvalidID, nil
+	}
+
+	dbName, err := resolveTargetDB(databasesByID, intoDB, tree.RequestedDescriptors, table)
+	if err != nil {
+		return "", descpb.InvalidID, err
+	}
+	scName := catconstants.PublicSchemaName
+	if sc, ok := schemasByID[table.GetParentSchemaID()]; ok {
+		scName = sc.GetName()
+	}
+	existingName := tree.MakeTableNameWithSchema(
+		tree.Name(dbName), tree.Name(scName), tree.Name(table.GetName()))
+	_, existing, err := p.ResolveMutableTableDescriptor(
+		ctx, &existingName, true /* required */, tree.ResolveRequireTableDesc)
+	if err != nil {
+		return "", descpb.InvalidID, errors.Wrapf(err, "resolving table for %s", restoreOptIntoExistingTable)
+	}
+	for _, priv := range []privilege.Kind{privilege.INSERT, privilege.DELETE} {
+		if err := p.CheckPrivilege(ctx, existing, priv); err != nil {
+			return "", descpb.InvalidID, err
+		}
+	}
+	if err := checkRowFilterTablesCompatible(table, existing); err != nil {
+		return "", descpb.InvalidID, err
+	}
+	if _, _, err := schemaexpr.ValidateRowFilter(
+		ctx, existing, expr, &existingName, p.SemaCtx(), version,
+	); err != nil {
+		return "", descpb.InvalidID, errors.Wrapf(err, "invalid %s for table %s",
+			restoreOptRowFilter, existingName.FQString())
+	}
+	table.SetName(table.GetName() + restoredRowsSuffix)
+	return filter, existing.GetID(), nil
+}
+
+// checkRowFilterSupported returns an error if the rows of the table cannot be
+// filtered during a restore. The restore data processor decodes each row from
+// its primary index and re-encodes the secondary index entries of the rows it
+// keeps, so every secondary index must be derivable from the primary index
+// alone.
+func checkRowFilterSupported(table catalog.TableDescriptor) error {
+	if len(table.AllMutations()) > 0 {
+		return errors.Newf("%s cannot be used to restore table %q with in-progress schema changes",
+			restoreOptRowFilter, table.GetName())
+	}
+	for _, col := range table.PublicColumns() {
+		if col.GetType().UserDefined() {
+			return errors.Newf("%s cannot be used to restore table %q: column %q has a user-defined type",
+				restoreOptRowFilter, table.GetName(), col.GetName())
+		}
+	}
+	for _, idx := range table.PublicNonPrimaryIndexes() {
+		if idx.IsPartial() {
+			return errors.Newf("%s cannot be used to restore table %q: index %q is a partial index",
+				restoreOptRowFilter, table.GetName(), idx.GetName())
+		}
+		if idx.GetType() == idxtype.VECTOR {
+			return errors.Newf("%s cannot be used to restore table %q: index %q is a vector index",
+				restoreOptRowFilter, table.GetName(), idx.GetName())
+		}
+		colIDs := idx.CollectKeyColumnIDs()
+		for i := 0; i < idx.NumSecondaryStoredColumns(); i++ {
+			colIDs.Add(idx.GetStoredColumnID(i))
+		}
+		for _, colID := range colIDs.Ordered() {
+			col, err := catalog.MustFindColumnByID(table, colID)
+			if err != nil {
+				return err
+			}
+			if !isRowFilterDecodable(table, col) {
+				return errors.Newf("%s cannot be used to restore table %q: index %q references virtual column %q",
+					restoreOptRowFilter, table.GetName(), idx.GetName(), col.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+// isRowFilterDecodable returns true if the value of the column can be decoded
+// from the primary index of the table.
+func isRowFilterDecodable(table catalog.TableDescriptor, col catalog.Column) bool {
+	return !col.IsVirtual() || table.GetPrimaryIndex().CollectKeyColumnIDs().Contains(col.GetID())
+}
+
+// checkRowFilterTablesCompatible returns an error if the rows of the restored
+// table cannot be inserted into the existing table by column name.
+func checkRowFilterTablesCompatible(restored, existing catalog.TableDescriptor) error {
+	for _, col := range restored.PublicColumns() {
+		if col.IsComputed() {
+			continue
+		}
+		existingCol := catalog.FindColumnByName(existing, col.GetName())
+		if existingCol == nil || !existingCol.Public() {
+			return errors.Newf("%s: column %q of the backed up table does not exist in table %q",
+				restoreOptIntoExistingTable, col.GetName(), existing.GetName())
+		}
+		if existingCol.IsComputed() {
+			return errors.Newf("%s: column %q is computed in table %q but not in the backed up table",
+				restoreOptIntoExistingTable, col.GetName(), existing.GetName())
+		}
+		if !existingCol.GetType().Equivalent(col.GetType()) {
+			return errors.Newf("%s: column %q has type %s in table %q but %s in the backed up table",
+				restoreOptIntoExistingTable, col.GetName(), existingCol.GetType().SQLString(),
+				existing.GetName(), col.GetType().SQLString())
+		}
+	}
+	return nil
+}
+
+// rowFilterPrimaryIndexSpan returns the span of the primary index of the table
+// that can contain rows matching the filter. If the filter constrains a prefix
+// of the primary key columns to constants, the span is narrowed to that prefix
+// so the restore only reads the matching part of the backup; otherwise it is
+// the span of the whole primary index. The secondary indexes are not read at
+// all: their entries are re-encoded from the restored rows.
+func rowFilterPrimaryIndexSpan(
+	ctx context.Context,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	filter string,
+	evalCtx *eval.Context,
+) (roachpb.Span, error) {
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	expr, err := schemaexpr.MakeRowFilterExpr(ctx, table, filter, evalCtx, &semaCtx)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	constants := make(map[descpb.ColumnID]tree.Datum)
+	collectRowFilterConstants(expr, table.PublicColumns(), constants)
+
+	idx := table.GetPrimaryIndex()
+	key := rowenc.MakeIndexKeyPrefix(codec, table.GetID(), idx.GetID())
+	for i := 0; i < idx.NumKeyColumns(); i++ {
+		d, ok := constants[idx.GetKeyColumnID(i)]
+		if !ok {
+			break
+		}
+		dir, err := catalogkeys.IndexColumnEncodingDirection(idx.GetKeyColumnDirection(i))
+		if err != nil {
+			return roachpb.Span{}, err
+		}
+		if key, err = keyside.Encode(key, d, dir); err != nil {
+			return roachpb.Span{}, err
+		}
+	}
+	return roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()}, nil
+}
+
+// collectRowFilterConstants adds to constants the columns that the filter
+// requires to be equal to a constant. Only the top-level conjuncts of the
+// filter are considered, and only constants whose key encoding is the same as
+// the encoding of the column's values.
+func collectRowFilterConstants(
+	expr tree.TypedExpr, cols []catalog.Column, constants map[descpb.ColumnID]tree.Datum,
+) {
+	switch t := expr.(type) {
+	case *tree.AndExpr:
+		collectRowFilterConstants(t.TypedLeft(), cols, constants)
+		collectRowFilterConstants(t.TypedRight(), cols, constants)
+	case *tree.ParenExpr:
+		collectRowFilterConstants(t.TypedInnerExpr(), cols, constants)
+	case *tree.ComparisonExpr:
+		if t.Operator.Symbol != treecmp.EQ {
+			return
+		}
+		v, ok := t.Left.(*tree.IndexedVar)
+		if !ok || v.Idx >= len(cols) {
+			return
+		}
+		d, ok := t.Right.(tree.Datum)
+		if !ok || d == tree.DNull {
+			return
+		}
+		col := cols[v.Idx]
+		if !d.ResolvedType().Equivalent(col.GetType()) ||
+			colinfo.CanHaveCompositeKeyEncoding(col.GetType()) {
+			return
+		}
+		constants[col.GetID()] = d
+	}
+}
+
+// rowFilterTable holds what a restore worker needs to filter the rows of one
+// restored table.
+type rowFilterTable struct {
+	desc    catalog.TableDescriptor
+	fetcher row.Fetcher
+	expr    tree.TypedExpr
+	ivars   schemaexpr.RowIndexedVarContainer
+	// colMap maps the IDs of the fetched columns to their ordinal in the
+	// decoded row.
+	colMap  catalog.TableColMap
+	indexes []catalog.Index
+}
+
+type rowFilterKV struct {
+	key   storage.MVCCKey
+	value []byte
+}
+
+// restoreRowFilter filters the rows restored by a restore worker. The KVs of
+// the primary index of a filtered table are buffered until a whole row has
+// been read, at which point the row is decoded and the filter evaluated. The
+// KVs of matching rows are passed through to the SST batcher, and their
+// secondary index entries are encoded and written through a BulkAdder, as they
+// are not ordered with the primary index KVs. KVs of the secondary indexes
+// read from the backup are dropped.
+type restoreRowFilter struct {
+	codec   keys.SQLCodec
+	evalCtx *eval.Context
+	tables  map[descpb.ID]*rowFilterTable
+
+	// adder ingests the secondary index entries of the rows of the span entry
+	// being processed.
+	adder kvserverbase.BulkAdder
+
+	// pending holds the KVs of the row currently being read.
+	pending struct {
+		table  *rowFilterTable
+		prefix roachpb.Key
+		kvs    []rowFilterKV
+	}
+	kvScratch []roachpb.KeyValue
+}
+
+// makeRestoreRowFilter prepares the filtering of the rows of the tables
+// rewritten by the key rewriter of a restore worker.
+func makeRestoreRowFilter(
+	ctx context.Context, flowCtx *execinfra.FlowCtx, kr *KeyRewriter, filter string,
+) (*restoreRowFilter, error) {
+	f := &restoreRowFilter{
+		codec:   flowCtx.Codec(),
+		evalCtx: flowCtx.NewEvalCtx(),
+		tables:  make(map[descpb.ID]*rowFilterTable, len(kr.descs)),
+	}
+	for _, desc := range kr.descs {
+		t := &rowFilterTable{desc: desc}
+		semaCtx := tree.MakeSemaContext(nil /* resolver */)
+		var err error
+		if t.expr, err = schemaexpr.MakeRowFilterExpr(ctx, desc, filter, f.evalCtx, &semaCtx); err != nil {
+			return nil, errors.Wrapf(err, "building %s for table %q", restoreOptRowFilter, desc.GetName())
+		}
+		var fetchColumnIDs []descpb.ColumnID
+		for _, col := range desc.PublicColumns() {
+			if isRowFilterDecodable(desc, col) {
+				t.colMap.Set(col.GetID(), len(fetchColumnIDs))
+				fetchColumnIDs = append(fetchColumnIDs, col.GetID())
+			}
+		}
+		var spec fetchpb.IndexFetchSpec
+		if err := rowenc.InitIndexFetchSpec(
+			&spec, f.codec, desc, desc.GetPrimaryIndex(), fetchColumnIDs,
+		); err != nil {
+			return nil, err
+		}
+		if err := t.fetcher.Init(ctx, row.FetcherInitArgs{
+			WillUseKVProvider: true,
+			Alloc:             &tree.DatumAlloc{},
+			Spec:              &spec,
+		}); err != nil {
+			return nil, err
+		}
+		t.ivars = schemaexpr.RowIndexedVarContainer{Cols: desc.PublicColumns(), Mapping: t.colMap}
+		t.indexes = desc.PublicNonPrimaryIndexes()
+		f.tables[desc.GetID()] = t
+	}
+	return f, nil
+}
+
+// startEntry prepares the filter to process a restore span entry.
+func (f *restoreRowFilter) startEntry(ctx context.Context, flowCtx *execinfra.FlowCtx) error {
+	db := flowCtx.Cfg.DB.KV()
+	var err error
+	f.adder, err = flowCtx.Cfg.BulkAdder(ctx, db, db.Clock().Now(), kvserverbase.BulkAdderOptions{
+		Name:          "restore-row-filter",
+		MinBufferSize: 8 << 20,
+		MaxBufferSize: func() int64 { return 32 << 20 },
+		// Inverted indexes can encode the same entry more than once for a row.
+		SkipDuplicates:        true,
+		WriteAtBatchTimestamp: true,
+	})
+	return err
+}
+
+// finishEntry flushes the row being read and the secondary index entries of
+// the span entry, returning a summary of the secondary index entries written.
+func (f *restoreRowFilter) finishEntry(
+	ctx context.Context, batcher SSTBatcherExecutor,
+) (kvpb.BulkOpSummary, error) {
+	if err := f.flushRow(ctx, batcher); err != nil {
+		return kvpb.BulkOpSummary{}, err
+	}
+	if err := f.adder.Flush(ctx); err != nil {
+		return kvpb.BulkOpSummary{}, err
+	}
+	return f.adder.GetSummary(), nil
+}
+
+// closeEntry releases the resources used to process a restore span entry.
+func (f *restoreRowFilter) closeEntry(ctx context.Context) {
+	if f.adder != nil {
+		f.adder.Close(ctx)
+		f.adder = nil
+	}
+	f.resetPending()
+}
+
+// add is called with each rewritten KV of the span entry, in key order, in
+// place of batcher.AddMVCCKey.
+func (f *restoreRowFilter) add(
+	ctx context.Context, batcher SSTBatcherExecutor, key storage.MVCCKey, value []byte,
+) error {
+	_, tableID, indexID, err := f.codec.DecodeIndexPrefix(key.Key)
+	if err != nil {
+		return err
+	}
+	t, ok := f.tables[descpb.ID(tableID)]
+	if !ok {
+		if err := f.flushRow(ctx, batcher); err != nil {
+			return err
+		}
+		return batcher.AddMVCCKey(ctx, key, value)
+	}
+	if descpb.IndexID(indexID) != t.desc.GetPrimaryIndexID() {
+		// Secondary index entries are encoded from the rows that match the
+		// filter.
+		return nil
+	}
+	prefixLen, err := keys.GetRowPrefixLength(key.Key)
+	if err != nil {
+		return err
+	}
+	if f.pending.table != t || !bytes.Equal(f.pending.prefix, key.Key[:prefixLen]) {
+		if err := f.flushRow(ctx, batcher); err != nil {
+			return err
+		}
+		f.pending.table = t
+		f.pending.prefix = append(f.pending.prefix[:0], key.Key[:prefixLen]...)
+	}
+	f.pending.kvs = append(f.pending.kvs, rowFilterKV{
+		key: storage.MVCCKey{
+			Key:       append(roachpb.Key(nil), key.Key...),
+			Timestamp: key.Timestamp,
+		},
+		value: append([]byte(nil), value...),
+	})
+	return nil
+}
+
+// flushRow decodes the pending row and, if it matches the filter, adds its
+// KVs to the batcher and its secondary index entries to the adder.
+func (f *restoreRowFilter) flushRow(ctx context.Context, batcher SSTBatcherExecutor) error {
+	t := f.pending.table
+	if t == nil {
+		return nil
+	}
+	defer f.resetPending()
+
+	f.kvScratch = f.kvScratch[:0]
+	for _, kv := range f.pending.kvs {
+		v, err := storage.DecodeValueFromMVCCValue(kv.value)
+		if err != nil {
+			return err
+		}
+		f.kvScratch = append(f.kvScratch, roachpb.KeyValue{Key: kv.key.Key, Value: v})
+	}
+	if err := t.fetcher.ConsumeKVProvider(ctx, &row.KVProvider{KVs: f.kvScratch}); err != nil {
+		return err
+	}
+	datums, _, err := t.fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return err
+	}
+	if datums == nil {
+		return errors.AssertionFailedf("no row decoded from key %s", f.pending.prefix)
+	}
+
+	t.ivars.CurSourceRow = datums
+	f.evalCtx.PushIVarContainer(&t.ivars)
+	res, err := eval.Expr(ctx, f.evalCtx, t.expr)
+	f.evalCtx.PopIVarContainer()
+	if err != nil {
+		return errors.Wrapf(err, "evaluating %s", restoreOptRowFilter)
+	}
+	if res != tree.DBoolTrue {
+		return nil
+	}
+
+	for _, kv := range f.pending.kvs {
+		if err := batcher.AddMVCCKey(ctx, kv.key, kv.value); err != nil {
+			return errors.Wrapf(err, "adding to batch: %s", kv.key)
+		}
+	}
+	for _, idx := range t.indexes {
+		entries, err := rowenc.EncodeSecondaryIndex(
+			ctx, f.codec, t.desc, idx, t.colMap, datums,
+			rowenc.EmptyVectorIndexEncodingHelper, false, /* includeEmpty */
+		)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := f.adder.Add(ctx, e.Key, e.Value.RawBytes); err != nil {
+				return errors.Wrapf(err, "adding index entry of index %q", idx.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+func (f *restoreRowFilter) resetPending() {
+	f.pending.table = nil
+	f.pending.prefix = f.pending.prefix[:0]
+	f.pending.kvs = f.pending.kvs[:0]
+}
+
+// mergeRestoredRows moves the rows restored by a row_filter RESTORE with
+// into_existing_table into the existing table: the rows of the existing table
+// that match the filter are replaced by the restored ones, and the table
+// holding the restored rows is dropped. It is a no-op if the restored table
+// was already dropped by a previous attempt.
+func (r *restoreResumer) mergeRestoredRows(
+	ctx context.Context, execCfg *sql.ExecutorConfig, details jobspb.RestoreDetails,
+) error {
+	if len(details.TableDescs) != 1 {
+		return errors.AssertionFailedf("expected a single restored table, found %d",
+			len(details.TableDescs))
+	}
+	restoredID := details.TableDescs[0].ID
+	user := r.job.Payload().UsernameProto.Decode()
+	return execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		restored, err := txn.Descriptors().ByIDWithoutLeased(txn.KV()).Get().Table(ctx, restoredID)
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if restored.Dropped() {
+			return nil
+		}
+		existing, err := txn.Descriptors().ByIDWithoutLeased(txn.KV()).WithoutNonPublic().Get().Table(
+			ctx, details.RowFilterIntoTableID)
+		if err != nil {
+			return errors.Wrapf(err, "resolving table for %s", restoreOptIntoExistingTable)
+		}
+		restoredName, err := descs.GetObjectName(ctx, txn.KV(), txn.Descriptors(), restored)
+		if err != nil {
+			return err
+		}
+		existingName, err := descs.GetObjectName(ctx, txn.KV(), txn.Descriptors(), existing)
+		if err != nil {
+			return err
+		}
+
+		var cols []string
+		for _, col := range restored.PublicColumns() {
+			if !col.IsComputed() {
+				cols = append(cols, tree.NameString(col.GetName()))
+			}
+		}
+		colList := strings.Join(cols, ", ")
+		override := sessiondata.InternalExecutorOverride{User: user}
+		stmts := []struct {
+			opName redact.RedactableString
+			stmt   string
+		}{
+			{"restore-row-filter-delete",
+				fmt.Sprintf("DELETE FROM %s WHERE %s", existingName.FQString(), details.RowFilter)},
+			{"restore-row-filter-insert",
+				fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+					existingName.FQString(), colList, colList, restoredName.FQString())},
+			{"restore-row-filter-drop",
+				fmt.Sprintf("DROP TABLE %s", restoredName.FQString())},
+		}
+		for _, s := range stmts {
+			if _, err := txn.ExecEx(ctx, s.opName, txn.KV(), override, s.stmt); err != nil {
+				return errors.Wrapf(err, "merging restored rows into %s", existingName.FQString())
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backuptestutils"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRestoreRowFilter tests that a RESTORE with the row_filter option only
+// restores the rows that match the filter, along with their secondary index
+// entries, and that into_existing_table replaces the matching rows of an
+// existing table.
+func TestRestoreRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := backuptestutils.StartBackupRestoreTestCluster(t, singleNode)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (
+		customer_id INT,
+		id INT,
+		name STRING,
+		amount INT,
+		PRIMARY KEY (customer_id, id),
+		INDEX name_idx (name) STORING (amount),
+		FAMILY f1 (customer_id, id, name),
+		FAMILY f2 (amount)
+	)`)
+	sqlDB.Exec(t, `INSERT INTO d.t
+		SELECT c, i, 'name' || (i % 7)::STRING, c * 100 + i
+		FROM generate_series(1, 5) AS c, generate_series(1, 20) AS i`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO 'nodelocal://1/backup'`)
+
+	const expected = `SELECT customer_id, id, name, amount FROM d.t WHERE customer_id = 3 ORDER BY id`
+
+	t.Run("into new database", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE filtered`)
+		sqlDB.Exec(t, `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+			WITH into_db = 'filtered', row_filter = 'customer_id = 3'`)
+
+		sqlDB.CheckQueryResults(t,
+			`SELECT customer_id, id, name, amount FROM filtered.t ORDER BY id`,
+			sqlDB.QueryStr(t, expected))
+		sqlDB.CheckQueryResults(t,
+			`SELECT customer_id, id, name, amount FROM filtered.t@name_idx ORDER BY id`,
+			sqlDB.QueryStr(t, expected))
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM filtered.t WHERE customer_id != 3`, [][]string{{"0"}})
+	})
+
+	t.Run("predicate on non-key column", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE filtered_amount`)
+		sqlDB.Exec(t, `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+			WITH into_db = 'filtered_amount', row_filter = 'amount > 410'`)
+
+		sqlDB.CheckQueryResults(t,
+			`SELECT customer_id, id, name, amount FROM filtered_amount.t@name_idx ORDER BY customer_id, id`,
+			sqlDB.QueryStr(t, `SELECT customer_id, id, name, amount FROM d.t WHERE amount > 410 ORDER BY customer_id, id`))
+	})
+
+	t.Run("into existing table", func(t *testing.T) {
+		before := sqlDB.QueryStr(t, `SELECT * FROM d.t ORDER BY customer_id, id`)
+
+		// Damage the rows of one customer, and some rows of another customer
+		// which must not be touched by the restore.
+		sqlDB.Exec(t, `DELETE FROM d.t WHERE customer_id = 3 AND id > 10`)
+		sqlDB.Exec(t, `UPDATE d.t SET name = 'damaged' WHERE customer_id = 3`)
+		sqlDB.Exec(t, `INSERT INTO d.t VALUES (3, 100, 'new', 1)`)
+		sqlDB.Exec(t, `UPDATE d.t SET amount = 0 WHERE customer_id = 4`)
+		untouched := sqlDB.QueryStr(t, `SELECT * FROM d.t WHERE customer_id != 3 ORDER BY customer_id, id`)
+
+		sqlDB.Exec(t, `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+			WITH row_filter = 'customer_id = 3', into_existing_table`)
+
+		var expectedRows [][]string
+		for _, row := range before {
+			if row[0] == "3" {
+				expectedRows = append(expectedRows, row)
+			}
+		}
+		sqlDB.CheckQueryResults(t, `SELECT * FROM d.t WHERE customer_id = 3 ORDER BY id`, expectedRows)
+		sqlDB.CheckQueryResults(t,
+			`SELECT * FROM d.t@name_idx WHERE customer_id = 3 ORDER BY id`, expectedRows)
+		sqlDB.CheckQueryResults(t,
+			`SELECT * FROM d.t WHERE customer_id != 3 ORDER BY customer_id, id`, untouched)
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW TABLES FROM d] WHERE table_name = 't_restored_rows'`,
+			[][]string{{"0"}})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE errs`)
+		for _, tc := range []struct {
+			stmt string
+			err  string
+		}{
+			{
+				stmt: `RESTORE DATABASE d FROM LATEST IN 'nodelocal://1/backup'
+					WITH new_db_name = 'd2', row_filter = 'customer_id = 3'`,
+				err: "row_filter can only be used when restoring a single table",
+			},
+			{
+				stmt: `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+					WITH into_db = 'errs', into_existing_table`,
+				err: "into_existing_table can only be used with the row_filter option",
+			},
+			{
+				stmt: `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+					WITH into_db = 'errs', row_filter = 'missing = 3'`,
+				err: `column "missing" does not exist`,
+			},
+			{
+				stmt: `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+					WITH into_db = 'errs', row_filter = 'customer_id'`,
+				err: "expected RESTORE ROW FILTER expression to have type bool",
+			},
+			{
+				stmt: `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+					WITH into_db = 'errs', row_filter = 'now() > ''2020-01-01'''`,
+				err: "context-dependent operators are not allowed",
+			},
+			{
+				stmt: `RESTORE TABLE d.t FROM LATEST IN 'nodelocal://1/backup'
+					WITH into_db = 'errs', row_filter = 'customer_id = 3', into_existing_table`,
+				err: "resolving table for into_existing_table",
+			},
+		} {
+			sqlDB.ExpectErr(t, tc.err, tc.stmt)
+		}
+	})
+}
+
+// TestRowFilterPrimaryIndexSpan tests that the span read by a row_filter
+// RESTORE is narrowed to the primary key prefix constrained by the filter.
+func TestRowFilterPrimaryIndexSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc, sqlDB, _, cleanupFn := backuptestutils.StartBackupRestoreTestCluster(t, singleNode)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (a INT, b STRING, c DECIMAL, d INT, PRIMARY KEY (a, b DESC, c))`)
+
+	s := tc.ApplicationLayer(0)
+	codec := s.Codec()
+	table := desctestutils.TestingGetPublicTableDescriptor(s.DB(), codec, "d", "t")
+	prefix := rowenc.MakeIndexKeyPrefix(codec, table.GetID(), table.GetPrimaryIndexID())
+	encode := func(key []byte, d tree.Datum, dir encoding.Direction) []byte {
+		key, err := keyside.Encode(key, d, dir)
+		require.NoError(t, err)
+		return key
+	}
+	spanFor := func(key []byte) roachpb.Span {
+		return roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()}
+	}
+
+	a := encode(append([]byte(nil), prefix...), tree.NewDInt(3), encoding.Ascending)
+	ab := encode(append([]byte(nil), a...), tree.NewDString("x"), encoding.Descending)
+	for _, tc := range []struct {
+		filter   string
+		expected roachpb.Span
+	}{
+		{filter: `d = 1`, expected: spanFor(prefix)},
+		{filter: `a = 3`, expected: spanFor(a)},
+		{filter: `a = 3 AND d > 1`, expected: spanFor(a)},
+		{filter: `a = 3 OR a = 4`, expected: spanFor(prefix)},
+		{filter: `b = 'x'`, expected: spanFor(prefix)},
+		{filter: `b = 'x' AND (a = 3)`, expected: spanFor(ab)},
+		// DECIMAL values have a composite key encoding, so c never narrows the
+		// span.
+		{filter: `a = 3 AND b = 'x' AND c = 1.0`, expected: spanFor(ab)},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+			span, err := rowFilterPrimaryIndexSpan(ctx, codec, table, tc.filter, &evalCtx)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, span)
+		})
+	}
+}
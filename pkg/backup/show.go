@@ -158,6 +158,13 @@ func showBackupTypeCheck(
 	); err != nil {
 		return false, nil, err
 	}
+	if backup.Details == tree.BackupTableDetails {
+		header, err := showBackupTableTypeCheck(ctx, p, backup)
+		if err != nil {
+			return false, nil, err
+		}
+		return true, header, nil
+	}
 	infoReader := getBackupInfoReader(p, backup)
 	return true, infoReader.header(), nil
 }
@@ -192,6 +199,10 @@ func showBackupPlanHook(
 		return nil, nil, false, err
 	}
 
+	if showStmt.Details == tree.BackupTableDetails {
+		return showBackupTablePlanHook(ctx, p, showStmt, exprEval, subdir, dest)
+	}
+
 	infoReader := getBackupInfoReader(p, showStmt)
 
 	if err != nil {
@@ -202,224 +213,249 @@ func showBackupPlanHook(
 		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
 		defer span.Finish()
 
-		if err := sql.CheckDestinationPrivileges(ctx, p, dest); err != nil {
-			return err
-		}
+		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+		defer mem.Close(ctx)
 
-		if strings.EqualFold(subdir, backupbase.LatestFileName) {
-			subdir, err = backupdest.ReadLatestFile(ctx, dest[0],
-				p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
-				p.User())
-			if err != nil {
-				return errors.Wrap(err, "read LATEST path")
-			}
-		}
-		fullyResolvedDest, err := backuputils.AppendPaths(dest, subdir)
+		return withShowBackupInfo(ctx, p, showStmt, exprEval, subdir, dest, hlc.Timestamp{},
+			true /* includeSkipped */, true /* includeCompacted */, &mem,
+			func(info backupInfo) error {
+				mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+				if err := infoReader.showBackup(ctx, &mem, mkStore, info, p.User(), info.kmsEnv, resultsCh); err != nil {
+					return err
+				}
+				telemetry.Count("show-backup.collection")
+				return nil
+			})
+	}
+
+	return fn, infoReader.header(), false, nil
+}
+
+// withShowBackupInfo resolves the backup chain targeted by a SHOW BACKUP
+// statement, up to endTime if it is set, and calls fn with the resolved
+// backupInfo. The stores and memory backing the backupInfo are released once
+// fn returns.
+func withShowBackupInfo(
+	ctx context.Context,
+	p sql.PlanHookState,
+	showStmt *tree.ShowBackup,
+	exprEval exprutil.Evaluator,
+	subdir string,
+	dest []string,
+	endTime hlc.Timestamp,
+	includeSkipped, includeCompacted bool,
+	mem *mon.BoundAccount,
+	fn func(info backupInfo) error,
+) error {
+	if err := sql.CheckDestinationPrivileges(ctx, p, dest); err != nil {
+		return err
+	}
+
+	var err error
+	if strings.EqualFold(subdir, backupbase.LatestFileName) {
+		subdir, err = backupdest.ReadLatestFile(ctx, dest[0],
+			p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
+			p.User())
 		if err != nil {
-			return err
+			return errors.Wrap(err, "read LATEST path")
 		}
-		baseStores := make([]cloud.ExternalStorage, len(fullyResolvedDest))
-		for j := range fullyResolvedDest {
-			baseStores[j], err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, fullyResolvedDest[j], p.User())
-			if err != nil {
-				return errors.Wrapf(err, "make storage")
-			}
-			//nolint:deferloop
-			defer baseStores[j].Close()
+	}
+	fullyResolvedDest, err := backuputils.AppendPaths(dest, subdir)
+	if err != nil {
+		return err
+	}
+	baseStores := make([]cloud.ExternalStorage, len(fullyResolvedDest))
+	for j := range fullyResolvedDest {
+		baseStores[j], err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, fullyResolvedDest[j], p.User())
+		if err != nil {
+			return errors.Wrapf(err, "make storage")
 		}
+		//nolint:deferloop
+		defer baseStores[j].Close()
+	}
 
-		// TODO(msbutler): put encryption resolution in helper function, hopefully shared with RESTORE
+	// TODO(msbutler): put encryption resolution in helper function, hopefully shared with RESTORE
 
-		encStore := baseStores[0]
-		if showStmt.Options.EncryptionInfoDir != nil {
-			encDir, err := exprEval.String(ctx, showStmt.Options.EncryptionInfoDir)
-			if err != nil {
-				return err
-			}
-			encStore, err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, encDir, p.User())
-			if err != nil {
-				return errors.Wrap(err, "make storage")
-			}
-			defer encStore.Close()
+	encStore := baseStores[0]
+	if showStmt.Options.EncryptionInfoDir != nil {
+		encDir, err := exprEval.String(ctx, showStmt.Options.EncryptionInfoDir)
+		if err != nil {
+			return err
 		}
-		var encryption *jobspb.BackupEncryptionOptions
-		kmsEnv := backupencryption.MakeBackupKMSEnv(
-			p.ExecCfg().Settings,
-			&p.ExecCfg().ExternalIODirConfig,
-			p.ExecCfg().InternalDB,
-			p.User(),
-		)
-		showEncErr := `If you are running SHOW BACKUP exclusively on an incremental backup,
+		encStore, err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, encDir, p.User())
+		if err != nil {
+			return errors.Wrap(err, "make storage")
+		}
+		defer encStore.Close()
+	}
+	var encryption *jobspb.BackupEncryptionOptions
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		p.ExecCfg().Settings,
+		&p.ExecCfg().ExternalIODirConfig,
+		p.ExecCfg().InternalDB,
+		p.User(),
+	)
+	showEncErr := `If you are running SHOW BACKUP exclusively on an incremental backup,
 you must pass the 'encryption_info_dir' parameter that points to the directory of your full backup`
-		if showStmt.Options.EncryptionPassphrase != nil {
-			passphrase, err := exprEval.String(ctx, showStmt.Options.EncryptionPassphrase)
-			if err != nil {
-				return err
-			}
-			opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
-			if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
-				return errors.WithHint(err, showEncErr)
-			}
-			if err != nil {
-				return err
-			}
-			encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts[0].Salt)
-			encryption = &jobspb.BackupEncryptionOptions{
-				Mode: jobspb.EncryptionMode_Passphrase,
-				Key:  encryptionKey,
-			}
-		} else if showStmt.Options.DecryptionKMSURI != nil {
-			kms, err := exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.DecryptionKMSURI))
-			if err != nil {
-				return err
-			}
-			opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
-			if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
-				return errors.WithHint(err, showEncErr)
-			}
-			if err != nil {
-				return err
-			}
-			var defaultKMSInfo *jobspb.BackupEncryptionOptions_KMSInfo
-			for _, encFile := range opts {
-				defaultKMSInfo, err = backupencryption.ValidateKMSURIsAgainstFullBackup(
-					ctx,
-					kms,
-					backupencryption.NewEncryptedDataKeyMapFromProtoMap(encFile.EncryptedDataKeyByKMSMasterKeyID),
-					&kmsEnv,
-				)
-				if err == nil {
-					break
-				}
-			}
-			if err != nil {
-				return err
-			}
-			encryption = &jobspb.BackupEncryptionOptions{
-				Mode:    jobspb.EncryptionMode_KMS,
-				KMSInfo: defaultKMSInfo,
-			}
+	if showStmt.Options.EncryptionPassphrase != nil {
+		passphrase, err := exprEval.String(ctx, showStmt.Options.EncryptionPassphrase)
+		if err != nil {
+			return err
 		}
-		var explicitIncPaths []string
-		if showStmt.Options.IncrementalStorage != nil {
-			explicitIncPaths, err = exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.IncrementalStorage))
-			if err != nil {
-				return err
-			}
+		opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
+		if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
+			return errors.WithHint(err, showEncErr)
 		}
-		collections, computedSubdir, err := backupdest.CollectionsAndSubdir(dest, subdir)
 		if err != nil {
 			return err
 		}
-		fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
-			ctx,
-			p.User(),
-			p.ExecCfg(),
-			explicitIncPaths,
-			collections,
-			computedSubdir,
-		)
+		encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts[0].Salt)
+		encryption = &jobspb.BackupEncryptionOptions{
+			Mode: jobspb.EncryptionMode_Passphrase,
+			Key:  encryptionKey,
+		}
+	} else if showStmt.Options.DecryptionKMSURI != nil {
+		kms, err := exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.DecryptionKMSURI))
 		if err != nil {
-			if errors.Is(err, cloud.ErrListingUnsupported) {
-				// We can proceed with base backups here just fine, so log a warning and move on.
-				// Note that actually _writing_ an incremental backup to this location would fail loudly.
-				log.Warningf(
-					ctx, "storage sink %v does not support listing, only showing the base backup", explicitIncPaths)
-			} else {
-				return err
-			}
+			return err
+		}
+		opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
+		if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
+			return errors.WithHint(err, showEncErr)
 		}
-		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
-		defer mem.Close(ctx)
-
-		var (
-			info        backupInfo
-			memReserved int64
-		)
-		info.collectionURI = dest[0]
-		info.subdir = computedSubdir
-		info.kmsEnv = &kmsEnv
-		info.enc = encryption
-
-		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
-		incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
-			fullyResolvedIncrementalsDirectory)
 		if err != nil {
 			return err
 		}
-		defer func() {
-			if err := cleanupFn(); err != nil {
-				log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		var defaultKMSInfo *jobspb.BackupEncryptionOptions_KMSInfo
+		for _, encFile := range opts {
+			defaultKMSInfo, err = backupencryption.ValidateKMSURIsAgainstFullBackup(
+				ctx,
+				kms,
+				backupencryption.NewEncryptedDataKeyMapFromProtoMap(encFile.EncryptedDataKeyByKMSMasterKeyID),
+				&kmsEnv,
+			)
+			if err == nil {
+				break
 			}
-		}()
-
-		info.defaultURIs, info.manifests, info.localityInfo, memReserved,
-			err = backupdest.ResolveBackupManifests(
-			ctx, &mem, baseStores, incStores, mkStore, fullyResolvedDest,
-			fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, encryption, &kmsEnv, p.User(),
-			true /* includeSkipped */, true, /* includeCompacted */
-		)
-		defer func() {
-			mem.Shrink(ctx, memReserved)
-		}()
+		}
 		if err != nil {
-			if errors.Is(err, backupinfo.ErrLocalityDescriptor) && subdir == "" {
-				p.BufferClientNotice(ctx,
-					pgnotice.Newf("`SHOW BACKUP` using the old syntax ("+
-						"without the `IN` keyword) on a locality aware backup does not display or validate"+
-						" data specific to locality aware backups. "+
-						"Consider using the new `BACKUP INTO` syntax and `SHOW BACKUP"+
-						" FROM <backup> IN <collection>`"))
-			} else if errors.Is(err, cloud.ErrFileDoesNotExist) {
-				latestFileExists, errLatestFile := backupdest.CheckForLatestFileInCollection(ctx, baseStores[0])
-
-				if errLatestFile == nil && latestFileExists {
-					return errors.WithHintf(err, "The specified path is the root of a backup collection. "+
-						"Use SHOW BACKUPS IN with this path to list all the backup subdirectories in the"+
-						" collection. SHOW BACKUP can be used with any of these subdirectories to inspect a"+
-						" backup.")
-				}
-				return errors.CombineErrors(err, errLatestFile)
-			} else {
-				return err
-			}
+			return err
 		}
-
-		info.layerToIterFactory, err = backupinfo.GetBackupManifestIterFactories(ctx, p.ExecCfg().DistSQLSrv.ExternalStorage, info.manifests, info.enc, info.kmsEnv)
+		encryption = &jobspb.BackupEncryptionOptions{
+			Mode:    jobspb.EncryptionMode_KMS,
+			KMSInfo: defaultKMSInfo,
+		}
+	}
+	var explicitIncPaths []string
+	if showStmt.Options.IncrementalStorage != nil {
+		explicitIncPaths, err = exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.IncrementalStorage))
 		if err != nil {
 			return err
 		}
+	}
+	collections, computedSubdir, err := backupdest.CollectionsAndSubdir(dest, subdir)
+	if err != nil {
+		return err
+	}
+	fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx,
+		p.User(),
+		p.ExecCfg(),
+		explicitIncPaths,
+		collections,
+		computedSubdir,
+	)
+	if err != nil {
+		if errors.Is(err, cloud.ErrListingUnsupported) {
+			// We can proceed with base backups here just fine, so log a warning and move on.
+			// Note that actually _writing_ an incremental backup to this location would fail loudly.
+			log.Warningf(
+				ctx, "storage sink %v does not support listing, only showing the base backup", explicitIncPaths)
+		} else {
+			return err
+		}
+	}
+	var (
+		info        backupInfo
+		memReserved int64
+	)
+	info.collectionURI = dest[0]
+	info.subdir = computedSubdir
+	info.kmsEnv = &kmsEnv
+	info.enc = encryption
+
+	mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+	incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
+		fullyResolvedIncrementalsDirectory)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupFn(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
 
-		// If backup is locality aware, check that user passed at least some localities.
-
-		// TODO (msbutler): this is an extremely crude check that the user is
-		// passing at least as many URIS as there are localities in the backup. This
-		// check is only meant for the 22.1 backport. Ben is working on a much more
-		// robust check.
-		for _, locMap := range info.localityInfo {
-			if len(locMap.URIsByOriginalLocalityKV) > len(dest) && subdir != "" {
-				p.BufferClientNotice(ctx,
-					pgnotice.Newf("The backup contains %d localities; however, "+
-						"the SHOW BACKUP command contains only %d URIs. To capture all locality aware data, "+
-						"pass every locality aware URI from the backup", len(locMap.URIsByOriginalLocalityKV),
-						len(dest)))
+	info.defaultURIs, info.manifests, info.localityInfo, memReserved,
+		err = backupdest.ResolveBackupManifests(
+		ctx, mem, baseStores, incStores, mkStore, fullyResolvedDest,
+		fullyResolvedIncrementalsDirectory, endTime, encryption, &kmsEnv, p.User(),
+		includeSkipped, includeCompacted,
+	)
+	defer func() {
+		mem.Shrink(ctx, memReserved)
+	}()
+	if err != nil {
+		if errors.Is(err, backupinfo.ErrLocalityDescriptor) && subdir == "" {
+			p.BufferClientNotice(ctx,
+				pgnotice.Newf("`SHOW BACKUP` using the old syntax ("+
+					"without the `IN` keyword) on a locality aware backup does not display or validate"+
+					" data specific to locality aware backups. "+
+					"Consider using the new `BACKUP INTO` syntax and `SHOW BACKUP"+
+					" FROM <backup> IN <collection>`"))
+		} else if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			latestFileExists, errLatestFile := backupdest.CheckForLatestFileInCollection(ctx, baseStores[0])
+
+			if errLatestFile == nil && latestFileExists {
+				return errors.WithHintf(err, "The specified path is the root of a backup collection. "+
+					"Use SHOW BACKUPS IN with this path to list all the backup subdirectories in the"+
+					" collection. SHOW BACKUP can be used with any of these subdirectories to inspect a"+
+					" backup.")
 			}
+			return errors.CombineErrors(err, errLatestFile)
+		} else {
+			return err
 		}
-		if showStmt.Options.CheckFiles {
-			fileSizes, err := checkBackupFiles(ctx, info, p.ExecCfg(), p.User(), encryption, &kmsEnv)
-			if err != nil {
-				return err
-			}
-			info.fileSizes = fileSizes
+	}
+
+	info.layerToIterFactory, err = backupinfo.GetBackupManifestIterFactories(ctx, p.ExecCfg().DistSQLSrv.ExternalStorage, info.manifests, info.enc, info.kmsEnv)
+	if err != nil {
+		return err
+	}
+
+	// If backup is locality aware, check that user passed at least some localities.
+
+	// TODO (msbutler): this is an extremely crude check that the user is
+	// passing at least as many URIS as there are localities in the backup. This
+	// check is only meant for the 22.1 backport. Ben is working on a much more
+	// robust check.
+	for _, locMap := range info.localityInfo {
+		if len(locMap.URIsByOriginalLocalityKV) > len(dest) && subdir != "" {
+			p.BufferClientNotice(ctx,
+				pgnotice.Newf("The backup contains %d localities; however, "+
+					"the SHOW BACKUP command contains only %d URIs. To capture all locality aware data, "+
+					"pass every locality aware URI from the backup", len(locMap.URIsByOriginalLocalityKV),
+					len(dest)))
 		}
-		if err := infoReader.showBackup(ctx, &mem, mkStore, info, p.User(), &kmsEnv, resultsCh); err != nil {
+	}
+	if showStmt.Options.CheckFiles {
+		fileSizes, err := checkBackupFiles(ctx, info, p.ExecCfg(), p.User(), encryption, &kmsEnv)
+		if err != nil {
 			return err
 		}
-		telemetry.Count("show-backup.collection")
-		return nil
+		info.fileSizes = fileSizes
 	}
-
-	return fn, infoReader.header(), false, nil
+	return fn(info)
 }
 
 func getBackupInfoReader(p sql.PlanHookState, showStmt *tree.ShowBackup) backupInfoReader {
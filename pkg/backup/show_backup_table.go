@@ -0,0 +1,309 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// showBackupTableTarget is the table targeted by a SHOW BACKUP TABLE
+// statement, as resolved while planning the statement.
+type showBackupTableTarget struct {
+	subdir  string
+	dest    []string
+	endTime hlc.Timestamp
+
+	tableID descpb.ID
+	version descpb.DescriptorVersion
+	header  colinfo.ResultColumns
+	// filter is the WHERE clause of the statement, if any, validated against
+	// the table and with its column references dequalified.
+	filter string
+}
+
+// showBackupTableTypeCheck returns the columns of the table targeted by a
+// SHOW BACKUP TABLE statement. The table is resolved from the backup, so the
+// statement cannot be prepared with placeholders in place of its backup
+// location.
+func showBackupTableTypeCheck(
+	ctx context.Context, p sql.PlanHookState, showStmt *tree.ShowBackup,
+) (colinfo.ResultColumns, error) {
+	exprEval := p.ExprEvaluator("SHOW BACKUP")
+	subdir, err := exprEval.String(ctx, showStmt.Path)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := exprEval.StringArray(ctx, tree.Exprs(showStmt.InCollection))
+	if err != nil {
+		return nil, err
+	}
+	target, err := resolveShowBackupTableTarget(ctx, p, showStmt, exprEval, subdir, dest)
+	if err != nil {
+		return nil, err
+	}
+	return target.header, nil
+}
+
+// showBackupTablePlanHook plans a SHOW BACKUP TABLE statement, which returns
+// the rows of a table as of the end time of a backup, or as of the AS OF SYSTEM
+// TIME of the statement if the backup has revision history. The rows are read
+// from the backup files directly, without restoring them into the cluster, so
+// the statement can be used as the data source of a query to inspect backed up
+// data in place.
+//
+// The WHERE clause of the statement is evaluated against each row read from
+// the backup. If it constrains a prefix of the primary key columns to
+// constants, only the matching span of the primary index is read, and only the
+// files overlapping that span are opened. A filter applied by an enclosing
+// query is not pushed down and requires reading the whole table.
+func showBackupTablePlanHook(
+	ctx context.Context,
+	p sql.PlanHookState,
+	showStmt *tree.ShowBackup,
+	exprEval exprutil.Evaluator,
+	subdir string,
+	dest []string,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	target, err := resolveShowBackupTableTarget(ctx, p, showStmt, exprEval, subdir, dest)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, showStmt.StatementTag())
+		defer span.Finish()
+
+		return withShowBackupTable(ctx, p, showStmt, exprEval, target,
+			func(info backupInfo, table catalog.TableDescriptor) error {
+				// The subdirectory may have been LATEST, in which case a newer
+				// backup could have been taken since the statement was planned.
+				if table.GetID() != target.tableID || table.GetVersion() != target.version {
+					return errors.Newf(
+						"table %q in the backup changed after the statement was planned",
+						tree.ErrString(showStmt.Table))
+				}
+				spans, keep, err := makeShowBackupTableFilter(ctx, p, info, table, target.filter)
+				if err != nil {
+					return err
+				}
+				if err := backupinfo.ScanBackupTable(
+					ctx, p.ExecCfg().DistSQLSrv.ExternalStorage, p.User(), info.manifests,
+					info.layerToIterFactory, info.localityInfo, info.enc, info.kmsEnv, table, spans,
+					showBackupTableReadTime(info, target.endTime),
+					func(row tree.Datums) error {
+						if ok, err := keep(row); err != nil || !ok {
+							return err
+						}
+						select {
+						case <-ctx.Done():
+							return ctx.Err()
+						case resultsCh <- row:
+							return nil
+						}
+					},
+				); err != nil {
+					return err
+				}
+				telemetry.Count("show-backup.table")
+				return nil
+			})
+	}
+	return fn, target.header, false, nil
+}
+
+// resolveShowBackupTableTarget evaluates the AS OF SYSTEM TIME of a SHOW
+// BACKUP TABLE statement and resolves the table it targets in the backup.
+func resolveShowBackupTableTarget(
+	ctx context.Context,
+	p sql.PlanHookState,
+	showStmt *tree.ShowBackup,
+	exprEval exprutil.Evaluator,
+	subdir string,
+	dest []string,
+) (showBackupTableTarget, error) {
+	if showStmt.Options.AsJson {
+		return showBackupTableTarget{}, errors.New("as_json cannot be used with SHOW BACKUP TABLE")
+	}
+	target := showBackupTableTarget{subdir: subdir, dest: dest}
+	if showStmt.AsOf.Expr != nil {
+		asOf, err := p.EvalAsOfTimestamp(ctx, showStmt.AsOf)
+		if err != nil {
+			return showBackupTableTarget{}, err
+		}
+		target.endTime = asOf.Timestamp
+	}
+	if err := withShowBackupTable(ctx, p, showStmt, exprEval, target,
+		func(_ backupInfo, table catalog.TableDescriptor) error {
+			target.tableID = table.GetID()
+			target.version = table.GetVersion()
+			if showStmt.Where != nil {
+				filter, err := validateShowBackupTableFilter(ctx, p, table, showStmt.Where.Expr)
+				if err != nil {
+					return err
+				}
+				target.filter = filter
+			}
+			for _, col := range backupinfo.BackupTableColumns(table) {
+				target.header = append(target.header, colinfo.ResultColumn{
+					Name:   col.GetName(),
+					Typ:    col.GetType(),
+					Hidden: col.IsHidden(),
+				})
+			}
+			return nil
+		}); err != nil {
+		return showBackupTableTarget{}, err
+	}
+	return target, nil
+}
+
+// withShowBackupTable resolves the backup chain targeted by a SHOW BACKUP
+// TABLE statement the way a RESTORE of the table would, and calls fn with the
+// descriptor of the table in the backup.
+func withShowBackupTable(
+	ctx context.Context,
+	p sql.PlanHookState,
+	showStmt *tree.ShowBackup,
+	exprEval exprutil.Evaluator,
+	target showBackupTableTarget,
+	fn func(info backupInfo, table catalog.TableDescriptor) error,
+) error {
+	mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	includeCompacted := restoreCompactedBackups.Get(&p.ExecCfg().Settings.SV)
+	return withShowBackupInfo(ctx, p, showStmt, exprEval, target.subdir, target.dest, target.endTime,
+		false /* includeSkipped */, includeCompacted, &mem,
+		func(info backupInfo) error {
+			tn := showStmt.Table.ToTableName()
+			targets := tree.BackupTargetList{
+				Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{&tn}},
+			}
+			descs, _, descsByTablePattern, _, err := selectTargets(
+				ctx, p, info.manifests, info.layerToIterFactory, targets,
+				tree.RequestedDescriptors, target.endTime,
+			)
+			if err != nil {
+				return err
+			}
+			if err := maybeUpgradeDescriptors(
+				p.ExecCfg().Settings.Version.ActiveVersion(ctx), descs, true, /* skipFKsWithNoMatchingTable */
+			); err != nil {
+				return err
+			}
+			tableID := descsByTablePattern[&tn].GetID()
+			var table catalog.TableDescriptor
+			for _, desc := range descs {
+				if desc.GetID() == tableID {
+					table, _ = desc.(catalog.TableDescriptor)
+				}
+			}
+			if table == nil || !table.IsPhysicalTable() || table.IsSequence() {
+				return errors.Newf("%q is not a table", tree.ErrString(&tn))
+			}
+			for _, col := range backupinfo.BackupTableColumns(table) {
+				if col.GetType().UserDefined() {
+					return errors.Newf(
+						"SHOW BACKUP TABLE cannot be used on table %q: column %q has a user-defined type",
+						table.GetName(), col.GetName())
+				}
+			}
+			return fn(info, table)
+		})
+}
+
+// validateShowBackupTableFilter validates the WHERE clause of a SHOW BACKUP
+// TABLE statement against the table in the backup, returning it with its
+// column references dequalified.
+func validateShowBackupTableFilter(
+	ctx context.Context, p sql.PlanHookState, table catalog.TableDescriptor, expr tree.Expr,
+) (string, error) {
+	version := p.ExecCfg().Settings.Version.ActiveVersion(ctx)
+	tn := tree.MakeUnqualifiedTableName(tree.Name(table.GetName()))
+	filter, cols, err := schemaexpr.ValidateRowFilter(ctx, table, expr, &tn, p.SemaCtx(), version)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid WHERE clause")
+	}
+	for _, colID := range cols.Ordered() {
+		col, err := catalog.MustFindColumnByID(table, colID)
+		if err != nil {
+			return "", err
+		}
+		if !isRowFilterDecodable(table, col) {
+			return "", errors.Newf("WHERE clause cannot reference virtual column %q", col.GetName())
+		}
+	}
+	return filter, nil
+}
+
+// makeShowBackupTableFilter returns the spans of the primary index of the
+// table that can contain rows matching the filter, and a function that returns
+// whether a row returned by backupinfo.ScanBackupTable matches it. Without a
+// filter, the whole primary index is read and every row matches.
+func makeShowBackupTableFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	info backupInfo,
+	table catalog.TableDescriptor,
+	filter string,
+) (roachpb.Spans, func(tree.Datums) (bool, error), error) {
+	if filter == "" {
+		return nil, func(tree.Datums) (bool, error) { return true, nil }, nil
+	}
+	codec, err := backupinfo.MakeBackupCodec(info.manifests)
+	if err != nil {
+		return nil, nil, err
+	}
+	evalCtx := p.ExtendedEvalContext().Context.Copy()
+	span, err := rowFilterPrimaryIndexSpan(ctx, codec, table, filter, evalCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	expr, err := schemaexpr.MakeRowFilterExpr(ctx, table, filter, evalCtx, &semaCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	ivars := schemaexpr.RowIndexedVarContainer{Cols: table.PublicColumns()}
+	for i, col := range backupinfo.BackupTableColumns(table) {
+		ivars.Mapping.Set(col.GetID(), i)
+	}
+	keep := func(row tree.Datums) (bool, error) {
+		ivars.CurSourceRow = row
+		evalCtx.PushIVarContainer(&ivars)
+		defer evalCtx.PopIVarContainer()
+		res, err := eval.Expr(ctx, evalCtx, expr)
+		if err != nil {
+			return false, errors.Wrap(err, "evaluating WHERE clause")
+		}
+		return res == tree.DBoolTrue, nil
+	}
+	return roachpb.Spans{span}, keep, nil
+}
+
+// showBackupTableReadTime returns the time as of which the rows of a SHOW
+// BACKUP TABLE statement are read.
+func showBackupTableReadTime(info backupInfo, endTime hlc.Timestamp) hlc.Timestamp {
+	if !endTime.IsEmpty() {
+		return endTime
+	}
+	return info.manifests[len(info.manifests)-1].EndTime
+}
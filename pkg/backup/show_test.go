@@ -883,3 +883,103 @@ func TestShowBackupCheckFiles(t *testing.T) {
 		}
 	}
 }
+
+// TestShowBackupTable tests that SHOW BACKUP TABLE, and the [BACKUP ...].db.t
+// table expression, return the rows of a table in a backup chain, as of the
+// end of the chain or as of an AS OF SYSTEM TIME in a backup with revision
+// history, and that they can be queried like a table.
+func TestShowBackupTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, 0, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (
+		id INT PRIMARY KEY,
+		name STRING,
+		amount DECIMAL,
+		FAMILY f1 (id, name),
+		FAMILY f2 (amount)
+	)`)
+	sqlDB.Exec(t, `CREATE TABLE d.no_pk (a INT, b STRING)`)
+	sqlDB.Exec(t, `CREATE TYPE d.e AS ENUM ('a', 'b')`)
+	sqlDB.Exec(t, `CREATE TABLE d.udt (id INT PRIMARY KEY, e d.e)`)
+	sqlDB.Exec(t, `INSERT INTO d.t SELECT i, 'name' || i::STRING, i * 1.5 FROM generate_series(1, 50) AS i`)
+	sqlDB.Exec(t, `INSERT INTO d.no_pk VALUES (1, 'a'), (2, NULL)`)
+
+	const dest = `'nodelocal://1/backup'`
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO `+dest+` WITH revision_history`)
+	fullRows := sqlDB.QueryStr(t, `SELECT * FROM d.t ORDER BY id`)
+
+	sqlDB.Exec(t, `UPDATE d.t SET amount = amount + 1 WHERE id % 2 = 0`)
+	var beforeDelete string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&beforeDelete)
+	updatedRows := sqlDB.QueryStr(t, `SELECT * FROM d.t ORDER BY id`)
+	sqlDB.Exec(t, `DELETE FROM d.t WHERE id > 40`)
+	sqlDB.Exec(t, `INSERT INTO d.t VALUES (100, 'new', NULL)`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN `+dest+` WITH revision_history`)
+	latestRows := sqlDB.QueryStr(t, `SELECT * FROM d.t ORDER BY id`)
+
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM [SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+`] ORDER BY id`, latestRows)
+	sqlDB.CheckQueryResults(t,
+		`SELECT name, amount FROM [SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+`] WHERE id = 100`,
+		[][]string{{"new", "NULL"}})
+
+	// A WHERE clause of the statement itself constrains the primary index
+	// spans read from the backup, and filters the rows on other columns.
+	sqlDB.CheckQueryResults(t,
+		`SELECT name, amount FROM [SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+` WHERE id = 100]`,
+		[][]string{{"new", "NULL"}})
+	sqlDB.CheckQueryResults(t,
+		`SELECT id FROM [SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+` WHERE id = 7 AND name = 'name7']`,
+		[][]string{{"7"}})
+	sqlDB.CheckQueryResults(t,
+		`SELECT id FROM [SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+` WHERE id = 7 AND name = 'other']`,
+		[][]string{})
+	sqlDB.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+` WHERE amount > 30]`,
+		sqlDB.QueryStr(t, `SELECT count(*) FROM d.t WHERE amount > 30`))
+	sqlDB.ExpectErr(t, `invalid WHERE clause`,
+		`SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+` WHERE missing = 1`)
+
+	// Read the chain as of a time before the incremental backup.
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT * FROM [SHOW BACKUP TABLE d.t FROM LATEST IN %s AS OF SYSTEM TIME %s] ORDER BY id`,
+			dest, beforeDelete),
+		updatedRows)
+
+	// Read only the full backup.
+	fullSubdir := sqlDB.QueryStr(t, `SHOW BACKUPS IN `+dest)[0][0]
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT * FROM [SHOW BACKUP TABLE d.public.t FROM '%s' IN %s] ORDER BY id`, fullSubdir, dest),
+		fullRows)
+
+	// A table in a backup can also be used as a table expression, which reads
+	// the latest backup of the collection unless a subdirectory is given.
+	sqlDB.CheckQueryResults(t, `SELECT * FROM [BACKUP `+dest+`].d.t ORDER BY id`, latestRows)
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT * FROM [BACKUP %s AS OF SYSTEM TIME %s].d.t WHERE t.id >= 0 ORDER BY t.id`,
+			dest, beforeDelete),
+		updatedRows)
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT b.* FROM [BACKUP '%s' IN %s].d.public.t AS b ORDER BY b.id`, fullSubdir, dest),
+		fullRows)
+
+	// The hidden rowid column is only returned when it is asked for.
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM [SHOW BACKUP TABLE d.no_pk FROM LATEST IN `+dest+`] ORDER BY a`,
+		[][]string{{"1", "a"}, {"2", "NULL"}})
+	sqlDB.CheckQueryResults(t,
+		`SELECT count(DISTINCT rowid) FROM [SHOW BACKUP TABLE d.no_pk FROM LATEST IN `+dest+`]`,
+		[][]string{{"2"}})
+
+	sqlDB.ExpectErr(t, `table "d.missing" does not exist`,
+		`SHOW BACKUP TABLE d.missing FROM LATEST IN `+dest)
+	sqlDB.ExpectErr(t, `column "e" has a user-defined type`,
+		`SHOW BACKUP TABLE d.udt FROM LATEST IN `+dest)
+	sqlDB.ExpectErr(t, `as_json cannot be used with SHOW BACKUP TABLE`,
+		`SHOW BACKUP TABLE d.t FROM LATEST IN `+dest+` WITH as_json`)
+}
@@ -129,6 +129,12 @@ func alterChangefeedPlanHook(
 			return errors.Errorf(`job %d is not paused`, jobID)
 		}
 
+		if len(prevDetails.TargetScopes) > 0 {
+			return errors.Errorf(
+				`ALTER CHANGEFEED is not supported for changefeed %d, which targets a database or wildcard pattern`,
+				jobID)
+		}
+
 		newChangefeedStmt := &tree.CreateChangefeed{}
 
 		prevOpts, err := getPrevOpts(job.Payload().Description, prevDetails.Opts)
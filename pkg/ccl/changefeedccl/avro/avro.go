@@ -222,6 +222,23 @@ type EnvelopeOpts struct {
 	UpdatedField, ResolvedField          bool
 	MVCCTimestampField                   bool
 	OpField, TsField, SourceField        bool
+	SchemaChangeField                    bool
+}
+
+// SchemaChange is the metadata of a schema change event, encoded in the
+// schema_change field of an envelope.
+type SchemaChange struct {
+	Table                       string
+	TableID                     int64
+	Statement                   string
+	Timestamp                   hlc.Timestamp
+	ColumnsBefore, ColumnsAfter []SchemaChangeColumn
+}
+
+// SchemaChangeColumn is a visible column of a table before or after a schema
+// change.
+type SchemaChangeColumn struct {
+	Name, Type string
 }
 
 // EnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -234,6 +251,8 @@ type EnvelopeRecord struct {
 	Opts               EnvelopeOpts      `json:"-"`
 	Before, After, Rec *DataRecord       `json:"-"`
 	Source             *FunctionalRecord `json:"-"`
+
+	schemaChange *Record
 }
 
 // typeToSchema converts a database type to an avro field
@@ -1078,6 +1097,15 @@ func NewEnvelopeRecord(
 		}
 		schema.Fields = append(schema.Fields, opField)
 	}
+	if opts.SchemaChangeField {
+		schema.schemaChange = newSchemaChangeRecord(topic, namespace)
+		schemaChangeField := &SchemaField{
+			Name:       `schema_change`,
+			SchemaType: []SchemaType{SchemaTypeNull, schema.schemaChange},
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, schemaChangeField)
+	}
 
 	schemaJSON, err := json.Marshal(schema)
 	if err != nil {
@@ -1199,6 +1227,19 @@ func (r *EnvelopeRecord) BinaryFromRow(
 		}
 	}
 
+	if r.Opts.SchemaChangeField {
+		native[`schema_change`] = nil
+		if u, ok := meta[`schema_change`]; ok {
+			delete(meta, `schema_change`)
+			sc, ok := u.(SchemaChange)
+			if !ok {
+				return nil, changefeedbase.WithTerminalError(
+					errors.Errorf(`unknown metadata schema change type: %T`, u))
+			}
+			native[`schema_change`] = goavro.Union(unionKey(r.schemaChange), sc.native())
+		}
+	}
+
 	for k := range meta {
 		return nil, changefeedbase.WithTerminalError(errors.AssertionFailedf(`unhandled meta key: %s`, k))
 	}
@@ -1209,6 +1250,65 @@ func (r *EnvelopeRecord) BinaryFromRow(
 	return buf, nil
 }
 
+// newSchemaChangeRecord returns the schema of the schema_change field of the
+// envelope of the given topic.
+func newSchemaChangeRecord(topic, namespace string) *Record {
+	optional := func(name string, typ SchemaType) *SchemaField {
+		return &SchemaField{Name: name, SchemaType: []SchemaType{SchemaTypeNull, typ}}
+	}
+	column := &Record{
+		Name:       changefeedbase.SQLNameToAvroName(topic) + `_schema_change_column`,
+		SchemaType: `record`,
+		Namespace:  namespace,
+		Fields: []*SchemaField{
+			optional(`name`, SchemaTypeString),
+			optional(`type`, SchemaTypeString),
+		},
+	}
+	return &Record{
+		Name:       changefeedbase.SQLNameToAvroName(topic) + `_schema_change`,
+		SchemaType: `record`,
+		Namespace:  namespace,
+		Fields: []*SchemaField{
+			optional(`table`, SchemaTypeString),
+			optional(`table_id`, SchemaTypeLong),
+			optional(`statement`, SchemaTypeString),
+			optional(`timestamp`, SchemaTypeString),
+			// The column record is defined by the first field using it and
+			// referenced by name afterwards.
+			optional(`columns_before`, ArrayType{SchemaType: SchemaTypeArray, Items: column}),
+			optional(`columns_after`, ArrayType{SchemaType: SchemaTypeArray, Items: unionKey(column)}),
+		},
+	}
+}
+
+// native returns the schema change in the format expected by the codec of the
+// record returned by newSchemaChangeRecord.
+func (sc SchemaChange) native() map[string]interface{} {
+	columns := func(cols []SchemaChangeColumn) interface{} {
+		res := make([]interface{}, len(cols))
+		for i, col := range cols {
+			res[i] = map[string]interface{}{
+				`name`: goavro.Union(SchemaTypeString, col.Name),
+				`type`: goavro.Union(SchemaTypeString, col.Type),
+			}
+		}
+		return goavro.Union(SchemaTypeArray, res)
+	}
+	var statement interface{}
+	if sc.Statement != `` {
+		statement = goavro.Union(SchemaTypeString, sc.Statement)
+	}
+	return map[string]interface{}{
+		`table`:          goavro.Union(SchemaTypeString, sc.Table),
+		`table_id`:       goavro.Union(SchemaTypeLong, sc.TableID),
+		`statement`:      statement,
+		`timestamp`:      goavro.Union(SchemaTypeString, sc.Timestamp.AsOfSystemTime()),
+		`columns_before`: columns(sc.ColumnsBefore),
+		`columns_after`:  columns(sc.ColumnsAfter),
+	}
+}
+
 // Refresh the metadata for user-defined types on a cached schema
 // The only user-defined type is enum, so this is usually a no-op.
 func (r *DataRecord) RefreshTypeMetadata(row cdcevent.Row) error {
@@ -39,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/collatedstring"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
@@ -872,6 +873,51 @@ func TestAvroMigration(t *testing.T) {
 	}
 }
 
+func TestAvroSchemaChangeEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	schema, err := NewEnvelopeRecord(`foo`, EnvelopeOpts{SchemaChangeField: true},
+		nil /* before */, nil /* after */, nil /* record */, nil /* source */, `ns`)
+	require.NoError(t, err)
+
+	sc := SchemaChange{
+		Table:     `foo`,
+		TableID:   104,
+		Statement: `ALTER TABLE foo ADD COLUMN b INT8`,
+		Timestamp: hlc.Timestamp{WallTime: 1, Logical: 2},
+		ColumnsBefore: []SchemaChangeColumn{
+			{Name: `a`, Type: `INT8`},
+		},
+		ColumnsAfter: []SchemaChangeColumn{
+			{Name: `a`, Type: `INT8`},
+			{Name: `b`, Type: `INT8`},
+		},
+	}
+	var nilRow cdcevent.Row
+	buf, err := schema.BinaryFromRow(nil, Metadata{`schema_change`: sc}, nilRow, nilRow, nilRow, ``)
+	require.NoError(t, err)
+
+	native, rest, err := schema.codec.NativeFromBinary(buf)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	textual, err := schema.codec.TextualFromNative(nil, native)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"schema_change": {"ns.foo_schema_change": {
+		"table": {"string": "foo"},
+		"table_id": {"long": 104},
+		"statement": {"string": "ALTER TABLE foo ADD COLUMN b INT8"},
+		"timestamp": {"string": "1.0000000002"},
+		"columns_before": {"array": [
+			{"name": {"string": "a"}, "type": {"string": "INT8"}}
+		]},
+		"columns_after": {"array": [
+			{"name": {"string": "a"}, "type": {"string": "INT8"}},
+			{"name": {"string": "b"}, "type": {"string": "INT8"}}
+		]}
+	}}}`, string(textual))
+}
+
 func TestDecimalRatRoundtrip(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -12,7 +12,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -56,6 +58,57 @@ type BatchBuffer interface {
 	Close() (SinkPayload, error)
 }
 
+// rejectedMessage is a message that a sink permanently rejected, for example
+// because it exceeds the maximum message size of the sink.
+type rejectedMessage struct {
+	topic      TopicDescriptor
+	topicName  string
+	key, value []byte
+	mvcc       hlc.Timestamp
+	reason     error
+}
+
+// rejectedMessageHandler handles a message that a sink permanently rejected.
+// If it returns nil, the message is considered emitted.
+type rejectedMessageHandler func(ctx context.Context, m rejectedMessage) error
+
+// sinkWithRejectedMessageHandler is implemented by sinks and sink clients that
+// can hand the messages they permanently reject to a handler rather than
+// failing the changefeed.
+type sinkWithRejectedMessageHandler interface {
+	setRejectedMessageHandler(rejectedMessageHandler)
+}
+
+// sinkWithTransactions is implemented by sinks and sink clients that can
+// commit the messages they emit atomically along with a marker holding the
+// resolved spans that the messages cover. It is used by changefeeds created
+// with the exactly_once option.
+type sinkWithTransactions interface {
+	// beginTransactions switches the sink to emit messages in transactions
+	// using the given transactional ID, fencing any other producer that uses
+	// the ID. It must be called before any rows are emitted.
+	beginTransactions(ctx context.Context, transactionalID string) error
+	// commitTransaction commits the messages flushed since the previous
+	// commit along with the marker. The sink must be flushed first.
+	commitTransaction(ctx context.Context, marker jobspb.ResolvedSpans) error
+	// recoverTransactions fences the producers whose transactional IDs start
+	// with the prefix and returns the last marker each of them committed.
+	recoverTransactions(ctx context.Context, transactionalIDPrefix string) ([]jobspb.ResolvedSpans, error)
+}
+
+// transactionalIDPrefix returns the prefix of the transactional IDs used by
+// the aggregators of a changefeed created with the exactly_once option.
+func transactionalIDPrefix(jobID jobspb.JobID) string {
+	return fmt.Sprintf("crdb-changefeed-%d-", jobID)
+}
+
+// transactionalID returns the transactional ID used by the aggregator running
+// on the given SQL instance. It is stable across restarts of the changefeed so
+// that an aggregator fences the producers of its previous runs.
+func transactionalID(jobID jobspb.JobID, instanceID base.SQLInstanceID) string {
+	return fmt.Sprintf("%s%d", transactionalIDPrefix(jobID), instanceID)
+}
+
 // SinkPayload is an interface representing a sink-specific representation of a
 // batch of messages that is ready to be emitted by its Flush method.
 type SinkPayload interface{}
@@ -104,6 +157,7 @@ type attributes struct {
 	tableName string
 	headers   map[string][]byte
 	mvcc      hlc.Timestamp
+	topic     TopicDescriptor
 }
 
 type rowEvent struct {
@@ -116,6 +170,48 @@ type rowEvent struct {
 	mvcc  hlc.Timestamp
 }
 
+var _ sinkWithRejectedMessageHandler = (*batchingSink)(nil)
+
+// setRejectedMessageHandler implements the sinkWithRejectedMessageHandler
+// interface. The handler is passed on to the sink client if it supports
+// one, and must be set before any rows are emitted.
+func (s *batchingSink) setRejectedMessageHandler(h rejectedMessageHandler) {
+	if c, ok := s.client.(sinkWithRejectedMessageHandler); ok {
+		c.setRejectedMessageHandler(h)
+	}
+}
+
+var _ sinkWithTransactions = (*batchingSink)(nil)
+
+// beginTransactions implements the sinkWithTransactions interface.
+func (s *batchingSink) beginTransactions(ctx context.Context, transactionalID string) error {
+	c, ok := s.client.(sinkWithTransactions)
+	if !ok {
+		return errors.AssertionFailedf("sink client %T does not support transactions", s.client)
+	}
+	return c.beginTransactions(ctx, transactionalID)
+}
+
+// commitTransaction implements the sinkWithTransactions interface.
+func (s *batchingSink) commitTransaction(ctx context.Context, marker jobspb.ResolvedSpans) error {
+	c, ok := s.client.(sinkWithTransactions)
+	if !ok {
+		return errors.AssertionFailedf("sink client %T does not support transactions", s.client)
+	}
+	return c.commitTransaction(ctx, marker)
+}
+
+// recoverTransactions implements the sinkWithTransactions interface.
+func (s *batchingSink) recoverTransactions(
+	ctx context.Context, transactionalIDPrefix string,
+) ([]jobspb.ResolvedSpans, error) {
+	c, ok := s.client.(sinkWithTransactions)
+	if !ok {
+		return nil, errors.AssertionFailedf("sink client %T does not support transactions", s.client)
+	}
+	return c.recoverTransactions(ctx, transactionalIDPrefix)
+}
+
 // Flush implements the Sink interface, returning the first error that has
 // occured in the past EmitRow calls.
 func (s *batchingSink) Flush(ctx context.Context) error {
@@ -313,6 +409,7 @@ func (sb *sinkBatch) Append(ctx context.Context, e *rowEvent) {
 		tableName: e.topicDescriptor.GetTableName(),
 		headers:   e.headers,
 		mvcc:      e.mvcc,
+		topic:     e.topicDescriptor,
 	})
 
 	sb.keys.Add(hashToInt(sb.hasher, e.key))
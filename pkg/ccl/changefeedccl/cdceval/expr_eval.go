@@ -39,6 +39,7 @@ type Evaluator struct {
 	user        username.SQLUsername
 	sessionData *sessiondata.SessionData
 	withDiff    bool
+	lookupTS    changefeedbase.LookupJoinTimestamp
 	familyEval  map[descpb.FamilyID]*familyEvaluator
 }
 
@@ -58,6 +59,10 @@ type familyEvaluator struct {
 	prevRowTuple *tree.DTuple
 	alloc        tree.DatumAlloc
 
+	// lookups are the tables joined into the expression.
+	lookups  lookupJoins
+	lookupTS changefeedbase.LookupJoinTimestamp
+
 	// Execution context.
 	execCfg     *sql.ExecutorConfig
 	user        username.SQLUsername
@@ -84,6 +89,7 @@ func NewEvaluator(
 	sd *sessiondata.SessionData,
 	statementTS hlc.Timestamp,
 	withDiff bool,
+	lookupTS changefeedbase.LookupJoinTimestamp,
 ) *Evaluator {
 	return &Evaluator{
 		sc:          sc,
@@ -92,6 +98,7 @@ func NewEvaluator(
 		sessionData: sd,
 		statementTS: statementTS,
 		withDiff:    withDiff,
+		lookupTS:    lookupTS,
 		familyEval:  make(map[descpb.FamilyID]*familyEvaluator, 1), // usually, just 1 family.
 	}
 }
@@ -105,7 +112,13 @@ func newFamilyEvaluator(
 	sd *sessiondata.SessionData,
 	statementTS hlc.Timestamp,
 	withDiff bool,
-) *familyEvaluator {
+	lookupTS changefeedbase.LookupJoinTimestamp,
+) (*familyEvaluator, error) {
+	_, lookups, err := extractLookupJoins(sc)
+	if err != nil {
+		return nil, err
+	}
+
 	e := familyEvaluator{
 		targetFamilyID: targetFamilyID,
 		execCfg:        execCfg,
@@ -117,12 +130,14 @@ func newFamilyEvaluator(
 		rowCh:       make(chan tree.Datums, 1),
 		statementTS: statementTS,
 		withDiff:    withDiff,
+		lookups:     lookups,
+		lookupTS:    lookupTS,
 	}
 
 	// Arrange to be notified when event does not match predicate.
 	predicateAsProjection(e.norm)
 
-	return &e
+	return &e, nil
 }
 
 // Close closes currently running execution.
@@ -140,18 +155,23 @@ func (e *Evaluator) Eval(
 	ctx context.Context, updatedRow cdcevent.Row, prevRow cdcevent.Row,
 ) (projection cdcevent.Row, evalErr error) {
 	defer func() {
-		if evalErr != nil {
-			// If we can't evaluate a row, we are bound to keep failing.
-			// So mark error permanent.
+		// If we can't evaluate a row, we are bound to keep failing, so mark
+		// the error permanent. Errors reading joined tables are the exception.
+		if evalErr != nil && !errors.Is(evalErr, &lookupError{}) {
 			evalErr = changefeedbase.WithTerminalError(evalErr)
 		}
 	}()
 
 	fe, ok := e.familyEval[updatedRow.FamilyID]
 	if !ok {
-		fe = newFamilyEvaluator(
+		var err error
+		fe, err = newFamilyEvaluator(
 			e.sc, updatedRow.FamilyID, e.execCfg, e.user, e.sessionData, e.statementTS, e.withDiff,
+			e.lookupTS,
 		)
+		if err != nil {
+			return cdcevent.Row{}, err
+		}
 		e.familyEval[updatedRow.FamilyID] = fe
 	}
 
@@ -174,8 +194,22 @@ func (e *familyEvaluator) eval(
 			"current family id (%d) differs from previous (%d)", updatedRow.FamilyID, prevRow.FamilyID)
 	}
 
+	// Schema changes to the joined tables require re-planning too.
+	var lookupTS hlc.Timestamp
+	lookupsChanged := false
+	if len(e.lookups) > 0 {
+		lookupTS = e.lookupTimestamp(updatedRow)
+		for _, lj := range e.lookups {
+			changed, err := lj.versionChanged(ctx, e.execCfg.LeaseManager, lookupTS)
+			if err != nil {
+				return cdcevent.Row{}, err
+			}
+			lookupsChanged = lookupsChanged || changed
+		}
+	}
+
 	havePrev := prevRow.IsInitialized()
-	if !(sameVersion(e.currDesc, updatedRow.EventDescriptor) &&
+	if lookupsChanged || !(sameVersion(e.currDesc, updatedRow.EventDescriptor) &&
 		(!havePrev || sameVersion(e.prevDesc, prevRow.EventDescriptor))) {
 		// Descriptor versions changed; re-initialize.
 		if err := e.closeErr(); err != nil {
@@ -185,7 +219,7 @@ func (e *familyEvaluator) eval(
 		e.errCh = make(chan error, 1)
 		e.currDesc, e.prevDesc = updatedRow.EventDescriptor, prevRow.EventDescriptor
 
-		if err := e.planAndRun(ctx); err != nil {
+		if err := e.planAndRun(ctx, lookupTS); err != nil {
 			return cdcevent.Row{}, err
 		}
 	}
@@ -207,6 +241,20 @@ func (e *familyEvaluator) eval(
 		}
 	}
 
+	// Look up the joined rows.
+	for _, lj := range e.lookups {
+		joined, err := lj.lookup(ctx, e.execCfg, updatedRow, lookupTS,
+			e.lookupTS == changefeedbase.OptLookupJoinTimestampLatest)
+		if err != nil {
+			return cdcevent.Row{}, err
+		}
+		if joined == tree.DNull && lj.isInner() {
+			// No matching row; filter the event.
+			return cdcevent.Row{}, nil
+		}
+		encDatums = append(encDatums, rowenc.EncDatum{Datum: joined})
+	}
+
 	// Push data into DistSQL.
 	if st := e.input.Push(encDatums, nil); st != execinfra.NeedMoreRows {
 		return cdcevent.Row{}, errors.Newf("familyEvaluator shutting down due to status %s", st)
@@ -253,8 +301,23 @@ func sameVersion(currentVersion, newVersion *cdcevent.EventDescriptor) bool {
 	return sameVersion && sameTypes
 }
 
-// planAndRun plans CDC expression and starts execution pipeline.
-func (e *familyEvaluator) planAndRun(ctx context.Context) (err error) {
+// lookupTimestamp returns the timestamp as of which the rows of joined tables
+// are read for the updated row.
+func (e *familyEvaluator) lookupTimestamp(updatedRow cdcevent.Row) hlc.Timestamp {
+	if e.lookupTS == changefeedbase.OptLookupJoinTimestampLatest {
+		return e.execCfg.Clock.Now()
+	}
+	// Rows emitted by the initial scan carry the timestamp at which they were
+	// last written; the history of joined tables prior to the changefeed start
+	// may be gone by now, so read joined rows as of the start instead.
+	ts := updatedRow.MvccTimestamp
+	ts.Forward(e.statementTS)
+	return ts
+}
+
+// planAndRun plans CDC expression and starts execution pipeline. Joined
+// tables, if any, are described as of the lookup timestamp.
+func (e *familyEvaluator) planAndRun(ctx context.Context, lookupTS hlc.Timestamp) (err error) {
 	if log.V(1) {
 		start := timeutil.Now()
 		defer func() {
@@ -265,7 +328,7 @@ func (e *familyEvaluator) planAndRun(ctx context.Context) (err error) {
 
 	var plan sql.CDCExpressionPlan
 	var prevCol catalog.Column
-	plan, prevCol, err = e.preparePlan(ctx)
+	plan, prevCol, err = e.preparePlan(ctx, lookupTS)
 	if err != nil {
 		return withErrorHint(err, e.currDesc.FamilyName, e.currDesc.HasOtherFamilies)
 	}
@@ -278,7 +341,7 @@ func (e *familyEvaluator) planAndRun(ctx context.Context) (err error) {
 // preparePlan creates a plan for CDC expression. If no error is returned, the
 // caller must call e.performCleanup().
 func (e *familyEvaluator) preparePlan(
-	ctx context.Context,
+	ctx context.Context, lookupTS hlc.Timestamp,
 ) (plan sql.CDCExpressionPlan, prevCol catalog.Column, err error) {
 	// Perform cleanup of the previous plan if there is one.
 	e.performCleanup()
@@ -303,7 +366,23 @@ func (e *familyEvaluator) preparePlan(
 				opts = append(opts, sql.WithExtraColumn(prevCol))
 			}
 
-			plan, err = sql.PlanCDCExpression(ctx, execCtx, e.norm.SelectStatementForFamily(), opts...)
+			if len(e.lookups) > 0 {
+				// Joined rows use the column IDs following the one of cdc_prev.
+				firstColID := e.currDesc.TableDescriptor().GetNextColumnID() + 1
+				lookupCols, err := e.lookups.init(ctx, execCtx, e.currDesc, lookupTS, firstColID)
+				if err != nil {
+					return err
+				}
+				for _, c := range lookupCols {
+					opts = append(opts, sql.WithExtraColumn(c))
+				}
+			}
+
+			stmt, err := e.norm.SelectStatementForFamily()
+			if err != nil {
+				return err
+			}
+			plan, err = sql.PlanCDCExpression(ctx, execCtx, stmt, opts...)
 			return err
 		})
 	if err != nil {
@@ -341,7 +420,7 @@ func (e *familyEvaluator) setupProjection(presentation colinfo.ResultColumns) {
 // inputSpecForEventDescriptor returns input specification for the
 // event descriptor.
 func inputSpecForEventDescriptor(
-	ed *cdcevent.EventDescriptor, prevCol catalog.Column,
+	ed *cdcevent.EventDescriptor, prevCol catalog.Column, lookups lookupJoins,
 ) ([]*types.T, catalog.TableColMap, error) {
 	numCols := len(ed.ResultColumns()) + len(colinfo.AllSystemColumnDescs)
 	inputTypes := make([]*types.T, 0, numCols)
@@ -366,6 +445,12 @@ func inputSpecForEventDescriptor(
 		inputCols.Set(prevCol.GetID(), inputCols.Len())
 		inputTypes = append(inputTypes, prevCol.GetType())
 	}
+
+	// Followed by the joined rows.
+	for _, lj := range lookups {
+		inputCols.Set(lj.col.GetID(), inputCols.Len())
+		inputTypes = append(inputTypes, lj.col.GetType())
+	}
 	return inputTypes, inputCols, nil
 }
 
@@ -375,7 +460,7 @@ func (e *familyEvaluator) executePlan(
 	ctx context.Context, plan sql.CDCExpressionPlan, prevCol catalog.Column,
 ) (inputReceiver execinfra.RowReceiver, err error) {
 	// Configure input.
-	inputTypes, inputCols, err := inputSpecForEventDescriptor(e.currDesc, prevCol)
+	inputTypes, inputCols, err := inputSpecForEventDescriptor(e.currDesc, prevCol, e.lookups)
 	if err != nil {
 		return nil, err
 	}
@@ -787,7 +787,7 @@ func newEvaluatorWithNormCheck(
 
 	const withDiff = true
 	return NewEvaluator(norm.SelectClause, execCfg, username.RootUserName(),
-		defaultDBSessionData, hlc.Timestamp{}, withDiff), nil
+		defaultDBSessionData, hlc.Timestamp{}, withDiff, changefeedbase.OptLookupJoinTimestampEvent), nil
 }
 
 var defaultDBSessionData = &sessiondata.SessionData{
@@ -0,0 +1,650 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cdceval
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// lookupJoin is a table joined into a CDC query. Joined tables are not watched
+// by the changefeed. Instead, when an event is evaluated, the joined row is
+// looked up by its primary key, and handed to the expression as a hidden tuple
+// column named after the alias of the joined table. References to the columns
+// of the joined table are rewritten to access that tuple (see
+// rewriteForLookupJoins).
+type lookupJoin struct {
+	alias    tree.Name
+	name     tree.TableName
+	joinType string
+	// keys maps each joined table column referenced by the ON clause to the
+	// target table column it must be equal to.
+	keys map[tree.Name]tree.Name
+
+	// State initialized when the expression is planned.
+	tableID   descpb.ID
+	desc      catalog.TableDescriptor
+	col       catalog.Column
+	keyCols   []string // Target table columns, in the primary key order.
+	keyColMap catalog.TableColMap
+	keyPrefix []byte
+	spec      fetchpb.IndexFetchSpec
+	cache     *cache.UnorderedCache
+	keyDatums tree.Datums
+	alloc     tree.DatumAlloc
+}
+
+// lookupJoins is the list of tables joined into a CDC query, in the order
+// they are joined.
+type lookupJoins []*lookupJoin
+
+// lookupError marks errors encountered when reading the rows or descriptors
+// of joined tables. Unlike the other errors evaluating an expression, those are
+// usually transient (e.g. an unavailable range), and should not fail the
+// changefeed.
+type lookupError struct{}
+
+func (e *lookupError) Error() string {
+	return "lookup join error"
+}
+
+// lookupCacheEntry is a joined row cached by lookupJoin.
+type lookupCacheEntry struct {
+	// row is the joined row tuple, or DNull if the row does not exist.
+	row tree.Datum
+	// validFrom and validTo bound the timestamps at which row is the current
+	// version of the joined row.
+	validFrom, validTo hlc.Timestamp
+	// expiration is the time after which a row read with
+	// lookup_join_timestamp=latest must be read again.
+	expiration time.Time
+}
+
+// qualifiedName is a column name, along with its (optional) table qualifier.
+type qualifiedName struct {
+	table, col tree.Name
+}
+
+// extractLookupJoins extracts the lookup joins from the FROM clause of the
+// select clause. Returns the table expression for the target table, and the
+// list of lookup joins, if any.
+func extractLookupJoins(sc *tree.SelectClause) (tree.TableExpr, lookupJoins, error) {
+	// This really shouldn't happen as it's enforced by sql.y.
+	if len(sc.From.Tables) != 1 {
+		return nil, nil, pgerror.Newf(pgcode.Syntax,
+			"expected 1 table, found %d", len(sc.From.Tables))
+	}
+
+	var joinExprs []*tree.JoinTableExpr
+	target := sc.From.Tables[0]
+	for {
+		j, ok := target.(*tree.JoinTableExpr)
+		if !ok {
+			break
+		}
+		joinExprs = append([]*tree.JoinTableExpr{j}, joinExprs...)
+		target = j.Left
+	}
+	if len(joinExprs) == 0 {
+		return target, nil, nil
+	}
+
+	targetAlias := tableExprAlias(target)
+	seen := map[tree.Name]struct{}{targetAlias: {}}
+	joins := make(lookupJoins, 0, len(joinExprs))
+	for _, j := range joinExprs {
+		lj, err := newLookupJoin(j, targetAlias)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, dup := seen[lj.alias]; dup {
+			return nil, nil, pgerror.Newf(pgcode.DuplicateAlias,
+				"table name %q specified more than once", lj.alias)
+		}
+		seen[lj.alias] = struct{}{}
+		joins = append(joins, lj)
+	}
+	return target, joins, nil
+}
+
+// tableExprAlias returns the name used to refer to the table expression.
+func tableExprAlias(e tree.TableExpr) tree.Name {
+	switch t := e.(type) {
+	case *tree.TableName:
+		return t.ObjectName
+	case *tree.AliasedTableExpr:
+		if t.As.Alias != "" {
+			return t.As.Alias
+		}
+		if tn, ok := t.Expr.(*tree.TableName); ok {
+			return tn.ObjectName
+		}
+	}
+	return ""
+}
+
+// asQualifiedName returns the column name referenced by the expression.
+func asQualifiedName(expr tree.Expr) (qualifiedName, bool) {
+	if p, ok := expr.(*tree.ParenExpr); ok {
+		return asQualifiedName(p.Expr)
+	}
+	n, ok := expr.(*tree.UnresolvedName)
+	if !ok || n.Star {
+		return qualifiedName{}, false
+	}
+	switch n.NumParts {
+	case 1:
+		return qualifiedName{col: tree.Name(n.Parts[0])}, true
+	case 2:
+		return qualifiedName{table: tree.Name(n.Parts[1]), col: tree.Name(n.Parts[0])}, true
+	default:
+		return qualifiedName{}, false
+	}
+}
+
+// newLookupJoin returns a lookupJoin for the right side of the join
+// expression.
+func newLookupJoin(j *tree.JoinTableExpr, targetAlias tree.Name) (*lookupJoin, error) {
+	switch j.JoinType {
+	case "", tree.AstInner, tree.AstLeft:
+	default:
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s JOIN is not supported by CDC; only INNER and LEFT joins are supported", j.JoinType)
+	}
+	if j.Hint != "" {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "join hints are not supported by CDC")
+	}
+
+	var tn *tree.TableName
+	var alias tree.Name
+	switch t := j.Right.(type) {
+	case *tree.TableName:
+		tn = t
+	case *tree.AliasedTableExpr:
+		if name, ok := t.Expr.(*tree.TableName); ok && t.IndexFlags == nil && len(t.As.Cols) == 0 {
+			tn, alias = name, t.As.Alias
+		}
+	}
+	if tn == nil {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"unsupported joined table expression %s", tree.AsString(j.Right))
+	}
+	if alias == "" {
+		alias = tn.ObjectName
+	}
+
+	cond, ok := j.Cond.(*tree.OnJoinCond)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"join with %s must specify an ON condition", alias)
+	}
+
+	lj := &lookupJoin{
+		alias:    alias,
+		name:     *tn,
+		joinType: j.JoinType,
+		keys:     make(map[tree.Name]tree.Name),
+	}
+
+	// Collect equalities of the form "alias.col = target_col".
+	unsupported := func() error {
+		return errors.WithHint(pgerror.Newf(pgcode.FeatureNotSupported,
+			"unsupported join condition %s", tree.AsString(cond.Expr)),
+			"join conditions must equate each primary key column of the joined table "+
+				"with a column of the target table, e.g. ON t.pk = target.col")
+	}
+	var collect func(expr tree.Expr) error
+	collect = func(expr tree.Expr) error {
+		switch e := expr.(type) {
+		case *tree.ParenExpr:
+			return collect(e.Expr)
+		case *tree.AndExpr:
+			if err := collect(e.Left); err != nil {
+				return err
+			}
+			return collect(e.Right)
+		case *tree.ComparisonExpr:
+			if e.Operator.Symbol != treecmp.EQ {
+				return unsupported()
+			}
+			joined, lok := asQualifiedName(e.Left)
+			other, rok := asQualifiedName(e.Right)
+			if !lok || !rok {
+				return unsupported()
+			}
+			if other.table == alias {
+				joined, other = other, joined
+			}
+			if joined.table != alias || (other.table != "" && other.table != targetAlias) {
+				return unsupported()
+			}
+			if _, dup := lj.keys[joined.col]; dup {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"column %s.%s is constrained more than once by the join condition", alias, joined.col)
+			}
+			lj.keys[joined.col] = other.col
+			return nil
+		default:
+			return unsupported()
+		}
+	}
+	if err := collect(cond.Expr); err != nil {
+		return nil, err
+	}
+	return lj, nil
+}
+
+// isInner returns true if events without a matching joined row are filtered.
+func (lj *lookupJoin) isInner() bool {
+	return lj.joinType != tree.AstLeft
+}
+
+// resolve resolves the name of the joined table using the planner, and
+// verifies the user may read it.
+func (lj *lookupJoin) resolve(
+	ctx context.Context, execCtx sql.JobExecContext,
+) (catalog.TableDescriptor, error) {
+	tn := lj.name
+	_, desc, err := resolver.ResolveExistingTableObject(ctx, execCtx.(resolver.SchemaResolver), &tn,
+		tree.ObjectLookupFlags{
+			Required:             true,
+			DesiredObjectKind:    tree.TableObject,
+			DesiredTableDescKind: tree.ResolveRequireTableDesc,
+		})
+	if err != nil {
+		return nil, err
+	}
+	type privilegeChecker interface {
+		CheckPrivilege(context.Context, privilege.Object, privilege.Kind) error
+	}
+	if err := execCtx.(privilegeChecker).CheckPrivilege(ctx, desc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
+
+// fetchLookupDesc returns the descriptor, with hydrated types, of the joined
+// table as of the specified timestamp.
+func fetchLookupDesc(
+	ctx context.Context, execCfg *sql.ExecutorConfig, id descpb.ID, ts hlc.Timestamp,
+) (desc catalog.TableDescriptor, _ error) {
+	if err := sql.DescsTxn(ctx, execCfg, func(ctx context.Context, txn isql.Txn, col *descs.Collection) (err error) {
+		if err := txn.KV().SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		desc, err = col.ByIDWithLeased(txn.KV()).WithoutNonPublic().Get().Table(ctx, id)
+		return err
+	}); err != nil {
+		return nil, lookupDescError(err)
+	}
+	return desc, nil
+}
+
+// lookupDescError marks errors retrieving the descriptor of a joined table.
+// Dropped tables are terminal; everything else is assumed to be transient.
+func lookupDescError(err error) error {
+	if errors.Is(err, catalog.ErrDescriptorDropped) || errors.Is(err, catalog.ErrDescriptorNotFound) {
+		return changefeedbase.WithTerminalError(
+			errors.Wrap(err, "table joined by changefeed expression no longer exists"))
+	}
+	return errors.Mark(err, &lookupError{})
+}
+
+// versionChanged returns true if the version of the joined table as of the
+// specified timestamp differs from the version used to plan the expression.
+func (lj *lookupJoin) versionChanged(
+	ctx context.Context, leaseMgr *lease.Manager, ts hlc.Timestamp,
+) (bool, error) {
+	if lj.desc == nil {
+		return true, nil
+	}
+	// The lease manager does its own caching, so this is cheap.
+	ld, err := leaseMgr.Acquire(ctx, ts, lj.tableID)
+	if err != nil {
+		return false, lookupDescError(err)
+	}
+	version := ld.Underlying().GetVersion()
+	// Immediately release the lease, since we only need it for the exact
+	// timestamp requested.
+	ld.Release(ctx)
+	return version != lj.desc.GetVersion(), nil
+}
+
+// init configures the lookup of rows of the joined table described by desc,
+// for events described by target. The joined row is passed to the expression
+// as a column with the specified ID.
+func (lj *lookupJoin) init(
+	execCfg *sql.ExecutorConfig,
+	desc catalog.TableDescriptor,
+	target *cdcevent.EventDescriptor,
+	colID descpb.ColumnID,
+) error {
+	// See cdcOptCatalog.ResolveDataSource.
+	if desc.IsRowLevelSecurityEnabled() {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"CDC queries cannot join tables with row-level security enabled")
+	}
+	if catalog.FindColumnByTreeName(target.TableDescriptor(), lj.alias) != nil {
+		return pgerror.Newf(pgcode.DuplicateColumn,
+			"joined table name %s conflicts with a column of table %s; use a different alias",
+			lj.alias, target.TableName)
+	}
+
+	// The joined row is passed as a tuple of the visible, non-virtual, columns.
+	var colIDs []descpb.ColumnID
+	var tupleTypes []*types.T
+	var tupleLabels []string
+	for _, c := range desc.VisibleColumns() {
+		if c.IsVirtual() {
+			continue
+		}
+		colIDs = append(colIDs, c.GetID())
+		tupleTypes = append(tupleTypes, c.GetType())
+		tupleLabels = append(tupleLabels, c.GetName())
+	}
+
+	// Match primary key columns of the joined table with the target columns.
+	pk := desc.GetPrimaryIndex()
+	lj.keyCols = lj.keyCols[:0]
+	lj.keyColMap = catalog.TableColMap{}
+	for i := 0; i < pk.NumKeyColumns(); i++ {
+		name := tree.Name(pk.GetKeyColumnName(i))
+		targetCol, ok := lj.keys[name]
+		if !ok {
+			return errors.WithHintf(pgerror.Newf(pgcode.FeatureNotSupported,
+				"join condition must constrain primary key column %s of %s", name, lj.alias),
+				"CDC queries only support lookup joins on the primary key of the joined table")
+		}
+		pkCol, err := catalog.MustFindColumnByID(desc, pk.GetKeyColumnID(i))
+		if err != nil {
+			return err
+		}
+		var targetType *types.T
+		for _, c := range target.ResultColumns() {
+			if c.Name == string(targetCol) {
+				targetType = c.Typ
+			}
+		}
+		if targetType == nil {
+			return pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist in table %s", targetCol, target.TableName)
+		}
+		if !targetType.Equivalent(pkCol.GetType()) {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"cannot join %s (type %s) with %s.%s (type %s)",
+				targetCol, targetType.SQLString(), lj.alias, name, pkCol.GetType().SQLString())
+		}
+		lj.keyCols = append(lj.keyCols, string(targetCol))
+		lj.keyColMap.Set(pkCol.GetID(), i)
+	}
+	if len(lj.keys) != len(lj.keyCols) {
+		return errors.WithHintf(pgerror.Newf(pgcode.FeatureNotSupported,
+			"join condition may only reference the primary key columns of %s", lj.alias),
+			"use the WHERE clause to filter on other columns of %s", lj.alias)
+	}
+
+	lj.keyPrefix = rowenc.MakeIndexKeyPrefix(execCfg.Codec, desc.GetID(), pk.GetID())
+	if err := rowenc.InitIndexFetchSpec(&lj.spec, execCfg.Codec, desc, pk, colIDs); err != nil {
+		return err
+	}
+	// Fetch the MVCC timestamp of the row too; it bounds the timestamps at
+	// which the cached row may be used.
+	lj.spec.FetchedColumns = append(lj.spec.FetchedColumns, fetchpb.IndexFetchSpec_Column{
+		ColumnID: colinfo.MVCCTimestampColumnID,
+		Name:     colinfo.MVCCTimestampColumnName,
+		Type:     colinfo.MVCCTimestampColumnType,
+	})
+
+	lj.tableID = desc.GetID()
+	lj.desc = desc
+	lj.col = &prevCol{
+		name: lj.alias,
+		t:    types.MakeLabeledTuple(tupleTypes, tupleLabels),
+		id:   colID,
+	}
+	lj.cache = newLookupCache(&execCfg.Settings.SV)
+	return nil
+}
+
+// newLookupCache returns a cache of joined rows.
+func newLookupCache(sv *settings.Values) *cache.UnorderedCache {
+	return cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _ interface{}, _ interface{}) bool {
+			return int64(size) > changefeedbase.LookupJoinCacheSize.Get(sv)
+		},
+	})
+}
+
+// lookup returns the row of the joined table matching the updated row, as of
+// the specified timestamp. Returns DNull if there is no such row.
+func (lj *lookupJoin) lookup(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	updated cdcevent.Row,
+	ts hlc.Timestamp,
+	latest bool,
+) (tree.Datum, error) {
+	it, err := updated.DatumsNamed(lj.keyCols)
+	if err != nil {
+		return nil, err
+	}
+	lj.keyDatums = lj.keyDatums[:0]
+	if err := it.Datum(func(d tree.Datum, _ cdcevent.ResultColumn) error {
+		lj.keyDatums = append(lj.keyDatums, d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Limit the capacity of the prefix so that encoding the key does not
+	// overwrite the prefix of the previously encoded key.
+	prefix := lj.keyPrefix[:len(lj.keyPrefix):len(lj.keyPrefix)]
+	key, containsNull, err := rowenc.EncodeIndexKey(
+		lj.desc, lj.desc.GetPrimaryIndex(), lj.keyColMap, lj.keyDatums, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if containsNull {
+		// NULLs never match.
+		return tree.DNull, nil
+	}
+
+	if v, ok := lj.cache.Get(string(key)); ok {
+		e := v.(*lookupCacheEntry)
+		if latest {
+			if timeutil.Now().Before(e.expiration) {
+				return e.row, nil
+			}
+		} else if e.validFrom.LessEq(ts) && ts.LessEq(e.validTo) {
+			return e.row, nil
+		}
+	}
+
+	e, err := lj.fetch(ctx, execCfg.DB, key, ts, latest)
+	if err != nil {
+		return nil, errors.Mark(
+			errors.Wrapf(err, "error looking up row of %s", lj.alias), &lookupError{})
+	}
+	if latest {
+		e.expiration = timeutil.Now().Add(changefeedbase.LookupJoinCacheTTL.Get(&execCfg.Settings.SV))
+	}
+	lj.cache.Add(string(key), e)
+	return e.row, nil
+}
+
+// fetch reads the joined row with the specified primary key. The row is read
+// as of the specified timestamp, unless the latest version is requested.
+func (lj *lookupJoin) fetch(
+	ctx context.Context, db *kv.DB, key roachpb.Key, ts hlc.Timestamp, latest bool,
+) (*lookupCacheEntry, error) {
+	e := &lookupCacheEntry{row: tree.DNull}
+	if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if !latest {
+			if err := txn.SetFixedTimestamp(ctx, ts); err != nil {
+				return err
+			}
+		}
+
+		var rf row.Fetcher
+		if err := rf.Init(ctx, row.FetcherInitArgs{
+			Txn:   txn,
+			Alloc: &lj.alloc,
+			Spec:  &lj.spec,
+		}); err != nil {
+			return err
+		}
+		defer rf.Close(ctx)
+
+		spans := roachpb.Spans{{Key: key, EndKey: key.PrefixEnd()}}
+		if err := rf.StartScan(ctx, spans, nil /* spanIDs */, rowinfra.NoBytesLimit, 1 /* rowLimitHint */); err != nil {
+			return err
+		}
+		datums, _, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return err
+		}
+
+		e.validTo = txn.ReadTimestamp()
+		e.validFrom = e.validTo
+		e.row = tree.DNull
+		if datums == nil {
+			// No such row. We can't tell since when, so the entry is only valid
+			// for the timestamp of the read.
+			return nil
+		}
+
+		// The last datum is the MVCC timestamp of the row.
+		numCols := len(datums) - 1
+		tuple := tree.NewDTupleWithLen(lj.col.GetType(), numCols)
+		copy(tuple.D, datums[:numCols])
+		e.row = tuple
+		if d, ok := datums[numCols].(*tree.DDecimal); ok {
+			e.validFrom, err = hlc.DecimalToHLC(&d.Decimal)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// init resolves the joined tables and configures their lookups. If lookupTS
+// is set, the joined tables are described as of that timestamp. Returns the
+// columns holding the joined rows; the first column is assigned the specified
+// ID, and the rest use subsequent IDs.
+func (joins lookupJoins) init(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	target *cdcevent.EventDescriptor,
+	lookupTS hlc.Timestamp,
+	firstColID descpb.ColumnID,
+) ([]catalog.Column, error) {
+	cols := make([]catalog.Column, 0, len(joins))
+	for i, lj := range joins {
+		desc, id := lj.desc, lj.tableID
+		if id == 0 {
+			// The table is resolved by name once; afterwards, it is tracked by
+			// ID so that it may be renamed.
+			resolved, err := lj.resolve(ctx, execCtx)
+			if err != nil {
+				return nil, err
+			}
+			desc, id = resolved, resolved.GetID()
+		}
+		if lookupTS.IsSet() {
+			var err error
+			if desc, err = fetchLookupDesc(ctx, execCtx.ExecCfg(), id, lookupTS); err != nil {
+				return nil, err
+			}
+		}
+		if err := lj.init(execCtx.ExecCfg(), desc, target, firstColID+descpb.ColumnID(i)); err != nil {
+			return nil, err
+		}
+		cols = append(cols, lj.col)
+	}
+	return cols, nil
+}
+
+// rewriteForLookupJoins returns a copy of the select clause which reads only
+// from the target table, and in which references to the columns of joined
+// tables access the tuple columns holding the joined rows instead.
+func rewriteForLookupJoins(
+	sc *tree.SelectClause, target tree.TableExpr, joins lookupJoins,
+) (*tree.SelectClause, error) {
+	aliases := make(map[string]struct{}, len(joins))
+	for _, lj := range joins {
+		aliases[string(lj.alias)] = struct{}{}
+	}
+
+	rewritten := *sc
+	rewritten.From.Tables = tree.TableExprs{target}
+	rewritten.Exprs = make(tree.SelectExprs, 0, len(sc.Exprs))
+	for _, e := range sc.Exprs {
+		rewritten.Exprs = append(rewritten.Exprs, e)
+		if _, isStar := e.Expr.(tree.UnqualifiedStar); isStar {
+			// Star expands to the columns of the target table, followed by the
+			// columns of each joined table.
+			for _, lj := range joins {
+				rewritten.Exprs = append(rewritten.Exprs, tree.SelectExpr{
+					Expr: &tree.TupleStar{Expr: tree.NewUnresolvedName(string(lj.alias))},
+				})
+			}
+		}
+	}
+
+	stmt, err := tree.SimpleStmtVisit(&rewritten, func(expr tree.Expr) (bool, tree.Expr, error) {
+		n, ok := expr.(*tree.UnresolvedName)
+		if !ok || n.NumParts != 2 {
+			return true, expr, nil
+		}
+		if _, joined := aliases[n.Parts[1]]; !joined {
+			return true, expr, nil
+		}
+		tuple := tree.NewUnresolvedName(n.Parts[1])
+		if n.Star {
+			return false, &tree.TupleStar{Expr: tuple}, nil
+		}
+		return false, &tree.ColumnAccessExpr{Expr: tuple, ColName: tree.Name(n.Parts[0])}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	rewrittenSC, ok := stmt.(*tree.SelectClause)
+	if !ok {
+		// We walked tree.SelectClause -- getting anything else would be surprising.
+		return nil, errors.AssertionFailedf("unexpected result type %T", stmt)
+	}
+	return rewrittenSC, nil
+}
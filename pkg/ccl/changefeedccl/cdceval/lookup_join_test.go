@@ -0,0 +1,208 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cdceval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteForLookupJoins(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		expr      string
+		rewritten string
+		err       string
+	}{
+		{
+			expr:      "SELECT * FROM foo",
+			rewritten: "SELECT * FROM foo",
+		},
+		{
+			expr:      "SELECT a, b.c FROM foo JOIN bar AS b ON b.id = a",
+			rewritten: "SELECT a, (b).c FROM foo",
+		},
+		{
+			expr:      "SELECT * FROM foo AS f LEFT JOIN bar ON bar.id = f.a WHERE bar.c > 1",
+			rewritten: "SELECT *, (bar).* FROM foo AS f WHERE (bar).c > 1",
+		},
+		{
+			expr: "SELECT f.*, b.*, z.x FROM foo AS f INNER JOIN bar AS b ON (b.k1 = a) AND (f.c = b.k2) " +
+				"LEFT JOIN baz AS z ON z.id = f.d",
+			rewritten: "SELECT f.*, (b).*, (z).x FROM foo AS f",
+		},
+		{
+			expr: "SELECT * FROM foo JOIN bar ON bar.id = foo.a JOIN bar ON bar.id = foo.b",
+			err:  `table name "bar" specified more than once`,
+		},
+		{
+			expr: "SELECT * FROM foo JOIN bar ON bar.id > foo.a",
+			err:  "unsupported join condition",
+		},
+		{
+			expr: "SELECT * FROM foo JOIN bar ON bar.id = foo.a OR bar.id = foo.b",
+			err:  "unsupported join condition",
+		},
+		{
+			expr: "SELECT * FROM foo JOIN bar ON bar.id = baz.a",
+			err:  "unsupported join condition",
+		},
+		{
+			expr: "SELECT * FROM foo JOIN bar ON bar.id = foo.a AND bar.id = foo.b",
+			err:  "column bar.id is constrained more than once",
+		},
+		{
+			expr: "SELECT * FROM foo JOIN [123 AS bar] ON bar.id = foo.a",
+			err:  "unsupported joined table expression",
+		},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			sc, err := ParseChangefeedExpression(tc.expr)
+			require.NoError(t, err)
+
+			target, joins, err := extractLookupJoins(sc)
+			if tc.err != "" {
+				require.Regexp(t, tc.err, err)
+				return
+			}
+			require.NoError(t, err)
+
+			rewritten := sc
+			if len(joins) > 0 {
+				rewritten, err = rewriteForLookupJoins(sc, target, joins)
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.rewritten, AsStringUnredacted(rewritten))
+			// Rewrite must not modify the original expression.
+			require.Equal(t, tc.expr, AsStringUnredacted(sc))
+		})
+	}
+}
+
+func TestLookupJoinEvaluator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	for _, l := range []serverutils.ApplicationLayerInterface{s, srv.SystemLayer()} {
+		kvserver.RangefeedEnabled.Override(ctx, &l.ClusterSettings().SV, true)
+	}
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.ExecMultiple(t,
+		`CREATE TABLE customers (id INT PRIMARY KEY, name STRING, tier STRING)`,
+		`CREATE TABLE orders (id INT PRIMARY KEY, customer_id INT, amount DECIMAL)`,
+		`INSERT INTO customers VALUES (1, 'alice', 'gold'), (2, 'bob', 'silver')`,
+	)
+
+	desc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "orders")
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+	target := changefeedbase.Target{
+		Type:    jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID: desc.GetID(),
+	}
+	targets := changefeedbase.Targets{}
+	targets.Add(target)
+
+	for _, tc := range []struct {
+		name      string
+		stmt      string
+		expectErr string
+		// Expected values for orders 1, 2 and 3, ordered by key; nil if the
+		// event is expected to be filtered.
+		expect []map[string]string
+	}{
+		{
+			name: "inner",
+			stmt: "SELECT o.id, c.name FROM orders AS o JOIN customers AS c ON c.id = o.customer_id",
+			expect: []map[string]string{
+				{"id": "1", "name": "alice"},
+				{"id": "2", "name": "bob"},
+				nil,
+			},
+		},
+		{
+			name: "left",
+			stmt: "SELECT id, customers.tier FROM orders LEFT JOIN customers ON customers.id = customer_id",
+			expect: []map[string]string{
+				{"id": "1", "tier": "gold"},
+				{"id": "2", "tier": "silver"},
+				{"id": "3", "tier": "NULL"},
+			},
+		},
+		{
+			name: "filter",
+			stmt: "SELECT id FROM orders JOIN customers AS c ON c.id = customer_id WHERE c.tier = 'gold'",
+			expect: []map[string]string{
+				{"id": "1"},
+				nil,
+				nil,
+			},
+		},
+		{
+			name:      "non-key join",
+			stmt:      "SELECT * FROM orders JOIN customers AS c ON c.name = customer_id",
+			expectErr: "join condition must constrain primary key column id of c",
+		},
+		{
+			name:      "unknown table",
+			stmt:      "SELECT * FROM orders JOIN nope ON nope.id = customer_id",
+			expectErr: `relation "nope" does not exist`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB.Exec(t, "DELETE FROM orders WHERE true")
+
+			e, err := newEvaluatorWithNormCheck(&execCfg, desc, s.Clock().Now(), target, tc.stmt)
+			if tc.expectErr != "" {
+				require.Regexp(t, tc.expectErr, err)
+				return
+			}
+			require.NoError(t, err)
+			defer e.Close()
+
+			decoder, err := cdcevent.NewEventDecoder(ctx, &execCfg, targets, false, false)
+			require.NoError(t, err)
+
+			popRow, cleanup := cdctest.MakeRangeFeedValueReader(t, s.ExecutorConfig(), desc)
+			defer cleanup()
+
+			sqlDB.Exec(t, `INSERT INTO orders VALUES (1, 1, 10), (2, 2, 20), (3, 42, 30)`)
+
+			for i, v := range readSortedRangeFeedValues(t, len(tc.expect), popRow) {
+				updatedRow := decodeRow(t, decoder, &v, cdcevent.CurrentRow)
+				prevRow := decodeRow(t, decoder, &v, cdcevent.PrevRow)
+				projection, err := e.Eval(ctx, updatedRow, prevRow)
+				require.NoError(t, err)
+				if tc.expect[i] == nil {
+					require.False(t, projection.IsInitialized(), "keys: %v", slurpKeys(t, updatedRow))
+					continue
+				}
+				require.Equal(t, tc.expect[i], slurpValues(t, projection))
+			}
+		})
+	}
+}
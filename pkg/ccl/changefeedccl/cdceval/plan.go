@@ -69,15 +69,18 @@ func normalizeExpression(
 	}
 
 	// Add cdc_prev column; we may or may not need it, but we'll check below.
-	prevCol, err := newPrevColumnForDesc(norm.desc)
+	prevCol, opts, err := extraColumnsForExpression(ctx, execCtx, norm)
 	if err != nil {
 		return nil, false, err
 	}
 
 	// Plan execution; this steps triggers optimizer, which
 	// performs various validation steps.
-	plan, err := sql.PlanCDCExpression(ctx, execCtx,
-		norm.SelectStatementForFamily(), sql.WithExtraColumn(prevCol))
+	stmt, err := norm.SelectStatementForFamily()
+	if err != nil {
+		return nil, false, err
+	}
+	plan, err := sql.PlanCDCExpression(ctx, execCtx, stmt, opts...)
 	if err != nil {
 		return nil, false, err
 	}
@@ -121,13 +124,16 @@ func SpansForExpression(
 
 			// Add cdc_prev column; we may or may not need it, add it just in case
 			// expression uses it.
-			prevCol, err := newPrevColumnForDesc(norm.desc)
+			_, opts, err := extraColumnsForExpression(ctx, execCtx, norm)
 			if err != nil {
 				return err
 			}
 
-			plan, err = sql.PlanCDCExpression(ctx, execCtx,
-				norm.SelectStatementForFamily(), sql.WithExtraColumn(prevCol))
+			stmt, err := norm.SelectStatementForFamily()
+			if err != nil {
+				return err
+			}
+			plan, err = sql.PlanCDCExpression(ctx, execCtx, stmt, opts...)
 			return err
 
 		}); err != nil {
@@ -145,6 +151,32 @@ func SpansForExpression(
 	return spans, nil
 }
 
+// extraColumnsForExpression returns the options to plan the expression with
+// the cdc_prev column, as well as the columns holding the rows of joined
+// tables, if any. The joined tables are resolved using the planner.
+func extraColumnsForExpression(
+	ctx context.Context, execCtx sql.JobExecContext, norm *NormalizedSelectClause,
+) (prevCol catalog.Column, opts []sql.CDCOption, _ error) {
+	prevCol, err := newPrevColumnForDesc(norm.desc)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts = append(opts, sql.WithExtraColumn(prevCol))
+
+	_, joins, err := extractLookupJoins(norm.SelectClause)
+	if err != nil {
+		return nil, nil, err
+	}
+	lookupCols, err := joins.init(ctx, execCtx, norm.desc, hlc.Timestamp{}, prevCol.GetID()+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range lookupCols {
+		opts = append(opts, sql.WithExtraColumn(c))
+	}
+	return prevCol, opts, nil
+}
+
 // withErrorHint wraps error with error hints.
 func withErrorHint(err error, targetFamily string, multiFamily bool) error {
 	// Wrap error with some additional information.
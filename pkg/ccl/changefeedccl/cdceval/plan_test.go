@@ -327,12 +327,15 @@ func normalizeAndPlan(
 			}
 
 			// Add cdc_prev column; we may or may not need it, but we'll check below.
-			prevCol, err := newPrevColumnForDesc(norm.desc)
+			_, opts, err := extraColumnsForExpression(ctx, execCtx, norm)
 			if err != nil {
 				return err
 			}
-			plan, err = sql.PlanCDCExpression(ctx, execCtx,
-				norm.SelectStatementForFamily(), sql.WithExtraColumn(prevCol))
+			stmt, err := norm.SelectStatementForFamily()
+			if err != nil {
+				return err
+			}
+			plan, err = sql.PlanCDCExpression(ctx, execCtx, stmt, opts...)
 			return err
 		}); err != nil {
 		return nil, false, sql.CDCExpressionPlan{}, err
@@ -32,9 +32,22 @@ type NormalizedSelectClause struct {
 }
 
 // SelectStatementForFamily returns tree.Select representing this object.
-func (n *NormalizedSelectClause) SelectStatementForFamily() *tree.Select {
+// Lookup joins are removed from the returned statement; see
+// rewriteForLookupJoins.
+func (n *NormalizedSelectClause) SelectStatementForFamily() (*tree.Select, error) {
+	sc := n.SelectClause
+	target, joins, err := extractLookupJoins(sc)
+	if err != nil {
+		return nil, err
+	}
+	if len(joins) > 0 {
+		if sc, err = rewriteForLookupJoins(sc, target, joins); err != nil {
+			return nil, err
+		}
+	}
+
 	if !n.desc.HasOtherFamilies {
-		return &tree.Select{Select: n.SelectClause}
+		return &tree.Select{Select: sc}, nil
 	}
 
 	// Configure index flags to restrict access to specific column family. To do
@@ -42,14 +55,14 @@ func (n *NormalizedSelectClause) SelectStatementForFamily() *tree.Select {
 	// make sure that when we do that, we do not mutate underlying select clause.
 	// This is done so that the same NormalizedSelectClause can be used to build
 	// expression evaluation for different table column families.
-	sc := *n.SelectClause
-	sc.From.Tables = append(tree.TableExprs(nil), n.SelectClause.From.Tables...)
-	sc.From.Tables[0] = &tree.AliasedTableExpr{
-		Expr:       n.SelectClause.From.Tables[0],
+	familySC := *sc
+	familySC.From.Tables = append(tree.TableExprs(nil), sc.From.Tables...)
+	familySC.From.Tables[0] = &tree.AliasedTableExpr{
+		Expr:       sc.From.Tables[0],
 		IndexFlags: &tree.IndexFlags{FamilyID: &n.desc.FamilyID},
 	}
 
-	return &tree.Select{Select: &sc}
+	return &tree.Select{Select: &familySC}, nil
 }
 
 // normalizeAndValidateSelectForTarget normalizes select expression and verifies
@@ -76,10 +89,9 @@ func normalizeAndValidateSelectForTarget(
 		}
 	}()
 
-	// This really shouldn't happen as it's enforced by sql.y.
-	if len(sc.From.Tables) != 1 {
-		return nil, pgerror.Newf(pgcode.Syntax,
-			"expected 1 table, found %d", len(sc.From.Tables))
+	_, joins, err := extractLookupJoins(sc)
+	if err != nil {
+		return nil, err
 	}
 
 	// Sanity check target and descriptor refer to the same table.
@@ -92,7 +104,21 @@ func normalizeAndValidateSelectForTarget(
 		desc:         desc,
 		splitColFams: splitColFams,
 	}
-	err := columnVisitor.FindColumnFamilies(sc)
+	for _, lj := range joins {
+		if columnVisitor.lookupAliases == nil {
+			columnVisitor.lookupAliases = make(map[string]struct{}, len(joins))
+		}
+		columnVisitor.lookupAliases[string(lj.alias)] = struct{}{}
+		// The target columns used to look up joined rows are referenced too.
+		for _, name := range lj.keys {
+			col, err := catalog.MustFindColumnByTreeName(desc, name)
+			if err != nil {
+				return nil, err
+			}
+			columnVisitor.columns = append(columnVisitor.columns, col.GetID())
+		}
+	}
+	err = columnVisitor.FindColumnFamilies(sc)
 	if err != nil {
 		return nil, err
 	}
@@ -343,6 +369,18 @@ type checkColumnsVisitor struct {
 	columns      []descpb.ColumnID
 	seenStar     bool
 	splitColFams bool
+	// lookupAliases are the names of the tables joined into the expression.
+	// Their columns are not columns of the target table.
+	lookupAliases map[string]struct{}
+}
+
+// isLookupTable returns true if the name refers to a joined table.
+func (c *checkColumnsVisitor) isLookupTable(tn *tree.UnresolvedObjectName) bool {
+	if tn == nil {
+		return false
+	}
+	_, joined := c.lookupAliases[tn.Object()]
+	return joined
 }
 
 func (c *checkColumnsVisitor) VisitCols(expr tree.Expr) (bool, tree.Expr) {
@@ -356,14 +394,25 @@ func (c *checkColumnsVisitor) VisitCols(expr tree.Expr) (bool, tree.Expr) {
 		return c.VisitCols(vn)
 
 	case *tree.ColumnItem:
+		if c.isLookupTable(e.TableName) {
+			return true, expr
+		}
 		col, err := catalog.MustFindColumnByTreeName(c.desc, e.ColumnName)
 		if err != nil {
+			if len(c.lookupAliases) > 0 {
+				err = errors.WithHint(err,
+					"columns of joined tables must be qualified with the table name or alias")
+			}
 			c.err = err
 			return false, expr
 		}
 
 		c.columns = append(c.columns, col.GetID())
-	case tree.UnqualifiedStar, *tree.AllColumnsSelector:
+	case *tree.AllColumnsSelector:
+		if !c.isLookupTable(e.TableName) {
+			c.seenStar = true
+		}
+	case tree.UnqualifiedStar:
 		c.seenStar = true
 	}
 	return true, expr
@@ -0,0 +1,224 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cdctest
+
+import (
+	"crypto/tls"
+	"net"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// MockGRPCSink is a changefeedpb.ChangefeedSink server used in tests.
+type MockGRPCSink struct {
+	server   *grpc.Server
+	listener net.Listener
+	mu       struct {
+		syncutil.Mutex
+		numBatches  int
+		inFlight    int
+		maxInFlight int
+		// reject is the number of upcoming batches to reject.
+		reject int
+		// paused is set while acknowledgements are held back.
+		paused bool
+		held   []heldAck
+		rows   []changefeedpb.SinkMessage
+		notify chan struct{}
+	}
+}
+
+var _ changefeedpb.ChangefeedSinkServer = (*MockGRPCSink)(nil)
+
+type heldAck struct {
+	stream *mockGRPCSinkStream
+	ack    changefeedpb.SinkAck
+}
+
+type mockGRPCSinkStream struct {
+	stream changefeedpb.ChangefeedSink_EmitBatchesServer
+	// sendMu serializes Send calls, since held acknowledgements are sent from
+	// ResumeAcks.
+	sendMu syncutil.Mutex
+}
+
+func (s *mockGRPCSinkStream) send(ack *changefeedpb.SinkAck) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.stream.Send(ack)
+}
+
+// StartMockGRPCSinkInsecure starts a mock grpc sink without TLS.
+func StartMockGRPCSinkInsecure() (*MockGRPCSink, error) {
+	return startMockGRPCSink()
+}
+
+// StartMockGRPCSink starts a mock grpc sink serving TLS with the specified
+// certificate. If requireClientCert is set, clients must present a
+// certificate.
+func StartMockGRPCSink(
+	certificate *tls.Certificate, requireClientCert bool,
+) (*MockGRPCSink, error) {
+	if certificate == nil {
+		return nil, errors.Errorf("Must pass a CA cert when creating a mock grpc sink.")
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{*certificate},
+	}
+	if requireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	}
+	return startMockGRPCSink(grpc.Creds(credentials.NewTLS(tlsConfig)))
+}
+
+func startMockGRPCSink(opts ...grpc.ServerOption) (*MockGRPCSink, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &MockGRPCSink{
+		server:   grpc.NewServer(opts...),
+		listener: listener,
+	}
+	changefeedpb.RegisterChangefeedSinkServer(s.server, s)
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	return s, nil
+}
+
+// Addr returns the address the mock grpc sink listens on.
+func (s *MockGRPCSink) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the mock grpc sink.
+func (s *MockGRPCSink) Close() {
+	s.server.Stop()
+}
+
+// RejectNext arranges for the next n batches to be acknowledged with an
+// error.
+func (s *MockGRPCSink) RejectNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.reject = n
+}
+
+// PauseAcks holds back acknowledgements until ResumeAcks is called.
+func (s *MockGRPCSink) PauseAcks() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.paused = true
+}
+
+// ResumeAcks sends the acknowledgements held back since PauseAcks.
+func (s *MockGRPCSink) ResumeAcks() error {
+	s.mu.Lock()
+	held := s.mu.held
+	s.mu.held = nil
+	s.mu.paused = false
+	s.mu.inFlight -= len(held)
+	s.mu.Unlock()
+
+	for i := range held {
+		if err := held[i].stream.send(&held[i].ack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NumBatches returns the number of batches received.
+func (s *MockGRPCSink) NumBatches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.numBatches
+}
+
+// InFlight returns the number of batches whose acknowledgements are held
+// back.
+func (s *MockGRPCSink) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.inFlight
+}
+
+// MaxInFlight returns the largest number of batches whose acknowledgements
+// were held back at the same time.
+func (s *MockGRPCSink) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.maxInFlight
+}
+
+// Pop deletes and returns the oldest message accepted by the mock grpc sink.
+func (s *MockGRPCSink) Pop() *changefeedpb.SinkMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		oldest := s.mu.rows[0]
+		s.mu.rows = s.mu.rows[1:]
+		return &oldest
+	}
+	return nil
+}
+
+// NotifyMessage arranges for channel to be closed when message arrives.
+func (s *MockGRPCSink) NotifyMessage() chan struct{} {
+	c := make(chan struct{})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		close(c)
+	} else {
+		s.mu.notify = c
+	}
+	return c
+}
+
+// EmitBatches implements the changefeedpb.ChangefeedSinkServer interface.
+func (s *MockGRPCSink) EmitBatches(stream changefeedpb.ChangefeedSink_EmitBatchesServer) error {
+	ms := &mockGRPCSinkStream{stream: stream}
+	for {
+		batch, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		ack := changefeedpb.SinkAck{ID: batch.ID}
+
+		s.mu.Lock()
+		s.mu.numBatches++
+		if s.mu.reject > 0 {
+			s.mu.reject--
+			ack.Error = "rejected by mock grpc sink"
+		} else {
+			s.mu.rows = append(s.mu.rows, batch.Messages...)
+			if s.mu.notify != nil {
+				close(s.mu.notify)
+				s.mu.notify = nil
+			}
+		}
+		if s.mu.paused {
+			s.mu.held = append(s.mu.held, heldAck{stream: ms, ack: ack})
+			s.mu.inFlight++
+			if s.mu.inFlight > s.mu.maxInFlight {
+				s.mu.maxInFlight = s.mu.inFlight
+			}
+			s.mu.Unlock()
+			continue
+		}
+		s.mu.Unlock()
+
+		if err := ms.send(&ack); err != nil {
+			return err
+		}
+	}
+}
@@ -75,6 +75,7 @@ func AllTargets(cd jobspb.ChangefeedDetails) (targets changefeedbase.Targets) {
 			})
 		}
 	}
+	targets.Scopes = cd.TargetScopes
 	return
 }
 
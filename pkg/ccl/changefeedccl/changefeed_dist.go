@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
@@ -43,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metamorphic"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
 	"github.com/cockroachdb/errors"
 )
 
@@ -130,6 +133,17 @@ func distChangefeedFlow(
 		}
 	}
 
+	// Changefeeds on databases or wildcard patterns restart whenever a table
+	// enters or leaves their scope; pick up the current set of tables as of
+	// the timestamp we are about to start from.
+	if len(details.TargetScopes) > 0 {
+		var err error
+		details, err = refreshTargetMembership(ctx, execCtx.ExecCfg(), jobID, details, localState, schemaTS)
+		if err != nil {
+			return err
+		}
+	}
+
 	if knobs, ok := execCtx.ExecCfg().DistSQLSrv.TestingKnobs.Changefeed.(*TestingKnobs); ok {
 		if knobs != nil && knobs.StartDistChangefeedInitialHighwater != nil {
 			knobs.StartDistChangefeedInitialHighwater(ctx, initialHighWater)
@@ -175,6 +189,35 @@ func fetchTableDescriptors(
 	return targetDescs, nil
 }
 
+// fetchMVCCExpirationLookback returns the longest ttl_expire_after of the
+// target tables that have ttl_mvcc_expiration set, as of ts, or zero if there
+// are none. Rows of such tables are deleted by reaching their MVCC expiration
+// rather than by the TTL job, so the changefeed has to ask its rangefeeds for
+// those deletions, with this lookback.
+func fetchMVCCExpirationLookback(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	targets changefeedbase.Targets,
+	ts hlc.Timestamp,
+) (time.Duration, error) {
+	targetDescs, err := fetchTableDescriptors(ctx, execCfg, targets, ts)
+	if err != nil {
+		return 0, err
+	}
+	var lookback time.Duration
+	for _, desc := range targetDescs {
+		if !desc.HasRowLevelTTL() || !desc.GetRowLevelTTL().MVCCExpiration {
+			continue
+		}
+		expireAfter, err := tabledesc.MVCCExpireAfter(desc.GetRowLevelTTL())
+		if err != nil {
+			return 0, err
+		}
+		lookback = max(lookback, expireAfter)
+	}
+	return lookback, nil
+}
+
 // changefeedResultTypes is the types returned by changefeed stream.
 var changefeedResultTypes = []*types.T{
 	types.Bytes,  // aggregator progress update
@@ -250,6 +293,15 @@ func startDistChangefeed(
 	if err != nil {
 		return err
 	}
+	if _, ok := details.Opts[changefeedbase.OptReplay]; ok {
+		// Track the spans of a replay range by range, so that its progress can
+		// be reported as the fraction of the ranges it replayed.
+		sender := execCfg.DB.NonTransactionalSender()
+		distSender := sender.(*kv.CrossRangeTxnWrapperSender).Wrapped().(*kvcoord.DistSender)
+		if trackedSpans, _, err = distSender.AllRangeSpans(ctx, trackedSpans); err != nil {
+			return err
+		}
+	}
 	if log.ExpensiveLogEnabled(ctx, 2) {
 		log.Infof(ctx, "tracked spans: %s", trackedSpans)
 	}
@@ -269,6 +321,14 @@ func startDistChangefeed(
 	if progress := localState.progress.GetChangefeed(); progress != nil && progress.SpanLevelCheckpoint != nil {
 		spanLevelCheckpoint = progress.SpanLevelCheckpoint
 	}
+	if _, ok := details.Opts[changefeedbase.OptExactlyOnce]; ok && jobID != 0 {
+		recovered, err := recoverSinkTransactions(ctx, execCtx, jobID, details, initialHighWater,
+			trackedSpans, checkpoint, spanLevelCheckpoint)
+		if err != nil {
+			return err
+		}
+		checkpoint, spanLevelCheckpoint = nil, recovered
+	}
 	p, planCtx, err := makePlan(execCtx, jobID, details, description, initialHighWater,
 		trackedSpans, checkpoint, spanLevelCheckpoint, localState.drainingNodes)(ctx, dsp)
 	if err != nil {
@@ -342,6 +402,76 @@ func startDistChangefeed(
 	return ctxgroup.GoAndWait(ctx, execPlan)
 }
 
+// recoverSinkTransactions recovers the transaction markers committed by the
+// aggregators of an exactly_once changefeed and returns the span level
+// checkpoint to start the changefeed from. Messages of rows at or below the
+// resolved spans of a marker have been committed to the sink, so the
+// changefeed resumes those spans from the marker rather than from the possibly
+// older job checkpoint.
+func recoverSinkTransactions(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	jobID jobspb.JobID,
+	details jobspb.ChangefeedDetails,
+	initialHighWater hlc.Timestamp,
+	trackedSpans []roachpb.Span,
+	//lint:ignore SA1019 deprecated usage
+	legacyCheckpoint *jobspb.ChangefeedProgress_Checkpoint,
+	spanLevelCheckpoint *jobspb.TimestampSpansMap,
+) (*jobspb.TimestampSpansMap, error) {
+	execCfg := execCtx.ExecCfg()
+	sli, err := execCfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics).getSLIMetrics(
+		details.Opts[changefeedbase.OptMetricsScope])
+	if err != nil {
+		return nil, err
+	}
+	var nilOracle timestampLowerBoundOracle
+	s, err := getAndDialSink(ctx, &execCfg.DistSQLSrv.ServerConfig, details, nilOracle,
+		execCtx.User(), jobID, sli)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := s.Close(); err != nil {
+			log.Warningf(ctx, "failed to close sink after recovering transactions: %v", err)
+		}
+	}()
+	txnSink, ok := s.(sinkWithTransactions)
+	if !ok {
+		return nil, errors.AssertionFailedf("sink %T does not support %s", s, changefeedbase.OptExactlyOnce)
+	}
+	markers, err := txnSink.recoverTransactions(ctx, transactionalIDPrefix(jobID))
+	if err != nil {
+		return nil, changefeedbase.MarkRetryableError(err)
+	}
+
+	if spanLevelCheckpoint == nil {
+		spanLevelCheckpoint = checkpoint.ConvertFromLegacyCheckpoint(
+			legacyCheckpoint, details.StatementTime, initialHighWater)
+	}
+	if len(markers) == 0 {
+		return spanLevelCheckpoint, nil
+	}
+
+	sf, err := span.MakeFrontierAt(initialHighWater, trackedSpans...)
+	if err != nil {
+		return nil, err
+	}
+	defer sf.Release()
+	if err := checkpoint.Restore(sf, spanLevelCheckpoint); err != nil {
+		return nil, err
+	}
+	for _, marker := range markers {
+		for _, rs := range marker.ResolvedSpans {
+			if _, err := sf.Forward(rs.Span, rs.Timestamp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	log.Infof(ctx, "recovered %d transaction markers of changefeed %d", len(markers), jobID)
+	return checkpoint.Make(initialHighWater, sf.Entries(), math.MaxInt64, nil /* metrics */), nil
+}
+
 // The bin packing choice gives preference to leaseholder replicas if possible.
 var replicaOracleChoice = replicaoracle.BinPackingChoice
 
@@ -96,6 +96,19 @@ type changeAggregator struct {
 	// span was forwarded to the frontier
 	recentKVCount uint64
 
+	// dlq, if set, receives the rows that cannot be encoded or that the sink
+	// rejects.
+	dlq *deadLetterQueue
+
+	// txnSink is set if the changefeed was created with the exactly_once
+	// option. The messages flushed to it are committed along with the resolved
+	// spans they cover before the spans are forwarded to the frontier.
+	txnSink sinkWithTransactions
+
+	// ddlEvents is set if the changefeed was created with the ddl_events
+	// option. It is the schema feed of the kvfeed.
+	ddlEvents schemafeed.DDLEventSource
+
 	// eventProducer produces the next event from the kv feed.
 	eventProducer kvevent.Reader
 	// eventConsumer consumes the event.
@@ -406,6 +419,33 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		ca.changedRowBuf = &b.buf
 	}
 
+	ca.dlq, err = makeDeadLetterQueue(ctx, ca.FlowCtx.Cfg, ca.spec, feed, timestampOracle,
+		recorder, ca.sliMetrics)
+	if err != nil {
+		err = changefeedbase.MarkRetryableError(err)
+		if log.V(2) {
+			log.Infof(ca.Ctx(), "change aggregator moving to draining due to error creating dead letter queue: %v", err)
+		}
+		ca.MoveToDraining(err)
+		ca.cancel()
+		return
+	}
+	if s, ok := ca.sink.(sinkWithRejectedMessageHandler); ok && ca.dlq != nil {
+		s.setRejectedMessageHandler(ca.dlq.writeRejected)
+	}
+
+	if opts.IsSet(changefeedbase.OptExactlyOnce) {
+		if err := ca.beginSinkTransactions(ctx); err != nil {
+			err = changefeedbase.MarkRetryableError(err)
+			if log.V(2) {
+				log.Infof(ca.Ctx(), "change aggregator moving to draining due to error beginning sink transactions: %v", err)
+			}
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return
+		}
+	}
+
 	// If the initial scan was disabled the highwater would've already been forwarded
 	needsInitialScan := ca.frontier.Frontier().IsEmpty()
 
@@ -436,7 +476,7 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	ca.sink = &errorWrapperSink{wrapped: ca.sink}
 	ca.eventConsumer, ca.sink, err = newEventConsumer(
 		ctx, ca.FlowCtx.Cfg, ca.spec, feed, ca.frontier, kvFeedHighWater,
-		ca.sink, ca.metrics, ca.sliMetrics, ca.knobs)
+		ca.sink, ca.metrics, ca.sliMetrics, ca.dlq, ca.ddlEvents, ca.knobs)
 	if err != nil {
 		if log.V(2) {
 			log.Infof(ca.Ctx(), "change aggregator moving to draining due to error creating event consumer: %v", err)
@@ -533,8 +573,12 @@ func (ca *changeAggregator) makeKVFeedCfg(
 	if schemaChange.Policy == changefeedbase.OptSchemaChangePolicyIgnore || initialScanOnly {
 		sf = schemafeed.DoNothingSchemaFeed
 	} else {
-		sf = schemafeed.New(ctx, cfg, schemaChange.EventClass, AllTargets(ca.spec.Feed),
+		ddlEvents := config.Opts.IsSet(changefeedbase.OptDDLEvents)
+		sf = schemafeed.New(ctx, cfg, schemaChange.EventClass, ddlEvents, AllTargets(ca.spec.Feed),
 			initialHighWater, &ca.metrics.SchemaFeedMetrics, config.Opts.GetCanHandle())
+		if ddlEvents {
+			ca.ddlEvents = sf.(schemafeed.DDLEventSource)
+		}
 	}
 
 	monitoringCfg, err := makeKVFeedMonitoringCfg(ctx, ca.sliMetrics, opts, ca.FlowCtx.Cfg.Settings)
@@ -542,6 +586,16 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		return kvfeed.Config{}, err
 	}
 
+	descTS := initialHighWater
+	if descTS.IsEmpty() {
+		descTS = cfg.DB.KV().Clock().Now()
+	}
+	expirationLookback, err := fetchMVCCExpirationLookback(
+		ctx, cfg.ExecutorConfig.(*sql.ExecutorConfig), AllTargets(ca.spec.Feed), descTS)
+	if err != nil {
+		return kvfeed.Config{}, err
+	}
+
 	return kvfeed.Config{
 		Writer:               buf,
 		Settings:             cfg.Settings,
@@ -557,11 +611,14 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		EndTime:              config.EndTime,
 		WithDiff:             filters.WithDiff,
 		WithFiltering:        filters.WithFiltering,
+		ExpirationLookback:   expirationLookback,
+		WithTxnIDs:           config.Opts.IsSet(changefeedbase.OptTransactionBoundaries),
 		WithFrontierQuantize: changefeedbase.Quantize.Get(&cfg.Settings.SV),
 		NeedsInitialScan:     needsInitialScan,
 		SchemaChangeEvents:   schemaChange.EventClass,
 		SchemaChangePolicy:   schemaChange.Policy,
 		SchemaFeed:           sf,
+		Replay:               config.Opts.IsSet(changefeedbase.OptReplay),
 		Knobs:                ca.knobs.FeedKnobs,
 		ScopedTimers:         ca.sliMetrics.Timers,
 		MonitoringCfg:        monitoringCfg,
@@ -695,6 +752,7 @@ func (ca *changeAggregator) close() {
 		// Best effort: context is often cancel by now, so we expect to see an error
 		_ = ca.sink.Close()
 	}
+	_ = ca.dlq.Close()
 
 	// The sliMetrics registry may hold on to some state for each aggregator
 	// (ex. last known resolved timestamp). De-register the aggregator so this
@@ -834,12 +892,23 @@ func (ca *changeAggregator) computeTrailingMetadata(meta *execinfrapb.Changefeed
 		return
 	}
 
+	resolvedSpans := slices.Collect(ca.resolvedSpans())
+	if ca.txnSink != nil {
+		if err := ca.txnSink.commitTransaction(ca.Ctx(), jobspb.ResolvedSpans{ResolvedSpans: resolvedSpans}); err != nil {
+			// As above, the checkpoint is dropped. The spans are not checkpointed,
+			// so the events of the uncommitted transaction are emitted again when
+			// the changefeed restarts.
+			log.Warningf(ca.Ctx(), "failed to commit transactional sink on shutdown: %v", err)
+			return
+		}
+	}
+
 	// Build out the list of frontier spans.
-	for sp, ts := range ca.frontier.Entries() {
+	for _, r := range resolvedSpans {
 		meta.Checkpoint = append(meta.Checkpoint,
 			execinfrapb.ChangefeedMeta_FrontierSpan{
-				Span:      sp,
-				Timestamp: ts,
+				Span:      r.Span,
+				Timestamp: r.Timestamp,
 			})
 	}
 }
@@ -927,6 +996,14 @@ func (ca *changeAggregator) noteResolvedSpan(resolved jobspb.ResolvedSpan) (retu
 		ca.sliMetrics.setResolved(ca.sliMetricsID, ca.frontier.Frontier())
 	}
 
+	// Emit the rows of the transactions that the frontier advanced past
+	// without waiting for the next flush.
+	if tc, ok := ca.eventConsumer.(txnGroupingConsumer); ok && advanced {
+		if err := tc.emitResolvedTxns(ctx); err != nil {
+			return err
+		}
+	}
+
 	forceFlush := resolved.BoundaryType != jobspb.ResolvedSpan_NONE
 
 	// NB: if we miss flush window, and the flush frequency is fairly high (minutes),
@@ -978,16 +1055,58 @@ func (ca *changeAggregator) flushFrontier(ctx context.Context) error {
 
 	// Iterate frontier spans and build a list of spans to emit.
 	batch := jobspb.ResolvedSpans{
-		ResolvedSpans: slices.Collect(ca.frontier.All()),
+		ResolvedSpans: slices.Collect(ca.resolvedSpans()),
+	}
+
+	// With exactly once delivery, the spans may only be forwarded once the
+	// messages flushed above are committed.
+	if ca.txnSink != nil {
+		if err := ca.txnSink.commitTransaction(ctx, batch); err != nil {
+			return err
+		}
 	}
 	return ca.emitResolved(batch)
 }
 
+// beginSinkTransactions switches the sink of a changefeed created with the
+// exactly_once option to emit messages in transactions. An initial marker
+// holding the spans the aggregator starts from is committed right away so
+// that a later run of the changefeed knows to fence this aggregator's
+// transactional ID even if it never commits any messages.
+func (ca *changeAggregator) beginSinkTransactions(ctx context.Context) error {
+	s, ok := ca.sink.(sinkWithTransactions)
+	if !ok {
+		return errors.AssertionFailedf("sink %T does not support %s", ca.sink, changefeedbase.OptExactlyOnce)
+	}
+	id := transactionalID(ca.spec.JobID, ca.FlowCtx.NodeID.SQLInstanceID())
+	if err := s.beginTransactions(ctx, id); err != nil {
+		return err
+	}
+	ca.txnSink = s
+	return s.commitTransaction(ctx, jobspb.ResolvedSpans{
+		ResolvedSpans: slices.Collect(ca.frontier.All()),
+	})
+}
+
+// resolvedSpans returns an iterator over the resolved spans of the frontier.
+// If the event consumer holds back the rows of transactions that are not yet
+// resolved, the spans are held below the oldest of those transactions so
+// that the changefeed does not checkpoint past rows it has not emitted.
+func (ca *changeAggregator) resolvedSpans() iter.Seq[jobspb.ResolvedSpan] {
+	if tc, ok := ca.eventConsumer.(txnGroupingConsumer); ok {
+		if oldest := tc.oldestUnresolvedTxn(); !oldest.IsEmpty() {
+			return ca.frontier.AllBefore(oldest)
+		}
+	}
+	return ca.frontier.All()
+}
+
 func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 	progressUpdate := jobspb.ResolvedSpans{
 		ResolvedSpans: batch.ResolvedSpans,
 		Stats: jobspb.ResolvedSpans_Stats{
 			RecentKvCount: ca.recentKVCount,
+			DLQRowCount:   ca.dlq.takeCount(),
 		},
 	}
 	if log.V(2) {
@@ -1055,11 +1174,18 @@ type changeFrontier struct {
 	freqEmitResolved time.Duration
 	// lastEmitResolved is the last time a resolved timestamp was emitted.
 	lastEmitResolved time.Time
+	// replayEndTime, if set, is the end time of a changefeed in replay mode.
+	// Its spans move from the cursor straight to the end time.
+	replayEndTime hlc.Timestamp
 
 	// latestResolvedKV indicates the last time the frontier received a resolved
 	// span from an aggregator that has recently emitted kv events.
 	latestResolvedKV time.Time
 
+	// pendingDLQRowCount is the number of rows the aggregators wrote to the dead
+	// letter queue that have not been added to the job progress yet.
+	pendingDLQRowCount uint64
+
 	// lastProtectedTimestampUpdate is the last time the protected timestamp
 	// record was updated to the frontier's highwater mark
 	lastProtectedTimestampUpdate time.Time
@@ -1305,6 +1431,9 @@ func newChangeFrontierProcessor(
 	} else {
 		cf.freqEmitResolved = emitNoResolved
 	}
+	if opts.IsSet(changefeedbase.OptReplay) {
+		cf.replayEndTime = cf.spec.Feed.EndTime
+	}
 
 	encodingOpts, err := opts.GetEncodingOptions()
 	if err != nil {
@@ -1701,6 +1830,7 @@ func (cf *changeFrontier) noteAggregatorProgress(ctx context.Context, d rowenc.E
 	}
 
 	cf.maybeMarkJobIdle(resolvedSpans.Stats.RecentKvCount)
+	cf.pendingDLQRowCount += resolvedSpans.Stats.DLQRowCount
 
 	for _, resolved := range resolvedSpans.ResolvedSpans {
 		// Inserting a timestamp less than the one the changefeed flow started at
@@ -1783,6 +1913,11 @@ func (cf *changeFrontier) maybeCheckpointJob(
 	// When in a Backfill, the frontier remains unchanged at the backfill boundary
 	// as we receive spans from the scan request at the Backfill Timestamp
 	inBackfill := !frontierChanged && cf.frontier.InBackfill(resolvedSpan)
+	// A replay is checkpointed like a backfill: the spans it completes are
+	// ahead of a highwater which does not move until it finishes.
+	if !cf.replayEndTime.IsEmpty() && !frontierChanged {
+		inBackfill = true
+	}
 
 	// If we're not in a backfill, highwater progress and an empty checkpoint will
 	// be saved. This is throttled however we always persist progress to a schema
@@ -1860,6 +1995,17 @@ func (cf *changeFrontier) checkpointJobProgress(
 			progress.Progress = &jobspb.Progress_HighWater{
 				HighWater: &frontier,
 			}
+			// The highwater of a replay stays at the cursor until every span has
+			// been replayed, so its progress is reported as the fraction of the
+			// tracked spans it replayed instead. A resumed replay without a
+			// highwater starts from the cursor, as it does with one.
+			var replayed, total int
+			if !cf.replayEndTime.IsEmpty() && frontier.Less(cf.replayEndTime) {
+				replayed, total = cf.replayedSpans()
+				progress.Progress = &jobspb.Progress_FractionCompleted{
+					FractionCompleted: float32(replayed) / float32(max(total, 1)),
+				}
+			}
 
 			changefeedProgress := progress.Details.(*jobspb.Progress_Changefeed).Changefeed
 			if cv.IsActive(cf.Ctx(), clusterversion.V25_2) {
@@ -1871,13 +2017,21 @@ func (cf *changeFrontier) checkpointJobProgress(
 				checkpointStr = legacyCheckpoint.String()
 			}
 
+			// The progress is read anew if the transaction is retried, so the
+			// pending count is only added once.
+			changefeedProgress.DLQRowCount += cf.pendingDLQRowCount
+
 			if ptsUpdated, err = cf.manageProtectedTimestamps(ctx, txn, changefeedProgress); err != nil {
 				log.Warningf(ctx, "error managing protected timestamp record: %v", err)
 				return err
 			}
 
 			if updateRunStatus {
-				progress.StatusMessage = fmt.Sprintf("running: resolved=%s", frontier)
+				if total > 0 {
+					progress.StatusMessage = fmt.Sprintf("running: replayed %d of %d spans", replayed, total)
+				} else {
+					progress.StatusMessage = fmt.Sprintf("running: resolved=%s", frontier)
+				}
 			}
 
 			ju.UpdateProgress(progress)
@@ -1889,6 +2043,7 @@ func (cf *changeFrontier) checkpointJobProgress(
 		if ptsUpdated {
 			cf.lastProtectedTimestampUpdate = timeutil.Now()
 		}
+		cf.pendingDLQRowCount = 0
 		if log.V(2) {
 			log.Infof(cf.Ctx(), "change frontier persisted highwater=%s and checkpoint=%s",
 				frontier, checkpointStr)
@@ -1901,6 +2056,25 @@ func (cf *changeFrontier) checkpointJobProgress(
 	return true, nil
 }
 
+// replayedSpans returns the number of tracked spans which a changefeed in
+// replay mode has replayed up to its end time, and the number of tracked
+// spans.
+func (cf *changeFrontier) replayedSpans() (replayed, total int) {
+	for _, sp := range cf.spec.TrackedSpans {
+		done := true
+		for _, ts := range cf.frontier.SpanEntries(sp) {
+			if ts.Less(cf.replayEndTime) {
+				done = false
+				break
+			}
+		}
+		if done {
+			replayed++
+		}
+	}
+	return replayed, len(cf.spec.TrackedSpans)
+}
+
 // manageProtectedTimestamps periodically advances the protected timestamp for
 // the changefeed's targets to the current highwater mark.  The record is
 // cleared during changefeedResumer.OnFailOrCancel
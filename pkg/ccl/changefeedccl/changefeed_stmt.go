@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
 	"sort"
 	"time"
 
@@ -526,17 +527,30 @@ func createChangefeedJobRecord(
 		}
 	}
 
-	tableOnlyTargetList := tree.BackupTargetList{}
+	targetList := tree.BackupTargetList{}
+	if changefeedStmt.Database != "" {
+		targetList.Databases = tree.NameList{changefeedStmt.Database}
+	}
 	for _, t := range changefeedStmt.Targets {
-		tableOnlyTargetList.Tables.TablePatterns = append(tableOnlyTargetList.Tables.TablePatterns, t.TableName)
+		targetList.Tables.TablePatterns = append(targetList.Tables.TablePatterns, t.TableName)
 	}
 
 	// This grabs table descriptors once to get their ids.
-	targetDescs, err := getTableDescriptors(ctx, p, &tableOnlyTargetList, statementTime, initialHighWater)
+	targetDescs, allDescs, err := getTableDescriptors(ctx, p, &targetList, statementTime, initialHighWater)
 	if err != nil {
 		return nil, err
 	}
 
+	// Databases and wildcard patterns become target scopes, whose membership
+	// is tracked as tables are created and dropped.
+	tableTargets, scopes, scopedTables, err := resolveTargetScopes(changefeedStmt, targetDescs, allDescs)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDLQTableScopes(opts, scopes, allDescs); err != nil {
+		return nil, err
+	}
+
 	for _, t := range targetDescs {
 		if tbl, ok := t.(catalog.TableDescriptor); ok && tbl.ExternalRowData() != nil {
 			if tbl.ExternalRowData().TenantID.IsSet() {
@@ -546,12 +560,17 @@ func createChangefeedJobRecord(
 		}
 	}
 
-	targets, tables, err := getTargetsAndTables(ctx, p, targetDescs, changefeedStmt.Targets,
+	targets, tables, err := getTargetsAndTables(ctx, p, targetDescs, tableTargets,
 		changefeedStmt.originalSpecs, opts.ShouldUseFullStatementTimeName(), sinkURI)
 
 	if err != nil {
 		return nil, err
 	}
+	targets, err = appendScopedTargets(ctx, p, targets, tables, scopedTables,
+		opts.ShouldUseFullStatementTimeName())
+	if err != nil {
+		return nil, err
+	}
 	tolerances := opts.GetCanHandle()
 	sd := p.SessionData().Clone()
 	// Add non-local session data state (localization, etc).
@@ -563,12 +582,20 @@ func createChangefeedJobRecord(
 		EndTime:              endTime,
 		TargetSpecifications: targets,
 		SessionData:          &sd.SessionData,
+		TargetScopes:         scopes,
 	}
 
 	specs := AllTargets(details)
 	hasSelectPrivOnAllTables := true
 	hasChangefeedPrivOnAllTables := true
+	checkDescs := make([]catalog.Descriptor, 0, len(targetDescs)+len(scopedTables))
 	for _, desc := range targetDescs {
+		checkDescs = append(checkDescs, desc)
+	}
+	for _, table := range scopedTables {
+		checkDescs = append(checkDescs, table)
+	}
+	for _, desc := range checkDescs {
 		if table, isTable := desc.(catalog.TableDescriptor); isTable {
 			if err := changefeedvalidators.ValidateTable(specs, table, tolerances); err != nil {
 				return nil, err
@@ -724,6 +751,16 @@ func createChangefeedJobRecord(
 	}
 
 	if details.SinkURI == `` {
+		if onErrorRow, err := opts.GetOnErrorRow(); err != nil {
+			return nil, err
+		} else if onErrorRow == changefeedbase.OptOnErrorRowDLQ {
+			return nil, errors.Errorf(`%s=%s is not supported with sinkless changefeeds`,
+				changefeedbase.OptOnErrorRow, changefeedbase.OptOnErrorRowDLQ)
+		}
+		if opts.IsSet(changefeedbase.OptExactlyOnce) {
+			return nil, errors.Errorf(`%s is not supported with sinkless changefeeds`,
+				changefeedbase.OptExactlyOnce)
+		}
 
 		if details.Select != `` {
 			if err := utilccl.CheckEnterpriseEnabled(
@@ -853,6 +890,11 @@ Few hours to a few days range are appropriate values for this option.`
 			for _, desc := range targetDescs {
 				sqlDescIDs = append(sqlDescIDs, desc.GetID())
 			}
+			for _, table := range scopedTables {
+				if !slices.Contains(sqlDescIDs, table.GetID()) {
+					sqlDescIDs = append(sqlDescIDs, table.GetID())
+				}
+			}
 			return sqlDescIDs
 		}(),
 		Details:       details,
@@ -889,25 +931,20 @@ func getTableDescriptors(
 	targets *tree.BackupTargetList,
 	statementTime hlc.Timestamp,
 	initialHighWater hlc.Timestamp,
-) (map[tree.TablePattern]catalog.Descriptor, error) {
-	// For now, disallow targeting a database or wildcard table selection.
-	// Getting it right as tables enter and leave the set over time is
-	// tricky.
-	if len(targets.Databases) > 0 {
-		return nil, errors.Errorf(`CHANGEFEED cannot target %s`,
-			tree.AsString(targets))
-	}
+) (map[tree.TablePattern]catalog.Descriptor, []catalog.Descriptor, error) {
 	for _, t := range targets.Tables.TablePatterns {
 		p, err := t.NormalizeTablePattern()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		if _, ok := p.(*tree.TableName); !ok {
-			return nil, errors.Errorf(`CHANGEFEED cannot target %s`, tree.AsString(t))
+		switch p.(type) {
+		case *tree.TableName, *tree.AllTablesSelector:
+		default:
+			return nil, nil, errors.Errorf(`CHANGEFEED cannot target %s`, tree.AsString(t))
 		}
 	}
 
-	_, _, _, targetDescs, err := backupresolver.ResolveTargetsToDescriptors(ctx, p, statementTime, targets)
+	allDescs, _, _, targetDescs, err := backupresolver.ResolveTargetsToDescriptors(ctx, p, statementTime, targets)
 	if err != nil {
 		var m *backupresolver.MissingTableErr
 		if errors.As(err, &m) {
@@ -921,7 +958,7 @@ func getTableDescriptors(
 				"do the targets exist at the specified cursor time %s?", initialHighWater)
 		}
 	}
-	return targetDescs, err
+	return targetDescs, allDescs, err
 }
 
 func getTargetsAndTables(
@@ -1041,6 +1078,17 @@ func validateSink(
 		return err
 	}
 
+	if opts.GetDLQSink() != `` {
+		dlqSink, err := getAndDialSink(ctx, &p.ExecCfg().DistSQLSrv.ServerConfig, dlqSinkDetails(details),
+			nilOracle, p.User(), jobID, sli)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", changefeedbase.OptDLQSink)
+		}
+		if err := dlqSink.Close(); err != nil {
+			return err
+		}
+	}
+
 	// envelope=enriched is only allowed for non-query feeds and certain sinks.
 	if details.Opts[changefeedbase.OptEnvelope] == string(changefeedbase.OptEnvelopeEnriched) {
 		if details.Select != `` {
@@ -1051,6 +1099,7 @@ func validateSink(
 			sinkTypePubsub:         {},
 			sinkTypeKafka:          {},
 			sinkTypeWebhook:        {},
+			sinkTypeGRPC:           {},
 			sinkTypeSinklessBuffer: {},
 			sinkTypeCloudstorage:   {},
 		}
@@ -159,6 +159,57 @@ func TestChangefeedBasics(t *testing.T) {
 	// cloudStorageTest is a regression test for #36994.
 }
 
+// TestChangefeedForDatabase verifies that a database changefeed follows the
+// tables of the database as they are created and dropped.
+func TestChangefeedForDatabase(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+		dbFeed := feed(t, f, `CREATE CHANGEFEED FOR DATABASE d`)
+		defer closeFeed(t, dbFeed)
+
+		assertPayloads(t, dbFeed, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+		})
+
+		// A table created after the changefeed started is picked up.
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1, 'new')`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'old')`)
+		assertPayloads(t, dbFeed, []string{
+			`bar: [1]->{"after": {"a": 1, "b": "new"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "old"}}`,
+		})
+
+		// Dropping a table does not fail the changefeed, which keeps emitting
+		// the rows of the remaining tables only.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'before drop')`)
+		assertPayloads(t, dbFeed, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "before drop"}}`,
+		})
+		sqlDB.Exec(t, `DROP TABLE foo`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (4, 'after drop')`)
+		assertPayloads(t, dbFeed, []string{
+			`bar: [4]->{"after": {"a": 4, "b": "after drop"}}`,
+		})
+
+		// A table created after the drop is picked up as well.
+		sqlDB.Exec(t, `CREATE TABLE baz (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO baz VALUES (5)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (6, 'last')`)
+		assertPayloads(t, dbFeed, []string{
+			`baz: [5]->{"after": {"a": 5}}`,
+			`bar: [6]->{"after": {"a": 6, "b": "last"}}`,
+		})
+	}
+
+	cdcTest(t, testFn)
+}
+
 func TestChangefeedBasicQuery(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -6665,6 +6716,15 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `CHANGEFEED targets TABLE foo FAMILY f_a and TABLE foo FAMILY f_a are duplicates`,
 		`EXPERIMENTAL CHANGEFEED FOR foo family f_a, foo FAMILY f_b, foo FAMILY f_a`,
 	)
+	sqlDB.ExpectErrWithTimeout(
+		t, `CHANGEFEED cannot target a column family of defaultdb\.\*`,
+		`EXPERIMENTAL CHANGEFEED FOR defaultdb.* FAMILY f_a`,
+	)
+	sqlDB.Exec(t, `CREATE DATABASE emptydb`)
+	sqlDB.ExpectErrWithTimeout(
+		t, `CHANGEFEED target emptydb does not contain any tables`,
+		`EXPERIMENTAL CHANGEFEED FOR DATABASE emptydb`,
+	)
 
 	// Backup has the same bad error message #28170.
 	sqlDB.ExpectErrWithTimeout(
@@ -7153,6 +7213,59 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo into $1 WITH headers_json_column_name='j'`,
 		`nodelocal://.`)
 
+	sqlDB.ExpectErrWithTimeout(
+		t, `unknown on_error_row: skip`,
+		`CREATE CHANGEFEED FOR foo into $1 WITH on_error_row='skip'`,
+		`kafka://nope`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `dlq_sink is only usable with on_error_row=dlq`,
+		`CREATE CHANGEFEED FOR foo into $1 WITH dlq_sink='kafka://nope'`,
+		`kafka://nope`)
+
+	// The dead letter queue tables would be watched by database changefeeds.
+	sqlDB.ExpectErrWithTimeout(
+		t, `on_error_row=dlq writes rows to tables in the crdb_changefeed schema, which cannot be watched`,
+		`CREATE CHANGEFEED FOR DATABASE defaultdb into $1 WITH on_error_row='dlq'`,
+		`kafka://nope`)
+	sqlDB.ExpectErrWithTimeout(
+		t, `on_error_row=dlq writes rows to tables in the crdb_changefeed schema, which cannot be watched`,
+		`CREATE CHANGEFEED FOR defaultdb.* into $1 WITH on_error_row='dlq'`,
+		`kafka://nope`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `on_error_row=dlq is not supported with sinkless changefeeds`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH on_error_row='dlq'`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `exactly_once is not supported with sinkless changefeeds`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH exactly_once, initial_scan='no', schema_change_policy='nobackfill'`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `this sink is incompatible with option exactly_once`,
+		`CREATE CHANGEFEED FOR foo into $1 WITH exactly_once, initial_scan='no', schema_change_policy='nobackfill'`,
+		`nodelocal://.`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `unordered is not usable with exactly_once`,
+		`CREATE CHANGEFEED FOR foo into $1 WITH exactly_once, initial_scan='no', schema_change_policy='nobackfill', unordered`,
+		`kafka://nope`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `resolved is not usable with exactly_once`,
+		`CREATE CHANGEFEED FOR foo into $1 WITH exactly_once, initial_scan='no', schema_change_policy='nobackfill', resolved`,
+		`kafka://nope`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `exactly_once requires initial_scan='no'`,
+		`CREATE CHANGEFEED FOR foo into $1 WITH exactly_once, schema_change_policy='nobackfill'`,
+		`kafka://nope`)
+
+	sqlDB.ExpectErrWithTimeout(
+		t, `exactly_once is not usable with schema_change_policy=backfill`,
+		`CREATE CHANGEFEED FOR foo into $1 WITH exactly_once, initial_scan='no'`,
+		`kafka://nope`)
+
 	sqlDB.ExpectErrWithTimeout(
 		t, `headers_json_column_name is only usable with format=json/avro`,
 		`CREATE CHANGEFEED FOR foo into $1 WITH headers_json_column_name='j', format=csv, initial_scan='only'`,
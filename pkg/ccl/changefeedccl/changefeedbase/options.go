@@ -44,6 +44,10 @@ type FormatType string
 // OnErrorType configures the job behavior when an error occurs.
 type OnErrorType string
 
+// OnErrorRowType configures the job behavior when a single row cannot be
+// encoded or is permanently rejected by the sink.
+type OnErrorRowType string
+
 // SchemaChangeEventClass defines a set of schema change event types which
 // trigger the action defined by the SchemaChangeEventPolicy.
 type SchemaChangeEventClass string
@@ -56,6 +60,14 @@ type SchemaChangePolicy string
 // include virtual columns in an event
 type VirtualColumnVisibility string
 
+// TransactionBoundaryMode defines how a changefeed that groups rows by
+// transaction marks the rows that committed together.
+type TransactionBoundaryMode string
+
+// LookupJoinTimestamp defines the timestamp at which a CDC query reads the
+// rows of the tables it joins against.
+type LookupJoinTimestamp string
+
 // InitialScanType configures whether the changefeed will perform an
 // initial scan, and the type of initial scan that it will perform
 type InitialScanType int
@@ -106,6 +118,8 @@ const (
 	OptWebhookAuthHeader                  = `webhook_auth_header`
 	OptWebhookClientTimeout               = `webhook_client_timeout`
 	OptOnError                            = `on_error`
+	OptOnErrorRow                         = `on_error_row`
+	OptDLQSink                            = `dlq_sink`
 	OptMetricsScope                       = `metrics_label`
 	OptUnordered                          = `unordered`
 	OptVirtualColumns                     = `virtual_columns`
@@ -117,10 +131,29 @@ const (
 	// TODO(#142273): look into whether we want to add headers to pub/sub, and other
 	// sinks as well (eg cloudstorage, webhook, ..). Currently it's kafka-only.
 	OptHeadersJSONColumnName = `headers_json_column_name`
+	OptTransactionBoundaries = `transaction_boundaries`
+	OptLookupJoinTimestamp   = `lookup_join_timestamp`
+	OptExactlyOnce           = `exactly_once`
+	OptDDLEvents             = `ddl_events`
+	OptReplay                = `replay`
 
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
 
+	// OptTransactionBoundariesMarkers emits a begin and a commit marker message
+	// around the rows of each transaction.
+	OptTransactionBoundariesMarkers TransactionBoundaryMode = `markers`
+	// OptTransactionBoundariesField includes the transaction ID and the write
+	// ordinal of the row in a `txn` field of each row.
+	OptTransactionBoundariesField TransactionBoundaryMode = `field`
+
+	// OptLookupJoinTimestampEvent reads joined rows as of the MVCC timestamp
+	// of the event being joined.
+	OptLookupJoinTimestampEvent LookupJoinTimestamp = `event`
+	// OptLookupJoinTimestampLatest reads the latest version of joined rows
+	// when the event is emitted.
+	OptLookupJoinTimestampLatest LookupJoinTimestamp = `latest`
+
 	// OptSchemaChangeEventClassColumnChange corresponds to all schema change
 	// events which add or remove any column.
 	OptSchemaChangeEventClassColumnChange SchemaChangeEventClass = `column_changes`
@@ -167,14 +200,21 @@ const (
 	OptEnvelopeBare          EnvelopeType = `bare`
 	OptEnvelopeEnriched      EnvelopeType = `enriched`
 
-	OptFormatJSON    FormatType = `json`
-	OptFormatAvro    FormatType = `avro`
-	OptFormatCSV     FormatType = `csv`
-	OptFormatParquet FormatType = `parquet`
+	OptFormatJSON     FormatType = `json`
+	OptFormatAvro     FormatType = `avro`
+	OptFormatCSV      FormatType = `csv`
+	OptFormatParquet  FormatType = `parquet`
+	OptFormatProtobuf FormatType = `protobuf`
 
 	OptOnErrorFail  OnErrorType = `fail`
 	OptOnErrorPause OnErrorType = `pause`
 
+	// OptOnErrorRowFail fails the changefeed when a row cannot be emitted.
+	OptOnErrorRowFail OnErrorRowType = `fail`
+	// OptOnErrorRowDLQ writes rows that cannot be emitted to a dead letter
+	// queue and continues.
+	OptOnErrorRowDLQ OnErrorRowType = `dlq`
+
 	DeprecatedOptFormatAvro                   = `experimental_avro`
 	DeprecatedSinkSchemeCloudStorageAzure     = `experimental-azure`
 	DeprecatedSinkSchemeCloudStorageGCS       = `experimental-gs`
@@ -191,6 +231,7 @@ const (
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptPubsubSinkConfig  = `pubsub_sink_config`
+	OptGRPCSinkConfig    = `grpc_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
 
 	// OptSink allows users to alter the Sink URI of an existing changefeed.
@@ -203,9 +244,12 @@ const (
 	SinkParamCACert                 = `ca_cert`
 	SinkParamClientCert             = `client_cert`
 	SinkParamClientKey              = `client_key`
+	SinkParamCommitInterval         = `commit_interval`
+	SinkParamControlTopic           = `control_topic`
 	SinkParamFileSize               = `file_size`
 	SinkParamPartitionFormat        = `partition_format`
 	SinkParamSchemaTopic            = `schema_topic`
+	SinkParamTableFormat            = `table_format`
 	SinkParamTLSEnabled             = `tls_enabled`
 	SinkParamSkipTLSVerify          = `insecure_tls_skip_verify`
 	SinkParamTopicPrefix            = `topic_prefix`
@@ -222,6 +266,9 @@ const (
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
 	SinkSchemePulsar                = `pulsar`
+	SinkSchemeGRPC                  = `grpc`
+	SinkSchemeGRPCS                 = `grpcs`
+	SinkParamMaxInFlight            = `max_in_flight`
 	SinkSchemeExternalConnection    = `external`
 	SinkParamSASLEnabled            = `sasl_enabled`
 	SinkParamSASLHandshake          = `sasl_handshake`
@@ -374,7 +421,7 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptCustomKeyColumn:                    stringOption,
 	OptEndTime:                            timestampOption,
 	OptEnvelope:                           enum("row", "key_only", "wrapped", "deprecated_row", "bare", "enriched"),
-	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet"),
+	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet", "protobuf"),
 	OptFullTableName:                      flagOption,
 	OptKeyInValue:                         flagOption,
 	OptTopicInValue:                       flagOption,
@@ -394,10 +441,13 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptExpirePTSAfter:                     durationOption.thatCanBeZero(),
 	OptKafkaSinkConfig:                    jsonOption,
 	OptPubsubSinkConfig:                   jsonOption,
+	OptGRPCSinkConfig:                     jsonOption,
 	OptWebhookSinkConfig:                  jsonOption,
 	OptWebhookAuthHeader:                  stringOption,
 	OptWebhookClientTimeout:               durationOption,
 	OptOnError:                            enum("pause", "fail"),
+	OptOnErrorRow:                         enum("fail", "dlq"),
+	OptDLQSink:                            stringOption,
 	OptMetricsScope:                       stringOption,
 	OptUnordered:                          flagOption,
 	OptVirtualColumns:                     enum("omitted", "null"),
@@ -408,6 +458,11 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptEncodeJSONValueNullAsObject:        flagOption,
 	OptEnrichedProperties:                 csv(string(EnrichedPropertySource), string(EnrichedPropertySchema)),
 	OptHeadersJSONColumnName:              stringOption,
+	OptTransactionBoundaries:              enum("markers", "field"),
+	OptLookupJoinTimestamp:                enum("event", "latest"),
+	OptExactlyOnce:                        flagOption,
+	OptDDLEvents:                          flagOption,
+	OptReplay:                             flagOption,
 }
 
 // CommonOptions is options common to all sinks
@@ -422,13 +477,16 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
 	OptExecutionLocality, OptLaggingRangesThreshold, OptLaggingRangesPollingInterval,
 	OptIgnoreDisableChangefeedReplication, OptEncodeJSONValueNullAsObject, OptEnrichedProperties,
+	OptTransactionBoundaries, OptLookupJoinTimestamp, OptOnErrorRow, OptDLQSink, OptDDLEvents,
+	OptReplay,
 )
 
 // SQLValidOptions is options exclusive to SQL sink
 var SQLValidOptions map[string]struct{} = nil
 
 // KafkaValidOptions is options exclusive to Kafka sink
-var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig, OptHeadersJSONColumnName)
+var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig, OptHeadersJSONColumnName,
+	OptExactlyOnce)
 
 // CloudStorageValidOptions is options exclusive to cloud storage sink
 var CloudStorageValidOptions = makeStringSet(OptCompression)
@@ -439,6 +497,9 @@ var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTi
 // PubsubValidOptions is options exclusive to pubsub sink
 var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
 
+// GRPCValidOptions is options exclusive to grpc sink
+var GRPCValidOptions = makeStringSet(OptGRPCSinkConfig)
+
 // ExternalConnectionValidOptions is options exclusive to the external
 // connection sink.
 //
@@ -449,7 +510,8 @@ var ExternalConnectionValidOptions = unionStringSets(SQLValidOptions, KafkaValid
 
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents,
-	OptSchemaChangePolicy, OptOnError, OptInitialScan)
+	OptSchemaChangePolicy, OptOnError, OptInitialScan, OptTransactionBoundaries, OptLookupJoinTimestamp,
+	OptOnErrorRow)
 
 // RetiredOptions are the options which are no longer active.
 var RetiredOptions = makeStringSet(DeprecatedOptProtectDataFromGCOnPause)
@@ -479,6 +541,7 @@ var RedactedOptions = map[string]redactionFunc{
 	OptWebhookAuthHeader:       redactSimple,
 	SinkParamClientKey:         redactSimple,
 	OptConfluentSchemaRegistry: RedactUserFromURI,
+	OptDLQSink:                 redactSimple,
 }
 
 // NoLongerExperimental aliases options prefixed with experimental that no longer need to be
@@ -498,7 +561,7 @@ type OptionsSet map[string]struct{}
 // InitialScanOnlyUnsupportedOptions is options that are not supported with the
 // initial scan only option
 var InitialScanOnlyUnsupportedOptions OptionsSet = makeStringSet(OptEndTime, OptResolvedTimestamps, OptDiff,
-	OptMVCCTimestamps, OptUpdatedTimestamps)
+	OptMVCCTimestamps, OptUpdatedTimestamps, OptDDLEvents, OptReplay)
 
 // ParquetFormatUnsupportedOptions is options that are not supported with the
 // parquet format.
@@ -511,7 +574,7 @@ var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
 // allowed to alter either of these options. We need to support the alteration
 // of these fields.
 var AlterChangefeedUnsupportedOptions OptionsSet = makeStringSet(OptCursor, OptInitialScan,
-	OptNoInitialScan, OptInitialScanOnly, OptEndTime)
+	OptNoInitialScan, OptInitialScanOnly, OptEndTime, OptExactlyOnce, OptReplay)
 
 // AlterChangefeedOptionExpectValues is used to parse alter changefeed options
 // using PlanHookState.TypeAsStringOpts().
@@ -562,10 +625,17 @@ func makeDirectedInvertedIndex(pairs []dependentOption) map[string][]dependentOp
 
 var incompatibleOptionsMap = makeInvertedIndex([]incompatibleOptions{
 	{opt1: OptUnordered, opt2: OptResolvedTimestamps, reason: `resolved timestamps cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptUnordered, opt2: OptTransactionBoundaries, reason: `transactions are grouped using the resolved timestamps, which cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptUnordered, opt2: OptExactlyOnce, reason: `messages are committed using the resolved timestamps, which cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptResolvedTimestamps, opt2: OptExactlyOnce, reason: `resolved timestamp messages are emitted outside of the kafka transactions and cannot be delivered exactly once`},
+	{opt1: OptUnordered, opt2: OptDDLEvents, reason: `schema change events are ordered with rows using the resolved timestamps, which cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptReplay, opt2: OptDiff, reason: `the previous value of the first revision of a key in the replayed window is not exported`},
 })
 
 var dependentOptionsMap = makeDirectedInvertedIndex([]dependentOption{
 	{opt1: OptCustomKeyColumn, opt2: OptUnordered, reason: `using a value other than the primary key as the message key means end-to-end ordering cannot be preserved`},
+	{opt1: OptReplay, opt2: OptCursor, reason: `the replayed window starts at the cursor`},
+	{opt1: OptReplay, opt2: OptEndTime, reason: `the replayed window ends at the end time`},
 })
 
 // MakeStatementOptions wraps and canonicalizes the options we get
@@ -853,6 +923,8 @@ type EncodingOptions struct {
 	CustomKeyColumn             string
 	EnrichedProperties          map[EnrichedProperty]struct{}
 	HeadersJSONColName          string
+	TransactionBoundaries       TransactionBoundaryMode
+	DDLEvents                   bool
 }
 
 // GetEncodingOptions populates and validates an EncodingOptions.
@@ -895,6 +967,7 @@ func (s StatementOptions) GetEncodingOptions() (EncodingOptions, error) {
 	_, o.MVCCTimestamps = s.m[OptMVCCTimestamps]
 	_, o.Diff = s.m[OptDiff]
 	_, o.EncodeJSONValueNullAsObject = s.m[OptEncodeJSONValueNullAsObject]
+	_, o.DDLEvents = s.m[OptDDLEvents]
 
 	o.SchemaRegistryURI = s.m[OptConfluentSchemaRegistry]
 	o.AvroSchemaPrefix = s.m[OptAvroSchemaPrefix]
@@ -913,6 +986,12 @@ func (s StatementOptions) GetEncodingOptions() (EncodingOptions, error) {
 		}
 	}
 
+	txnBoundaries, err := s.getEnumValue(OptTransactionBoundaries)
+	if err != nil {
+		return o, err
+	}
+	o.TransactionBoundaries = TransactionBoundaryMode(txnBoundaries)
+
 	s.cache.EncodingOptions = &o
 
 	return o, o.Validate()
@@ -920,9 +999,9 @@ func (s StatementOptions) GetEncodingOptions() (EncodingOptions, error) {
 
 // Validate checks for incompatible encoding options.
 func (e EncodingOptions) Validate() error {
-	if e.Envelope == OptEnvelopeRow && e.Format == OptFormatAvro {
+	if e.Envelope == OptEnvelopeRow && (e.Format == OptFormatAvro || e.Format == OptFormatProtobuf) {
 		return errors.Errorf(`%s=%s is not supported with %s=%s`,
-			OptEnvelope, OptEnvelopeRow, OptFormat, OptFormatAvro,
+			OptEnvelope, OptEnvelopeRow, OptFormat, e.Format,
 		)
 	}
 	if e.Format != OptFormatJSON && e.EncodeJSONValueNullAsObject {
@@ -930,8 +1009,9 @@ func (e EncodingOptions) Validate() error {
 	}
 
 	if e.Envelope == OptEnvelopeEnriched {
-		if e.Format != OptFormatJSON && e.Format != OptFormatAvro {
-			return errors.Errorf(`%s=%s is only usable with %s=%s/%s`, OptEnvelope, OptEnvelopeEnriched, OptFormat, OptFormatJSON, OptFormatAvro)
+		if e.Format != OptFormatJSON && e.Format != OptFormatAvro && e.Format != OptFormatProtobuf {
+			return errors.Errorf(`%s=%s is only usable with %s=%s/%s/%s`, OptEnvelope, OptEnvelopeEnriched,
+				OptFormat, OptFormatJSON, OptFormatAvro, OptFormatProtobuf)
 		}
 	} else {
 		if len(e.EnrichedProperties) > 0 {
@@ -943,6 +1023,28 @@ func (e EncodingOptions) Validate() error {
 		return errors.Errorf(`%s is only usable with %s=%s/%s`, OptHeadersJSONColumnName, OptFormat, OptFormatJSON, OptFormatAvro)
 	}
 
+	if e.TransactionBoundaries != `` {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptTransactionBoundaries, OptFormat, OptFormatJSON)
+		}
+		if e.TransactionBoundaries == OptTransactionBoundariesField &&
+			e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeEnriched {
+			return errors.Errorf(`%s=%s is only usable with %s=%s or %s=%s`,
+				OptTransactionBoundaries, OptTransactionBoundariesField,
+				OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeEnriched)
+		}
+	}
+
+	if e.DDLEvents {
+		if e.Format != OptFormatJSON && e.Format != OptFormatAvro {
+			return errors.Errorf(`%s is only usable with %s=%s/%s`, OptDDLEvents, OptFormat, OptFormatJSON, OptFormatAvro)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeEnriched {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptDDLEvents, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeEnriched)
+		}
+	}
+
 	// TODO(#140110): refactor this logic.
 	if (e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeEnriched) && e.Format != OptFormatJSON && e.Format != OptFormatParquet {
 		requiresWrap := []struct {
@@ -1064,6 +1166,12 @@ func (s StatementOptions) GetPubsubConfigJSON() SinkSpecificJSONConfig {
 	return s.getJSONValue(OptPubsubSinkConfig)
 }
 
+// GetGRPCConfigJSON returns arbitrary json to be interpreted
+// by the grpc sink.
+func (s StatementOptions) GetGRPCConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptGRPCSinkConfig)
+}
+
 // GetResolvedTimestampInterval gets the best-effort interval at which resolved timestamps
 // should be emitted. Nil or 0 means emit as often as possible. False means do not emit at all.
 // Returns an error for negative or invalid duration value.
@@ -1119,6 +1227,38 @@ func (s StatementOptions) KeyOnly() bool {
 	return s.m[OptEnvelope] == string(OptEnvelopeKeyOnly)
 }
 
+// GetLookupJoinTimestamp returns the timestamp at which CDC queries read the
+// rows of joined tables; defaults to the timestamp of the event.
+func (s StatementOptions) GetLookupJoinTimestamp() (LookupJoinTimestamp, error) {
+	ts, err := s.getEnumValue(OptLookupJoinTimestamp)
+	if err != nil {
+		return ``, err
+	}
+	if ts == `` {
+		return OptLookupJoinTimestampEvent, nil
+	}
+	return LookupJoinTimestamp(ts), nil
+}
+
+// GetOnErrorRow returns the desired behavior when a row cannot be encoded or
+// is permanently rejected by the sink; defaults to failing the changefeed.
+func (s StatementOptions) GetOnErrorRow() (OnErrorRowType, error) {
+	v, err := s.getEnumValue(OptOnErrorRow)
+	if err != nil {
+		return ``, err
+	}
+	if v == `` {
+		return OptOnErrorRowFail, nil
+	}
+	return OnErrorRowType(v), nil
+}
+
+// GetDLQSink returns the URI of the sink that rows are written to when
+// on_error_row='dlq'. If it is empty, rows are written to a table instead.
+func (s StatementOptions) GetDLQSink() string {
+	return s.m[OptDLQSink]
+}
+
 // GetMinCheckpointFrequency returns the minimum frequency with which checkpoints should be
 // recorded. Returns nil if not set, and an error if invalid.
 func (s StatementOptions) GetMinCheckpointFrequency() (*time.Duration, error) {
@@ -1270,6 +1410,36 @@ func (s StatementOptions) ValidateForCreateChangefeed(isPredicateChangefeed bool
 			return err
 		}
 	}
+	if !isPredicateChangefeed && s.IsSet(OptLookupJoinTimestamp) {
+		return errors.Newf(`%s is only usable with CDC queries`, OptLookupJoinTimestamp)
+	}
+	if s.IsSet(OptDDLEvents) && s.m[OptSchemaChangePolicy] == string(OptSchemaChangePolicyIgnore) {
+		return errors.Newf(`%s is not usable with %s=%s`,
+			OptDDLEvents, OptSchemaChangePolicy, OptSchemaChangePolicyIgnore)
+	}
+	if s.IsSet(OptReplay) && s.HasStartCursor() && scanType == InitialScan {
+		return errors.Newf(`%s is not usable with %s='yes'`, OptReplay, OptInitialScan)
+	}
+	if s.IsSet(OptExactlyOnce) {
+		// The rows emitted by a scan are not resolved until the scan completes,
+		// so a scan that is interrupted is restarted from its beginning and
+		// emits its rows again.
+		if scanType != NoInitialScan {
+			return errors.Newf(`%s requires %s='no' because the rows of an interrupted initial scan may be emitted again`,
+				OptExactlyOnce, OptInitialScan)
+		}
+		if p := s.m[OptSchemaChangePolicy]; p == `` || p == string(OptSchemaChangePolicyBackfill) {
+			return errors.Newf(`%s is not usable with %s=%s because the rows of an interrupted backfill may be emitted again`,
+				OptExactlyOnce, OptSchemaChangePolicy, OptSchemaChangePolicyBackfill)
+		}
+	}
+	if s.IsSet(OptDLQSink) && s.m[OptOnErrorRow] != string(OptOnErrorRowDLQ) {
+		return errors.Newf(`%s is only usable with %s=%s`, OptDLQSink, OptOnErrorRow, OptOnErrorRowDLQ)
+	}
+	if s.m[OptOnErrorRow] == string(OptOnErrorRowDLQ) && s.m[OptFormat] == string(OptFormatParquet) {
+		return errors.Newf(`%s=%s is not supported with %s=%s`,
+			OptOnErrorRow, OptOnErrorRowDLQ, OptFormat, OptFormatParquet)
+	}
 	for o := range s.m {
 		for _, pair := range incompatibleOptionsMap[o] {
 			if s.IsSet(pair.opt1) && s.IsSet(pair.opt2) {
@@ -37,6 +37,33 @@ func TestOptionsValidations(t *testing.T) {
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"key_column": "b"}, false, "requires the unordered option"},
+		{map[string]string{"transaction_boundaries": "begin"}, false, "unknown transaction_boundaries"},
+		{map[string]string{"transaction_boundaries": "markers", "unordered": ""}, false, "is not usable with"},
+		{map[string]string{"lookup_join_timestamp": "latest"}, false, "only usable with CDC queries"},
+		{map[string]string{"lookup_join_timestamp": "latest"}, true, ""},
+		{map[string]string{"lookup_join_timestamp": "now"}, true, "unknown lookup_join_timestamp"},
+		{map[string]string{"on_error_row": "skip"}, false, "unknown on_error_row"},
+		{map[string]string{"on_error_row": "dlq"}, false, ""},
+		{map[string]string{"on_error_row": "dlq", "dlq_sink": "kafka://dlq"}, false, ""},
+		{map[string]string{"dlq_sink": "kafka://dlq"}, false, "only usable with on_error_row=dlq"},
+		{map[string]string{"on_error_row": "fail", "dlq_sink": "kafka://dlq"}, false, "only usable with on_error_row=dlq"},
+		{map[string]string{"on_error_row": "dlq", "format": "parquet"}, false, "not supported with format=parquet"},
+		{map[string]string{"exactly_once": "", "initial_scan": "no", "schema_change_policy": "nobackfill"}, false, ""},
+		{map[string]string{"exactly_once": "", "cursor": "1", "schema_change_policy": "stop"}, false, ""},
+		{map[string]string{"exactly_once": "", "initial_scan": "no", "schema_change_policy": "nobackfill", "unordered": ""}, false, "is not usable with"},
+		{map[string]string{"exactly_once": "", "initial_scan": "no", "schema_change_policy": "nobackfill", "resolved": ""}, false, "resolved is not usable with exactly_once"},
+		{map[string]string{"exactly_once": "", "schema_change_policy": "nobackfill"}, false, "exactly_once requires initial_scan='no'"},
+		{map[string]string{"exactly_once": "", "initial_scan": "only", "schema_change_policy": "nobackfill"}, false, "exactly_once requires initial_scan='no'"},
+		{map[string]string{"exactly_once": "", "initial_scan": "no"}, false, "exactly_once is not usable with schema_change_policy=backfill"},
+		{map[string]string{"exactly_once": "", "initial_scan": "no", "schema_change_policy": "backfill"}, false, "exactly_once is not usable with schema_change_policy=backfill"},
+		{map[string]string{"ddl_events": ""}, false, ""},
+		{map[string]string{"ddl_events": "", "schema_change_policy": "ignore"}, false, "is not usable with schema_change_policy=ignore"},
+		{map[string]string{"ddl_events": "", "initial_scan": "only"}, false, "cannot specify both initial_scan='only'"},
+		{map[string]string{"replay": "", "cursor": "1", "end_time": "2"}, false, ""},
+		{map[string]string{"replay": "", "end_time": "2"}, false, "replay requires the cursor option"},
+		{map[string]string{"replay": "", "cursor": "1"}, false, "replay requires the end_time option"},
+		{map[string]string{"replay": "", "cursor": "1", "end_time": "2", "diff": ""}, false, "is not usable with"},
+		{map[string]string{"replay": "", "cursor": "1", "end_time": "2", "initial_scan": "yes"}, false, "replay is not usable with initial_scan='yes'"},
 	}
 
 	for _, test := range tests {
@@ -66,6 +93,16 @@ func TestEncodingOptionsValidations(t *testing.T) {
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, UpdatedTimestamps: true}, "is only usable with envelope=wrapped"},
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, MVCCTimestamps: true}, "is only usable with envelope=wrapped"},
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, Diff: true}, "is only usable with envelope=wrapped"},
+		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeWrapped, TransactionBoundaries: OptTransactionBoundariesMarkers}, "is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeBare, TransactionBoundaries: OptTransactionBoundariesField}, "is only usable with envelope=wrapped or envelope=enriched"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeBare, TransactionBoundaries: OptTransactionBoundariesMarkers}, ""},
+		{EncodingOptions{Envelope: OptEnvelopeRow, Format: OptFormatProtobuf}, "envelope=row is not supported with format=protobuf"},
+		{EncodingOptions{Format: OptFormatProtobuf, Envelope: OptEnvelopeBare, Diff: true}, "is only usable with envelope=wrapped"},
+		{EncodingOptions{Format: OptFormatProtobuf, Envelope: OptEnvelopeEnriched, Diff: true}, ""},
+		{EncodingOptions{Format: OptFormatCSV, Envelope: OptEnvelopeEnriched}, "envelope=enriched is only usable with format=json/avro/protobuf"},
+		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeEnriched, DDLEvents: true}, ""},
+		{EncodingOptions{Format: OptFormatProtobuf, Envelope: OptEnvelopeWrapped, DDLEvents: true}, "ddl_events is only usable with format=json/avro"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeBare, DDLEvents: true}, "ddl_events is only usable with envelope=wrapped or envelope=enriched"},
 	}
 
 	for _, c := range cases {
@@ -349,3 +349,33 @@ var Quantize = settings.RegisterDurationSettingWithExplicitUnit(
 	time.Duration(metamorphic.ConstantWithTestRange("changefeed.resolved_timestamp.granularity", 1, 0, 10))*time.Second,
 	settings.DurationWithMinimum(0),
 )
+
+// TransactionBoundariesMaxBufferedBytes limits how much data a change
+// aggregator buffers while waiting for the transactions of a changefeed
+// using the transaction_boundaries or exactly_once options to be resolved.
+var TransactionBoundariesMaxBufferedBytes = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"changefeed.transaction_boundaries.max_buffered_bytes",
+	"the maximum amount of row data a change aggregator buffers while grouping rows by transaction",
+	64<<20, // 64 MiB
+)
+
+// LookupJoinCacheSize is the maximum number of joined rows cached by each
+// CDC query that uses lookup joins.
+var LookupJoinCacheSize = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"changefeed.cdc_query.lookup_join.cache_size",
+	"the maximum number of rows cached by each lookup join in a CDC query",
+	10000,
+	settings.NonNegativeInt,
+)
+
+// LookupJoinCacheTTL is how long a CDC query reading the latest version of
+// joined rows may reuse a row it has already read.
+var LookupJoinCacheTTL = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"changefeed.cdc_query.lookup_join.cache_ttl",
+	"how long a CDC query using lookup_join_timestamp=latest may reuse a joined row it has already read",
+	10*time.Second,
+	settings.DurationWithMinimum(0),
+)
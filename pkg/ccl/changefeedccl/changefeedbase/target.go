@@ -64,6 +64,22 @@ func (tbt targetsByTable) each(f func(t Target) error) error {
 type Targets struct {
 	Size uint
 	m    map[descpb.ID]targetsByTable
+
+	// Scopes are the databases and schemas watched by a database or schema
+	// level changefeed. Tables created in a scope become targets when the
+	// changefeed restarts.
+	Scopes []jobspb.ChangefeedTargetScope
+}
+
+// InScope returns whether a table with the given parent database and schema
+// belongs to one of the target scopes.
+func (ts *Targets) InScope(dbID, schemaID descpb.ID) bool {
+	for _, s := range ts.Scopes {
+		if s.DatabaseID == dbID && (s.SchemaID == 0 || s.SchemaID == schemaID) {
+			return true
+		}
+	}
+	return false
 }
 
 // Add adds a target to the list.
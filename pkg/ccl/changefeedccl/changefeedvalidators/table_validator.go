@@ -124,3 +124,11 @@ func WarningsForTable(
 	}
 	return warnings
 }
+
+// IsScopedTable returns whether a table that belongs to one of the target
+// scopes of a database or schema level changefeed is watched by it. Views,
+// sequences and tables that are not public are skipped rather than rejected.
+func IsScopedTable(tableDesc catalog.TableDescriptor) bool {
+	return tableDesc.Public() && tableDesc.IsTable() && !tableDesc.IsVirtualTable() &&
+		!catalog.IsSystemDescriptor(tableDesc) && tableDesc.ExternalRowData() == nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/schemafeed"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// A changefeed created with the ddl_events option emits a message to the
+// topics of a table whenever a schema change alters the name, type or presence
+// of one of its visible columns. The schema feed of each aggregator queues
+// these events (see schemafeed.DDLEventSource). The aggregator watching the
+// start of the table's primary index pops them once its local frontier reaches
+// them and emits them in timestamp order with the transactions it buffers (see
+// txnGroupBuffer), so that every row written before the schema change precedes
+// the event and every row written after it follows it. The rows of a backfill
+// performed by the schema change are preceded by the event as well. The other
+// aggregators discard the events.
+
+// schemaChangeColumn is a visible column of a table before or after a schema
+// change.
+type schemaChangeColumn struct {
+	name, typ string
+}
+
+// schemaChangeEvent describes a schema change which altered the visible
+// columns of a table.
+type schemaChangeEvent struct {
+	tableID descpb.ID
+	table   string
+	// statement is the statement which caused the schema change. It is only
+	// known if the schema change was run by the declarative schema changer.
+	statement                   string
+	ts                          hlc.Timestamp
+	columnsBefore, columnsAfter []schemaChangeColumn
+}
+
+// schemaChangeEncoder is implemented by the encoders which can encode schema
+// change events.
+type schemaChangeEncoder interface {
+	// EncodeSchemaChange encodes a schema change event of the table of the
+	// given metadata. The returned bytes are only valid until the next call to
+	// Encode*.
+	EncodeSchemaChange(ctx context.Context, meta cdcevent.Metadata, ev schemaChangeEvent) ([]byte, error)
+}
+
+func makeSchemaChangeEvent(
+	e schemafeed.TableEvent, targets changefeedbase.Targets,
+) schemaChangeEvent {
+	columns := func(desc catalog.TableDescriptor) []schemaChangeColumn {
+		cols := schemafeed.WatchedVisibleColumns(desc, targets)
+		res := make([]schemaChangeColumn, len(cols))
+		for i, col := range cols {
+			res[i] = schemaChangeColumn{name: col.GetName(), typ: col.GetType().SQLString()}
+		}
+		return res
+	}
+	return schemaChangeEvent{
+		tableID:       e.After.GetID(),
+		table:         e.After.GetName(),
+		statement:     schemafeed.SchemaChangeStatement(e),
+		ts:            e.Timestamp(),
+		columnsBefore: columns(e.Before),
+		columnsAfter:  columns(e.After),
+	}
+}
+
+// ddlEventEmitter encodes the schema change events owned by an aggregator.
+type ddlEventEmitter struct {
+	source  schemafeed.DDLEventSource
+	targets changefeedbase.Targets
+	codec   keys.SQLCodec
+	spans   roachpb.Spans
+	encoder schemaChangeEncoder
+}
+
+func newDDLEventEmitter(
+	source schemafeed.DDLEventSource,
+	targets changefeedbase.Targets,
+	codec keys.SQLCodec,
+	spec execinfrapb.ChangeAggregatorSpec,
+	encoder Encoder,
+) (*ddlEventEmitter, error) {
+	sce, ok := encoder.(schemaChangeEncoder)
+	if !ok {
+		return nil, errors.AssertionFailedf("%T cannot encode schema change events", encoder)
+	}
+	spans := make(roachpb.Spans, 0, len(spec.Watches))
+	for _, w := range spec.Watches {
+		spans = append(spans, w.Span)
+	}
+	return &ddlEventEmitter{
+		source:  source,
+		targets: targets,
+		codec:   codec,
+		spans:   spans,
+		encoder: sce,
+	}, nil
+}
+
+// take pops the schema change events at or before the given timestamp and
+// returns the messages of those owned by the aggregator, in timestamp order.
+func (d *ddlEventEmitter) take(ctx context.Context, atOrBefore hlc.Timestamp) ([]bufferedRow, error) {
+	if atOrBefore.IsEmpty() {
+		return nil, nil
+	}
+	events, err := d.source.PopDDLEvents(ctx, atOrBefore)
+	if err != nil {
+		return nil, err
+	}
+	var res []bufferedRow
+	for _, e := range events {
+		if !d.owns(e) {
+			continue
+		}
+		msgs, err := d.encode(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, msgs...)
+	}
+	return res, nil
+}
+
+// owns returns true if the aggregator watches the start of the primary index
+// of the table of the event.
+func (d *ddlEventEmitter) owns(e schemafeed.TableEvent) bool {
+	key := e.Before.PrimaryIndexSpan(d.codec).Key
+	for _, sp := range d.spans {
+		if sp.ContainsKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// encode returns one message for each topic the rows of the table of the event
+// are emitted to.
+func (d *ddlEventEmitter) encode(ctx context.Context, e schemafeed.TableEvent) ([]bufferedRow, error) {
+	ev := makeSchemaChangeEvent(e, d.targets)
+	var res []bufferedRow
+	emit := func(t changefeedbase.Target, family *descpb.ColumnFamilyDescriptor) error {
+		meta := cdcevent.Metadata{
+			TableID:          e.After.GetID(),
+			TableName:        e.After.GetName(),
+			Version:          e.After.GetVersion(),
+			FamilyID:         family.ID,
+			FamilyName:       family.Name,
+			HasOtherFamilies: e.After.NumFamilies() > 1,
+			SchemaTS:         ev.ts,
+		}
+		topic, err := makeTopicDescriptorFromSpec(t, meta)
+		if err != nil {
+			return err
+		}
+		value, err := d.encoder.EncodeSchemaChange(ctx, meta, ev)
+		if err != nil {
+			return err
+		}
+		res = append(res, bufferedRow{
+			topic:   topic,
+			value:   append([]byte(nil), value...),
+			updated: ev.ts,
+			mvcc:    ev.ts,
+		})
+		return nil
+	}
+	if _, err := d.targets.EachHavingTableID(e.After.GetID(), func(t changefeedbase.Target) error {
+		switch t.Type {
+		case jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY:
+			family, err := catalog.MustFindFamilyByID(e.After, 0 /* familyID */)
+			if err != nil {
+				return err
+			}
+			return emit(t, family)
+		default:
+			return e.After.ForeachFamily(func(family *descpb.ColumnFamilyDescriptor) error {
+				if t.Type == jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY && family.Name != t.FamilyName {
+					return nil
+				}
+				return emit(t, family)
+			})
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONEncodeSchemaChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ev := schemaChangeEvent{
+		tableID:       104,
+		table:         `foo`,
+		statement:     `ALTER TABLE foo ADD COLUMN b INT8`,
+		ts:            hlc.Timestamp{WallTime: 1, Logical: 2},
+		columnsBefore: []schemaChangeColumn{{name: `a`, typ: `INT8`}},
+		columnsAfter:  []schemaChangeColumn{{name: `a`, typ: `INT8`}, {name: `b`, typ: `INT8`}},
+	}
+
+	for _, envelope := range []changefeedbase.EnvelopeType{
+		changefeedbase.OptEnvelopeWrapped, changefeedbase.OptEnvelopeEnriched,
+	} {
+		t.Run(string(envelope), func(t *testing.T) {
+			o := changefeedbase.EncodingOptions{
+				Format:    changefeedbase.OptFormatJSON,
+				Envelope:  envelope,
+				DDLEvents: true,
+			}
+			require.NoError(t, o.Validate())
+			e, err := getEncoder(ctx, o, changefeedbase.Targets{}, false, nil, nil, getTestingEnrichedSourceProvider(t, o))
+			require.NoError(t, err)
+			sce, ok := e.(schemaChangeEncoder)
+			require.True(t, ok)
+
+			value, err := sce.EncodeSchemaChange(ctx, cdcevent.Metadata{}, ev)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"schema_change": {
+				"table": "foo",
+				"table_id": 104,
+				"statement": "ALTER TABLE foo ADD COLUMN b INT8",
+				"timestamp": "1.0000000002",
+				"columns_before": [{"name": "a", "type": "INT8"}],
+				"columns_after": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "INT8"}]
+			}}`, string(value))
+
+			// The statement is omitted if the schema change was not run by the
+			// declarative schema changer.
+			noStmt := ev
+			noStmt.statement = ``
+			value, err = sce.EncodeSchemaChange(ctx, cdcevent.Metadata{}, noStmt)
+			require.NoError(t, err)
+			require.NotContains(t, string(value), `statement`)
+		})
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	dlqSchemaName = "crdb_changefeed"
+	// dlqTableName is defined as: "<dbName>.<dlqSchemaName>.dlq_<jobID>"
+	dlqTableName           = "%s.%s.%s"
+	createDLQSchemaStmt    = `CREATE SCHEMA IF NOT EXISTS %s.%s`
+	createDLQTableBaseStmt = `CREATE TABLE IF NOT EXISTS %s (
+		id              INT8 DEFAULT unique_rowid(),
+		job_id          INT8 NOT NULL,
+		table_id        INT8 NOT NULL,
+		dlq_timestamp   TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+		dlq_reason      STRING NOT NULL,
+		topic           STRING NOT NULL,
+		updated         DECIMAL NOT NULL,
+		mvcc_timestamp  DECIMAL NOT NULL,
+		key             BYTES,
+		value           BYTES,
+		decoded_row     JSONB,
+		PRIMARY KEY (job_id, dlq_timestamp, id) USING HASH
+	)`
+	insertDLQRowBaseStmt = `INSERT INTO %s (
+		job_id,
+		table_id,
+		dlq_reason,
+		topic,
+		updated,
+		mvcc_timestamp,
+		key,
+		value,
+		decoded_row
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+)
+
+// dlqRow is a row the changefeed could not emit.
+type dlqRow struct {
+	topic TopicDescriptor
+	// topicName is the name of the topic the row was rejected from. If it is
+	// empty, the name is derived from topic.
+	topicName string
+	// key and value are the encoded row. They are nil if the row could not be
+	// encoded.
+	key, value []byte
+	// row is the decoded row. It is uninitialized if the row was rejected by
+	// the sink after it was encoded.
+	row           cdcevent.Row
+	updated, mvcc hlc.Timestamp
+	reason        error
+}
+
+// dlqWriter writes rows to a dead letter queue. Implementations must be safe
+// for concurrent use.
+type dlqWriter interface {
+	write(ctx context.Context, r dlqRow, topicName string) error
+	Close() error
+}
+
+// deadLetterQueue records the rows that a changefeed with
+// on_error_row='dlq' could not encode or that the sink permanently rejected,
+// so that the changefeed can keep making progress. It is shared by the event
+// consumers and the sink of an aggregator.
+type deadLetterQueue struct {
+	w       dlqWriter
+	metrics *sliMetrics
+
+	// count is the number of rows written since the last call to takeCount.
+	count atomic.Uint64
+
+	mu struct {
+		syncutil.Mutex
+		topicNamer *TopicNamer
+	}
+}
+
+var dlqLogLim = log.Every(10 * time.Second)
+
+// makeDeadLetterQueue returns the dead letter queue of a changefeed, or nil if
+// the changefeed fails on rows it cannot emit.
+func makeDeadLetterQueue(
+	ctx context.Context,
+	cfg *execinfra.ServerConfig,
+	spec execinfrapb.ChangeAggregatorSpec,
+	feed ChangefeedConfig,
+	timestampOracle timestampLowerBoundOracle,
+	m metricsRecorder,
+	sliMetrics *sliMetrics,
+) (*deadLetterQueue, error) {
+	onErrorRow, err := feed.Opts.GetOnErrorRow()
+	if err != nil {
+		return nil, err
+	}
+	if onErrorRow != changefeedbase.OptOnErrorRowDLQ {
+		return nil, nil
+	}
+
+	topicNamer, err := MakeTopicNamer(feed.Targets)
+	if err != nil {
+		return nil, err
+	}
+
+	var w dlqWriter
+	if feed.Opts.GetDLQSink() != `` {
+		sink, err := getEventSink(ctx, cfg, dlqSinkDetails(spec.Feed), timestampOracle,
+			spec.User(), spec.JobID, m)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating dead letter queue sink")
+		}
+		w = &sinkDLQWriter{sink: sink}
+	} else {
+		w = &tableDLQWriter{
+			db:     cfg.ExecutorConfig.(*sql.ExecutorConfig).InternalDB,
+			jobID:  spec.JobID,
+			tables: make(map[descpb.ID]string),
+		}
+	}
+
+	q := &deadLetterQueue{w: w, metrics: sliMetrics}
+	q.mu.topicNamer = topicNamer
+	return q, nil
+}
+
+// dlqSinkDetails returns the changefeed details used to create the sink that
+// the dead letter queue of a changefeed with a dlq_sink writes to. The sink
+// receives JSON documents describing each row, so it is always configured
+// with the JSON format and none of the options of the changefeed itself.
+func dlqSinkDetails(details jobspb.ChangefeedDetails) jobspb.ChangefeedDetails {
+	details.SinkURI = details.Opts[changefeedbase.OptDLQSink]
+	details.Opts = map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	return details
+}
+
+// isDLQEligible returns true if err is an error specific to a row that should
+// send the row to the dead letter queue rather than fail the changefeed.
+// Errors that may go away on retry, such as failures to reach the schema
+// registry, and assertion failures are not eligible.
+func isDLQEligible(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !changefeedbase.IsRetryableError(err) &&
+		!errors.Is(err, context.Canceled) && !errors.HasAssertionFailure(err)
+}
+
+// write writes a row to the dead letter queue.
+func (q *deadLetterQueue) write(ctx context.Context, r dlqRow) error {
+	topicName := r.topicName
+	if topicName == `` {
+		var err error
+		if topicName, err = q.topicName(r.topic); err != nil {
+			return err
+		}
+	}
+	if err := q.w.write(ctx, r, topicName); err != nil {
+		return errors.Wrapf(err, "writing row to dead letter queue (original error: %v)", r.reason)
+	}
+	q.count.Add(1)
+	q.metrics.DLQRows.Inc(1)
+	if dlqLogLim.ShouldLog() {
+		log.Warningf(ctx, "row of %s written to dead letter queue: %v", topicName, r.reason)
+	}
+	return nil
+}
+
+func (q *deadLetterQueue) topicName(topic TopicDescriptor) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.mu.topicNamer.Name(topic)
+}
+
+// writeRejected writes a message that the sink permanently rejected to the
+// dead letter queue. It implements rejectedMessageHandler.
+func (q *deadLetterQueue) writeRejected(ctx context.Context, m rejectedMessage) error {
+	return q.write(ctx, dlqRow{
+		topic:     m.topic,
+		topicName: m.topicName,
+		key:       m.key,
+		value:     m.value,
+		updated:   m.mvcc,
+		mvcc:      m.mvcc,
+		reason:    m.reason,
+	})
+}
+
+// takeCount returns the number of rows written since it was last called.
+func (q *deadLetterQueue) takeCount() uint64 {
+	if q == nil {
+		return 0
+	}
+	return q.count.Swap(0)
+}
+
+// Close closes the dead letter queue.
+func (q *deadLetterQueue) Close() error {
+	if q == nil {
+		return nil
+	}
+	return q.w.Close()
+}
+
+// validateDLQTableScopes returns an error if rows are written to dead letter
+// queue tables and the changefeed has a target scope which contains them. The
+// tables are created in the crdb_changefeed schema of the database of each
+// watched table, so a database level changefeed would watch them.
+func validateDLQTableScopes(
+	opts changefeedbase.StatementOptions,
+	scopes []jobspb.ChangefeedTargetScope,
+	allDescs []catalog.Descriptor,
+) error {
+	if onErrorRow, err := opts.GetOnErrorRow(); err != nil {
+		return err
+	} else if onErrorRow != changefeedbase.OptOnErrorRowDLQ || opts.GetDLQSink() != `` {
+		return nil
+	}
+	for _, scope := range scopes {
+		if scope.SchemaID != 0 {
+			var schemaName string
+			for _, desc := range allDescs {
+				if desc.GetID() == scope.SchemaID {
+					schemaName = desc.GetName()
+				}
+			}
+			if schemaName != dlqSchemaName {
+				continue
+			}
+		}
+		return errors.WithHintf(
+			errors.Newf(`%s=%s writes rows to tables in the %s schema, which cannot be watched by the changefeed`,
+				changefeedbase.OptOnErrorRow, changefeedbase.OptOnErrorRowDLQ, dlqSchemaName),
+			"Use %s to write the rows to a sink, or target the schemas of the database instead.",
+			changefeedbase.OptDLQSink)
+	}
+	return nil
+}
+
+// tableDLQWriter writes rows to a table named dlq_<jobID> in the
+// crdb_changefeed schema of the database of the row's table. The table is
+// created the first time a row of a database is written.
+type tableDLQWriter struct {
+	db    descs.DB
+	jobID jobspb.JobID
+
+	mu syncutil.Mutex
+	// tables maps the ID of a watched table to the name of the dead letter
+	// queue table its rows are written to.
+	tables map[descpb.ID]string
+}
+
+var _ dlqWriter = (*tableDLQWriter)(nil)
+
+func (w *tableDLQWriter) write(ctx context.Context, r dlqRow, topicName string) error {
+	tableID := r.topic.GetTopicIdentifier().TableID
+	dlqTable, err := w.tableFor(ctx, tableID)
+	if err != nil {
+		return err
+	}
+
+	row := tree.DNull
+	if r.row.IsInitialized() {
+		j, err := r.row.ToJSON()
+		if err != nil {
+			log.Warningf(ctx, "failed to convert row to json: %v", err)
+		} else {
+			row = j
+		}
+	}
+
+	_, err = w.db.Executor().Exec(ctx, "insert-changefeed-dlq-row", nil, /* txn */
+		fmt.Sprintf(insertDLQRowBaseStmt, dlqTable),
+		w.jobID,
+		tableID,
+		r.reason.Error(),
+		topicName,
+		eval.TimestampToDecimalDatum(r.updated),
+		eval.TimestampToDecimalDatum(r.mvcc),
+		bytesOrNull(r.key),
+		bytesOrNull(r.value),
+		row,
+	)
+	return err
+}
+
+// tableFor returns the name of the dead letter queue table for the rows of
+// the specified table, creating it if necessary.
+func (w *tableDLQWriter) tableFor(ctx context.Context, tableID descpb.ID) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if name, ok := w.tables[tableID]; ok {
+		return name, nil
+	}
+
+	var dbName string
+	if err := w.db.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		byID := txn.Descriptors().ByIDWithoutLeased(txn.KV()).Get()
+		tableDesc, err := byID.Table(ctx, tableID)
+		if err != nil {
+			return err
+		}
+		dbDesc, err := byID.Database(ctx, tableDesc.GetParentID())
+		if err != nil {
+			return err
+		}
+		dbName = dbDesc.GetName()
+		return nil
+	}); err != nil {
+		return "", errors.Wrapf(err, "resolving database of table %d", tableID)
+	}
+
+	escapedDB := lexbase.EscapeSQLIdent(dbName)
+	name := fmt.Sprintf(dlqTableName, escapedDB, dlqSchemaName,
+		lexbase.EscapeSQLIdent(fmt.Sprintf("dlq_%d", w.jobID)))
+	if _, err := w.db.Executor().Exec(ctx, "create-changefeed-dlq-schema", nil, /* txn */
+		fmt.Sprintf(createDLQSchemaStmt, escapedDB, dlqSchemaName)); err != nil {
+		return "", errors.Wrapf(err, "failed to create %s schema in database %s", dlqSchemaName, escapedDB)
+	}
+	if _, err := w.db.Executor().Exec(ctx, "create-changefeed-dlq-table", nil, /* txn */
+		fmt.Sprintf(createDLQTableBaseStmt, name)); err != nil {
+		return "", errors.Wrapf(err, "failed to create dead letter queue table %s", name)
+	}
+	w.tables[tableID] = name
+	return name, nil
+}
+
+// Close implements the dlqWriter interface.
+func (w *tableDLQWriter) Close() error {
+	return nil
+}
+
+func bytesOrNull(b []byte) tree.Datum {
+	if b == nil {
+		return tree.DNull
+	}
+	return tree.NewDBytes(tree.DBytes(b))
+}
+
+// sinkDLQWriter writes rows to a sink as JSON documents. Each row is flushed
+// before write returns, so that the row is not lost if the changefeed
+// checkpoints past it.
+type sinkDLQWriter struct {
+	mu   syncutil.Mutex
+	sink EventSink
+}
+
+var _ dlqWriter = (*sinkDLQWriter)(nil)
+
+func (w *sinkDLQWriter) write(ctx context.Context, r dlqRow, topicName string) error {
+	value, err := dlqRowToJSON(r, topicName)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.sink.EmitRow(ctx, r.topic, r.key, value, r.updated, r.mvcc,
+		kvevent.Alloc{}, nil /* headers */); err != nil {
+		return err
+	}
+	return w.sink.Flush(ctx)
+}
+
+// Close implements the dlqWriter interface.
+func (w *sinkDLQWriter) Close() error {
+	return w.sink.Close()
+}
+
+// dlqRowToJSON returns the JSON document describing the row that is emitted to
+// a dead letter queue sink. The encoded key and value are base64 encoded, as
+// they need not be valid JSON.
+func dlqRowToJSON(r dlqRow, topicName string) ([]byte, error) {
+	b := json.NewObjectBuilder(8)
+	b.Add("error", json.FromString(r.reason.Error()))
+	b.Add("topic", json.FromString(topicName))
+	b.Add("updated", json.FromString(r.updated.AsOfSystemTime()))
+	b.Add("mvcc_timestamp", json.FromString(r.mvcc.AsOfSystemTime()))
+	if r.key != nil {
+		b.Add("key", json.FromString(base64.StdEncoding.EncodeToString(r.key)))
+	}
+	if r.value != nil {
+		b.Add("value", json.FromString(base64.StdEncoding.EncodeToString(r.value)))
+	}
+	if r.row.IsInitialized() {
+		row, err := r.row.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		b.Add("row", row.JSON)
+	}
+	var buf bytes.Buffer
+	b.Build().Format(&buf)
+	return buf.Bytes(), nil
+}
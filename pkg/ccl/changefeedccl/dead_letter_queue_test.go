@@ -0,0 +1,133 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/cidr"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterQueueTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.foo (a INT PRIMARY KEY)`)
+
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+	desc := desctestutils.TestingGetPublicTableDescriptor(s.DB(), s.Codec(), "d", "foo")
+	topic := &tableDescriptorTopic{
+		Metadata: makeMetadata(desc),
+		spec: changefeedbase.Target{
+			Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+			TableID:           desc.GetID(),
+			StatementTimeName: "foo",
+		},
+	}
+	targets := changefeedbase.Targets{}
+	targets.Add(topic.spec)
+	topicNamer, err := MakeTopicNamer(targets)
+	require.NoError(t, err)
+
+	sli, err := MakeMetrics(base.DefaultHistogramWindowInterval(), cidr.NewTestLookup()).(*Metrics).AggMetrics.getOrCreateScope("")
+	require.NoError(t, err)
+
+	q := &deadLetterQueue{
+		w: &tableDLQWriter{
+			db:     execCfg.InternalDB,
+			jobID:  123,
+			tables: make(map[descpb.ID]string),
+		},
+		metrics: sli,
+	}
+	q.mu.topicNamer = topicNamer
+	defer func() { require.NoError(t, q.Close()) }()
+
+	ts := hlc.Timestamp{WallTime: 42}
+	require.NoError(t, q.write(ctx, dlqRow{
+		topic:   topic,
+		key:     []byte(`[1]`),
+		updated: ts,
+		mvcc:    ts,
+		reason:  errors.New("cannot encode value"),
+	}))
+	require.NoError(t, q.writeRejected(ctx, rejectedMessage{
+		topic:     topic,
+		topicName: "prefix_foo",
+		key:       []byte(`[2]`),
+		value:     []byte(`{"after": {"a": 2}}`),
+		mvcc:      ts,
+		reason:    errors.New("message too large"),
+	}))
+
+	require.Equal(t, uint64(2), q.takeCount())
+	require.Equal(t, uint64(0), q.takeCount())
+	require.Equal(t, int64(2), sli.DLQRows.Value())
+
+	sqlDB.CheckQueryResults(t, `
+SELECT job_id, table_id = $1, dlq_reason, topic, updated, mvcc_timestamp,
+  encode(key, 'escape'), encode(value, 'escape')
+FROM d.crdb_changefeed.dlq_123 ORDER BY key`,
+		[][]string{
+			{"123", "true", "cannot encode value", "foo", "42.0000000000", "42.0000000000", `[1]`, "NULL"},
+			{"123", "true", "message too large", "prefix_foo", "42.0000000000", "42.0000000000", `[2]`, `{"after": {"a": 2}}`},
+		}, desc.GetID())
+}
+
+func TestDLQRowToJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	r := dlqRow{
+		topic:   noTopic{},
+		key:     []byte(`[1]`),
+		value:   []byte{0xff},
+		updated: hlc.Timestamp{WallTime: 1},
+		mvcc:    hlc.Timestamp{WallTime: 2},
+		reason:  errors.New("boom"),
+	}
+	j, err := dlqRowToJSON(r, "foo")
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"error": "boom", "key": "WzFd", "mvcc_timestamp": "2.0000000000", `+
+			`"topic": "foo", "updated": "1.0000000000", "value": "/w=="}`,
+		string(j))
+}
+
+func TestIsDLQEligible(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	require.True(t, isDLQEligible(ctx, errors.New("cannot encode")))
+	require.False(t, isDLQEligible(ctx, changefeedbase.MarkRetryableError(errors.New("registry down"))))
+	require.False(t, isDLQEligible(ctx, errors.AssertionFailedf("bug")))
+	require.False(t, isDLQEligible(ctx, context.Canceled))
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.False(t, isDLQEligible(cancelCtx, errors.New("cannot encode")))
+}
@@ -56,6 +56,8 @@ func getEncoder(
 		return newConfluentAvroEncoder(opts, targets, p, sliMetrics, sourceProvider)
 	case changefeedbase.OptFormatCSV:
 		return newCSVEncoder(opts), nil
+	case changefeedbase.OptFormatProtobuf:
+		return newConfluentProtobufEncoder(opts, targets, p, sliMetrics, sourceProvider)
 	case changefeedbase.OptFormatParquet:
 		//We will return no encoder for parquet format because there is a separate
 		//sink implemented for parquet format for cloud storage, which does the job
@@ -46,6 +46,8 @@ type confluentAvroEncoder struct {
 	// resolvedCache doesn't need to be bounded like the other caches because the number of topics
 	// is fixed per changefeed.
 	resolvedCache map[string]confluentRegisteredEnvelopeSchema
+	// schemaChangeCache is keyed by topic like resolvedCache.
+	schemaChangeCache map[string]confluentRegisteredEnvelopeSchema
 }
 
 type tableIDAndVersion struct {
@@ -122,6 +124,7 @@ func newConfluentAvroEncoder(
 	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
 	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
 	e.resolvedCache = make(map[string]confluentRegisteredEnvelopeSchema)
+	e.schemaChangeCache = make(map[string]confluentRegisteredEnvelopeSchema)
 	return e, nil
 }
 
@@ -347,8 +350,63 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 	return registered.schema.BinaryFromRow(header, meta, nilRow, nilRow, nilRow, "")
 }
 
+var _ schemaChangeEncoder = &confluentAvroEncoder{}
+
+// EncodeSchemaChange implements the schemaChangeEncoder interface.
+func (e *confluentAvroEncoder) EncodeSchemaChange(
+	ctx context.Context, meta cdcevent.Metadata, ev schemaChangeEvent,
+) ([]byte, error) {
+	topic, err := getTableName(e.targets, e.schemaPrefix, meta)
+	if err != nil {
+		return nil, err
+	}
+	registered, ok := e.schemaChangeCache[topic]
+	if !ok {
+		opts := avro.EnvelopeOpts{SchemaChangeField: true}
+		registered.schema, err = avro.NewEnvelopeRecord(topic, opts, nil /* before */, nil /* after */, nil /* record */, nil /* source */, e.schemaPrefix /* namespace */)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := changefeedbase.SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, &registered.schema.Record, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		e.schemaChangeCache[topic] = registered
+	}
+	columns := func(cols []schemaChangeColumn) []avro.SchemaChangeColumn {
+		res := make([]avro.SchemaChangeColumn, len(cols))
+		for i, col := range cols {
+			res[i] = avro.SchemaChangeColumn{Name: col.name, Type: col.typ}
+		}
+		return res
+	}
+	avroMeta := avro.Metadata{
+		`schema_change`: avro.SchemaChange{
+			Table:         ev.table,
+			TableID:       int64(ev.tableID),
+			Statement:     ev.statement,
+			Timestamp:     ev.ts,
+			ColumnsBefore: columns(ev.columnsBefore),
+			ColumnsAfter:  columns(ev.columnsAfter),
+		},
+	}
+	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
+	header := []byte{
+		changefeedbase.ConfluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
+	var nilRow cdcevent.Row
+	return registered.schema.BinaryFromRow(header, avroMeta, nilRow, nilRow, nilRow, "")
+}
+
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avro.Record, subject string,
 ) (int32, error) {
-	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, schema.Schema())
+	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, schema.Schema(), confluentSchemaTypeAvro)
 }
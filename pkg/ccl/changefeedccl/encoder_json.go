@@ -55,7 +55,7 @@ func init() {
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
 	updatedField, mvccTimestampField, beforeField, keyInValue, topicInValue,
-	sourceField, schemaField bool
+	sourceField, schemaField, txnField bool
 	envelopeType                   changefeedbase.EnvelopeType
 	enrichedEnvelopeSourceProvider *enrichedSourceProvider
 	targets                        changefeedbase.Targets
@@ -119,6 +119,7 @@ func makeJSONEncoder(
 		topicInValue: opts.TopicInValue,
 		sourceField:  inSet(changefeedbase.EnrichedPropertySource, opts.EnrichedProperties),
 		schemaField:  inSet(changefeedbase.EnrichedPropertySchema, opts.EnrichedProperties),
+		txnField:     opts.TransactionBoundaries == changefeedbase.OptTransactionBoundariesField,
 		versionEncoder: func(ed *cdcevent.EventDescriptor, isPrev bool) *versionEncoder {
 			key := jsonEncoderVersionKey{
 				CacheKey: cdcevent.CacheKey{
@@ -498,6 +499,9 @@ func (e *jsonEncoder) initWrappedEnvelope(ctx context.Context) error {
 	if e.mvccTimestampField {
 		keys = append(keys, "mvcc_timestamp")
 	}
+	if e.txnField {
+		keys = append(keys, "txn")
+	}
 	b, err := json.NewFixedKeysObjectBuilder(keys)
 	if err != nil {
 		return err
@@ -554,6 +558,12 @@ func (e *jsonEncoder) initWrappedEnvelope(ctx context.Context) error {
 			}
 		}
 
+		if e.txnField {
+			if err := b.Set("txn", txnFieldJSON(evCtx)); err != nil {
+				return nil, err
+			}
+		}
+
 		return b.Build()
 	}
 	return nil
@@ -657,6 +667,9 @@ func (e *jsonEncoder) initEnrichedEnvelope(ctx context.Context) error {
 	if e.sourceField {
 		payloadKeys = append(payloadKeys, "source")
 	}
+	if e.txnField {
+		payloadKeys = append(payloadKeys, "txn")
+	}
 	// TODO(#various): implement options for this envelope: before, key, topic, updated, mvcc_timestamp, ..
 	payloadBuilder, err := json.NewFixedKeysObjectBuilder(payloadKeys)
 	if err != nil {
@@ -709,6 +722,11 @@ func (e *jsonEncoder) initEnrichedEnvelope(ctx context.Context) error {
 				return nil, err
 			}
 		}
+		if e.txnField {
+			if err := payloadBuilder.Set("txn", txnFieldJSON(evCtx)); err != nil {
+				return nil, err
+			}
+		}
 
 		payload, err := payloadBuilder.Build()
 		if err != nil {
@@ -777,6 +795,34 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 	return gojson.Marshal(jsonEntries)
 }
 
+var _ schemaChangeEncoder = &jsonEncoder{}
+
+// EncodeSchemaChange implements the schemaChangeEncoder interface.
+func (e *jsonEncoder) EncodeSchemaChange(
+	_ context.Context, _ cdcevent.Metadata, ev schemaChangeEvent,
+) ([]byte, error) {
+	columns := func(cols []schemaChangeColumn) []map[string]string {
+		res := make([]map[string]string, len(cols))
+		for i, col := range cols {
+			res[i] = map[string]string{`name`: col.name, `type`: col.typ}
+		}
+		return res
+	}
+	schemaChange := map[string]interface{}{
+		`table`:          ev.table,
+		`table_id`:       ev.tableID,
+		`timestamp`:      eval.TimestampToDecimalDatum(ev.ts).Decimal.String(),
+		`columns_before`: columns(ev.columnsBefore),
+		`columns_after`:  columns(ev.columnsAfter),
+	}
+	if ev.statement != `` {
+		schemaChange[`statement`] = ev.statement
+	}
+	return gojson.Marshal(map[string]interface{}{
+		`schema_change`: schemaChange,
+	})
+}
+
 var placeholderCtx = eventContext{topic: "topic"}
 
 // EncodeAsJSONChangefeedWithFlags implements the crdb_internal.to_json_as_changefeed_with_flags
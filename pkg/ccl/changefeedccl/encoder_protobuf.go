@@ -0,0 +1,868 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the envelope messages. The numbers don't depend on which
+// fields are present so that the fields keep their numbers as options change.
+const (
+	protobufFieldAfter         protowire.Number = 1
+	protobufFieldRecord        protowire.Number = 1
+	protobufFieldBefore        protowire.Number = 2
+	protobufFieldUpdated       protowire.Number = 3
+	protobufFieldMVCCTimestamp protowire.Number = 4
+	protobufFieldOp            protowire.Number = 5
+	protobufFieldTsNs          protowire.Number = 6
+	protobufFieldSource        protowire.Number = 7
+	protobufFieldResolved      protowire.Number = 1
+	protobufFieldElementValue  protowire.Number = 1
+)
+
+const (
+	protobufImportTimestamp = `google/protobuf/timestamp.proto`
+	protobufImportStruct    = `google/protobuf/struct.proto`
+)
+
+// Bounds of google.protobuf.Timestamp, 0001-01-01T00:00:00Z through
+// 9999-12-31T23:59:59Z.
+const (
+	protobufMinTimestampSeconds = -62135596800
+	protobufMaxTimestampSeconds = 253402300799
+)
+
+// confluentProtobufEncoder encodes changefeed entries as Protobuf messages in
+// the Confluent wire format. The message descriptors are generated from the
+// table descriptors and registered with the schema registry. Keys are the
+// primary key columns in a message. Values are an envelope message holding
+// all columns in a nested message.
+type confluentProtobufEncoder struct {
+	schemaRegistry                         schemaRegistry
+	updatedField, beforeField, sourceField bool
+	mvccTimestampField                     bool
+	targets                                changefeedbase.Targets
+	envelopeType                           changefeedbase.EnvelopeType
+	schemaPrefix                           string
+	customKeyColumn                        string
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]confluentRegisteredProtobufKey
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredProtobufEnvelope
+
+	enrichedSourceProvider *enrichedSourceProvider
+
+	// resolvedCache doesn't need to be bounded like the other caches because
+	// the number of topics is fixed per changefeed.
+	resolvedCache map[string]int32
+}
+
+type confluentRegisteredProtobufKey struct {
+	schema     *protobufMessage
+	registryID int32
+}
+
+type confluentRegisteredProtobufEnvelope struct {
+	// record holds the columns of the updated row, and before those of the
+	// previous row if the before field is present.
+	record, before *protobufMessage
+	registryID     int32
+}
+
+var _ Encoder = &confluentProtobufEncoder{}
+
+func newConfluentProtobufEncoder(
+	opts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	p externalConnectionProvider,
+	sliMetrics *sliMetrics,
+	enrichedSourceProvider *enrichedSourceProvider,
+) (*confluentProtobufEncoder, error) {
+	e := &confluentProtobufEncoder{
+		updatedField:           opts.UpdatedTimestamps,
+		beforeField:            opts.Diff,
+		sourceField:            inSet(changefeedbase.EnrichedPropertySource, opts.EnrichedProperties),
+		mvccTimestampField:     opts.MVCCTimestamps,
+		targets:                targets,
+		envelopeType:           opts.Envelope,
+		schemaPrefix:           opts.AvroSchemaPrefix,
+		customKeyColumn:        opts.CustomKeyColumn,
+		enrichedSourceProvider: enrichedSourceProvider,
+	}
+
+	if opts.KeyInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if opts.TopicInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if len(opts.SchemaRegistryURI) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	reg, err := newConfluentSchemaRegistry(opts.SchemaRegistryURI, p, sliMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	e.schemaRegistry = reg
+	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.resolvedCache = make(map[string]int32)
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeKey(
+	ctx context.Context, row cdcevent.Row,
+) ([]byte, error) {
+	it := row.ForEachKeyColumn()
+	if e.customKeyColumn != "" {
+		var err error
+		if it, err = row.DatumNamed(e.customKeyColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	// No familyID in the cache key for keys because it's the same schema for
+	// all families.
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version}
+	var registered confluentRegisteredProtobufKey
+	if v, ok := e.keyCache.Get(cacheKey); ok {
+		registered = v.(confluentRegisteredProtobufKey)
+	} else {
+		tableName, err := getTableName(e.targets, e.schemaPrefix, row.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		registered.schema, err = newProtobufRowMessage(changefeedbase.SQLNameToAvroName(tableName), it)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := changefeedbase.SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+		registered.registryID, err = e.register(ctx, subject, registered.schema)
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registered)
+	}
+
+	return registered.schema.appendRow(confluentProtobufHeader(registered.registryID), it)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if e.envelopeType == changefeedbase.OptEnvelopeKeyOnly {
+		return nil, nil
+	}
+
+	withBefore := e.beforeField && prevRow.IsInitialized()
+	var cacheKey tableIDAndVersionPair
+	if withBefore {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: prevRow.TableID, version: prevRow.Version, familyID: prevRow.FamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
+	}
+
+	var registered confluentRegisteredProtobufEnvelope
+	var err error
+	if v, ok := e.valueCache.Get(cacheKey); ok {
+		registered = v.(confluentRegisteredProtobufEnvelope)
+	} else {
+		if registered, err = e.registerEnvelope(ctx, updatedRow, prevRow, withBefore); err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registered)
+	}
+
+	buf := confluentProtobufHeader(registered.registryID)
+	recordField := protobufFieldAfter
+	if e.envelopeType == changefeedbase.OptEnvelopeBare {
+		recordField = protobufFieldRecord
+	}
+	if !updatedRow.IsDeleted() {
+		if buf, err = registered.record.appendRowField(buf, recordField, updatedRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+	}
+	if withBefore && !prevRow.IsDeleted() {
+		if buf, err = registered.before.appendRowField(buf, protobufFieldBefore, prevRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.envelopeType == changefeedbase.OptEnvelopeEnriched {
+		buf = protowire.AppendTag(buf, protobufFieldOp, protowire.BytesType)
+		buf = protowire.AppendString(buf, string(deduceOp(updatedRow, prevRow)))
+		buf = protowire.AppendTag(buf, protobufFieldTsNs, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(timeutil.Now().UnixNano()))
+		if e.sourceField {
+			source, err := e.enrichedSourceProvider.GetJSON(updatedRow, evCtx)
+			if err != nil {
+				return nil, err
+			}
+			if buf, err = appendProtobufMessageField(buf, protobufFieldSource, source, appendProtobufStruct); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	if e.updatedField {
+		buf = protowire.AppendTag(buf, protobufFieldUpdated, protowire.BytesType)
+		buf = protowire.AppendString(buf, evCtx.updated.AsOfSystemTime())
+	}
+	if e.mvccTimestampField {
+		buf = protowire.AppendTag(buf, protobufFieldMVCCTimestamp, protowire.BytesType)
+		buf = protowire.AppendString(buf, evCtx.mvcc.AsOfSystemTime())
+	}
+	return buf, nil
+}
+
+// registerEnvelope generates and registers the value schema of a row.
+func (e *confluentProtobufEncoder) registerEnvelope(
+	ctx context.Context, updatedRow cdcevent.Row, prevRow cdcevent.Row, withBefore bool,
+) (confluentRegisteredProtobufEnvelope, error) {
+	var registered confluentRegisteredProtobufEnvelope
+	tableName, err := getTableName(e.targets, e.schemaPrefix, updatedRow.Metadata)
+	if err != nil {
+		return registered, err
+	}
+	name := changefeedbase.SQLNameToAvroName(tableName)
+	if registered.record, err = newProtobufRowMessage(name, updatedRow.ForEachColumn()); err != nil {
+		return registered, err
+	}
+	if withBefore {
+		if registered.before, err = newProtobufRowMessage(name+`_before`, prevRow.ForEachColumn()); err != nil {
+			return registered, err
+		}
+	}
+
+	// In the wrapped and enriched envelopes, row data goes in the "after"
+	// field. In the bare envelope, it goes in the "record" field. Metadata can
+	// safely go in the envelope as there are never arbitrary column names for
+	// it to conflict with.
+	envelope := &protobufMessage{name: name + `_envelope`}
+	switch e.envelopeType {
+	case changefeedbase.OptEnvelopeWrapped, changefeedbase.OptEnvelopeEnriched:
+		envelope.addMessageField(`after`, protobufFieldAfter, registered.record.name)
+		if withBefore {
+			envelope.addMessageField(`before`, protobufFieldBefore, registered.before.name)
+		}
+	case changefeedbase.OptEnvelopeBare:
+		envelope.addMessageField(`record`, protobufFieldRecord, registered.record.name)
+	// key_only is handled by the caller, and row is not supported in protobuf.
+	default:
+		return registered, errors.AssertionFailedf(`unknown envelope type: %s`, e.envelopeType)
+	}
+	if e.envelopeType == changefeedbase.OptEnvelopeEnriched {
+		envelope.fields = append(envelope.fields,
+			protobufField{name: `op`, number: protobufFieldOp, scalar: protobufString},
+			protobufField{name: `ts_ns`, number: protobufFieldTsNs, scalar: protobufInt64},
+		)
+		if e.sourceField {
+			envelope.fields = append(envelope.fields, protobufField{
+				name: `source`, number: protobufFieldSource, scalar: protobufStruct,
+			})
+		}
+	} else {
+		if e.updatedField {
+			envelope.fields = append(envelope.fields, protobufField{
+				name: `updated`, number: protobufFieldUpdated, scalar: protobufString,
+			})
+		}
+		if e.mvccTimestampField {
+			envelope.fields = append(envelope.fields, protobufField{
+				name: `mvcc_timestamp`, number: protobufFieldMVCCTimestamp, scalar: protobufString,
+			})
+		}
+	}
+
+	// NB: This uses the kafka name escaper because it has to match the name of
+	// the kafka topic.
+	subject := changefeedbase.SQLNameToKafkaName(tableName) + confluentSubjectSuffixValue
+	registered.registryID, err = e.register(ctx, subject, envelope, registered.record, registered.before)
+	return registered, err
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registryID, ok := e.resolvedCache[topic]
+	if !ok {
+		msg := &protobufMessage{
+			name: changefeedbase.SQLNameToAvroName(topic) + `_resolved`,
+			fields: []protobufField{
+				{name: `resolved`, number: protobufFieldResolved, scalar: protobufString},
+			},
+		}
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := changefeedbase.SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		var err error
+		if registryID, err = e.register(ctx, subject, msg); err != nil {
+			return nil, err
+		}
+		e.resolvedCache[topic] = registryID
+	}
+
+	buf := confluentProtobufHeader(registryID)
+	buf = protowire.AppendTag(buf, protobufFieldResolved, protowire.BytesType)
+	return protowire.AppendString(buf, resolved.AsOfSystemTime()), nil
+}
+
+func (e *confluentProtobufEncoder) register(
+	ctx context.Context, subject string, messages ...*protobufMessage,
+) (int32, error) {
+	return e.schemaRegistry.RegisterSchemaForSubject(
+		ctx, subject, protobufSchema(messages...), confluentSchemaTypeProtobuf)
+}
+
+// confluentProtobufHeader returns the header of a message in the Confluent
+// Protobuf wire format: the magic byte, the schema ID, and the index of the
+// encoded message in the schema. The encoded message is always the first one
+// of the schema, whose index is written as a single zero byte.
+//
+//	https://docs.confluent.io/platform/current/schema-registry/fundamentals/serdes-develop/index.html#wire-format
+func confluentProtobufHeader(registryID int32) []byte {
+	header := []byte{
+		changefeedbase.ConfluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+		0, // Message index.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
+}
+
+// protobufSchema returns the .proto source of a schema defining the given
+// messages. The first message is the one encoded using the schema. Nil
+// messages are skipped.
+func protobufSchema(messages ...*protobufMessage) string {
+	imports := make(map[string]struct{})
+	for _, m := range messages {
+		if m == nil {
+			continue
+		}
+		for _, f := range m.fields {
+			if f.scalar.importPath != `` {
+				imports[f.scalar.importPath] = struct{}{}
+			}
+		}
+	}
+	sortedImports := make([]string, 0, len(imports))
+	for i := range imports {
+		sortedImports = append(sortedImports, i)
+	}
+	sort.Strings(sortedImports)
+
+	var sb strings.Builder
+	sb.WriteString("syntax = \"proto3\";\n")
+	for _, i := range sortedImports {
+		fmt.Fprintf(&sb, "\nimport %q;", i)
+	}
+	if len(sortedImports) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, m := range messages {
+		if m != nil {
+			sb.WriteString("\n")
+			m.writeSchema(&sb)
+		}
+	}
+	return sb.String()
+}
+
+// protobufScalar describes how the values of a SQL type are represented in a
+// protobuf field.
+type protobufScalar struct {
+	// typeName is the type of the field in the schema. Message types are fully
+	// qualified so that they can't be shadowed by generated names.
+	typeName string
+	// importPath is the file defining the type, if it isn't a scalar type.
+	importPath string
+	wireType   protowire.Type
+	// message is set for message types, which track presence without the
+	// optional keyword.
+	message bool
+	// appendValue appends the encoding of a non-NULL datum, without a tag.
+	appendValue func(b []byte, d tree.Datum) ([]byte, error)
+}
+
+var (
+	protobufBool = protobufScalar{
+		typeName: `bool`, wireType: protowire.VarintType,
+		appendValue: func(b []byte, d tree.Datum) ([]byte, error) {
+			return protowire.AppendVarint(b, protowire.EncodeBool(bool(*d.(*tree.DBool)))), nil
+		},
+	}
+	protobufInt32 = protobufScalar{
+		typeName: `int32`, wireType: protowire.VarintType,
+		appendValue: appendProtobufInt,
+	}
+	protobufInt64 = protobufScalar{
+		typeName: `int64`, wireType: protowire.VarintType,
+		appendValue: appendProtobufInt,
+	}
+	protobufFloat = protobufScalar{
+		typeName: `float`, wireType: protowire.Fixed32Type,
+		appendValue: func(b []byte, d tree.Datum) ([]byte, error) {
+			return protowire.AppendFixed32(b, math.Float32bits(float32(*d.(*tree.DFloat)))), nil
+		},
+	}
+	protobufDouble = protobufScalar{
+		typeName: `double`, wireType: protowire.Fixed64Type,
+		appendValue: func(b []byte, d tree.Datum) ([]byte, error) {
+			return protowire.AppendFixed64(b, math.Float64bits(float64(*d.(*tree.DFloat)))), nil
+		},
+	}
+	protobufBytes = protobufScalar{
+		typeName: `bytes`, wireType: protowire.BytesType,
+		appendValue: func(b []byte, d tree.Datum) ([]byte, error) {
+			return protowire.AppendString(b, string(*d.(*tree.DBytes))), nil
+		},
+	}
+	protobufString = protobufScalar{
+		typeName: `string`, wireType: protowire.BytesType,
+		appendValue: func(b []byte, d tree.Datum) ([]byte, error) {
+			switch t := d.(type) {
+			case *tree.DString:
+				return protowire.AppendString(b, string(*t)), nil
+			case *tree.DCollatedString:
+				return protowire.AppendString(b, t.Contents), nil
+			case *tree.DDecimal:
+				return protowire.AppendString(b, t.Decimal.String()), nil
+			case *tree.DEnum:
+				return protowire.AppendString(b, t.LogicalRep), nil
+			default:
+				return protowire.AppendString(b, tree.AsStringWithFlags(d, tree.FmtExport)), nil
+			}
+		},
+	}
+	protobufTimestamp = protobufScalar{
+		typeName: `.google.protobuf.Timestamp`, importPath: protobufImportTimestamp,
+		wireType: protowire.BytesType, message: true,
+		appendValue: appendProtobufTimestamp,
+	}
+	// JSON values are encoded as google.protobuf.Value rather than
+	// google.protobuf.Struct so that documents that aren't objects can be
+	// represented as well.
+	protobufJSON = protobufScalar{
+		typeName: `.google.protobuf.Value`, importPath: protobufImportStruct,
+		wireType: protowire.BytesType, message: true,
+		appendValue: func(b []byte, d tree.Datum) ([]byte, error) {
+			return appendProtobufMessage(b, d.(*tree.DJSON).JSON, appendProtobufValue)
+		},
+	}
+	protobufStruct = protobufScalar{
+		typeName: `.google.protobuf.Struct`, importPath: protobufImportStruct,
+		wireType: protowire.BytesType, message: true,
+	}
+)
+
+func appendProtobufInt(b []byte, d tree.Datum) ([]byte, error) {
+	return protowire.AppendVarint(b, uint64(tree.MustBeDInt(d))), nil
+}
+
+func appendProtobufTimestamp(b []byte, d tree.Datum) ([]byte, error) {
+	var secs int64
+	var nanos int32
+	switch t := d.(type) {
+	case *tree.DTimestamp:
+		secs, nanos = t.Unix(), int32(t.Nanosecond())
+	case *tree.DTimestampTZ:
+		secs, nanos = t.Unix(), int32(t.Nanosecond())
+	default:
+		return nil, errors.AssertionFailedf(`unexpected timestamp datum %T`, d)
+	}
+	if secs < protobufMinTimestampSeconds || secs > protobufMaxTimestampSeconds {
+		return nil, changefeedbase.WithTerminalError(errors.Newf(
+			`timestamp %s is outside of the range of google.protobuf.Timestamp`, d))
+	}
+	var msg []byte
+	if secs != 0 {
+		msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(secs))
+	}
+	if nanos != 0 {
+		msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(nanos))
+	}
+	return protowire.AppendBytes(b, msg), nil
+}
+
+// appendProtobufMessage appends the length prefixed encoding of a JSON value
+// as a message using the given function.
+func appendProtobufMessage(
+	b []byte, j json.JSON, appendFn func(b []byte, j json.JSON) ([]byte, error),
+) ([]byte, error) {
+	msg, err := appendFn(nil, j)
+	if err != nil {
+		return nil, err
+	}
+	return protowire.AppendBytes(b, msg), nil
+}
+
+// appendProtobufMessageField is like appendProtobufMessage, but also appends
+// the tag of the field.
+func appendProtobufMessageField(
+	b []byte, num protowire.Number, j json.JSON, appendFn func(b []byte, j json.JSON) ([]byte, error),
+) ([]byte, error) {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return appendProtobufMessage(b, j, appendFn)
+}
+
+// appendProtobufValue appends the fields of a google.protobuf.Value holding
+// the given JSON value. Numbers are represented as doubles.
+func appendProtobufValue(b []byte, j json.JSON) ([]byte, error) {
+	switch j.Type() {
+	case json.NullJSONType:
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		return protowire.AppendVarint(b, 0), nil
+	case json.NumberJSONType:
+		dec, _ := j.AsDecimal()
+		f, err := dec.Float64()
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, 2, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(f)), nil
+	case json.StringJSONType:
+		s, err := j.AsText()
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		return protowire.AppendString(b, *s), nil
+	case json.FalseJSONType, json.TrueJSONType:
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(j.Type() == json.TrueJSONType)), nil
+	case json.ObjectJSONType:
+		return appendProtobufMessageField(b, 5, j, appendProtobufStruct)
+	case json.ArrayJSONType:
+		return appendProtobufMessageField(b, 6, j, appendProtobufListValue)
+	default:
+		return nil, errors.AssertionFailedf(`unexpected JSON type %d`, j.Type())
+	}
+}
+
+// appendProtobufStruct appends the fields of a google.protobuf.Struct holding
+// the given JSON object.
+func appendProtobufStruct(b []byte, j json.JSON) ([]byte, error) {
+	it, err := j.ObjectIter()
+	if err != nil {
+		return nil, err
+	}
+	if it == nil {
+		return nil, errors.AssertionFailedf(`expected JSON object, found %s`, j)
+	}
+	for it.Next() {
+		// Each field is a map entry whose key and value are fields 1 and 2.
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, it.Key())
+		if entry, err = appendProtobufMessageField(entry, 2, it.Value(), appendProtobufValue); err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b, nil
+}
+
+// appendProtobufListValue appends the fields of a google.protobuf.ListValue
+// holding the given JSON array.
+func appendProtobufListValue(b []byte, j json.JSON) ([]byte, error) {
+	elems, ok := j.AsArray()
+	if !ok {
+		return nil, errors.AssertionFailedf(`expected JSON array, found %s`, j)
+	}
+	for _, elem := range elems {
+		var err error
+		if b, err = appendProtobufMessageField(b, 1, elem, appendProtobufValue); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// protobufField is a field of a generated message.
+type protobufField struct {
+	name   string
+	number protowire.Number
+	// repeated is set for array columns. The scalar of a repeated field is the
+	// message wrapping each element, and element describes the elements.
+	repeated bool
+	scalar   protobufScalar
+	element  protobufScalar
+}
+
+// appendDatum appends the encoding of the field holding the given datum.
+// NULLs are encoded by omitting the field.
+func (f *protobufField) appendDatum(b []byte, d tree.Datum) ([]byte, error) {
+	if d == tree.DNull {
+		return b, nil
+	}
+	d = tree.UnwrapDOidWrapper(d)
+	if !f.repeated {
+		b = protowire.AppendTag(b, f.number, f.scalar.wireType)
+		return f.scalar.appendValue(b, d)
+	}
+
+	arr, ok := d.(*tree.DArray)
+	if !ok {
+		return nil, errors.AssertionFailedf(`expected array datum, found %T`, d)
+	}
+	// Each element is wrapped in a message so that NULL elements, which
+	// protobuf can't represent in a repeated field, are encoded as messages
+	// without a value.
+	var elem []byte
+	for _, e := range arr.Array {
+		elem = elem[:0]
+		if e != tree.DNull {
+			var err error
+			elem = protowire.AppendTag(elem, protobufFieldElementValue, f.element.wireType)
+			if elem, err = f.element.appendValue(elem, tree.UnwrapDOidWrapper(e)); err != nil {
+				return nil, err
+			}
+		}
+		b = protowire.AppendTag(b, f.number, protowire.BytesType)
+		b = protowire.AppendBytes(b, elem)
+	}
+	return b, nil
+}
+
+// protobufElement is a message wrapping an element of an array.
+type protobufElement struct {
+	name   string
+	scalar protobufScalar
+}
+
+// protobufMessage is a generated message.
+type protobufMessage struct {
+	name     string
+	fields   []protobufField
+	elements []protobufElement
+
+	// fieldIdxByColumn maps the names of the columns of a row message to their
+	// fields.
+	fieldIdxByColumn map[string]int
+	// names holds the identifiers defined in the scope of the message.
+	names map[string]struct{}
+}
+
+// newProtobufRowMessage generates a message holding the given columns.
+//
+// Where possible, fields are numbered by the ID of their column so that
+// columns keep their field numbers as other columns are added and dropped,
+// which keeps consecutive versions of a schema compatible with each other.
+// Columns that don't refer to a table column, such as expressions in CDC
+// queries, cause all fields to be numbered by position instead.
+func newProtobufRowMessage(name string, it cdcevent.Iterator) (*protobufMessage, error) {
+	var cols []cdcevent.ResultColumn
+	if err := it.Col(func(col cdcevent.ResultColumn) error {
+		cols = append(cols, col)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	m := &protobufMessage{
+		name:             name,
+		fieldIdxByColumn: make(map[string]int, len(cols)),
+		names:            make(map[string]struct{}, len(cols)),
+	}
+	byColumnID := true
+	seen := make(map[protowire.Number]struct{}, len(cols))
+	for _, col := range cols {
+		num := protowire.Number(col.PGAttributeNum)
+		if _, dup := seen[num]; dup || num < protowire.MinValidNumber || num >= protowire.FirstReservedNumber {
+			byColumnID = false
+			break
+		}
+		seen[num] = struct{}{}
+	}
+	// Column names are unique and escaping them preserves that, so the field
+	// names are reserved before any element message is named.
+	for _, col := range cols {
+		m.names[changefeedbase.SQLNameToAvroName(col.Name)] = struct{}{}
+	}
+
+	for i, col := range cols {
+		f := protobufField{
+			name:   changefeedbase.SQLNameToAvroName(col.Name),
+			number: protowire.Number(col.PGAttributeNum),
+		}
+		if !byColumnID {
+			f.number = protowire.Number(i + 1)
+		}
+		typ := col.Typ
+		if typ.Family() == types.ArrayFamily {
+			f.repeated = true
+			typ = typ.ArrayContents()
+		}
+		scalar, err := protobufScalarForType(typ)
+		if err != nil {
+			return nil, errors.Wrapf(err, `column %s`, col.Name)
+		}
+		if f.repeated {
+			f.element = scalar
+			f.scalar = m.addElement(f.name, scalar)
+		} else {
+			f.scalar = scalar
+		}
+		m.fieldIdxByColumn[col.Name] = len(m.fields)
+		m.fields = append(m.fields, f)
+	}
+	return m, nil
+}
+
+// protobufScalarForType returns the representation of a non-array SQL type.
+func protobufScalarForType(typ *types.T) (protobufScalar, error) {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return protobufBool, nil
+	case types.IntFamily:
+		if typ.Width() == 16 || typ.Width() == 32 {
+			return protobufInt32, nil
+		}
+		return protobufInt64, nil
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			return protobufFloat, nil
+		}
+		return protobufDouble, nil
+	case types.BytesFamily:
+		return protobufBytes, nil
+	case types.TimestampFamily, types.TimestampTZFamily:
+		return protobufTimestamp, nil
+	case types.JsonFamily:
+		return protobufJSON, nil
+	case types.ArrayFamily, types.TupleFamily:
+		return protobufScalar{}, changefeedbase.WithTerminalError(
+			errors.Errorf(`type %s is not supported with %s=%s`,
+				typ.SQLString(), changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf))
+	default:
+		// Decimals are represented as strings to preserve their precision. Other
+		// types without a natural protobuf counterpart, such as dates, intervals,
+		// and UUIDs, use their SQL text representation. Enums are represented by
+		// their labels rather than as protobuf enums, whose numbers would change
+		// when a label is added before existing ones in sort order.
+		return protobufString, nil
+	}
+}
+
+// addElement adds a message wrapping the elements of the array field with the
+// given name and returns the representation of the wrapper.
+func (m *protobufMessage) addElement(fieldName string, scalar protobufScalar) protobufScalar {
+	elem := protobufElement{name: m.reserveName(fieldName + `_element`), scalar: scalar}
+	m.elements = append(m.elements, elem)
+	return protobufScalar{
+		typeName: elem.name, importPath: scalar.importPath,
+		wireType: protowire.BytesType, message: true,
+	}
+}
+
+// reserveName returns an identifier based on the given name that isn't yet
+// defined in the scope of the message.
+func (m *protobufMessage) reserveName(name string) string {
+	for {
+		if _, ok := m.names[name]; !ok {
+			m.names[name] = struct{}{}
+			return name
+		}
+		name += `_`
+	}
+}
+
+// addMessageField adds a field holding the message with the given name.
+func (m *protobufMessage) addMessageField(name string, num protowire.Number, typeName string) {
+	m.fields = append(m.fields, protobufField{
+		name:   name,
+		number: num,
+		scalar: protobufScalar{typeName: `.` + typeName, wireType: protowire.BytesType, message: true},
+	})
+}
+
+// appendRow appends the fields of a row message holding the datums of the
+// given iterator.
+func (m *protobufMessage) appendRow(b []byte, it cdcevent.Iterator) ([]byte, error) {
+	err := it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) (err error) {
+		idx, ok := m.fieldIdxByColumn[col.Name]
+		if !ok {
+			return changefeedbase.WithTerminalError(
+				errors.AssertionFailedf("could not find protobuf field for column %s", col.Name))
+		}
+		b, err = m.fields[idx].appendDatum(b, d)
+		return err
+	})
+	return b, err
+}
+
+// appendRowField appends a field holding a row message.
+func (m *protobufMessage) appendRowField(
+	b []byte, num protowire.Number, it cdcevent.Iterator,
+) ([]byte, error) {
+	msg, err := m.appendRow(nil, it)
+	if err != nil {
+		return nil, err
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg), nil
+}
+
+// writeSchema writes the definition of the message.
+func (m *protobufMessage) writeSchema(sb *strings.Builder) {
+	fmt.Fprintf(sb, "message %s {\n", m.name)
+	for _, elem := range m.elements {
+		fmt.Fprintf(sb, "  message %s {\n    ", elem.name)
+		writeProtobufFieldType(sb, elem.scalar, false /* repeated */)
+		fmt.Fprintf(sb, " value = %d;\n  }\n", protobufFieldElementValue)
+	}
+	for _, f := range m.fields {
+		sb.WriteString("  ")
+		writeProtobufFieldType(sb, f.scalar, f.repeated)
+		fmt.Fprintf(sb, " %s = %d;\n", f.name, f.number)
+	}
+	sb.WriteString("}\n")
+}
+
+// writeProtobufFieldType writes the label and the type of a field.
+func writeProtobufFieldType(sb *strings.Builder, scalar protobufScalar, repeated bool) {
+	if repeated {
+		sb.WriteString("repeated ")
+	} else if !scalar.message {
+		// Scalar fields are optional to distinguish NULLs from zero values.
+		sb.WriteString("optional ")
+	}
+	sb.WriteString(scalar.typeName)
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	status := createEnum(tree.EnumValueList{`open`, `closed`}, tree.MakeUnqualifiedTypeName(`status`))
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (
+		a INT PRIMARY KEY, b STRING, c DECIMAL, d JSONB, e INT[], f status, g TIMESTAMPTZ
+	)`)
+	require.NoError(t, err)
+
+	reg := cdctest.StartTestSchemaRegistry()
+	defer reg.Close()
+
+	opts := changefeedbase.EncodingOptions{
+		Format:            changefeedbase.OptFormatProtobuf,
+		Envelope:          changefeedbase.OptEnvelopeWrapped,
+		Diff:              true,
+		UpdatedTimestamps: true,
+		SchemaRegistryURI: reg.URL(),
+	}
+	require.NoError(t, opts.Validate())
+	e, err := getEncoder(ctx, opts, mkTargets(tableDesc), false, nil, nil,
+		getTestingEnrichedSourceProvider(t, opts))
+	require.NoError(t, err)
+
+	dec, err := tree.ParseDDecimal(`1.50`)
+	require.NoError(t, err)
+	j, err := json.ParseJSON(`{"k": [1, "v", null, true]}`)
+	require.NoError(t, err)
+	arr := tree.NewDArray(types.Int)
+	require.NoError(t, arr.Append(tree.NewDInt(-1)))
+	require.NoError(t, arr.Append(tree.DNull))
+	require.NoError(t, arr.Append(tree.NewDInt(2)))
+	closed, err := tree.MakeDEnumFromLogicalRepresentation(status, `closed`)
+	require.NoError(t, err)
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC)
+	dTS, err := tree.MakeDTimestampTZ(ts, time.Microsecond)
+	require.NoError(t, err)
+
+	row := cdcevent.TestingMakeEventRow(tableDesc, 0, rowenc.EncDatumRow{
+		{Datum: tree.NewDInt(1)}, {Datum: tree.NewDString(`bar`)}, {Datum: dec},
+		{Datum: tree.NewDJSON(j)}, {Datum: arr}, {Datum: tree.NewDEnum(closed)}, {Datum: dTS},
+	}, false)
+	prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, rowenc.EncDatumRow{
+		{Datum: tree.NewDInt(1)}, {Datum: tree.DNull}, {Datum: tree.DNull},
+		{Datum: tree.DNull}, {Datum: tree.DNull}, {Datum: tree.DNull}, {Datum: tree.DNull},
+	}, false)
+
+	key, err := e.EncodeKey(ctx, row)
+	require.NoError(t, err)
+	require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-key`))
+	require.Equal(t, "syntax = \"proto3\";\n\nmessage foo {\n  optional int64 a = 1;\n}\n",
+		reg.SchemaForSubject(`foo-key`))
+	// The key is the magic byte, the schema ID, the message index and a
+	// message holding a=1.
+	require.Len(t, key, 8)
+	require.Equal(t, changefeedbase.ConfluentAvroWireFormatMagic, key[0])
+	require.Equal(t, []byte{0, 0x08, 0x01}, key[5:])
+
+	updated := hlc.Timestamp{WallTime: 1, Logical: 2}
+	value, err := e.EncodeValue(ctx, eventContext{updated: updated}, row, prevRow)
+	require.NoError(t, err)
+	require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-value`))
+	rowSchema := `message %s {
+  message e_element {
+    optional int64 value = 1;
+  }
+  optional int64 a = 1;
+  optional string b = 2;
+  optional string c = 3;
+  .google.protobuf.Value d = 4;
+  repeated e_element e = 5;
+  optional string f = 6;
+  .google.protobuf.Timestamp g = 7;
+}
+`
+	require.Equal(t, `syntax = "proto3";
+
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+
+message foo_envelope {
+  .foo after = 1;
+  .foo_before before = 2;
+  optional string updated = 3;
+}
+
+`+fmt.Sprintf(rowSchema, `foo`)+"\n"+fmt.Sprintf(rowSchema, `foo_before`),
+		reg.SchemaForSubject(`foo-value`))
+
+	require.Equal(t, []byte{0}, value[5:6])
+	envelope := consumeProtobufTestFields(t, value[6:])
+	require.Equal(t, []interface{}{[]byte(`1.0000000002`)}, envelope[protobufFieldUpdated])
+
+	// NULL columns are omitted.
+	before := consumeProtobufTestFields(t, envelope[protobufFieldBefore][0].([]byte))
+	require.Equal(t, map[protowire.Number][]interface{}{1: {uint64(1)}}, before)
+
+	after := consumeProtobufTestFields(t, envelope[protobufFieldAfter][0].([]byte))
+	require.Equal(t, []interface{}{uint64(1)}, after[1])
+	require.Equal(t, []interface{}{[]byte(`bar`)}, after[2])
+	require.Equal(t, []interface{}{[]byte(`1.50`)}, after[3])
+
+	var jsonValue structpb.Value
+	require.NoError(t, proto.Unmarshal(after[4][0].([]byte), &jsonValue))
+	expectedJSON, err := structpb.NewValue(map[string]interface{}{`k`: []interface{}{1, `v`, nil, true}})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(expectedJSON, &jsonValue), "%v", &jsonValue)
+
+	// Each array element is wrapped in a message, which has no value for NULL
+	// elements.
+	var elems []interface{}
+	for _, elem := range after[5] {
+		fields := consumeProtobufTestFields(t, elem.([]byte))
+		if v, ok := fields[protobufFieldElementValue]; ok {
+			elems = append(elems, int64(v[0].(uint64)))
+		} else {
+			elems = append(elems, nil)
+		}
+	}
+	require.Equal(t, []interface{}{int64(-1), nil, int64(2)}, elems)
+
+	// Enums are encoded as their labels.
+	require.Equal(t, []interface{}{[]byte(`closed`)}, after[6])
+
+	var tsValue timestamppb.Timestamp
+	require.NoError(t, proto.Unmarshal(after[7][0].([]byte), &tsValue))
+	require.Equal(t, ts, tsValue.AsTime())
+
+	resolved, err := e.EncodeResolvedTimestamp(ctx, `foo`, updated)
+	require.NoError(t, err)
+	require.Contains(t, reg.SchemaForSubject(`foo-value`),
+		"message foo_resolved {\n  optional string resolved = 1;\n}\n")
+	require.Equal(t, map[protowire.Number][]interface{}{1: {[]byte(`1.0000000002`)}},
+		consumeProtobufTestFields(t, resolved[6:]))
+}
+
+// consumeProtobufTestFields decodes the fields of a message by number. Varint
+// and fixed width values are returned as uint64s and length delimited values
+// as byte slices.
+func consumeProtobufTestFields(t *testing.T, b []byte) map[protowire.Number][]interface{} {
+	fields := make(map[protowire.Number][]interface{})
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.NoError(t, protowire.ParseError(n))
+		b = b[n:]
+		var v interface{}
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var f uint32
+			f, n = protowire.ConsumeFixed32(b)
+			v = uint64(f)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+		require.NoError(t, protowire.ParseError(n))
+		fields[num] = append(fields[num], v)
+		b = b[n:]
+	}
+	return fields
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/schemafeed"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -43,6 +45,11 @@ type eventContext struct {
 	updated, mvcc hlc.Timestamp
 	// topic is set to the string to be included if TopicInValue is true
 	topic string
+	// txnID and txnWriteOrdinal identify the transaction that wrote the row
+	// and the position of the write within it. They are only populated when
+	// the transaction_boundaries option is set.
+	txnID           uuid.UUID
+	txnWriteOrdinal int32
 }
 
 type eventConsumer interface {
@@ -53,6 +60,18 @@ type eventConsumer interface {
 
 type frontier interface{ Frontier() hlc.Timestamp }
 
+// txnGroupingConsumer is implemented by event consumers that may hold rows
+// back until the transaction that wrote them is resolved.
+type txnGroupingConsumer interface {
+	eventConsumer
+	// emitResolvedTxns emits the rows of the buffered transactions whose
+	// timestamp is at or below the frontier.
+	emitResolvedTxns(ctx context.Context) error
+	// oldestUnresolvedTxn returns the timestamp of the oldest transaction
+	// whose rows are buffered, or an empty timestamp if there is none.
+	oldestUnresolvedTxn() hlc.Timestamp
+}
+
 type kvEventToRowConsumer struct {
 	frontier
 	encoder      Encoder
@@ -65,12 +84,26 @@ type kvEventToRowConsumer struct {
 	evaluator    *cdceval.Evaluator
 	encodingOpts changefeedbase.EncodingOptions
 
+	// txnGroups is set if the transaction_boundaries, exactly_once or
+	// ddl_events options are set. It holds the rows of transactions that are
+	// not yet resolved.
+	txnGroups *txnGroupBuffer
+
+	// ddlEvents is set if the ddl_events option is set. The schema change
+	// events it returns are emitted in timestamp order with the rows of the
+	// resolved transactions.
+	ddlEvents *ddlEventEmitter
+
 	topicDescriptorCache map[TopicIdentifier]TopicDescriptor
 	topicNamer           *TopicNamer
 
 	metrics *sliMetrics
 	sv      *settings.Values
 
+	// dlq, if set, receives the rows that cannot be encoded instead of failing
+	// the changefeed.
+	dlq *deadLetterQueue
+
 	// This pacer is used to incorporate event consumption to elastic CPU
 	// control. This helps ensure that event encoding/decoding does not throttle
 	// foreground SQL traffic.
@@ -93,6 +126,8 @@ func newEventConsumer(
 	sink EventSink,
 	metrics *Metrics,
 	sliMetrics *sliMetrics,
+	dlq *deadLetterQueue,
+	ddlEvents schemafeed.DDLEventSource,
 	knobs TestingKnobs,
 ) (eventConsumer, EventSink, error) {
 	encodingOpts, err := feed.Opts.GetEncodingOptions()
@@ -159,7 +194,7 @@ func newEventConsumer(
 
 		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
 		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s,
-			encoder, feed, spec, knobs, topicNamer, sliMetrics, pacer)
+			encoder, feed, spec, knobs, topicNamer, sliMetrics, dlq, ddlEvents, pacer)
 	}
 
 	numWorkers := changefeedbase.EventConsumerWorkers.Get(&cfg.Settings.SV)
@@ -177,8 +212,13 @@ func newEventConsumer(
 	// does not work for parquet format.
 	//
 	// TODO (jayshrivastava) enable parallel consumers for sinkless changefeeds.
+	//
+	// Grouping rows by transaction requires all of the aggregator's rows to go
+	// through a single consumer.
 	isSinkless := spec.JobID == 0
-	if numWorkers <= 1 || isSinkless || encodingOpts.Format == changefeedbase.OptFormatParquet {
+	if numWorkers <= 1 || isSinkless || encodingOpts.Format == changefeedbase.OptFormatParquet ||
+		encodingOpts.TransactionBoundaries != `` || feed.Opts.IsSet(changefeedbase.OptExactlyOnce) ||
+		encodingOpts.DDLEvents {
 		c, err := makeConsumer(sink, spanFrontier)
 		if err != nil {
 			return nil, nil, err
@@ -239,6 +279,8 @@ func newKVEventToRowConsumer(
 	knobs TestingKnobs,
 	topicNamer *TopicNamer,
 	metrics *sliMetrics,
+	dlq *deadLetterQueue,
+	ddlEvents schemafeed.DDLEventSource,
 	pacer *admission.Pacer,
 ) (_ *kvEventToRowConsumer, err error) {
 	includeVirtual := details.Opts.IncludeVirtual()
@@ -250,7 +292,11 @@ func newKVEventToRowConsumer(
 
 	var evaluator *cdceval.Evaluator
 	if spec.Select.Expr != "" {
-		evaluator, err = newEvaluator(ctx, cfg, spec, details.Opts.GetFilters().WithDiff)
+		lookupTS, err := details.Opts.GetLookupJoinTimestamp()
+		if err != nil {
+			return nil, err
+		}
+		evaluator, err = newEvaluator(ctx, cfg, spec, details.Opts.GetFilters().WithDiff, lookupTS)
 		if err != nil {
 			return nil, err
 		}
@@ -261,6 +307,25 @@ func newKVEventToRowConsumer(
 		return nil, err
 	}
 
+	// Exactly once delivery also holds rows back until they are resolved, so
+	// that the messages committed to the sink never go past the resolved spans
+	// committed along with them. Schema change events are ordered with the rows
+	// the same way.
+	var txnGroups *txnGroupBuffer
+	if encodingOpts.TransactionBoundaries != `` || details.Opts.IsSet(changefeedbase.OptExactlyOnce) ||
+		encodingOpts.DDLEvents {
+		txnGroups = newTxnGroupBuffer(encodingOpts.TransactionBoundaries, encodingOpts.Envelope,
+			changefeedbase.TransactionBoundariesMaxBufferedBytes.Get(cfg.SV()))
+	}
+
+	var ddlEmitter *ddlEventEmitter
+	if ddlEvents != nil {
+		ddlEmitter, err = newDDLEventEmitter(ddlEvents, details.Targets, cfg.Codec, spec, encoder)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &kvEventToRowConsumer{
 		frontier:             frontier,
 		encoder:              encoder,
@@ -273,7 +338,10 @@ func newKVEventToRowConsumer(
 		topicNamer:           topicNamer,
 		evaluator:            evaluator,
 		encodingOpts:         encodingOpts,
+		txnGroups:            txnGroups,
+		ddlEvents:            ddlEmitter,
 		metrics:              metrics,
+		dlq:                  dlq,
 		pacer:                pacer,
 		sv:                   cfg.SV(),
 	}, nil
@@ -284,6 +352,7 @@ func newEvaluator(
 	cfg *sql.ExecutorConfig,
 	spec execinfrapb.ChangeAggregatorSpec,
 	withDiff bool,
+	lookupTS changefeedbase.LookupJoinTimestamp,
 ) (*cdceval.Evaluator, error) {
 	sc, err := cdceval.ParseChangefeedExpression(spec.Select.Expr)
 	if err != nil {
@@ -311,7 +380,8 @@ func newEvaluator(
 		sd.SessionData = *spec.Feed.SessionData
 	}
 
-	return cdceval.NewEvaluator(sc, cfg, spec.User(), sd, spec.Feed.StatementTime, withDiff), nil
+	return cdceval.NewEvaluator(sc, cfg, spec.User(), sd, spec.Feed.StatementTime, withDiff,
+		lookupTS), nil
 }
 
 func (c *kvEventToRowConsumer) topicForEvent(eventMeta cdcevent.Metadata) (TopicDescriptor, error) {
@@ -353,6 +423,10 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	if backfillTs := ev.BackfillTimestamp(); !backfillTs.IsEmpty() {
 		schemaTimestamp = backfillTs
 		prevSchemaTimestamp = schemaTimestamp.Prev()
+		// The schema change which caused the backfill precedes its rows.
+		if err := c.emitDDLEvents(ctx, backfillTs); err != nil {
+			return err
+		}
 	}
 
 	updatedRow, err := c.decoder.DecodeKV(ctx, ev.KV(), cdcevent.CurrentRow, schemaTimestamp, keyOnly)
@@ -396,14 +470,24 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		}
 	}
 
-	return c.encodeAndEmit(ctx, updatedRow, prevRow, schemaTimestamp, ev.DetachAlloc())
+	// Rows found by backfills are not grouped by transaction.
+	var group *txnGroupKey
+	if c.txnGroups != nil && ev.BackfillTimestamp().IsEmpty() {
+		group = &txnGroupKey{txnID: ev.TxnID(), ts: ev.KV().Value.Timestamp}
+	}
+
+	return c.encodeAndEmit(ctx, updatedRow, prevRow, schemaTimestamp, group, ev.TxnWriteOrdinal(), ev.DetachAlloc())
 }
 
+// encodeAndEmit encodes a row and emits it to the sink. If group is set, the
+// row is buffered until the transaction that wrote it is resolved instead.
 func (c *kvEventToRowConsumer) encodeAndEmit(
 	ctx context.Context,
 	updatedRow cdcevent.Row,
 	prevRow cdcevent.Row,
 	schemaTS hlc.Timestamp,
+	group *txnGroupKey,
+	txnWriteOrdinal int32,
 	alloc kvevent.Alloc,
 ) error {
 	topic, err := c.topicForEvent(updatedRow.Metadata)
@@ -426,6 +510,15 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 		updated: schemaTS,
 		mvcc:    updatedRow.MvccTimestamp,
 	}
+	if group != nil {
+		evCtx.txnID = group.txnID
+		evCtx.txnWriteOrdinal = txnWriteOrdinal
+		if evCtx.txnWriteOrdinal == 0 {
+			// Rows without a transaction ID are numbered in the order they
+			// were received.
+			evCtx.txnWriteOrdinal = c.txnGroups.nextOrdinal(*group)
+		}
+	}
 
 	if c.topicNamer != nil {
 		topic, err := c.topicNamer.Name(topic)
@@ -451,14 +544,14 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
 	if err != nil {
-		return err
+		return c.maybeWriteToDLQ(ctx, err, topic, updatedRow, nil, nil, schemaTS, alloc)
 	}
 	c.scratch, keyCopy = c.scratch.Copy(encodedKey, 0 /* extraCap */)
 	// TODO(yevgeniy): Some refactoring is needed in the encoder: namely, prevRow
 	// might not be available at all when working with changefeed expressions.
 	encodedValue, err := c.encoder.EncodeValue(ctx, evCtx, updatedRow, prevRow)
 	if err != nil {
-		return err
+		return c.maybeWriteToDLQ(ctx, err, topic, updatedRow, keyCopy, nil, schemaTS, alloc)
 	}
 	c.scratch, valueCopy = c.scratch.Copy(encodedValue, 0 /* extraCap */)
 
@@ -469,13 +562,76 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 
 	headers, err := c.makeRowHeaders(ctx, updatedRow)
 	if err != nil {
+		return c.maybeWriteToDLQ(ctx, err, topic, updatedRow, keyCopy, valueCopy, schemaTS, alloc)
+	}
+
+	if group != nil {
+		// The key and value were copied into the scratch allocator, which does
+		// not reuse its memory. Release the event's memory while the row waits
+		// for its transaction; the buffered rows are bounded separately.
+		alloc.Release(ctx)
+		return c.txnGroups.add(*group, bufferedRow{
+			topic:   topic,
+			key:     keyCopy,
+			value:   valueCopy,
+			updated: schemaTS,
+			mvcc:    updatedRow.MvccTimestamp,
+			headers: headers,
+		})
+	}
+
+	if err := c.emitToSink(
+		ctx, topic, keyCopy, valueCopy, schemaTS, updatedRow.MvccTimestamp, alloc, headers,
+	); err != nil {
+		return err
+	}
+	if log.V(3) {
+		log.Infof(ctx, `r %s: %s(%+v) -> %s`, updatedRow.TableName, keyCopy, headers, valueCopy)
+	}
+	return nil
+}
+
+// maybeWriteToDLQ writes a row that could not be encoded to the dead letter
+// queue and releases its allocation. It returns the original error if the
+// changefeed does not have a dead letter queue or the error is not specific to
+// the row.
+func (c *kvEventToRowConsumer) maybeWriteToDLQ(
+	ctx context.Context,
+	err error,
+	topic TopicDescriptor,
+	row cdcevent.Row,
+	key, value []byte,
+	schemaTS hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if c.dlq == nil || !isDLQEligible(ctx, err) {
+		return err
+	}
+	if err := c.dlq.write(ctx, dlqRow{
+		topic:   topic,
+		key:     key,
+		value:   value,
+		row:     row,
+		updated: schemaTS,
+		mvcc:    row.MvccTimestamp,
+		reason:  err,
+	}); err != nil {
 		return err
 	}
+	alloc.Release(ctx)
+	return nil
+}
 
+func (c *kvEventToRowConsumer) emitToSink(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+	headers rowHeaders,
+) (err error) {
 	c.metrics.Timers.EmitRow.Time(func() {
-		err = c.sink.EmitRow(
-			ctx, topic, keyCopy, valueCopy, schemaTS, updatedRow.MvccTimestamp, alloc, headers,
-		)
+		err = c.sink.EmitRow(ctx, topic, key, value, updated, mvcc, alloc, headers)
 	})
 	if err != nil {
 		if !errors.Is(err, context.Canceled) {
@@ -484,9 +640,6 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 		}
 		return err
 	}
-	if log.V(3) {
-		log.Infof(ctx, `r %s: %s(%+v) -> %s`, updatedRow.TableName, keyCopy, headers, valueCopy)
-	}
 	return nil
 }
 
@@ -574,11 +727,77 @@ func (c *kvEventToRowConsumer) encodeForParquet(
 	return nil
 }
 
-// Flush is a noop for the kvEventToRowConsumer because it does not buffer any events.
+// Flush emits the rows of the resolved transactions if rows are grouped by
+// transaction. Otherwise, it is a noop because the kvEventToRowConsumer does
+// not buffer any events.
 func (c *kvEventToRowConsumer) Flush(ctx context.Context) error {
+	return c.emitResolvedTxns(ctx)
+}
+
+var _ txnGroupingConsumer = (*kvEventToRowConsumer)(nil)
+
+// emitResolvedTxns implements the txnGroupingConsumer interface.
+func (c *kvEventToRowConsumer) emitResolvedTxns(ctx context.Context) error {
+	if c.txnGroups == nil {
+		return nil
+	}
+	frontier := c.frontier.Frontier()
+	var ddl []bufferedRow
+	if c.ddlEvents != nil {
+		var err error
+		if ddl, err = c.ddlEvents.take(ctx, frontier); err != nil {
+			return err
+		}
+	}
+	emitBuffered := func(ctx context.Context, r bufferedRow) error {
+		return c.emitToSink(ctx, r.topic, r.key, r.value, r.updated, r.mvcc, kvevent.Alloc{}, r.headers)
+	}
+	for _, g := range c.txnGroups.takeResolved(frontier) {
+		// A schema change precedes the transactions at its timestamp, which
+		// were written using the new schema.
+		for ; len(ddl) > 0 && ddl[0].updated.LessEq(g.ts); ddl = ddl[1:] {
+			if err := emitBuffered(ctx, ddl[0]); err != nil {
+				return err
+			}
+		}
+		if err := c.txnGroups.emit(ctx, g, emitBuffered); err != nil {
+			return err
+		}
+	}
+	for _, r := range ddl {
+		if err := emitBuffered(ctx, r); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// emitDDLEvents emits the schema change events at or before the given
+// timestamp.
+func (c *kvEventToRowConsumer) emitDDLEvents(ctx context.Context, atOrBefore hlc.Timestamp) error {
+	if c.ddlEvents == nil {
+		return nil
+	}
+	ddl, err := c.ddlEvents.take(ctx, atOrBefore)
+	if err != nil {
+		return err
+	}
+	for _, r := range ddl {
+		if err := c.emitToSink(ctx, r.topic, r.key, r.value, r.updated, r.mvcc, kvevent.Alloc{}, r.headers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// oldestUnresolvedTxn implements the txnGroupingConsumer interface.
+func (c *kvEventToRowConsumer) oldestUnresolvedTxn() hlc.Timestamp {
+	if c.txnGroups == nil {
+		return hlc.Timestamp{}
+	}
+	return c.txnGroups.oldest()
+}
+
 type parallelEventConsumer struct {
 	// g is a group used to manage worker goroutines.
 	g ctxgroup.Group
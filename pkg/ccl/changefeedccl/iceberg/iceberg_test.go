@@ -0,0 +1,158 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package iceberg
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldType(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		typ      *types.T
+		expected string
+	}{
+		{typ: types.Bool, expected: `"boolean"`},
+		{typ: types.Int, expected: `"long"`},
+		{typ: types.Int4, expected: `"int"`},
+		{typ: types.Float4, expected: `"float"`},
+		{typ: types.Float, expected: `"double"`},
+		{typ: types.Uuid, expected: `"uuid"`},
+		{typ: types.Time, expected: `"time"`},
+		{typ: types.String, expected: `"string"`},
+		{typ: types.Decimal, expected: `"string"`},
+		{typ: types.TimestampTZ, expected: `"string"`},
+		{typ: types.Bytes, expected: `"binary"`},
+		{
+			typ:      types.MakeArray(types.Int),
+			expected: `{"type":"list","element-id":1048579,"element":"long","element-required":false}`,
+		},
+	} {
+		typ, err := FieldType(3, tc.typ)
+		require.NoError(t, err, tc.typ.SQLString())
+		require.Equal(t, tc.expected, string(typ), tc.typ.SQLString())
+	}
+
+	_, err := FieldType(3, types.MakeTuple([]*types.T{types.Int}))
+	require.ErrorContains(t, err, "iceberg tables do not support columns of type")
+}
+
+func TestTableCommit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	es := nodelocal.TestingMakeNodelocalStorage(
+		t.TempDir(), cluster.MakeTestingClusterSettings(), cloudpb.ExternalStorage{})
+	const location = "nodelocal://1/feed/t"
+
+	field := func(id int32, name string, typ *types.T, required bool) Field {
+		ft, err := FieldType(id, typ)
+		require.NoError(t, err)
+		return Field{ID: id, Name: name, Required: required, Type: ft}
+	}
+
+	tbl, err := LoadTable(ctx, es, "t", location)
+	require.NoError(t, err)
+	require.Nil(t, tbl.CurrentSnapshot())
+
+	s1, err := tbl.AddSchema([]Field{
+		field(1, "id", types.Int, true),
+		field(2, "v", types.MakeArray(types.String), false),
+	}, []int32{1})
+	require.NoError(t, err)
+	_, err = tbl.AddSnapshot(ctx, s1, []DataFile{
+		{Path: "data/a.parquet", Content: ContentData, RecordCount: 3, FileSizeInBytes: 100},
+		{Path: "data/a-deletes.parquet", Content: ContentEqualityDeletes, RecordCount: 4,
+			FileSizeInBytes: 50, EqualityIDs: []int32{1}},
+	}, map[string]string{"crdb.resolved": "1"})
+	require.NoError(t, err)
+	require.NoError(t, tbl.Commit(ctx))
+
+	// Rename v to w and add a snapshot with the new schema.
+	s2, err := tbl.AddSchema([]Field{
+		field(1, "id", types.Int, true),
+		field(2, "w", types.MakeArray(types.String), false),
+	}, []int32{1})
+	require.NoError(t, err)
+	require.NotEqual(t, s1, s2)
+	require.NoError(t, tbl.SetCurrentSchema(s2))
+	_, err = tbl.AddSnapshot(ctx, s2, []DataFile{
+		{Path: "data/b.parquet", Content: ContentData, RecordCount: 1, FileSizeInBytes: 10},
+	}, map[string]string{"crdb.resolved": "2"})
+	require.NoError(t, err)
+	require.NoError(t, tbl.Commit(ctx))
+
+	// Adding an existing schema returns its ID.
+	again, err := tbl.AddSchema([]Field{
+		field(1, "id", types.Int, true),
+		field(2, "v", types.MakeArray(types.String), false),
+	}, []int32{1})
+	require.NoError(t, err)
+	require.Equal(t, s1, again)
+
+	reloaded, err := LoadTable(ctx, es, "t", location)
+	require.NoError(t, err)
+	require.Equal(t, 2, reloaded.version)
+	snap := reloaded.CurrentSnapshot()
+	require.NotNil(t, snap)
+	require.Equal(t, int64(2), snap.SequenceNumber)
+	require.Equal(t, "2", snap.Summary["crdb.resolved"])
+	require.Equal(t, "append", snap.Summary["operation"])
+	require.Equal(t, "w", reloaded.CurrentSchema().Fields[1].Name)
+	require.Equal(t, int32(2+listElementIDOffset), reloaded.meta.LastColumnID)
+	require.Len(t, reloaded.meta.Snapshots, 2)
+	require.Equal(t, "overwrite", reloaded.meta.Snapshots[0].Summary["operation"])
+	require.Equal(t, reloaded.meta.Snapshots[0].SnapshotID, *snap.ParentSnapshotID)
+	require.Len(t, reloaded.meta.MetadataLog, 1)
+
+	// The manifest list of the current snapshot carries the manifests of the
+	// previous one forward.
+	require.Len(t, reloaded.manifests, 3)
+	require.Equal(t, manifestContentData, reloaded.manifests[0].content)
+	require.Equal(t, manifestContentDeletes, reloaded.manifests[1].content)
+	require.Equal(t, int64(4), reloaded.manifests[1].addedRowsCount)
+	require.Equal(t, int64(1), reloaded.manifests[1].sequenceNumber)
+	require.Equal(t, int64(2), reloaded.manifests[2].sequenceNumber)
+	for _, m := range reloaded.manifests {
+		rel, err := reloaded.relative(m.path)
+		require.NoError(t, err)
+		size, err := es.Size(ctx, "t/"+rel)
+		require.NoError(t, err)
+		require.Equal(t, m.length, size)
+	}
+
+	// Both names of the renamed column map to its field ID.
+	var mapping []nameMappingEntry
+	require.NoError(t, json.Unmarshal([]byte(reloaded.meta.Properties[nameMappingProperty]), &mapping))
+	require.Equal(t, []nameMappingEntry{
+		{FieldID: 1, Names: []string{"id"}},
+		{FieldID: 2, Names: []string{"v", "w"}, Fields: []nameMappingEntry{
+			{FieldID: 2 + listElementIDOffset, Names: []string{"element"}},
+		}},
+	}, mapping)
+
+	hint, err := reloaded.readFile(ctx, versionHintFile)
+	require.NoError(t, err)
+	require.Equal(t, "2", string(hint))
+	_, err = LoadTable(ctx, es, "missing", "nodelocal://1/feed/missing")
+	require.NoError(t, err)
+	_, _, err = es.ReadFile(ctx, "missing/"+versionHintFile, cloud.ReadOptions{})
+	require.ErrorIs(t, err, cloud.ErrFileDoesNotExist)
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/errors"
+	"github.com/linkedin/goavro/v2"
+)
+
+// FileContent is the content of a data file, as defined by the Iceberg spec.
+type FileContent int32
+
+const (
+	// ContentData is the content of files holding the rows of a table.
+	ContentData FileContent = 0
+	// ContentEqualityDeletes is the content of files holding the key columns of
+	// rows deleted from a table.
+	ContentEqualityDeletes FileContent = 2
+)
+
+// manifestContent is the content of a manifest: data files or delete files.
+type manifestContent int32
+
+const (
+	manifestContentData    manifestContent = 0
+	manifestContentDeletes manifestContent = 1
+)
+
+func (c manifestContent) String() string {
+	if c == manifestContentDeletes {
+		return "deletes"
+	}
+	return "data"
+}
+
+// manifestEntryStatusAdded is the status of a manifest entry of a file added
+// by the snapshot which wrote the manifest.
+const manifestEntryStatusAdded = 1
+
+// DataFile is a Parquet file added to a table by a snapshot.
+type DataFile struct {
+	// Path is the path of the file relative to the location of the table.
+	Path            string
+	Content         FileContent
+	RecordCount     int64
+	FileSizeInBytes int64
+	// EqualityIDs are the field IDs of the columns of an equality delete file.
+	EqualityIDs []int32
+}
+
+// manifestFile is an entry of a manifest list.
+type manifestFile struct {
+	path              string
+	length            int64
+	content           manifestContent
+	sequenceNumber    int64
+	minSequenceNumber int64
+	addedSnapshotID   int64
+	addedFilesCount   int32
+	addedRowsCount    int64
+}
+
+// The Avro schemas below are the subset of the manifest and manifest list
+// schemas of version 2 of the Iceberg spec needed to describe unpartitioned
+// tables. The field IDs are the ones assigned by the spec.
+const manifestEntrySchema = `{
+  "type": "record",
+  "name": "manifest_entry",
+  "fields": [
+    {"name": "status", "type": "int", "field-id": 0},
+    {"name": "snapshot_id", "type": ["null", "long"], "default": null, "field-id": 1},
+    {"name": "sequence_number", "type": ["null", "long"], "default": null, "field-id": 3},
+    {"name": "file_sequence_number", "type": ["null", "long"], "default": null, "field-id": 4},
+    {"name": "data_file", "field-id": 2, "type": {
+      "type": "record",
+      "name": "r2",
+      "fields": [
+        {"name": "content", "type": "int", "field-id": 134},
+        {"name": "file_path", "type": "string", "field-id": 100},
+        {"name": "file_format", "type": "string", "field-id": 101},
+        {"name": "partition", "field-id": 102, "type": {"type": "record", "name": "r102", "fields": []}},
+        {"name": "record_count", "type": "long", "field-id": 103},
+        {"name": "file_size_in_bytes", "type": "long", "field-id": 104},
+        {"name": "equality_ids", "default": null, "field-id": 135,
+         "type": ["null", {"type": "array", "items": "int", "element-id": 136}]}
+      ]
+    }}
+  ]
+}`
+
+const manifestFileSchema = `{
+  "type": "record",
+  "name": "manifest_file",
+  "fields": [
+    {"name": "manifest_path", "type": "string", "field-id": 500},
+    {"name": "manifest_length", "type": "long", "field-id": 501},
+    {"name": "partition_spec_id", "type": "int", "field-id": 502},
+    {"name": "content", "type": "int", "field-id": 517},
+    {"name": "sequence_number", "type": "long", "field-id": 515},
+    {"name": "min_sequence_number", "type": "long", "field-id": 516},
+    {"name": "added_snapshot_id", "type": "long", "field-id": 503},
+    {"name": "added_files_count", "type": "int", "field-id": 504},
+    {"name": "existing_files_count", "type": "int", "field-id": 505},
+    {"name": "deleted_files_count", "type": "int", "field-id": 506},
+    {"name": "added_rows_count", "type": "long", "field-id": 512},
+    {"name": "existing_rows_count", "type": "long", "field-id": 513},
+    {"name": "deleted_rows_count", "type": "long", "field-id": 514}
+  ]
+}`
+
+// writeManifest writes a manifest of the files of the given content added by
+// a snapshot and returns its manifest list entry.
+func (t *Table) writeManifest(
+	ctx context.Context,
+	schema Schema,
+	content manifestContent,
+	snapshotID, sequenceNumber int64,
+	files []DataFile,
+) (manifestFile, error) {
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return manifestFile{}, err
+	}
+	var buf bytes.Buffer
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:      &buf,
+		Schema: manifestEntrySchema,
+		MetaData: map[string][]byte{
+			"schema":            schemaJSON,
+			"schema-id":         []byte(strconv.Itoa(schema.SchemaID)),
+			"partition-spec":    []byte("[]"),
+			"partition-spec-id": []byte("0"),
+			"format-version":    []byte("2"),
+			"content":           []byte(content.String()),
+		},
+	})
+	if err != nil {
+		return manifestFile{}, err
+	}
+	res := manifestFile{
+		content:           content,
+		sequenceNumber:    sequenceNumber,
+		minSequenceNumber: sequenceNumber,
+		addedSnapshotID:   snapshotID,
+		addedFilesCount:   int32(len(files)),
+	}
+	entries := make([]interface{}, 0, len(files))
+	for _, f := range files {
+		var equalityIDs interface{}
+		if len(f.EqualityIDs) > 0 {
+			ids := make([]interface{}, len(f.EqualityIDs))
+			for i, id := range f.EqualityIDs {
+				ids[i] = id
+			}
+			equalityIDs = goavro.Union("array", ids)
+		}
+		entries = append(entries, map[string]interface{}{
+			"status":      int32(manifestEntryStatusAdded),
+			"snapshot_id": goavro.Union("long", snapshotID),
+			// The sequence numbers of added files are inherited from the
+			// snapshot.
+			"sequence_number":      nil,
+			"file_sequence_number": nil,
+			"data_file": map[string]interface{}{
+				"content":            int32(f.Content),
+				"file_path":          t.absolute(f.Path),
+				"file_format":        "PARQUET",
+				"partition":          map[string]interface{}{},
+				"record_count":       f.RecordCount,
+				"file_size_in_bytes": f.FileSizeInBytes,
+				"equality_ids":       equalityIDs,
+			},
+		})
+		res.addedRowsCount += f.RecordCount
+	}
+	if err := ocf.Append(entries); err != nil {
+		return manifestFile{}, err
+	}
+	path := "metadata/" + newFileUUID() + "-m0.avro"
+	res.path = t.absolute(path)
+	res.length = int64(buf.Len())
+	if err := cloud.WriteFile(ctx, t.es, t.join(path), &buf); err != nil {
+		return manifestFile{}, err
+	}
+	return res, nil
+}
+
+// writeManifestList writes the manifest list of a snapshot and returns its
+// absolute path.
+func (t *Table) writeManifestList(
+	ctx context.Context, snapshot Snapshot, manifests []manifestFile,
+) (string, error) {
+	parentID := "null"
+	if snapshot.ParentSnapshotID != nil {
+		parentID = strconv.FormatInt(*snapshot.ParentSnapshotID, 10)
+	}
+	var buf bytes.Buffer
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:      &buf,
+		Schema: manifestFileSchema,
+		MetaData: map[string][]byte{
+			"snapshot-id":        []byte(strconv.FormatInt(snapshot.SnapshotID, 10)),
+			"parent-snapshot-id": []byte(parentID),
+			"sequence-number":    []byte(strconv.FormatInt(snapshot.SequenceNumber, 10)),
+			"format-version":     []byte("2"),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	entries := make([]interface{}, 0, len(manifests))
+	for _, m := range manifests {
+		entries = append(entries, map[string]interface{}{
+			"manifest_path":        m.path,
+			"manifest_length":      m.length,
+			"partition_spec_id":    int32(0),
+			"content":              int32(m.content),
+			"sequence_number":      m.sequenceNumber,
+			"min_sequence_number":  m.minSequenceNumber,
+			"added_snapshot_id":    m.addedSnapshotID,
+			"added_files_count":    m.addedFilesCount,
+			"existing_files_count": int32(0),
+			"deleted_files_count":  int32(0),
+			"added_rows_count":     m.addedRowsCount,
+			"existing_rows_count":  int64(0),
+			"deleted_rows_count":   int64(0),
+		})
+	}
+	if err := ocf.Append(entries); err != nil {
+		return "", err
+	}
+	path := "metadata/snap-" + strconv.FormatInt(snapshot.SnapshotID, 10) + "-1-" + newFileUUID() + ".avro"
+	if err := cloud.WriteFile(ctx, t.es, t.join(path), &buf); err != nil {
+		return "", err
+	}
+	return t.absolute(path), nil
+}
+
+// readManifestList reads the manifest list with the given absolute path.
+func (t *Table) readManifestList(ctx context.Context, path string) ([]manifestFile, error) {
+	rel, err := t.relative(path)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := t.es.ReadFile(ctx, t.join(rel), cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close(ctx)
+	data, err := ioctx.ReadAll(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	ocf, err := goavro.NewOCFReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var res []manifestFile
+	for ocf.Scan() {
+		v, err := ocf.Read()
+		if err != nil {
+			return nil, err
+		}
+		rec, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.AssertionFailedf("unexpected manifest list entry %T", v)
+		}
+		m := manifestFile{}
+		m.path, _ = rec["manifest_path"].(string)
+		m.length, _ = rec["manifest_length"].(int64)
+		content, _ := rec["content"].(int32)
+		m.content = manifestContent(content)
+		m.sequenceNumber, _ = rec["sequence_number"].(int64)
+		m.minSequenceNumber, _ = rec["min_sequence_number"].(int64)
+		m.addedSnapshotID, _ = rec["added_snapshot_id"].(int64)
+		m.addedFilesCount, _ = rec["added_files_count"].(int32)
+		m.addedRowsCount, _ = rec["added_rows_count"].(int64)
+		res = append(res, m)
+	}
+	return res, ocf.Err()
+}
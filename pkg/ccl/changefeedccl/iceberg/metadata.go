@@ -0,0 +1,513 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+// Package iceberg writes the metadata of Apache Iceberg tables stored in an
+// ExternalStorage.
+//
+// A Table is laid out like the tables of Iceberg's Hadoop catalog: the
+// metadata files live in the metadata/ directory of the table and the version
+// of the current one is recorded in metadata/version-hint.text. Replacing the
+// version hint commits all the snapshots added since the previous commit
+// atomically. A Table must therefore have a single writer.
+//
+// Only unpartitioned, unsorted tables of version 2 of the spec are supported.
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	formatVersion   = 2
+	versionHintFile = "metadata/version-hint.text"
+	// lastPartitionID is the last-partition-id of unpartitioned tables.
+	// Partition field IDs start at 1000.
+	lastPartitionID = 999
+	// nameMappingProperty is the table property holding the mapping from
+	// column names to field IDs used to read data files without field IDs.
+	nameMappingProperty = "schema.name-mapping.default"
+)
+
+// Field is a top level field of an Iceberg schema.
+type Field struct {
+	ID       int32           `json:"id"`
+	Name     string          `json:"name"`
+	Required bool            `json:"required"`
+	Type     json.RawMessage `json:"type"`
+}
+
+// Schema is an Iceberg schema.
+type Schema struct {
+	SchemaID           int     `json:"schema-id"`
+	Type               string  `json:"type"`
+	Fields             []Field `json:"fields"`
+	IdentifierFieldIDs []int32 `json:"identifier-field-ids,omitempty"`
+}
+
+// Snapshot is an Iceberg snapshot.
+type Snapshot struct {
+	SnapshotID       int64             `json:"snapshot-id"`
+	ParentSnapshotID *int64            `json:"parent-snapshot-id,omitempty"`
+	SequenceNumber   int64             `json:"sequence-number"`
+	TimestampMs      int64             `json:"timestamp-ms"`
+	ManifestList     string            `json:"manifest-list"`
+	Summary          map[string]string `json:"summary"`
+	SchemaID         int               `json:"schema-id"`
+}
+
+type partitionSpec struct {
+	SpecID int        `json:"spec-id"`
+	Fields []struct{} `json:"fields"`
+}
+
+type sortOrder struct {
+	OrderID int        `json:"order-id"`
+	Fields  []struct{} `json:"fields"`
+}
+
+type snapshotRef struct {
+	SnapshotID int64  `json:"snapshot-id"`
+	Type       string `json:"type"`
+}
+
+type snapshotLogEntry struct {
+	TimestampMs int64 `json:"timestamp-ms"`
+	SnapshotID  int64 `json:"snapshot-id"`
+}
+
+type metadataLogEntry struct {
+	TimestampMs  int64  `json:"timestamp-ms"`
+	MetadataFile string `json:"metadata-file"`
+}
+
+// tableMetadata is the content of a table metadata file.
+type tableMetadata struct {
+	FormatVersion      int                    `json:"format-version"`
+	TableUUID          string                 `json:"table-uuid"`
+	Location           string                 `json:"location"`
+	LastSequenceNumber int64                  `json:"last-sequence-number"`
+	LastUpdatedMs      int64                  `json:"last-updated-ms"`
+	LastColumnID       int32                  `json:"last-column-id"`
+	Schemas            []Schema               `json:"schemas"`
+	CurrentSchemaID    int                    `json:"current-schema-id"`
+	PartitionSpecs     []partitionSpec        `json:"partition-specs"`
+	DefaultSpecID      int                    `json:"default-spec-id"`
+	LastPartitionID    int                    `json:"last-partition-id"`
+	Properties         map[string]string      `json:"properties"`
+	CurrentSnapshotID  *int64                 `json:"current-snapshot-id,omitempty"`
+	Snapshots          []Snapshot             `json:"snapshots"`
+	SnapshotLog        []snapshotLogEntry     `json:"snapshot-log"`
+	MetadataLog        []metadataLogEntry     `json:"metadata-log"`
+	SortOrders         []sortOrder            `json:"sort-orders"`
+	DefaultSortOrderID int                    `json:"default-sort-order-id"`
+	Refs               map[string]snapshotRef `json:"refs"`
+}
+
+// Table is an Iceberg table. Its methods are not safe for concurrent use.
+type Table struct {
+	es cloud.ExternalStorage
+	// dir is the directory of the table within es.
+	dir string
+	// version is the version of the last committed metadata file, or 0 if
+	// the table has never been committed.
+	version int
+	meta    tableMetadata
+	// manifests are the manifests of the current snapshot.
+	manifests []manifestFile
+}
+
+// LoadTable loads the table stored in the given directory of the external
+// storage. If the table does not exist yet, an empty table located at the
+// given location is returned; it is created by its first commit. The location
+// is the URI of the directory, which Iceberg readers use to find its files.
+func LoadTable(
+	ctx context.Context, es cloud.ExternalStorage, dir string, location string,
+) (*Table, error) {
+	t := &Table{es: es, dir: dir}
+	hint, err := t.readFile(ctx, versionHintFile)
+	if errors.Is(err, cloud.ErrFileDoesNotExist) {
+		t.meta = tableMetadata{
+			FormatVersion:   formatVersion,
+			TableUUID:       uuid.MakeV4().String(),
+			Location:        strings.TrimSuffix(location, "/"),
+			PartitionSpecs:  []partitionSpec{{SpecID: 0, Fields: []struct{}{}}},
+			LastPartitionID: lastPartitionID,
+			Properties:      map[string]string{},
+			SortOrders:      []sortOrder{{OrderID: 0, Fields: []struct{}{}}},
+			Refs:            map[string]snapshotRef{},
+		}
+		return t, nil
+	} else if err != nil {
+		return nil, err
+	}
+	t.version, err = strconv.Atoi(strings.TrimSpace(string(hint)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s of iceberg table %s", versionHintFile, dir)
+	}
+	data, err := t.readFile(ctx, metadataFile(t.version))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &t.meta); err != nil {
+		return nil, errors.Wrapf(err, "parsing metadata of iceberg table %s", dir)
+	}
+	if t.meta.FormatVersion != formatVersion {
+		return nil, errors.Errorf("iceberg table %s has unsupported format version %d",
+			dir, t.meta.FormatVersion)
+	}
+	if t.meta.Properties == nil {
+		t.meta.Properties = map[string]string{}
+	}
+	if t.meta.Refs == nil {
+		t.meta.Refs = map[string]snapshotRef{}
+	}
+	if s := t.CurrentSnapshot(); s != nil {
+		if t.manifests, err = t.readManifestList(ctx, s.ManifestList); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Location returns the location of the table.
+func (t *Table) Location() string {
+	return t.meta.Location
+}
+
+// CurrentSnapshot returns the current snapshot of the table, or nil if the
+// table has none.
+func (t *Table) CurrentSnapshot() *Snapshot {
+	if t.meta.CurrentSnapshotID == nil {
+		return nil
+	}
+	for i := range t.meta.Snapshots {
+		if t.meta.Snapshots[i].SnapshotID == *t.meta.CurrentSnapshotID {
+			return &t.meta.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// Snapshots returns the snapshots of the table, in the order they were added.
+func (t *Table) Snapshots() []Snapshot {
+	return t.meta.Snapshots
+}
+
+// CurrentSchema returns the current schema of the table, or nil if the table
+// has none.
+func (t *Table) CurrentSchema() *Schema {
+	for i := range t.meta.Schemas {
+		if t.meta.Schemas[i].SchemaID == t.meta.CurrentSchemaID {
+			return &t.meta.Schemas[i]
+		}
+	}
+	return nil
+}
+
+// AddSchema adds a schema with the given fields to the table and returns its
+// ID. If the table already has such a schema, its ID is returned instead.
+func (t *Table) AddSchema(fields []Field, identifierFieldIDs []int32) (int, error) {
+	s := Schema{Type: "struct", Fields: fields, IdentifierFieldIDs: identifierFieldIDs}
+	nextID := 0
+	for _, existing := range t.meta.Schemas {
+		if existing.equal(s) {
+			return existing.SchemaID, nil
+		}
+		if existing.SchemaID >= nextID {
+			nextID = existing.SchemaID + 1
+		}
+	}
+	for _, f := range fields {
+		id, err := maxFieldID(f.ID, f.Type)
+		if err != nil {
+			return 0, err
+		}
+		if id > t.meta.LastColumnID {
+			t.meta.LastColumnID = id
+		}
+	}
+	s.SchemaID = nextID
+	if len(t.meta.Schemas) == 0 {
+		t.meta.CurrentSchemaID = s.SchemaID
+	}
+	t.meta.Schemas = append(t.meta.Schemas, s)
+	return s.SchemaID, nil
+}
+
+// SetCurrentSchema makes the schema with the given ID the current schema of
+// the table.
+func (t *Table) SetCurrentSchema(schemaID int) error {
+	if _, err := t.schema(schemaID); err != nil {
+		return err
+	}
+	t.meta.CurrentSchemaID = schemaID
+	return nil
+}
+
+// AddSnapshot adds a snapshot adding the given files to the current snapshot
+// of the table, and makes it the current snapshot. The files must have been
+// written with the schema with the given ID. The snapshot is only visible to
+// readers once the table is committed.
+//
+// Equality delete files added by the snapshot apply to the data files added
+// by previous snapshots, not to the data files added along with them.
+func (t *Table) AddSnapshot(
+	ctx context.Context, schemaID int, files []DataFile, summary map[string]string,
+) (Snapshot, error) {
+	schema, err := t.schema(schemaID)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot := Snapshot{
+		SnapshotID:       newSnapshotID(),
+		ParentSnapshotID: t.meta.CurrentSnapshotID,
+		SequenceNumber:   t.meta.LastSequenceNumber + 1,
+		TimestampMs:      timeutil.Now().UnixMilli(),
+		Summary:          map[string]string{"operation": "append"},
+		SchemaID:         schemaID,
+	}
+
+	var data, deletes []DataFile
+	var dataRecords, deleteRecords, size int64
+	for _, f := range files {
+		size += f.FileSizeInBytes
+		if f.Content == ContentEqualityDeletes {
+			deletes = append(deletes, f)
+			deleteRecords += f.RecordCount
+		} else {
+			data = append(data, f)
+			dataRecords += f.RecordCount
+		}
+	}
+	manifests := append([]manifestFile(nil), t.manifests...)
+	for _, m := range []struct {
+		content manifestContent
+		files   []DataFile
+	}{{manifestContentData, data}, {manifestContentDeletes, deletes}} {
+		if len(m.files) == 0 {
+			continue
+		}
+		mf, err := t.writeManifest(ctx, *schema, m.content, snapshot.SnapshotID, snapshot.SequenceNumber, m.files)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		manifests = append(manifests, mf)
+	}
+	if snapshot.ManifestList, err = t.writeManifestList(ctx, snapshot, manifests); err != nil {
+		return Snapshot{}, err
+	}
+
+	if len(deletes) > 0 {
+		snapshot.Summary["operation"] = "overwrite"
+	}
+	snapshot.Summary["added-data-files"] = strconv.Itoa(len(data))
+	snapshot.Summary["added-records"] = strconv.FormatInt(dataRecords, 10)
+	snapshot.Summary["added-delete-files"] = strconv.Itoa(len(deletes))
+	snapshot.Summary["added-equality-delete-files"] = strconv.Itoa(len(deletes))
+	snapshot.Summary["added-equality-deletes"] = strconv.FormatInt(deleteRecords, 10)
+	snapshot.Summary["added-files-size"] = strconv.FormatInt(size, 10)
+	for k, v := range summary {
+		snapshot.Summary[k] = v
+	}
+
+	t.manifests = manifests
+	t.meta.Snapshots = append(t.meta.Snapshots, snapshot)
+	t.meta.SnapshotLog = append(t.meta.SnapshotLog, snapshotLogEntry{
+		TimestampMs: snapshot.TimestampMs,
+		SnapshotID:  snapshot.SnapshotID,
+	})
+	currentID := snapshot.SnapshotID
+	t.meta.CurrentSnapshotID = &currentID
+	t.meta.Refs["main"] = snapshotRef{SnapshotID: snapshot.SnapshotID, Type: "branch"}
+	t.meta.LastSequenceNumber = snapshot.SequenceNumber
+	t.meta.LastUpdatedMs = snapshot.TimestampMs
+	return snapshot, nil
+}
+
+// Commit writes a new metadata file for the table and then points the version
+// hint at it, making the changes made since the last commit visible to
+// readers.
+func (t *Table) Commit(ctx context.Context) error {
+	if len(t.meta.Schemas) == 0 {
+		return errors.AssertionFailedf("cannot commit iceberg table %s without a schema", t.dir)
+	}
+	now := timeutil.Now().UnixMilli()
+	if t.version > 0 {
+		t.meta.MetadataLog = append(t.meta.MetadataLog, metadataLogEntry{
+			TimestampMs:  t.meta.LastUpdatedMs,
+			MetadataFile: t.absolute(metadataFile(t.version)),
+		})
+	}
+	t.meta.LastUpdatedMs = now
+	mapping, err := t.nameMapping()
+	if err != nil {
+		return err
+	}
+	t.meta.Properties[nameMappingProperty] = mapping
+
+	data, err := json.Marshal(t.meta)
+	if err != nil {
+		return err
+	}
+	version := t.version + 1
+	if err := cloud.WriteFile(ctx, t.es, t.join(metadataFile(version)), bytes.NewReader(data)); err != nil {
+		return err
+	}
+	if err := cloud.WriteFile(ctx, t.es, t.join(versionHintFile),
+		strings.NewReader(strconv.Itoa(version))); err != nil {
+		return err
+	}
+	t.version = version
+	return nil
+}
+
+// nameMappingEntry is an entry of the name mapping of a table.
+type nameMappingEntry struct {
+	FieldID int32              `json:"field-id"`
+	Names   []string           `json:"names"`
+	Fields  []nameMappingEntry `json:"fields,omitempty"`
+}
+
+// nameMapping returns the JSON name mapping of the table. It maps every name a
+// field has had to its ID, so that data files written before a column was
+// renamed are still read correctly. A name used by several fields is mapped to
+// the field using it in the most recent schema.
+func (t *Table) nameMapping() (string, error) {
+	owner := make(map[string]int32)
+	types := make(map[int32]json.RawMessage)
+	for _, s := range t.meta.Schemas {
+		for _, f := range s.Fields {
+			owner[f.Name] = f.ID
+			types[f.ID] = f.Type
+		}
+	}
+	entries := make(map[int32]*nameMappingEntry)
+	for name, id := range owner {
+		e, ok := entries[id]
+		if !ok {
+			e = &nameMappingEntry{FieldID: id}
+			var list listType
+			if err := json.Unmarshal(types[id], &list); err == nil && list.Type == "list" {
+				e.Fields = []nameMappingEntry{{FieldID: list.ElementID, Names: []string{"element"}}}
+			}
+			entries[id] = e
+		}
+		e.Names = append(e.Names, name)
+	}
+	res := make([]nameMappingEntry, 0, len(entries))
+	for _, e := range entries {
+		sort.Strings(e.Names)
+		res = append(res, *e)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].FieldID < res[j].FieldID })
+	data, err := json.Marshal(res)
+	return string(data), err
+}
+
+func (t *Table) schema(schemaID int) (*Schema, error) {
+	for i := range t.meta.Schemas {
+		if t.meta.Schemas[i].SchemaID == schemaID {
+			return &t.meta.Schemas[i], nil
+		}
+	}
+	return nil, errors.AssertionFailedf("iceberg table %s has no schema %d", t.dir, schemaID)
+}
+
+// join returns the path within the external storage of the given path
+// relative to the table location.
+func (t *Table) join(p string) string {
+	return path.Join(t.dir, p)
+}
+
+// absolute returns the absolute path of the given path relative to the table
+// location.
+func (t *Table) absolute(p string) string {
+	return t.meta.Location + "/" + p
+}
+
+// relative returns the path relative to the table location of the given
+// absolute path.
+func (t *Table) relative(p string) (string, error) {
+	if rel := strings.TrimPrefix(p, t.meta.Location+"/"); rel != p {
+		return rel, nil
+	}
+	return "", errors.Errorf("%s is not located in iceberg table %s", p, t.meta.Location)
+}
+
+func (t *Table) readFile(ctx context.Context, p string) ([]byte, error) {
+	r, _, err := t.es.ReadFile(ctx, t.join(p), cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close(ctx)
+	return ioctx.ReadAll(ctx, r)
+}
+
+func (s Schema) equal(o Schema) bool {
+	if len(s.Fields) != len(o.Fields) || len(s.IdentifierFieldIDs) != len(o.IdentifierFieldIDs) {
+		return false
+	}
+	for i, f := range s.Fields {
+		g := o.Fields[i]
+		if f.ID != g.ID || f.Name != g.Name || f.Required != g.Required || !bytes.Equal(f.Type, g.Type) {
+			return false
+		}
+	}
+	for i, id := range s.IdentifierFieldIDs {
+		if id != o.IdentifierFieldIDs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// maxFieldID returns the largest field ID used by a field with the given ID
+// and type, including the IDs of nested fields.
+func maxFieldID(id int32, typ json.RawMessage) (int32, error) {
+	if len(typ) == 0 || typ[0] != '{' {
+		return id, nil
+	}
+	var list listType
+	if err := json.Unmarshal(typ, &list); err != nil {
+		return 0, err
+	}
+	elementID, err := maxFieldID(list.ElementID, list.Element)
+	if err != nil {
+		return 0, err
+	}
+	if elementID > id {
+		return elementID, nil
+	}
+	return id, nil
+}
+
+func metadataFile(version int) string {
+	return fmt.Sprintf("metadata/v%d.metadata.json", version)
+}
+
+func newFileUUID() string {
+	return uuid.MakeV4().String()
+}
+
+// newSnapshotID returns a random positive snapshot ID.
+func newSnapshotID() int64 {
+	id := uuid.MakeV4()
+	return int64(binary.BigEndian.Uint64(id[:8]) & math.MaxInt64)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package iceberg
+
+import (
+	"encoding/json"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/lib/pq/oid"
+)
+
+// listElementIDOffset is added to the field ID of a list column to obtain the
+// field ID of its element. CockroachDB column IDs are used as the field IDs of
+// top level fields, so the element IDs must not collide with them.
+const listElementIDOffset = 1 << 20
+
+// listType is the JSON representation of an Iceberg list type.
+type listType struct {
+	Type            string          `json:"type"`
+	ElementID       int32           `json:"element-id"`
+	Element         json.RawMessage `json:"element"`
+	ElementRequired bool            `json:"element-required"`
+}
+
+// FieldType returns the JSON representation of the Iceberg type of the field
+// with the given ID holding values of the given type.
+//
+// The type must match the Parquet representation of the values written by
+// util/parquet. Types which util/parquet writes as strings (e.g. timestamps and
+// decimals) are therefore strings in Iceberg as well.
+func FieldType(fieldID int32, typ *types.T) (json.RawMessage, error) {
+	primitive := func(name string) (json.RawMessage, error) {
+		return json.Marshal(name)
+	}
+	switch typ.Family() {
+	case types.BoolFamily:
+		return primitive("boolean")
+	case types.IntFamily:
+		if typ.Oid() == oid.T_int8 {
+			return primitive("long")
+		}
+		return primitive("int")
+	case types.OidFamily:
+		return primitive("int")
+	case types.PGLSNFamily:
+		return primitive("long")
+	case types.FloatFamily:
+		if typ.Oid() == oid.T_float4 {
+			return primitive("float")
+		}
+		return primitive("double")
+	case types.UuidFamily:
+		return primitive("uuid")
+	case types.TimeFamily:
+		return primitive("time")
+	case types.StringFamily, types.CollatedStringFamily, types.RefCursorFamily,
+		types.DecimalFamily, types.TimestampFamily, types.TimestampTZFamily,
+		types.DateFamily, types.TimeTZFamily, types.IntervalFamily, types.INetFamily,
+		types.JsonFamily, types.EnumFamily, types.Box2DFamily:
+		return primitive("string")
+	case types.BytesFamily, types.BitFamily, types.GeographyFamily, types.GeometryFamily:
+		return primitive("binary")
+	case types.ArrayFamily:
+		contents := typ.ArrayContents()
+		if contents.Family() == types.ArrayFamily || contents.Family() == types.TupleFamily {
+			break
+		}
+		elementID := fieldID + listElementIDOffset
+		element, err := FieldType(elementID, contents)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(listType{Type: "list", ElementID: elementID, Element: element})
+	}
+	return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+		"iceberg tables do not support columns of type %s", typ.SQLString())
+}
+
+// IdentifierType returns true if columns of the given type can be identifier
+// fields of an Iceberg schema.
+func IdentifierType(typ *types.T) bool {
+	switch typ.Family() {
+	case types.FloatFamily, types.ArrayFamily:
+		return false
+	}
+	return true
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -160,6 +161,20 @@ func (e *Event) PrevKeyValue() roachpb.KeyValue {
 	return roachpb.KeyValue{Key: v.Key, Value: v.PrevValue}
 }
 
+// TxnID returns the ID of the transaction that wrote this KV event. It is
+// empty if the value was not written by a transaction that committed through
+// intent resolution, or if the event came from a catch-up scan or backfill.
+func (e *Event) TxnID() uuid.UUID {
+	return e.ev.Val.TxnID
+}
+
+// TxnWriteOrdinal returns the 1-based position of this KV event among the
+// values of its transaction that were published by the range. It is zero if
+// TxnID is empty.
+func (e *Event) TxnWriteOrdinal() int32 {
+	return e.ev.Val.TxnWriteOrdinal
+}
+
 func (e *Event) boundaryType() jobspb.ResolvedSpan_BoundaryType {
 	switch e.et {
 	case resolvedNone:
@@ -91,12 +91,28 @@ type Config struct {
 	// enables filtering out any transactional writes with that flag set to true.
 	WithFiltering bool
 
+	// ExpirationLookback, if positive, is propagated via the RangefeedRequest
+	// to the rangefeed server, which then emits a deletion when a value reaches
+	// its MVCC expiration. It is set to the longest ttl_expire_after of the
+	// target tables with ttl_mvcc_expiration.
+	ExpirationLookback time.Duration
+
+	// WithTxnIDs is propagated via the RangefeedRequest to the rangefeed
+	// server, which then populates the transaction ID and write ordinal of
+	// values published when a transaction's intent is committed.
+	WithTxnIDs bool
+
 	// WithFrontierQuantize specifies the resolved timestamp quantization
 	// granularity. If non-zero, resolved timestamps from rangefeed checkpoint
 	// events will be rounded down to the nearest multiple of the quantization
 	// granularity.
 	WithFrontierQuantize time.Duration
 
+	// If Replay is set, the feed exports every revision of the spans between
+	// InitialHighWater and EndTime, rather than running rangefeeds, and then
+	// completes.
+	Replay bool
+
 	// Knobs are kvfeed testing knobs.
 	Knobs TestingKnobs
 
@@ -140,6 +156,9 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.Targets, cfg.ScopedTimers, cfg.Knobs)
 	f.onBackfillCallback = cfg.MonitoringCfg.OnBackfillCallback
+	f.withReplay = cfg.Replay
+	f.expirationLookback = cfg.ExpirationLookback
+	f.withTxnIDs = cfg.WithTxnIDs
 	f.rangeObserver = startLaggingRangesObserver(g, cfg.MonitoringCfg.LaggingRangesCallback,
 		cfg.MonitoringCfg.LaggingRangesPollingInterval, cfg.MonitoringCfg.LaggingRangesThreshold)
 
@@ -258,6 +277,9 @@ type kvFeed struct {
 	withDiff             bool
 	withFiltering        bool
 	withInitialBackfill  bool
+	withReplay           bool
+	expirationLookback   time.Duration
+	withTxnIDs           bool
 	consumerID           int64
 	initialHighWater     hlc.Timestamp
 	endTime              hlc.Timestamp
@@ -354,6 +376,16 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 	rangeFeedResumeFrontier = span.MakeConcurrentFrontier(rangeFeedResumeFrontier)
 	defer rangeFeedResumeFrontier.Release()
 
+	if f.withReplay {
+		if err := f.replay(ctx, rangeFeedResumeFrontier); err != nil {
+			return err
+		}
+		if err := emitResolved(f.endTime, jobspb.ResolvedSpan_EXIT); err != nil {
+			return err
+		}
+		return errChangefeedCompleted
+	}
+
 	for i := 0; ; i++ {
 		initialScan := i == 0
 		initialScanOnly := f.endTime == f.initialHighWater
@@ -423,7 +455,12 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 		// should not trigger a failure in the `stop` policy because this change is
 		// effectively invisible to consumers.
 		primaryIndexChange, noColumnChanges := isPrimaryKeyChange(events, f.targets)
-		if primaryIndexChange && (noColumnChanges ||
+		if isTargetMembershipChange(events, f.targets) {
+			// A table entered or left the target scopes. Restart so that the
+			// changefeed is replanned with the new set of tables, regardless of
+			// the schema change policy.
+			boundaryType = jobspb.ResolvedSpan_RESTART
+		} else if primaryIndexChange && (noColumnChanges ||
 			f.schemaChangePolicy != changefeedbase.OptSchemaChangePolicyStop) {
 			boundaryType = jobspb.ResolvedSpan_RESTART
 		} else if f.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyStop {
@@ -448,6 +485,17 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 	}
 }
 
+func isTargetMembershipChange(
+	events []schemafeed.TableEvent, targets changefeedbase.Targets,
+) bool {
+	for _, ev := range events {
+		if schemafeed.IsTargetMembershipChange(ev, targets) {
+			return true
+		}
+	}
+	return false
+}
+
 func isPrimaryKeyChange(
 	events []schemafeed.TableEvent, targets changefeedbase.Targets,
 ) (isPrimaryIndexChange, hasNoColumnChanges bool) {
@@ -577,6 +625,55 @@ func (f *kvFeed) scanIfShould(
 	return spansToScan, scanTime, nil
 }
 
+// replay exports every revision of the watched spans in (initialHighWater,
+// endTime] in place of the rangefeeds. Spans which the checkpoint records as
+// already replayed are skipped.
+//
+// Schema changes in the window are not supported, unless the policy is to
+// not backfill: the rows a backfill writes to a new primary index are not
+// in the watched spans.
+func (f *kvFeed) replay(ctx context.Context, resumeFrontier span.Frontier) error {
+	ctx, sp := tracing.ChildSpan(ctx, "changefeed.kvfeed.replay")
+	defer sp.Finish()
+
+	events, err := f.tableFeed.Peek(ctx, f.endTime)
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 && f.schemaChangePolicy != changefeedbase.OptSchemaChangePolicyNoBackfill {
+		ev := events[0]
+		return changefeedbase.WithTerminalError(errors.Newf(
+			"cannot replay across the schema change of table %q (id %d) at %s",
+			ev.After.GetName(), ev.After.GetID(), ev.After.GetModificationTime()))
+	}
+
+	if f.spanLevelCheckpoint != nil {
+		if err := checkpoint.Restore(resumeFrontier, f.spanLevelCheckpoint); err != nil {
+			return err
+		}
+	}
+	var spansToReplay []roachpb.Span
+	for s, ts := range resumeFrontier.Entries() {
+		if ts.Less(f.endTime) {
+			spansToReplay = append(spansToReplay, s)
+		}
+	}
+	if len(spansToReplay) == 0 {
+		return nil
+	}
+
+	if f.onBackfillCallback != nil {
+		defer f.onBackfillCallback()()
+	}
+	return f.scanner.Scan(ctx, f.writer, scanConfig{
+		Spans:      spansToReplay,
+		Timestamp:  f.endTime,
+		ReplayFrom: f.initialHighWater,
+		Knobs:      f.knobs,
+		Boundary:   jobspb.ResolvedSpan_EXIT,
+	})
+}
+
 // runUntilTableEvent starts rangefeeds for the spans being watched by
 // the kv feed and runs until a table event (schema change) is encountered.
 //
@@ -617,6 +714,8 @@ func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Fro
 		Frontier:             resumeFrontier.Frontier(),
 		WithDiff:             f.withDiff,
 		WithFiltering:        f.withFiltering,
+		ExpirationLookback:   f.expirationLookback,
+		WithTxnIDs:           f.withTxnIDs,
 		WithFrontierQuantize: f.withFrontierQuantize,
 		ConsumerID:           f.consumerID,
 		Knobs:                f.knobs,
@@ -701,6 +701,97 @@ func TestCopyFromSourceToDestUntilTableEvent(t *testing.T) {
 	}
 }
 
+func TestKVFeedReplay(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ts := func(ts int) hlc.Timestamp { return hlc.Timestamp{WallTime: int64(ts)} }
+	codec := keys.SystemSQLCodec
+	sp := tableSpan(codec, 42)
+	mid := codec.IndexPrefix(42, 2)
+	schemaChange := []catalog.TableDescriptor{
+		schematestutils.MakeTableDesc(42, 1, ts(1), 2, 1),
+		schematestutils.AddColumnDropBackfillMutation(schematestutils.MakeTableDesc(42, 2, ts(5), 1, 1)),
+	}
+
+	for name, tc := range map[string]struct {
+		spanLevelCheckpoint *jobspb.TimestampSpansMap
+		descs               []catalog.TableDescriptor
+		schemaChangePolicy  changefeedbase.SchemaChangePolicy
+		expSpans            []roachpb.Span
+		expErrRE            string
+	}{
+		"replay": {
+			expSpans: []roachpb.Span{sp},
+		},
+		"partially replayed": {
+			spanLevelCheckpoint: jobspb.NewTimestampSpansMap(map[hlc.Timestamp]roachpb.Spans{
+				ts(9): {{Key: sp.Key, EndKey: mid}},
+			}),
+			expSpans: []roachpb.Span{{Key: mid, EndKey: sp.EndKey}},
+		},
+		"replayed": {
+			spanLevelCheckpoint: jobspb.NewTimestampSpansMap(map[hlc.Timestamp]roachpb.Spans{
+				ts(9): {sp},
+			}),
+		},
+		"schema change": {
+			descs:    schemaChange,
+			expErrRE: `cannot replay across the schema change of table "foo"`,
+		},
+		"schema change - no backfill": {
+			descs:              schemaChange,
+			schemaChangePolicy: changefeedbase.OptSchemaChangePolicyNoBackfill,
+			expSpans:           []roachpb.Span{sp},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var scans []scanConfig
+			sf := scannerFunc(func(ctx context.Context, sink kvevent.Writer, cfg scanConfig) error {
+				scans = append(scans, cfg)
+				return nil
+			})
+			policy := tc.schemaChangePolicy
+			if policy == "" {
+				policy = changefeedbase.OptSchemaChangePolicyBackfill
+			}
+			w := &testKVEventWriter{}
+			f := newKVFeed(w, []roachpb.Span{sp}, tc.spanLevelCheckpoint,
+				changefeedbase.OptSchemaChangeEventClassDefault, policy,
+				false /* withInitialBackfill */, false /* withDiff */, true /* withFiltering */, 0, /* withFrontierQuantize */
+				0, /* consumerID */
+				ts(2), ts(9),
+				codec,
+				newRawTableFeed(tc.descs, ts(2)), sf, nil /* pff */, nil, /* bf */
+				changefeedbase.Targets{},
+				timers.New(time.Minute).GetOrCreateScopedTimers(""), TestingKnobs{})
+			f.withReplay = true
+
+			err := f.run(context.Background())
+			if tc.expErrRE != "" {
+				require.Regexp(t, tc.expErrRE, err)
+				return
+			}
+			require.ErrorIs(t, err, errChangefeedCompleted)
+
+			if tc.expSpans == nil {
+				require.Empty(t, scans)
+			} else {
+				require.Len(t, scans, 1)
+				require.Equal(t, tc.expSpans, scans[0].Spans)
+				require.Equal(t, ts(2), scans[0].ReplayFrom)
+				require.Equal(t, ts(9), scans[0].Timestamp)
+				require.Equal(t, jobspb.ResolvedSpan_EXIT, scans[0].Boundary)
+			}
+			// The feed exits once all its spans are resolved at the end time.
+			require.Len(t, w.events, 1)
+			require.Equal(t, jobspb.ResolvedSpan{
+				Span: sp, Timestamp: ts(9), BoundaryType: jobspb.ResolvedSpan_EXIT,
+			}, w.events[0].Resolved())
+		})
+	}
+}
+
 func makeCheckpointEvent(key []byte, endKey []byte, ts int, logical int32) *kvpb.RangeFeedEvent {
 	return &kvpb.RangeFeedEvent{
 		Checkpoint: &kvpb.RangeFeedCheckpoint{
@@ -33,6 +33,8 @@ type rangeFeedConfig struct {
 	Spans                []kvcoord.SpanTimePair
 	WithDiff             bool
 	WithFiltering        bool
+	ExpirationLookback   time.Duration
+	WithTxnIDs           bool
 	WithFrontierQuantize time.Duration
 	ConsumerID           int64
 	RangeObserver        kvcoord.RangeObserver
@@ -100,6 +102,12 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	if cfg.WithFiltering {
 		rfOpts = append(rfOpts, kvcoord.WithFiltering())
 	}
+	if cfg.ExpirationLookback > 0 {
+		rfOpts = append(rfOpts, kvcoord.WithExpirations(cfg.ExpirationLookback))
+	}
+	if cfg.WithTxnIDs {
+		rfOpts = append(rfOpts, kvcoord.WithTxnIDs())
+	}
 	if cfg.RangeObserver != nil {
 		rfOpts = append(rfOpts, kvcoord.WithRangeObserver(cfg.RangeObserver))
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/covering"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -40,6 +41,9 @@ type scanConfig struct {
 	WithDiff  bool
 	Knobs     TestingKnobs
 	Boundary  jobspb.ResolvedSpan_BoundaryType
+	// ReplayFrom, if set, makes the scan export every revision of the spans
+	// in (ReplayFrom, Timestamp] instead of the latest values at Timestamp.
+	ReplayFrom hlc.Timestamp
 }
 
 type kvScanner interface {
@@ -113,7 +117,11 @@ func (p *scanRequestScanner) Scan(ctx context.Context, sink kvevent.Writer, cfg
 			}
 			defer spanAlloc.Release(ctx)
 
-			err = p.exportSpan(ctx, span, cfg.Timestamp, cfg.Boundary, cfg.WithDiff, sink, cfg.Knobs)
+			if cfg.ReplayFrom.IsEmpty() {
+				err = p.exportSpan(ctx, span, cfg.Timestamp, cfg.Boundary, cfg.WithDiff, sink, cfg.Knobs)
+			} else {
+				err = p.replaySpan(ctx, span, cfg.ReplayFrom, cfg.Timestamp, cfg.Boundary, sink)
+			}
 			finished := atomic.AddInt64(&atomicFinished, 1)
 			if backfillDec != nil {
 				backfillDec()
@@ -254,6 +262,151 @@ func (p *scanRequestScanner) exportSpan(
 	return nil
 }
 
+// replaySpan exports every revision of the keys in the span written in
+// (startTime, endTime], including deletions, and emits them to the sink in
+// timestamp order per key. Like backup, it uses incremental ExportRequests
+// with MVCCFilter_All.
+func (p *scanRequestScanner) replaySpan(
+	ctx context.Context,
+	span roachpb.Span,
+	startTime, endTime hlc.Timestamp,
+	boundaryType jobspb.ResolvedSpan_BoundaryType,
+	sink kvevent.Writer,
+) error {
+	ctx, sp := tracing.ChildSpan(ctx, "changefeed.kvfeed.scanner.replay_span")
+	defer sp.Finish()
+
+	if log.V(2) {
+		log.Infof(ctx, `sending ExportRequest %s over (%s, %s]`, span, startTime, endTime)
+	}
+	stopwatchStart := timeutil.Now()
+	for remaining := &span; remaining != nil; {
+		req := &kvpb.ExportRequest{
+			RequestHeader:  kvpb.RequestHeaderFromSpan(*remaining),
+			StartTime:      startTime,
+			MVCCFilter:     kvpb.MVCCFilter_All,
+			TargetFileSize: changefeedbase.ScanRequestSize.Get(&p.settings.SV),
+			// Keep all the revisions of a key in the same response so that they
+			// can be emitted in timestamp order.
+			SplitMidKey: false,
+		}
+		header := kvpb.Header{
+			// As in backup, the sentinel value of 1 forces the ExportRequest to
+			// paginate after a single SST, which bounds the size of a response
+			// by the memory acquired for it.
+			TargetBytes:                 1,
+			Timestamp:                   endTime,
+			ConnectionClass:             rpcbase.RangefeedClass,
+			ReturnElasticCPUResumeSpans: true,
+		}
+		admissionHeader := kvpb.AdmissionHeader{
+			Priority:                 int32(admissionpb.BulkNormalPri),
+			CreateTime:               timeutil.Now().UnixNano(),
+			Source:                   kvpb.AdmissionHeader_FROM_SQL,
+			NoMemoryReservedAtSource: true,
+		}
+		rawResp, pErr := kv.SendWrappedWithAdmission(
+			ctx, p.db.NonTransactionalSender(), header, admissionHeader, req)
+		if pErr != nil {
+			return errors.Wrapf(pErr.GoError(), `exporting revisions for %s`, span)
+		}
+		resp := rawResp.(*kvpb.ExportResponse)
+		for _, file := range resp.Files {
+			if err := slurpExportedRevisions(ctx, sink, file.SST, *remaining); err != nil {
+				return err
+			}
+		}
+		if resp.ResumeSpan != nil {
+			if !resp.ResumeSpan.Valid() {
+				return errors.Errorf("invalid resume span: %s", resp.ResumeSpan)
+			}
+			consumed := roachpb.Span{Key: remaining.Key, EndKey: resp.ResumeSpan.Key}
+			if err := sink.Add(
+				ctx, kvevent.NewBackfillResolvedEvent(consumed, endTime, boundaryType),
+			); err != nil {
+				return err
+			}
+		}
+		remaining = resp.ResumeSpan
+	}
+	if err := sink.Add(
+		ctx, kvevent.NewBackfillResolvedEvent(span, endTime, boundaryType),
+	); err != nil {
+		return err
+	}
+	if log.V(2) {
+		log.Infof(ctx, `finished replay of %s over (%s, %s] took %s`,
+			span, startTime.AsOfSystemTime(), endTime.AsOfSystemTime(), timeutil.Since(stopwatchStart))
+	}
+	return nil
+}
+
+// slurpExportedRevisions iterates an SST returned by an ExportRequest with
+// MVCCFilter_All and inserts the contained revisions into the KVFeed's buffer.
+// The SST holds the revisions of a key from newest to oldest; they are
+// inserted from oldest to newest. Deletions are inserted with an empty value,
+// as the rangefeed does.
+func slurpExportedRevisions(
+	ctx context.Context, sink kvevent.Writer, sst []byte, span roachpb.Span,
+) error {
+	it, err := storage.NewMemSSTIterator(sst, false /* verify */, storage.IterOptions{
+		// NB: MVCC range tombstones are ignored, as they are by the rangefeed.
+		KeyTypes:   storage.IterKeyTypePointsOnly,
+		LowerBound: keys.MinKey,
+		UpperBound: keys.MaxKey,
+	})
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	var revisions []roachpb.KeyValue
+	flush := func() error {
+		for i := len(revisions) - 1; i >= 0; i-- {
+			if log.V(3) {
+				log.Infof(ctx, "exportResponse: %s@%s",
+					keys.PrettyPrint(nil, revisions[i].Key), revisions[i].Value.Timestamp)
+			}
+			if err := sink.Add(ctx, kvevent.MakeKVEvent(&kvpb.RangeFeedEvent{
+				Val: &kvpb.RangeFeedValue{Key: revisions[i].Key, Value: revisions[i].Value},
+			})); err != nil {
+				return errors.Wrapf(err, `buffering changes for %s`, span)
+			}
+		}
+		revisions = revisions[:0]
+		return nil
+	}
+	for it.SeekGE(storage.NilKey); ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return errors.Wrapf(err, `decoding changes for %s`, span)
+		} else if !ok {
+			break
+		}
+		k := it.UnsafeKey()
+		if len(revisions) > 0 && !revisions[0].Key.Equal(k.Key) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		v, err := it.UnsafeValue()
+		if err != nil {
+			return err
+		}
+		mvccValue, err := storage.DecodeMVCCValue(v)
+		if err != nil {
+			return errors.Wrapf(err, `decoding changes for %s`, span)
+		}
+		revisions = append(revisions, roachpb.KeyValue{
+			Key: k.Key.Clone(),
+			Value: roachpb.Value{
+				RawBytes:  append([]byte(nil), mvccValue.Value.RawBytes...),
+				Timestamp: k.Timestamp,
+			},
+		})
+	}
+	return flush()
+}
+
 // getRangesToProcess returns the list of ranges covering input list of spans.
 // Returns the number of nodes that are leaseholders for those spans.
 func getRangesToProcess(
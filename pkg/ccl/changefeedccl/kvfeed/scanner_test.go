@@ -24,12 +24,16 @@ import (
 
 type recordResolvedWriter struct {
 	resolved    []jobspb.ResolvedSpan
+	kvs         []roachpb.KeyValue
 	memAcquired bool
 }
 
 func (r *recordResolvedWriter) Add(ctx context.Context, e kvevent.Event) error {
-	if e.Type() == kvevent.TypeResolved {
+	switch e.Type() {
+	case kvevent.TypeResolved:
 		r.resolved = append(r.resolved, e.Resolved())
+	case kvevent.TypeKV:
+		r.kvs = append(r.kvs, e.KV())
 	}
 	return nil
 }
@@ -100,3 +104,58 @@ INSERT INTO t VALUES (1), (2), (3);
 	require.Equal(t, span, sink.resolved[2].Span)
 	require.Equal(t, exportTime, sink.resolved[2].Timestamp)
 }
+
+func TestReplayEmitsRevisions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, kvdb := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b INT)`)
+	codec := s.Codec()
+	descr := desctestutils.TestingGetPublicTableDescriptor(kvdb, codec, "defaultdb", "t")
+	span := tableSpan(codec, uint32(descr.GetID()))
+
+	startTime := kvdb.Clock().Now()
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 1)`)
+	sqlDB.Exec(t, `UPDATE t SET b = 2 WHERE a = 1`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (2, 1)`)
+	sqlDB.Exec(t, `DELETE FROM t WHERE a = 1`)
+	endTime := kvdb.Clock().Now()
+	sqlDB.Exec(t, `INSERT INTO t VALUES (3, 1)`)
+
+	scanner := &scanRequestScanner{
+		settings: s.ClusterSettings(),
+		db:       kvdb,
+	}
+	sink := &recordResolvedWriter{}
+	require.NoError(t, scanner.Scan(ctx, sink, scanConfig{
+		Spans:      []roachpb.Span{span},
+		Timestamp:  endTime,
+		ReplayFrom: startTime,
+		Boundary:   jobspb.ResolvedSpan_EXIT,
+	}))
+
+	// The three revisions of the first row, including its deletion, are
+	// emitted in timestamp order, followed by the revision of the second row.
+	require.Len(t, sink.kvs, 4)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, sink.kvs[0].Key, sink.kvs[i].Key)
+		require.True(t, startTime.Less(sink.kvs[i].Value.Timestamp))
+		if i > 0 {
+			require.True(t, sink.kvs[i-1].Value.Timestamp.Less(sink.kvs[i].Value.Timestamp))
+		}
+	}
+	require.True(t, sink.kvs[1].Value.IsPresent())
+	require.False(t, sink.kvs[2].Value.IsPresent())
+	require.NotEqual(t, sink.kvs[0].Key, sink.kvs[3].Key)
+	require.True(t, sink.kvs[3].Value.Timestamp.LessEq(endTime))
+
+	require.Equal(t, jobspb.ResolvedSpan{
+		Span: span, Timestamp: endTime, BoundaryType: jobspb.ResolvedSpan_EXIT,
+	}, sink.resolved[len(sink.resolved)-1])
+}
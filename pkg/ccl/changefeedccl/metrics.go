@@ -56,6 +56,7 @@ type AggMetrics struct {
 	EmittedMessages             *aggmetric.AggCounter
 	EmittedBatchSizes           *aggmetric.AggHistogram
 	FilteredMessages            *aggmetric.AggCounter
+	DLQRows                     *aggmetric.AggCounter
 	MessageSize                 *aggmetric.AggHistogram
 	EmittedBytes                *aggmetric.AggCounter
 	FlushedBytes                *aggmetric.AggCounter
@@ -141,6 +142,7 @@ type sliMetrics struct {
 	EmittedResolvedMessages     *aggmetric.Counter
 	EmittedBatchSizes           *aggmetric.Histogram
 	FilteredMessages            *aggmetric.Counter
+	DLQRows                     *aggmetric.Counter
 	MessageSize                 *aggmetric.Histogram
 	EmittedBytes                *aggmetric.Counter
 	FlushedBytes                *aggmetric.Counter
@@ -815,6 +817,13 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 		Measurement: "Messages",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaChangefeedDLQRows := metric.Metadata{
+		Name: "changefeed.dlq_rows",
+		Help: "Rows written to the dead letter queue by all feeds because they " +
+			"could not be encoded or were rejected by the sink",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaChangefeedEmittedBytes := metric.Metadata{
 		Name:        "changefeed.emitted_bytes",
 		Help:        "Bytes emitted by all feeds",
@@ -1044,6 +1053,7 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 			BucketConfig: metric.DataCount16MBuckets,
 		}),
 		FilteredMessages: b.Counter(metaChangefeedFilteredMessages),
+		DLQRows:          b.Counter(metaChangefeedDLQRows),
 		MessageSize: b.Histogram(metric.HistogramOptions{
 			Metadata:     metaMessageSize,
 			Duration:     histogramWindow,
@@ -1166,6 +1176,7 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		EmittedResolvedMessages:     a.EmittedMessages.AddChild(scope, "resolved"),
 		EmittedBatchSizes:           a.EmittedBatchSizes.AddChild(scope),
 		FilteredMessages:            a.FilteredMessages.AddChild(scope),
+		DLQRows:                     a.DLQRows.AddChild(scope),
 		MessageSize:                 a.MessageSize.AddChild(scope),
 		EmittedBytes:                a.EmittedBytes.AddChild(scope),
 		FlushedBytes:                a.FlushedBytes.AddChild(scope),
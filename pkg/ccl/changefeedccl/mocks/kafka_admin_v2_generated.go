@@ -35,6 +35,26 @@ func (m *MockKafkaAdminClientV2) EXPECT() *MockKafkaAdminClientV2MockRecorder {
 	return m.recorder
 }
 
+// ListCommittedOffsets mocks base method.
+func (m *MockKafkaAdminClientV2) ListCommittedOffsets(arg0 context.Context, arg1 ...string) (kadm.ListedOffsets, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListCommittedOffsets", varargs...)
+	ret0, _ := ret[0].(kadm.ListedOffsets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommittedOffsets indicates an expected call of ListCommittedOffsets.
+func (mr *MockKafkaAdminClientV2MockRecorder) ListCommittedOffsets(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommittedOffsets", reflect.TypeOf((*MockKafkaAdminClientV2)(nil).ListCommittedOffsets), varargs...)
+}
+
 // ListTopics mocks base method.
 func (m *MockKafkaAdminClientV2) ListTopics(arg0 context.Context, arg1 ...string) (kadm.TopicDetails, error) {
 	m.ctrl.T.Helper()
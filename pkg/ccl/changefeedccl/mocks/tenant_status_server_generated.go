@@ -82,6 +82,21 @@ func (mr *MockTenantStatusServerMockRecorder) Gossip(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Gossip", reflect.TypeOf((*MockTenantStatusServer)(nil).Gossip), arg0, arg1)
 }
 
+// HotKeys mocks base method.
+func (m *MockTenantStatusServer) HotKeys(arg0 context.Context, arg1 *serverpb.HotKeysRequest) (*serverpb.HotKeysResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HotKeys", arg0, arg1)
+	ret0, _ := ret[0].(*serverpb.HotKeysResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HotKeys indicates an expected call of HotKeys.
+func (mr *MockTenantStatusServerMockRecorder) HotKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HotKeys", reflect.TypeOf((*MockTenantStatusServer)(nil).HotKeys), arg0, arg1)
+}
+
 // HotRangesV2 mocks base method.
 func (m *MockTenantStatusServer) HotRangesV2(arg0 context.Context, arg1 *serverpb.HotRangesRequest) (*serverpb.HotRangesResponseV2, error) {
 	m.ctrl.T.Helper()
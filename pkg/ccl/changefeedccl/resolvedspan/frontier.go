@@ -59,6 +59,28 @@ func (f *AggregatorFrontier) ForwardResolvedSpan(
 	return f.resolvedSpanFrontier.ForwardResolvedSpan(r)
 }
 
+// AllBefore returns an iterator over the resolved spans in the frontier with
+// their timestamps held below ts. It is used when the change aggregator has
+// not yet emitted all of the rows at or above ts, so the spans must not be
+// checkpointed at or past it.
+func (f *AggregatorFrontier) AllBefore(ts hlc.Timestamp) iter.Seq[jobspb.ResolvedSpan] {
+	limit := ts.Prev()
+	return func(yield func(jobspb.ResolvedSpan) bool) {
+		for r := range f.All() {
+			if limit.Less(r.Timestamp) {
+				r.Timestamp = limit
+				r.BoundaryType = jobspb.ResolvedSpan_NONE
+				if ok, bt := f.boundary.At(limit); ok {
+					r.BoundaryType = bt
+				}
+			}
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
 // CoordinatorFrontier wraps a resolvedSpanFrontier with additional
 // checks specific to how the coordinator/change frontier processes boundaries.
 type CoordinatorFrontier struct {
@@ -292,3 +292,46 @@ func TestAggregatorFrontier_ForwardResolvedSpan(t *testing.T) {
 		require.Equal(t, makeTS(10), f.Frontier())
 	})
 }
+
+func TestAggregatorFrontier_AllBefore(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	f, err := resolvedspan.NewAggregatorFrontier(
+		hlc.Timestamp{},
+		hlc.Timestamp{},
+		makeSpan("a", "f"),
+	)
+	require.NoError(t, err)
+	for _, r := range []jobspb.ResolvedSpan{
+		makeResolvedSpan("a", "b", makeTS(10), jobspb.ResolvedSpan_NONE),
+		makeResolvedSpan("b", "d", makeTS(20), jobspb.ResolvedSpan_NONE),
+		makeResolvedSpan("d", "f", makeTS(30), jobspb.ResolvedSpan_RESTART),
+	} {
+		_, err := f.ForwardResolvedSpan(r)
+		require.NoError(t, err)
+	}
+
+	// Spans resolved at or past the limit are held just below it and lose
+	// their boundary type; the others are unchanged.
+	var spans []jobspb.ResolvedSpan
+	for r := range f.AllBefore(makeTS(20)) {
+		spans = append(spans, r)
+	}
+	require.Equal(t, []jobspb.ResolvedSpan{
+		makeResolvedSpan("a", "b", makeTS(10), jobspb.ResolvedSpan_NONE),
+		makeResolvedSpan("b", "d", makeTS(20).Prev(), jobspb.ResolvedSpan_NONE),
+		makeResolvedSpan("d", "f", makeTS(20).Prev(), jobspb.ResolvedSpan_NONE),
+	}, spans)
+
+	// A limit past every span returns the spans as they are.
+	spans = nil
+	for r := range f.AllBefore(makeTS(40)) {
+		spans = append(spans, r)
+	}
+	require.Equal(t, []jobspb.ResolvedSpan{
+		makeResolvedSpan("a", "b", makeTS(10), jobspb.ResolvedSpan_NONE),
+		makeResolvedSpan("b", "d", makeTS(20), jobspb.ResolvedSpan_NONE),
+		makeResolvedSpan("d", "f", makeTS(30), jobspb.ResolvedSpan_RESTART),
+	}, spans)
+}
@@ -268,6 +268,24 @@ func getFirstRunOpt(evalCtx *eval.Context, opts map[string]string) (*time.Time,
 
 // makeScheduleChangefeedSpec prepares helper scheduledChangefeedSpec struct to
 // assist in evaluation of various schedule and changefeed specific components.
+// withQualifiedTarget returns a copy of the table expression of a CDC query in
+// which the target table is replaced with its fully qualified name. The alias
+// of the target, as well as any tables joined with it, are preserved.
+func withQualifiedTarget(expr tree.TableExpr, qualified tree.TableExpr) tree.TableExpr {
+	switch t := expr.(type) {
+	case *tree.JoinTableExpr:
+		j := *t
+		j.Left = withQualifiedTarget(t.Left, qualified)
+		return &j
+	case *tree.AliasedTableExpr:
+		aliased := *t
+		aliased.Expr = qualified
+		return &aliased
+	default:
+		return qualified
+	}
+}
+
 func makeScheduledChangefeedSpec(
 	ctx context.Context, p sql.PlanHookState, schedule *tree.ScheduledChangefeed,
 ) (*scheduledChangefeedSpec, error) {
@@ -310,7 +328,8 @@ func makeScheduledChangefeedSpec(
 		// tree.TableExpr (from Select clause) into tree.ChangefeedTarget. If that
 		// typecasting was successful, it is guaranteed that the reverse should work
 		// without any errors.
-		tableExprs[0] = qualifiedTablePatterns[0].(tree.TableExpr)
+		tableExprs[0] = withQualifiedTarget(
+			schedule.Select.From.Tables[0], qualifiedTablePatterns[0].(tree.TableExpr))
 		schedule.Select.From.Tables = tableExprs
 	}
 
@@ -77,7 +77,17 @@ type SchemaFeed interface {
 	Pop(ctx context.Context, atOrBefore hlc.Timestamp) (events []TableEvent, err error)
 }
 
+// DDLEventSource is implemented by schema feeds created with ddlEvents set.
+// It is a stream of the events that change the visible columns of the target
+// tables, regardless of whether they are filtered out of the SchemaFeed.
+type DDLEventSource interface {
+	// PopDDLEvents returns the events changing the visible columns of a table
+	// occurring up to atOrBefore and removes them from the feed.
+	PopDDLEvents(ctx context.Context, atOrBefore hlc.Timestamp) (events []TableEvent, err error)
+}
+
 // New creates a SchemaFeed tracking 'targets' and emitting specified 'events'.
+// If ddlEvents is set, the returned SchemaFeed also implements DDLEventSource.
 //
 // initialFrontier is the earliest timestamp for which updates should be emitted.
 // NB: When clients want to create a changefeed which has a resolved timestamp
@@ -88,6 +98,7 @@ func New(
 	ctx context.Context,
 	cfg *execinfra.ServerConfig,
 	events changefeedbase.SchemaChangeEventClass,
+	ddlEvents bool,
 	targets changefeedbase.Targets,
 	initialFrontier hlc.Timestamp,
 	metrics *Metrics,
@@ -95,6 +106,7 @@ func New(
 ) SchemaFeed {
 	m := &schemaFeed{
 		filter:          schemaChangeEventFilters[events],
+		ddlEvents:       ddlEvents,
 		db:              cfg.DB,
 		clock:           cfg.DB.KV().Clock(),
 		settings:        cfg.Settings,
@@ -106,6 +118,8 @@ func New(
 	}
 	m.mu.previousTableVersion = make(map[descpb.ID]catalog.TableDescriptor)
 	m.mu.typeDeps = typeDependencyTracker{deps: make(map[descpb.ID][]descpb.ID)}
+	m.mu.pendingScopedTables = make(map[descpb.ID]struct{})
+	m.mu.scopeChanges = make(map[descpb.ID]struct{})
 	return m
 }
 
@@ -121,6 +135,7 @@ func New(
 // earliest timestamp where at least one table doesn't meet the invariant.
 type schemaFeed struct {
 	filter          tableEventFilter
+	ddlEvents       bool
 	db              descs.DB
 	clock           *hlc.Clock
 	settings        *cluster.Settings
@@ -148,6 +163,11 @@ type schemaFeed struct {
 		// TODO(yang): Refactor this into a struct and extract out all the logic.
 		events []TableEvent
 
+		// ddlEvents is a sorted list of the table events which change the
+		// visible columns of a table and have not been popped. It is only
+		// populated if the schema feed was created with ddlEvents set.
+		ddlEvents []TableEvent
+
 		// previousTableVersion is a map from tableID to the most recent version
 		// of the table descriptor seen by the poller. This is needed to determine
 		// when a backfilling mutation has successfully completed - this can only
@@ -162,6 +182,16 @@ type schemaFeed struct {
 		// Polling can be paused if all tables are locked from schema changes because
 		// we know no table events will occur.
 		pollingPaused bool
+
+		// pendingScopedTables are targets of a changefeed with target scopes that
+		// were not public at the initial frontier. These are tables that entered
+		// a scope right after it, and the event for their first public version
+		// tells the kv feed to scan them.
+		pendingScopedTables map[descpb.ID]struct{}
+
+		// scopeChanges are the tables for which an event that changes the
+		// target membership has already been queued.
+		scopeChanges map[descpb.ID]struct{}
 	}
 }
 
@@ -283,12 +313,14 @@ func (tf *schemaFeed) Run(ctx context.Context) error {
 
 func (tf *schemaFeed) primeInitialTableDescs(ctx context.Context) error {
 	var initialDescs []catalog.Descriptor
+	var pendingScopedTables []descpb.ID
 
 	initialTableDescsFn := func(
 		ctx context.Context, txn descs.Txn,
 	) error {
 		descriptors := txn.Descriptors()
 		initialDescs = initialDescs[:0]
+		pendingScopedTables = pendingScopedTables[:0]
 		if err := txn.KV().SetFixedTimestamp(ctx, tf.initialFrontier); err != nil {
 			return err
 		}
@@ -296,6 +328,13 @@ func (tf *schemaFeed) primeInitialTableDescs(ctx context.Context) error {
 		return tf.targets.EachTableID(func(id descpb.ID) error {
 			tableDesc, err := descriptors.ByIDWithoutLeased(txn.KV()).WithoutNonPublic().Get().Table(ctx, id)
 			if err != nil {
+				// A table that entered a target scope right after the initial
+				// frontier is a target that is not public yet.
+				if len(tf.targets.Scopes) > 0 &&
+					(errors.Is(err, catalog.ErrDescriptorNotFound) || catalog.HasInactiveDescriptorError(err)) {
+					pendingScopedTables = append(pendingScopedTables, id)
+					return nil
+				}
 				return err
 			}
 			initialDescs = append(initialDescs, tableDesc)
@@ -315,6 +354,9 @@ func (tf *schemaFeed) primeInitialTableDescs(ctx context.Context) error {
 			tbl := desc.(catalog.TableDescriptor)
 			tf.mu.typeDeps.ingestTable(tbl)
 		}
+		for _, id := range pendingScopedTables {
+			tf.mu.pendingScopedTables[id] = struct{}{}
+		}
 	}()
 
 	return tf.ingestDescriptors(ctx, hlc.Timestamp{}, tf.initialFrontier, initialDescs, tf.validateDescriptor)
@@ -391,6 +433,29 @@ func (tf *schemaFeed) Pop(
 	return tf.peekOrPop(ctx, atOrBefore, true /* pop */)
 }
 
+// PopDDLEvents implements the DDLEventSource interface.
+func (tf *schemaFeed) PopDDLEvents(
+	ctx context.Context, atOrBefore hlc.Timestamp,
+) (events []TableEvent, err error) {
+	if !tf.ddlEvents {
+		return nil, errors.AssertionFailedf("schema feed was not created with ddl events")
+	}
+	if err = tf.pauseOrResumePolling(ctx, atOrBefore); err != nil {
+		return nil, err
+	}
+	if err = tf.waitForTS(ctx, atOrBefore); err != nil {
+		return nil, err
+	}
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	i := sort.Search(len(tf.mu.ddlEvents), func(i int) bool {
+		return !tf.mu.ddlEvents[i].Timestamp().LessEq(atOrBefore)
+	})
+	events = tf.mu.ddlEvents[:i]
+	tf.mu.ddlEvents = tf.mu.ddlEvents[i:]
+	return events, nil
+}
+
 func (tf *schemaFeed) peekOrPop(
 	ctx context.Context, atOrBefore hlc.Timestamp, pop bool,
 ) (events []TableEvent, err error) {
@@ -502,6 +567,12 @@ func (tf *schemaFeed) pauseOrResumePolling(ctx context.Context, atOrBefore hlc.T
 	// Always assume we need to resume polling until we've proven otherwise.
 	tf.mu.pollingPaused = false
 
+	// Tables can enter a target scope at any time, so polling is never paused
+	// for changefeeds with target scopes.
+	if len(tf.targets.Scopes) > 0 {
+		return nil
+	}
+
 	if canPausePolling, err := tf.targets.EachTableIDWithBool(func(id descpb.ID) (bool, error) {
 		// Check if target table is schema-locked at the current frontier.
 		ld1, err := tf.leaseMgr.Acquire(ctx, frontier, id)
@@ -684,6 +755,9 @@ func (tf *schemaFeed) validateDescriptor(
 		}
 		return nil
 	case catalog.TableDescriptor:
+		if handled, err := tf.maybeQueueScopeEventLocked(ctx, earliestTsBeingIngested, desc); handled || err != nil {
+			return err
+		}
 		if err := changefeedvalidators.ValidateTable(tf.targets, desc, tf.tolerances); err != nil {
 			return err
 		}
@@ -712,23 +786,16 @@ func (tf *schemaFeed) validateDescriptor(
 				Before: lastVersion,
 				After:  desc,
 			}
+			if tf.ddlEvents && IsVisibleColumnChange(e, tf.targets) {
+				tf.mu.ddlEvents = insertEventSorted(tf.mu.ddlEvents, earliestTsBeingIngested, e)
+			}
 			shouldFilter, err := tf.filter.shouldFilter(ctx, e, tf.targets)
 			log.VEventf(ctx, 1, "validate shouldFilter %v %v", formatEvent(e), shouldFilter)
 			if err != nil {
 				return changefeedbase.WithTerminalError(err)
 			}
 			if !shouldFilter {
-				// Only sort the tail of the events from earliestTsBeingIngested.
-				// The head could already have been handed out and sorting is not
-				// stable.
-				idxToSort := sort.Search(len(tf.mu.events), func(i int) bool {
-					return !tf.mu.events[i].After.GetModificationTime().Less(earliestTsBeingIngested)
-				})
-				tf.mu.events = append(tf.mu.events, e)
-				toSort := tf.mu.events[idxToSort:]
-				sort.Slice(toSort, func(i, j int) bool {
-					return descLess(toSort[i].After, toSort[j].After)
-				})
+				tf.queueEventLocked(earliestTsBeingIngested, e)
 			}
 		}
 		// Add the types used by the table into the dependency tracker.
@@ -740,6 +807,89 @@ func (tf *schemaFeed) validateDescriptor(
 	}
 }
 
+// queueEventLocked adds an event to the sorted list of events.
+func (tf *schemaFeed) queueEventLocked(earliestTsBeingIngested hlc.Timestamp, e TableEvent) {
+	tf.mu.events = insertEventSorted(tf.mu.events, earliestTsBeingIngested, e)
+}
+
+// insertEventSorted adds an event to a sorted list of events.
+func insertEventSorted(
+	events []TableEvent, earliestTsBeingIngested hlc.Timestamp, e TableEvent,
+) []TableEvent {
+	// Only sort the tail of the events from earliestTsBeingIngested.
+	// The head could already have been handed out and sorting is not
+	// stable.
+	idxToSort := sort.Search(len(events), func(i int) bool {
+		return !events[i].After.GetModificationTime().Less(earliestTsBeingIngested)
+	})
+	events = append(events, e)
+	toSort := events[idxToSort:]
+	sort.Slice(toSort, func(i, j int) bool {
+		return descLess(toSort[i].After, toSort[j].After)
+	})
+	return events
+}
+
+// maybeQueueScopeEventLocked handles the versions of tables in the target
+// scopes of a changefeed that change its target membership, or that make a
+// table which just joined the targets visible. It returns true if the
+// descriptor was handled and needs no further validation.
+//
+//   - A table entering a scope yields an event whose Before and After are its
+//     first public version. The kv feed restarts the changefeed at it; see
+//     IsTargetMembershipChange.
+//   - A watched table being dropped yields an event whose After is the dropped
+//     version, which also restarts the changefeed.
+//   - After such a restart, the first public version of a table that entered
+//     a scope yields an event whose Before and After are that version, which
+//     the kv feed uses to scan the table.
+func (tf *schemaFeed) maybeQueueScopeEventLocked(
+	ctx context.Context, earliestTsBeingIngested hlc.Timestamp, desc catalog.TableDescriptor,
+) (bool, error) {
+	if len(tf.targets.Scopes) == 0 || !tf.targets.InScope(desc.GetParentID(), desc.GetParentSchemaID()) {
+		return false, nil
+	}
+	id := desc.GetID()
+	isTarget, _ := tf.targets.EachHavingTableID(id, func(changefeedbase.Target) error { return nil })
+	switch {
+	case !isTarget:
+		if _, queued := tf.mu.scopeChanges[id]; !queued && changefeedvalidators.IsScopedTable(desc) {
+			log.Infof(ctx, "table %q [%d] entered changefeed target scope at %s",
+				desc.GetName(), id, desc.GetModificationTime())
+			tf.mu.scopeChanges[id] = struct{}{}
+			tf.queueEventLocked(earliestTsBeingIngested, TableEvent{Before: desc, After: desc})
+		}
+		return true, nil
+	case desc.Dropped():
+		if _, queued := tf.mu.scopeChanges[id]; !queued {
+			log.Infof(ctx, "table %q [%d] left changefeed target scope at %s",
+				desc.GetName(), id, desc.GetModificationTime())
+			tf.mu.scopeChanges[id] = struct{}{}
+			before, ok := tf.mu.previousTableVersion[id]
+			if !ok {
+				before = desc
+			}
+			tf.queueEventLocked(earliestTsBeingIngested, TableEvent{Before: before, After: desc})
+		}
+		return true, nil
+	}
+	if _, pending := tf.mu.pendingScopedTables[id]; !pending {
+		return false, nil
+	}
+	if !desc.Public() {
+		// Wait for the first public version of the table.
+		return true, nil
+	}
+	if err := changefeedvalidators.ValidateTable(tf.targets, desc, tf.tolerances); err != nil {
+		return true, err
+	}
+	delete(tf.mu.pendingScopedTables, id)
+	tf.queueEventLocked(earliestTsBeingIngested, TableEvent{Before: desc, After: desc})
+	tf.mu.typeDeps.ingestTable(desc)
+	tf.mu.previousTableVersion[id] = desc
+	return true, nil
+}
+
 var highPriorityAfter = settings.RegisterDurationSetting(
 	settings.ApplicationLevel,
 	"changefeed.schema_feed.read_with_priority_after",
@@ -866,8 +1016,11 @@ func (tf *schemaFeed) fetchDescriptorVersions(
 						return found // sentinel error to break the loop
 					})
 					isType := tf.mu.typeDeps.containsType(descpb.ID(id))
+					// Any table may enter the target scopes of a database or schema
+					// level changefeed, so all table descriptors are interesting.
+					hasScopes := len(tf.targets.Scopes) > 0
 					// Check if the descriptor is an interesting table or type.
-					if !(isTable || isType) {
+					if !(isTable || isType || hasScopes) {
 						// Uninteresting descriptor.
 						continue
 					}
@@ -882,6 +1035,12 @@ func (tf *schemaFeed) fetchDescriptorVersions(
 							return changefeedbase.WithTerminalError(
 								errors.Wrapf(catalog.ErrDescriptorDropped, "type descriptor %d dropped", id))
 						}
+						if !isTable || hasScopes {
+							// Either an uninteresting descriptor was deleted, or the table
+							// left the target scopes with the dropped version that
+							// preceded its deletion.
+							continue
+						}
 
 						name := origName
 						if name == "" {
@@ -898,7 +1057,13 @@ func (tf *schemaFeed) fetchDescriptorVersions(
 						return err
 					}
 					if b != nil && (b.DescriptorType() == catalog.Table || b.DescriptorType() == catalog.Type) {
-						descriptors = append(descriptors, b.BuildImmutable())
+						desc := b.BuildImmutable()
+						if !(isTable || isType) &&
+							(desc.DescriptorType() != catalog.Table ||
+								!tf.targets.InScope(desc.GetParentID(), desc.GetParentSchemaID())) {
+							continue
+						}
+						descriptors = append(descriptors, desc)
 					}
 				}
 			}(); err != nil {
@@ -249,7 +249,7 @@ func TestFetchDescriptorVersionsCPULimiterPagination(t *testing.T) {
 	})
 	now := s.Clock().Now()
 	sf := New(ctx, &sqlServer.GetExecutorConfig().DistSQLSrv.ServerConfig,
-		TestingAllEventFilter, targets, now, nil, changefeedbase.CanHandle{
+		TestingAllEventFilter, false /* ddlEvents */, targets, now, nil, changefeedbase.CanHandle{
 			MultipleColumnFamilies: true,
 			VirtualColumns:         true,
 		})
@@ -293,7 +293,7 @@ func TestSchemaFeedHandlesCascadeDatabaseDrop(t *testing.T) {
 		StatementTimeName: "foo",
 	})
 	sf := New(ctx, &sqlServer.GetExecutorConfig().DistSQLSrv.ServerConfig,
-		TestingAllEventFilter, targets, s.Clock().Now(), nil, changefeedbase.CanHandle{
+		TestingAllEventFilter, false /* ddlEvents */, targets, s.Clock().Now(), nil, changefeedbase.CanHandle{
 			MultipleColumnFamilies: true,
 			VirtualColumns:         true,
 		}).(*schemaFeed)
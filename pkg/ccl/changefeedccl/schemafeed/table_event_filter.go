@@ -422,3 +422,83 @@ func IsOnlyPrimaryIndexChange(e TableEvent) bool {
 func IsRegionalByRowChange(e TableEvent) bool {
 	return classifyTableEvent(e).Contains(tableEventLocalityRegionalByRowChange)
 }
+
+// IsTargetMembershipChange returns true if the event corresponds to a table
+// entering or leaving the target scopes of a database or schema level
+// changefeed. The changefeed must restart to watch the new set of tables.
+func IsTargetMembershipChange(e TableEvent, targets changefeedbase.Targets) bool {
+	if len(targets.Scopes) == 0 {
+		return false
+	}
+	if e.After.Dropped() {
+		return true
+	}
+	isTarget, _ := targets.EachHavingTableID(e.After.GetID(), func(changefeedbase.Target) error { return nil })
+	return !isTarget
+}
+
+// IsVisibleColumnChange returns true if the event changes the name, type or
+// presence of a visible column of the table. If targets specifies column
+// families of the table, only the columns in those families are considered.
+func IsVisibleColumnChange(e TableEvent, targets changefeedbase.Targets) bool {
+	if e.Before == nil || e.After == nil || e.After.Dropped() {
+		return false
+	}
+	before, after := WatchedVisibleColumns(e.Before, targets), WatchedVisibleColumns(e.After, targets)
+	if len(before) != len(after) {
+		return true
+	}
+	for i := range before {
+		if before[i].GetID() != after[i].GetID() ||
+			before[i].GetName() != after[i].GetName() ||
+			!before[i].GetType().Identical(after[i].GetType()) {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchedVisibleColumns returns the visible columns of the table which belong
+// to a column family watched by targets, in the order of the table.
+func WatchedVisibleColumns(
+	desc catalog.TableDescriptor, targets changefeedbase.Targets,
+) []catalog.Column {
+	families := targets.GetSpecifiedColumnFamilies(desc.GetID())
+	if len(families) == 0 {
+		return desc.VisibleColumns()
+	}
+	var watched intsets.Fast
+	_ = desc.ForeachFamily(func(family *descpb.ColumnFamilyDescriptor) error {
+		if _, ok := families[family.Name]; ok {
+			for _, colID := range family.ColumnIDs {
+				watched.Add(int(colID))
+			}
+		}
+		return nil
+	})
+	var res []catalog.Column
+	for _, col := range desc.VisibleColumns() {
+		if watched.Contains(int(col.GetID())) {
+			res = append(res, col)
+		}
+	}
+	return res
+}
+
+// SchemaChangeStatement returns the statement which caused the event, if the
+// schema change is being run by the declarative schema changer.
+func SchemaChangeStatement(e TableEvent) string {
+	for _, desc := range []catalog.TableDescriptor{e.After, e.Before} {
+		if desc == nil || desc.GetDeclarativeSchemaChangerState() == nil {
+			continue
+		}
+		var stmts []string
+		for _, s := range desc.GetDeclarativeSchemaChangerState().RelevantStatements {
+			stmts = append(stmts, s.Statement.Statement)
+		}
+		if len(stmts) > 0 {
+			return strings.Join(stmts, "; ")
+		}
+	}
+	return ""
+}
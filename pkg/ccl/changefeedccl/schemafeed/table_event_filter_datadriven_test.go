@@ -115,7 +115,7 @@ func TestDataDriven(t *testing.T) {
 				cfg := &ts.SQLServer().(*sql.Server).GetExecutorConfig().DistSQLSrv.ServerConfig
 				now := ts.Clock().Now()
 				targets := parseTargets(t, d.Input)
-				f := schemafeed.New(ctx, cfg, schemafeed.TestingAllEventFilter, targets, now, nil, changefeedbase.CanHandle{
+				f := schemafeed.New(ctx, cfg, schemafeed.TestingAllEventFilter, false /* ddlEvents */, targets, now, nil, changefeedbase.CanHandle{
 					MultipleColumnFamilies: true,
 					VirtualColumns:         true,
 				})
@@ -12,8 +12,11 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/schemafeed/schematestutils"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/gogo/protobuf/proto"
@@ -232,6 +235,146 @@ func TestTableEventIsOnlyPrimaryIndexChange(t *testing.T) {
 	}
 }
 
+func TestTableEventIsTargetMembershipChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(seconds int) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: (time.Duration(seconds) * time.Second).Nanoseconds()}
+	}
+	mkTableDesc := schematestutils.MakeTableDesc
+	dropped := func(desc catalog.TableDescriptor) catalog.TableDescriptor {
+		td := desc.TableDesc()
+		td.State = descpb.DescriptorState_DROP
+		return tabledesc.NewBuilder(td).BuildImmutableTable()
+	}
+
+	var scoped changefeedbase.Targets
+	scoped.Add(changefeedbase.Target{TableID: 42, StatementTimeName: "foo"})
+	scoped.Scopes = []jobspb.ChangefeedTargetScope{{DatabaseID: 1}}
+	var unscoped changefeedbase.Targets
+	unscoped.Add(changefeedbase.Target{TableID: 42, StatementTimeName: "foo"})
+
+	for _, c := range []struct {
+		name    string
+		targets changefeedbase.Targets
+		e       TableEvent
+		exp     bool
+	}{
+		{
+			name:    "schema change of a target",
+			targets: scoped,
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 1, 1),
+				After:  mkTableDesc(42, 2, ts(3), 2, 1),
+			},
+			exp: false,
+		},
+		{
+			name:    "target dropped",
+			targets: scoped,
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 1, 1),
+				After:  dropped(mkTableDesc(42, 2, ts(3), 1, 1)),
+			},
+			exp: true,
+		},
+		{
+			name:    "table added to scope",
+			targets: scoped,
+			e: TableEvent{
+				Before: mkTableDesc(43, 1, ts(2), 1, 1),
+				After:  mkTableDesc(43, 1, ts(2), 1, 1),
+			},
+			exp: true,
+		},
+		{
+			name:    "target dropped without scopes",
+			targets: unscoped,
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 1, 1),
+				After:  dropped(mkTableDesc(42, 2, ts(3), 1, 1)),
+			},
+			exp: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equalf(t, c.exp, IsTargetMembershipChange(c.e, c.targets), "event %v", c.e)
+		})
+	}
+}
+
+func TestTableEventIsVisibleColumnChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(seconds int) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: (time.Duration(seconds) * time.Second).Nanoseconds()}
+	}
+	mkTableDesc := schematestutils.MakeTableDesc
+	addColBackfill := schematestutils.AddNewColumnBackfillMutation
+	alterFirstCol := func(
+		desc catalog.TableDescriptor, alter func(col *descpb.ColumnDescriptor),
+	) catalog.TableDescriptor {
+		td := desc.TableDesc()
+		alter(&td.Columns[0])
+		return tabledesc.NewBuilder(td).BuildImmutableTable()
+	}
+
+	for _, c := range []struct {
+		name string
+		e    TableEvent
+		exp  bool
+	}{
+		{
+			name: "column added",
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 1, 1),
+				After:  mkTableDesc(42, 2, ts(3), 2, 1),
+			},
+			exp: true,
+		},
+		{
+			name: "column being backfilled",
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 1, 1),
+				After:  addColBackfill(mkTableDesc(42, 2, ts(3), 1, 1)),
+			},
+			exp: false,
+		},
+		{
+			name: "column renamed",
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 1, 1),
+				After: alterFirstCol(mkTableDesc(42, 2, ts(3), 1, 1), func(col *descpb.ColumnDescriptor) {
+					col.Name = "renamed"
+				}),
+			},
+			exp: true,
+		},
+		{
+			name: "column type changed",
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 1, 1),
+				After: alterFirstCol(mkTableDesc(42, 2, ts(3), 1, 1), func(col *descpb.ColumnDescriptor) {
+					col.Type = types.Int
+				}),
+			},
+			exp: true,
+		},
+		{
+			name: "no column change",
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 2, 1),
+				After:  mkTableDesc(42, 2, ts(3), 2, 1),
+			},
+			exp: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equalf(t, c.exp, IsVisibleColumnChange(c.e, CreateChangefeedTargets(42)), "event %v", c.e)
+		})
+	}
+}
+
 func TestTableEventFilterErrorsWithIncompletePolicy(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
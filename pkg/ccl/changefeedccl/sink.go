@@ -57,6 +57,7 @@ const (
 	sinkTypeCloudstorage
 	sinkTypeSQL
 	sinkTypePulsar
+	sinkTypeGRPC
 )
 
 func (st sinkType) String() string {
@@ -77,6 +78,8 @@ func (st sinkType) String() string {
 		return `sql`
 	case sinkTypePulsar:
 		return `pulsar`
+	case sinkTypeGRPC:
+		return `grpc`
 	default:
 		return `unknown`
 	}
@@ -251,9 +254,13 @@ func getSink(
 			return validateOptionsAndMakeSink(changefeedbase.KafkaValidOptions, func() (Sink, error) {
 				if KafkaV2Enabled.Get(&serverCfg.Settings.SV) {
 					return makeKafkaSinkV2(ctx, &changefeedbase.SinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(),
-						numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
-						serverCfg.Settings, metricsBuilder, kafkaSinkV2Knobs{})
+						opts.IsSet(changefeedbase.OptExactlyOnce), numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg),
+						timeutil.DefaultTimeSource{}, serverCfg.Settings, metricsBuilder, kafkaSinkV2Knobs{})
 				} else {
+					if opts.IsSet(changefeedbase.OptExactlyOnce) {
+						return nil, errors.Errorf(`%s requires %s to be enabled`,
+							changefeedbase.OptExactlyOnce, KafkaV2Enabled.Name())
+					}
 					return makeKafkaSink(ctx, &changefeedbase.SinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(), serverCfg.Settings, metricsBuilder)
 				}
 			})
@@ -274,6 +281,12 @@ func getSink(
 					numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
 					metricsBuilder, serverCfg.Settings)
 			})
+		case isGRPCSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.GRPCValidOptions, func() (Sink, error) {
+				return makeGRPCSink(ctx, &changefeedbase.SinkURL{URL: u}, encodingOpts, opts.GetGRPCConfigJSON(),
+					AllTargets(feedCfg), numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg),
+					timeutil.DefaultTimeSource{}, metricsBuilder, serverCfg.Settings)
+			})
 		case isPubsubSink(u):
 			var testingKnobs *TestingKnobs
 			if knobs, ok := serverCfg.TestingKnobs.Changefeed.(*TestingKnobs); ok {
@@ -284,6 +297,17 @@ func getSink(
 				newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
 				metricsBuilder, serverCfg.Settings, testingKnobs)
 		case isCloudStorageSink(u):
+			if u.Query().Get(changefeedbase.SinkParamTableFormat) == icebergTableFormat {
+				// Iceberg tables are committed when resolved timestamps are emitted.
+				if !opts.IsSet(changefeedbase.OptResolvedTimestamps) {
+					return nil, errors.Errorf(`%s=%s requires the %s option`,
+						changefeedbase.SinkParamTableFormat, icebergTableFormat, changefeedbase.OptResolvedTimestamps)
+				}
+				if feedCfg.Select != `` {
+					return nil, errors.Errorf(`%s=%s is not supported with CDC queries`,
+						changefeedbase.SinkParamTableFormat, icebergTableFormat)
+				}
+			}
 			return validateOptionsAndMakeSink(changefeedbase.CloudStorageValidOptions, func() (Sink, error) {
 				var testingKnobs *TestingKnobs
 				if knobs, ok := serverCfg.TestingKnobs.Changefeed.(*TestingKnobs); ok {
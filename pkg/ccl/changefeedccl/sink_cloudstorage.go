@@ -416,6 +416,31 @@ func makeCloudStorageSink(
 			return nil, pgerror.Wrapf(err, pgcode.Syntax, `parsing %s`, fileSizeParam)
 		}
 	}
+	tableFormat := u.ConsumeParam(changefeedbase.SinkParamTableFormat)
+	commitInterval := defaultIcebergCommitInterval
+	if commitIntervalParam := u.ConsumeParam(changefeedbase.SinkParamCommitInterval); commitIntervalParam != `` {
+		if tableFormat != icebergTableFormat {
+			return nil, errors.Errorf("%s requires %s=%s", changefeedbase.SinkParamCommitInterval,
+				changefeedbase.SinkParamTableFormat, icebergTableFormat)
+		}
+		var err error
+		if commitInterval, err = time.ParseDuration(commitIntervalParam); err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.Syntax, `parsing %s`, commitIntervalParam)
+		}
+		if commitInterval < time.Second {
+			return nil, errors.Errorf("%s must be at least 1s", changefeedbase.SinkParamCommitInterval)
+		}
+	}
+	switch tableFormat {
+	case ``:
+	case icebergTableFormat:
+		if encodingOpts.Format != changefeedbase.OptFormatParquet {
+			return nil, errors.Errorf("%s=%s requires %s=%s", changefeedbase.SinkParamTableFormat,
+				icebergTableFormat, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+		}
+	default:
+		return nil, errors.Errorf("invalid %s of %s", changefeedbase.SinkParamTableFormat, tableFormat)
+	}
 	u.Scheme = strings.TrimPrefix(u.Scheme, `experimental-`)
 	u.Scheme = strings.TrimPrefix(u.Scheme, `file-`)
 
@@ -519,6 +544,16 @@ func makeCloudStorageSink(
 		s.metrics = (*sliMetrics)(nil)
 	}
 
+	if tableFormat == icebergTableFormat {
+		location := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+		icebergSink, err := makeIcebergSink(s, location, commitInterval)
+		if err != nil {
+			return nil, err
+		}
+		s.compression = ""
+		return icebergSink, nil
+	}
+
 	if encodingOpts.Format == changefeedbase.OptFormatParquet {
 		parquetSinkWithEncoder, err := makeParquetCloudStorageSink(s)
 		if err != nil {
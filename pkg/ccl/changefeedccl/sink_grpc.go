@@ -0,0 +1,475 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/cidr"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// errGRPCSinkClosed is returned for batches in flight when the sink is closed.
+var errGRPCSinkClosed = errors.New("grpc sink closed")
+
+func isGRPCSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemeGRPC, changefeedbase.SinkSchemeGRPCS:
+		return true
+	default:
+		return false
+	}
+}
+
+// grpcSinkClient emits batches on an EmitBatches stream of the
+// changefeedpb.ChangefeedSink service. All the IO workers of the batching sink
+// share a single stream; each Flush sends its batch and waits for the batch to
+// be acknowledged. A stream which fails is replaced by the next Flush.
+type grpcSinkClient struct {
+	// ctx is the context of the sink; streams outlive the Flush calls which
+	// open them.
+	ctx      context.Context
+	conn     *grpc.ClientConn
+	client   changefeedpb.ChangefeedSinkClient
+	batchCfg sinkBatchConfig
+
+	// inFlight limits the number of batches which were sent but not yet
+	// acknowledged.
+	inFlight chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		stream *grpcSinkStream
+		nextID uint64
+		closed bool
+	}
+}
+
+var _ SinkClient = (*grpcSinkClient)(nil)
+var _ SinkPayload = (*changefeedpb.SinkBatch)(nil)
+
+// grpcSinkStream is an EmitBatches stream, along with the batches awaiting
+// acknowledgement on it.
+type grpcSinkStream struct {
+	stream changefeedpb.ChangefeedSink_EmitBatchesClient
+	cancel context.CancelFunc
+
+	// sendMu serializes Send calls, which are not safe for concurrent use.
+	sendMu syncutil.Mutex
+
+	mu struct {
+		syncutil.Mutex
+		// pending maps the IDs of the batches in flight to the channels on which
+		// their acknowledgement is delivered.
+		pending map[uint64]chan error
+		// err is set once the stream failed.
+		err error
+	}
+}
+
+func makeGRPCSinkClient(
+	ctx context.Context,
+	u *changefeedbase.SinkURL,
+	batchCfg sinkBatchConfig,
+	maxInFlight int,
+	nm *cidr.NetMetrics,
+) (*grpcSinkClient, error) {
+	creds, err := grpcSinkCredentials(u)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf(`grpc sink requires a host, e.g. %s://localhost:50051`, u.Scheme)
+	}
+
+	dialContext := nm.Wrap((&net.Dialer{}).DialContext, "grpc")
+	dial := func(ctx context.Context, target string) (net.Conn, error) {
+		return dialContext(ctx, "tcp", target)
+	}
+	// The connection is established lazily, on the first stream.
+	conn, err := grpc.DialContext(ctx, u.Host,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(dial),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &grpcSinkClient{
+		ctx:      ctx,
+		conn:     conn,
+		client:   changefeedpb.NewChangefeedSinkClient(conn),
+		batchCfg: batchCfg,
+		inFlight: make(chan struct{}, maxInFlight),
+	}
+	return sc, nil
+}
+
+// grpcSinkCredentials returns the transport credentials for the sink URL.
+// grpcs uses TLS, optionally with a custom CA and a client certificate, while
+// grpc uses plaintext.
+func grpcSinkCredentials(u *changefeedbase.SinkURL) (credentials.TransportCredentials, error) {
+	var skipVerify bool
+	if _, err := u.ConsumeBool(changefeedbase.SinkParamSkipTLSVerify, &skipVerify); err != nil {
+		return nil, err
+	}
+	var caCert, clientCert, clientKey []byte
+	if err := u.DecodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return nil, err
+	}
+	if err := u.DecodeBase64(changefeedbase.SinkParamClientCert, &clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.DecodeBase64(changefeedbase.SinkParamClientKey, &clientKey); err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == changefeedbase.SinkSchemeGRPC {
+		if skipVerify || caCert != nil || clientCert != nil || clientKey != nil {
+			return nil, errors.Errorf(`TLS parameters require the %s scheme`,
+				changefeedbase.SinkSchemeGRPCS)
+		}
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig, err := newTLSConfig(caCert, clientCert, clientKey)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.InsecureSkipVerify = skipVerify
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// getStream returns the current stream, opening a new one if there is none,
+// or if the current one failed.
+func (sc *grpcSinkClient) getStream() (*grpcSinkStream, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.mu.closed {
+		return nil, errGRPCSinkClosed
+	}
+	if s := sc.mu.stream; s != nil && s.failed() == nil {
+		return s, nil
+	}
+
+	ctx, cancel := context.WithCancel(sc.ctx)
+	stream, err := sc.client.EmitBatches(ctx)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "opening grpc sink stream")
+	}
+	s := &grpcSinkStream{stream: stream, cancel: cancel}
+	s.mu.pending = make(map[uint64]chan error)
+	go s.receiveAcks()
+	sc.mu.stream = s
+	return s, nil
+}
+
+// nextBatchID returns the ID of the next batch to send.
+func (sc *grpcSinkClient) nextBatchID() uint64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.mu.nextID++
+	return sc.mu.nextID
+}
+
+// Flush implements the SinkClient interface.
+func (sc *grpcSinkClient) Flush(ctx context.Context, payload SinkPayload) error {
+	batch := payload.(*changefeedpb.SinkBatch)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case sc.inFlight <- struct{}{}:
+	}
+	defer func() { <-sc.inFlight }()
+
+	s, err := sc.getStream()
+	if err != nil {
+		return err
+	}
+
+	// Retried batches are assigned a new ID, so that a late acknowledgement of
+	// a previous attempt is not mistaken for this one.
+	batch.ID = sc.nextBatchID()
+	ackCh, err := s.register(batch.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.send(batch); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		s.unregister(batch.ID)
+		return ctx.Err()
+	case err := <-ackCh:
+		return err
+	}
+}
+
+// register returns the channel on which the acknowledgement of the batch with
+// the specified ID is delivered.
+func (s *grpcSinkStream) register(id uint64) (chan error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.err != nil {
+		return nil, s.mu.err
+	}
+	ch := make(chan error, 1)
+	s.mu.pending[id] = ch
+	return ch, nil
+}
+
+func (s *grpcSinkStream) unregister(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.mu.pending, id)
+}
+
+func (s *grpcSinkStream) send(batch *changefeedpb.SinkBatch) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if err := s.stream.Send(batch); err != nil {
+		// The actual error of the stream is returned by Recv.
+		err = errors.Wrap(err, "sending to grpc sink")
+		s.fail(err)
+		return err
+	}
+	return nil
+}
+
+// receiveAcks delivers acknowledgements to the batches awaiting them until the
+// stream fails.
+func (s *grpcSinkStream) receiveAcks() {
+	for {
+		ack, err := s.stream.Recv()
+		if err != nil {
+			s.fail(errors.Wrap(err, "grpc sink stream failed"))
+			return
+		}
+		var ackErr error
+		if ack.Error != "" {
+			ackErr = errors.Newf("grpc sink rejected batch: %s", ack.Error)
+		}
+		s.mu.Lock()
+		// Batches which gave up waiting are no longer pending; their
+		// acknowledgement is ignored.
+		if ch, ok := s.mu.pending[ack.ID]; ok {
+			delete(s.mu.pending, ack.ID)
+			ch <- ackErr
+		}
+		s.mu.Unlock()
+	}
+}
+
+// fail fails all the batches in flight, and cancels the stream.
+func (s *grpcSinkStream) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.err != nil {
+		return
+	}
+	s.mu.err = err
+	for id, ch := range s.mu.pending {
+		ch <- err
+		delete(s.mu.pending, id)
+	}
+	s.cancel()
+}
+
+// failed returns the error with which the stream failed, if any.
+func (s *grpcSinkStream) failed() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.err
+}
+
+// FlushResolvedPayload implements the SinkClient interface.
+func (sc *grpcSinkClient) FlushResolvedPayload(
+	ctx context.Context,
+	body []byte,
+	forEachTopic func(func(topic string) error) error,
+	retryOpts retry.Options,
+) error {
+	// Resolved messages for all the topics are sent in a single batch.
+	batch := &changefeedpb.SinkBatch{}
+	if err := forEachTopic(func(topic string) error {
+		batch.Messages = append(batch.Messages, changefeedpb.SinkMessage{
+			Topic:    topic,
+			Value:    body,
+			Resolved: true,
+		})
+		return nil
+	}); err != nil {
+		return err
+	}
+	return retry.WithMaxAttempts(ctx, retryOpts, retryOpts.MaxRetries+1, func() error {
+		return sc.Flush(ctx, batch)
+	})
+}
+
+// CheckConnection implements the SinkClient interface.
+func (sc *grpcSinkClient) CheckConnection(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Opening a stream fails unless the server is reachable.
+	stream, err := sc.client.EmitBatches(ctx)
+	if err != nil {
+		return errors.Wrap(err, "connecting to grpc sink")
+	}
+	return stream.CloseSend()
+}
+
+// Close implements the SinkClient interface.
+func (sc *grpcSinkClient) Close() error {
+	sc.mu.Lock()
+	sc.mu.closed = true
+	s := sc.mu.stream
+	sc.mu.stream = nil
+	sc.mu.Unlock()
+
+	if s != nil {
+		s.fail(errGRPCSinkClosed)
+	}
+	return sc.conn.Close()
+}
+
+type grpcBuffer struct {
+	sc       *grpcSinkClient
+	topic    string
+	messages []changefeedpb.SinkMessage
+	numBytes int
+}
+
+var _ BatchBuffer = (*grpcBuffer)(nil)
+
+// Append implements the BatchBuffer interface.
+func (gb *grpcBuffer) Append(ctx context.Context, key []byte, value []byte, _ attributes) {
+	gb.messages = append(gb.messages, changefeedpb.SinkMessage{
+		Topic: gb.topic,
+		Key:   key,
+		Value: value,
+	})
+	gb.numBytes += len(key) + len(value)
+}
+
+// ShouldFlush implements the BatchBuffer interface.
+func (gb *grpcBuffer) ShouldFlush() bool {
+	return shouldFlushBatch(gb.numBytes, len(gb.messages), gb.sc.batchCfg)
+}
+
+// Close implements the BatchBuffer interface.
+func (gb *grpcBuffer) Close() (SinkPayload, error) {
+	return &changefeedpb.SinkBatch{Messages: gb.messages}, nil
+}
+
+// MakeBatchBuffer implements the SinkClient interface.
+func (sc *grpcSinkClient) MakeBatchBuffer(topic string) BatchBuffer {
+	return &grpcBuffer{
+		sc:       sc,
+		topic:    topic,
+		messages: make([]changefeedpb.SinkMessage, 0, sc.batchCfg.Messages),
+	}
+}
+
+func validateGRPCOpts(encodingOpts changefeedbase.EncodingOptions) error {
+	switch encodingOpts.Format {
+	case changefeedbase.OptFormatJSON, changefeedbase.OptFormatAvro,
+		changefeedbase.OptFormatProtobuf, changefeedbase.OptFormatCSV:
+	default:
+		return errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, encodingOpts.Format)
+	}
+	return nil
+}
+
+func makeGRPCSink(
+	ctx context.Context,
+	u *changefeedbase.SinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	targets changefeedbase.Targets,
+	parallelism int,
+	pacerFactory func() *admission.Pacer,
+	source timeutil.TimeSource,
+	mb metricsRecorderBuilder,
+	settings *cluster.Settings,
+) (Sink, error) {
+	if err := validateGRPCOpts(encodingOpts); err != nil {
+		return nil, err
+	}
+
+	m := mb(requiresResourceAccounting)
+
+	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{
+		Flush: sinkBatchConfig{
+			Frequency: jsonDuration(10 * time.Millisecond),
+			Messages:  1000,
+			Bytes:     1 << 20,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, each IO worker may have a batch in flight.
+	maxInFlight := parallelism
+	if s := u.ConsumeParam(changefeedbase.SinkParamMaxInFlight); s != "" {
+		if maxInFlight, err = strconv.Atoi(s); err != nil || maxInFlight <= 0 {
+			return nil, errors.Errorf(`param %s must be a positive integer: %q`,
+				changefeedbase.SinkParamMaxInFlight, s)
+		}
+	}
+
+	topicNamer, err := MakeTopicNamer(targets,
+		WithPrefix(u.ConsumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.ConsumeParam(changefeedbase.SinkParamTopicName)))
+	if err != nil {
+		return nil, err
+	}
+
+	sinkClient, err := makeGRPCSinkClient(ctx, u, batchCfg, maxInFlight, m.netMetrics())
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.RemainingQueryParams(); len(unknownParams) > 0 {
+		_ = sinkClient.Close()
+		return nil, errors.Errorf(
+			`unknown grpc sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	return makeBatchingSink(
+		ctx,
+		sinkTypeGRPC,
+		sinkClient,
+		time.Duration(batchCfg.Frequency),
+		retryOpts,
+		parallelism,
+		topicNamer,
+		pacerFactory,
+		source,
+		m,
+		settings,
+	), nil
+}
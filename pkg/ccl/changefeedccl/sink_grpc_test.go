@@ -0,0 +1,277 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestGRPCSink(
+	t *testing.T, sinkURI string, parallelism int, opts map[string]string,
+) (Sink, error) {
+	u, err := url.Parse(sinkURI)
+	require.NoError(t, err)
+
+	stmtOpts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+		// Speed up test by using faster backoff times.
+		changefeedbase.OptGRPCSinkConfig: `{"Retry":{"Backoff": "5ms"}}`,
+	}
+	for k, v := range opts {
+		stmtOpts[k] = v
+	}
+	o := changefeedbase.MakeStatementOptions(stmtOpts)
+	encodingOpts, err := o.GetEncodingOptions()
+	require.NoError(t, err)
+
+	s, err := makeGRPCSink(context.Background(), &changefeedbase.SinkURL{URL: u}, encodingOpts,
+		o.GetGRPCConfigJSON(), makeChangefeedTargets("foo"), parallelism, nilPacerFactory,
+		timeutil.DefaultTimeSource{}, nilMetricsRecorderBuilder, cluster.MakeTestingClusterSettings())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Dial(); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// expectGRPCMessages waits for the mock sink to accept the expected messages,
+// in order.
+func expectGRPCMessages(
+	t *testing.T, sinkDest *cdctest.MockGRPCSink, expected ...changefeedpb.SinkMessage,
+) {
+	t.Helper()
+	for _, e := range expected {
+		var m *changefeedpb.SinkMessage
+		testutils.SucceedsSoon(t, func() error {
+			if m = sinkDest.Pop(); m == nil {
+				return errors.New("waiting for message")
+			}
+			return nil
+		})
+		require.Equal(t, e, *m)
+	}
+}
+
+func waitForAllocsReleased(t *testing.T, pool *testAllocPool) {
+	t.Helper()
+	testutils.SucceedsSoon(t, func() error {
+		if remaining := pool.used(); remaining != 0 {
+			return errors.Newf("waiting for 0 allocs (%d)", remaining)
+		}
+		return nil
+	})
+}
+
+func TestGRPCSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	topic := makeTopic("foo")
+
+	testSendAndReceive := func(t *testing.T, sinkSrc Sink, sinkDest *cdctest.MockGRPCSink) {
+		var pool testAllocPool
+		require.NoError(t, sinkSrc.EmitRow(ctx, topic, []byte(`[1]`), []byte(`{"after":{"a":1}}`),
+			zeroTS, zeroTS, pool.alloc(), nil))
+		require.NoError(t, sinkSrc.EmitRow(ctx, topic, []byte(`[2]`), []byte(`{"after":null}`),
+			zeroTS, zeroTS, pool.alloc(), nil))
+		require.NoError(t, sinkSrc.Flush(ctx))
+		waitForAllocsReleased(t, &pool)
+		expectGRPCMessages(t, sinkDest,
+			changefeedpb.SinkMessage{Topic: "foo", Key: []byte(`[1]`), Value: []byte(`{"after":{"a":1}}`)},
+			changefeedpb.SinkMessage{Topic: "foo", Key: []byte(`[2]`), Value: []byte(`{"after":null}`)},
+		)
+
+		opts, err := changefeedbase.MakeStatementOptions(map[string]string{}).GetEncodingOptions()
+		require.NoError(t, err)
+		enc, err := makeJSONEncoder(ctx, jsonEncoderOptions{EncodingOptions: opts},
+			getTestingEnrichedSourceProvider(t, opts), makeChangefeedTargets("foo"))
+		require.NoError(t, err)
+		require.NoError(t, sinkSrc.EmitResolvedTimestamp(ctx, enc, hlc.Timestamp{WallTime: 2}))
+		expectGRPCMessages(t, sinkDest,
+			changefeedpb.SinkMessage{Topic: "foo", Value: []byte(`{"resolved":"2.0000000000"}`), Resolved: true},
+		)
+	}
+
+	t.Run("insecure", func(t *testing.T) {
+		sinkDest, err := cdctest.StartMockGRPCSinkInsecure()
+		require.NoError(t, err)
+		defer sinkDest.Close()
+
+		sinkSrc, err := makeTestGRPCSink(t, fmt.Sprintf("grpc://%s", sinkDest.Addr()), 4, nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sinkSrc.Close()) }()
+
+		testSendAndReceive(t, sinkSrc, sinkDest)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		cert, certEncoded, err := cdctest.NewCACertBase64Encoded()
+		require.NoError(t, err)
+		sinkDest, err := cdctest.StartMockGRPCSink(cert, false /* requireClientCert */)
+		require.NoError(t, err)
+		defer sinkDest.Close()
+
+		// The server certificate is not trusted without the CA cert.
+		_, err = makeTestGRPCSink(t, fmt.Sprintf("grpcs://%s", sinkDest.Addr()), 4, nil)
+		require.Error(t, err)
+
+		sinkSrc, err := makeTestGRPCSink(t,
+			fmt.Sprintf("grpcs://%s?ca_cert=%s", sinkDest.Addr(), url.QueryEscape(certEncoded)), 4, nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sinkSrc.Close()) }()
+
+		testSendAndReceive(t, sinkSrc, sinkDest)
+	})
+
+	t.Run("mtls", func(t *testing.T) {
+		cert, certEncoded, err := cdctest.NewCACertBase64Encoded()
+		require.NoError(t, err)
+		sinkDest, err := cdctest.StartMockGRPCSink(cert, true /* requireClientCert */)
+		require.NoError(t, err)
+		defer sinkDest.Close()
+
+		clientCertPEM, clientKeyPEM, err := cdctest.GenerateClientCertAndKey(cert)
+		require.NoError(t, err)
+		params := url.Values{}
+		params.Set(changefeedbase.SinkParamCACert, certEncoded)
+		params.Set(changefeedbase.SinkParamClientCert, base64.StdEncoding.EncodeToString(clientCertPEM))
+		params.Set(changefeedbase.SinkParamClientKey, base64.StdEncoding.EncodeToString(clientKeyPEM))
+
+		sinkSrc, err := makeTestGRPCSink(t,
+			fmt.Sprintf("grpcs://%s?%s", sinkDest.Addr(), params.Encode()), 4, nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sinkSrc.Close()) }()
+
+		testSendAndReceive(t, sinkSrc, sinkDest)
+	})
+
+	t.Run("retry rejected batches", func(t *testing.T) {
+		sinkDest, err := cdctest.StartMockGRPCSinkInsecure()
+		require.NoError(t, err)
+		defer sinkDest.Close()
+
+		sinkSrc, err := makeTestGRPCSink(t, fmt.Sprintf("grpc://%s", sinkDest.Addr()), 4, nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sinkSrc.Close()) }()
+
+		sinkDest.RejectNext(2)
+		var pool testAllocPool
+		require.NoError(t, sinkSrc.EmitRow(ctx, topic, []byte(`[1]`), []byte(`{"after":{"a":1}}`),
+			zeroTS, zeroTS, pool.alloc(), nil))
+		require.NoError(t, sinkSrc.Flush(ctx))
+		require.Equal(t, 3, sinkDest.NumBatches())
+		expectGRPCMessages(t, sinkDest,
+			changefeedpb.SinkMessage{Topic: "foo", Key: []byte(`[1]`), Value: []byte(`{"after":{"a":1}}`)},
+		)
+	})
+
+	t.Run("fail after retries", func(t *testing.T) {
+		sinkDest, err := cdctest.StartMockGRPCSinkInsecure()
+		require.NoError(t, err)
+		defer sinkDest.Close()
+
+		sinkSrc, err := makeTestGRPCSink(t, fmt.Sprintf("grpc://%s", sinkDest.Addr()), 4,
+			map[string]string{changefeedbase.OptGRPCSinkConfig: `{"Retry":{"Max": 2, "Backoff": "5ms"}}`})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sinkSrc.Close()) }()
+
+		sinkDest.RejectNext(100)
+		var pool testAllocPool
+		require.NoError(t, sinkSrc.EmitRow(ctx, topic, []byte(`[1]`), []byte(`{"after":{"a":1}}`),
+			zeroTS, zeroTS, pool.alloc(), nil))
+		require.Regexp(t, "rejected by mock grpc sink", sinkSrc.Flush(ctx))
+	})
+
+	t.Run("flow control", func(t *testing.T) {
+		sinkDest, err := cdctest.StartMockGRPCSinkInsecure()
+		require.NoError(t, err)
+		defer sinkDest.Close()
+
+		// Every message is its own batch, and up to 4 batches may be flushed in
+		// parallel, but only one may be in flight.
+		sinkSrc, err := makeTestGRPCSink(t,
+			fmt.Sprintf("grpc://%s?%s=1", sinkDest.Addr(), changefeedbase.SinkParamMaxInFlight), 4,
+			map[string]string{changefeedbase.OptGRPCSinkConfig: `{"Flush":{"Messages": 1, "Frequency": "1h"}}`})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sinkSrc.Close()) }()
+
+		sinkDest.PauseAcks()
+		var pool testAllocPool
+		for i := 0; i < 10; i++ {
+			require.NoError(t, sinkSrc.EmitRow(ctx, topic, []byte(fmt.Sprintf(`[%d]`, i)),
+				[]byte(`{"after":{}}`), zeroTS, zeroTS, pool.alloc(), nil))
+		}
+		testutils.SucceedsSoon(t, func() error {
+			if sinkDest.InFlight() == 0 {
+				return errors.New("waiting for a batch")
+			}
+			return nil
+		})
+		// Give other batches a chance to be sent, which they must not be.
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, 1, sinkDest.MaxInFlight())
+
+		require.NoError(t, sinkDest.ResumeAcks())
+		require.NoError(t, sinkSrc.Flush(ctx))
+		require.Equal(t, 10, sinkDest.NumBatches())
+		waitForAllocsReleased(t, &pool)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			uri  string
+			opts map[string]string
+			err  string
+		}{
+			{
+				uri: "grpc://localhost:1234?ca_cert=Zm9v",
+				err: "TLS parameters require the grpcs scheme",
+			},
+			{
+				uri: "grpc://localhost:1234?max_in_flight=0",
+				err: "param max_in_flight must be a positive integer",
+			},
+			{
+				uri: "grpc://localhost:1234?foo=bar",
+				err: "unknown grpc sink query parameters: foo",
+			},
+			{
+				uri: "grpc://?max_in_flight=1",
+				err: "grpc sink requires a host",
+			},
+			{
+				uri:  "grpc://localhost:1234",
+				opts: map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatParquet)},
+				err:  "this sink is incompatible with format=parquet",
+			},
+		} {
+			_, err := makeTestGRPCSink(t, tc.uri, 1, tc.opts)
+			require.Regexp(t, tc.err, err)
+		}
+	})
+}
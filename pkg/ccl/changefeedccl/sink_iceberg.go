@@ -0,0 +1,613 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/iceberg"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// icebergTableFormat is the value of the table_format parameter of cloud
+	// storage sinks which write Iceberg tables.
+	icebergTableFormat = "iceberg"
+	// icebergBatchDir is the directory of the sink holding the descriptions of
+	// the batches of rows which have not been committed yet.
+	icebergBatchDir = "_crdb_iceberg_batches"
+	// icebergResolvedProperty is the snapshot summary property holding the
+	// commit timestamp of the snapshot.
+	icebergResolvedProperty = "crdb.resolved"
+	// defaultIcebergCommitInterval is the default interval between the commit
+	// timestamps of an Iceberg table.
+	defaultIcebergCommitInterval = time.Minute
+)
+
+// icebergSink writes the rows of a changefeed to Apache Iceberg tables, one per
+// topic, located in the directory named after the topic. It is used by cloud
+// storage sinks with the table_format=iceberg parameter.
+//
+// Commits happen at commit timestamps, which are the multiples of the commit
+// interval. The snapshot of a commit timestamp holds exactly the state of the
+// table as of that timestamp: each row update is assigned to the earliest
+// commit timestamp at or after its timestamp, and the aggregators write the
+// updates of each commit timestamp to Parquet files (see EncodeAndEmitRow and
+// Flush). For every batch of updates, an aggregator writes a data file holding
+// the latest version of the rows which were not deleted, an equality delete
+// file holding the primary keys of all the updated rows, and a description of
+// the batch in icebergBatchDir.
+//
+// Once a commit timestamp is resolved, every aggregator has flushed all the
+// updates assigned to it, so the frontier adds a snapshot for each of its
+// batches, in the order they were written, to the table of their topic and
+// commits the table (see EmitResolvedTimestamp). As the equality deletes of a
+// snapshot apply to the data files of the previous snapshots, every snapshot
+// replaces the rows updated by its batch. The snapshot summary records the
+// commit timestamp so that batches committed before a restart are not
+// committed again.
+//
+// Columns are identified by their column ID, which is used as their Iceberg
+// field ID, so renamed columns keep their identity. A new schema is added to
+// the table whenever the columns of a table change.
+type icebergSink struct {
+	wrapped        *cloudStorageSink
+	compression    parquet.CompressionCodec
+	commitInterval time.Duration
+	// location is the URI of the sink directory, without its parameters.
+	location string
+
+	// Aggregator state.
+	batches map[icebergBatchKey]*icebergBatch
+	fileID  int64
+
+	// Frontier state.
+	tables    map[string]*iceberg.Table
+	committed hlc.Timestamp
+}
+
+var _ SinkWithEncoder = (*icebergSink)(nil)
+
+// icebergBatchKey identifies the rows buffered by an aggregator which are
+// written to the same files.
+type icebergBatchKey struct {
+	topic   string
+	commit  hlc.Timestamp
+	version descpb.DescriptorVersion
+}
+
+// icebergRow is the latest version of a row buffered by an aggregator.
+type icebergRow struct {
+	datums  tree.Datums
+	deleted bool
+	updated hlc.Timestamp
+}
+
+// icebergBatch buffers the rows of a batch until it is written.
+type icebergBatch struct {
+	fields             []iceberg.Field
+	identifierFieldIDs []int32
+	keyFieldIDs        []int32
+	names              []string
+	types              []*types.T
+	// keyOrds are the ordinals of the key columns in names and types.
+	keyOrds []int
+	// rows are the buffered rows indexed by their formatted primary key.
+	// keys holds the same keys, in insertion order.
+	rows       map[string]icebergRow
+	keys       []string
+	bytes      int64
+	alloc      kvevent.Alloc
+	created    time.Time
+	oldestMVCC hlc.Timestamp
+}
+
+// icebergBatchDescription is the description of a batch read by the
+// frontier.
+type icebergBatchDescription struct {
+	Topic              string                   `json:"topic"`
+	CommitTimestamp    string                   `json:"commit_timestamp"`
+	DescriptorVersion  descpb.DescriptorVersion `json:"descriptor_version"`
+	Fields             []iceberg.Field          `json:"fields"`
+	IdentifierFieldIDs []int32                  `json:"identifier_field_ids,omitempty"`
+	Files              []iceberg.DataFile       `json:"files"`
+}
+
+func makeIcebergSink(
+	wrapped *cloudStorageSink, location string, commitInterval time.Duration,
+) (*icebergSink, error) {
+	// The parquet sink translates the compression option to a parquet codec.
+	parquetSink, err := makeParquetCloudStorageSink(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return &icebergSink{
+		wrapped:        wrapped,
+		compression:    parquetSink.compression,
+		commitInterval: commitInterval,
+		location:       strings.TrimSuffix(location, "/"),
+		batches:        make(map[icebergBatchKey]*icebergBatch),
+		tables:         make(map[string]*iceberg.Table),
+	}, nil
+}
+
+// icebergCommitTimestamp returns the earliest commit timestamp at or after the
+// given timestamp.
+func icebergCommitTimestamp(ts hlc.Timestamp, interval time.Duration) hlc.Timestamp {
+	res := icebergLastCommitTimestamp(ts, interval)
+	if res.Less(ts) {
+		res.WallTime += interval.Nanoseconds()
+	}
+	return res
+}
+
+// icebergLastCommitTimestamp returns the latest commit timestamp at or before
+// the given timestamp.
+func icebergLastCommitTimestamp(ts hlc.Timestamp, interval time.Duration) hlc.Timestamp {
+	return hlc.Timestamp{WallTime: ts.WallTime - ts.WallTime%interval.Nanoseconds()}
+}
+
+// getConcreteType implements the Sink interface.
+func (s *icebergSink) getConcreteType() sinkType {
+	return s.wrapped.getConcreteType()
+}
+
+// Dial implements the Sink interface.
+func (s *icebergSink) Dial() error {
+	return s.wrapped.Dial()
+}
+
+// Close implements the Sink interface.
+func (s *icebergSink) Close() error {
+	for _, b := range s.batches {
+		b.alloc.Release(context.Background())
+	}
+	s.batches = nil
+	return s.wrapped.Close()
+}
+
+// EmitRow does not do anything. It must not be called. It is present so that
+// icebergSink implements the Sink interface.
+func (s *icebergSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+	headers rowHeaders,
+) error {
+	return errors.AssertionFailedf("EmitRow unimplemented by the iceberg sink")
+}
+
+// EncodeAndEmitRow buffers the row in the batch of its topic, schema version
+// and commit timestamp. Only the latest version of each row is kept. Implements
+// the SinkWithEncoder interface.
+func (s *icebergSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	encodingOpts changefeedbase.EncodingOptions,
+	alloc kvevent.Alloc,
+) error {
+	if s.batches == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	name, err := s.wrapped.topicNamer.Name(topic)
+	if err != nil {
+		return err
+	}
+	key := icebergBatchKey{
+		topic:   name,
+		commit:  icebergCommitTimestamp(updated, s.commitInterval),
+		version: topic.GetVersion(),
+	}
+	b, ok := s.batches[key]
+	if !ok {
+		if b, err = newIcebergBatch(updatedRow, mvcc); err != nil {
+			return err
+		}
+		s.batches[key] = b
+	}
+	if err := b.add(updatedRow, updated, mvcc, &alloc); err != nil {
+		return err
+	}
+	if b.bytes > s.wrapped.targetMaxFileSize {
+		s.wrapped.metrics.recordSizeBasedFlush()
+		return s.flushTopic(ctx, name)
+	}
+	return nil
+}
+
+func newIcebergBatch(row cdcevent.Row, mvcc hlc.Timestamp) (*icebergBatch, error) {
+	b := &icebergBatch{
+		rows:       make(map[string]icebergRow),
+		created:    timeutil.Now(),
+		oldestMVCC: mvcc,
+	}
+	keyNames := make(map[string]struct{})
+	if err := row.ForEachKeyColumn().Col(func(col cdcevent.ResultColumn) error {
+		keyNames[col.Name] = struct{}{}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	identifiers := true
+	if err := row.ForAllColumns().Col(func(col cdcevent.ResultColumn) error {
+		if slices.Contains(b.names, col.Name) {
+			return nil
+		}
+		id := int32(col.PGAttributeNum)
+		if id == 0 {
+			return errors.Errorf("column %s of the iceberg table of %s is not a table column",
+				col.Name, row.TableName)
+		}
+		typ, err := iceberg.FieldType(id, col.Typ)
+		if err != nil {
+			return errors.Wrapf(err, "column %s of %s", col.Name, row.TableName)
+		}
+		_, isKey := keyNames[col.Name]
+		if isKey {
+			b.keyOrds = append(b.keyOrds, len(b.names))
+			b.keyFieldIDs = append(b.keyFieldIDs, id)
+			identifiers = identifiers && iceberg.IdentifierType(col.Typ)
+		}
+		b.fields = append(b.fields, iceberg.Field{ID: id, Name: col.Name, Required: isKey, Type: typ})
+		b.names = append(b.names, col.Name)
+		b.types = append(b.types, col.Typ)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if identifiers {
+		b.identifierFieldIDs = b.keyFieldIDs
+	}
+	return b, nil
+}
+
+// add buffers the given version of a row, replacing any earlier version of the
+// same row.
+func (b *icebergBatch) add(
+	row cdcevent.Row, updated, mvcc hlc.Timestamp, alloc *kvevent.Alloc,
+) error {
+	datums := make(tree.Datums, 0, len(b.names))
+	if err := row.ForAllColumns().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		if len(datums) < len(b.names) && b.names[len(datums)] == col.Name {
+			datums = append(datums, d)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(datums) != len(b.names) {
+		return errors.AssertionFailedf("row of %s does not match the columns of its batch", row.TableName)
+	}
+	keyDatums := make(tree.Datums, len(b.keyOrds))
+	for i, ord := range b.keyOrds {
+		keyDatums[i] = datums[ord]
+	}
+	key := tree.AsString(&keyDatums)
+	if existing, ok := b.rows[key]; !ok {
+		b.keys = append(b.keys, key)
+	} else if updated.Less(existing.updated) {
+		// A duplicate of an earlier version of the row.
+		alloc.Release(context.Background())
+		return nil
+	}
+	b.rows[key] = icebergRow{datums: datums, deleted: row.IsDeleted(), updated: updated}
+	b.bytes += alloc.Bytes()
+	b.alloc.Merge(alloc)
+	if mvcc.Less(b.oldestMVCC) {
+		b.oldestMVCC = mvcc
+	}
+	return nil
+}
+
+// Flush implements the Sink interface. It writes all the buffered batches.
+func (s *icebergSink) Flush(ctx context.Context) error {
+	if s.batches == nil {
+		return errors.New(`cannot Flush on a closed sink`)
+	}
+	s.wrapped.metrics.recordFlushRequestCallback()()
+	return s.flushBatches(ctx, func(icebergBatchKey) bool { return true })
+}
+
+// flushTopic writes the buffered batches of the given topic.
+func (s *icebergSink) flushTopic(ctx context.Context, topic string) error {
+	return s.flushBatches(ctx, func(k icebergBatchKey) bool { return k.topic == topic })
+}
+
+// flushBatches writes the buffered batches matching the filter. The batches
+// of a topic are written in commit timestamp and schema version order, so
+// that the frontier adds the later versions of a row after the earlier ones.
+func (s *icebergSink) flushBatches(ctx context.Context, filter func(icebergBatchKey) bool) error {
+	var keys []icebergBatchKey
+	for k := range s.batches {
+		if filter(k) {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b icebergBatchKey) int {
+		if c := cmp.Compare(a.topic, b.topic); c != 0 {
+			return c
+		}
+		if c := a.commit.Compare(b.commit); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.version, b.version)
+	})
+	for _, k := range keys {
+		if err := s.writeBatch(ctx, k, s.batches[k]); err != nil {
+			return err
+		}
+		delete(s.batches, k)
+	}
+	return nil
+}
+
+// writeBatch writes the data file, the equality delete file and the
+// description of a batch.
+func (s *icebergSink) writeBatch(ctx context.Context, k icebergBatchKey, b *icebergBatch) error {
+	defer b.alloc.Release(ctx)
+	defer s.wrapped.metrics.timers().DownstreamClientSend.Start()()
+
+	s.fileID++
+	commit := cloudStorageFormatTime(k.commit)
+	// The written timestamp orders the batches written by different sessions
+	// of the changefeed.
+	name := fmt.Sprintf("%s-%s-%s-%d-%d-%08d", commit,
+		cloudStorageFormatTime(hlc.Timestamp{WallTime: timeutil.Now().UnixNano()}),
+		s.wrapped.jobSessionID, s.wrapped.srcID, s.wrapped.sinkID, s.fileID)
+	desc := icebergBatchDescription{
+		Topic:              k.topic,
+		CommitTimestamp:    k.commit.AsOfSystemTime(),
+		DescriptorVersion:  k.version,
+		Fields:             b.fields,
+		IdentifierFieldIDs: b.identifierFieldIDs,
+	}
+
+	keyNames := make([]string, len(b.keyOrds))
+	keyTypes := make([]*types.T, len(b.keyOrds))
+	for i, ord := range b.keyOrds {
+		keyNames[i] = b.names[ord]
+		keyTypes[i] = b.types[ord]
+	}
+	var data, deletes [][]tree.Datum
+	for _, key := range b.keys {
+		row := b.rows[key]
+		keyDatums := make([]tree.Datum, len(b.keyOrds))
+		for i, ord := range b.keyOrds {
+			keyDatums[i] = row.datums[ord]
+		}
+		deletes = append(deletes, keyDatums)
+		if !row.deleted {
+			data = append(data, row.datums)
+		}
+	}
+
+	var compressedSize int
+	for _, f := range []struct {
+		content iceberg.FileContent
+		suffix  string
+		names   []string
+		types   []*types.T
+		rows    [][]tree.Datum
+	}{
+		{content: iceberg.ContentData, suffix: ".parquet", names: b.names, types: b.types, rows: data},
+		{content: iceberg.ContentEqualityDeletes, suffix: "-deletes.parquet", names: keyNames, types: keyTypes, rows: deletes},
+	} {
+		if len(f.rows) == 0 {
+			continue
+		}
+		buf, err := s.encodeParquet(f.names, f.types, f.rows)
+		if err != nil {
+			return err
+		}
+		file := iceberg.DataFile{
+			Path:            "data/" + name + f.suffix,
+			Content:         f.content,
+			RecordCount:     int64(len(f.rows)),
+			FileSizeInBytes: int64(buf.Len()),
+		}
+		if f.content == iceberg.ContentEqualityDeletes {
+			file.EqualityIDs = b.keyFieldIDs
+		}
+		compressedSize += buf.Len()
+		if err := cloud.WriteFile(ctx, s.wrapped.es, path.Join(k.topic, file.Path), buf); err != nil {
+			return err
+		}
+		desc.Files = append(desc.Files, file)
+	}
+
+	descBytes, err := json.Marshal(desc)
+	if err != nil {
+		return err
+	}
+	descPath := path.Join(icebergBatchDir, commit, name+".json")
+	if log.V(1) {
+		log.Infof(ctx, "writing iceberg batch %s", descPath)
+	}
+	if err := cloud.WriteFile(ctx, s.wrapped.es, descPath, bytes.NewReader(descBytes)); err != nil {
+		return err
+	}
+	s.wrapped.metrics.recordEmittedBatch(b.created, len(b.keys), b.oldestMVCC, int(b.bytes), compressedSize)
+	return nil
+}
+
+func (s *icebergSink) encodeParquet(
+	names []string, typs []*types.T, rows [][]tree.Datum,
+) (*bytes.Buffer, error) {
+	sch, err := parquet.NewSchema(names, typs)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(sch, &buf, parquet.WithCompressionCodec(s.compression))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if err := w.AddRow(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface. It commits the batches
+// of all the commit timestamps at or before the resolved timestamp.
+func (s *icebergSink) EmitResolvedTimestamp(
+	ctx context.Context, _ Encoder, resolved hlc.Timestamp,
+) error {
+	if s.batches == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	defer s.wrapped.metrics.recordResolvedCallback()()
+
+	commitTS := icebergLastCommitTimestamp(resolved, s.commitInterval)
+	if commitTS.LessEq(s.committed) {
+		return nil
+	}
+	bound := cloudStorageFormatTime(commitTS)
+	var paths []string
+	if err := s.wrapped.es.List(ctx, icebergBatchDir+"/", "", func(p string) error {
+		if commit, _, ok := strings.Cut(p, "/"); ok && commit <= bound {
+			paths = append(paths, p)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	var topics []string
+	batches := make(map[string][]icebergBatchDescription)
+	for _, p := range paths {
+		data, err := s.readFile(ctx, path.Join(icebergBatchDir, p))
+		if err != nil {
+			return err
+		}
+		var desc icebergBatchDescription
+		if err := json.Unmarshal(data, &desc); err != nil {
+			return errors.Wrapf(err, "parsing iceberg batch %s", p)
+		}
+		if _, ok := batches[desc.Topic]; !ok {
+			topics = append(topics, desc.Topic)
+		}
+		batches[desc.Topic] = append(batches[desc.Topic], desc)
+	}
+	for _, topic := range topics {
+		if err := s.commitTable(ctx, topic, commitTS, batches[topic]); err != nil {
+			// The table may hold uncommitted snapshots; reload it next time.
+			delete(s.tables, topic)
+			return errors.Wrapf(err, "committing iceberg table %s", topic)
+		}
+	}
+
+	// The batches are now part of their tables.
+	for _, p := range paths {
+		if err := s.wrapped.es.Delete(ctx, path.Join(icebergBatchDir, p)); err != nil {
+			return err
+		}
+	}
+	s.committed = commitTS
+	return nil
+}
+
+// commitTable adds a snapshot for each of the given batches of a topic, in
+// order, and commits the table of the topic.
+func (s *icebergSink) commitTable(
+	ctx context.Context, topic string, commitTS hlc.Timestamp, batches []icebergBatchDescription,
+) error {
+	tbl, ok := s.tables[topic]
+	if !ok {
+		var err error
+		tbl, err = iceberg.LoadTable(ctx, s.wrapped.es, topic, s.location+"/"+topic)
+		if err != nil {
+			return err
+		}
+		s.tables[topic] = tbl
+	}
+	// Batches at or before the commit timestamp of the current snapshot were
+	// committed before the changefeed restarted.
+	var committed hlc.Timestamp
+	if snap := tbl.CurrentSnapshot(); snap != nil {
+		var err error
+		if committed, err = hlc.ParseHLC(snap.Summary[icebergResolvedProperty]); err != nil {
+			return errors.Wrapf(err, "parsing %s of snapshot %d", icebergResolvedProperty, snap.SnapshotID)
+		}
+	}
+
+	var added bool
+	var version descpb.DescriptorVersion
+	currentSchemaID := -1
+	for _, b := range batches {
+		ts, err := hlc.ParseHLC(b.CommitTimestamp)
+		if err != nil {
+			return err
+		}
+		if ts.LessEq(committed) {
+			continue
+		}
+		schemaID, err := tbl.AddSchema(b.Fields, b.IdentifierFieldIDs)
+		if err != nil {
+			return err
+		}
+		if b.DescriptorVersion >= version {
+			version, currentSchemaID = b.DescriptorVersion, schemaID
+		}
+		if _, err := tbl.AddSnapshot(ctx, schemaID, b.Files, map[string]string{
+			icebergResolvedProperty: commitTS.AsOfSystemTime(),
+		}); err != nil {
+			return err
+		}
+		added = true
+	}
+	if !added {
+		return nil
+	}
+	if err := tbl.SetCurrentSchema(currentSchemaID); err != nil {
+		return err
+	}
+	if log.V(1) {
+		log.Infof(ctx, "committing iceberg table %s at %s", topic, commitTS)
+	}
+	return tbl.Commit(ctx)
+}
+
+func (s *icebergSink) readFile(ctx context.Context, p string) ([]byte, error) {
+	r, _, err := s.wrapped.es.ReadFile(ctx, p, cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close(ctx)
+	return ioctx.ReadAll(ctx, r)
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/blobs"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/iceberg"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIcebergCommitTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sec := func(s int64, logical int32) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: s * int64(time.Second), Logical: logical}
+	}
+	for _, tc := range []struct {
+		ts         hlc.Timestamp
+		commit     hlc.Timestamp
+		lastCommit hlc.Timestamp
+	}{
+		{ts: sec(10, 0), commit: sec(10, 0), lastCommit: sec(10, 0)},
+		{ts: sec(10, 1), commit: sec(20, 0), lastCommit: sec(10, 0)},
+		{ts: sec(11, 0), commit: sec(20, 0), lastCommit: sec(10, 0)},
+		{ts: sec(19, 5), commit: sec(20, 0), lastCommit: sec(10, 0)},
+	} {
+		require.Equal(t, tc.commit, icebergCommitTimestamp(tc.ts, 10*time.Second), tc.ts)
+		require.Equal(t, tc.lastCommit, icebergLastCommitTimestamp(tc.ts, 10*time.Second), tc.ts)
+	}
+}
+
+func TestIcebergSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	externalIODir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+	settings := cluster.MakeTestingClusterSettings()
+	clientFactory := blobs.TestBlobServiceClient(externalIODir)
+	externalStorageFromURI := func(
+		ctx context.Context, uri string, user username.SQLUsername, opts ...cloud.ExternalStorageOption,
+	) (cloud.ExternalStorage, error) {
+		return cloud.ExternalStorageFromURI(ctx, uri, base.ExternalIODirConfig{}, settings,
+			clientFactory, user, nil /* db */, nil /* limiters */, cloud.NilMetrics, opts...)
+	}
+	opts := changefeedbase.EncodingOptions{
+		Format:   changefeedbase.OptFormatParquet,
+		Envelope: changefeedbase.OptEnvelopeWrapped,
+	}
+	makeSink := func(t *testing.T, params map[string]string) (Sink, error) {
+		u := sinkURI(t, unlimitedFileSize)
+		for k, v := range params {
+			u.AddParam(k, v)
+		}
+		return makeCloudStorageSink(ctx, u, 1, settings, opts, nil, /* timestampOracle */
+			externalStorageFromURI, username.RootUserName(), nil, nil)
+	}
+	icebergParams := map[string]string{
+		changefeedbase.SinkParamTableFormat:    icebergTableFormat,
+		changefeedbase.SinkParamCommitInterval: "1s",
+	}
+
+	desc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	topic := makeTopic(`foo`)
+	ts := func(ms int64) hlc.Timestamp { return hlc.Timestamp{WallTime: ms * int64(time.Millisecond)} }
+	emit := func(t *testing.T, s Sink, a int, b string, deleted bool, updated hlc.Timestamp) {
+		row := rowenc.EncDatumRow{rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(a)))}
+		if !deleted {
+			row = append(row, rowenc.DatumToEncDatum(types.String, tree.NewDString(b)))
+		} else {
+			row = append(row, rowenc.DatumToEncDatum(types.String, tree.DNull))
+		}
+		require.NoError(t, s.(SinkWithEncoder).EncodeAndEmitRow(ctx,
+			cdcevent.TestingMakeEventRow(desc, 0, row, deleted), cdcevent.Row{},
+			topic, updated, updated, opts, kvevent.Alloc{}))
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := makeSink(t, map[string]string{changefeedbase.SinkParamTableFormat: "delta"})
+		require.ErrorContains(t, err, "invalid table_format of delta")
+		_, err = makeSink(t, map[string]string{changefeedbase.SinkParamCommitInterval: "1m"})
+		require.ErrorContains(t, err, "commit_interval requires table_format=iceberg")
+		_, err = makeSink(t, map[string]string{
+			changefeedbase.SinkParamTableFormat:    icebergTableFormat,
+			changefeedbase.SinkParamCommitInterval: "1ms",
+		})
+		require.ErrorContains(t, err, "commit_interval must be at least 1s")
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		agg, err := makeSink(t, icebergParams)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, agg.Close()) }()
+		frontier, err := makeSink(t, icebergParams)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, frontier.Close()) }()
+		es := frontier.(*icebergSink).wrapped.es
+
+		emit(t, agg, 1, "x", false, ts(500))
+		emit(t, agg, 2, "x", false, ts(700))
+		emit(t, agg, 2, "y", false, ts(800))
+		emit(t, agg, 1, "y", false, ts(1500))
+		emit(t, agg, 2, "", true, ts(1600))
+		require.NoError(t, agg.Flush(ctx))
+
+		loadSnapshots := func() []iceberg.Snapshot {
+			tbl, err := iceberg.LoadTable(ctx, es, "foo", frontier.(*icebergSink).location+"/foo")
+			require.NoError(t, err)
+			return tbl.Snapshots()
+		}
+
+		// Nothing is committed before the first commit timestamp is resolved.
+		require.NoError(t, frontier.EmitResolvedTimestamp(ctx, nil, ts(900)))
+		require.Empty(t, loadSnapshots())
+
+		require.NoError(t, frontier.EmitResolvedTimestamp(ctx, nil, ts(1200)))
+		snapshots := loadSnapshots()
+		require.Len(t, snapshots, 1)
+		require.Equal(t, ts(1000).AsOfSystemTime(), snapshots[0].Summary[icebergResolvedProperty])
+		require.Equal(t, "2", snapshots[0].Summary["added-records"])
+		require.Equal(t, "2", snapshots[0].Summary["added-equality-deletes"])
+
+		require.NoError(t, frontier.EmitResolvedTimestamp(ctx, nil, ts(2500)))
+		snapshots = loadSnapshots()
+		require.Len(t, snapshots, 2)
+		require.Equal(t, ts(2000).AsOfSystemTime(), snapshots[1].Summary[icebergResolvedProperty])
+		require.Equal(t, "1", snapshots[1].Summary["added-records"])
+		require.Equal(t, "2", snapshots[1].Summary["added-equality-deletes"])
+
+		// The descriptions of the committed batches are removed.
+		var batches []string
+		require.NoError(t, es.List(ctx, icebergBatchDir+"/", "", func(p string) error {
+			batches = append(batches, p)
+			return nil
+		}))
+		require.Empty(t, batches)
+
+		// A batch committed before a restart is not committed again.
+		emit(t, agg, 1, "y", false, ts(1500))
+		require.NoError(t, agg.Flush(ctx))
+		restarted, err := makeSink(t, icebergParams)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, restarted.Close()) }()
+		require.NoError(t, restarted.EmitResolvedTimestamp(ctx, nil, ts(2500)))
+		require.Len(t, loadSnapshots(), 2)
+	})
+}
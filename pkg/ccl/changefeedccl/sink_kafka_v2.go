@@ -13,6 +13,7 @@ import (
 	"hash/fnv"
 	"io"
 	"net"
+	"slices"
 	"strings"
 	"time"
 
@@ -45,6 +46,20 @@ type kafkaSinkClientV2 struct {
 	canTryResizing bool
 	recordResize   func(numRecords int64)
 
+	// clientOpts are the options kgo clients are created with, apart from the
+	// ones that depend on whether the client is transactional.
+	clientOpts []kgo.Opt
+	// controlTopic is the topic that the markers of transactions are committed
+	// to. It is only set if the changefeed was created with the exactly_once
+	// option.
+	controlTopic string
+	// transactionalID is set once the client emits messages in transactions.
+	transactionalID string
+
+	// onRejected, if set, is called with messages that are too large to be
+	// accepted by the broker instead of failing the flush.
+	onRejected rejectedMessageHandler
+
 	topicsForConnectionCheck []string
 
 	// we need to fetch and keep track of this ourselves since kgo doesnt expose metadata to us
@@ -67,13 +82,11 @@ func newKafkaSinkClientV2(
 	knobs kafkaSinkV2Knobs,
 	mb metricsRecorderBuilder,
 	topicsForConnectionCheck []string,
+	controlTopic string,
 ) (*kafkaSinkClientV2, error) {
 	bootstrapBrokers := strings.Split(bootstrapAddrsStr, `,`)
 
 	baseOpts := []kgo.Opt{
-		// Disable idempotency to maintain parity with the v1 sink and not add surface area for unknowns.
-		kgo.DisableIdempotentWrite(),
-
 		kgo.SeedBrokers(bootstrapBrokers...),
 		kgo.WithLogger(kgoLogAdapter{ctx: ctx}),
 		kgo.RecordPartitioner(newKgoChangefeedPartitioner()),
@@ -102,42 +115,61 @@ func newKafkaSinkClientV2(
 		}
 	}
 
-	clientOpts = append(baseOpts, clientOpts...)
-
-	var client KafkaClientV2
-	var adminClient KafkaAdminClientV2
-	var err error
-
-	if knobs.OverrideClient != nil {
-		client, adminClient = knobs.OverrideClient(clientOpts)
-	} else {
-		client, err = kgo.NewClient(clientOpts...)
-		if err != nil {
-			return nil, err
-		}
-		adminClient = kadm.NewClient(client.(*kgo.Client))
-	}
-
 	c := &kafkaSinkClientV2{
-		client:                   client,
-		adminClient:              adminClient,
 		knobs:                    knobs,
 		batchCfg:                 batchCfg,
 		canTryResizing:           changefeedbase.BatchReductionRetryEnabled.Get(&settings.SV),
 		recordResize:             recordResize,
+		clientOpts:               append(baseOpts, clientOpts...),
+		controlTopic:             controlTopic,
 		topicsForConnectionCheck: topicsForConnectionCheck,
 	}
 	c.metadataMu.allTopicPartitions = make(map[string][]int32)
 
+	// Disable idempotency to maintain parity with the v1 sink and not add
+	// surface area for unknowns. Transactional clients, which require it, are
+	// only created once the sink switches to emitting messages in
+	// transactions.
+	var err error
+	c.client, c.adminClient, err = c.newClient(kgo.DisableIdempotentWrite())
+	if err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
+// newClient creates a kgo client with the sink's options followed by the
+// given ones.
+func (k *kafkaSinkClientV2) newClient(
+	opts ...kgo.Opt,
+) (KafkaClientV2, KafkaAdminClientV2, error) {
+	opts = append(slices.Clip(k.clientOpts), opts...)
+	if k.knobs.OverrideClient != nil {
+		client, adminClient := k.knobs.OverrideClient(opts)
+		return client, adminClient, nil
+	}
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, kadm.NewClient(client), nil
+}
+
 // Close implements SinkClient.
 func (k *kafkaSinkClientV2) Close() error {
 	k.client.Close()
 	return nil
 }
 
+var _ sinkWithRejectedMessageHandler = (*kafkaSinkClientV2)(nil)
+
+// setRejectedMessageHandler implements the sinkWithRejectedMessageHandler
+// interface.
+func (k *kafkaSinkClientV2) setRejectedMessageHandler(h rejectedMessageHandler) {
+	k.onRejected = h
+}
+
 // Flush implements SinkClient. Does not retry -- retries will be handled either by kafka or ParallelIO.
 func (k *kafkaSinkClientV2) Flush(ctx context.Context, payload SinkPayload) (retErr error) {
 	msgs := payload.([]*kgo.Record)
@@ -176,6 +208,16 @@ func (k *kafkaSinkClientV2) Flush(ctx context.Context, payload SinkPayload) (ret
 						"Kafka message too large: key=%s size=%d mvcc=%s",
 						string(msg.Key), len(msg.Key)+len(msg.Value), ts,
 					)
+					if topic, ok := msg.Context.Value(topicDescriptorKey{}).(TopicDescriptor); ok && k.onRejected != nil {
+						return k.onRejected(ctx, rejectedMessage{
+							topic:     topic,
+							topicName: msg.Topic,
+							key:       msg.Key,
+							value:     msg.Value,
+							mvcc:      ts,
+							reason:    err,
+						})
+					}
 				}
 				return err
 			}
@@ -277,7 +319,9 @@ func (k *kafkaSinkClientV2) MakeBatchBuffer(topic string) BatchBuffer {
 }
 
 func (k *kafkaSinkClientV2) shouldTryResizing(err error, msgs []*kgo.Record) bool {
-	if !k.canTryResizing || err == nil || len(msgs) < 2 {
+	// Splitting the batch is also how a message that is too large is isolated
+	// so that it can be handed to the rejected message handler.
+	if !(k.canTryResizing || k.onRejected != nil) || err == nil || len(msgs) < 2 {
 		return false
 	}
 	// NOTE: This is what the v1 sink checks for, but I'm not convinced it's right. kerr.RecordListTooLarge sounds more like what we want.
@@ -290,13 +334,23 @@ func (k *kafkaSinkClientV2) shouldTryResizing(err error, msgs []*kgo.Record) boo
 type KafkaClientV2 interface {
 	ProduceSync(ctx context.Context, msgs ...*kgo.Record) kgo.ProduceResults
 	Close()
+
+	// The following are used by changefeeds created with the exactly_once
+	// option, to emit messages in transactions and read back the markers
+	// committed with them.
+	ProducerID(ctx context.Context) (int64, int16, error)
+	BeginTransaction() error
+	EndTransaction(ctx context.Context, commit kgo.TransactionEndTry) error
+	PollFetches(ctx context.Context) kgo.Fetches
 }
 
 // KafkaAdminClientV2 is a small interface restricting the functionality in
 // *kadm.Client. It's used to list topics so we can iterate over all partitions
-// to flush resolved messages.
+// to flush resolved messages, and to find the end of the control topic of
+// changefeeds created with the exactly_once option.
 type KafkaAdminClientV2 interface {
 	ListTopics(ctx context.Context, topics ...string) (kadm.TopicDetails, error)
+	ListCommittedOffsets(ctx context.Context, topics ...string) (kadm.ListedOffsets, error)
 }
 
 type kafkaSinkV2Knobs struct {
@@ -316,6 +370,8 @@ type kafkaBuffer struct {
 
 type mvccTSKey struct{}
 
+type topicDescriptorKey struct{}
+
 func (b *kafkaBuffer) Append(ctx context.Context, key []byte, value []byte, attrs attributes) {
 	// HACK: kafka sink v1 encodes nil keys as sarama.ByteEncoder(key) which is != nil, and unit tests rely on this.
 	// So do something equivalent.
@@ -329,6 +385,9 @@ func (b *kafkaBuffer) Append(ctx context.Context, key []byte, value []byte, attr
 	}
 
 	rctx := context.WithValue(ctx, mvccTSKey{}, attrs.mvcc)
+	if attrs.topic != nil {
+		rctx = context.WithValue(rctx, topicDescriptorKey{}, attrs.topic)
+	}
 
 	b.messages = append(b.messages, &kgo.Record{Key: key, Value: value, Topic: b.topic, Headers: headers, Context: rctx})
 	b.byteCount += len(value)
@@ -349,6 +408,7 @@ func makeKafkaSinkV2(
 	u *changefeedbase.SinkURL,
 	targets changefeedbase.Targets,
 	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	exactlyOnce bool,
 	parallelism int,
 	pacerFactory func() *admission.Pacer,
 	timeSource timeutil.TimeSource,
@@ -374,6 +434,18 @@ func makeKafkaSinkV2(
 	if schemaTopic := u.ConsumeParam(changefeedbase.SinkParamSchemaTopic); schemaTopic != `` {
 		return nil, errors.Errorf(`%s is not yet supported`, changefeedbase.SinkParamSchemaTopic)
 	}
+	controlTopic := u.ConsumeParam(changefeedbase.SinkParamControlTopic)
+	if exactlyOnce {
+		if controlTopic == `` {
+			controlTopic = defaultControlTopic
+		}
+		if err := validateExactlyOnceConfig(jsonConfig); err != nil {
+			return nil, err
+		}
+	} else if controlTopic != `` {
+		return nil, errors.Errorf(`%s is only usable with %s`,
+			changefeedbase.SinkParamControlTopic, changefeedbase.OptExactlyOnce)
+	}
 
 	clientOpts, err := buildKgoConfig(ctx, u, jsonConfig, mb(true).netMetrics())
 	if err != nil {
@@ -394,7 +466,8 @@ func makeKafkaSinkV2(
 	}
 
 	topicsForConnectionCheck := topicNamer.DisplayNamesSlice()
-	client, err := newKafkaSinkClientV2(ctx, clientOpts, batchCfg, u.Host, settings, knobs, mb, topicsForConnectionCheck)
+	client, err := newKafkaSinkClientV2(ctx, clientOpts, batchCfg, u.Host, settings, knobs, mb,
+		topicsForConnectionCheck, controlTopic)
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -18,11 +19,14 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/mocks"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -128,7 +132,8 @@ func TestKafkaSinkClientV2_Resize(t *testing.T) {
 				[]byte("k1"),
 				[]byte(strconv.Itoa(i)),
 				attributes{
-					mvcc: hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
+					mvcc:  hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
+					topic: noTopic{},
 				},
 			)
 		}
@@ -196,6 +201,37 @@ func TestKafkaSinkClientV2_Resize(t *testing.T) {
 		require.NoError(t, fx.sink.Flush(fx.ctx, payload))
 		require.Len(t, gotRecordValues, 100)
 	})
+
+	t.Run("too large message is handed to rejected message handler", func(t *testing.T) {
+		fx, payload, _ := setup(t, false)
+
+		var rejected []rejectedMessage
+		fx.sink.setRejectedMessageHandler(func(ctx context.Context, m rejectedMessage) error {
+			rejected = append(rejected, m)
+			return nil
+		})
+
+		prErr := kgo.ProduceResults{kgo.ProduceResult{Err: fmt.Errorf("..: %w", kerr.MessageTooLarge)}}
+		gotRecordValues := make(map[string]struct{})
+		fx.kc.EXPECT().ProduceSync(fx.ctx, gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, records ...*kgo.Record) kgo.ProduceResults {
+			for _, r := range records {
+				if string(r.Value) == "13" {
+					return prErr
+				}
+			}
+			for _, r := range records {
+				gotRecordValues[string(r.Value)] = struct{}{}
+			}
+			return kgo.ProduceResults{}
+		})
+		require.NoError(t, fx.sink.Flush(fx.ctx, payload))
+		require.Len(t, gotRecordValues, 99)
+		require.Len(t, rejected, 1)
+		require.Equal(t, "13", string(rejected[0].value))
+		require.Equal(t, "t", rejected[0].topicName)
+		require.False(t, rejected[0].mvcc.IsEmpty())
+		require.ErrorIs(t, rejected[0].reason, kerr.MessageTooLarge)
+	})
 }
 
 // These are really tests of the TopicNamer and our configuration of it.
@@ -561,6 +597,104 @@ func TestKafkaSinkClientV2_CompressionOpts(t *testing.T) {
 
 }
 
+func TestKafkaSinkClientV2_Transactions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const id = "crdb-changefeed-1-1"
+	marker := func(ts int64) jobspb.ResolvedSpans {
+		return jobspb.ResolvedSpans{ResolvedSpans: []jobspb.ResolvedSpan{{
+			Span:      roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("b")},
+			Timestamp: hlc.Timestamp{WallTime: ts},
+		}}}
+	}
+	markerRecord := func(key string, offset int64, m jobspb.ResolvedSpans) *kgo.Record {
+		value, err := protoutil.Marshal(&m)
+		require.NoError(t, err)
+		return &kgo.Record{Topic: defaultControlTopic, Key: []byte(key), Value: value, Offset: offset}
+	}
+
+	t.Run("messages are committed with the marker", func(t *testing.T) {
+		fx := newKafkaSinkV2Fx(t, withExactlyOnce())
+		defer fx.close()
+
+		buf := fx.sink.MakeBatchBuffer("t")
+		buf.Append(context.Background(), []byte("k1"), []byte("v1"), attributes{})
+		payload, err := buf.Close()
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			fx.kc.EXPECT().ProducerID(fx.ctx).Times(1).Return(int64(1), int16(0), nil),
+			fx.kc.EXPECT().BeginTransaction().Times(1).Return(nil),
+			fx.kc.EXPECT().Close().Times(1),
+			fx.kc.EXPECT().ProduceSync(fx.ctx, payload.([]*kgo.Record)).Times(1).Return(nil),
+			fx.kc.EXPECT().ProduceSync(fx.ctx, fnMatcher(func(arg any) bool {
+				rec := arg.(*kgo.Record)
+				var m jobspb.ResolvedSpans
+				return rec.Topic == defaultControlTopic && string(rec.Key) == id &&
+					protoutil.Unmarshal(rec.Value, &m) == nil && reflect.DeepEqual(m, marker(42))
+			})).Times(1).Return(nil),
+			fx.kc.EXPECT().EndTransaction(fx.ctx, kgo.TryCommit).Times(1).Return(nil),
+			fx.kc.EXPECT().BeginTransaction().Times(1).Return(nil),
+		)
+
+		require.NoError(t, fx.sink.beginTransactions(fx.ctx, id))
+		require.NoError(t, fx.sink.Flush(fx.ctx, payload))
+		require.NoError(t, fx.sink.commitTransaction(fx.ctx, marker(42)))
+	})
+
+	t.Run("the last markers are recovered and their producers fenced", func(t *testing.T) {
+		fx := newKafkaSinkV2Fx(t, withExactlyOnce())
+		defer fx.close()
+		fx.kc.EXPECT().Close().AnyTimes()
+
+		offsets := kadm.ListedOffsets{defaultControlTopic: {
+			0: {Topic: defaultControlTopic, Partition: 0, Offset: 3},
+		}}
+		fetches := kgo.Fetches{{Topics: []kgo.FetchTopic{{
+			Topic: defaultControlTopic,
+			Partitions: []kgo.FetchPartition{{
+				Partition: 0,
+				Records: []*kgo.Record{
+					markerRecord(id, 0, marker(1)),
+					markerRecord("crdb-changefeed-2-1", 1, marker(2)),
+					markerRecord(id, 2, marker(3)),
+				},
+			}},
+		}}}}
+		fx.ac.EXPECT().ListCommittedOffsets(fx.ctx, defaultControlTopic).Times(2).Return(offsets, nil)
+		fx.kc.EXPECT().PollFetches(fx.ctx).Times(2).Return(fetches)
+		fx.kc.EXPECT().ProducerID(fx.ctx).Times(1).Return(int64(1), int16(1), nil)
+
+		markers, err := fx.sink.recoverTransactions(fx.ctx, transactionalIDPrefix(1))
+		require.NoError(t, err)
+		require.Len(t, markers, 1)
+		require.Equal(t, marker(3), markers[0])
+	})
+
+	t.Run("missing control topic", func(t *testing.T) {
+		fx := newKafkaSinkV2Fx(t, withExactlyOnce())
+		defer fx.close()
+
+		offsets := kadm.ListedOffsets{defaultControlTopic: {
+			-1: {Topic: defaultControlTopic, Partition: -1, Err: kerr.UnknownTopicOrPartition},
+		}}
+		fx.ac.EXPECT().ListCommittedOffsets(fx.ctx, defaultControlTopic).Times(1).Return(offsets, nil)
+
+		markers, err := fx.sink.recoverTransactions(fx.ctx, transactionalIDPrefix(1))
+		require.NoError(t, err)
+		require.Empty(t, markers)
+	})
+
+	t.Run("required acks must be all", func(t *testing.T) {
+		var createErr error
+		fx := newKafkaSinkV2Fx(t, withExactlyOnce(), withJSONConfig(`{"RequiredAcks": "ONE"}`),
+			withCreateClientErrorCb(func(err error) { createErr = err }))
+		defer fx.close()
+		require.ErrorContains(t, createErr, "exactly_once requires RequiredAcks to be ALL")
+	})
+}
+
 func TestKafkaSinkClientV2_ErrorsEventually(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -629,6 +763,7 @@ type kafkaSinkV2Fx struct {
 	additionalKOpts     []kgo.Opt
 	createClientErrorCb func(error)
 	uri                 string
+	exactlyOnce         bool
 
 	sink *kafkaSinkClientV2
 	bs   *batchingSink
@@ -684,6 +819,12 @@ func withKOptsClient(kOpts []kgo.Opt) fxOpt {
 	}
 }
 
+func withExactlyOnce() fxOpt {
+	return func(fx *kafkaSinkV2Fx) {
+		fx.exactlyOnce = true
+	}
+}
+
 func withCreateClientErrorCb(cb func(error)) fxOpt {
 	return func(fx *kafkaSinkV2Fx) {
 		fx.createClientErrorCb = cb
@@ -725,8 +866,13 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 		uri = fx.uri
 	}
 
+	var controlTopic string
+	if fx.exactlyOnce {
+		controlTopic = defaultControlTopic
+	}
+
 	var err error
-	fx.sink, err = newKafkaSinkClientV2(ctx, fx.additionalKOpts, fx.batchConfig, uri, settings, knobs, nilMetricsRecorderBuilder, nil)
+	fx.sink, err = newKafkaSinkClientV2(ctx, fx.additionalKOpts, fx.batchConfig, uri, settings, knobs, nilMetricsRecorderBuilder, nil, controlTopic)
 	if err != nil && fx.createClientErrorCb != nil {
 		fx.createClientErrorCb(err)
 		return fx
@@ -747,7 +893,7 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 	}
 	u.RawQuery = q.Encode()
 
-	bs, err := makeKafkaSinkV2(ctx, &changefeedbase.SinkURL{URL: u}, targets, fx.sinkJSONConfig, 1, nilPacerFactory, timeutil.DefaultTimeSource{}, settings, nilMetricsRecorderBuilder, knobs)
+	bs, err := makeKafkaSinkV2(ctx, &changefeedbase.SinkURL{URL: u}, targets, fx.sinkJSONConfig, fx.exactlyOnce, 1, nilPacerFactory, timeutil.DefaultTimeSource{}, settings, nilMetricsRecorderBuilder, knobs)
 	if err != nil && fx.createClientErrorCb != nil {
 		fx.createClientErrorCb(err)
 		return fx
@@ -0,0 +1,246 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// A changefeed created with the exactly_once option emits the messages of each
+// aggregator to Kafka in transactions. Each aggregator uses a transactional ID
+// derived from the job ID and the SQL instance it runs on. When the aggregator
+// forwards its resolved spans to the change frontier, it first commits the
+// messages it flushed along with a marker message, holding those resolved
+// spans, produced to a control topic and keyed by the transactional ID. Rows
+// are held back until they are resolved (see txnGroupBuffer), so a committed
+// transaction holds exactly the messages of the rows at or below the resolved
+// spans of its marker.
+//
+// When the changefeed starts, the coordinator reads the last marker committed
+// for each transactional ID of the job, fences the producers using those IDs,
+// which aborts their open transactions, and starts the changefeed from the
+// resolved spans of the markers rather than from the possibly older job
+// checkpoint. Consumers reading with the read_committed isolation level
+// therefore see every message exactly once.
+//
+// This only holds for messages of rows that are resolved through the marker
+// spans. Resolved timestamp messages are emitted outside of transactions, and
+// the rows of an interrupted initial scan or schema change backfill are
+// emitted again when it restarts, so exactly_once cannot be combined with the
+// resolved option, an initial scan or schema_change_policy=backfill.
+
+// defaultControlTopic is the topic that transaction markers are committed to
+// if the control_topic sink parameter is not set.
+const defaultControlTopic = `crdb_changefeed_control`
+
+// validateExactlyOnceConfig returns an error if the kafka_sink_config is not
+// compatible with emitting messages in transactions.
+func validateExactlyOnceConfig(jsonConfig changefeedbase.SinkSpecificJSONConfig) error {
+	sinkCfg, err := getSaramaConfig(jsonConfig)
+	if err != nil {
+		return errors.Wrapf(err,
+			"failed to parse sink config; check %s option", changefeedbase.OptKafkaSinkConfig)
+	}
+	switch strings.ToUpper(sinkCfg.RequiredAcks) {
+	case ``, `ALL`, `-1`:
+		return nil
+	default:
+		return errors.Errorf(`%s requires RequiredAcks to be ALL, got %s`,
+			changefeedbase.OptExactlyOnce, sinkCfg.RequiredAcks)
+	}
+}
+
+// transactionalOpts returns the options of a client producing with the given
+// transactional ID.
+func transactionalOpts(transactionalID string) []kgo.Opt {
+	return []kgo.Opt{
+		kgo.TransactionalID(transactionalID),
+		// Transactions rely on idempotent writes, which require acks from all
+		// in-sync replicas.
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+	}
+}
+
+var _ sinkWithTransactions = (*kafkaSinkClientV2)(nil)
+
+// beginTransactions implements the sinkWithTransactions interface. It replaces
+// the client of the sink with a transactional one.
+func (k *kafkaSinkClientV2) beginTransactions(ctx context.Context, transactionalID string) error {
+	if k.controlTopic == `` {
+		return errors.AssertionFailedf("kafka sink was not created with %s", changefeedbase.OptExactlyOnce)
+	}
+	client, adminClient, err := k.newClient(transactionalOpts(transactionalID)...)
+	if err != nil {
+		return err
+	}
+	// Initializing the producer ID fences any other producer using the same
+	// transactional ID and aborts its open transaction.
+	if _, _, err := client.ProducerID(ctx); err != nil {
+		client.Close()
+		return errors.Wrapf(err, "initializing transactional producer %s", transactionalID)
+	}
+	if err := client.BeginTransaction(); err != nil {
+		client.Close()
+		return err
+	}
+	k.client.Close()
+	k.client, k.adminClient, k.transactionalID = client, adminClient, transactionalID
+	return nil
+}
+
+// commitTransaction implements the sinkWithTransactions interface.
+func (k *kafkaSinkClientV2) commitTransaction(
+	ctx context.Context, marker jobspb.ResolvedSpans,
+) error {
+	if k.transactionalID == `` {
+		return errors.AssertionFailedf("kafka sink is not emitting messages in transactions")
+	}
+	value, err := protoutil.Marshal(&marker)
+	if err != nil {
+		return err
+	}
+	if err := k.client.ProduceSync(ctx, &kgo.Record{
+		Topic: k.controlTopic,
+		Key:   []byte(k.transactionalID),
+		Value: value,
+	}).FirstErr(); err != nil {
+		return errors.Wrapf(err, "producing transaction marker to %s", k.controlTopic)
+	}
+	if err := k.client.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		return errors.Wrap(err, "committing kafka transaction")
+	}
+	return k.client.BeginTransaction()
+}
+
+// recoverTransactions implements the sinkWithTransactions interface.
+func (k *kafkaSinkClientV2) recoverTransactions(
+	ctx context.Context, transactionalIDPrefix string,
+) ([]jobspb.ResolvedSpans, error) {
+	if k.controlTopic == `` {
+		return nil, errors.AssertionFailedf("kafka sink was not created with %s", changefeedbase.OptExactlyOnce)
+	}
+	markers, err := k.readMarkers(ctx, transactionalIDPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(markers) == 0 {
+		return nil, nil
+	}
+	for id := range markers {
+		if err := k.fenceProducer(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	// The fenced producers may have committed a transaction after the markers
+	// were read; read them again now that no further commits are possible.
+	markers, err = k.readMarkers(ctx, transactionalIDPrefix)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]jobspb.ResolvedSpans, 0, len(markers))
+	for _, id := range slices.Sorted(maps.Keys(markers)) {
+		res = append(res, markers[id])
+	}
+	return res, nil
+}
+
+// fenceProducer fences the producer using the given transactional ID.
+func (k *kafkaSinkClientV2) fenceProducer(ctx context.Context, transactionalID string) error {
+	client, _, err := k.newClient(transactionalOpts(transactionalID)...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if _, _, err := client.ProducerID(ctx); err != nil {
+		return errors.Wrapf(err, "fencing transactional producer %s", transactionalID)
+	}
+	log.Infof(ctx, "fenced kafka transactional producer %s", transactionalID)
+	return nil
+}
+
+// readMarkers reads the control topic up to its last stable offset and returns
+// the last committed marker of each transactional ID with the given prefix.
+func (k *kafkaSinkClientV2) readMarkers(
+	ctx context.Context, transactionalIDPrefix string,
+) (map[string]jobspb.ResolvedSpans, error) {
+	ends, err := k.adminClient.ListCommittedOffsets(ctx, k.controlTopic)
+	if err != nil {
+		return nil, err
+	}
+	partitions := make(map[int32]kgo.Offset)
+	remaining := make(map[int32]int64)
+	var listErr error
+	ends.Each(func(o kadm.ListedOffset) {
+		switch {
+		case errors.Is(o.Err, kerr.UnknownTopicOrPartition):
+			// The control topic is created when the first marker is committed.
+		case o.Err != nil:
+			listErr = errors.CombineErrors(listErr, o.Err)
+		case o.Offset > 0:
+			partitions[o.Partition] = kgo.NewOffset().AtStart()
+			remaining[o.Partition] = o.Offset
+		}
+	})
+	if listErr != nil {
+		return nil, errors.Wrapf(listErr, "listing offsets of %s", k.controlTopic)
+	}
+
+	markers := make(map[string]jobspb.ResolvedSpans)
+	if len(remaining) == 0 {
+		return markers, nil
+	}
+
+	consumer, _, err := k.newClient(
+		kgo.DisableIdempotentWrite(),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{k.controlTopic: partitions}),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		// Records of aborted transactions are skipped, but the commit and abort
+		// markers are kept so that the end of each partition is always seen.
+		kgo.KeepControlRecords(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+
+	for len(remaining) > 0 {
+		fetches := consumer.PollFetches(ctx)
+		if err := fetches.Err(); err != nil {
+			return nil, errors.Wrapf(err, "reading %s", k.controlTopic)
+		}
+		var decodeErr error
+		fetches.EachRecord(func(r *kgo.Record) {
+			if end, ok := remaining[r.Partition]; ok && r.Offset+1 >= end {
+				delete(remaining, r.Partition)
+			}
+			if r.Attrs.IsControl() || !strings.HasPrefix(string(r.Key), transactionalIDPrefix) {
+				return
+			}
+			var m jobspb.ResolvedSpans
+			if err := protoutil.Unmarshal(r.Value, &m); err != nil {
+				decodeErr = errors.CombineErrors(decodeErr, err)
+				return
+			}
+			markers[string(r.Key)] = m
+		})
+		if decodeErr != nil {
+			return nil, errors.Wrapf(decodeErr, "decoding marker in %s", k.controlTopic)
+		}
+	}
+	return markers, nil
+}
@@ -0,0 +1,308 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedvalidators"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// resolveTargetScopes splits the targets of a changefeed statement into
+// individual tables and target scopes. A scope is either a whole database
+// (CHANGEFEED FOR DATABASE db or TABLE db.*) or a single schema
+// (TABLE db.sc.*). The tables belonging to the scopes at the resolution time
+// are returned in scopedTables, ordered by ID.
+func resolveTargetScopes(
+	stmt *annotatedChangefeedStatement,
+	targetDescs map[tree.TablePattern]catalog.Descriptor,
+	allDescs []catalog.Descriptor,
+) (
+	tableTargets tree.ChangefeedTargets,
+	scopes []jobspb.ChangefeedTargetScope,
+	scopedTables []catalog.TableDescriptor,
+	_ error,
+) {
+	var scopeNames []string
+	addScope := func(scope jobspb.ChangefeedTargetScope, name string) {
+		for _, s := range scopes {
+			if s == scope {
+				return
+			}
+		}
+		scopes = append(scopes, scope)
+		scopeNames = append(scopeNames, name)
+	}
+
+	if stmt.Database != "" {
+		var dbDesc catalog.DatabaseDescriptor
+		for _, desc := range allDescs {
+			if db, ok := desc.(catalog.DatabaseDescriptor); ok && db.GetName() == string(stmt.Database) {
+				dbDesc = db
+				break
+			}
+		}
+		if dbDesc == nil {
+			return nil, nil, nil, errors.Errorf("database %q does not exist", stmt.Database)
+		}
+		addScope(jobspb.ChangefeedTargetScope{DatabaseID: dbDesc.GetID()}, tree.AsString(&stmt.Database))
+	}
+
+	for _, ct := range stmt.Targets {
+		pattern, err := ct.TableName.NormalizeTablePattern()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if _, ok := pattern.(*tree.AllTablesSelector); !ok {
+			tableTargets = append(tableTargets, ct)
+			continue
+		}
+		if ct.FamilyName != "" {
+			return nil, nil, nil, errors.Errorf(
+				`CHANGEFEED cannot target a column family of %s`, tree.AsString(ct.TableName))
+		}
+		switch desc := targetDescs[ct.TableName].(type) {
+		case catalog.DatabaseDescriptor:
+			addScope(jobspb.ChangefeedTargetScope{DatabaseID: desc.GetID()}, tree.AsString(ct.TableName))
+		case catalog.SchemaDescriptor:
+			addScope(jobspb.ChangefeedTargetScope{
+				DatabaseID: desc.GetParentID(),
+				SchemaID:   desc.GetID(),
+			}, tree.AsString(ct.TableName))
+		default:
+			return nil, nil, nil, errors.Errorf(`CHANGEFEED cannot target %s`, tree.AsString(ct.TableName))
+		}
+	}
+
+	if len(scopes) == 0 {
+		return tableTargets, nil, nil, nil
+	}
+
+	tablesPerScope := make([]int, len(scopes))
+	for _, desc := range allDescs {
+		td, ok := desc.(catalog.TableDescriptor)
+		if !ok || !changefeedvalidators.IsScopedTable(td) {
+			continue
+		}
+		inScope := false
+		for i, s := range scopes {
+			if s.DatabaseID == td.GetParentID() && (s.SchemaID == 0 || s.SchemaID == td.GetParentSchemaID()) {
+				tablesPerScope[i]++
+				inScope = true
+			}
+		}
+		if inScope {
+			scopedTables = append(scopedTables, td)
+		}
+	}
+	for i, n := range tablesPerScope {
+		if n == 0 {
+			return nil, nil, nil, errors.Errorf(`CHANGEFEED target %s does not contain any tables`, scopeNames[i])
+		}
+	}
+	sort.Slice(scopedTables, func(i, j int) bool { return scopedTables[i].GetID() < scopedTables[j].GetID() })
+	return tableTargets, scopes, scopedTables, nil
+}
+
+// makeScopedTargetSpecification returns the target specification used for a
+// table that is watched because it belongs to one of the changefeed's scopes.
+// Scoped tables are always watched in their entirety.
+func makeScopedTargetSpecification(
+	td catalog.TableDescriptor, statementTimeName string,
+) jobspb.ChangefeedTargetSpecification {
+	typ := jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY
+	if td.NumFamilies() > 1 {
+		typ = jobspb.ChangefeedTargetSpecification_EACH_FAMILY
+	}
+	return jobspb.ChangefeedTargetSpecification{
+		Type:              typ,
+		TableID:           td.GetID(),
+		StatementTimeName: statementTimeName,
+	}
+}
+
+// appendScopedTargets adds a target specification for every scoped table which
+// is not already targeted explicitly.
+func appendScopedTargets(
+	ctx context.Context,
+	p sql.PlanHookState,
+	targets []jobspb.ChangefeedTargetSpecification,
+	tables jobspb.ChangefeedTargets,
+	scopedTables []catalog.TableDescriptor,
+	fullTableName bool,
+) ([]jobspb.ChangefeedTargetSpecification, error) {
+	for _, td := range scopedTables {
+		if _, ok := tables[td.GetID()]; ok {
+			continue
+		}
+		name, err := getChangefeedTargetName(ctx, td, p.ExecCfg(), p.Txn(), fullTableName)
+		if err != nil {
+			return nil, err
+		}
+		tables[td.GetID()] = jobspb.ChangefeedTargetTable{StatementTimeName: name}
+		targets = append(targets, makeScopedTargetSpecification(td, name))
+	}
+	return targets, nil
+}
+
+// refreshTargetMembership recomputes the set of tables watched by a changefeed
+// with target scopes as of the provided timestamp, which is the timestamp the
+// changefeed is about to (re)start from. Tables which were created in, or
+// moved into, one of the scopes are added; tables which were dropped are
+// removed. The resulting membership is persisted in the job progress so that
+// subsequent restarts only need to account for changes after that point.
+//
+// The returned details are a copy of the provided details whose targets
+// reflect the current membership.
+func refreshTargetMembership(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	jobID jobspb.JobID,
+	details jobspb.ChangefeedDetails,
+	localState *cachedState,
+	ts hlc.Timestamp,
+) (jobspb.ChangefeedDetails, error) {
+	opts := changefeedbase.MakeStatementOptions(details.Opts)
+	prevSpecs := details.TargetSpecifications
+	if cfProgress := localState.progress.GetChangefeed(); cfProgress != nil && cfProgress.TargetMembership != nil {
+		prevSpecs = cfProgress.TargetMembership.TargetSpecifications
+	}
+	scopes := changefeedbase.Targets{Scopes: details.TargetScopes}
+
+	var specs []jobspb.ChangefeedTargetSpecification
+	var droppedSpans []roachpb.Span
+	var changed bool
+	if err := sql.DescsTxn(ctx, execCfg, func(ctx context.Context, txn isql.Txn, col *descs.Collection) error {
+		specs, droppedSpans, changed = nil, nil, false
+		if err := txn.KV().SetFixedTimestamp(ctx, ts); err != nil {
+			return errors.Wrapf(err, "setting timestamp for target membership refresh")
+		}
+
+		// Keep every previous target whose table still exists. Offline tables
+		// are kept as well; they are handled like any other offline target.
+		members := make(map[descpb.ID]struct{})
+		for _, spec := range prevSpecs {
+			td, err := col.ByIDWithoutLeased(txn.KV()).Get().Table(ctx, spec.TableID)
+			if err != nil && !errors.Is(err, catalog.ErrDescriptorNotFound) {
+				return errors.Wrapf(err, "fetching table descriptor %d", spec.TableID)
+			}
+			if err != nil || td.Dropped() {
+				log.Infof(ctx, "table %d (%s) is no longer a changefeed target", spec.TableID, spec.StatementTimeName)
+				droppedSpans = append(droppedSpans, execCfg.Codec.TableSpan(uint32(spec.TableID)))
+				changed = true
+				continue
+			}
+			specs = append(specs, spec)
+			members[spec.TableID] = struct{}{}
+		}
+
+		// Add every table which entered one of the scopes.
+		seenDBs := make(map[descpb.ID]struct{})
+		for _, scope := range details.TargetScopes {
+			if _, ok := seenDBs[scope.DatabaseID]; ok {
+				continue
+			}
+			seenDBs[scope.DatabaseID] = struct{}{}
+			db, err := col.ByIDWithoutLeased(txn.KV()).WithoutNonPublic().Get().Database(ctx, scope.DatabaseID)
+			if err != nil {
+				if errors.Is(err, catalog.ErrDescriptorNotFound) || errors.Is(err, catalog.ErrDescriptorDropped) {
+					// The database is gone; its tables were removed above.
+					continue
+				}
+				return errors.Wrapf(err, "fetching database descriptor %d", scope.DatabaseID)
+			}
+			dbTables, err := col.GetAllTablesInDatabase(ctx, txn.KV(), db)
+			if err != nil {
+				return err
+			}
+			for _, desc := range dbTables.OrderedDescriptors() {
+				td, ok := desc.(catalog.TableDescriptor)
+				if !ok || !changefeedvalidators.IsScopedTable(td) ||
+					!scopes.InScope(td.GetParentID(), td.GetParentSchemaID()) {
+					continue
+				}
+				if _, ok := members[td.GetID()]; ok {
+					continue
+				}
+				name, err := getChangefeedTargetName(ctx, td, execCfg, txn.KV(), opts.ShouldUseFullStatementTimeName())
+				if err != nil {
+					return err
+				}
+				log.Infof(ctx, "table %d (%s) is now a changefeed target", td.GetID(), name)
+				specs = append(specs, makeScopedTargetSpecification(td, name))
+				members[td.GetID()] = struct{}{}
+				changed = true
+			}
+		}
+		return nil
+	}); err != nil {
+		return jobspb.ChangefeedDetails{}, err
+	}
+
+	if len(specs) == 0 {
+		return jobspb.ChangefeedDetails{}, changefeedbase.WithTerminalError(
+			errors.Errorf("no tables remain in the scope of the changefeed targets"))
+	}
+
+	if changed {
+		updateProgress := func(progress *jobspb.Progress) error {
+			cfProgress := progress.GetChangefeed()
+			if cfProgress == nil {
+				cfProgress = &jobspb.ChangefeedProgress{}
+				progress.Details = &jobspb.Progress_Changefeed{Changefeed: cfProgress}
+			}
+			cfProgress.TargetMembership = &jobspb.ChangefeedTargetMembership{
+				TargetSpecifications: specs,
+				AsOf:                 ts,
+			}
+			return removeSpansFromProgress(*progress, droppedSpans, details.StatementTime)
+		}
+		if err := updateProgress(&localState.progress); err != nil {
+			return jobspb.ChangefeedDetails{}, err
+		}
+		// Sinkless changefeeds have no job to persist the membership to; they
+		// recompute it from the local state on every retry.
+		if jobID != 0 {
+			job, err := execCfg.JobRegistry.LoadClaimedJob(ctx, jobID)
+			if err != nil {
+				return jobspb.ChangefeedDetails{}, err
+			}
+			if err := job.NoTxn().Update(ctx, func(
+				txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+			) error {
+				if err := updateProgress(md.Progress); err != nil {
+					return err
+				}
+				ju.UpdateProgress(md.Progress)
+				return nil
+			}); err != nil {
+				return jobspb.ChangefeedDetails{}, err
+			}
+		}
+	}
+
+	tables := make(jobspb.ChangefeedTargets, len(specs))
+	for _, spec := range specs {
+		tables[spec.TableID] = jobspb.ChangefeedTargetTable{StatementTimeName: spec.StatementTimeName}
+	}
+	details.TargetSpecifications = specs
+	details.Tables = tables
+	return details, nil
+}
@@ -15,7 +15,10 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Client, error) {
+// newTLSConfig returns a TLS configuration which trusts the system root CAs,
+// as well as caCert if provided, and presents the client certificate if
+// provided.
+func newTLSConfig(caCert, clientCert, clientKey []byte) (*tls.Config, error) {
 	rootCAs, err := x509.SystemCertPool()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not load system root CA pool")
@@ -39,9 +42,6 @@ func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Cl
 			changefeedbase.RegistryParamClientCert, changefeedbase.RegistryParamClientKey)
 	}
 
-	client := httputil.NewClientWithTimeout(httputil.StandardHTTPTimeout)
-	transport := client.Transport.(*http.Transport)
-
 	if clientCertProvided {
 		cert, err := tls.X509KeyPair(clientCert, clientKey)
 		if err != nil {
@@ -50,6 +50,17 @@ func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Cl
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
+	return tlsConfig, nil
+}
+
+func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Client, error) {
+	tlsConfig, err := newTLSConfig(caCert, clientCert, clientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	client := httputil.NewClientWithTimeout(httputil.StandardHTTPTimeout)
+	transport := client.Transport.(*http.Transport)
 	transport.TLSClientConfig = tlsConfig
 	client.Client.Transport = transport
 
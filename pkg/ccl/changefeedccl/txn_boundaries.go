@@ -0,0 +1,246 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"slices"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// A changefeed created with the transaction_boundaries option groups the rows
+// it emits by the transaction that wrote them. Rangefeed values carry the ID
+// of the transaction that wrote them when the value was published as the
+// result of resolving the transaction's intent. Values written by one phase
+// commits, non-transactional writes, and values found by catch-up scans carry
+// no transaction ID; these are grouped by their MVCC timestamp instead.
+//
+// The change aggregator holds the rows of a group in a txnGroupBuffer until
+// its local resolved span frontier reaches the group's timestamp, at which
+// point every write of the transaction to the spans watched by the aggregator
+// has been received. The group is then emitted contiguously, surrounded by
+// begin and commit marker messages or with a txn field in every row,
+// depending on the option's value. The resolved spans forwarded to the change
+// frontier are held below the oldest buffered group so that the changefeed
+// never checkpoints past rows that have not been emitted.
+//
+// A transaction that wrote to spans watched by different aggregators is
+// emitted as one group per aggregator; the groups share the transaction ID.
+//
+// Changefeeds created with the exactly_once option buffer rows the same way,
+// without marking the groups, so that the messages a transactional sink
+// commits never go past the resolved spans committed along with them.
+
+const (
+	txnBeginMarker  = `txn_begin`
+	txnCommitMarker = `txn_commit`
+)
+
+// txnGroupKey identifies the group a row belongs to.
+type txnGroupKey struct {
+	txnID uuid.UUID
+	ts    hlc.Timestamp
+}
+
+// bufferedRow is an encoded row waiting for its group to be resolved.
+type bufferedRow struct {
+	topic         TopicDescriptor
+	key, value    []byte
+	updated, mvcc hlc.Timestamp
+	headers       rowHeaders
+}
+
+// txnGroup is the set of rows written by a transaction.
+type txnGroup struct {
+	txnGroupKey
+	// seq orders groups with the same timestamp by arrival.
+	seq  int
+	rows []bufferedRow
+}
+
+// txnGroupBuffer holds the rows of unresolved transactions.
+type txnGroupBuffer struct {
+	mode     changefeedbase.TransactionBoundaryMode
+	envelope changefeedbase.EnvelopeType
+	maxBytes int64
+
+	groups  map[txnGroupKey]*txnGroup
+	nextSeq int
+	bytes   int64
+}
+
+func newTxnGroupBuffer(
+	mode changefeedbase.TransactionBoundaryMode,
+	envelope changefeedbase.EnvelopeType,
+	maxBytes int64,
+) *txnGroupBuffer {
+	return &txnGroupBuffer{
+		mode:     mode,
+		envelope: envelope,
+		maxBytes: maxBytes,
+		groups:   make(map[txnGroupKey]*txnGroup),
+	}
+}
+
+// nextOrdinal returns the 1-based position the next row of the given group
+// will have within the group.
+func (b *txnGroupBuffer) nextOrdinal(k txnGroupKey) int32 {
+	if g, ok := b.groups[k]; ok {
+		return int32(len(g.rows)) + 1
+	}
+	return 1
+}
+
+// add buffers a row of the given group.
+func (b *txnGroupBuffer) add(k txnGroupKey, r bufferedRow) error {
+	g, ok := b.groups[k]
+	if !ok {
+		g = &txnGroup{txnGroupKey: k, seq: b.nextSeq}
+		b.nextSeq++
+		b.groups[k] = g
+	}
+	g.rows = append(g.rows, r)
+	b.bytes += int64(len(r.key) + len(r.value))
+	if b.bytes > b.maxBytes {
+		return changefeedbase.WithTerminalError(errors.WithHintf(
+			errors.Newf("buffered %s of unresolved transactions, exceeding the limit of %s",
+				humanizeutil.IBytes(b.bytes), humanizeutil.IBytes(b.maxBytes)),
+			"consider increasing %s", changefeedbase.TransactionBoundariesMaxBufferedBytes.Name()))
+	}
+	return nil
+}
+
+// oldest returns the timestamp of the oldest buffered group, or an empty
+// timestamp if no rows are buffered.
+func (b *txnGroupBuffer) oldest() hlc.Timestamp {
+	var ts hlc.Timestamp
+	for k := range b.groups {
+		if ts.IsEmpty() || k.ts.Less(ts) {
+			ts = k.ts
+		}
+	}
+	return ts
+}
+
+// takeResolved removes and returns the groups at or below the frontier, in
+// timestamp order.
+func (b *txnGroupBuffer) takeResolved(frontier hlc.Timestamp) []*txnGroup {
+	var resolved []*txnGroup
+	for k, g := range b.groups {
+		if k.ts.LessEq(frontier) {
+			resolved = append(resolved, g)
+			delete(b.groups, k)
+		}
+	}
+	slices.SortFunc(resolved, func(a, b *txnGroup) int {
+		if c := a.ts.Compare(b.ts); c != 0 {
+			return c
+		}
+		return a.seq - b.seq
+	})
+	for _, g := range resolved {
+		for _, r := range g.rows {
+			b.bytes -= int64(len(r.key) + len(r.value))
+		}
+	}
+	return resolved
+}
+
+// emit emits the rows of a resolved group, surrounded by markers if
+// requested, using the provided function.
+func (b *txnGroupBuffer) emit(
+	ctx context.Context,
+	g *txnGroup,
+	emitRow func(ctx context.Context, r bufferedRow) error,
+) error {
+	if b.mode != changefeedbase.OptTransactionBoundariesMarkers {
+		for _, r := range g.rows {
+			if err := emitRow(ctx, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Markers are emitted to every topic the group wrote to, along with the
+	// number of rows emitted to that topic.
+	var topics []TopicDescriptor
+	counts := make(map[TopicIdentifier]int)
+	for _, r := range g.rows {
+		id := r.topic.GetTopicIdentifier()
+		if _, ok := counts[id]; !ok {
+			topics = append(topics, r.topic)
+		}
+		counts[id]++
+	}
+	emitMarkers := func(marker string) error {
+		for _, topic := range topics {
+			value, err := b.encodeMarker(marker, g, counts[topic.GetTopicIdentifier()])
+			if err != nil {
+				return err
+			}
+			if err := emitRow(ctx, bufferedRow{
+				topic: topic, value: value, updated: g.ts, mvcc: g.ts,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := emitMarkers(txnBeginMarker); err != nil {
+		return err
+	}
+	for _, r := range g.rows {
+		if err := emitRow(ctx, r); err != nil {
+			return err
+		}
+	}
+	return emitMarkers(txnCommitMarker)
+}
+
+// encodeMarker encodes a transaction marker message. Like resolved timestamp
+// messages, markers are top level objects in the wrapped and enriched
+// envelopes and are nested under the metadata key otherwise.
+func (b *txnGroupBuffer) encodeMarker(marker string, g *txnGroup, rows int) ([]byte, error) {
+	var id interface{}
+	if g.txnID != uuid.Nil {
+		id = g.txnID.String()
+	}
+	meta := map[string]interface{}{
+		marker: map[string]interface{}{
+			`id`:             id,
+			`mvcc_timestamp`: g.ts.AsOfSystemTime(),
+			`rows`:           rows,
+		},
+	}
+	switch b.envelope {
+	case changefeedbase.OptEnvelopeWrapped, changefeedbase.OptEnvelopeEnriched:
+		return gojson.Marshal(meta)
+	default:
+		return gojson.Marshal(map[string]interface{}{metaSentinel: meta})
+	}
+}
+
+// txnFieldJSON returns the value of the txn field of a row: the ID of the
+// transaction that wrote it, or null for rows grouped by timestamp, and the
+// ordinal of the write.
+func txnFieldJSON(evCtx eventContext) json.JSON {
+	b := json.NewObjectBuilder(2)
+	if evCtx.txnID != uuid.Nil {
+		b.Add("id", json.FromString(evCtx.txnID.String()))
+	} else {
+		b.Add("id", json.NullJSONValue)
+	}
+	b.Add("ordinal", json.FromInt(int(evCtx.txnWriteOrdinal)))
+	return b.Build()
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnGroupBuffer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wt int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wt} }
+	row := func(v string) bufferedRow {
+		return bufferedRow{topic: noTopic{}, key: []byte(`[1]`), value: []byte(v)}
+	}
+	txn1 := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000001")
+	txn2 := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000002")
+
+	b := newTxnGroupBuffer(changefeedbase.OptTransactionBoundariesMarkers,
+		changefeedbase.OptEnvelopeWrapped, 1<<20)
+	require.True(t, b.oldest().IsEmpty())
+
+	txn1Key := txnGroupKey{txnID: txn1, ts: ts(20)}
+	onePCKey := txnGroupKey{ts: ts(10)}
+	txn2Key := txnGroupKey{txnID: txn2, ts: ts(30)}
+	require.Equal(t, int32(1), b.nextOrdinal(txn1Key))
+	require.NoError(t, b.add(txn1Key, row(`a`)))
+	require.NoError(t, b.add(txn2Key, row(`b`)))
+	require.NoError(t, b.add(onePCKey, row(`c`)))
+	require.NoError(t, b.add(txn1Key, row(`d`)))
+	require.Equal(t, int32(3), b.nextOrdinal(txn1Key))
+	require.Equal(t, ts(10), b.oldest())
+
+	// Nothing is resolved below the oldest group.
+	require.Empty(t, b.takeResolved(ts(9)))
+
+	// Groups at or below the frontier are returned in timestamp order and
+	// surrounded by markers when emitted.
+	var emitted []string
+	for _, g := range b.takeResolved(ts(20)) {
+		require.NoError(t, b.emit(ctx, g, func(_ context.Context, r bufferedRow) error {
+			emitted = append(emitted, string(r.value))
+			return nil
+		}))
+	}
+	require.Equal(t, []string{
+		`{"txn_begin":{"id":null,"mvcc_timestamp":"10.0000000000","rows":1}}`,
+		`c`,
+		`{"txn_commit":{"id":null,"mvcc_timestamp":"10.0000000000","rows":1}}`,
+		`{"txn_begin":{"id":"00000000-0000-0000-0000-000000000001","mvcc_timestamp":"20.0000000000","rows":2}}`,
+		`a`,
+		`d`,
+		`{"txn_commit":{"id":"00000000-0000-0000-0000-000000000001","mvcc_timestamp":"20.0000000000","rows":2}}`,
+	}, emitted)
+	require.Equal(t, ts(30), b.oldest())
+
+	// Exceeding the buffer limit is an error.
+	b = newTxnGroupBuffer(changefeedbase.OptTransactionBoundariesField,
+		changefeedbase.OptEnvelopeWrapped, 4)
+	require.NoError(t, b.add(txn1Key, row(`a`)))
+	require.ErrorContains(t, b.add(txn1Key, row(`b`)), "exceeding the limit")
+}
+
+func TestTxnFieldJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	txnID := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000001")
+	require.Equal(t, `{"id": "00000000-0000-0000-0000-000000000001", "ordinal": 2}`,
+		txnFieldJSON(eventContext{txnID: txnID, txnWriteOrdinal: 2}).String())
+	require.Equal(t, `{"id": null, "ordinal": 1}`,
+		txnFieldJSON(eventContext{txnWriteOrdinal: 1}).String())
+}
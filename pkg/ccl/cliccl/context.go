@@ -14,6 +14,7 @@ import (
 func init() {
 	setProxyContextDefaults()
 	setTestDirectorySvrContextDefaults()
+	setDebugBackupArgsDefault()
 }
 
 // proxyContext captures the command-line parameters of the `mt start-proxy` command.
@@ -47,3 +48,29 @@ var testDirectorySvrContext struct {
 func setTestDirectorySvrContextDefaults() {
 	testDirectorySvrContext.port = 36257
 }
+
+// debugBackupArgs captures the command-line parameters of the `debug backup`
+// commands.
+var debugBackupArgs struct {
+	externalIODir        string
+	encryptionPassphrase string
+	decryptionKMSURIs    []string
+	incrementalLocation  string
+
+	exportTableName string
+	destination     string
+	nullas          string
+	maxRows         int
+}
+
+// setDebugBackupArgsDefault sets the default values in debugBackupArgs.
+func setDebugBackupArgsDefault() {
+	debugBackupArgs.externalIODir = ""
+	debugBackupArgs.encryptionPassphrase = ""
+	debugBackupArgs.decryptionKMSURIs = nil
+	debugBackupArgs.incrementalLocation = ""
+	debugBackupArgs.exportTableName = ""
+	debugBackupArgs.destination = ""
+	debugBackupArgs.nullas = "null"
+	debugBackupArgs.maxRows = 0
+}
@@ -0,0 +1,707 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cliccl
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupresolver"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupsink"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/blobs"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cli"
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlexec"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var debugBackupCmd = &cobra.Command{
+	Use:   "backup [command]",
+	Short: "inspect and verify backups without a running cluster",
+	Long: `
+Inspects and verifies the backups of a backup collection directly in external
+storage, without a running cluster.
+`,
+	RunE: cli.UsageAndErr,
+}
+
+var debugBackupShowCmd = &cobra.Command{
+	Use:   "show <collection-uri> [<subdirectory>]",
+	Short: "show the layers of a backup",
+	Long: `
+Shows the full backup and the incremental backups in the chain of the backup in
+the given subdirectory of the collection. The subdirectory defaults to LATEST.
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: clierrorplus.MaybeDecorateError(runDebugBackupShow),
+}
+
+var debugBackupVerifyCmd = &cobra.Command{
+	Use:   "verify <collection-uri> [<subdirectory>]",
+	Short: "verify that a backup can be restored",
+	Long: `
+Verifies every layer of the backup chain in the given subdirectory of the
+collection. The subdirectory defaults to LATEST.
+
+The manifests are read and their checksums validated, and every SST of the
+chain is read in full. The keys of each SST must be in order and within the
+spans the manifest records for the file, and the size of the data, the number
+of rows and the number of index entries of each file must match the manifest.
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: clierrorplus.MaybeDecorateError(runDebugBackupVerify),
+}
+
+var debugBackupExportCmd = &cobra.Command{
+	Use:   "export <collection-uri> [<subdirectory>] --table=<database>.[<schema>.]<table>",
+	Short: "export the rows of a table in a backup as CSV",
+	Long: `
+Exports the rows of a table as of the end time of the backup chain in the given
+subdirectory of the collection, as CSV. The subdirectory defaults to LATEST.
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: clierrorplus.MaybeDecorateError(runDebugBackupExport),
+}
+
+func init() {
+	debugBackupCmd.AddCommand(debugBackupShowCmd, debugBackupVerifyCmd, debugBackupExportCmd)
+	cli.DebugCmd.AddCommand(debugBackupCmd)
+}
+
+// debugBackupChain is a backup chain resolved by a `debug backup` command.
+type debugBackupChain struct {
+	uris               []string
+	manifests          []backuppb.BackupManifest
+	localityInfo       []jobspb.RestoreDetails_BackupLocalityInfo
+	layerToIterFactory backupinfo.LayerToBackupManifestFileIterFactory
+	encryption         *jobspb.BackupEncryptionOptions
+	kmsEnv             cloud.KMSEnv
+
+	makeStore cloud.ExternalStorageFactory
+}
+
+// debugBackupStorage returns the factories used by the `debug backup`
+// commands to access external storage. Node-local URIs are resolved
+// against --external-io-dir.
+func debugBackupStorage() (cloud.ExternalStorageFactory, cloud.ExternalStorageFromURIFactory) {
+	settings := cluster.MakeClusterSettings()
+	blobFactory := func(ctx context.Context, _ roachpb.NodeID) (blobs.BlobClient, error) {
+		if debugBackupArgs.externalIODir == "" {
+			return nil, errors.New("--external-io-dir must be set to read nodelocal backups")
+		}
+		return blobs.NewLocalClient(debugBackupArgs.externalIODir)
+	}
+	makeStore := func(
+		ctx context.Context, dest cloudpb.ExternalStorage, opts ...cloud.ExternalStorageOption,
+	) (cloud.ExternalStorage, error) {
+		return cloud.MakeExternalStorage(ctx, dest, base.ExternalIODirConfig{}, settings,
+			blobFactory, nil /* db */, nil /* limiters */, cloud.NilMetrics, opts...)
+	}
+	makeStoreFromURI := func(
+		ctx context.Context, uri string, user username.SQLUsername, opts ...cloud.ExternalStorageOption,
+	) (cloud.ExternalStorage, error) {
+		return cloud.ExternalStorageFromURI(ctx, uri, base.ExternalIODirConfig{}, settings,
+			blobFactory, user, nil /* db */, nil /* limiters */, cloud.NilMetrics, opts...)
+	}
+	return makeStore, makeStoreFromURI
+}
+
+// resolveDebugBackupChain resolves the backup chain in the subdirectory of
+// the collection given as arguments to a `debug backup` command, and calls fn
+// with it.
+func resolveDebugBackupChain(
+	ctx context.Context, args []string, fn func(chain debugBackupChain) error,
+) error {
+	user := username.RootUserName()
+	makeStore, makeStoreFromURI := debugBackupStorage()
+	collectionURI := args[0]
+	subdir := backupbase.LatestFileName
+	if len(args) > 1 {
+		subdir = args[1]
+	}
+	if strings.EqualFold(subdir, backupbase.LatestFileName) {
+		var err error
+		subdir, err = backupdest.ReadLatestFile(ctx, collectionURI, makeStoreFromURI, user)
+		if err != nil {
+			return errors.Wrap(err, "read LATEST path")
+		}
+	}
+	fullyResolvedDest, err := backuputils.AppendPaths([]string{collectionURI}, subdir)
+	if err != nil {
+		return err
+	}
+	baseStore, err := makeStoreFromURI(ctx, fullyResolvedDest[0], user)
+	if err != nil {
+		return errors.Wrap(err, "make storage")
+	}
+	defer baseStore.Close()
+
+	settings := cluster.MakeClusterSettings()
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		settings, &base.ExternalIODirConfig{}, nil /* db */, user,
+	)
+	chain := debugBackupChain{kmsEnv: &kmsEnv, makeStore: makeStore}
+	if debugBackupArgs.encryptionPassphrase != "" || len(debugBackupArgs.decryptionKMSURIs) > 0 {
+		opts, err := backupencryption.ReadEncryptionOptions(ctx, baseStore)
+		if err != nil {
+			return err
+		}
+		if debugBackupArgs.encryptionPassphrase != "" {
+			chain.encryption = &jobspb.BackupEncryptionOptions{
+				Mode: jobspb.EncryptionMode_Passphrase,
+				Key:  storageccl.GenerateKey([]byte(debugBackupArgs.encryptionPassphrase), opts[0].Salt),
+			}
+		} else {
+			var kmsInfo *jobspb.BackupEncryptionOptions_KMSInfo
+			for _, encFile := range opts {
+				kmsInfo, err = backupencryption.ValidateKMSURIsAgainstFullBackup(
+					ctx, debugBackupArgs.decryptionKMSURIs,
+					backupencryption.NewEncryptedDataKeyMapFromProtoMap(encFile.EncryptedDataKeyByKMSMasterKeyID),
+					&kmsEnv,
+				)
+				if err == nil {
+					break
+				}
+			}
+			if err != nil {
+				return err
+			}
+			chain.encryption = &jobspb.BackupEncryptionOptions{
+				Mode:    jobspb.EncryptionMode_KMS,
+				KMSInfo: kmsInfo,
+			}
+		}
+	}
+
+	incDirs, err := resolveDebugBackupIncrementalsDir(ctx, collectionURI, subdir, makeStoreFromURI)
+	if err != nil {
+		return err
+	}
+	incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, user, makeStoreFromURI, incDirs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cleanupFn()
+	}()
+
+	mem := mon.NewStandaloneUnlimitedAccount()
+	defer mem.Close(ctx)
+	chain.uris, chain.manifests, chain.localityInfo, _, err = backupdest.ResolveBackupManifests(
+		ctx, mem, []cloud.ExternalStorage{baseStore}, incStores, makeStoreFromURI, fullyResolvedDest,
+		incDirs, hlc.Timestamp{}, chain.encryption, &kmsEnv, user,
+		true /* includeSkipped */, true, /* includeCompacted */
+	)
+	if err != nil {
+		return err
+	}
+	chain.layerToIterFactory, err = backupinfo.GetBackupManifestIterFactories(
+		ctx, makeStore, chain.manifests, chain.encryption, &kmsEnv,
+	)
+	if err != nil {
+		return err
+	}
+	return fn(chain)
+}
+
+// resolveDebugBackupIncrementalsDir returns the directory of the incremental
+// backups of the full backup in subdir: --incremental-location if it is set,
+// or else the default location of the collection, falling back to the legacy
+// location in the full backup's directory if that is where they were written.
+func resolveDebugBackupIncrementalsDir(
+	ctx context.Context,
+	collectionURI, subdir string,
+	makeStoreFromURI cloud.ExternalStorageFromURIFactory,
+) ([]string, error) {
+	if debugBackupArgs.incrementalLocation != "" {
+		return backuputils.AppendPaths([]string{debugBackupArgs.incrementalLocation}, subdir)
+	}
+	hasBackups := func(dirs []string) (bool, error) {
+		store, err := makeStoreFromURI(ctx, dirs[0], username.RootUserName())
+		if err != nil {
+			return false, err
+		}
+		defer store.Close()
+		prev, err := backupdest.FindPriorBackups(ctx, store, backupdest.OmitManifest)
+		if err != nil && !errors.Is(err, cloud.ErrListingUnsupported) {
+			return false, err
+		}
+		return len(prev) > 0, nil
+	}
+	dirs, err := backuputils.AppendPaths([]string{collectionURI}, backupbase.DefaultIncrementalsSubdir, subdir)
+	if err != nil {
+		return nil, err
+	}
+	legacyDirs, err := backuputils.AppendPaths([]string{collectionURI}, subdir)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := hasBackups(dirs); err != nil || ok {
+		return dirs, err
+	}
+	if ok, err := hasBackups(legacyDirs); err != nil || ok {
+		return legacyDirs, err
+	}
+	return dirs, nil
+}
+
+func runDebugBackupShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	return resolveDebugBackupChain(ctx, args, func(chain debugBackupChain) error {
+		cols := []string{
+			"layer", "backup_type", "path", "start_time", "end_time", "revision_history",
+			"is_compacted", "files", "size_bytes", "rows",
+		}
+		var rows [][]string
+		for layer, m := range chain.manifests {
+			backupType := "full"
+			if !m.StartTime.IsEmpty() {
+				backupType = "incremental"
+			}
+			files, err := countDebugBackupFiles(ctx, chain, layer)
+			if err != nil {
+				return err
+			}
+			path, err := cloud.SanitizeExternalStorageURI(chain.uris[layer], nil /* extraParams */)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, []string{
+				strconv.Itoa(layer),
+				backupType,
+				path,
+				formatDebugBackupTime(m.StartTime),
+				formatDebugBackupTime(m.EndTime),
+				strconv.FormatBool(m.MVCCFilter == backuppb.MVCCFilter_All),
+				strconv.FormatBool(m.IsCompacted),
+				strconv.Itoa(files),
+				strconv.FormatInt(m.EntryCounts.DataSize, 10),
+				strconv.FormatInt(m.EntryCounts.Rows, 10),
+			})
+		}
+		return cli.PrintQueryOutput(os.Stdout, cols, clisqlexec.NewRowSliceIter(rows, "rlllllllrr"))
+	})
+}
+
+func formatDebugBackupTime(ts hlc.Timestamp) string {
+	if ts.IsEmpty() {
+		return ""
+	}
+	return ts.GoTime().UTC().String()
+}
+
+func countDebugBackupFiles(ctx context.Context, chain debugBackupChain, layer int) (int, error) {
+	it, err := chain.layerToIterFactory[layer].NewFileIter(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	var n int
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return 0, err
+		} else if !ok {
+			return n, nil
+		}
+		n++
+	}
+}
+
+// debugBackupMaxProblems is the number of problems after which `debug backup
+// verify` stops verifying a backup.
+const debugBackupMaxProblems = 100
+
+// debugBackupVerifier accumulates the problems found by `debug backup verify`.
+type debugBackupVerifier struct {
+	chain    debugBackupChain
+	problems []string
+}
+
+func (v *debugBackupVerifier) addProblem(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *debugBackupVerifier) tooManyProblems() bool {
+	return len(v.problems) >= debugBackupMaxProblems
+}
+
+func runDebugBackupVerify(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	return resolveDebugBackupChain(ctx, args, func(chain debugBackupChain) error {
+		v := debugBackupVerifier{chain: chain}
+		cols := []string{"layer", "path", "files", "keys", "size_bytes"}
+		var rows [][]string
+		for layer := range chain.manifests {
+			files, keys, size, err := v.verifyLayer(ctx, layer)
+			if err != nil {
+				return err
+			}
+			path, err := cloud.SanitizeExternalStorageURI(chain.uris[layer], nil /* extraParams */)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, []string{
+				strconv.Itoa(layer), path, strconv.Itoa(files),
+				strconv.FormatInt(keys, 10), strconv.FormatInt(size, 10),
+			})
+			if v.tooManyProblems() {
+				break
+			}
+		}
+		if err := cli.PrintQueryOutput(os.Stdout, cols, clisqlexec.NewRowSliceIter(rows, "rlrrr")); err != nil {
+			return err
+		}
+		if len(v.problems) > 0 {
+			for _, p := range v.problems {
+				fmt.Fprintln(os.Stderr, p)
+			}
+			return errors.Newf("backup verification found %d problems", len(v.problems))
+		}
+		fmt.Println("backup verified")
+		return nil
+	})
+}
+
+// verifyLayer verifies the metadata and the SSTs of a layer of the backup
+// chain, returning the number of files, keys and bytes it read.
+func (v *debugBackupVerifier) verifyLayer(
+	ctx context.Context, layer int,
+) (files int, keyCount int64, size int64, _ error) {
+	m := v.chain.manifests[layer]
+	defaultStore, err := v.chain.makeStore(ctx, m.Dir)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer defaultStore.Close()
+	// The checksum of the manifest itself was validated when it was read.
+	for _, name := range append(
+		[]string{backupbase.BackupManifestName + backupinfo.BackupManifestChecksumSuffix},
+		statisticsFilenames(m)...,
+	) {
+		if _, err := defaultStore.Size(ctx, name); err != nil {
+			v.addProblem("layer %d: metadata file %s: %v", layer, name, err)
+		}
+	}
+
+	// The manifest counts the KVs of the primary indexes of its tables as rows
+	// and the others as index entries.
+	pkIDs := make(map[uint64]bool)
+	descIt := v.chain.layerToIterFactory[layer].NewDescIter(ctx)
+	defer descIt.Close()
+	for ; ; descIt.Next() {
+		if ok, err := descIt.Valid(); err != nil {
+			return 0, 0, 0, err
+		} else if !ok {
+			break
+		}
+		if t, _, _, _, _ := descpb.GetDescriptors(descIt.Value()); t != nil {
+			pkIDs[kvpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+
+	// Group the files of the manifest by SST, as an SST may hold the data of
+	// several files with adjacent spans.
+	type sst struct {
+		localityKV string
+		path       string
+		files      []backuppb.BackupManifest_File
+	}
+	var ssts []*sst
+	sstByPath := make(map[string]*sst)
+	it, err := v.chain.layerToIterFactory[layer].NewFileIter(ctx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return 0, 0, 0, err
+		} else if !ok {
+			break
+		}
+		f := *it.Value()
+		files++
+		key := f.LocalityKV + "/" + f.Path
+		s, ok := sstByPath[key]
+		if !ok {
+			s = &sst{localityKV: f.LocalityKV, path: f.Path}
+			sstByPath[key] = s
+			ssts = append(ssts, s)
+		}
+		s.files = append(s.files, f)
+	}
+
+	stores := map[string]cloud.ExternalStorage{"": defaultStore}
+	defer func() {
+		for localityKV, store := range stores {
+			if localityKV != "" {
+				_ = store.Close()
+			}
+		}
+	}()
+	var encOpts *kvpb.FileEncryptionOptions
+	if v.chain.encryption != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, v.chain.encryption, v.chain.kmsEnv)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		encOpts = &kvpb.FileEncryptionOptions{Key: key}
+	}
+	for _, s := range ssts {
+		store, ok := stores[s.localityKV]
+		if !ok {
+			store = defaultStore
+			if uri, ok := v.chain.localityInfo[layer].URIsByOriginalLocalityKV[s.localityKV]; ok {
+				conf, err := cloud.ExternalStorageConfFromURI(uri, username.RootUserName())
+				if err != nil {
+					return 0, 0, 0, err
+				}
+				conf.URI = uri
+				if store, err = v.chain.makeStore(ctx, conf); err != nil {
+					return 0, 0, 0, err
+				}
+			}
+			stores[s.localityKV] = store
+		}
+		n, sz := v.verifySST(ctx, layer, m, store, encOpts, s.path, s.files, pkIDs)
+		keyCount += n
+		size += sz
+		if v.tooManyProblems() {
+			break
+		}
+	}
+	return files, keyCount, size, nil
+}
+
+func statisticsFilenames(m backuppb.BackupManifest) []string {
+	names := make([]string, 0, len(m.StatisticsFilenames))
+	for _, name := range m.StatisticsFilenames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// verifySST reads an SST in full, checking that its keys are in order and
+// within the spans of the manifest files it holds, and that the size of the
+// data and the row and index entry counts of each file match the manifest. The
+// KVs of the indexes in pkIDs are counted as rows. It returns the number of
+// keys and bytes it read.
+func (v *debugBackupVerifier) verifySST(
+	ctx context.Context,
+	layer int,
+	m backuppb.BackupManifest,
+	store cloud.ExternalStorage,
+	encOpts *kvpb.FileEncryptionOptions,
+	path string,
+	files []backuppb.BackupManifest_File,
+	pkIDs map[uint64]bool,
+) (keyCount int64, size int64) {
+	sort.Slice(files, func(i, j int) bool { return files[i].Span.Key.Compare(files[j].Span.Key) < 0 })
+	if _, err := store.Size(ctx, path); err != nil {
+		v.addProblem("layer %d: file %s: %v", layer, path, err)
+		return 0, 0
+	}
+	// The SST sink starts a new SST whenever the elided prefix changes, so
+	// every file of an SST shares the same prefix.
+	elidedPrefix, err := backupsink.ElidedPrefix(files[0].Span.Key, m.ElidedPrefix)
+	if err != nil {
+		v.addProblem("layer %d: file %s: %v", layer, path, err)
+		return 0, 0
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, []storageccl.StoreFile{{Store: store, FilePath: path}},
+		encOpts, storage.IterOptions{
+			KeyTypes:   storage.IterKeyTypePointsOnly,
+			LowerBound: keys.MinKey,
+			UpperBound: keys.MaxKey,
+		})
+	if err != nil {
+		v.addProblem("layer %d: file %s: %v", layer, path, err)
+		return 0, 0
+	}
+	defer iter.Close()
+
+	dataSizes := make([]int64, len(files))
+	// Like the export that wrote the SST, count the distinct rows of each file,
+	// including the rows of all of their revisions.
+	rowCounters := make([]storage.RowCounter, len(files))
+	var prev storage.MVCCKey
+	var fileIdx int
+	for iter.SeekGE(storage.MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			v.addProblem("layer %d: file %s: %v", layer, path, err)
+			return keyCount, size
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if keyCount > 0 && !prev.Less(key) {
+			v.addProblem("layer %d: file %s: key %s is not after the previous key %s", layer, path, key, prev)
+			return keyCount, size
+		}
+		prev = key.Clone()
+		value, err := iter.UnsafeValue()
+		if err != nil {
+			v.addProblem("layer %d: file %s: %v", layer, path, err)
+			return keyCount, size
+		}
+		keyCount++
+		kvSize := int64(len(elidedPrefix) + len(key.Key) + len(value))
+		size += kvSize
+
+		fullKey := append(append(roachpb.Key(nil), elidedPrefix...), key.Key...)
+		for fileIdx < len(files) && fullKey.Compare(files[fileIdx].Span.EndKey) >= 0 {
+			fileIdx++
+		}
+		if fileIdx == len(files) || fullKey.Compare(files[fileIdx].Span.Key) < 0 {
+			v.addProblem("layer %d: file %s: key %s is outside of the spans of the file", layer, path, fullKey)
+			return keyCount, size
+		}
+		dataSizes[fileIdx] += kvSize
+		if err := rowCounters[fileIdx].Count(fullKey); err != nil {
+			v.addProblem("layer %d: file %s: key %s: %v", layer, path, fullKey, err)
+			return keyCount, size
+		}
+	}
+	for i, f := range files {
+		// The data size of files with range keys also accounts for the range
+		// keys, which are not read here.
+		if !f.HasRangeKeys && dataSizes[i] != f.EntryCounts.DataSize {
+			v.addProblem("layer %d: file %s: span %s holds %d bytes of data, but the manifest records %d",
+				layer, path, f.Span, dataSizes[i], f.EntryCounts.DataSize)
+		}
+		var rows, indexEntries int64
+		for id, count := range rowCounters[i].EntryCounts {
+			if pkIDs[id] {
+				rows += count
+			} else {
+				indexEntries += count
+			}
+		}
+		if rows != f.EntryCounts.Rows || indexEntries != f.EntryCounts.IndexEntries {
+			v.addProblem("layer %d: file %s: span %s holds %d rows and %d index entries, "+
+				"but the manifest records %d and %d",
+				layer, path, f.Span, rows, indexEntries, f.EntryCounts.Rows, f.EntryCounts.IndexEntries)
+		}
+	}
+	return keyCount, size
+}
+
+func runDebugBackupExport(cmd *cobra.Command, args []string) error {
+	if debugBackupArgs.exportTableName == "" {
+		return errors.New("export data requires table name specified by --table flag")
+	}
+	tableName, err := parser.ParseQualifiedTableName(debugBackupArgs.exportTableName)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	return resolveDebugBackupChain(ctx, args, func(chain debugBackupChain) error {
+		descs, _, err := backupinfo.LoadSQLDescsFromBackupsAtTime(
+			ctx, chain.manifests, chain.layerToIterFactory, hlc.Timestamp{},
+		)
+		if err != nil {
+			return err
+		}
+		targets := tree.BackupTargetList{
+			Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{tableName}},
+		}
+		matched, err := backupresolver.DescriptorsMatchingTargets(ctx, "", /* currentDatabase */
+			sessiondata.MakeSearchPath(nil), descs, targets, hlc.Timestamp{})
+		if err != nil {
+			return err
+		}
+		table, ok := matched.DescsByTablePattern[tableName].(catalog.TableDescriptor)
+		if !ok || !table.IsPhysicalTable() || table.IsSequence() {
+			return errors.Newf("%q is not a table", tree.ErrString(tableName))
+		}
+		cols := backupinfo.BackupTableColumns(table)
+		for _, col := range cols {
+			if col.GetType().UserDefined() {
+				return errors.Newf("table %q cannot be exported: column %q has a user-defined type",
+					table.GetName(), col.GetName())
+			}
+		}
+
+		var out io.Writer = os.Stdout
+		if debugBackupArgs.destination != "" {
+			f, err := os.Create(debugBackupArgs.destination)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		w := csv.NewWriter(out)
+		header := make([]string, len(cols))
+		for i, col := range cols {
+			header[i] = col.GetName()
+		}
+		if err := w.Write(header); err != nil {
+			return err
+		}
+
+		fmtCtx := tree.NewFmtCtx(tree.FmtExport)
+		defer fmtCtx.Close()
+		record := make([]string, len(cols))
+		var rows int
+		err = backupinfo.ScanBackupTable(ctx, chain.makeStore, username.RootUserName(),
+			chain.manifests, chain.layerToIterFactory, chain.localityInfo, chain.encryption,
+			chain.kmsEnv, table, nil /* spans */, chain.manifests[len(chain.manifests)-1].EndTime,
+			func(datums tree.Datums) error {
+				if debugBackupArgs.maxRows > 0 && rows >= debugBackupArgs.maxRows {
+					return iterutil.StopIteration()
+				}
+				rows++
+				for i, d := range datums {
+					if d == tree.DNull {
+						record[i] = debugBackupArgs.nullas
+						continue
+					}
+					fmtCtx.FormatNode(d)
+					record[i] = fmtCtx.CloseAndGetString()
+				}
+				return w.Write(record)
+			})
+		if err := iterutil.Map(err); err != nil {
+			return err
+		}
+		w.Flush()
+		return w.Error()
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cliccl_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cli"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO d.t VALUES (1, 'x'), (2, NULL)`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO 'nodelocal://1/plain'`)
+	sqlDB.Exec(t, `INSERT INTO d.t VALUES (3, 'z')`)
+	sqlDB.Exec(t, `DELETE FROM d.t WHERE a = 1`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN 'nodelocal://1/plain'`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO 'nodelocal://1/enc' WITH encryption_passphrase = 'abc'`)
+
+	c := cli.NewCLITest(cli.TestCLIParams{T: t, NoServer: true})
+	defer c.Cleanup()
+
+	run := func(cmd string) string {
+		out, err := c.RunWithCapture(fmt.Sprintf("debug backup %s --external-io-dir=%s", cmd, dir))
+		require.NoError(t, err)
+		return out
+	}
+
+	t.Run("show", func(t *testing.T) {
+		out := run("show nodelocal://1/plain")
+		require.Contains(t, out, "full")
+		require.Contains(t, out, "incremental")
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		require.Contains(t, run("verify nodelocal://1/plain"), "backup verified")
+	})
+
+	t.Run("export", func(t *testing.T) {
+		out := run("export nodelocal://1/plain --table=d.t")
+		require.Contains(t, out, "a,b\n2,null\n3,z\n")
+
+		out = run("export nodelocal://1/plain --table=d.t --nullas=NULL --max-rows=1")
+		require.Contains(t, out, "a,b\n2,NULL\n")
+		require.NotContains(t, out, "3,z")
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		require.Contains(t, run("verify nodelocal://1/enc"), "ERROR")
+		require.Contains(t, run("verify nodelocal://1/enc --encryption-passphrase=abc"), "backup verified")
+		require.Contains(t, run("export nodelocal://1/enc --table=d.t --encryption-passphrase=abc"),
+			"a,b\n1,x\n2,null\n")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var removed bool
+		require.NoError(t, filepath.WalkDir(filepath.Join(dir, "plain"),
+			func(path string, d fs.DirEntry, err error) error {
+				if err != nil || removed || !strings.HasSuffix(path, ".sst") {
+					return err
+				}
+				removed = true
+				return os.Remove(path)
+			}))
+		require.True(t, removed)
+		require.Contains(t, run("verify nodelocal://1/plain"), "backup verification found")
+	})
+}
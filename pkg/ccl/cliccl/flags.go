@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/cli/cliflags"
 	"github.com/cockroachdb/cockroach/pkg/cli/exit"
 	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
@@ -91,6 +92,22 @@ func init() {
 		cliflagcfg.StringFlagDepth(1, f, &testDirectorySvrContext.kvAddrs, cliflags.KVAddrs)
 	})
 
+	// Debug backup command flags.
+	cli.RegisterFlags(func() {
+		for _, cmd := range []*cobra.Command{debugBackupShowCmd, debugBackupVerifyCmd, debugBackupExportCmd} {
+			f := cmd.Flags()
+			cliflagcfg.StringFlag(f, &debugBackupArgs.externalIODir, cliflags.ExternalIODir)
+			cliflagcfg.StringFlag(f, &debugBackupArgs.encryptionPassphrase, cliflags.BackupEncryptionPassphrase)
+			cliflagcfg.StringSliceFlag(f, &debugBackupArgs.decryptionKMSURIs, cliflags.BackupDecryptionKMSURI)
+			cliflagcfg.StringFlag(f, &debugBackupArgs.incrementalLocation, cliflags.BackupIncrementalLocation)
+		}
+		f := debugBackupExportCmd.Flags()
+		cliflagcfg.StringFlag(f, &debugBackupArgs.exportTableName, cliflags.ExportTableTarget)
+		cliflagcfg.StringFlag(f, &debugBackupArgs.destination, cliflags.ExportDestination)
+		cliflagcfg.StringFlag(f, &debugBackupArgs.nullas, cliflags.ExportCSVNullas)
+		cliflagcfg.IntFlag(f, &debugBackupArgs.maxRows, cliflags.MaxRows)
+	})
+
 	// FIPS verification flags.
 	cli.RegisterFlags(func() {
 		cmd := cli.CockroachCmd()
@@ -0,0 +1,132 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package engineccl
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/testutils/storageutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyStorage is a cloud.ExternalStorage whose reads fail a given number of
+// times.
+type flakyStorage struct {
+	cloud.ExternalStorage
+	failures atomic.Int32
+}
+
+func (s *flakyStorage) ReadFile(
+	ctx context.Context, basename string, opts cloud.ReadOptions,
+) (ioctx.ReadCloserCtx, int64, error) {
+	if s.failures.Add(-1) >= 0 {
+		return nil, 0, errors.New("injected cold storage failure")
+	}
+	return s.ExternalStorage.ReadFile(ctx, basename, opts)
+}
+
+// TestColdStorage opens an engine with cold storage, checks that only the
+// sstables of cold spans are moved to it, that they can be read while the
+// cold storage fails transiently, and that they are moved back once their
+// spans are no longer cold.
+func TestColdStorage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	dir := t.TempDir()
+	cold := &flakyStorage{
+		ExternalStorage: nodelocal.TestingMakeNodelocalStorage(
+			filepath.Join(dir, "cold"), st, cloudpb.ExternalStorage{}),
+	}
+	open := func() storage.Engine {
+		env := fs.MustInitPhysicalTestingEnv(filepath.Join(dir, "store"))
+		eng, err := storage.Open(ctx, env, st, storage.ColdStorage(cold))
+		require.NoError(t, err)
+		require.NoError(t, eng.SetStoreID(ctx, 1))
+		return eng
+	}
+	coldObjects := func() int {
+		var n int
+		require.NoError(t, cold.List(ctx, "", "", func(name string) error {
+			if strings.HasSuffix(name, ".sst") {
+				n++
+			}
+			return nil
+		}))
+		return n
+	}
+
+	coldPrefix := keys.SystemSQLCodec.TablePrefix(100)
+	hotPrefix := keys.SystemSQLCodec.TablePrefix(101)
+	prefixes := []roachpb.Key{coldPrefix, hotPrefix}
+	key := func(prefix roachpb.Key, i int) roachpb.Key {
+		return encoding.EncodeVarintAscending(prefix.Clone(), int64(i))
+	}
+	const numKeys = 100
+	checkValues := func(eng storage.Engine) {
+		for _, prefix := range prefixes {
+			for i := 0; i < numKeys; i++ {
+				require.Equal(t, []byte("value"),
+					storageutils.MVCCGetRaw(t, eng, storageutils.PointKey(string(key(prefix, i)), 0)))
+			}
+		}
+	}
+
+	// Write each table into its own bottom-level sstable.
+	eng := open()
+	for _, prefix := range prefixes {
+		for i := 0; i < numKeys; i++ {
+			require.NoError(t, eng.PutUnversioned(key(prefix, i), []byte("value")))
+		}
+		require.NoError(t, eng.Flush())
+		require.NoError(t, eng.CompactRange(ctx, prefix, prefix.PrefixEnd()))
+	}
+
+	coldSpan := roachpb.Span{Key: coldPrefix, EndKey: coldPrefix.PrefixEnd()}
+	isCold := func(_ context.Context, span roachpb.Span) (bool, error) {
+		return coldSpan.Contains(span), nil
+	}
+	require.NoError(t, eng.(storage.ColdStorageTierer).TierColdStorage(ctx, isCold))
+	require.Equal(t, 1, coldObjects())
+	require.Positive(t, eng.GetMetrics().ColdStorageBytes)
+	checkValues(eng)
+	eng.Close()
+
+	// Reopen the engine so that the cold sstable is read from the cold storage,
+	// which fails the first reads.
+	cold.failures.Store(2)
+	eng = open()
+	defer eng.Close()
+	checkValues(eng)
+	require.Positive(t, eng.GetMetrics().ColdStorageReadRetries)
+
+	// Once the span is no longer cold, its sstable is moved back to local disk.
+	notCold := func(context.Context, roachpb.Span) (bool, error) {
+		return false, nil
+	}
+	require.NoError(t, eng.(storage.ColdStorageTierer).TierColdStorage(ctx, notCold))
+	require.Zero(t, eng.GetMetrics().ColdStorageBytes)
+	checkValues(eng)
+}
@@ -9,11 +9,15 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/storage/storageconfig"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble/vfs"
 )
 
@@ -297,14 +301,27 @@ func newEncryptedEnv(
 	readOnly bool,
 	options *storageconfig.EncryptionOptions,
 ) (*fs.EncryptionEnv, error) {
-	if options.KeySource != storageconfig.EncryptionKeyFromFiles {
-		return nil, fmt.Errorf("unknown encryption key source: %d", options.KeySource)
-	}
 	storeKeyManager := &StoreKeyManager{
 		fs:                unencryptedFS,
 		activeKeyFilename: options.KeyFiles.CurrentKey,
 		oldKeyFilename:    options.KeyFiles.OldKey,
 	}
+	switch options.KeySource {
+	case storageconfig.EncryptionKeyFromFiles:
+	case storageconfig.EncryptionKeyFromKMS:
+		// The store is opened before the node has joined the cluster, so the KMS
+		// is configured with default settings and can only rely on credentials
+		// carried in its URI.
+		kms, err := cloud.KMSFromURI(context.TODO(), options.KMSURI,
+			cloud.MakeNodeKMSEnv(cluster.MakeClusterSettings(), &base.ExternalIODirConfig{}))
+		if err != nil {
+			return nil, errors.Wrap(err, "opening store key KMS")
+		}
+		defer func() { _ = kms.Close() }()
+		storeKeyManager.kms = kms
+	default:
+		return nil, fmt.Errorf("unknown encryption key source: %d", options.KeySource)
+	}
 	if err := storeKeyManager.Load(context.TODO()); err != nil {
 		return nil, err
 	}
@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip/kmiptestutils"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
@@ -323,6 +325,88 @@ func TestPebbleEncryption(t *testing.T) {
 	}()
 }
 
+// TestPebbleEncryptionKMS opens an encrypted Pebble whose store key file is
+// wrapped by a KMIP KMS.
+func TestPebbleEncryptionKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const stickyVFSID = `foo`
+
+	ctx := context.Background()
+	srv := kmiptestutils.NewTestServer(t)
+	defer srv.Close()
+	srv.CreateKey("store-key")
+	kmsURI := srv.URI("store-key")
+	kms, err := cloud.KMSFromURI(ctx, kmsURI, cloud.MakeNodeKMSEnv(
+		cluster.MakeTestingClusterSettings(), &base.ExternalIODirConfig{}))
+	require.NoError(t, err)
+	wrapped, err := kms.Encrypt(ctx, []byte("111111111111111111111111111111111234567890123456"))
+	require.NoError(t, err)
+	require.NoError(t, kms.Close())
+
+	stickyRegistry := fs.NewStickyRegistry()
+	writeToFile(t, stickyRegistry.Get(stickyVFSID), "16.key", wrapped)
+
+	encOptions := &storageconfig.EncryptionOptions{
+		KeySource: storageconfig.EncryptionKeyFromKMS,
+		KeyFiles: &storageconfig.EncryptionKeyFiles{
+			CurrentKey: "16.key",
+			OldKey:     "plain",
+		},
+		KMSURI:                kmsURI,
+		DataKeyRotationPeriod: 1000, // arbitrary seconds
+	}
+	openEnv := func() (*fs.Env, error) {
+		return fs.InitEnvFromStoreSpec(
+			ctx,
+			base.StoreSpec{
+				InMemory:          true,
+				Size:              storageconfig.Size{Bytes: 512 << 20},
+				EncryptionOptions: encOptions,
+				StickyVFSID:       stickyVFSID,
+			},
+			fs.ReadWrite,
+			stickyRegistry, /* sticky registry */
+			nil,            /* statsCollector */
+		)
+	}
+
+	func() {
+		env, err := openEnv()
+		require.NoError(t, err)
+		db, err := storage.Open(ctx, env, cluster.MakeTestingClusterSettings())
+		require.NoError(t, err)
+		defer db.Close()
+
+		stats, err := db.GetEnvStats()
+		require.NoError(t, err)
+		var s enginepb.EncryptionStatus
+		require.NoError(t, protoutil.Unmarshal(stats.EncryptionStatus, &s))
+		require.Equal(t, "16.key", s.ActiveStoreKey.Source)
+		require.Equal(t, int32(enginepb.EncryptionType_AES128_CTR), stats.EncryptionType)
+
+		batch := db.NewWriteBatch()
+		defer batch.Close()
+		require.NoError(t, batch.PutUnversioned(roachpb.Key("a"), []byte("a")))
+		require.NoError(t, batch.Commit(true))
+		require.NoError(t, db.Flush())
+	}()
+
+	func() {
+		env, err := openEnv()
+		require.NoError(t, err)
+		db, err := storage.Open(ctx, env, cluster.MakeTestingClusterSettings())
+		require.NoError(t, err)
+		defer db.Close()
+		require.Equal(t, []byte("a"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("a", 0)))
+	}()
+
+	// The store cannot be opened while the KMS is unreachable.
+	srv.Close()
+	_, err = openEnv()
+	require.ErrorContains(t, err, "unwrapping store key 16.key")
+}
+
 func TestPebbleEncryption2(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -13,6 +13,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -82,6 +83,8 @@ type StoreKeyManager struct {
 	fs                vfs.FS
 	activeKeyFilename string
 	oldKeyFilename    string
+	// kms, if set, is used to unwrap the contents of the key files.
+	kms cloud.KMS
 
 	// Implementation. Both are not nil after a successful call to Load().
 	activeKey *enginepb.SecretKey
@@ -91,11 +94,11 @@ type StoreKeyManager struct {
 // Load must be called before calling other functions.
 func (m *StoreKeyManager) Load(ctx context.Context) error {
 	var err error
-	m.activeKey, err = LoadKeyFromFile(m.fs, m.activeKeyFilename)
+	m.activeKey, err = LoadWrappedKeyFromFile(ctx, m.fs, m.activeKeyFilename, m.kms)
 	if err != nil {
 		return err
 	}
-	m.oldKey, err = LoadKeyFromFile(m.fs, m.oldKeyFilename)
+	m.oldKey, err = LoadWrappedKeyFromFile(ctx, m.fs, m.oldKeyFilename, m.kms)
 	if err != nil {
 		return err
 	}
@@ -130,6 +133,15 @@ func (m *StoreKeyManager) GetKey(id string) (*enginepb.SecretKey, error) {
 
 // LoadKeyFromFile reads a secret key from the given file.
 func LoadKeyFromFile(fs vfs.FS, filename string) (*enginepb.SecretKey, error) {
+	return LoadWrappedKeyFromFile(context.Background(), fs, filename, nil /* kms */)
+}
+
+// LoadWrappedKeyFromFile reads a secret key from the given file. If kms is
+// not nil, the contents of the file are unwrapped with it before being parsed,
+// so that the file on disk does not hold the key in the clear.
+func LoadWrappedKeyFromFile(
+	ctx context.Context, fs vfs.FS, filename string, kms cloud.KMS,
+) (*enginepb.SecretKey, error) {
 	now := kmTimeNow().Unix()
 	key := &enginepb.SecretKey{}
 	key.Info = &enginepb.KeyInfo{}
@@ -150,6 +162,11 @@ func LoadKeyFromFile(fs vfs.FS, filename string) (*enginepb.SecretKey, error) {
 	if err != nil {
 		return nil, err
 	}
+	if kms != nil {
+		if b, err = kms.Decrypt(ctx, b); err != nil {
+			return nil, errors.Wrapf(err, "unwrapping store key %s with KMS key %s", filename, kms.MasterKeyID())
+		}
+	}
 
 	// We support two file formats:
 	// - Old-style keys are just raw random data with no delimiters; the only
@@ -17,6 +17,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip/kmiptestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
@@ -197,6 +201,48 @@ func TestStoreKeyManager(t *testing.T) {
 	}
 }
 
+func TestStoreKeyManagerKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	srv := kmiptestutils.NewTestServer(t)
+	defer srv.Close()
+	srv.CreateKey("store-key")
+	kms, err := cloud.KMSFromURI(ctx, srv.URI("store-key"), cloud.MakeNodeKMSEnv(
+		cluster.MakeTestingClusterSettings(), &base.ExternalIODirConfig{}))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, kms.Close()) }()
+
+	memFS := vfs.NewMem()
+	wrapped, err := kms.Encrypt(ctx, []byte(keyFile128))
+	require.NoError(t, err)
+	writeToFile(t, memFS, "16.key", wrapped)
+
+	// The wrapped key cannot be loaded without the KMS.
+	skm := &StoreKeyManager{fs: memFS, activeKeyFilename: "16.key", oldKeyFilename: "plain"}
+	require.Error(t, skm.Load(ctx))
+
+	skm = &StoreKeyManager{fs: memFS, activeKeyFilename: "16.key", oldKeyFilename: "plain", kms: kms}
+	require.NoError(t, skm.Load(ctx))
+	key, err := skm.GetKey(keyID128)
+	require.NoError(t, err)
+	require.Equal(t, enginepb.EncryptionType_AES128_CTR, key.Info.EncryptionType)
+	require.Equal(t, []byte(key128), key.Key)
+
+	// Once the KMS key is rotated, keys wrapped with the previous version can
+	// still be unwrapped.
+	srv.Rotate("store-key")
+	rewrapped, err := kms.Encrypt(ctx, []byte(keyFile256))
+	require.NoError(t, err)
+	writeToFile(t, memFS, "32.key", rewrapped)
+	skm = &StoreKeyManager{fs: memFS, activeKeyFilename: "32.key", oldKeyFilename: "16.key", kms: kms}
+	require.NoError(t, skm.Load(ctx))
+	_, err = skm.GetKey(keyID128)
+	require.NoError(t, err)
+	_, err = skm.GetKey(keyID256)
+	require.NoError(t, err)
+}
+
 func setActiveStoreKeyInProto(dkr *enginepb.DataKeysRegistry, id string) {
 	dkr.StoreKeys[id] = &enginepb.KeyInfo{
 		EncryptionType: enginepb.EncryptionType_AES128_CTR,
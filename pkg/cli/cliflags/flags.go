@@ -1110,8 +1110,9 @@ See the storage.wal_failover.unhealthy_op_threshold cluster setting.
 	SecondaryCache = FlagInfo{
 		Name: "experimental-secondary-cache",
 		Description: `
-Enables the use of a secondary cache to store objects from shared storage (see
---experimental-shared-storage) inside local paths for each store. A size must
+Enables the use of a secondary cache to store objects from shared or cold
+storage (see --experimental-shared-storage and --experimental-cold-storage)
+inside local paths for each store. A size must
 be specified with this flag, which will be the maximum size for the secondary
 cache on each store on this node:
 <PRE>
@@ -1149,6 +1150,27 @@ initialized node is not supported.
 `, docs.URL("use-cloud-storage-for-bulk-operations")),
 	}
 
+	ColdStorage = FlagInfo{
+		Name: "experimental-cold-storage",
+		Description: fmt.Sprintf(`
+Storage URL (with a cloud scheme, eg. s3://, gcs://, nodelocal://) to use for
+cold data on this cockroach node. The stores of this node move the bottom-level
+SSTables whose keys all belong to zones with storage_tier = 'cold' to this
+storage, and keep all other SSTables on local disk. Reads of cold data are
+retried if the storage is temporarily unavailable, and may be cached locally
+with --experimental-secondary-cache. The format of this URL is the same as that specified
+for bulk operations, for more on that see:
+
+<PRE>
+%s
+</PRE>
+
+This is an experimental option, and must be specified on every start of this
+node starting from the very first call to start. It cannot be used with
+--experimental-shared-storage.
+`, docs.URL("use-cloud-storage-for-bulk-operations")),
+	}
+
 	Size = FlagInfo{
 		Name:      "size",
 		Shorthand: "z",
@@ -1939,6 +1961,28 @@ The bytekey format does not require table-key prefix.`,
 		Description: `Export revisions of data from a backup table up to a specific timestamp.`,
 	}
 
+	BackupEncryptionPassphrase = FlagInfo{
+		Name:        "encryption-passphrase",
+		Description: `The passphrase used to decrypt a backup encrypted with a passphrase.`,
+	}
+
+	BackupDecryptionKMSURI = FlagInfo{
+		Name: "decryption-kms-uri",
+		Description: `
+The URI of a KMS key used to decrypt a backup encrypted with KMS. The flag can
+be specified once per KMS URI the backup was encrypted with; any one of them is
+sufficient to decrypt the backup.
+`,
+	}
+
+	BackupIncrementalLocation = FlagInfo{
+		Name: "incremental-location",
+		Description: `
+The collection URI the incremental backups of the backup were written to, if
+they were not written to the default location of the collection.
+`,
+	}
+
 	Recursive = FlagInfo{
 		Name:      "recursive",
 		Shorthand: "r",
@@ -2047,6 +2091,10 @@ Valid fields:
 * key     (required): path to the current key file, or "plain"
 * old-key (required): path to the previous key file, or "plain"
 * rotation-period   : amount of time after which data keys should be rotated
+* kms               : URI of a KMS (e.g. kmip://host:5696/key-name?...) with
+                      which the key files were wrapped by
+                      "cockroach gen encryption-key --kms". The KMS must be
+                      reachable whenever the store is opened
 
 </PRE>
 example:
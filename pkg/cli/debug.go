@@ -1359,6 +1359,7 @@ var debugCmds = []*cobra.Command{
 	debugZipCmd,
 	debugMergeLogsCmd,
 	debugListFilesCmd,
+	debugAllocatorSimulateCmd,
 	debugResetQuorumCmd,
 	debugSendKVBatchCmd,
 	debugRecoverCmd,
@@ -1579,6 +1580,17 @@ func init() {
 	f.BoolVar(&debugZipUploadOpts.dryRun, "dry-run", false, "run in dry-run mode without making any actual uploads")
 	f.Lookup("dry-run").Hidden = true
 
+	f = debugAllocatorSimulateCmd.Flags()
+	f.DurationVar(&debugAllocatorSimulateOpts.duration, "duration", debugAllocatorSimulateOpts.duration,
+		"simulated duration to run the allocator for")
+	f.StringArrayVar(&debugAllocatorSimulateOpts.addNodes, "add-node", nil,
+		"locality of a node with a single store to add before simulating, e.g. region=us-east1,zone=us-east1-b. "+
+			"May be repeated to add multiple nodes.")
+	f.IntSliceVar(&debugAllocatorSimulateOpts.decommissionNode, "decommission-node", nil,
+		"IDs of nodes to decommission before simulating")
+	f.StringVar(&debugAllocatorSimulateOpts.zoneConfig, "zone-config", "",
+		"path to a YAML zone config to apply to every range instead of the replication factor observed in the debug zip")
+
 	f = debugDecodeKeyCmd.Flags()
 	f.Var(&decodeKeyOptions.encoding, "encoding", "key argument encoding")
 	f.BoolVar(&decodeKeyOptions.userKey, "user-key", false, "key type")
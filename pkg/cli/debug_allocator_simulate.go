@@ -0,0 +1,589 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlexec"
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/scheduled"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var debugAllocatorSimulateCmd = &cobra.Command{
+	Use:   "allocator-simulate <path to debug dir>",
+	Short: "simulate the allocator against the cluster captured in a debug zip",
+	Long: `
+Builds an allocator simulation from the contents of an unzipped debug zip and
+runs the allocator against it for the given duration.
+
+The simulated cluster has the nodes, stores, localities and capacities found in
+nodes/*/status.json and the ranges, replicas, leaseholders and per-range load
+found in nodes/*/ranges.json. The debug zip must therefore have been collected
+with --include-range-info. The load observed on each range is replayed for the
+whole simulation. The load, including cpu, and size of a range are taken from
+the leaseholder's hot range report in tenant_ranges/*.json when the debug zip
+has one, and from ranges.json otherwise.
+
+Each range is simulated with the span config found for it in
+system.span_configurations.txt. Ranges without a span config, e.g. in
+redacted debug zips, use the default zone configuration with the replication
+factor of their current replicas.
+
+Proposed changes can be applied before the simulation starts: nodes may be
+added with --add-node, decommissioned with --decommission-node, and the zone
+configuration applied to every range may be replaced with --zone-config.
+
+The simulation runs the replicate queue, lease queue, split queue and store
+rebalancer, as well as the multi-metric allocator's load based rebalancing. The
+simulator does not model cpu: the cpu used by each request and the cpu capacity
+of the nodes are derived from the hot range report and node statuses.
+
+The command reports the number of replica rebalances and lease transfers,
+the volume of data rebalanced, how long it took for the cluster to stop
+changing, and the per-store balance before and after the simulation.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: clierrorplus.MaybeDecorateError(runDebugAllocatorSimulate),
+}
+
+var debugAllocatorSimulateOpts = struct {
+	duration         time.Duration
+	addNodes         []string
+	decommissionNode []int
+	zoneConfig       string
+}{
+	duration: 30 * time.Minute,
+}
+
+// allocatorSimulateKeySpacing is the distance between the simulator keys
+// assigned to consecutive ranges. Leaving room between ranges allows the
+// simulated split queue to split them.
+const allocatorSimulateKeySpacing = 1000
+
+// allocatorSimulateInput is the cluster information read from a debug zip.
+type allocatorSimulateInput struct {
+	// nodes is sorted by node ID.
+	nodes []statuspb.NodeStatus
+	// ranges contains one entry per range ID, sorted by start key.
+	ranges []serverpb.RangeInfo
+	// hotRanges contains the leaseholder's hot range report for each range,
+	// if the debug zip has one.
+	hotRanges map[roachpb.RangeID]serverpb.TenantRangeInfo
+	// spanConfigs contains the entries of system.span_configurations, sorted
+	// by start key.
+	spanConfigs []roachpb.SpanConfigEntry
+}
+
+// spanConfig returns the span config that applies to the given key, if any.
+func (in *allocatorSimulateInput) spanConfig(key roachpb.RKey) (roachpb.SpanConfig, bool) {
+	i := sort.Search(len(in.spanConfigs), func(i int) bool {
+		return bytes.Compare(in.spanConfigs[i].Target.GetSpan().Key, key) > 0
+	})
+	if i == 0 {
+		return roachpb.SpanConfig{}, false
+	}
+	entry := in.spanConfigs[i-1]
+	if bytes.Compare(key, entry.Target.GetSpan().EndKey) >= 0 {
+		return roachpb.SpanConfig{}, false
+	}
+	return entry.Config, true
+}
+
+// rangeLoad returns the load of the range. The leaseholder's hot range report
+// is preferred since only the leaseholder tracks the load of a range, and
+// ranges.json may have come from a follower. It also includes the range's cpu.
+func (in *allocatorSimulateInput) rangeLoad(r serverpb.RangeInfo) serverpb.RangeStatistics {
+	if hr, ok := in.hotRanges[r.State.Desc.RangeID]; ok {
+		return hr.RangeStats
+	}
+	return r.Stats
+}
+
+// allocatorSimulateStore describes a simulated store in terms of the cluster
+// captured in the debug zip.
+type allocatorSimulateStore struct {
+	// storeID and nodeID are zero for stores that were added by the
+	// simulation.
+	storeID  roachpb.StoreID
+	nodeID   roachpb.NodeID
+	locality roachpb.Locality
+}
+
+// allocatorSimulateResult summarizes an allocator simulation.
+type allocatorSimulateResult struct {
+	duration       time.Duration
+	ranges         int
+	rebalances     int64
+	rebalanceBytes int64
+	leaseTransfers int64
+	// settled is the simulated time after which no more rebalances or lease
+	// transfers happened. It is zero if nothing changed.
+	settled time.Duration
+	// metricsInterval is the interval at which store metrics were recorded.
+	metricsInterval time.Duration
+	stores          map[state.StoreID]allocatorSimulateStore
+	// initial and final hold the first and last recorded metrics for each
+	// store.
+	initial, final map[state.StoreID]metrics.StoreMetrics
+}
+
+func runDebugAllocatorSimulate(_ *cobra.Command, args []string) error {
+	ctx := context.Background()
+	opts := debugAllocatorSimulateOpts
+
+	in, err := readAllocatorSimulateInput(args[0])
+	if err != nil {
+		return err
+	}
+
+	var zone *zonepb.ZoneConfig
+	if opts.zoneConfig != "" {
+		b, err := os.ReadFile(opts.zoneConfig)
+		if err != nil {
+			return err
+		}
+		zone = zonepb.NewZoneConfig()
+		if err := yaml.UnmarshalStrict(b, zone); err != nil {
+			return errors.Wrapf(err, "could not parse zone config %s", opts.zoneConfig)
+		}
+		if err := zone.Validate(); err != nil {
+			return err
+		}
+	}
+
+	var addLocalities []roachpb.Locality
+	for _, l := range opts.addNodes {
+		var locality roachpb.Locality
+		if err := locality.Set(l); err != nil {
+			return errors.Wrapf(err, "invalid locality for added node %q", l)
+		}
+		addLocalities = append(addLocalities, locality)
+	}
+
+	decommission := make([]roachpb.NodeID, len(opts.decommissionNode))
+	for i, id := range opts.decommissionNode {
+		decommission[i] = roachpb.NodeID(id)
+	}
+
+	res, err := simulateAllocator(ctx, in, opts.duration, zone, addLocalities, decommission)
+	if err != nil {
+		return err
+	}
+	return printAllocatorSimulateResult(res)
+}
+
+// readAllocatorSimulateInput reads the node statuses and range information
+// from the unzipped debug zip at dir.
+func readAllocatorSimulateInput(dir string) (allocatorSimulateInput, error) {
+	var in allocatorSimulateInput
+
+	statusFiles, err := filepath.Glob(filepath.Join(dir, "nodes", "*", statusFileName))
+	if err != nil {
+		return in, err
+	}
+	for _, f := range statusFiles {
+		var ns statuspb.NodeStatus
+		if err := readJSONFile(f, &ns); err != nil {
+			return in, err
+		}
+		if len(ns.StoreStatuses) == 0 {
+			// SQL-only nodes have no stores and can be ignored.
+			continue
+		}
+		in.nodes = append(in.nodes, ns)
+	}
+	if len(in.nodes) == 0 {
+		return in, errors.Newf("no node status found in %s", dir)
+	}
+	sort.Slice(in.nodes, func(i, j int) bool {
+		return in.nodes[i].Desc.NodeID < in.nodes[j].Desc.NodeID
+	})
+
+	rangeFiles, err := filepath.Glob(filepath.Join(dir, "nodes", "*", rangesInfoFileName))
+	if err != nil {
+		return in, err
+	}
+	// Every replica of a range reports the range, prefer the report from the
+	// leaseholder since it is the one tracking the range's load.
+	byRangeID := map[roachpb.RangeID]serverpb.RangeInfo{}
+	for _, f := range rangeFiles {
+		var ranges []serverpb.RangeInfo
+		if err := readJSONFile(f, &ranges); err != nil {
+			return in, err
+		}
+		for _, r := range ranges {
+			desc := r.State.Desc
+			if desc == nil {
+				continue
+			}
+			prev, ok := byRangeID[desc.RangeID]
+			if !ok || (!prev.IsLeaseholder && r.IsLeaseholder) ||
+				(prev.IsLeaseholder == r.IsLeaseholder && prev.State.Desc.Generation < desc.Generation) {
+				byRangeID[desc.RangeID] = r
+			}
+		}
+	}
+	if len(byRangeID) == 0 {
+		return in, errors.WithHint(
+			errors.Newf("no range information found in %s", dir),
+			"The debug zip must be collected with --include-range-info.")
+	}
+	for _, r := range byRangeID {
+		in.ranges = append(in.ranges, r)
+	}
+	// Redacted debug zips may not contain the range keys, fall back to the
+	// range ID to produce a stable order.
+	sort.Slice(in.ranges, func(i, j int) bool {
+		a, b := in.ranges[i].State.Desc, in.ranges[j].State.Desc
+		if c := bytes.Compare(a.StartKey, b.StartKey); c != 0 {
+			return c < 0
+		}
+		return a.RangeID < b.RangeID
+	})
+
+	hotRangeFiles, err := filepath.Glob(filepath.Join(dir, "tenant_ranges", "*.json"))
+	if err != nil {
+		return in, err
+	}
+	in.hotRanges = map[roachpb.RangeID]serverpb.TenantRangeInfo{}
+	for _, f := range hotRangeFiles {
+		var ranges []serverpb.TenantRangeInfo
+		if err := readJSONFile(f, &ranges); err != nil {
+			return in, err
+		}
+		for _, r := range ranges {
+			if r.IsLeaseholder {
+				in.hotRanges[r.RangeID] = r
+			}
+		}
+	}
+
+	const spanConfigsFile = "system.span_configurations.txt"
+	if checkIfFileExists(dir, spanConfigsFile) {
+		if err := slurp(dir, spanConfigsFile, func(row string) error {
+			// The columns are start_key, end_key and config.
+			fields := strings.Fields(row)
+			if len(fields) != 3 {
+				return errors.Newf("unexpected row in %s: %q", spanConfigsFile, row)
+			}
+			var raw [3][]byte
+			for i, f := range fields {
+				b, ok := interpretString(f)
+				if !ok {
+					return errors.Newf("failed to decode %q in %s", f, spanConfigsFile)
+				}
+				raw[i] = b
+			}
+			var conf roachpb.SpanConfig
+			if err := protoutil.Unmarshal(raw[2], &conf); err != nil {
+				return errors.Wrapf(err, "failed to decode span config in %s", spanConfigsFile)
+			}
+			in.spanConfigs = append(in.spanConfigs, roachpb.SpanConfigEntry{
+				Target: roachpb.SpanConfigTarget{
+					Union: &roachpb.SpanConfigTarget_Span{
+						Span: &roachpb.Span{Key: raw[0], EndKey: raw[1]},
+					},
+				},
+				Config: conf,
+			})
+			return nil
+		}); err != nil {
+			return in, err
+		}
+		sort.Slice(in.spanConfigs, func(i, j int) bool {
+			return bytes.Compare(in.spanConfigs[i].Target.GetSpan().Key, in.spanConfigs[j].Target.GetSpan().Key) < 0
+		})
+	}
+	return in, nil
+}
+
+func readJSONFile(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(b, v), "could not parse %s", path)
+}
+
+// simulateAllocator builds the simulator state from the debug zip input,
+// applies the proposed changes and runs the simulation for duration.
+func simulateAllocator(
+	ctx context.Context,
+	in allocatorSimulateInput,
+	duration time.Duration,
+	zone *zonepb.ZoneConfig,
+	addLocalities []roachpb.Locality,
+	decommission []roachpb.NodeID,
+) (allocatorSimulateResult, error) {
+	settings := config.DefaultSimulationSettings()
+	settings.MMARebalancing = true
+	// The multi-metric allocator balances cpu, which the simulator derives
+	// from the QPS. Calibrate the cpu per query using the recorded load of the
+	// ranges and the cpu capacity using the largest node.
+	var cpuNanos, queries float64
+	for _, r := range in.ranges {
+		load := in.rangeLoad(r)
+		cpuNanos += load.CPUTimePerSecond
+		queries += load.QueriesPerSecond
+	}
+	if cpuNanos > 0 && queries > 0 {
+		settings.MMACPUNanosPerQuery = int64(cpuNanos / queries)
+	}
+	var maxCPUs int32
+	for _, ns := range in.nodes {
+		if ns.NumCpus > maxCPUs {
+			maxCPUs = ns.NumCpus
+		}
+	}
+	if maxCPUs > 0 {
+		settings.MMANodeCPUCapacity = int64(maxCPUs) * time.Second.Nanoseconds()
+	}
+	s := state.NewState(settings)
+	res := allocatorSimulateResult{
+		duration:        duration,
+		ranges:          len(in.ranges),
+		stores:          map[state.StoreID]allocatorSimulateStore{},
+		metricsInterval: settings.MetricsInterval,
+	}
+
+	// The simulator assigns its own node and store IDs, remember how they map
+	// to the IDs in the debug zip.
+	nodeIDs := map[roachpb.NodeID]state.NodeID{}
+	storeIDs := map[roachpb.StoreID]state.StoreID{}
+	var maxCapacity int64
+	addNode := func(
+		nodeID roachpb.NodeID, locality roachpb.Locality, stores []roachpb.StoreDescriptor,
+	) error {
+		node := s.AddNode()
+		s.SetNodeLocality(node.NodeID(), locality)
+		if nodeID != 0 {
+			nodeIDs[nodeID] = node.NodeID()
+		}
+		for _, desc := range stores {
+			store, ok := s.AddStore(node.NodeID())
+			if !ok {
+				return errors.Newf("unable to add store to simulated node %d", node.NodeID())
+			}
+			if desc.Capacity.Capacity > 0 {
+				s.SetStoreCapacity(store.StoreID(), desc.Capacity.Capacity)
+			}
+			if desc.StoreID != 0 {
+				storeIDs[desc.StoreID] = store.StoreID()
+			}
+			res.stores[store.StoreID()] = allocatorSimulateStore{
+				storeID:  desc.StoreID,
+				nodeID:   nodeID,
+				locality: locality,
+			}
+		}
+		return nil
+	}
+	for _, ns := range in.nodes {
+		stores := make([]roachpb.StoreDescriptor, len(ns.StoreStatuses))
+		for i, ss := range ns.StoreStatuses {
+			stores[i] = ss.Desc
+			if ss.Desc.Capacity.Capacity > maxCapacity {
+				maxCapacity = ss.Desc.Capacity.Capacity
+			}
+		}
+		if err := addNode(ns.Desc.NodeID, ns.Desc.Locality, stores); err != nil {
+			return res, err
+		}
+	}
+	// Added nodes have a single store, sized like the largest existing store.
+	for _, locality := range addLocalities {
+		if err := addNode(
+			0, locality, []roachpb.StoreDescriptor{{Capacity: roachpb.StoreCapacity{Capacity: maxCapacity}}},
+		); err != nil {
+			return res, err
+		}
+	}
+
+	rangeInfos := make([]state.RangeInfo, 0, len(in.ranges))
+	rates := make([]workload.ReplayRate, 0, len(in.ranges))
+	for i, r := range in.ranges {
+		key := state.MinKey + state.Key(i*allocatorSimulateKeySpacing)
+		desc := r.State.Desc
+
+		var conf roachpb.SpanConfig
+		if zone != nil {
+			conf = zone.AsSpanConfig()
+		} else if c, ok := in.spanConfig(desc.StartKey); ok {
+			conf = c
+		} else {
+			// The range has no span config in the debug zip, infer the
+			// replication factor from the current replicas instead.
+			conf = zonepb.DefaultZoneConfigRef().AsSpanConfig()
+			voters := len(desc.Replicas().VoterDescriptors())
+			nonVoters := len(desc.Replicas().NonVoterDescriptors())
+			conf.NumReplicas = int32(voters + nonVoters)
+			if nonVoters > 0 {
+				conf.NumVoters = int32(voters)
+			}
+		}
+
+		ri := state.RangeInfo{
+			Descriptor: roachpb.RangeDescriptor{StartKey: key.ToRKey()},
+			Config:     &conf,
+		}
+		load := in.rangeLoad(r)
+		if r.State.Stats != nil {
+			ri.Size = r.State.Stats.Total()
+		}
+		if hr, ok := in.hotRanges[desc.RangeID]; ok && hr.MVCCStats != nil {
+			ri.Size = hr.MVCCStats.Total()
+		}
+		for _, repl := range desc.InternalReplicas {
+			storeID, ok := storeIDs[repl.StoreID]
+			if !ok {
+				return res, errors.Newf("r%d has a replica on s%d which is not in the debug zip",
+					desc.RangeID, repl.StoreID)
+			}
+			ri.Descriptor.InternalReplicas = append(ri.Descriptor.InternalReplicas, roachpb.ReplicaDescriptor{
+				NodeID:  roachpb.NodeID(nodeIDs[repl.NodeID]),
+				StoreID: roachpb.StoreID(storeID),
+				Type:    repl.Type,
+			})
+		}
+		if len(ri.Descriptor.InternalReplicas) == 0 {
+			return res, errors.Newf("r%d has no replicas", desc.RangeID)
+		}
+		ri.Leaseholder = state.StoreID(ri.Descriptor.InternalReplicas[0].StoreID)
+		if r.State.Lease != nil {
+			if storeID, ok := storeIDs[r.State.Lease.Replica.StoreID]; ok {
+				ri.Leaseholder = storeID
+			}
+		}
+		rangeInfos = append(rangeInfos, ri)
+
+		rates = append(rates, workload.ReplayRate{
+			Key:                 int64(key),
+			ReadsPerSecond:      load.ReadsPerSecond,
+			WritesPerSecond:     load.WritesPerSecond,
+			ReadBytesPerSecond:  load.ReadBytesPerSecond,
+			WriteBytesPerSecond: load.WriteBytesPerSecond,
+		})
+	}
+	state.LoadRangeInfo(s, rangeInfos...)
+
+	for _, nodeID := range decommission {
+		simNodeID, ok := nodeIDs[nodeID]
+		if !ok {
+			return res, errors.Newf("n%d is not in the debug zip", nodeID)
+		}
+		s.SetNodeLiveness(simNodeID, livenesspb.NodeLivenessStatus_DECOMMISSIONING)
+	}
+
+	tracker := metrics.NewTracker(settings.MetricsInterval)
+	sim := asim.NewSimulator(
+		duration,
+		[]workload.Generator{workload.NewReplayGenerator(settings.StartTime, rates)},
+		s,
+		settings,
+		tracker,
+		scheduled.NewExecutorWithNoEvents(),
+	)
+	sim.RunSim(ctx)
+
+	recorded := sim.History().Recorded
+	if len(recorded) == 0 {
+		return res, errors.Newf("simulation duration %s is too short to record any metrics", duration)
+	}
+	res.initial = map[state.StoreID]metrics.StoreMetrics{}
+	res.final = map[state.StoreID]metrics.StoreMetrics{}
+	for _, sm := range recorded[0] {
+		res.initial[state.StoreID(sm.StoreID)] = sm
+	}
+	var lastChanges int64
+	for _, tick := range recorded {
+		var rebalances, rebalanceBytes, leaseTransfers int64
+		for _, sm := range tick {
+			rebalances += sm.Rebalances
+			rebalanceBytes += sm.RebalanceSentBytes
+			leaseTransfers += sm.LeaseTransfers
+			res.final[state.StoreID(sm.StoreID)] = sm
+		}
+		// The store metrics are cumulative, the cluster settled at the last
+		// tick which observed a change.
+		if changes := rebalances + leaseTransfers; changes != lastChanges {
+			lastChanges = changes
+			res.settled = tick[0].Tick.Sub(settings.StartTime)
+		}
+		res.rebalances, res.rebalanceBytes, res.leaseTransfers = rebalances, rebalanceBytes, leaseTransfers
+	}
+	return res, nil
+}
+
+func printAllocatorSimulateResult(res allocatorSimulateResult) error {
+	fmt.Printf("simulated %s with %d ranges on %d stores\n", res.duration, res.ranges, len(res.stores))
+	fmt.Printf("replica rebalances: %d (%s)\n", res.rebalances, humanizeutil.IBytes(res.rebalanceBytes))
+	fmt.Printf("lease transfers: %d\n", res.leaseTransfers)
+	if res.rebalances+res.leaseTransfers == 0 {
+		fmt.Printf("no rebalancing was necessary\n")
+	} else if res.settled+res.metricsInterval >= res.duration {
+		fmt.Printf("rebalancing did not settle within %s\n", res.duration)
+	} else {
+		fmt.Printf("rebalancing settled after %s\n", res.settled)
+	}
+	fmt.Println()
+
+	storeIDs := make([]state.StoreID, 0, len(res.stores))
+	for storeID := range res.stores {
+		storeIDs = append(storeIDs, storeID)
+	}
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+
+	headers := []string{"node_id", "store_id", "locality",
+		"replicas_before", "replicas_after", "leases_before", "leases_after",
+		"qps_before", "qps_after", "disk_used_after"}
+	alignment := "rrlrrrrrrr"
+	var rows [][]string
+	for _, storeID := range storeIDs {
+		store := res.stores[storeID]
+		nodeID, storeIDStr := "new", "new"
+		if store.storeID != 0 {
+			nodeID, storeIDStr = fmt.Sprintf("%d", store.nodeID), fmt.Sprintf("%d", store.storeID)
+		}
+		before, after := res.initial[storeID], res.final[storeID]
+		rows = append(rows, []string{
+			nodeID,
+			storeIDStr,
+			store.locality.String(),
+			fmt.Sprintf("%d", before.Replicas),
+			fmt.Sprintf("%d", after.Replicas),
+			fmt.Sprintf("%d", before.Leases),
+			fmt.Sprintf("%d", after.Leases),
+			fmt.Sprintf("%d", before.QPS),
+			fmt.Sprintf("%d", after.QPS),
+			fmt.Sprintf("%.1f%%", after.DiskFractionUsed*100),
+		})
+	}
+	return sqlExecCtx.PrintQueryOutput(os.Stdout, stderr, headers, clisqlexec.NewRowSliceIter(rows, alignment))
+}
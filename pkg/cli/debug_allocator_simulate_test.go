@@ -0,0 +1,246 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAllocatorSimulateZip writes the status and range files of a debug zip
+// for a cluster with three nodes, each with a single store, and numRanges
+// ranges replicated on every store.
+func writeAllocatorSimulateZip(t *testing.T, dir string, numRanges int) {
+	writeJSON := func(path string, v interface{}) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, b, 0644))
+	}
+
+	var replicas []roachpb.ReplicaDescriptor
+	for i := 1; i <= 3; i++ {
+		nodeID := roachpb.NodeID(i)
+		storeID := roachpb.StoreID(i)
+		locality := roachpb.Locality{Tiers: []roachpb.Tier{{Key: "zone", Value: fmt.Sprintf("z%d", i)}}}
+		writeJSON(filepath.Join(dir, "nodes", fmt.Sprint(i), statusFileName), statuspb.NodeStatus{
+			Desc: roachpb.NodeDescriptor{NodeID: nodeID, Locality: locality},
+			StoreStatuses: []statuspb.StoreStatus{{Desc: roachpb.StoreDescriptor{
+				StoreID:  storeID,
+				Node:     roachpb.NodeDescriptor{NodeID: nodeID, Locality: locality},
+				Capacity: roachpb.StoreCapacity{Capacity: 1 << 40},
+			}}},
+		})
+		replicas = append(replicas, roachpb.ReplicaDescriptor{
+			NodeID: nodeID, StoreID: storeID, ReplicaID: roachpb.ReplicaID(i),
+		})
+	}
+
+	ranges := make([][]serverpb.RangeInfo, 3)
+	for i := 0; i < numRanges; i++ {
+		desc := &roachpb.RangeDescriptor{
+			RangeID:          roachpb.RangeID(i + 1),
+			StartKey:         roachpb.RKey(fmt.Sprintf("k%04d", i)),
+			EndKey:           roachpb.RKey(fmt.Sprintf("k%04d", i+1)),
+			InternalReplicas: replicas,
+		}
+		if i == 0 {
+			desc.StartKey = roachpb.RKeyMin
+		}
+		// Spread the leases over the stores, every store reports every range.
+		leaseholder := replicas[i%3]
+		for j := range ranges {
+			ranges[j] = append(ranges[j], serverpb.RangeInfo{
+				State: kvserverpb.RangeInfo{ReplicaState: kvserverpb.ReplicaState{
+					Desc:  desc,
+					Lease: &roachpb.Lease{Replica: leaseholder},
+					Stats: &enginepb.MVCCStats{KeyBytes: 1 << 20, ValBytes: 1 << 20},
+				}},
+				Stats: serverpb.RangeStatistics{
+					ReadsPerSecond:      10,
+					ReadBytesPerSecond:  1000,
+					WritesPerSecond:     1,
+					WriteBytesPerSecond: 100,
+				},
+				IsLeaseholder: j == i%3,
+			})
+		}
+	}
+	for j := range ranges {
+		writeJSON(filepath.Join(dir, "nodes", fmt.Sprint(j+1), rangesInfoFileName), ranges[j])
+	}
+}
+
+func TestDebugAllocatorSimulate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	const numRanges = 30
+	writeAllocatorSimulateZip(t, dir, numRanges)
+
+	in, err := readAllocatorSimulateInput(dir)
+	require.NoError(t, err)
+	require.Len(t, in.nodes, 3)
+	require.Len(t, in.ranges, numRanges)
+	for i, r := range in.ranges {
+		require.Equal(t, roachpb.RangeID(i+1), r.State.Desc.RangeID)
+		require.True(t, r.IsLeaseholder)
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		res, err := simulateAllocator(ctx, in, 10*time.Minute, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, res.stores, 3)
+		require.Equal(t, numRanges, res.ranges)
+		// Every store already holds a replica of every range, and the leases
+		// and load are balanced.
+		require.Zero(t, res.rebalances)
+		require.Zero(t, res.leaseTransfers)
+		for _, sm := range res.final {
+			require.Equal(t, int64(numRanges), sm.Replicas)
+		}
+	})
+
+	t.Run("add node", func(t *testing.T) {
+		added := roachpb.Locality{Tiers: []roachpb.Tier{{Key: "zone", Value: "z4"}}}
+		res, err := simulateAllocator(ctx, in, time.Hour, nil, []roachpb.Locality{added}, nil)
+		require.NoError(t, err)
+		require.Len(t, res.stores, 4)
+		require.Greater(t, res.rebalances, int64(0))
+		require.Greater(t, res.rebalanceBytes, int64(0))
+		for storeID, store := range res.stores {
+			if store.storeID == 0 {
+				require.Equal(t, added, store.locality)
+				require.Zero(t, res.initial[storeID].Replicas)
+				require.Greater(t, res.final[storeID].Replicas, int64(0))
+			}
+		}
+	})
+
+	t.Run("decommission unknown node", func(t *testing.T) {
+		_, err := simulateAllocator(ctx, in, time.Minute, nil, nil, []roachpb.NodeID{7})
+		require.ErrorContains(t, err, "n7 is not in the debug zip")
+	})
+
+	// storeMetrics returns the initial and final metrics of the store with the
+	// given ID in the debug zip.
+	storeMetrics := func(
+		t *testing.T, res allocatorSimulateResult, storeID roachpb.StoreID,
+	) (initial, final metrics.StoreMetrics) {
+		for simStoreID, store := range res.stores {
+			if store.storeID == storeID {
+				return res.initial[simStoreID], res.final[simStoreID]
+			}
+		}
+		t.Fatalf("s%d was not simulated", storeID)
+		return initial, final
+	}
+
+	t.Run("span configs", func(t *testing.T) {
+		dir := t.TempDir()
+		writeAllocatorSimulateZip(t, dir, numRanges)
+
+		conf := roachpb.TestingDefaultSpanConfig()
+		conf.NumReplicas = 3
+		conf.LeasePreferences = []roachpb.LeasePreference{{
+			Constraints: []roachpb.Constraint{{Type: roachpb.Constraint_REQUIRED, Key: "zone", Value: "z1"}},
+		}}
+		confBytes, err := protoutil.Marshal(&conf)
+		require.NoError(t, err)
+		// Only the ranges in [k0010, k0020) have a span config.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "system.span_configurations.txt"), []byte(fmt.Sprintf(
+			"start_key\tend_key\tconfig\n\\x%x\t\\x%x\t\\x%x\n", "k0010", "k0020", confBytes)), 0644))
+
+		in, err := readAllocatorSimulateInput(dir)
+		require.NoError(t, err)
+		require.Len(t, in.spanConfigs, 1)
+		_, ok := in.spanConfig(roachpb.RKey("k0009"))
+		require.False(t, ok)
+		c, ok := in.spanConfig(roachpb.RKey("k0010"))
+		require.True(t, ok)
+		require.Len(t, c.LeasePreferences, 1)
+		_, ok = in.spanConfig(roachpb.RKey("k0020"))
+		require.False(t, ok)
+
+		// Without the span configs the leases stay where they are, with them the
+		// leases of the ranges in [k0010, k0020) move to s1 in z1.
+		res, err := simulateAllocator(ctx, in, 30*time.Minute, nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, numRanges, res.ranges)
+		require.Greater(t, res.leaseTransfers, int64(0))
+		initial, final := storeMetrics(t, res, 1)
+		require.Equal(t, int64(numRanges/3), initial.Leases)
+		require.GreaterOrEqual(t, final.Leases, int64(10))
+	})
+
+	t.Run("hot ranges", func(t *testing.T) {
+		dir := t.TempDir()
+		writeAllocatorSimulateZip(t, dir, numRanges)
+
+		// The hot range reports of leaseholders are read from tenant_ranges. Make
+		// every range led by s1 hot, both in QPS and cpu.
+		var hot []serverpb.TenantRangeInfo
+		for i := 0; i < numRanges; i += 3 {
+			hot = append(hot, serverpb.TenantRangeInfo{
+				RangeID:       roachpb.RangeID(i + 1),
+				IsLeaseholder: true,
+				RangeStats: serverpb.RangeStatistics{
+					QueriesPerSecond: 1000,
+					ReadsPerSecond:   1000,
+					CPUTimePerSecond: 1e8,
+				},
+				MVCCStats: &enginepb.MVCCStats{KeyBytes: 1 << 20, ValBytes: 1 << 20},
+			})
+		}
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "tenant_ranges"), 0755))
+		b, err := json.Marshal(hot)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "tenant_ranges", "zone=z1.json"), b, 0644))
+
+		in, err := readAllocatorSimulateInput(dir)
+		require.NoError(t, err)
+		require.Len(t, in.hotRanges, numRanges/3)
+		// The hot range report takes precedence over ranges.json.
+		require.Equal(t, float64(1000), in.rangeLoad(in.ranges[0]).ReadsPerSecond)
+		require.Equal(t, float64(10), in.rangeLoad(in.ranges[1]).ReadsPerSecond)
+
+		// Unlike with the uniform load of the ranges.json files, the load of s1
+		// is shed to the other stores.
+		res, err := simulateAllocator(ctx, in, 30*time.Minute, nil, nil, nil)
+		require.NoError(t, err)
+		require.Greater(t, res.leaseTransfers, int64(0))
+		initial, final := storeMetrics(t, res, 1)
+		require.Less(t, final.Leases, initial.Leases)
+	})
+
+	t.Run("missing ranges", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "nodes", "1"), 0755))
+		b, err := json.Marshal(in.nodes[0])
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "nodes", "1", statusFileName), b, 0644))
+		_, err = readAllocatorSimulateInput(dir)
+		require.ErrorContains(t, err, "no range information found")
+	})
+}
@@ -533,6 +533,7 @@ func init() {
 		// passing a bootstrap configuration file.
 		cliflagcfg.StringFlag(f, &serverCfg.StorageConfig.SharedStorage.URI, cliflags.SharedStorage)
 		cliflagcfg.VarFlag(f, newSizeFlagVal(&serverCfg.StorageConfig.SharedStorage.Cache), cliflags.SecondaryCache)
+		cliflagcfg.StringFlag(f, &serverCfg.StorageConfig.ColdStorage.URI, cliflags.ColdStorage)
 		cliflagcfg.VarFlag(f, &serverCfg.MaxOffset, cliflags.MaxOffset)
 		cliflagcfg.BoolFlag(f, &serverCfg.DisableMaxOffsetCheck, cliflags.DisableMaxOffsetCheck)
 		cliflagcfg.StringFlag(f, &serverCfg.ClockDevicePath, cliflags.ClockDevice)
@@ -857,6 +858,7 @@ func init() {
 			demoCmd,
 			statementBundleRecreateCmd,
 			debugListFilesCmd,
+			debugAllocatorSimulateCmd,
 			debugJobTraceFromClusterCmd,
 			debugZipCmd,
 		},
@@ -1181,6 +1183,13 @@ func extraServerFlagInit(cmd *cobra.Command) error {
 		}
 	}
 
+	// Cold storage is configured through the same Pebble remote storage as
+	// shared storage, so the two cannot be combined.
+	if serverCfg.StorageConfig.ColdStorage.URI != "" && serverCfg.StorageConfig.SharedStorage.URI != "" {
+		return errors.Newf("--%s cannot be used with --%s",
+			cliflags.ColdStorage.Name, cliflags.SharedStorage.Name)
+	}
+
 	// Construct the main RPC listen address.
 	serverCfg.Addr = net.JoinHostPort(startCtx.serverListenAddr, serverListenPort)
 
@@ -1182,6 +1182,36 @@ func TestHttpAdvertiseAddrFlagValue(t *testing.T) {
 	}
 }
 
+func TestColdStorageFlag(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Avoid leaking configuration changes after the tests end.
+	defer initCLIDefaults()
+
+	f := startCmd.Flags()
+	for _, tc := range []struct {
+		args        []string
+		expectedErr string
+	}{
+		{[]string{"start", "--experimental-cold-storage=nodelocal://1/cold"}, ""},
+		{[]string{"start", "--experimental-shared-storage=nodelocal://1/shared"}, ""},
+		{[]string{"start", "--experimental-cold-storage=nodelocal://1/cold", "--experimental-shared-storage=nodelocal://1/shared"},
+			"--experimental-cold-storage cannot be used with --experimental-shared-storage"},
+	} {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			initCLIDefaults()
+			require.NoError(t, f.Parse(tc.args))
+			err := extraServerFlagInit(startCmd)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
 func TestHttpHostFlagValue(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -6,12 +6,16 @@
 package cli
 
 import (
+	"context"
 	"crypto/rand"
 	gohex "encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/errors"
 	"github.com/lestrrat-go/jwx/v2/jwk"
@@ -21,9 +25,18 @@ import (
 var aesSizeFlag int
 var overwriteKeyFlag bool
 var keyVersionFlag int
+var keyKMSFlag string
 
+// genEncryptionKey writes a new store key to encryptionKeyPath. If kms is not
+// nil, the key is wrapped with it before being written, and the file can only
+// be used in an --enterprise-encryption spec with the same kms field.
 func genEncryptionKey(
-	encryptionKeyPath string, aesSize int, overwriteKey bool, keyVersion int,
+	ctx context.Context,
+	encryptionKeyPath string,
+	aesSize int,
+	overwriteKey bool,
+	keyVersion int,
+	kms cloud.KMS,
 ) error {
 	// Check encryptionKeySize is suitable for the encryption algorithm.
 	if aesSize != 128 && aesSize != 192 && aesSize != 256 {
@@ -88,6 +101,13 @@ func genEncryptionKey(
 		return fmt.Errorf("unsupported version %d", keyVersion)
 	}
 
+	if kms != nil {
+		var err error
+		if b, err = kms.Encrypt(ctx, b); err != nil {
+			return errors.Wrapf(err, "wrapping key with KMS key %s", kms.MasterKeyID())
+		}
+	}
+
 	// Write key to the file with owner read/write permission.
 	openMode := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
 	if !overwriteKey {
@@ -114,12 +134,28 @@ var genEncryptionKeyCmd = &cobra.Command{
 
 Generates a key suitable for use as a store key for Encryption At Rest.
 The resulting key file will be 32 bytes (random key ID) + key_size in bytes.
+
+If --kms is specified, the key is wrapped with the given KMS before being
+written, and the same KMS URI must be passed in the kms field of
+--enterprise-encryption to use the key.
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
 		encryptionKeyPath := args[0]
 
-		err := genEncryptionKey(encryptionKeyPath, aesSizeFlag, overwriteKeyFlag, keyVersionFlag)
+		var kms cloud.KMS
+		if keyKMSFlag != "" {
+			var err error
+			kms, err = cloud.KMSFromURI(ctx, keyKMSFlag,
+				cloud.MakeNodeKMSEnv(cluster.MakeClusterSettings(), &base.ExternalIODirConfig{}))
+			if err != nil {
+				return err
+			}
+			defer func() { _ = kms.Close() }()
+		}
+
+		err := genEncryptionKey(ctx, encryptionKeyPath, aesSizeFlag, overwriteKeyFlag, keyVersionFlag, kms)
 
 		if err != nil {
 			return err
@@ -137,4 +173,6 @@ func init() {
 		"Overwrite key if it exists")
 	genEncryptionKeyCmd.PersistentFlags().IntVar(&keyVersionFlag, "version", 1,
 		"Encryption format version (1 or 2)")
+	genEncryptionKeyCmd.PersistentFlags().StringVar(&keyKMSFlag, "kms", "",
+		"URI of a KMS with which to wrap the key")
 }
@@ -6,12 +6,17 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip/kmiptestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/pebble/vfs"
@@ -23,6 +28,7 @@ func TestGenEncryptionKey(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
 
+	ctx := context.Background()
 	dir := t.TempDir()
 
 	for _, keyVersion := range []int{1, 2} {
@@ -31,7 +37,7 @@ func TestGenEncryptionKey(t *testing.T) {
 				keyName := fmt.Sprintf("aes-%d-v%d.key", keySize, keyVersion)
 				keyPath := filepath.Join(dir, keyName)
 
-				err := genEncryptionKey(keyPath, keySize, false, keyVersion)
+				err := genEncryptionKey(ctx, keyPath, keySize, false, keyVersion, nil /* kms */)
 				require.NoError(t, err)
 
 				if keyVersion == 1 {
@@ -47,12 +53,37 @@ func TestGenEncryptionKey(t *testing.T) {
 				// Key ID is hex encoded on load so it's 64 bytes here but 32 in the file size.
 				assert.EqualValues(t, 64, len(key.Info.KeyId))
 
-				err = genEncryptionKey(keyPath, keySize, false, keyVersion)
+				err = genEncryptionKey(ctx, keyPath, keySize, false, keyVersion, nil /* kms */)
 				require.ErrorContains(t, err, fmt.Sprintf("%s: file exists", keyName))
 
-				err = genEncryptionKey(keyPath, keySize, true /* overwrite */, keyVersion)
+				err = genEncryptionKey(ctx, keyPath, keySize, true /* overwrite */, keyVersion, nil /* kms */)
 				require.NoError(t, err)
 			})
 		}
 	}
 }
+
+func TestGenEncryptionKeyKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv := kmiptestutils.NewTestServer(t)
+	defer srv.Close()
+	srv.CreateKey("store-key")
+	kms, err := cloud.KMSFromURI(ctx, srv.URI("store-key"), cloud.MakeNodeKMSEnv(
+		cluster.MakeTestingClusterSettings(), &base.ExternalIODirConfig{}))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, kms.Close()) }()
+
+	keyPath := filepath.Join(t.TempDir(), "aes-256-v2.key")
+	require.NoError(t, genEncryptionKey(ctx, keyPath, 256, false, 2, kms))
+
+	// The key file does not contain the key in the clear.
+	_, err = engineccl.LoadKeyFromFile(vfs.Default, keyPath)
+	require.Error(t, err)
+
+	key, err := engineccl.LoadWrappedKeyFromFile(ctx, vfs.Default, keyPath, kms)
+	require.NoError(t, err)
+	assert.EqualValues(t, 32, len(key.Key))
+}
@@ -83,6 +83,7 @@ table_name NOT IN (
 	'databases',
 	'forward_dependencies',
 	'gossip_network',
+	'hot_keys',
 	'index_columns',
   'index_spans',
   'kv_builtin_function_comments',
@@ -13,7 +13,8 @@ func (d *ConnectionDetails) Type() ConnectionType {
 	case ConnectionProvider_nodelocal, ConnectionProvider_s3, ConnectionProvider_userfile,
 		ConnectionProvider_gs, ConnectionProvider_azure_storage:
 		return TypeStorage
-	case ConnectionProvider_gcp_kms, ConnectionProvider_aws_kms, ConnectionProvider_azure_kms:
+	case ConnectionProvider_gcp_kms, ConnectionProvider_aws_kms, ConnectionProvider_azure_kms,
+		ConnectionProvider_kmip:
 		return TypeKMS
 	case ConnectionProvider_kafka, ConnectionProvider_http, ConnectionProvider_https,
 		ConnectionProvider_webhookhttp, ConnectionProvider_webhookhttps, ConnectionProvider_gcpubsub:
@@ -17,6 +17,7 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/externalconn"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/gcp"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/httpsink"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/kmip"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/nullsink"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/userfile"
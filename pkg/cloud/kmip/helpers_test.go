@@ -0,0 +1,9 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kmip
+
+// DecodeEnvelope exports decodeEnvelope for tests.
+var DecodeEnvelope = decodeEnvelope
@@ -0,0 +1,407 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kmip
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip/ttlv"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// KMIPScheme is the URI scheme of a KMIP key, e.g.
+	// kmip://kms.example.com:5696/<key-name>.
+	KMIPScheme = "kmip"
+
+	// CACertParam is the base64 encoded PEM CA certificate used to verify the
+	// KMIP server. If unset, the system roots are used.
+	CACertParam = "KMIP_CA_CERT"
+	// ClientCertParam is the base64 encoded PEM client certificate presented
+	// to the KMIP server.
+	ClientCertParam = "KMIP_CLIENT_CERT"
+	// ClientKeyParam is the base64 encoded PEM private key of the client
+	// certificate.
+	ClientKeyParam = "KMIP_CLIENT_KEY"
+	// UsernameParam and PasswordParam are optional credentials sent with every
+	// request, for servers that require them in addition to a client
+	// certificate.
+	UsernameParam = "KMIP_USERNAME"
+	PasswordParam = "KMIP_PASSWORD"
+
+	defaultPort = "5696"
+	// defaultTimeout bounds a KMIP operation when the context has no deadline.
+	defaultTimeout = 30 * time.Second
+
+	ivLen  = 12
+	tagLen = 16
+
+	// envelopeVersion is the first byte of every ciphertext returned by
+	// Encrypt.
+	envelopeVersion byte = 1
+)
+
+// kmipKMS is a cloud.KMS backed by a symmetric AES key held by a KMIP server.
+// The key is referenced by name so that it can be rotated on the server
+// (e.g. by a KMIP ReKey, which moves the name to the replacement key) without
+// changing the URI: Encrypt always uses the key currently bearing the name,
+// and the ciphertexts it returns embed the unique identifier of that key so
+// that Decrypt keeps working after the name has moved on.
+type kmipKMS struct {
+	addr      string
+	keyName   string
+	tlsConfig *tls.Config
+	username  string
+	password  string
+}
+
+var _ cloud.KMS = &kmipKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeKMIPKMS, KMIPScheme)
+	cloud.RegisterRedactedParams(cloud.RedactedParams(ClientKeyParam, PasswordParam))
+}
+
+type kmsURIParams struct {
+	caCert     string
+	clientCert string
+	clientKey  string
+	username   string
+	password   string
+}
+
+// resolveKMSURIParams parses the `kmsURI` for all the supported KMS parameters.
+func resolveKMSURIParams(kmsURI cloud.ConsumeURL) (kmsURIParams, error) {
+	params := kmsURIParams{
+		caCert:     kmsURI.ConsumeParam(CACertParam),
+		clientCert: kmsURI.ConsumeParam(ClientCertParam),
+		clientKey:  kmsURI.ConsumeParam(ClientKeyParam),
+		username:   kmsURI.ConsumeParam(UsernameParam),
+		password:   kmsURI.ConsumeParam(PasswordParam),
+	}
+
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := kmsURI.RemainingQueryParams(); len(unknownParams) > 0 {
+		return kmsURIParams{}, errors.Errorf(
+			`unknown KMS query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	return params, nil
+}
+
+func decodePEMParam(name, value string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding value of %s", name)
+	}
+	return decoded, nil
+}
+
+func makeTLSConfig(host string, params kmsURIParams) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+	if params.caCert != "" {
+		caPEM, err := decodePEMParam(CACertParam, params.caCert)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, errors.Newf("no certificates found in %s", CACertParam)
+		}
+	}
+	if (params.clientCert == "") != (params.clientKey == "") {
+		return nil, errors.Newf("%s and %s must be set together", ClientCertParam, ClientKeyParam)
+	}
+	if params.clientCert != "" {
+		certPEM, err := decodePEMParam(ClientCertParam, params.clientCert)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := decodePEMParam(ClientKeyParam, params.clientKey)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing KMIP client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// MakeKMIPKMS is the factory method which returns a configured, ready-to-use
+// KMIP KMS object.
+func MakeKMIPKMS(ctx context.Context, uri string, env cloud.KMSEnv) (cloud.KMS, error) {
+	if env.KMSConfig().DisableOutbound {
+		return nil, errors.New("external IO must be enabled to use KMIP KMS")
+	}
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	keyName := strings.TrimPrefix(kmsURI.Path, "/")
+	if keyName == "" {
+		return nil, errors.Newf("path component of the KMS cannot be empty; must contain the name of the KMIP key")
+	}
+	if kmsURI.Hostname() == "" {
+		return nil, errors.Newf("host component of the KMS cannot be empty; must contain the address of the KMIP server")
+	}
+
+	kmsURIParams, err := resolveKMSURIParams(cloud.ConsumeURL{URL: kmsURI})
+	if err != nil {
+		return nil, err
+	}
+	if (kmsURIParams.username == "") != (kmsURIParams.password == "") {
+		return nil, errors.Newf("%s and %s must be set together", UsernameParam, PasswordParam)
+	}
+	tlsConfig, err := makeTLSConfig(kmsURI.Hostname(), kmsURIParams)
+	if err != nil {
+		return nil, err
+	}
+
+	port := kmsURI.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return &kmipKMS{
+		addr:      net.JoinHostPort(kmsURI.Hostname(), port),
+		keyName:   keyName,
+		tlsConfig: tlsConfig,
+		username:  kmsURIParams.username,
+		password:  kmsURIParams.password,
+	}, nil
+}
+
+// MasterKeyID implements the KMS interface.
+//
+// The ID is the name of the key rather than the unique identifier of the key
+// currently bearing it, so that it stays the same across key rotations.
+func (k *kmipKMS) MasterKeyID() string {
+	return k.keyName
+}
+
+// Encrypt implements the KMS interface.
+func (k *kmipKMS) Encrypt(ctx context.Context, data []byte) ([]byte, error) {
+	uid, err := k.locate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, ivLen)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	resp, err := k.roundTrip(ctx, ttlv.OperationEncrypt,
+		ttlv.TextString(ttlv.TagUniqueIdentifier, uid),
+		cryptographicParameters(),
+		ttlv.ByteString(ttlv.TagData, data),
+		ttlv.ByteString(ttlv.TagIVCounterNonce, iv),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, ok := resp.Find(ttlv.TagData)
+	if !ok {
+		return nil, errors.New("KMIP encrypt response is missing the ciphertext")
+	}
+	authTag, ok := resp.Find(ttlv.TagAuthenticatedEncryptionTag)
+	if !ok {
+		return nil, errors.New("KMIP encrypt response is missing the authentication tag")
+	}
+	// Servers may ignore the IV supplied by the client and generate their own.
+	if serverIV, ok := resp.Find(ttlv.TagIVCounterNonce); ok {
+		iv = serverIV.Bytes
+	}
+	return encodeEnvelope(uid, iv, authTag.Bytes, ciphertext.Bytes), nil
+}
+
+// Decrypt implements the KMS interface.
+func (k *kmipKMS) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	uid, iv, authTag, ciphertext, err := decodeEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.roundTrip(ctx, ttlv.OperationDecrypt,
+		ttlv.TextString(ttlv.TagUniqueIdentifier, uid),
+		cryptographicParameters(),
+		ttlv.ByteString(ttlv.TagData, ciphertext),
+		ttlv.ByteString(ttlv.TagIVCounterNonce, iv),
+		ttlv.ByteString(ttlv.TagAuthenticatedEncryptionTag, authTag),
+	)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, ok := resp.Find(ttlv.TagData)
+	if !ok {
+		return nil, errors.New("KMIP decrypt response is missing the plaintext")
+	}
+	return plaintext.Bytes, nil
+}
+
+// Close implements the KMS interface.
+func (k *kmipKMS) Close() error {
+	return nil
+}
+
+// locate returns the unique identifier of the key currently bearing the name
+// of the KMS key.
+func (k *kmipKMS) locate(ctx context.Context) (string, error) {
+	resp, err := k.roundTrip(ctx, ttlv.OperationLocate,
+		ttlv.Structure(ttlv.TagAttribute,
+			ttlv.TextString(ttlv.TagAttributeName, "Name"),
+			ttlv.Structure(ttlv.TagAttributeValue,
+				ttlv.TextString(ttlv.TagNameValue, k.keyName),
+				ttlv.Enumeration(ttlv.TagNameType, ttlv.NameTypeUninterpretedText),
+			),
+		),
+	)
+	if err != nil {
+		return "", err
+	}
+	// Servers return the matching keys most recently created first.
+	uids := resp.FindAll(ttlv.TagUniqueIdentifier)
+	if len(uids) == 0 {
+		return "", errors.Newf("KMIP key %q not found", k.keyName)
+	}
+	return string(uids[0].Bytes), nil
+}
+
+func cryptographicParameters() ttlv.Item {
+	return ttlv.Structure(ttlv.TagCryptographicParameters,
+		ttlv.Enumeration(ttlv.TagBlockCipherMode, ttlv.BlockCipherModeGCM),
+		ttlv.Enumeration(ttlv.TagCryptographicAlgorithm, ttlv.CryptographicAlgorithmAES),
+		ttlv.Integer(ttlv.TagTagLength, tagLen),
+	)
+}
+
+// roundTrip sends a request with a single batch item for the given operation
+// and returns the payload of the response.
+func (k *kmipKMS) roundTrip(
+	ctx context.Context, operation uint32, payload ...ttlv.Item,
+) (ttlv.Item, error) {
+	header := []ttlv.Item{
+		ttlv.Structure(ttlv.TagProtocolVersion,
+			ttlv.Integer(ttlv.TagProtocolVersionMajor, 1),
+			ttlv.Integer(ttlv.TagProtocolVersionMinor, 4),
+		),
+	}
+	if k.username != "" {
+		header = append(header, ttlv.Structure(ttlv.TagAuthentication,
+			ttlv.Structure(ttlv.TagCredential,
+				ttlv.Enumeration(ttlv.TagCredentialType, ttlv.CredentialTypeUsernamePasswd),
+				ttlv.Structure(ttlv.TagCredentialValue,
+					ttlv.TextString(ttlv.TagUsername, k.username),
+					ttlv.TextString(ttlv.TagPassword, k.password),
+				),
+			),
+		))
+	}
+	header = append(header, ttlv.Integer(ttlv.TagBatchCount, 1))
+	req := ttlv.Structure(ttlv.TagRequestMessage,
+		ttlv.Structure(ttlv.TagRequestHeader, header...),
+		ttlv.Structure(ttlv.TagBatchItem,
+			ttlv.Enumeration(ttlv.TagOperation, operation),
+			ttlv.Structure(ttlv.TagRequestPayload, payload...),
+		),
+	)
+
+	dialer := tls.Dialer{Config: k.tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", k.addr)
+	if err != nil {
+		return ttlv.Item{}, cloud.KMSInaccessible(errors.Wrapf(err, "connecting to KMIP server %s", k.addr))
+	}
+	defer conn.Close()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = timeutil.Now().Add(defaultTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return ttlv.Item{}, err
+	}
+	if err := ttlv.WriteMessage(conn, req); err != nil {
+		return ttlv.Item{}, cloud.KMSInaccessible(errors.Wrap(err, "sending KMIP request"))
+	}
+	resp, err := ttlv.ReadMessage(conn)
+	if err != nil {
+		return ttlv.Item{}, cloud.KMSInaccessible(errors.Wrap(err, "reading KMIP response"))
+	}
+	return checkResponse(resp, operation)
+}
+
+// checkResponse validates the response to a single operation request and
+// returns its payload.
+func checkResponse(resp ttlv.Item, operation uint32) (ttlv.Item, error) {
+	if resp.Tag != ttlv.TagResponseMessage {
+		return ttlv.Item{}, errors.Newf("unexpected KMIP response message %x", resp.Tag)
+	}
+	batchItem, ok := resp.Find(ttlv.TagBatchItem)
+	if !ok {
+		return ttlv.Item{}, errors.New("KMIP response has no batch item")
+	}
+	if op, ok := batchItem.Find(ttlv.TagOperation); ok && uint32(op.Integer) != operation {
+		return ttlv.Item{}, errors.Newf("KMIP response for operation %d, expected %d", op.Integer, operation)
+	}
+	status, ok := batchItem.Find(ttlv.TagResultStatus)
+	if !ok {
+		return ttlv.Item{}, errors.New("KMIP response has no result status")
+	}
+	if uint32(status.Integer) != ttlv.ResultStatusSuccess {
+		var reason int64
+		if r, ok := batchItem.Find(ttlv.TagResultReason); ok {
+			reason = r.Integer
+		}
+		return ttlv.Item{}, cloud.KMSInaccessible(errors.Newf(
+			"KMIP operation %d failed with status %d, reason %d: %s",
+			operation, status.Integer, reason, batchItem.Text(ttlv.TagResultMessage),
+		))
+	}
+	payload, _ := batchItem.Find(ttlv.TagResponsePayload)
+	return payload, nil
+}
+
+// encodeEnvelope encodes the output of a KMIP encrypt operation, along with
+// the identifier of the key which produced it, as a single ciphertext.
+func encodeEnvelope(uid string, iv, authTag, ciphertext []byte) []byte {
+	buf := []byte{envelopeVersion}
+	for _, b := range [][]byte{[]byte(uid), iv, authTag} {
+		buf = binary.AppendUvarint(buf, uint64(len(b)))
+		buf = append(buf, b...)
+	}
+	return append(buf, ciphertext...)
+}
+
+func decodeEnvelope(data []byte) (uid string, iv, authTag, ciphertext []byte, _ error) {
+	if len(data) == 0 || data[0] != envelopeVersion {
+		return "", nil, nil, nil, errors.New("unrecognized KMIP ciphertext")
+	}
+	r := bytes.NewReader(data[1:])
+	var fields [3][]byte
+	for i := range fields {
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n > uint64(r.Len()) {
+			return "", nil, nil, nil, errors.New("truncated KMIP ciphertext")
+		}
+		fields[i] = make([]byte, n)
+		_, _ = r.Read(fields[i])
+	}
+	ciphertext = data[len(data)-r.Len():]
+	return string(fields[0]), fields[1], fields[2], ciphertext, nil
+}
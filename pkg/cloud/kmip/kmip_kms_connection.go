@@ -0,0 +1,37 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kmip
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn/connectionpb"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn/utils"
+	"github.com/cockroachdb/errors"
+)
+
+func validateKMIPKMSConnectionURI(
+	ctx context.Context, env externalconn.ExternalConnEnv, uri string,
+) error {
+	if err := utils.CheckKMSConnection(ctx, env, uri); err != nil {
+		return errors.Wrap(err, "failed to create KMIP KMS external connection")
+	}
+
+	return nil
+}
+
+func init() {
+	externalconn.RegisterConnectionDetailsFromURIFactory(
+		KMIPScheme,
+		connectionpb.ConnectionProvider_kmip,
+		externalconn.SimpleURIFactory,
+	)
+	externalconn.RegisterDefaultValidation(
+		KMIPScheme,
+		validateKMIPKMSConnectionURI,
+	)
+}
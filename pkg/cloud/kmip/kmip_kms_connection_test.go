@@ -0,0 +1,18 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kmip
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn/connectionpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKMIPKMSConnection(t *testing.T) {
+	require.Equal(t, connectionpb.ConnectionProvider_kmip, externalconn.ProviderForURI("kmip://test/key"))
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kmip_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip"
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip/kmiptestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKMIPKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	srv := kmiptestutils.NewTestServer(t)
+	defer srv.Close()
+	srv.CreateKey("backup-key")
+
+	env := &cloud.TestKMSEnv{
+		Settings:         cluster.MakeTestingClusterSettings(),
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	}
+
+	t.Run("encrypt-decrypt", func(t *testing.T) {
+		cloud.KMSEncryptDecrypt(t, srv.URI("backup-key"), env)
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		kms, err := cloud.KMSFromURI(ctx, srv.URI("backup-key"), env)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, kms.Close()) }()
+
+		before, err := kms.Encrypt(ctx, []byte("before"))
+		require.NoError(t, err)
+		srv.Rotate("backup-key")
+		after, err := kms.Encrypt(ctx, []byte("after"))
+		require.NoError(t, err)
+
+		// The key ID is the name, which is unaffected by the rotation, while the
+		// ciphertexts refer to the keys which produced them.
+		require.Equal(t, "backup-key", kms.MasterKeyID())
+		beforeUID, _, _, _, err := kmip.DecodeEnvelope(before)
+		require.NoError(t, err)
+		afterUID, _, _, _, err := kmip.DecodeEnvelope(after)
+		require.NoError(t, err)
+		require.NotEqual(t, beforeUID, afterUID)
+
+		for plaintext, ciphertext := range map[string][]byte{"before": before, "after": after} {
+			decrypted, err := kms.Decrypt(ctx, ciphertext)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, string(decrypted))
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		kms, err := cloud.KMSFromURI(ctx, srv.URI("backup-key"), env)
+		require.NoError(t, err)
+		ciphertext, err := kms.Encrypt(ctx, []byte("secret"))
+		require.NoError(t, err)
+		ciphertext[len(ciphertext)-1] ^= 1
+		_, err = kms.Decrypt(ctx, ciphertext)
+		require.Error(t, err)
+		_, err = kms.Decrypt(ctx, []byte("garbage"))
+		require.ErrorContains(t, err, "unrecognized KMIP ciphertext")
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		kms, err := cloud.KMSFromURI(ctx, srv.URI("missing"), env)
+		require.NoError(t, err)
+		_, err = kms.Encrypt(ctx, []byte("secret"))
+		require.ErrorContains(t, err, `KMIP key "missing" not found`)
+	})
+
+	t.Run("invalid-uri", func(t *testing.T) {
+		for _, tc := range []struct {
+			uri string
+			err string
+		}{
+			{uri: srv.URI("backup-key") + "&foo=bar", err: "unknown KMS query parameters: foo"},
+			{uri: "kmip://localhost/", err: "path component of the KMS cannot be empty"},
+			{uri: "kmip:///backup-key", err: "host component of the KMS cannot be empty"},
+			{
+				uri: "kmip://localhost/backup-key?KMIP_CLIENT_CERT=YQ==",
+				err: "KMIP_CLIENT_CERT and KMIP_CLIENT_KEY must be set together",
+			},
+			{
+				uri: "kmip://localhost/backup-key?KMIP_USERNAME=u",
+				err: "KMIP_USERNAME and KMIP_PASSWORD must be set together",
+			},
+		} {
+			_, err := cloud.KMSFromURI(ctx, tc.uri, env)
+			require.ErrorContains(t, err, tc.err)
+		}
+	})
+
+	t.Run("disable-outbound", func(t *testing.T) {
+		_, err := cloud.KMSFromURI(ctx, srv.URI("backup-key"), &cloud.TestKMSEnv{
+			Settings:         env.Settings,
+			ExternalIOConfig: &base.ExternalIODirConfig{DisableOutbound: true},
+		})
+		require.ErrorContains(t, err, "external IO must be enabled to use KMIP KMS")
+	})
+
+	t.Run("no-client-cert", func(t *testing.T) {
+		// Without a client certificate the server rejects the connection.
+		q := url.Values{}
+		q.Set(kmip.CACertParam, base64.StdEncoding.EncodeToString(srv.CACertPEM()))
+		uri := "kmip://" + srv.Addr() + "/backup-key?" + q.Encode()
+		kms, err := cloud.KMSFromURI(ctx, uri, env)
+		require.NoError(t, err)
+		_, err = kms.Encrypt(ctx, []byte("secret"))
+		require.True(t, cloud.IsKMSInaccessible(err), "%+v", err)
+	})
+}
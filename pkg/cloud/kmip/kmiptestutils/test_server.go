@@ -0,0 +1,319 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kmiptestutils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip"
+	"github.com/cockroachdb/cockroach/pkg/cloud/kmip/ttlv"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// Result statuses and reasons returned by the TestServer.
+const (
+	resultStatusOperationFailed     uint32 = 0x01
+	resultReasonItemNotFound        uint32 = 0x01
+	resultReasonOperationNotSupport uint32 = 0x05
+	resultReasonInvalidField        uint32 = 0x07
+	resultReasonCryptographicFail   uint32 = 0x0A
+)
+
+// TestServer is an in-process KMIP server which supports just enough of the
+// protocol to back a KMIP KMS in tests: it locates AES keys by name and
+// encrypts and decrypts data with them using AES-GCM. Clients must present the
+// client certificate embedded in the URIs returned by URI.
+type TestServer struct {
+	ln       net.Listener
+	caPEM    []byte
+	certPEM  []byte
+	keyPEM   []byte
+	stopper  chan struct{}
+	serveErr chan error
+
+	mu struct {
+		syncutil.Mutex
+		nextID int
+		// keys maps the unique identifier of a key to its material.
+		keys map[string][]byte
+		// names maps the name of a key to the identifier of the key which
+		// currently bears it.
+		names map[string]string
+	}
+}
+
+// NewTestServer starts a TestServer listening on a local port. The caller is
+// responsible for closing it.
+func NewTestServer(t testing.TB) *TestServer {
+	caCert, caKey := makeTestCert(t, nil, nil, func(tmpl *x509.Certificate) {
+		tmpl.IsCA = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		tmpl.BasicConstraintsValid = true
+	})
+	serverCert, serverKey := makeTestCert(t, caCert, caKey, func(tmpl *x509.Certificate) {
+		tmpl.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1)}
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	})
+	clientCert, clientKey := makeTestCert(t, caCert, caKey, func(tmpl *x509.Certificate) {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+
+	s := &TestServer{
+		ln:       ln,
+		caPEM:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}),
+		certPEM:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Raw}),
+		stopper:  make(chan struct{}),
+		serveErr: make(chan error, 1),
+	}
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+	s.keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	s.mu.keys = make(map[string][]byte)
+	s.mu.names = make(map[string]string)
+
+	go func() { s.serveErr <- s.serve() }()
+	return s
+}
+
+// makeTestCert returns a certificate signed by the given parent, or a
+// self-signed certificate if the parent is nil.
+func makeTestCert(
+	t testing.TB,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+	configure func(tmpl *x509.Certificate),
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "kmip-test"},
+		NotBefore:    timeutil.Now().Add(-time.Hour),
+		NotAfter:     timeutil.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	configure(tmpl)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+// Close stops the server.
+func (s *TestServer) Close() {
+	select {
+	case <-s.stopper:
+		return
+	default:
+	}
+	close(s.stopper)
+	_ = s.ln.Close()
+	<-s.serveErr
+}
+
+// CreateKey creates a new AES-256 key with the given name and returns its
+// unique identifier.
+func (s *TestServer) CreateKey(name string) string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.nextID++
+	uid := strconv.Itoa(s.mu.nextID)
+	s.mu.keys[uid] = key
+	s.mu.names[name] = uid
+	return uid
+}
+
+// Rotate replaces the key bearing the given name with a new key, as a KMIP
+// ReKey operation would, and returns the unique identifier of the new key.
+// The replaced key remains available for decryption.
+func (s *TestServer) Rotate(name string) string {
+	return s.CreateKey(name)
+}
+
+// URI returns a kmip:// URI for the key with the given name, carrying the
+// credentials needed to connect to the server.
+func (s *TestServer) URI(name string) string {
+	q := url.Values{}
+	q.Set(kmip.CACertParam, base64.StdEncoding.EncodeToString(s.caPEM))
+	q.Set(kmip.ClientCertParam, base64.StdEncoding.EncodeToString(s.certPEM))
+	q.Set(kmip.ClientKeyParam, base64.StdEncoding.EncodeToString(s.keyPEM))
+	return fmt.Sprintf("%s://%s/%s?%s", kmip.KMIPScheme, s.Addr(), name, q.Encode())
+}
+
+// Addr returns the address the server listens on.
+func (s *TestServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// CACertPEM returns the PEM encoded certificate of the CA which signed the
+// certificates of the server and of its clients.
+func (s *TestServer) CACertPEM() []byte {
+	return s.caPEM
+}
+
+func (s *TestServer) serve() error {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			select {
+			case <-s.stopper:
+				return nil
+			default:
+				return err
+			}
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *TestServer) handleConn(conn net.Conn) {
+	defer conn.Close()
+	for {
+		req, err := ttlv.ReadMessage(conn)
+		if err != nil {
+			return
+		}
+		if err := ttlv.WriteMessage(conn, s.handleRequest(req)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *TestServer) handleRequest(req ttlv.Item) ttlv.Item {
+	batchItem, _ := req.Find(ttlv.TagBatchItem)
+	var operation uint32
+	if op, ok := batchItem.Find(ttlv.TagOperation); ok {
+		operation = uint32(op.Integer)
+	}
+	payload, _ := batchItem.Find(ttlv.TagRequestPayload)
+
+	var respItems []ttlv.Item
+	resp, reason, err := s.handleOperation(operation, payload)
+	if err != nil {
+		respItems = []ttlv.Item{
+			ttlv.Enumeration(ttlv.TagOperation, operation),
+			ttlv.Enumeration(ttlv.TagResultStatus, resultStatusOperationFailed),
+			ttlv.Enumeration(ttlv.TagResultReason, reason),
+			ttlv.TextString(ttlv.TagResultMessage, err.Error()),
+		}
+	} else {
+		respItems = []ttlv.Item{
+			ttlv.Enumeration(ttlv.TagOperation, operation),
+			ttlv.Enumeration(ttlv.TagResultStatus, ttlv.ResultStatusSuccess),
+			ttlv.Structure(ttlv.TagResponsePayload, resp...),
+		}
+	}
+	return ttlv.Structure(ttlv.TagResponseMessage,
+		ttlv.Structure(ttlv.TagResponseHeader,
+			ttlv.Structure(ttlv.TagProtocolVersion,
+				ttlv.Integer(ttlv.TagProtocolVersionMajor, 1),
+				ttlv.Integer(ttlv.TagProtocolVersionMinor, 4),
+			),
+			ttlv.DateTime(ttlv.TagTimeStamp, timeutil.Now().Unix()),
+			ttlv.Integer(ttlv.TagBatchCount, 1),
+		),
+		ttlv.Structure(ttlv.TagBatchItem, respItems...),
+	)
+}
+
+func (s *TestServer) handleOperation(operation uint32, payload ttlv.Item) ([]ttlv.Item, uint32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch operation {
+	case ttlv.OperationLocate:
+		attr, _ := payload.Find(ttlv.TagAttribute)
+		value, _ := attr.Find(ttlv.TagAttributeValue)
+		if attr.Text(ttlv.TagAttributeName) != "Name" {
+			return nil, resultReasonInvalidField, errors.New("only locating keys by name is supported")
+		}
+		var res []ttlv.Item
+		if uid, ok := s.mu.names[value.Text(ttlv.TagNameValue)]; ok {
+			res = append(res, ttlv.TextString(ttlv.TagUniqueIdentifier, uid))
+		}
+		return res, 0, nil
+
+	case ttlv.OperationEncrypt, ttlv.OperationDecrypt:
+		uid := payload.Text(ttlv.TagUniqueIdentifier)
+		key, ok := s.mu.keys[uid]
+		if !ok {
+			return nil, resultReasonItemNotFound, errors.Newf("key %q not found", uid)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, resultReasonCryptographicFail, err
+		}
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, resultReasonCryptographicFail, err
+		}
+		data, _ := payload.Find(ttlv.TagData)
+		iv, _ := payload.Find(ttlv.TagIVCounterNonce)
+		if len(iv.Bytes) != gcm.NonceSize() {
+			return nil, resultReasonInvalidField, errors.Newf("invalid IV length %d", len(iv.Bytes))
+		}
+		if operation == ttlv.OperationEncrypt {
+			sealed := gcm.Seal(nil, iv.Bytes, data.Bytes, nil)
+			ciphertext, authTag := sealed[:len(data.Bytes)], sealed[len(data.Bytes):]
+			return []ttlv.Item{
+				ttlv.TextString(ttlv.TagUniqueIdentifier, uid),
+				ttlv.ByteString(ttlv.TagData, ciphertext),
+				ttlv.ByteString(ttlv.TagAuthenticatedEncryptionTag, authTag),
+			}, 0, nil
+		}
+		authTag, _ := payload.Find(ttlv.TagAuthenticatedEncryptionTag)
+		plaintext, err := gcm.Open(nil, iv.Bytes, append(append([]byte(nil), data.Bytes...), authTag.Bytes...), nil)
+		if err != nil {
+			return nil, resultReasonCryptographicFail, err
+		}
+		return []ttlv.Item{
+			ttlv.TextString(ttlv.TagUniqueIdentifier, uid),
+			ttlv.ByteString(ttlv.TagData, plaintext),
+		}, 0, nil
+
+	default:
+		return nil, resultReasonOperationNotSupport, errors.Newf("operation %d is not supported", operation)
+	}
+}
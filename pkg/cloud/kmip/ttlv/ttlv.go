@@ -0,0 +1,285 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+// Package ttlv implements the subset of the KMIP Tag-Type-Length-Value (TTLV)
+// encoding needed to locate keys and to encrypt and decrypt data with them.
+// See section 9.1 of the KMIP 1.4 specification.
+package ttlv
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Tag identifies the meaning of an item.
+type Tag uint32
+
+// Tags of the items used by the client.
+const (
+	TagAttribute                  Tag = 0x420008
+	TagAttributeName              Tag = 0x42000A
+	TagAttributeValue             Tag = 0x42000B
+	TagAuthentication             Tag = 0x42000C
+	TagBatchCount                 Tag = 0x42000D
+	TagBatchItem                  Tag = 0x42000F
+	TagBlockCipherMode            Tag = 0x420011
+	TagCredential                 Tag = 0x420023
+	TagCredentialType             Tag = 0x420024
+	TagCredentialValue            Tag = 0x420025
+	TagCryptographicAlgorithm     Tag = 0x420028
+	TagCryptographicParameters    Tag = 0x42002B
+	TagIVCounterNonce             Tag = 0x42003D
+	TagName                       Tag = 0x420053
+	TagNameType                   Tag = 0x420054
+	TagNameValue                  Tag = 0x420055
+	TagOperation                  Tag = 0x42005C
+	TagPassword                   Tag = 0x4200A1
+	TagProtocolVersion            Tag = 0x420069
+	TagProtocolVersionMajor       Tag = 0x42006A
+	TagProtocolVersionMinor       Tag = 0x42006B
+	TagRequestHeader              Tag = 0x420077
+	TagRequestMessage             Tag = 0x420078
+	TagRequestPayload             Tag = 0x420079
+	TagResponseHeader             Tag = 0x42007A
+	TagResponseMessage            Tag = 0x42007B
+	TagResponsePayload            Tag = 0x42007C
+	TagResultMessage              Tag = 0x42007D
+	TagResultReason               Tag = 0x42007E
+	TagResultStatus               Tag = 0x42007F
+	TagTagLength                  Tag = 0x4200A7
+	TagTimeStamp                  Tag = 0x420092
+	TagUniqueIdentifier           Tag = 0x420094
+	TagUsername                   Tag = 0x420099
+	TagData                       Tag = 0x4200C2
+	TagAuthenticatedEncryptionTag Tag = 0x4200FF
+)
+
+// ItemType is the type of the value of an item.
+type ItemType byte
+
+const (
+	TypeStructure   ItemType = 0x01
+	TypeInteger     ItemType = 0x02
+	TypeLongInteger ItemType = 0x03
+	TypeEnumeration ItemType = 0x05
+	TypeBoolean     ItemType = 0x06
+	TypeTextString  ItemType = 0x07
+	TypeByteString  ItemType = 0x08
+	TypeDateTime    ItemType = 0x09
+)
+
+// Enumeration values used by the client.
+const (
+	OperationLocate  uint32 = 0x08
+	OperationEncrypt uint32 = 0x1F
+	OperationDecrypt uint32 = 0x20
+
+	ResultStatusSuccess uint32 = 0x00
+
+	BlockCipherModeGCM           uint32 = 0x09
+	CryptographicAlgorithmAES    uint32 = 0x03
+	NameTypeUninterpretedText    uint32 = 0x01
+	CredentialTypeUsernamePasswd uint32 = 0x01
+)
+
+const (
+	// ttlvHeaderLen is the length of the tag, type and length of an item.
+	ttlvHeaderLen = 8
+	// maxMessageLen bounds the size of a message read from a connection.
+	maxMessageLen = 1 << 20
+)
+
+// Item is a decoded TTLV item. Exactly one of the value fields is set,
+// according to its type.
+type Item struct {
+	Tag      Tag
+	Type     ItemType
+	Children []Item
+	Integer  int64
+	Bytes    []byte
+}
+
+// Structure returns a structure item with the given children.
+func Structure(t Tag, children ...Item) Item {
+	return Item{Tag: t, Type: TypeStructure, Children: children}
+}
+
+// Integer returns an integer item.
+func Integer(t Tag, v int32) Item {
+	return Item{Tag: t, Type: TypeInteger, Integer: int64(v)}
+}
+
+// Enumeration returns an enumeration item.
+func Enumeration(t Tag, v uint32) Item {
+	return Item{Tag: t, Type: TypeEnumeration, Integer: int64(v)}
+}
+
+// DateTime returns a date-time item holding the given Unix time.
+func DateTime(t Tag, v int64) Item {
+	return Item{Tag: t, Type: TypeDateTime, Integer: v}
+}
+
+// TextString returns a text string item.
+func TextString(t Tag, v string) Item {
+	return Item{Tag: t, Type: TypeTextString, Bytes: []byte(v)}
+}
+
+// ByteString returns a byte string item.
+func ByteString(t Tag, v []byte) Item {
+	return Item{Tag: t, Type: TypeByteString, Bytes: v}
+}
+
+// Find returns the first child of a structure with the given tag.
+func (it Item) Find(t Tag) (Item, bool) {
+	for _, c := range it.Children {
+		if c.Tag == t {
+			return c, true
+		}
+	}
+	return Item{}, false
+}
+
+// FindAll returns the children of a structure with the given tag.
+func (it Item) FindAll(t Tag) []Item {
+	var res []Item
+	for _, c := range it.Children {
+		if c.Tag == t {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Text returns the value of the child text string with the given tag, or the
+// empty string if there is none.
+func (it Item) Text(t Tag) string {
+	c, ok := it.Find(t)
+	if !ok {
+		return ""
+	}
+	return string(c.Bytes)
+}
+
+func padLen(n int) int {
+	return (n + 7) &^ 7
+}
+
+// Encode appends the TTLV encoding of the item to buf.
+func (it Item) Encode(buf []byte) []byte {
+	var header [ttlvHeaderLen]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(it.Tag)<<8|uint32(it.Type))
+	switch it.Type {
+	case TypeStructure:
+		start := len(buf)
+		buf = append(buf, header[:]...)
+		for _, c := range it.Children {
+			buf = c.Encode(buf)
+		}
+		binary.BigEndian.PutUint32(buf[start+4:], uint32(len(buf)-start-ttlvHeaderLen))
+		return buf
+	case TypeInteger, TypeEnumeration:
+		binary.BigEndian.PutUint32(header[4:], 4)
+		buf = append(buf, header[:]...)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(it.Integer))
+		return append(buf, 0, 0, 0, 0)
+	case TypeLongInteger, TypeDateTime, TypeBoolean:
+		binary.BigEndian.PutUint32(header[4:], 8)
+		buf = append(buf, header[:]...)
+		return binary.BigEndian.AppendUint64(buf, uint64(it.Integer))
+	default:
+		binary.BigEndian.PutUint32(header[4:], uint32(len(it.Bytes)))
+		buf = append(buf, header[:]...)
+		buf = append(buf, it.Bytes...)
+		return append(buf, make([]byte, padLen(len(it.Bytes))-len(it.Bytes))...)
+	}
+}
+
+// Decode decodes the item at the start of buf, returning the rest of buf.
+func Decode(buf []byte) (Item, []byte, error) {
+	if len(buf) < ttlvHeaderLen {
+		return Item{}, nil, errors.New("truncated KMIP item header")
+	}
+	it := Item{
+		Tag:  Tag(binary.BigEndian.Uint32(buf[:4]) >> 8),
+		Type: ItemType(buf[3]),
+	}
+	n := int(binary.BigEndian.Uint32(buf[4:8]))
+	buf = buf[ttlvHeaderLen:]
+	if n > len(buf) {
+		return Item{}, nil, errors.Newf("truncated KMIP item %x", it.Tag)
+	}
+	switch it.Type {
+	case TypeStructure:
+		rest := buf[:n]
+		for len(rest) > 0 {
+			var c Item
+			var err error
+			c, rest, err = Decode(rest)
+			if err != nil {
+				return Item{}, nil, err
+			}
+			it.Children = append(it.Children, c)
+		}
+		return it, buf[n:], nil
+	case TypeInteger, TypeEnumeration:
+		if n != 4 || len(buf) < 8 {
+			return Item{}, nil, errors.Newf("invalid length %d for KMIP item %x", n, it.Tag)
+		}
+		it.Integer = int64(int32(binary.BigEndian.Uint32(buf[:4])))
+		if it.Type == TypeEnumeration {
+			it.Integer = int64(binary.BigEndian.Uint32(buf[:4]))
+		}
+		return it, buf[8:], nil
+	case TypeLongInteger, TypeDateTime, TypeBoolean:
+		if n != 8 {
+			return Item{}, nil, errors.Newf("invalid length %d for KMIP item %x", n, it.Tag)
+		}
+		it.Integer = int64(binary.BigEndian.Uint64(buf[:8]))
+		return it, buf[8:], nil
+	case TypeTextString, TypeByteString:
+		padded := padLen(n)
+		if padded > len(buf) {
+			return Item{}, nil, errors.Newf("truncated KMIP item %x", it.Tag)
+		}
+		it.Bytes = append([]byte(nil), buf[:n]...)
+		return it, buf[padded:], nil
+	default:
+		// Items of types the client does not use are skipped.
+		padded := padLen(n)
+		if padded > len(buf) {
+			return Item{}, nil, errors.Newf("truncated KMIP item %x", it.Tag)
+		}
+		return it, buf[padded:], nil
+	}
+}
+
+// WriteMessage writes the encoding of the message to w.
+func WriteMessage(w io.Writer, msg Item) error {
+	_, err := w.Write(msg.Encode(nil))
+	return err
+}
+
+// ReadMessage reads a single message from r.
+func ReadMessage(r io.Reader) (Item, error) {
+	buf := make([]byte, ttlvHeaderLen)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return Item{}, err
+	}
+	if ItemType(buf[3]) != TypeStructure {
+		return Item{}, errors.Newf("unexpected KMIP message type %x", buf[3])
+	}
+	n := int(binary.BigEndian.Uint32(buf[4:8]))
+	if n > maxMessageLen {
+		return Item{}, errors.Newf("KMIP message of %d bytes exceeds the maximum of %d", n, maxMessageLen)
+	}
+	buf = append(buf, make([]byte, n)...)
+	if _, err := io.ReadFull(r, buf[ttlvHeaderLen:]); err != nil {
+		return Item{}, err
+	}
+	msg, _, err := Decode(buf)
+	return msg, err
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package ttlv
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	msg := Structure(TagRequestMessage,
+		Integer(TagBatchCount, -3),
+		Enumeration(TagOperation, OperationEncrypt),
+		DateTime(TagTimeStamp, 1700000000),
+		TextString(TagUniqueIdentifier, "abc"),
+		ByteString(TagData, []byte("0123456789")),
+		Structure(TagBatchItem),
+	)
+	encoded := msg.Encode(nil)
+	require.Zero(t, len(encoded)%8)
+
+	decoded, rest, err := Decode(encoded)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.Equal(t, msg.Encode(nil), decoded.Encode(nil))
+	require.Equal(t, "abc", decoded.Text(TagUniqueIdentifier))
+	c, ok := decoded.Find(TagBatchCount)
+	require.True(t, ok)
+	require.Equal(t, int64(-3), c.Integer)
+
+	_, _, err = Decode(encoded[:len(encoded)-8])
+	require.Error(t, err)
+}
@@ -40,6 +40,27 @@ type KMSEnv interface {
 	User() username.SQLUsername
 }
 
+// nodeKMSEnv is a KMSEnv for KMS use outside of a SQL session, e.g. when a
+// node unwraps its encryption-at-rest store keys during startup.
+type nodeKMSEnv struct {
+	settings *cluster.Settings
+	conf     *base.ExternalIODirConfig
+}
+
+var _ KMSEnv = &nodeKMSEnv{}
+
+// MakeNodeKMSEnv returns a KMSEnv which acts as the node user and has no
+// database handle. It is suitable for KMS providers which do not need to read
+// any SQL state, such as those whose credentials are carried in the URI.
+func MakeNodeKMSEnv(settings *cluster.Settings, conf *base.ExternalIODirConfig) KMSEnv {
+	return &nodeKMSEnv{settings: settings, conf: conf}
+}
+
+func (e *nodeKMSEnv) ClusterSettings() *cluster.Settings   { return e.settings }
+func (e *nodeKMSEnv) KMSConfig() *base.ExternalIODirConfig { return e.conf }
+func (e *nodeKMSEnv) DBHandle() isql.DB                    { return nil }
+func (e *nodeKMSEnv) User() username.SQLUsername           { return username.NodeUserName() }
+
 // KMSFromURIFactory describes a factory function for KMS given a URI.
 type KMSFromURIFactory func(ctx context.Context, uri string, env KMSEnv) (KMS, error)
 
@@ -93,6 +93,12 @@ var MultiRegionZoneConfigFields = []tree.Name{
 	"lease_preferences",
 }
 
+// Storage tiers which may be set through the storage_tier zone config field.
+const (
+	StorageTierHot  = "hot"
+	StorageTierCold = "cold"
+)
+
 var MaxReplicasPerRegion = settings.RegisterIntSetting(
 	settings.ApplicationLevel,
 	"sql.zone_configs.max_replicas_per_region",
@@ -439,6 +445,15 @@ func (z *ZoneConfig) Validate() error {
 			*z.RangeMinBytes, *z.RangeMaxBytes)
 	}
 
+	if z.StorageTier != nil {
+		switch *z.StorageTier {
+		case StorageTierHot, StorageTierCold:
+		default:
+			return fmt.Errorf("storage_tier must be either %q or %q, found %q",
+				StorageTierHot, StorageTierCold, *z.StorageTier)
+		}
+	}
+
 	// Reserve the value 0 to potentially have some special meaning in the future,
 	// such as to disable GC.
 	if z.GC != nil && z.GC.TTLSeconds < 1 {
@@ -597,6 +612,11 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.GlobalReads = proto.Bool(*parent.GlobalReads)
 		}
 	}
+	if z.StorageTier == nil {
+		if parent.StorageTier != nil {
+			z.StorageTier = proto.String(*parent.StorageTier)
+		}
+	}
 	if z.RangeMinBytes == nil {
 		if parent.RangeMinBytes != nil {
 			z.RangeMinBytes = proto.Int64(*parent.RangeMinBytes)
@@ -656,6 +676,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.GlobalReads != nil {
 				z.GlobalReads = proto.Bool(*other.GlobalReads)
 			}
+		case "storage_tier":
+			z.StorageTier = nil
+			if other.StorageTier != nil {
+				z.StorageTier = proto.String(*other.StorageTier)
+			}
 		case "gc.ttlseconds":
 			z.GC = nil
 			if other.GC != nil {
@@ -1346,6 +1371,17 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 	if z.NumVoters != nil {
 		sc.NumVoters = *z.NumVoters
 	}
+	// StorageTier is DEFAULT if unset.
+	if z.StorageTier != nil {
+		switch *z.StorageTier {
+		case StorageTierHot:
+			sc.StorageTier = roachpb.StorageTier_HOT
+		case StorageTierCold:
+			sc.StorageTier = roachpb.StorageTier_COLD
+		default:
+			return roachpb.SpanConfig{}, errors.AssertionFailedf("unknown storage tier: %q", *z.StorageTier)
+		}
+	}
 
 	toSpanConfigConstraints := func(src []Constraint) ([]roachpb.Constraint, error) {
 		spanConfigConstraints := make([]roachpb.Constraint, len(src))
@@ -247,6 +247,24 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"",
 		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				StorageTier:   proto.String("lukewarm"),
+			},
+			`storage_tier must be either "hot" or "cold", found "lukewarm"`,
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				StorageTier:   proto.String(StorageTierCold),
+			},
+			"",
+		},
 	}
 
 	for i, c := range testCases {
@@ -1426,6 +1444,64 @@ func TestZoneConfigToSpanConfigConversion(t *testing.T) {
 				},
 			},
 		},
+		{
+			// The storage tier is carried into the span config, where stores use it
+			// to place their SSTables. It does not constrain replica placement.
+			zoneConfig: ZoneConfig{
+				RangeMinBytes: proto.Int64(100000),
+				RangeMaxBytes: proto.Int64(200000),
+				GC: &GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: proto.Int32(3),
+				StorageTier: proto.String(StorageTierCold),
+			},
+			expectSpanConfig: roachpb.SpanConfig{
+				RangeMinBytes: 100000,
+				RangeMaxBytes: 200000,
+				GCPolicy: roachpb.GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: 3,
+				StorageTier: roachpb.StorageTier_COLD,
+			},
+		},
+		{
+			zoneConfig: ZoneConfig{
+				RangeMinBytes: proto.Int64(100000),
+				RangeMaxBytes: proto.Int64(200000),
+				GC: &GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: proto.Int32(3),
+				StorageTier: proto.String(StorageTierHot),
+				Constraints: []ConstraintsConjunction{
+					{
+						NumReplicas: 1,
+						Constraints: []Constraint{
+							{Type: Constraint_REQUIRED, Key: "region", Value: "region_a"},
+						},
+					},
+				},
+			},
+			expectSpanConfig: roachpb.SpanConfig{
+				RangeMinBytes: 100000,
+				RangeMaxBytes: 200000,
+				GCPolicy: roachpb.GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: 3,
+				StorageTier: roachpb.StorageTier_HOT,
+				Constraints: []roachpb.ConstraintsConjunction{
+					{
+						NumReplicas: 1,
+						Constraints: []roachpb.Constraint{
+							{Type: roachpb.Constraint_REQUIRED, Key: "region", Value: "region_a"},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		spanConfig, err := tc.zoneConfig.toSpanConfig()
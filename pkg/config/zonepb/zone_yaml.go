@@ -200,6 +200,7 @@ type marshalableZoneConfig struct {
 	GlobalReads                  *bool             `json:"global_reads" yaml:"global_reads"`
 	NumReplicas                  *int32            `json:"num_replicas" yaml:"num_replicas"`
 	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters"`
+	StorageTier                  *string           `json:"storage_tier,omitempty" yaml:"storage_tier,omitempty"`
 	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
 	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
 	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
@@ -230,6 +231,9 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if c.NumVoters != nil && *c.NumVoters != 0 {
 		m.NumVoters = proto.Int32(*c.NumVoters)
 	}
+	if c.StorageTier != nil {
+		m.StorageTier = proto.String(*c.StorageTier)
+	}
 	// NB: In order to preserve round-trippability, we're directly using
 	// `NullVoterConstraintsIsEmpty` as opposed to calling
 	// `c.InheritedVoterConstraints()`. This is copacetic as long as the value is
@@ -270,6 +274,9 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.NumVoters != nil {
 		c.NumVoters = proto.Int32(*m.NumVoters)
 	}
+	if m.StorageTier != nil {
+		c.StorageTier = proto.String(*m.StorageTier)
+	}
 	c.VoterConstraints = m.VoterConstraints.Constraints
 	c.NullVoterConstraintsIsEmpty = !m.VoterConstraints.Inherited
 	if m.LeasePreferences != nil {
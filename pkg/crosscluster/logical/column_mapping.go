@@ -0,0 +1,369 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// columnMappingsByTable maps the name of a source table, as written in the
+// CREATE LOGICAL REPLICATION STREAM statement, to its COLUMN and EXCLUDE
+// COLUMN options.
+type columnMappingsByTable map[string]*tableColumnMappings
+
+// tableColumnMappings are the COLUMN and EXCLUDE COLUMN options of a table.
+type tableColumnMappings struct {
+	// exprs maps the name of a destination column to the expression over the
+	// source columns that computes it. tree.DefaultVal means the column is not
+	// replicated.
+	exprs map[string]tree.Expr
+	// excluded is the set of source columns that are not replicated.
+	excluded map[string]struct{}
+}
+
+func (m columnMappingsByTable) forTable(name string) *tableColumnMappings {
+	if m[name] == nil {
+		m[name] = &tableColumnMappings{
+			exprs:    make(map[string]tree.Expr),
+			excluded: make(map[string]struct{}),
+		}
+	}
+	return m[name]
+}
+
+// hasColumnMappings returns true if any of the replicated tables has column
+// mappings. Column mappings are only evaluated by the crud writer, so jobs with
+// column mappings always use it.
+func hasColumnMappings(pairs []jobspb.LogicalReplicationDetails_ReplicationPair) bool {
+	for _, pair := range pairs {
+		if len(pair.ColumnMappings) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveColumnMappings validates the COLUMN and EXCLUDE COLUMN options of a
+// table and returns the mapping of every replicated column of the destination
+// table. Destination columns without a COLUMN option are replicated from the
+// source column with the same name, if there is one.
+func resolveColumnMappings(
+	ctx context.Context, src, dst catalog.TableDescriptor, m *tableColumnMappings,
+) ([]jobspb.LogicalReplicationDetails_ColumnMapping, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	srcKey := src.GetPrimaryIndex().CollectKeyColumnIDs()
+	for name := range m.excluded {
+		col := findPublicColumn(src, name)
+		if col == nil {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"source table %s has no column %q", src.GetName(), name)
+		}
+		if srcKey.Contains(col.GetID()) {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot exclude primary key column %q of source table %s", name, src.GetName())
+		}
+	}
+	for name := range m.exprs {
+		col := findPublicColumn(dst, name)
+		if col == nil {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"destination table %s has no column %q", dst.GetName(), name)
+		}
+		if col.IsComputed() {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot map computed column %q of destination table %s", name, dst.GetName())
+		}
+	}
+
+	var mappings []jobspb.LogicalReplicationDetails_ColumnMapping
+	for _, col := range getColumnSchema(dst) {
+		name := col.column.GetName()
+		expr, ok := m.exprs[name]
+		if !ok {
+			if _, excluded := m.excluded[name]; excluded || findPublicColumn(src, name) == nil {
+				continue
+			}
+			expr = &tree.ColumnItem{ColumnName: tree.Name(name)}
+		}
+		if _, ok := expr.(tree.DefaultVal); ok {
+			continue
+		}
+		mappings = append(mappings, jobspb.LogicalReplicationDetails_ColumnMapping{
+			ColumnID: col.column.GetID(),
+			Expr:     tree.Serialize(expr),
+		})
+	}
+
+	mapper, err := newColumnMapper(ctx, nil /* evalCtx */, src, dst, mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every column of the source table must either be replicated or be
+	// explicitly excluded, which guards against typos in the mappings.
+	used := make(map[string]struct{}, len(mapper.sourceColumns))
+	for _, name := range mapper.sourceColumns {
+		if _, excluded := m.excluded[name]; excluded {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"source column %q of table %s is excluded but is used by a column mapping", name, src.GetName())
+		}
+		used[name] = struct{}{}
+	}
+	for _, col := range src.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		_, isUsed := used[col.GetName()]
+		_, isExcluded := m.excluded[col.GetName()]
+		if !isUsed && !isExcluded {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"source column %q of table %s is not replicated to any destination column",
+				col.GetName(), src.GetName())
+		}
+	}
+
+	return mappings, nil
+}
+
+// checkColumnMappings verifies that the columns of the destination table can
+// be computed from the columns of the source table. Unlike the table
+// equivalence check, it tolerates columns that exist on only one side of the
+// stream.
+func checkColumnMappings(
+	ctx context.Context,
+	src, dst catalog.TableDescriptor,
+	mappings []jobspb.LogicalReplicationDetails_ColumnMapping,
+) error {
+	_, err := newColumnMapper(ctx, nil /* evalCtx */, src, dst, mappings)
+	return err
+}
+
+// columnMapper computes the replicated columns of a destination table from a
+// row decoded with the source table's descriptor.
+//
+// If the job has column mappings, only the destination columns they list are
+// replicated. Otherwise, destination columns are replicated from the source
+// columns with the same name. In both cases, destination columns that are not
+// replicated are left out of the crud writer's statements, so they take their
+// default value when a row is inserted and keep their local value when a row
+// is updated. Source columns that are not referenced are never decoded. This
+// allows columns to be added to either table without interrupting the stream.
+type columnMapper struct {
+	// columns are the replicated columns of the destination table, in the
+	// order of getColumnSchema.
+	columns []columnSchema
+	// sourceColumns are the names of the source columns to decode.
+	sourceColumns []string
+	// exprs compute each of the replicated columns from the source columns.
+	// Columns which are copied from a source column are an *tree.IndexedVar.
+	exprs []tree.TypedExpr
+
+	evalCtx *eval.Context
+	row     sourceRow
+}
+
+// sourceRow exposes the decoded source columns to the mapping expressions.
+type sourceRow struct {
+	types  []*types.T
+	datums tree.Datums
+}
+
+var _ eval.IndexedVarContainer = &sourceRow{}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (r *sourceRow) IndexedVarResolvedType(idx int) *types.T {
+	return r.types[idx]
+}
+
+// IndexedVarEval implements the eval.IndexedVarContainer interface.
+func (r *sourceRow) IndexedVarEval(idx int) (tree.Datum, error) {
+	return r.datums[idx], nil
+}
+
+// newColumnMapper creates a columnMapper for the given tables. The evalCtx may
+// be nil if the mapper is only used for validation.
+func newColumnMapper(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	src, dst catalog.TableDescriptor,
+	mappings []jobspb.LogicalReplicationDetails_ColumnMapping,
+) (*columnMapper, error) {
+	m := &columnMapper{}
+	if evalCtx != nil {
+		m.evalCtx = evalCtx.Copy()
+		m.evalCtx.IVarContainer = &m.row
+	}
+
+	exprByID := make(map[descpb.ColumnID]string, len(mappings))
+	for _, mapping := range mappings {
+		exprByID[mapping.ColumnID] = mapping.Expr
+	}
+
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	semaCtx.IVarContainer = &m.row
+	srcKey := src.GetPrimaryIndex().CollectKeyColumnIDs()
+	ordinals := make(map[string]int)
+	for _, col := range getColumnSchema(dst) {
+		name := col.column.GetName()
+
+		var expr tree.Expr
+		if len(mappings) == 0 {
+			if findPublicColumn(src, name) != nil {
+				expr = &tree.ColumnItem{ColumnName: tree.Name(name)}
+			}
+		} else if s, ok := exprByID[col.column.GetID()]; ok {
+			var err error
+			expr, err = parser.ParseExpr(s)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if expr == nil {
+			if col.isPrimaryKey {
+				return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+					"primary key column %q of destination table %s is not replicated", name, dst.GetName())
+			}
+			if !col.column.IsNullable() && !col.column.HasDefault() {
+				return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+					"column %q of destination table %s is not replicated and has no default value",
+					name, dst.GetName())
+			}
+			continue
+		}
+
+		// Replace the references to source columns with ordinal references into
+		// the decoded source row.
+		expr, err := tree.SimpleVisit(expr, func(e tree.Expr) (bool, tree.Expr, error) {
+			var item *tree.ColumnItem
+			switch t := e.(type) {
+			case *tree.UnresolvedName:
+				v, err := t.NormalizeVarName()
+				if err != nil {
+					return false, nil, err
+				}
+				c, ok := v.(*tree.ColumnItem)
+				if !ok {
+					return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+						"%s is not allowed in a column mapping", v)
+				}
+				item = c
+			case *tree.ColumnItem:
+				item = t
+			default:
+				return true, e, nil
+			}
+			if item.TableName != nil {
+				return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+					"column %s in the mapping of column %q must not be qualified", item, name)
+			}
+			srcName := string(item.ColumnName)
+			ord, ok := ordinals[srcName]
+			if !ok {
+				srcCol := findPublicColumn(src, srcName)
+				if srcCol == nil {
+					return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+						"source table %s has no column %q", src.GetName(), srcName)
+				}
+				ord = len(m.sourceColumns)
+				ordinals[srcName] = ord
+				m.sourceColumns = append(m.sourceColumns, srcName)
+				m.row.types = append(m.row.types, srcCol.GetType())
+			}
+			return false, tree.NewTypedOrdinalReference(ord, m.row.types[ord]), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var typed tree.TypedExpr
+		if iv, ok := expr.(*tree.IndexedVar); ok {
+			srcName := m.sourceColumns[iv.Idx]
+			if err := tabledesc.CheckLogicalReplicationTypesMatch(m.row.types[iv.Idx], col.column.GetType()); err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.DatatypeMismatch,
+					"cannot replicate source column %q into column %q of destination table %s",
+					srcName, name, dst.GetName())
+			}
+			if col.isPrimaryKey && !srcKey.Contains(findPublicColumn(src, srcName).GetID()) {
+				return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+					"primary key column %q of destination table %s must be replicated from a primary key column of the source table",
+					name, dst.GetName())
+			}
+			typed = iv
+		} else {
+			if col.isPrimaryKey {
+				return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+					"primary key column %q of destination table %s must be replicated from a primary key column of the source table",
+					name, dst.GetName())
+			}
+			semaCtx.Properties.Require("column mappings",
+				tree.RejectSpecial|tree.RejectSubqueries|tree.RejectVolatileFunctions|tree.RejectStableOperators)
+			typed, err = tree.TypeCheckAndRequire(ctx, expr, &semaCtx, col.column.GetType(),
+				"COLUMN "+tree.NameString(name))
+			if err != nil {
+				return nil, err
+			}
+		}
+		m.columns = append(m.columns, col)
+		m.exprs = append(m.exprs, typed)
+	}
+	return m, nil
+}
+
+// mapRow computes the replicated columns of the destination table from the
+// decoded source columns.
+func (m *columnMapper) mapRow(ctx context.Context, src tree.Datums) (tree.Datums, error) {
+	row := make(tree.Datums, len(m.exprs))
+	m.row.datums = src
+	for i, expr := range m.exprs {
+		if iv, ok := expr.(*tree.IndexedVar); ok {
+			row[i] = src[iv.Idx]
+			continue
+		}
+		if m.evalCtx == nil {
+			return nil, errors.AssertionFailedf("column mapper was created without an eval context")
+		}
+		d, err := eval.Expr(ctx, m.evalCtx, expr)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = d
+	}
+	return row, nil
+}
+
+// columnIDs returns the IDs of the replicated columns of the destination
+// table.
+func (m *columnMapper) columnIDs() []descpb.ColumnID {
+	ids := make([]descpb.ColumnID, len(m.columns))
+	for i, col := range m.columns {
+		ids[i] = col.column.GetID()
+	}
+	return ids
+}
+
+func findPublicColumn(table catalog.TableDescriptor, name string) catalog.Column {
+	for _, col := range table.PublicColumns() {
+		if col.GetName() == name {
+			return col
+		}
+	}
+	return nil
+}
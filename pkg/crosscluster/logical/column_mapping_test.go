@@ -0,0 +1,221 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/replicationtestutils"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnMappingBatchHandler(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	runner.Exec(t, `
+		CREATE TABLE orders_src (
+			id INT PRIMARY KEY,
+			first_name STRING,
+			last_name STRING,
+			price INT,
+			qty INT,
+			internal STRING
+		)
+	`)
+	runner.Exec(t, `
+		CREATE TABLE orders_dst (
+			id INT PRIMARY KEY,
+			full_name STRING,
+			total INT,
+			qty INT,
+			local_note STRING
+		)
+	`)
+	src := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "orders_src")
+	dst := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "orders_dst")
+
+	parseExpr := func(sql string) tree.Expr {
+		expr, err := parser.ParseExpr(sql)
+		require.NoError(t, err)
+		return expr
+	}
+	mappings, err := resolveColumnMappings(ctx, src, dst, &tableColumnMappings{
+		exprs: map[string]tree.Expr{
+			"full_name":  parseExpr(`first_name || ' ' || last_name`),
+			"total":      parseExpr(`price * qty`),
+			"local_note": tree.DefaultVal{},
+		},
+		excluded: map[string]struct{}{"internal": {}},
+	})
+	require.NoError(t, err)
+
+	handler := newCrudBatchHandlerForConfig(t, s, dst.GetID(), sqlProcessorTableConfig{
+		srcDesc:        src,
+		columnMappings: mappings,
+	})
+	defer handler.ReleaseLeases(ctx)
+	eb := newKvEventBuilder(t, src.TableDesc())
+
+	row := func(id int, first, last string, price, qty int, internal string) tree.Datums {
+		return tree.Datums{
+			tree.NewDInt(tree.DInt(id)), tree.NewDString(first), tree.NewDString(last),
+			tree.NewDInt(tree.DInt(price)), tree.NewDInt(tree.DInt(qty)), tree.NewDString(internal),
+		}
+	}
+	const query = `SELECT id, full_name, total, qty, local_note FROM orders_dst ORDER BY id`
+
+	// Inserts compute the mapped columns and leave the unreplicated column
+	// NULL.
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.insertEvent(s.Clock().Now(), row(1, "ada", "lovelace", 3, 2, "x")),
+		eb.insertEvent(s.Clock().Now(), row(2, "alan", "turing", 5, 1, "y")),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, query, [][]string{
+		{"1", "ada lovelace", "6", "2", "NULL"},
+		{"2", "alan turing", "5", "1", "NULL"},
+	})
+
+	// Updates keep the local value of the unreplicated column.
+	runner.Exec(t, `UPDATE orders_dst SET local_note = 'vip' WHERE id = 1`)
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.updateEvent(s.Clock().Now(), row(1, "ada", "byron", 3, 4, "z"), row(1, "ada", "lovelace", 3, 2, "x")),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, query, [][]string{
+		{"1", "ada byron", "12", "4", "vip"},
+		{"2", "alan turing", "5", "1", "NULL"},
+	})
+
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.deleteEvent(s.Clock().Now(), row(2, "alan", "turing", 5, 1, "y")),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, query, [][]string{
+		{"1", "ada byron", "12", "4", "vip"},
+	})
+
+	// Adding a nullable column to the destination table does not interrupt
+	// replication. The new column is not replicated until the handler is
+	// recreated.
+	runner.Exec(t, `ALTER TABLE orders_dst ADD COLUMN extra INT`)
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.insertEvent(s.Clock().Now(), row(3, "grace", "hopper", 1, 1, "w")),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, `SELECT id, full_name, extra FROM orders_dst ORDER BY id`, [][]string{
+		{"1", "ada byron", "NULL"},
+		{"3", "grace hopper", "NULL"},
+	})
+}
+
+func TestColumnMappingByName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	// Without column mappings, the columns the tables have in common are
+	// replicated. Columns that only exist in the source table are ignored and
+	// nullable columns that only exist in the destination table are left alone.
+	runner.Exec(t, `CREATE TABLE src (id INT PRIMARY KEY, a STRING, only_src INT)`)
+	runner.Exec(t, `CREATE TABLE dst (id INT PRIMARY KEY, only_dst INT, a STRING)`)
+	src := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "src")
+	dst := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "dst")
+
+	mapper, err := newColumnMapper(ctx, nil /* evalCtx */, src, dst, nil /* mappings */)
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "a"}, mapper.sourceColumns)
+
+	handler := newCrudBatchHandlerForConfig(t, s, dst.GetID(), sqlProcessorTableConfig{srcDesc: src})
+	defer handler.ReleaseLeases(ctx)
+	eb := newKvEventBuilder(t, src.TableDesc())
+
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.insertEvent(s.Clock().Now(), tree.Datums{tree.NewDInt(1), tree.NewDString("one"), tree.NewDInt(10)}),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, `SELECT id, only_dst, a FROM dst`, [][]string{{"1", "NULL", "one"}})
+
+	// A destination column that is not replicated must have a way to be
+	// populated.
+	runner.Exec(t, `CREATE TABLE dst_not_null (id INT PRIMARY KEY, a STRING, b INT NOT NULL)`)
+	dstNotNull := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "dst_not_null")
+	_, err = newColumnMapper(ctx, nil /* evalCtx */, src, dstNotNull, nil /* mappings */)
+	require.ErrorContains(t, err, `column "b" of destination table dst_not_null is not replicated and has no default value`)
+}
+
+func TestColumnMappingValidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	skip.UnderDeadlock(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	server, s, dbA, dbB := setupLogicalTestServer(t, ctx, testClusterBaseClusterArgs, 1)
+	defer server.Stopper().Stop(ctx)
+	dbBURL := replicationtestutils.GetExternalConnectionURI(t, s, s, serverutils.DBName("b"))
+
+	dbB.Exec(t, `CREATE TABLE people (id INT PRIMARY KEY, first_name STRING, last_name STRING, age INT, secret STRING)`)
+	dbA.Exec(t, `CREATE TABLE people (id INT PRIMARY KEY, full_name STRING, age INT, note STRING)`)
+
+	const stmt = `CREATE LOGICAL REPLICATION STREAM FROM TABLE people ON $1 INTO TABLE people WITH `
+	const fullName = `COLUMN full_name = first_name || ' ' || last_name FOR TABLE people`
+	for _, tc := range []struct {
+		options string
+		err     string
+	}{
+		{fullName, `source column "secret" of table people is not replicated to any destination column`},
+		{fullName + `, EXCLUDE COLUMN id FOR TABLE people`, `cannot exclude primary key column "id" of source table people`},
+		{fullName + `, EXCLUDE COLUMN missing FOR TABLE people`, `source table people has no column "missing"`},
+		{`COLUMN missing = age FOR TABLE people`, `destination table people has no column "missing"`},
+		{`COLUMN full_name = nickname FOR TABLE people`, `source table people has no column "nickname"`},
+		{`COLUMN age = first_name FOR TABLE people`, `cannot replicate source column "first_name" into column "age" of destination table people`},
+		{`COLUMN age = first_name || last_name FOR TABLE people`, `argument of COLUMN age must be type INT8, not type STRING`},
+		{`COLUMN id = age FOR TABLE people`, `primary key column "id" of destination table people must be replicated from a primary key column of the source table`},
+		{`COLUMN full_name = random()::STRING FOR TABLE people`, `volatile functions are not allowed in column mappings`},
+		{fullName + `, EXCLUDE COLUMN secret FOR TABLE people, EXCLUDE COLUMN age FOR TABLE people, COLUMN note = age::STRING FOR TABLE people`, `source column "age" of table people is excluded but is used by a column mapping`},
+		{`EXCLUDE COLUMN secret FOR TABLE other`, `COLUMN specified for table other which is not replicated`},
+	} {
+		t.Run(tc.options, func(t *testing.T) {
+			dbA.ExpectErr(t, tc.err, stmt+tc.options, dbBURL.String())
+		})
+	}
+
+	var jobID jobspb.JobID
+	dbA.QueryRow(t, stmt+fullName+`, EXCLUDE COLUMN secret FOR TABLE people`, dbBURL.String()).Scan(&jobID)
+	payload := jobutils.GetJobPayload(t, dbA, jobID)
+	pairs := payload.GetLogicalReplicationDetails().ReplicationPairs
+	require.Len(t, pairs, 1)
+	require.Equal(t, []jobspb.LogicalReplicationDetails_ColumnMapping{
+		{ColumnID: 1, Expr: `id`},
+		{ColumnID: 2, Expr: `(first_name || ' ') || last_name`},
+		{ColumnID: 3, Expr: `age`},
+	}, pairs[0].ColumnMappings)
+}
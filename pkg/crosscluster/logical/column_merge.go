@@ -0,0 +1,712 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/arith"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+type mergeStrategy = jobspb.LogicalReplicationDetails_ColumnMergeRule_Strategy
+
+// columnMergeRulesByTable maps the name of a source table, as written in the
+// CREATE LOGICAL REPLICATION STREAM statement, to the merge strategy of each
+// of its columns that has a MERGE COLUMN option.
+type columnMergeRulesByTable map[string]map[string]mergeStrategy
+
+const (
+	// mergeAppliedBaseTableName is defined as:
+	// "<dbName>.<dlqSchemaName>.merge_applied_<tableID>"
+	mergeAppliedBaseTableName       = "%s.%s.%s"
+	createMergeAppliedTableBaseStmt = `CREATE TABLE IF NOT EXISTS %s (
+			ingestion_job_id  INT8 NOT NULL,
+			row_key           BYTES NOT NULL,
+			applied_timestamp DECIMAL NOT NULL,
+			PRIMARY KEY (ingestion_job_id, row_key)
+		)`
+	selectMergeAppliedBaseStmt = `SELECT row_key, applied_timestamp FROM %s
+		WHERE ingestion_job_id = $1 AND row_key = ANY($2)`
+	upsertMergeAppliedBaseStmt = `UPSERT INTO %s (ingestion_job_id, row_key, applied_timestamp)
+		VALUES ($1, $2, $3)`
+	purgeMergeAppliedBaseStmt = `DELETE FROM %s
+		WHERE ingestion_job_id = $1 AND applied_timestamp <= $2 LIMIT $3`
+
+	// mergeAppliedPurgeBatchSize is the number of rows deleted from a merge
+	// applied table per statement when it is purged.
+	mergeAppliedPurgeBatchSize = 1000
+	// mergeAppliedPurgeInterval is the minimum interval between two purges of
+	// the merge applied tables by a running job.
+	mergeAppliedPurgeInterval = 10 * time.Minute
+)
+
+// toMergeAppliedTableName returns the name of the table which records the
+// origin timestamps of the events merged into the rows of the table.
+func (f dstTableMetadata) toMergeAppliedTableName() string {
+	return fmt.Sprintf(mergeAppliedBaseTableName,
+		f.getDatabaseName(),
+		dlqSchemaName,
+		lexbase.EscapeSQLIdent(fmt.Sprintf("merge_applied_%d", f.tableID)))
+}
+
+// createMergeAppliedTables creates the merge applied table of each replicated
+// table with column merge rules.
+func createMergeAppliedTables(
+	ctx context.Context,
+	ie isql.Executor,
+	pairs []jobspb.LogicalReplicationDetails_ReplicationPair,
+	destTableBySrcID map[descpb.ID]dstTableMetadata,
+) error {
+	for _, pair := range pairs {
+		if len(pair.ColumnMergeRules) == 0 {
+			continue
+		}
+		dstTableMeta, ok := destTableBySrcID[descpb.ID(pair.SrcDescriptorID)]
+		if !ok {
+			return errors.AssertionFailedf("no destination table for source table %d", pair.SrcDescriptorID)
+		}
+		if err := createMergeAppliedTable(ctx, ie, dstTableMeta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createMergeAppliedTable(ctx context.Context, ie isql.Executor, dst dstTableMetadata) error {
+	createSchemaStmt := fmt.Sprintf(createSchemaBaseStmt, dst.getDatabaseName(), dlqSchemaName)
+	if _, err := ie.Exec(ctx, "create-merge-applied-schema", nil, createSchemaStmt); err != nil {
+		return errors.Wrapf(err, "failed to create crdb_replication schema in database %s", dst.getDatabaseName())
+	}
+	createTableStmt := fmt.Sprintf(createMergeAppliedTableBaseStmt, dst.toMergeAppliedTableName())
+	if _, err := ie.Exec(ctx, "create-merge-applied-table", nil, createTableStmt); err != nil {
+		return errors.Wrapf(err, "failed to create merge applied table for table %d", dst.tableID)
+	}
+	return nil
+}
+
+// mergeAppliedTableNames returns the names of the merge applied tables of the
+// replicated tables with column merge rules.
+func mergeAppliedTableNames(
+	pairs []jobspb.LogicalReplicationDetails_ReplicationPair,
+	destTableBySrcID map[descpb.ID]dstTableMetadata,
+) []string {
+	var names []string
+	for _, pair := range pairs {
+		if len(pair.ColumnMergeRules) == 0 {
+			continue
+		}
+		if dst, ok := destTableBySrcID[descpb.ID(pair.SrcDescriptorID)]; ok {
+			names = append(names, dst.toMergeAppliedTableName())
+		}
+	}
+	return names
+}
+
+// purgeMergeApplied deletes the rows of the merge applied tables written by
+// the job whose applied timestamp is at or below upTo.
+//
+// Once the replicated time of the job reaches the applied timestamp recorded
+// for a row, no event it guards against can be delivered again, since the job
+// never resumes from below its replicated time. This is also how the records
+// of deleted rows are cleaned up: they are kept until the replicated time
+// passes the delete, so that a replay of an older update is not merged into a
+// row inserted again locally. When the job is dropped, all of its records are
+// purged.
+func purgeMergeApplied(
+	ctx context.Context, ie isql.Executor, tables []string, jobID jobspb.JobID, upTo hlc.Timestamp,
+) error {
+	for _, table := range tables {
+		stmt := fmt.Sprintf(purgeMergeAppliedBaseStmt, table)
+		for {
+			deleted, err := ie.ExecEx(ctx, "purge-merge-applied", nil, /* txn */
+				sessiondata.NodeUserSessionDataOverride, stmt,
+				int64(jobID), eval.TimestampToDecimalDatum(upTo), tree.NewDInt(mergeAppliedPurgeBatchSize),
+			)
+			if pgerror.GetPGCode(err) == pgcode.UndefinedTable {
+				// The table was never created, or was dropped with its database.
+				break
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to purge merge applied table %s", table)
+			}
+			if deleted < mergeAppliedPurgeBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// errUnresolvableMergeConflict is returned when a column merge rule cannot
+// compute the merged value of a column. Events which fail with this error are
+// sent to the DLQ without being retried.
+var errUnresolvableMergeConflict = errors.New("unresolvable merge conflict")
+
+func parseMergeStrategy(s string) (mergeStrategy, error) {
+	switch strings.ToLower(s) {
+	case "lww":
+		return jobspb.LogicalReplicationDetails_ColumnMergeRule_LWW, nil
+	case "additive":
+		return jobspb.LogicalReplicationDetails_ColumnMergeRule_Additive, nil
+	case "max":
+		return jobspb.LogicalReplicationDetails_ColumnMergeRule_Max, nil
+	case "min":
+		return jobspb.LogicalReplicationDetails_ColumnMergeRule_Min, nil
+	case "source-region-owned":
+		return jobspb.LogicalReplicationDetails_ColumnMergeRule_SourceRegionOwned, nil
+	case "destination-region-owned":
+		return jobspb.LogicalReplicationDetails_ColumnMergeRule_DestinationRegionOwned, nil
+	case "union":
+		return jobspb.LogicalReplicationDetails_ColumnMergeRule_Union, nil
+	default:
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue, "unknown merge strategy %q", s)
+	}
+}
+
+// resolveColumnMergeRules validates the merge rules for the columns of the
+// destination table and returns them sorted by column ID.
+func resolveColumnMergeRules(
+	table catalog.TableDescriptor, rules map[string]mergeStrategy,
+) ([]jobspb.LogicalReplicationDetails_ColumnMergeRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	isPrimaryKey := catalog.MakeTableColSet(table.GetPrimaryIndex().IndexDesc().KeyColumnIDs...)
+
+	resolved := make([]jobspb.LogicalReplicationDetails_ColumnMergeRule, 0, len(rules))
+	for name, strategy := range rules {
+		col, err := catalog.MustFindColumnByName(table, name)
+		if err != nil {
+			return nil, err
+		}
+		if isPrimaryKey.Contains(col.GetID()) {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot specify a merge rule for primary key column %q", name)
+		}
+		if col.IsSystemColumn() {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot specify a merge rule for system column %q", name)
+		}
+		if col.IsComputed() {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot specify a merge rule for computed column %q", name)
+		}
+		typ := col.GetType()
+		switch strategy {
+		case jobspb.LogicalReplicationDetails_ColumnMergeRule_Additive:
+			switch typ.Family() {
+			case types.IntFamily, types.FloatFamily, types.DecimalFamily:
+			default:
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"merge strategy 'additive' requires a numeric column, but column %q has type %s", name, typ.SQLString())
+			}
+		case jobspb.LogicalReplicationDetails_ColumnMergeRule_Max, jobspb.LogicalReplicationDetails_ColumnMergeRule_Min:
+			if !colinfo.ColumnTypeIsIndexable(typ) {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"merge strategies 'max' and 'min' require an orderable column, but column %q has type %s", name, typ.SQLString())
+			}
+		case jobspb.LogicalReplicationDetails_ColumnMergeRule_Union:
+			if typ.Family() != types.ArrayFamily {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"merge strategy 'union' requires an array column, but column %q has type %s", name, typ.SQLString())
+			}
+		}
+		resolved = append(resolved, jobspb.LogicalReplicationDetails_ColumnMergeRule{
+			ColumnID: col.GetID(),
+			Strategy: strategy,
+		})
+	}
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].ColumnID < resolved[j].ColumnID
+	})
+	return resolved, nil
+}
+
+// hasColumnMergeRules returns true if any of the replicated tables has column
+// merge rules. Merge rules are only evaluated by the crud writer, so jobs with
+// merge rules always use it.
+func hasColumnMergeRules(pairs []jobspb.LogicalReplicationDetails_ReplicationPair) bool {
+	for _, pair := range pairs {
+		if len(pair.ColumnMergeRules) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// columnMerger merges replicated rows with the local rows of a table with
+// column merge rules. The datums it operates on are in the order of the
+// table's replicated columns.
+type columnMerger struct {
+	cmpCtx tree.CompareContext
+	// strategies is the merge strategy of each column.
+	strategies []mergeStrategy
+	// keyColumns are the indexes of the primary key columns.
+	keyColumns []int
+
+	// The merge applied table records, for each row, the origin timestamp of
+	// the last event merged into it. Events are delivered again after the job
+	// resumes from its checkpoint, and merges such as 'additive' are not
+	// idempotent, so events which are not newer than it are skipped.
+	jobID           jobspb.JobID
+	sessionOverride sessiondata.InternalExecutorOverride
+	selectApplied   string
+	upsertApplied   string
+}
+
+func newColumnMerger(
+	table catalog.TableDescriptor,
+	cols []columnSchema,
+	rules []jobspb.LogicalReplicationDetails_ColumnMergeRule,
+	cmpCtx tree.CompareContext,
+	appliedTable string,
+	jobID jobspb.JobID,
+	sessionOverride sessiondata.InternalExecutorOverride,
+) (*columnMerger, error) {
+	m := &columnMerger{
+		cmpCtx:          cmpCtx,
+		strategies:      make([]mergeStrategy, len(cols)),
+		jobID:           jobID,
+		sessionOverride: sessionOverride,
+		selectApplied:   fmt.Sprintf(selectMergeAppliedBaseStmt, appliedTable),
+		upsertApplied:   fmt.Sprintf(upsertMergeAppliedBaseStmt, appliedTable),
+	}
+	for i, col := range cols {
+		if col.isPrimaryKey {
+			m.keyColumns = append(m.keyColumns, i)
+		}
+	}
+	for _, rule := range rules {
+		found := false
+		for i, col := range cols {
+			if col.column.GetID() == rule.ColumnID && !col.isPrimaryKey {
+				m.strategies[i] = rule.Strategy
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Newf("column %d of table %q has a merge rule but is not a replicated column",
+				rule.ColumnID, table.GetName())
+		}
+	}
+	return m, nil
+}
+
+// sameKey returns true if the two rows have the same primary key.
+func (m *columnMerger) sameKey(ctx context.Context, a, b tree.Datums) (bool, error) {
+	for _, i := range m.keyColumns {
+		c, err := a[i].Compare(ctx, m.cmpCtx, b[i])
+		if err != nil || c != 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// rowKey returns the encoding of the primary key of a row, which identifies
+// it in the merge applied table.
+func (m *columnMerger) rowKey(row tree.Datums) ([]byte, error) {
+	var key []byte
+	for _, i := range m.keyColumns {
+		var err error
+		if key, err = keyside.Encode(key, row[i], encoding.Ascending); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// readApplied returns the origin timestamps of the last events merged into
+// the rows with the given keys, if any.
+func (m *columnMerger) readApplied(
+	ctx context.Context, txn isql.Txn, keys [][]byte,
+) (map[string]hlc.Timestamp, error) {
+	keyArray := tree.NewDArray(types.Bytes)
+	for _, key := range keys {
+		if err := keyArray.Append(tree.NewDBytes(tree.DBytes(key))); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := txn.QueryBufferedEx(ctx, "replication-read-merge-applied", txn.KV(),
+		m.sessionOverride, m.selectApplied, int64(m.jobID), keyArray,
+	)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]hlc.Timestamp, len(rows))
+	for _, row := range rows {
+		key, ok := tree.AsDBytes(row[0])
+		if !ok {
+			return nil, errors.AssertionFailedf("expected column 0 to be the row key")
+		}
+		decimal, ok := tree.AsDDecimal(row[1])
+		if !ok {
+			return nil, errors.AssertionFailedf("expected column 1 to be the applied timestamp")
+		}
+		ts, err := hlc.DecimalToHLC(&decimal.Decimal)
+		if err != nil {
+			return nil, err
+		}
+		applied[string(key)] = ts
+	}
+	return applied, nil
+}
+
+// recordApplied records that the event with the given origin timestamp was
+// merged into the row with the given key.
+func (m *columnMerger) recordApplied(
+	ctx context.Context, txn isql.Txn, key []byte, ts hlc.Timestamp,
+) error {
+	_, err := txn.ExecEx(ctx, "replication-record-merge-applied", txn.KV(),
+		m.sessionOverride, m.upsertApplied,
+		int64(m.jobID), tree.NewDBytes(tree.DBytes(key)), eval.TimestampToDecimalDatum(ts),
+	)
+	return err
+}
+
+// mergeRow returns the row that results from merging a replicated update into
+// the local row. prevRow is the value of the row on the source before the
+// update, if it is known, and remoteWins is true if the replicated row wins
+// last-write-wins against the local row.
+func (m *columnMerger) mergeRow(
+	ctx context.Context, local, prevRow, row tree.Datums, remoteWins bool,
+) (tree.Datums, error) {
+	merged := make(tree.Datums, len(row))
+	for i := range row {
+		var err error
+		merged[i], err = m.mergeDatum(ctx, m.strategies[i], local[i], prevRow, row, i, remoteWins)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+func (m *columnMerger) mergeDatum(
+	ctx context.Context,
+	strategy mergeStrategy,
+	local tree.Datum,
+	prevRow, row tree.Datums,
+	i int,
+	remoteWins bool,
+) (tree.Datum, error) {
+	remote := row[i]
+	switch strategy {
+	case jobspb.LogicalReplicationDetails_ColumnMergeRule_LWW:
+		if remoteWins {
+			return remote, nil
+		}
+		return local, nil
+
+	case jobspb.LogicalReplicationDetails_ColumnMergeRule_SourceRegionOwned:
+		return remote, nil
+
+	case jobspb.LogicalReplicationDetails_ColumnMergeRule_DestinationRegionOwned:
+		return local, nil
+
+	case jobspb.LogicalReplicationDetails_ColumnMergeRule_Max, jobspb.LogicalReplicationDetails_ColumnMergeRule_Min:
+		// NULL is treated as the absence of a value, so the other value is
+		// taken.
+		if local == tree.DNull {
+			return remote, nil
+		}
+		if remote == tree.DNull {
+			return local, nil
+		}
+		c, err := local.Compare(ctx, m.cmpCtx, remote)
+		if err != nil {
+			return nil, err
+		}
+		if (strategy == jobspb.LogicalReplicationDetails_ColumnMergeRule_Max) == (c < 0) {
+			return remote, nil
+		}
+		return local, nil
+
+	case jobspb.LogicalReplicationDetails_ColumnMergeRule_Additive:
+		if len(prevRow) == 0 {
+			return nil, unresolvableMergeConflictf(
+				"the previous value of the row is required by the 'additive' merge strategy")
+		}
+		prev := prevRow[i]
+		c, err := prev.Compare(ctx, m.cmpCtx, remote)
+		if err != nil {
+			return nil, err
+		}
+		if c == 0 {
+			// The column was not changed on the source.
+			return local, nil
+		}
+		return addDelta(local, prev, remote)
+
+	case jobspb.LogicalReplicationDetails_ColumnMergeRule_Union:
+		return m.unionArrays(ctx, local, remote)
+
+	default:
+		return nil, errors.AssertionFailedf("unknown merge strategy %s", strategy)
+	}
+}
+
+// addDelta returns local + (remote - prev).
+func addDelta(local, prev, remote tree.Datum) (tree.Datum, error) {
+	if local == tree.DNull || prev == tree.DNull || remote == tree.DNull {
+		return nil, unresolvableMergeConflictf("cannot apply an 'additive' merge to a NULL value")
+	}
+	local, prev, remote = tree.UnwrapDOidWrapper(local), tree.UnwrapDOidWrapper(prev), tree.UnwrapDOidWrapper(remote)
+	switch l := local.(type) {
+	case *tree.DInt:
+		p, r := prev.(*tree.DInt), remote.(*tree.DInt)
+		delta, ok := arith.SubWithOverflow(int64(*r), int64(*p))
+		if ok {
+			var sum int64
+			if sum, ok = arith.AddWithOverflow(int64(*l), delta); ok {
+				return tree.NewDInt(tree.DInt(sum)), nil
+			}
+		}
+		return nil, unresolvableMergeConflictf("integer out of range")
+	case *tree.DFloat:
+		p, r := prev.(*tree.DFloat), remote.(*tree.DFloat)
+		return tree.NewDFloat(*l + (*r - *p)), nil
+	case *tree.DDecimal:
+		p, r := prev.(*tree.DDecimal), remote.(*tree.DDecimal)
+		res := &tree.DDecimal{}
+		if _, err := tree.ExactCtx.Sub(&res.Decimal, &r.Decimal, &p.Decimal); err != nil {
+			return nil, err
+		}
+		if _, err := tree.ExactCtx.Add(&res.Decimal, &l.Decimal, &res.Decimal); err != nil {
+			return nil, err
+		}
+		return res, nil
+	default:
+		return nil, errors.AssertionFailedf("unsupported type %T for 'additive' merge", local)
+	}
+}
+
+// unionArrays returns the distinct elements of the local array followed by
+// the distinct elements of the remote array which are not in the local array.
+// A NULL array is treated as an empty one.
+func (m *columnMerger) unionArrays(ctx context.Context, local, remote tree.Datum) (tree.Datum, error) {
+	if local == tree.DNull && remote == tree.DNull {
+		return tree.DNull, nil
+	}
+	var res *tree.DArray
+	for _, d := range []tree.Datum{local, remote} {
+		if d == tree.DNull {
+			continue
+		}
+		arr, ok := tree.AsDArray(d)
+		if !ok {
+			return nil, errors.AssertionFailedf("unsupported type %T for 'union' merge", d)
+		}
+		if res == nil {
+			res = tree.NewDArray(arr.ParamTyp)
+		}
+		for _, elem := range arr.Array {
+			found := false
+			for _, existing := range res.Array {
+				c, err := existing.Compare(ctx, m.cmpCtx, elem)
+				if err != nil {
+					return nil, err
+				}
+				if c == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				if err := res.Append(elem); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+func unresolvableMergeConflictf(format string, args ...interface{}) error {
+	return pgerror.Wrapf(errUnresolvableMergeConflict, pgcode.DataException, format, args...)
+}
+
+// rowsEqual returns true if all of the datums in the two rows are equal.
+func (m *columnMerger) rowsEqual(ctx context.Context, a, b tree.Datums) (bool, error) {
+	for i := range a {
+		c, err := a[i].Compare(ctx, m.cmpCtx, b[i])
+		if err != nil || c != 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// mergeBatch applies a batch of events to a table with column merge rules.
+// Unlike attemptBatch, it always reads the local rows before writing them,
+// since the merged row depends on the local row even if the replicated update
+// does not conflict with a local write.
+func (t *tableHandler) mergeBatch(
+	ctx context.Context, batch []decodedEvent,
+) (tableBatchStats, error) {
+	var stats tableBatchStats
+	err := t.db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		stats = tableBatchStats{refreshedRows: int64(len(batch))}
+
+		rows := make([]tree.Datums, 0, len(batch))
+		keys := make([][]byte, 0, len(batch))
+		for _, event := range batch {
+			rows = append(rows, event.row)
+			key, err := t.merger.rowKey(event.row)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+		localRows, err := t.sqlReader.ReadRows(ctx, txn, rows)
+		if err != nil {
+			return err
+		}
+		applied, err := t.merger.readApplied(ctx, txn, keys)
+		if err != nil {
+			return err
+		}
+
+		var last priorRow
+		var lastFound bool
+		for i, event := range batch {
+			local, found := localRows[i]
+			// Events are sorted by key, so if the batch contains several events
+			// for the same row, they are adjacent and all but the first must be
+			// merged with the row written by the previous event.
+			if i > 0 {
+				same, err := t.merger.sameKey(ctx, batch[i-1].row, event.row)
+				if err != nil {
+					return err
+				}
+				if same {
+					local, found = last, lastFound
+				}
+			}
+			if ts, ok := applied[string(keys[i])]; ok && event.originTimestamp.LessEq(ts) {
+				// The event was already merged into the row and is being
+				// delivered again, e.g. after the job resumed from its
+				// checkpoint.
+				stats.refreshLwwLosers++
+				last, lastFound = local, found
+				continue
+			}
+			last, lastFound, err = t.mergeEvent(ctx, txn, event, local, found, &stats)
+			if err != nil {
+				return err
+			}
+			if err := t.merger.recordApplied(ctx, txn, keys[i], event.originTimestamp); err != nil {
+				return err
+			}
+			applied[string(keys[i])] = event.originTimestamp
+		}
+		return nil
+	})
+	if err != nil {
+		return tableBatchStats{}, err
+	}
+	return stats, nil
+}
+
+// mergeEvent applies a single event given the local value of its row, if the
+// row exists, and returns the value of the row after the event is applied.
+func (t *tableHandler) mergeEvent(
+	ctx context.Context,
+	txn isql.Txn,
+	event decodedEvent,
+	local priorRow,
+	found bool,
+	stats *tableBatchStats,
+) (priorRow, bool, error) {
+	if !found {
+		if event.isDelete {
+			stats.tombstoneUpdates++
+			tombstoneUpdateStats, err := t.tombstoneUpdater.updateTombstone(ctx, txn, event.originTimestamp, event.row)
+			if err != nil {
+				return priorRow{}, false, err
+			}
+			stats.kvLwwLosers += tombstoneUpdateStats.kvWriteTooOld
+			return priorRow{}, false, nil
+		}
+		stats.inserts++
+		err := withSavepoint(ctx, txn.KV(), func() error {
+			return t.sqlWriter.InsertRow(ctx, txn, event.originTimestamp, event.row)
+		})
+		if isLwwLoser(err) {
+			// Insert may observe a LWW failure if it attempts to write over a tombstone.
+			stats.kvLwwLosers++
+			return priorRow{}, false, nil
+		}
+		if err != nil {
+			return priorRow{}, false, err
+		}
+		return priorRow{row: event.row, logicalTimestamp: event.originTimestamp}, true, nil
+	}
+
+	remoteWins := local.logicalTimestamp.Less(event.originTimestamp)
+	if event.isDelete {
+		// Deletes are not merged: the row is deleted if the delete wins
+		// last-write-wins.
+		if !remoteWins {
+			stats.refreshLwwLosers++
+			return local, true, nil
+		}
+		stats.deletes++
+		if err := t.sqlWriter.DeleteRow(ctx, txn, event.originTimestamp, local.row); err != nil {
+			return priorRow{}, false, err
+		}
+		return priorRow{}, false, nil
+	}
+
+	merged, err := t.merger.mergeRow(ctx, local.row, event.prevRow, event.row, remoteWins)
+	if err != nil {
+		return priorRow{}, false, err
+	}
+	unchanged, err := t.merger.rowsEqual(ctx, local.row, merged)
+	if err != nil {
+		return priorRow{}, false, err
+	}
+	if unchanged {
+		if !remoteWins {
+			stats.refreshLwwLosers++
+		}
+		return local, true, nil
+	}
+
+	originTimestamp := event.originTimestamp
+	if !remoteWins {
+		// The KV layer rejects writes whose origin timestamp is not newer than
+		// the timestamp of the local row, so a merge into a row that wins
+		// last-write-wins is written just after it.
+		originTimestamp = local.logicalTimestamp.Next()
+	}
+	stats.updates++
+	if err := t.sqlWriter.UpdateRow(ctx, txn, originTimestamp, local.row, merged); err != nil {
+		return priorRow{}, false, err
+	}
+	return priorRow{row: merged, logicalTimestamp: originTimestamp}, true, nil
+}
@@ -0,0 +1,309 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/replicationtestutils"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnMergerMergeRow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	evalCtx := eval.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(ctx)
+
+	m := &columnMerger{
+		cmpCtx: evalCtx,
+		strategies: []mergeStrategy{
+			jobspb.LogicalReplicationDetails_ColumnMergeRule_LWW,
+			jobspb.LogicalReplicationDetails_ColumnMergeRule_Additive,
+			jobspb.LogicalReplicationDetails_ColumnMergeRule_Max,
+			jobspb.LogicalReplicationDetails_ColumnMergeRule_Min,
+			jobspb.LogicalReplicationDetails_ColumnMergeRule_SourceRegionOwned,
+			jobspb.LogicalReplicationDetails_ColumnMergeRule_DestinationRegionOwned,
+		},
+	}
+	i, s := tree.NewDInt, tree.NewDString
+	row := func(lww string, additive, max, min int, source, destination string) tree.Datums {
+		return tree.Datums{
+			s(lww), i(tree.DInt(additive)), i(tree.DInt(max)), i(tree.DInt(min)), s(source), s(destination),
+		}
+	}
+
+	local := row("local", 10, 5, 5, "local", "local")
+	prev := row("prev", 3, 1, 9, "prev", "prev")
+	remote := row("remote", 7, 4, 2, "remote", "remote")
+
+	merged, err := m.mergeRow(ctx, local, prev, remote, true /* remoteWins */)
+	require.NoError(t, err)
+	require.Equal(t, row("remote", 14, 5, 2, "remote", "local"), merged)
+
+	merged, err = m.mergeRow(ctx, local, prev, remote, false /* remoteWins */)
+	require.NoError(t, err)
+	require.Equal(t, row("local", 14, 5, 2, "remote", "local"), merged)
+
+	// If the additive column is unchanged on the source, the previous value is
+	// not needed.
+	unchanged := row("remote", 3, 4, 2, "remote", "remote")
+	merged, err = m.mergeRow(ctx, local, prev, unchanged, true /* remoteWins */)
+	require.NoError(t, err)
+	require.Equal(t, row("remote", 10, 5, 2, "remote", "local"), merged)
+
+	// NULLs are ignored by max and min.
+	withNulls := row("local", 10, 5, 5, "local", "local")
+	withNulls[2], withNulls[3] = tree.DNull, tree.DNull
+	merged, err = m.mergeRow(ctx, withNulls, prev, remote, true /* remoteWins */)
+	require.NoError(t, err)
+	require.Equal(t, row("remote", 14, 4, 2, "remote", "local"), merged)
+
+	// Conflicts which the additive strategy cannot resolve.
+	_, err = m.mergeRow(ctx, local, nil /* prevRow */, remote, true /* remoteWins */)
+	require.True(t, errors.Is(err, errUnresolvableMergeConflict), "%+v", err)
+	nullLocal := row("local", 10, 5, 5, "local", "local")
+	nullLocal[1] = tree.DNull
+	_, err = m.mergeRow(ctx, nullLocal, prev, remote, true /* remoteWins */)
+	require.True(t, errors.Is(err, errUnresolvableMergeConflict), "%+v", err)
+	overflow := row("local", 1<<62, 5, 5, "local", "local")
+	_, err = m.mergeRow(ctx, overflow, row("prev", -(1<<62), 1, 9, "prev", "prev"), remote, true /* remoteWins */)
+	require.True(t, errors.Is(err, errUnresolvableMergeConflict), "%+v", err)
+}
+
+func TestColumnMergerUnion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	evalCtx := eval.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(ctx)
+
+	m := &columnMerger{
+		cmpCtx:     evalCtx,
+		strategies: []mergeStrategy{jobspb.LogicalReplicationDetails_ColumnMergeRule_Union},
+	}
+	array := func(elems ...string) tree.Datum {
+		arr := tree.NewDArray(types.String)
+		for _, e := range elems {
+			require.NoError(t, arr.Append(tree.NewDString(e)))
+		}
+		return arr
+	}
+	for _, tc := range []struct {
+		local, remote, expected tree.Datum
+	}{
+		{array("a", "b"), array("b", "c"), array("a", "b", "c")},
+		{array("a", "a"), array(), array("a")},
+		{tree.DNull, array("c", "a"), array("c", "a")},
+		{array("a"), tree.DNull, array("a")},
+		{tree.DNull, tree.DNull, tree.DNull},
+	} {
+		for _, remoteWins := range []bool{true, false} {
+			merged, err := m.mergeRow(ctx, tree.Datums{tc.local}, nil /* prevRow */, tree.Datums{tc.remote}, remoteWins)
+			require.NoError(t, err)
+			require.Equal(t, tree.Datums{tc.expected}, merged)
+		}
+	}
+}
+
+func TestAddDelta(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sum, err := addDelta(tree.NewDFloat(1.5), tree.NewDFloat(2), tree.NewDFloat(4.25))
+	require.NoError(t, err)
+	require.Equal(t, tree.NewDFloat(3.75), sum)
+
+	dec := func(s string) *tree.DDecimal {
+		d, _, err := apd.NewFromString(s)
+		require.NoError(t, err)
+		return &tree.DDecimal{Decimal: *d}
+	}
+	sum, err = addDelta(dec("10.10"), dec("1.05"), dec("2.5"))
+	require.NoError(t, err)
+	require.Equal(t, "11.55", sum.String())
+}
+
+func TestBatchHandlerColumnMergeRules(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	runner.Exec(t, `
+		CREATE TABLE inventory (
+			id INT PRIMARY KEY,
+			qty INT,
+			hi INT,
+			lo INT,
+			src STRING,
+			dst STRING,
+			note STRING
+		)
+	`)
+	desc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "inventory")
+	rules, err := resolveColumnMergeRules(desc, map[string]mergeStrategy{
+		"qty": jobspb.LogicalReplicationDetails_ColumnMergeRule_Additive,
+		"hi":  jobspb.LogicalReplicationDetails_ColumnMergeRule_Max,
+		"lo":  jobspb.LogicalReplicationDetails_ColumnMergeRule_Min,
+		"src": jobspb.LogicalReplicationDetails_ColumnMergeRule_SourceRegionOwned,
+		"dst": jobspb.LogicalReplicationDetails_ColumnMergeRule_DestinationRegionOwned,
+	})
+	require.NoError(t, err)
+	handler, desc := newCrudBatchHandler(t, s, "inventory", rules...)
+	defer handler.ReleaseLeases(ctx)
+	eb := newKvEventBuilder(t, desc.TableDesc())
+
+	row := func(id, qty, hi, lo int, src, dst, note string) []tree.Datum {
+		return []tree.Datum{
+			tree.NewDInt(tree.DInt(id)), tree.NewDInt(tree.DInt(qty)), tree.NewDInt(tree.DInt(hi)),
+			tree.NewDInt(tree.DInt(lo)), tree.NewDString(src), tree.NewDString(dst), tree.NewDString(note),
+		}
+	}
+	const query = `SELECT id, qty, hi, lo, src, dst, note FROM inventory ORDER BY id`
+
+	runner.Exec(t, `INSERT INTO inventory VALUES (1, 10, 5, 5, 'local', 'local', 'local'), (2, 0, 0, 0, '', '', '')`)
+
+	// The replicated update wins last-write-wins, but the columns with merge
+	// rules are merged with the local row. The updates to row 2 are applied in
+	// the same batch.
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.updateEvent(s.Clock().Now(), row(1, 7, 4, 2, "b", "b", "b"), row(1, 3, 1, 9, "a", "a", "a")),
+		eb.updateEvent(s.Clock().Now(), row(2, 1, 0, 0, "", "", ""), row(2, 0, 0, 0, "", "", "")),
+		eb.updateEvent(s.Clock().Now(), row(2, 3, 0, 0, "", "", ""), row(2, 1, 0, 0, "", "", "")),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, query, [][]string{
+		{"1", "14", "5", "2", "b", "local", "b"},
+		{"2", "3", "0", "0", "", "", ""},
+	})
+
+	// The local row wins last-write-wins, but the merged columns still observe
+	// the replicated update.
+	before := s.Clock().Now()
+	runner.Exec(t, `UPDATE inventory SET note = 'newer' WHERE id = 1`)
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.updateEvent(before, row(1, 8, 9, 2, "c", "c", "c"), row(1, 7, 4, 2, "b", "b", "b")),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, query, [][]string{
+		{"1", "15", "9", "2", "c", "local", "newer"},
+		{"2", "3", "0", "0", "", "", ""},
+	})
+
+	// Events are delivered again after the job resumes from its checkpoint.
+	// Whether they won or lost last-write-wins, they must not be merged twice.
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.updateEvent(before, row(1, 8, 9, 2, "c", "c", "c"), row(1, 7, 4, 2, "b", "b", "b")),
+	})
+	require.NoError(t, err)
+	replayedTS := s.Clock().Now()
+	replayed := eb.updateEvent(replayedTS, row(1, 10, 9, 2, "c", "c", "c"), row(1, 8, 9, 2, "c", "c", "c"))
+	for i := 0; i < 2; i++ {
+		_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{replayed})
+		require.NoError(t, err)
+	}
+	runner.CheckQueryResults(t, query, [][]string{
+		{"1", "17", "9", "2", "c", "local", "c"},
+		{"2", "3", "0", "0", "", "", ""},
+	})
+
+	// An additive column cannot be merged without the previous value of the
+	// row, so the event must be sent to the DLQ.
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.insertEvent(s.Clock().Now(), row(1, 1, 1, 1, "d", "d", "d")),
+	})
+	require.True(t, errors.Is(err, errUnresolvableMergeConflict), "%+v", err)
+	require.NoError(t, canDlqError(err))
+
+	// Deletes which win last-write-wins are applied.
+	_, err = handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+		eb.deleteEvent(s.Clock().Now(), row(2, 3, 0, 0, "", "", "")),
+	})
+	require.NoError(t, err)
+	runner.CheckQueryResults(t, query, [][]string{
+		{"1", "17", "9", "2", "c", "local", "c"},
+	})
+
+	// The merge applied table keeps a record for the deleted row until it is
+	// purged past the delete. Dropping the job purges every record of the job.
+	appliedTable := dstTableMetadata{database: "defaultdb", tableID: desc.GetID()}.toMergeAppliedTableName()
+	const jobID = jobspb.JobID(0)
+	countQuery := `SELECT count(*) FROM ` + appliedTable
+	runner.CheckQueryResults(t, countQuery, [][]string{{"2"}})
+	ie := s.InternalDB().(isql.DB).Executor()
+	require.NoError(t, purgeMergeApplied(ctx, ie, []string{appliedTable}, jobID, replayedTS))
+	runner.CheckQueryResults(t, countQuery, [][]string{{"1"}})
+	require.NoError(t, purgeMergeApplied(ctx, ie, []string{appliedTable}, jobID, hlc.MaxTimestamp))
+	runner.CheckQueryResults(t, countQuery, [][]string{{"0"}})
+	// Tables which do not exist are skipped.
+	require.NoError(t, purgeMergeApplied(ctx, ie, []string{"defaultdb.crdb_replication.missing"}, jobID, hlc.MaxTimestamp))
+}
+
+func TestColumnMergeRuleValidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	skip.UnderDeadlock(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	server, s, dbA, dbB := setupLogicalTestServer(t, ctx, testClusterBaseClusterArgs, 1)
+	defer server.Stopper().Stop(ctx)
+	dbBURL := replicationtestutils.GetExternalConnectionURI(t, s, s, serverutils.DBName("b"))
+
+	for _, db := range []*sqlutils.SQLRunner{dbA, dbB} {
+		db.Exec(t, `CREATE TABLE counters (id INT PRIMARY KEY, n INT, label STRING, geom GEOMETRY, tags STRING[])`)
+	}
+	const stmt = `CREATE LOGICAL REPLICATION STREAM FROM TABLE counters ON $1 INTO TABLE counters WITH `
+	for _, tc := range []struct {
+		options string
+		err     string
+	}{
+		{`MERGE COLUMN n USING 'sum' FOR TABLE counters`, `unknown merge strategy "sum"`},
+		{`MERGE COLUMN label USING 'additive' FOR TABLE counters`, `merge strategy 'additive' requires a numeric column, but column "label" has type STRING`},
+		{`MERGE COLUMN geom USING 'max' FOR TABLE counters`, `merge strategies 'max' and 'min' require an orderable column, but column "geom" has type GEOMETRY`},
+		{`MERGE COLUMN label USING 'union' FOR TABLE counters`, `merge strategy 'union' requires an array column, but column "label" has type STRING`},
+		{`MERGE COLUMN id USING 'max' FOR TABLE counters`, `cannot specify a merge rule for primary key column "id"`},
+		{`MERGE COLUMN missing USING 'max' FOR TABLE counters`, `column "missing" does not exist`},
+		{`MERGE COLUMN n USING 'max' FOR TABLE other`, `MERGE COLUMN specified for table other which is not replicated`},
+	} {
+		t.Run(tc.options, func(t *testing.T) {
+			dbA.ExpectErr(t, tc.err, stmt+tc.options, dbBURL.String())
+		})
+	}
+
+	var jobID jobspb.JobID
+	dbA.QueryRow(t, stmt+`MERGE COLUMN n USING 'additive' FOR TABLE counters`, dbBURL.String()).Scan(&jobID)
+	payload := jobutils.GetJobPayload(t, dbA, jobID)
+	pairs := payload.GetLogicalReplicationDetails().ReplicationPairs
+	require.Len(t, pairs, 1)
+	require.Equal(t, []jobspb.LogicalReplicationDetails_ColumnMergeRule{{
+		ColumnID: 2,
+		Strategy: jobspb.LogicalReplicationDetails_ColumnMergeRule_Additive,
+	}}, pairs[0].ColumnMergeRules)
+}
@@ -116,6 +116,12 @@ func createLogicalReplicationStreamPlanHook(
 		}
 
 		hasUDF := len(options.userFunctions) > 0 || options.defaultFunction != nil && options.defaultFunction.FunctionId != 0
+		if hasUDF && len(options.mergeRules) > 0 {
+			return pgerror.New(pgcode.InvalidParameterValue, "MERGE COLUMN cannot be used with user-defined functions")
+		}
+		if hasUDF && len(options.columnMappings) > 0 {
+			return pgerror.New(pgcode.InvalidParameterValue, "COLUMN and EXCLUDE COLUMN cannot be used with user-defined functions")
+		}
 
 		mode := jobspb.LogicalReplicationDetails_Immediate
 		if m, ok := options.GetMode(); ok {
@@ -146,6 +152,28 @@ func createLogicalReplicationStreamPlanHook(
 			}
 		}
 
+		var replicateSchemaChanges bool
+		if m, ok := options.SchemaChanges(); ok {
+			switch m {
+			case "replicate":
+				replicateSchemaChanges = true
+			case "block":
+			default:
+				return pgerror.Newf(pgcode.InvalidParameterValue, "unknown schema_changes option %q", m)
+			}
+		}
+		if replicateSchemaChanges {
+			// Replicated schema changes are applied to the destination table as
+			// DDL, which user-defined functions and column mappings could not
+			// keep up with.
+			if hasUDF {
+				return pgerror.New(pgcode.InvalidParameterValue, "SCHEMA_CHANGES = 'replicate' cannot be used with user-defined functions")
+			}
+			if len(options.columnMappings) > 0 {
+				return pgerror.New(pgcode.InvalidParameterValue, "SCHEMA_CHANGES = 'replicate' cannot be used with COLUMN or EXCLUDE COLUMN")
+			}
+		}
+
 		resolvedDestObjects, err := resolveDestinationObjects(ctx, p, p.SessionData(), stmt.Into, stmt.CreateTable)
 		if err != nil {
 			return err
@@ -228,6 +256,7 @@ func createLogicalReplicationStreamPlanHook(
 			TableNames:                  srcTableNames,
 			AllowOffline:                options.ParentID != 0,
 			UnvalidatedReverseStreamURI: options.BidirectionalURI(),
+			ReplicateSchemaChanges:      replicateSchemaChanges,
 		})
 		if err != nil {
 			return err
@@ -280,6 +309,24 @@ func createLogicalReplicationStreamPlanHook(
 				repPairs[i].DstFunctionID = uf[name]
 			}
 		}
+		for name := range options.mergeRules {
+			found := false
+			for _, srcName := range srcTableNames {
+				found = found || srcName == name
+			}
+			if !found {
+				return pgerror.Newf(pgcode.InvalidParameterValue, "MERGE COLUMN specified for table %s which is not replicated", name)
+			}
+		}
+		for name := range options.columnMappings {
+			found := false
+			for _, srcName := range srcTableNames {
+				found = found || srcName == name
+			}
+			if !found {
+				return pgerror.Newf(pgcode.InvalidParameterValue, "COLUMN specified for table %s which is not replicated", name)
+			}
+		}
 		if throwNoTTLWithCDCIgnoreError {
 			return pgerror.Newf(pgcode.InvalidParameterValue, "DISCARD = 'ttl-deletes' specified but no tables have changefeed-excluded TTLs")
 		}
@@ -325,10 +372,11 @@ func createLogicalReplicationStreamPlanHook(
 				ParentID:                  int64(options.ParentID),
 				Command:                   stmt.String(),
 				SkipSchemaCheck:           options.SkipSchemaCheck(),
+				ReplicateSchemaChanges:    replicateSchemaChanges,
 			},
 			Progress: progress,
 		}
-		if err := doLDRPlan(ctx, p.User(), p.ExecCfg(), jr, spec.ExternalCatalog, resolvedDestObjects); err != nil {
+		if err := doLDRPlan(ctx, p.User(), p.ExecCfg(), jr, spec.ExternalCatalog, resolvedDestObjects, options.mergeRules, options.columnMappings); err != nil {
 			return err
 		}
 		resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jr.JobID))}
@@ -432,6 +480,8 @@ func doLDRPlan(
 	jr jobs.Record,
 	srcExternalCatalog externalpb.ExternalCatalog,
 	resolvedDestObjects ResolvedDestObjects,
+	mergeRules columnMergeRulesByTable,
+	columnMappings columnMappingsByTable,
 ) error {
 	details := jr.Details.(jobspb.LogicalReplicationDetails)
 	return execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
@@ -477,10 +527,25 @@ func doLDRPlan(
 			}
 		}
 
+		for i := range details.ReplicationPairs {
+			details.ReplicationPairs[i].ColumnMergeRules, err = resolveColumnMergeRules(dstTableDescs[i], mergeRules[details.TableNames[i]])
+			if err != nil {
+				return err
+			}
+			srcTableDesc := tabledesc.NewBuilder(&srcExternalCatalog.Tables[i]).BuildImmutableTable()
+			details.ReplicationPairs[i].ColumnMappings, err = resolveColumnMappings(ctx, srcTableDesc, dstTableDescs[i], columnMappings[details.TableNames[i]])
+			if err != nil {
+				return err
+			}
+		}
+
 		writer, err := getWriterType(ctx, details.Mode, execCfg.Settings)
 		if err != nil {
 			return err
 		}
+		if hasColumnMergeRules(details.ReplicationPairs) || hasColumnMappings(details.ReplicationPairs) || details.ReplicateSchemaChanges {
+			writer = sqlclustersettings.LDRWriterTypeCRUD
+		}
 
 		for i := range srcExternalCatalog.Tables {
 			destTableDesc := dstTableDescs[i]
@@ -490,13 +555,17 @@ func doLDRPlan(
 				}
 			}
 
-			err := tabledesc.CheckLogicalReplicationCompatibility(&srcExternalCatalog.Tables[i], destTableDesc.TableDesc(), details.SkipSchemaCheck || details.CreateTable, writer == sqlclustersettings.LDRWriterTypeLegacyKV)
+			// Tables with column mappings were already checked by
+			// resolveColumnMappings, which tolerates differences in the set of
+			// columns.
+			skipEquivalenceCheck := details.SkipSchemaCheck || details.CreateTable || len(details.ReplicationPairs[i].ColumnMappings) != 0
+			err := tabledesc.CheckLogicalReplicationCompatibility(&srcExternalCatalog.Tables[i], destTableDesc.TableDesc(), skipEquivalenceCheck, writer == sqlclustersettings.LDRWriterTypeLegacyKV)
 			if err != nil {
 				return err
 			}
 		}
 
-		if err := replicationutils.LockLDRTables(ctx, txn, dstTableDescs, jr.JobID); err != nil {
+		if err := replicationutils.LockLDRTables(ctx, txn, dstTableDescs, jr.JobID, details.ReplicateSchemaChanges, false /* source */); err != nil {
 			return err
 		}
 		if _, err := execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, jr, jr.JobID, txn); err != nil {
@@ -521,6 +590,7 @@ func createLogicalReplicationStreamTypeCheck(
 			stmt.Options.Mode,
 			stmt.Options.MetricsLabel,
 			stmt.Options.Discard,
+			stmt.Options.SchemaChanges,
 			stmt.Options.BidirectionalURI,
 			stmt.Options.ParentID,
 		},
@@ -530,6 +600,9 @@ func createLogicalReplicationStreamTypeCheck(
 			stmt.Options.Unidirectional,
 		},
 	}
+	for _, rule := range stmt.Options.MergeRules {
+		toTypeCheck = append(toTypeCheck, exprutil.Strings{rule.Strategy})
+	}
 	if err := exprutil.TypeCheck(ctx, "LOGICAL REPLICATION STREAM", p.SemaCtx(),
 		toTypeCheck...,
 	); err != nil {
@@ -545,7 +618,10 @@ type resolvedLogicalReplicationOptions struct {
 	defaultFunction *jobspb.LogicalReplicationDetails_DefaultConflictResolution
 	// Mapping of table name to function descriptor
 	userFunctions    map[string]int32
+	mergeRules       columnMergeRulesByTable
+	columnMappings   columnMappingsByTable
 	discard          string
+	schemaChanges    string
 	skipSchemaCheck  bool
 	metricsLabel     string
 	bidirectionalURI string
@@ -632,6 +708,47 @@ func evalLogicalReplicationOptions(
 		}
 	}
 
+	if options.MergeRules != nil {
+		r.mergeRules = make(columnMergeRulesByTable)
+		for _, rule := range options.MergeRules {
+			objName, err := rule.Table.ToUnresolvedObjectName(tree.NoAnnotation)
+			if err != nil {
+				return nil, err
+			}
+			strategyName, err := eval.String(ctx, rule.Strategy)
+			if err != nil {
+				return nil, err
+			}
+			strategy, err := parseMergeStrategy(strategyName)
+			if err != nil {
+				return nil, err
+			}
+			if r.mergeRules[objName.String()] == nil {
+				r.mergeRules[objName.String()] = make(map[string]mergeStrategy)
+			}
+			r.mergeRules[objName.String()][string(rule.Column)] = strategy
+		}
+	}
+
+	if options.ColumnMappings != nil {
+		if createTable {
+			return nil, pgerror.New(pgcode.InvalidParameterValue, "COLUMN and EXCLUDE COLUMN cannot be used when creating the destination tables")
+		}
+		r.columnMappings = make(columnMappingsByTable)
+		for _, mapping := range options.ColumnMappings {
+			objName, err := mapping.Table.ToUnresolvedObjectName(tree.NoAnnotation)
+			if err != nil {
+				return nil, err
+			}
+			m := r.columnMappings.forTable(objName.String())
+			if mapping.IsExclude() {
+				m.excluded[string(mapping.Column)] = struct{}{}
+			} else {
+				m.exprs[string(mapping.Column)] = mapping.Expr
+			}
+		}
+	}
+
 	if options.Discard != nil {
 		discard, err := eval.String(ctx, options.Discard)
 		if err != nil {
@@ -639,6 +756,13 @@ func evalLogicalReplicationOptions(
 		}
 		r.discard = discard
 	}
+	if options.SchemaChanges != nil {
+		schemaChanges, err := eval.String(ctx, options.SchemaChanges)
+		if err != nil {
+			return nil, err
+		}
+		r.schemaChanges = schemaChanges
+	}
 	if options.SkipSchemaCheck == tree.DBoolTrue {
 		r.skipSchemaCheck = true
 	}
@@ -726,6 +850,13 @@ func (r *resolvedLogicalReplicationOptions) Discard() (string, bool) {
 	return r.discard, true
 }
 
+func (r *resolvedLogicalReplicationOptions) SchemaChanges() (string, bool) {
+	if r == nil || r.schemaChanges == "" {
+		return "", false
+	}
+	return r.schemaChanges, true
+}
+
 func (r *resolvedLogicalReplicationOptions) SkipSchemaCheck() bool {
 	if r == nil {
 		return false
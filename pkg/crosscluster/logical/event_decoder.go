@@ -13,7 +13,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
-	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -62,35 +61,25 @@ type decodedEvent struct {
 	prevRow tree.Datums
 }
 
+// newEventDecoder creates an eventDecoder that decodes the events of each
+// source table and maps them to the replicated columns of the destination
+// table using the mapper of the destination table.
 func newEventDecoder(
 	ctx context.Context,
-	descriptors descs.DB,
 	settings *cluster.Settings,
 	procConfigByDestID map[descpb.ID]sqlProcessorTableConfig,
+	mappers map[descpb.ID]*columnMapper,
 ) (*eventDecoder, error) {
 	srcToDest := make(map[descpb.ID]destinationTable, len(procConfigByDestID))
-	err := descriptors.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
-		for dstID, s := range procConfigByDestID {
-			descriptor, err := txn.Descriptors().GetLeasedImmutableTableByID(ctx, txn.KV(), dstID)
-			if err != nil {
-				return err
-			}
-
-			columns := getColumnSchema(descriptor)
-			columnNames := make([]string, 0, len(columns))
-			for _, column := range columns {
-				columnNames = append(columnNames, column.column.GetName())
-			}
-
-			srcToDest[s.srcDesc.GetID()] = destinationTable{
-				id:      dstID,
-				columns: columnNames,
-			}
+	for dstID, s := range procConfigByDestID {
+		mapper, ok := mappers[dstID]
+		if !ok {
+			return nil, errors.AssertionFailedf("no column mapper for table %d", dstID)
+		}
+		srcToDest[s.srcDesc.GetID()] = destinationTable{
+			id:     dstID,
+			mapper: mapper,
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	decoder, err := newCdcEventDecoder(ctx, procConfigByDestID, settings)
@@ -117,8 +106,12 @@ func (d *eventDecoder) decodeEvent(
 		return decodedEvent{}, errors.AssertionFailedf("table %d not found", decodedRow.TableID)
 	}
 
-	row := make(tree.Datums, 0, len(dstTable.columns))
-	row, err = appendDatums(row, decodedRow, dstTable.columns)
+	srcColumns := dstTable.mapper.sourceColumns
+	row, err := appendDatums(make(tree.Datums, 0, len(srcColumns)), decodedRow, srcColumns)
+	if err != nil {
+		return decodedEvent{}, err
+	}
+	row, err = dstTable.mapper.mapRow(ctx, row)
 	if err != nil {
 		return decodedEvent{}, err
 	}
@@ -133,8 +126,11 @@ func (d *eventDecoder) decodeEvent(
 		return decodedEvent{}, err
 	}
 
-	prevRow := make(tree.Datums, 0, len(dstTable.columns))
-	prevRow, err = appendDatums(prevRow, decodedPrevRow, dstTable.columns)
+	prevRow, err := appendDatums(make(tree.Datums, 0, len(srcColumns)), decodedPrevRow, srcColumns)
+	if err != nil {
+		return decodedEvent{}, err
+	}
+	prevRow, err = dstTable.mapper.mapRow(ctx, prevRow)
 	if err != nil {
 		return decodedEvent{}, err
 	}
@@ -179,6 +175,6 @@ func appendDatums(datums tree.Datums, row cdcevent.Row, columnNames []string) (t
 }
 
 type destinationTable struct {
-	id      descpb.ID
-	columns []string
+	id     descpb.ID
+	mapper *columnMapper
 }
@@ -38,6 +38,7 @@ func constructLogicalReplicationWriterSpecs(
 	mode jobspb.LogicalReplicationDetails_ApplyMode,
 	metricsLabel string,
 	writer sqlclustersettings.LDRWriterType,
+	replicateSchemaChanges bool,
 ) (map[base.SQLInstanceID][]execinfrapb.LogicalReplicationWriterSpec, error) {
 	spanGroup := roachpb.SpanGroup{}
 	baseSpec := execinfrapb.LogicalReplicationWriterSpec{
@@ -53,6 +54,7 @@ func constructLogicalReplicationWriterSpecs(
 		MetricsLabel:                metricsLabel,
 		TypeDescriptors:             srcTypes,
 		WriterType:                  string(writer),
+		ReplicateSchemaChanges:      replicateSchemaChanges,
 	}
 
 	writerSpecs := make(map[base.SQLInstanceID][]execinfrapb.LogicalReplicationWriterSpec, len(destSQLInstances))
@@ -234,6 +234,11 @@ func (r *logicalReplicationResumer) ingest(
 		if err := dlqClient.Create(ctx); err != nil {
 			return errors.Wrap(err, "failed to create dead letter queue")
 		}
+		if err := createMergeAppliedTables(
+			ctx, execCfg.InternalDB.Executor(), payload.ReplicationPairs, planInfo.destTableBySrcID,
+		); err != nil {
+			return err
+		}
 	}
 
 	frontier, err := span.MakeFrontierAt(replicatedTimeAtStart, planInfo.sourceSpans...)
@@ -288,6 +293,8 @@ func (r *logicalReplicationResumer) ingest(
 			job:                   r.job,
 			frontierUpdates:       heartbeatSender.FrontierUpdates,
 			rangeStats:            newRangeStatsCollector(planInfo.writeProcessorCount),
+			ie:                    execCfg.InternalDB.Executor(),
+			mergeAppliedTables:    mergeAppliedTableNames(payload.ReplicationPairs, planInfo.destTableBySrcID),
 			r:                     r,
 		}
 		rowResultWriter := sql.NewCallbackResultWriter(rh.handleRow)
@@ -335,6 +342,10 @@ func (r *logicalReplicationResumer) ingest(
 	if errors.Is(err, sql.ErrPlanChanged) {
 		metrics.ReplanCount.Inc(1)
 	}
+	var schemaChangeErr *sourceSchemaChangeError
+	if errors.As(err, &schemaChangeErr) {
+		return r.applySourceSchemaChange(ctx, jobExecCtx, planInfo.destTableBySrcID, schemaChangeErr.change)
+	}
 	return err
 }
 
@@ -491,6 +502,11 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 	if asOf.IsEmpty() {
 		asOf = payload.ReplicationStartTime
 	}
+	// After a replicated schema change, plan with the source descriptors as of
+	// the schema change.
+	if asOf.Less(progress.SchemaChangeTime) {
+		asOf = progress.SchemaChangeTime
+	}
 
 	req := streampb.LogicalReplicationPlanRequest{
 		PlanAsOf: asOf,
@@ -499,6 +515,9 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 		UseTableSpan: payload.CreateTable && progress.ReplicatedTime.IsEmpty(),
 		StreamID:     streampb.StreamID(payload.StreamID),
 	}
+	// The offline initial scan ingests the tables as of the start time, so
+	// there are no schema changes to replicate during it.
+	req.ReplicateSchemaChanges = payload.ReplicateSchemaChanges && !req.UseTableSpan
 	for _, pair := range payload.ReplicationPairs {
 		req.TableIDs = append(req.TableIDs, pair.SrcDescriptorID)
 	}
@@ -523,6 +542,9 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 	if err != nil {
 		return nil, nil, info, err
 	}
+	if hasColumnMergeRules(payload.ReplicationPairs) || hasColumnMappings(payload.ReplicationPairs) || payload.ReplicateSchemaChanges {
+		writer = sqlclustersettings.LDRWriterTypeCRUD
+	}
 	crossClusterResolver := crosscluster.MakeCrossClusterTypeResolver(plan.SourceTypes)
 	tableMetadataByDestID := make(map[int32]execinfrapb.TableReplicationMetadata)
 	if err := sql.DescsTxn(ctx, execCfg, func(ctx context.Context, txn isql.Txn, descriptors *descs.Collection) error {
@@ -548,7 +570,18 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 				return errors.Wrapf(err, "failed to look up schema descriptor for table %d", pair.DstDescriptorID)
 			}
 
-			if err := tabledesc.CheckLogicalReplicationCompatibility(&srcTableDesc, dstTableDesc.TableDesc(), payload.SkipSchemaCheck || payload.CreateTable, writer == sqlclustersettings.LDRWriterTypeLegacyKV); err != nil {
+			// The crud writer only replicates the columns the tables have in
+			// common, or the columns listed by the column mappings, so columns
+			// added to either table since the job was created are tolerated as
+			// long as the mapping is still valid.
+			skipEquivalenceCheck := payload.SkipSchemaCheck || payload.CreateTable
+			if writer == sqlclustersettings.LDRWriterTypeCRUD && !skipEquivalenceCheck {
+				if err := checkColumnMappings(ctx, cpy, dstTableDesc, pair.ColumnMappings); err != nil {
+					return err
+				}
+				skipEquivalenceCheck = true
+			}
+			if err := tabledesc.CheckLogicalReplicationCompatibility(&srcTableDesc, dstTableDesc.TableDesc(), skipEquivalenceCheck, writer == sqlclustersettings.LDRWriterTypeLegacyKV); err != nil {
 				return err
 			}
 
@@ -565,6 +598,8 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 				DestinationParentSchemaName:   scDesc.GetName(),
 				DestinationTableName:          dstTableDesc.GetName(),
 				DestinationFunctionOID:        uint32(fnOID),
+				ColumnMergeRules:              pair.ColumnMergeRules,
+				ColumnMappings:                pair.ColumnMappings,
 			}
 			info.destTableBySrcID[descpb.ID(pair.SrcDescriptorID)] = dstTableMetadata{
 				database: dbDesc.GetName(),
@@ -607,6 +642,7 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 		payload.Mode,
 		payload.MetricsLabel,
 		writer,
+		payload.ReplicateSchemaChanges,
 	)
 	if err != nil {
 		return nil, nil, info, err
@@ -755,11 +791,36 @@ type rowHandler struct {
 
 	rangeStats rangeStatsByProcessorID
 
+	// schemaChange is the earliest source schema change reported by the
+	// processors, if any.
+	schemaChange *jobspb.LogicalReplicationSchemaChange
+
 	lastPartitionUpdate time.Time
 
+	// mergeAppliedTables are the merge applied tables of the replicated tables
+	// with column merge rules, which are purged up to the persisted replicated
+	// time at most every mergeAppliedPurgeInterval.
+	ie                    isql.Executor
+	mergeAppliedTables    []string
+	lastMergeAppliedPurge time.Time
+
 	r *logicalReplicationResumer
 }
 
+// maybePurgeMergeApplied purges the merge applied tables up to the persisted
+// replicated time, if they were not purged recently. Failures are logged, since
+// the rows are purged again later.
+func (rh *rowHandler) maybePurgeMergeApplied(ctx context.Context, replicatedTime hlc.Timestamp) {
+	if len(rh.mergeAppliedTables) == 0 || replicatedTime.IsEmpty() ||
+		timeutil.Since(rh.lastMergeAppliedPurge) < mergeAppliedPurgeInterval {
+		return
+	}
+	rh.lastMergeAppliedPurge = timeutil.Now()
+	if err := purgeMergeApplied(ctx, rh.ie, rh.mergeAppliedTables, rh.job.ID(), replicatedTime); err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+}
+
 func (rh *rowHandler) handleTraceAgg(agg *execinfrapb.TracingAggregatorEvents) {
 	componentID := execinfrapb.ComponentID{
 		FlowID:        agg.FlowID,
@@ -782,6 +843,17 @@ func (rh *rowHandler) handleMeta(ctx context.Context, meta *execinfrapb.Producer
 		return nil
 	}
 
+	if pbtypes.Is(&meta.BulkProcessorProgress.ProgressDetails, &jobspb.LogicalReplicationSchemaChange{}) {
+		var change jobspb.LogicalReplicationSchemaChange
+		if err := pbtypes.UnmarshalAny(&meta.BulkProcessorProgress.ProgressDetails, &change); err != nil {
+			return errors.Wrap(err, "unable to unmarshal progress details")
+		}
+		if rh.schemaChange == nil || change.Timestamp.Less(rh.schemaChange.Timestamp) {
+			rh.schemaChange = &change
+		}
+		return nil
+	}
+
 	var stats streampb.StreamEvent_RangeStats
 	if err := pbtypes.UnmarshalAny(&meta.BulkProcessorProgress.ProgressDetails, &stats); err != nil {
 		return errors.Wrap(err, "unable to unmarshal progress details")
@@ -809,7 +881,10 @@ func (rh *rowHandler) handleRow(ctx context.Context, row tree.Datums) error {
 		}
 	}
 	replicatedTime := rh.frontier.Frontier()
-	alwaysPersist := rh.replicatedTimeAtStart.Less(replicatedTime) && rh.replicatedTimeAtStart.IsEmpty()
+	// The processors stop just before a source schema change, so once the
+	// replicated time reaches it, the schema change can be applied.
+	reachedSchemaChange := rh.schemaChange != nil && rh.schemaChange.Timestamp.Prev().LessEq(replicatedTime)
+	alwaysPersist := (rh.replicatedTimeAtStart.Less(replicatedTime) && rh.replicatedTimeAtStart.IsEmpty()) || reachedSchemaChange
 
 	updateFreq := jobCheckpointFrequency.Get(rh.settings)
 	if !alwaysPersist && (updateFreq == 0 || timeutil.Since(rh.lastPartitionUpdate) < updateFreq) {
@@ -869,6 +944,7 @@ func (rh *rowHandler) handleRow(ctx context.Context, row tree.Datums) error {
 		}); err != nil {
 		return err
 	}
+	rh.maybePurgeMergeApplied(ctx, replicatedTime)
 	select {
 	case rh.frontierUpdates <- replicatedTime:
 	case <-ctx.Done():
@@ -880,6 +956,9 @@ func (rh *rowHandler) handleRow(ctx context.Context, row tree.Datums) error {
 		// new heartbeat will be sent.
 		return errOfflineInitialScanComplete
 	}
+	if reachedSchemaChange {
+		return &sourceSchemaChangeError{change: rh.schemaChange}
+	}
 	return nil
 }
 
@@ -899,7 +978,9 @@ func (r *logicalReplicationResumer) ingestWithRetries(
 		// permanent job error in which case we pause the job.
 		// We also stop the job when this is a context cancellation error
 		// as requested pause or cancel will trigger a context cancellation.
-		if jobs.IsPermanentJobError(err) || ctx.Err() != nil {
+		// Schema changes that cannot be replicated pause the job until the
+		// operator applies them to the destination.
+		if jobs.IsPermanentJobError(err) || errors.Is(err, errUnsupportedSchemaChange) || ctx.Err() != nil {
 			break
 		}
 
@@ -967,10 +1048,49 @@ func (r *logicalReplicationResumer) OnFailOrCancel(
 		}
 	}
 
+	r.deleteMergeAppliedRecords(ctx, execCfg, details.ReplicationPairs)
 	r.completeProducerJob(ctx, execCfg.InternalDB)
 	return nil
 }
 
+// deleteMergeAppliedRecords deletes the records of the job from the merge
+// applied tables of its replicated tables. Failures are logged, since the job
+// is being dropped.
+func (r *logicalReplicationResumer) deleteMergeAppliedRecords(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	pairs []jobspb.LogicalReplicationDetails_ReplicationPair,
+) {
+	destTableBySrcID := make(map[descpb.ID]dstTableMetadata)
+	for _, pair := range pairs {
+		if len(pair.ColumnMergeRules) == 0 {
+			continue
+		}
+		if err := sql.DescsTxn(ctx, execCfg, func(ctx context.Context, txn isql.Txn, descriptors *descs.Collection) error {
+			table, err := descriptors.ByIDWithoutLeased(txn.KV()).Get().Table(ctx, descpb.ID(pair.DstDescriptorID))
+			if err != nil {
+				return err
+			}
+			db, err := descriptors.ByIDWithoutLeased(txn.KV()).Get().Database(ctx, table.GetParentID())
+			if err != nil {
+				return err
+			}
+			destTableBySrcID[descpb.ID(pair.SrcDescriptorID)] = dstTableMetadata{
+				database: db.GetName(),
+				tableID:  table.GetID(),
+			}
+			return nil
+		}); err != nil {
+			log.Warningf(ctx, "unable to look up the merge applied table of table %d: %v", pair.DstDescriptorID, err)
+		}
+	}
+	if err := purgeMergeApplied(ctx, execCfg.InternalDB.Executor(),
+		mergeAppliedTableNames(pairs, destTableBySrcID), r.job.ID(), hlc.MaxTimestamp,
+	); err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+}
+
 // CollectProfile implements the jobs.Resumer interface.
 func (r *logicalReplicationResumer) CollectProfile(ctx context.Context, execCtx interface{}) error {
 	p := execCtx.(sql.JobExecContext)
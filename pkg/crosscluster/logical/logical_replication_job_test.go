@@ -2299,7 +2299,7 @@ func TestLogicalReplicationSchemaChanges(t *testing.T) {
 		{"drop table", "DROP TABLE tab", false},
 
 		// Dissalow storage param updates if is not the only change.
-		{"storage param update", "ALTER TABLE tab ADD COLUMN C INT, SET (fillfactor = 70)", false},
+		{"storage param update", "ALTER TABLE tab ADD COLUMN C INT DEFAULT 1, SET (fillfactor = 70)", false},
 		{"storage param update", "ALTER TABLE tab SET (fillfactor = 70)", true},
 
 		// Allow ttl schema changes that do not conduct a backfill.
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -130,6 +131,11 @@ type logicalReplicationWriterProcessor struct {
 
 	rangeStatsCh chan *streampb.StreamEvent_RangeStats
 
+	// barrier is set if the job replicates schema changes. It holds back the
+	// rows that may follow a schema change of a source table.
+	barrier        *schemaChangeBarrier
+	schemaChangeCh chan *jobspb.LogicalReplicationSchemaChange
+
 	agg      *tracing.TracingAggregator
 	aggTimer timeutil.Timer
 
@@ -189,8 +195,10 @@ func newLogicalReplicationWriterProcessor(
 			return nil, err
 		}
 		procConfigByDestTableID[descpb.ID(dstTableID)] = sqlProcessorTableConfig{
-			srcDesc: srcDesc,
-			dstOID:  md.DestinationFunctionOID,
+			srcDesc:        srcDesc,
+			dstOID:         md.DestinationFunctionOID,
+			mergeRules:     md.ColumnMergeRules,
+			columnMappings: md.ColumnMappings,
 		}
 
 		destTableBySrcID[md.SourceDescriptor.GetID()] = dstTableMetadata{
@@ -201,6 +209,20 @@ func newLogicalReplicationWriterProcessor(
 		}
 	}
 
+	var barrier *schemaChangeBarrier
+	if spec.ReplicateSchemaChanges {
+		srcDescs := make(map[descpb.ID]catalog.TableDescriptor, len(procConfigByDestTableID))
+		for _, tc := range procConfigByDestTableID {
+			srcDescs[tc.srcDesc.GetID()] = tc.srcDesc
+		}
+		startTime := spec.PreviousReplicatedTimestamp
+		startTime.Forward(spec.InitialScanTimestamp)
+		barrier, err = newSchemaChangeBarrier(spec.PartitionSpec.Spans, startTime, spec.Checkpoint.ResolvedSpans, srcDescs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	dlqDbExec := flowCtx.Cfg.DB.Executor(isql.WithSessionData(sql.NewInternalSessionData(ctx, flowCtx.Cfg.Settings, "" /* opName */)))
 
 	var numTablesWithSecondaryIndexes int
@@ -218,6 +240,8 @@ func newLogicalReplicationWriterProcessor(
 		stopCh:         make(chan struct{}),
 		checkpointCh:   make(chan []jobspb.ResolvedSpan),
 		rangeStatsCh:   make(chan *streampb.StreamEvent_RangeStats),
+		barrier:        barrier,
+		schemaChangeCh: make(chan *jobspb.LogicalReplicationSchemaChange),
 		errCh:          make(chan error, 1),
 		logBufferEvery: log.Every(30 * time.Second),
 		debug: streampb.DebugLogicalConsumerStatus{
@@ -395,7 +419,14 @@ func (lrw *logicalReplicationWriterProcessor) Next() (
 			lrw.FlowCtx.NodeID.SQLInstanceID(), lrw.FlowCtx.ID, lrw.agg)
 
 	case stats := <-lrw.rangeStatsCh:
-		meta, err := lrw.newRangeStatsProgressMeta(stats)
+		meta, err := lrw.newProgressMeta(stats)
+		if err != nil {
+			lrw.MoveToDrainingAndLogError(err)
+			return nil, lrw.DrainHelper()
+		}
+		return nil, meta
+	case change := <-lrw.schemaChangeCh:
+		meta, err := lrw.newProgressMeta(change)
 		if err != nil {
 			lrw.MoveToDrainingAndLogError(err)
 			return nil, lrw.DrainHelper()
@@ -431,6 +462,9 @@ func (lrw *logicalReplicationWriterProcessor) close() {
 	}
 	log.Infof(lrw.Ctx(), "logical replication writer processor closing")
 	defer lrw.frontier.Release()
+	if lrw.barrier != nil {
+		defer lrw.barrier.release()
+	}
 
 	if lrw.streamPartitionClient != nil {
 		_ = lrw.streamPartitionClient.Close(lrw.Ctx())
@@ -513,11 +547,28 @@ func (lrw *logicalReplicationWriterProcessor) handleEvent(
 
 	switch event.Type() {
 	case crosscluster.KVEvent:
-		if err := lrw.handleStreamBuffer(ctx, event.GetKVs()); err != nil {
+		kvs := event.GetKVs()
+		if lrw.barrier != nil {
+			var err error
+			if kvs, err = lrw.barrier.admit(kvs); err != nil {
+				return err
+			}
+			if len(kvs) == 0 {
+				return nil
+			}
+		}
+		if err := lrw.handleStreamBuffer(ctx, kvs); err != nil {
 			return err
 		}
 	case crosscluster.CheckpointEvent:
-		if err := lrw.maybeCheckpoint(ctx, event.GetCheckpoint()); err != nil {
+		checkpoint := event.GetCheckpoint()
+		if lrw.barrier != nil {
+			var err error
+			if checkpoint, err = lrw.passBarrier(ctx, checkpoint); err != nil {
+				return err
+			}
+		}
+		if err := lrw.maybeCheckpoint(ctx, checkpoint); err != nil {
 			return err
 		}
 	case crosscluster.SSTableEvent, crosscluster.DeleteRangeEvent:
@@ -534,6 +585,36 @@ func (lrw *logicalReplicationWriterProcessor) handleEvent(
 	return nil
 }
 
+// passBarrier applies the held rows that the checkpoint allows to apply, and
+// returns the checkpoint with its resolved spans capped by the schema change
+// barrier. Once no earlier schema change can arrive, the first schema change is
+// reported to the coordinator.
+func (lrw *logicalReplicationWriterProcessor) passBarrier(
+	ctx context.Context, checkpoint *streampb.StreamEvent_StreamCheckpoint,
+) (*streampb.StreamEvent_StreamCheckpoint, error) {
+	ready, resolvedSpans, err := lrw.barrier.checkpoint(checkpoint.ResolvedSpans)
+	if err != nil {
+		return nil, err
+	}
+	if len(ready) > 0 {
+		if err := lrw.handleStreamBuffer(ctx, ready); err != nil {
+			return nil, err
+		}
+	}
+	if change := lrw.barrier.maybeReport(); change != nil {
+		log.Infof(ctx, "source table %d changed schema at %s", change.Descriptor.ID, change.Timestamp)
+		select {
+		case lrw.schemaChangeCh <- change:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-lrw.stopCh:
+		}
+	}
+	capped := *checkpoint
+	capped.ResolvedSpans = resolvedSpans
+	return &capped, nil
+}
+
 func (lrw *logicalReplicationWriterProcessor) maybeCheckpoint(
 	ctx context.Context, checkpoint *streampb.StreamEvent_StreamCheckpoint,
 ) error {
@@ -579,12 +660,12 @@ func (lrw *logicalReplicationWriterProcessor) rangeStats(
 	}
 }
 
-func (lrw *logicalReplicationWriterProcessor) newRangeStatsProgressMeta(
-	stats *streampb.StreamEvent_RangeStats,
+func (lrw *logicalReplicationWriterProcessor) newProgressMeta(
+	details protoutil.Message,
 ) (*execinfrapb.ProducerMetadata, error) {
-	asAny, err := pbtypes.MarshalAny(stats)
+	asAny, err := pbtypes.MarshalAny(details)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to convert stats into any proto")
+		return nil, errors.Wrap(err, "unable to convert progress details into any proto")
 	}
 	return &execinfrapb.ProducerMetadata{
 		BulkProcessorProgress: &execinfrapb.RemoteProducerMetadata_BulkProcessorProgress{
@@ -1099,6 +1180,11 @@ func (lrw *logicalReplicationWriterProcessor) shouldRetryLater(
 		return tooOld
 	}
 
+	if errors.Is(err, errUnresolvableMergeConflict) {
+		// Retrying would reach the same conclusion.
+		return errType
+	}
+
 	return retryAllowed
 }
 
@@ -224,6 +224,12 @@ func (q *queryBuffer) ApplierQueryForRow(row cdcevent.Row) (queryBuilder, error)
 type sqlProcessorTableConfig struct {
 	srcDesc catalog.TableDescriptor
 	dstOID  uint32
+	// mergeRules are the column merge rules of the destination table. They
+	// are only supported by the crud writer.
+	mergeRules []jobspb.LogicalReplicationDetails_ColumnMergeRule
+	// columnMappings are the column mappings of the destination table. They
+	// are only supported by the crud writer.
+	columnMappings []jobspb.LogicalReplicationDetails_ColumnMapping
 }
 
 func makeSQLProcessorFromQuerier(
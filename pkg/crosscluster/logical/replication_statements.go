@@ -82,12 +82,11 @@ func newTypedPlaceholder(idx int, col catalog.Column) (*tree.CastExpr, error) {
 // newInsertStatement returns a statement that can be used to insert a row into
 // the table.
 //
-// The statement will have `n` parameters, where `n` is the number of columns
-// in the table. Parameters are ordered by column ID.
+// The statement will have `n` parameters, where `n` is the number of
+// replicated columns. Parameters are ordered by column ID.
 func newInsertStatement(
-	table catalog.TableDescriptor,
+	table catalog.TableDescriptor, columns []columnSchema,
 ) (statements.Statement[tree.Statement], error) {
-	columns := getColumnSchema(table)
 
 	columnNames := make(tree.NameList, 0, len(columns))
 	parameters := make(tree.Exprs, 0, len(columns))
@@ -182,9 +181,8 @@ func newMatchesLastRow(columns []columnSchema, startParamIdx int) (tree.Expr, er
 //
 // Parameters are ordered by column ID.
 func newUpdateStatement(
-	table catalog.TableDescriptor,
+	table catalog.TableDescriptor, columns []columnSchema,
 ) (statements.Statement[tree.Statement], error) {
-	columns := getColumnSchema(table)
 
 	// Create WHERE clause for matching the previous row values
 	whereClause, err := newMatchesLastRow(columns, 1)
@@ -233,14 +231,13 @@ func newUpdateStatement(
 
 // newDeleteStatement returns a statement that can be used to delete a row from
 // the table. The statement will have `n` parameters, where `n` is the number of
-// columns in the table. Parameters are used in the WHERE clause to precisely
+// replicated columns. Parameters are used in the WHERE clause to precisely
 // identify the row to delete.
 //
 // Parameters are ordered by column ID.
 func newDeleteStatement(
-	table catalog.TableDescriptor,
+	table catalog.TableDescriptor, columns []columnSchema,
 ) (statements.Statement[tree.Statement], error) {
-	columns := getColumnSchema(table)
 
 	// Create WHERE clause for matching the row to delete
 	whereClause, err := newMatchesLastRow(columns, 1)
@@ -288,9 +285,8 @@ func newDeleteStatement(
 //		ON replication_target.id = key_list.key1
 //			AND replication_target.secondary_id = key_list.key2
 func newBulkSelectStatement(
-	table catalog.TableDescriptor,
+	table catalog.TableDescriptor, cols []columnSchema,
 ) (statements.Statement[tree.Statement], error) {
-	cols := getColumnSchema(table)
 	primaryKeyColumns := make([]catalog.Column, 0, len(cols))
 	for _, col := range cols {
 		if col.isPrimaryKey {
@@ -95,7 +95,7 @@ func TestReplicationStatements(t *testing.T) {
 
 				desc := getTableDesc(tableName)
 
-				insertStmt, err := newInsertStatement(desc)
+				insertStmt, err := newInsertStatement(desc, getColumnSchema(desc))
 				require.NoError(t, err)
 
 				prepareStatement(t, sqlDB, getTypes(desc), insertStmt)
@@ -107,7 +107,7 @@ func TestReplicationStatements(t *testing.T) {
 
 				desc := getTableDesc(tableName)
 
-				updateStmt, err := newUpdateStatement(desc)
+				updateStmt, err := newUpdateStatement(desc, getColumnSchema(desc))
 				require.NoError(t, err)
 
 				// update expects previous and current values to be passed as
@@ -124,7 +124,7 @@ func TestReplicationStatements(t *testing.T) {
 
 				// delete expects previous and current values to be passed as
 				// parameters.
-				deleteStmt, err := newDeleteStatement(desc)
+				deleteStmt, err := newDeleteStatement(desc, getColumnSchema(desc))
 				require.NoError(t, err)
 
 				types := slices.Concat(getTypes(desc), getTypes(desc))
@@ -137,7 +137,7 @@ func TestReplicationStatements(t *testing.T) {
 
 				desc := getTableDesc(tableName)
 
-				stmt, err := newBulkSelectStatement(desc)
+				stmt, err := newBulkSelectStatement(desc, getColumnSchema(desc))
 				require.NoError(t, err)
 
 				allColumns := getColumnSchema(desc)
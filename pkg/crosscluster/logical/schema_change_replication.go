@@ -0,0 +1,608 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/idxtype"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// Logical replication jobs created with SCHEMA_CHANGES = 'replicate' also
+// stream the system.descriptor rows of their source tables. Every writer
+// processor watches the descriptors and stops applying rows at the first
+// descriptor version that changes the replicated shape of a source table,
+// which is a barrier all processors agree on. Once the replicated time reaches
+// the barrier, the coordinator applies the equivalent schema change to the
+// destination table and replans the job as of the barrier, so that the rows
+// written after the schema change are decoded with the new descriptor.
+
+// errReplanAfterSchemaChange is returned by the coordinator once it applied a
+// replicated schema change, so that the job is replanned with the new source
+// descriptors.
+var errReplanAfterSchemaChange = errors.New("replanning after replicated schema change")
+
+// errUnsupportedSchemaChange marks errors about source schema changes that
+// could not be replicated. The job pauses on such errors instead of retrying,
+// so that the operator can apply the schema change manually.
+var errUnsupportedSchemaChange = errors.New("unsupported source schema change")
+
+// sourceSchemaChangeError is returned by the coordinator's row handler once the
+// replicated time reaches a source schema change.
+type sourceSchemaChangeError struct {
+	change *jobspb.LogicalReplicationSchemaChange
+}
+
+func (e *sourceSchemaChangeError) Error() string {
+	return fmt.Sprintf("source table %d changed schema at %s", e.change.Descriptor.ID, e.change.Timestamp)
+}
+
+// sourceSchemaDiff describes how the replicated shape of a source table
+// changed between two versions of its descriptor.
+type sourceSchemaDiff struct {
+	addColumns  []catalog.Column
+	dropColumns []catalog.Column
+	addIndexes  []catalog.Index
+	dropIndexes []catalog.Index
+	addChecks   []catalog.CheckConstraint
+	// newPrimaryIndex is true if the primary index of the table was rebuilt,
+	// which moves its rows to a new span even if nothing else changed.
+	newPrimaryIndex bool
+	// unsupported describes the changes that cannot be replicated.
+	unsupported []string
+}
+
+// empty returns true if the descriptor change does not affect replication.
+func (d *sourceSchemaDiff) empty() bool {
+	return len(d.addColumns) == 0 && len(d.dropColumns) == 0 &&
+		len(d.addIndexes) == 0 && len(d.dropIndexes) == 0 &&
+		len(d.addChecks) == 0 && !d.newPrimaryIndex && len(d.unsupported) == 0
+}
+
+// diffSourceSchema compares the public columns, secondary indexes and
+// constraints of two versions of a source table descriptor.
+func diffSourceSchema(prev, next catalog.TableDescriptor) sourceSchemaDiff {
+	var d sourceSchemaDiff
+	if next.Dropped() {
+		d.unsupported = append(d.unsupported, "table was dropped")
+		return d
+	}
+
+	prevCols := make(map[descpb.ColumnID]catalog.Column)
+	for _, col := range prev.PublicColumns() {
+		prevCols[col.GetID()] = col
+	}
+	nextCols := make(map[descpb.ColumnID]catalog.Column)
+	for _, col := range next.PublicColumns() {
+		nextCols[col.GetID()] = col
+		prevCol, ok := prevCols[col.GetID()]
+		if !ok {
+			if reason := unsupportedNewColumn(col); reason != "" {
+				d.unsupported = append(d.unsupported, fmt.Sprintf("column %q was added: %s", col.GetName(), reason))
+			} else {
+				d.addColumns = append(d.addColumns, col)
+			}
+			continue
+		}
+		switch {
+		case prevCol.GetName() != col.GetName():
+			d.unsupported = append(d.unsupported, fmt.Sprintf("column %q was renamed to %q", prevCol.GetName(), col.GetName()))
+		case !sameType(prevCol.GetType(), col.GetType()):
+			d.unsupported = append(d.unsupported, fmt.Sprintf("type of column %q was changed to %s", col.GetName(), col.GetType().SQLString()))
+		case prevCol.IsNullable() != col.IsNullable():
+			d.unsupported = append(d.unsupported, fmt.Sprintf("nullability of column %q was changed", col.GetName()))
+		}
+	}
+	for _, col := range prev.PublicColumns() {
+		if _, ok := nextCols[col.GetID()]; !ok {
+			d.dropColumns = append(d.dropColumns, col)
+		}
+	}
+
+	if prev.GetPrimaryIndexID() != next.GetPrimaryIndexID() {
+		if !sameKeyColumns(prev.GetPrimaryIndex(), next.GetPrimaryIndex()) {
+			d.unsupported = append(d.unsupported, "primary key was changed")
+		}
+		d.newPrimaryIndex = true
+	}
+
+	prevIndexes := make(map[descpb.IndexID]catalog.Index)
+	for _, idx := range prev.PublicNonPrimaryIndexes() {
+		prevIndexes[idx.GetID()] = idx
+	}
+	nextIndexes := make(map[descpb.IndexID]catalog.Index)
+	for _, idx := range next.PublicNonPrimaryIndexes() {
+		nextIndexes[idx.GetID()] = idx
+		prevIdx, ok := prevIndexes[idx.GetID()]
+		if !ok {
+			if reason := unsupportedNewIndex(next, idx); reason != "" {
+				d.unsupported = append(d.unsupported, fmt.Sprintf("index %q was added: %s", idx.GetName(), reason))
+			} else {
+				d.addIndexes = append(d.addIndexes, idx)
+			}
+			continue
+		}
+		if prevIdx.GetName() != idx.GetName() {
+			d.unsupported = append(d.unsupported, fmt.Sprintf("index %q was renamed to %q", prevIdx.GetName(), idx.GetName()))
+		}
+	}
+	for _, idx := range prev.PublicNonPrimaryIndexes() {
+		if _, ok := nextIndexes[idx.GetID()]; !ok {
+			d.dropIndexes = append(d.dropIndexes, idx)
+		}
+	}
+
+	prevChecks := make(map[descpb.ConstraintID]catalog.CheckConstraint)
+	for _, c := range replicatedChecks(prev) {
+		prevChecks[c.GetConstraintID()] = c
+	}
+	nextChecks := make(map[descpb.ConstraintID]struct{})
+	for _, c := range replicatedChecks(next) {
+		nextChecks[c.GetConstraintID()] = struct{}{}
+		if _, ok := prevChecks[c.GetConstraintID()]; !ok {
+			if reason := unsupportedExpr(c.GetExpr()); reason != "" {
+				d.unsupported = append(d.unsupported, fmt.Sprintf("constraint %q was added: %s", c.GetName(), reason))
+			} else {
+				d.addChecks = append(d.addChecks, c)
+			}
+		}
+	}
+	for _, c := range prevChecks {
+		if _, ok := nextChecks[c.GetConstraintID()]; !ok {
+			d.unsupported = append(d.unsupported, fmt.Sprintf("constraint %q was dropped", c.GetName()))
+		}
+	}
+
+	if len(prev.OutboundForeignKeys()) != len(next.OutboundForeignKeys()) {
+		d.unsupported = append(d.unsupported, "foreign keys were changed")
+	}
+	if len(prev.UniqueConstraintsWithoutIndex()) != len(next.UniqueConstraintsWithoutIndex()) {
+		d.unsupported = append(d.unsupported, "unique constraints without an index were changed")
+	}
+	return d
+}
+
+// unsupportedNewColumn returns the reason a new column cannot be added to the
+// destination table, or the empty string if it can.
+func unsupportedNewColumn(col catalog.Column) string {
+	switch {
+	case col.GetType().UserDefined():
+		return "user-defined types are not supported"
+	case col.IsComputed():
+		return "computed columns are not supported"
+	case col.IsGeneratedAsIdentity():
+		return "identity columns are not supported"
+	case col.NumUsesSequences() > 0:
+		return "columns using sequences are not supported"
+	case col.HasOnUpdate():
+		return "ON UPDATE expressions are not supported"
+	case col.IsHidden() || col.IsInaccessible():
+		return "hidden columns are not supported"
+	case col.HasDefault():
+		return unsupportedExpr(col.GetDefaultExpr())
+	}
+	return ""
+}
+
+// unsupportedNewIndex returns the reason a new index cannot be created on the
+// destination table, or the empty string if it can.
+func unsupportedNewIndex(desc catalog.TableDescriptor, idx catalog.Index) string {
+	switch {
+	case idx.IsSharded():
+		return "hash-sharded indexes are not supported"
+	case idx.GetType() != idxtype.FORWARD:
+		return "only forward indexes are supported"
+	case idx.GetPartitioning().NumColumns() > 0:
+		return "partitioned indexes are not supported"
+	}
+	for i := 0; i < idx.NumKeyColumns(); i++ {
+		col, err := catalog.MustFindColumnByID(desc, idx.GetKeyColumnID(i))
+		if err != nil || col.IsInaccessible() {
+			return "expression indexes are not supported"
+		}
+	}
+	if idx.IsPartial() {
+		return unsupportedExpr(idx.GetPredicate())
+	}
+	return ""
+}
+
+// unsupportedExpr returns the reason a serialized expression of the source
+// descriptor cannot be copied to the destination table, or the empty string if
+// it can. Descriptors reference user-defined types and functions by the IDs of
+// their source descriptors, e.g. 'a':::@100107 or [FUNCTION 100108](a), which
+// do not identify the same objects in the destination cluster.
+func unsupportedExpr(exprStr string) string {
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return fmt.Sprintf("expression %q cannot be parsed", exprStr)
+	}
+	typeRefs := &tree.TypeCollectorVisitor{OIDs: make(map[oid.Oid]struct{})}
+	tree.WalkExpr(typeRefs, expr)
+	for typOID := range typeRefs.OIDs {
+		if types.IsOIDUserDefinedType(typOID) {
+			return "expressions using user-defined types are not supported"
+		}
+	}
+	fnIDs, err := schemaexpr.GetUDFIDs(expr)
+	if err != nil {
+		return fmt.Sprintf("expression %q cannot be parsed", exprStr)
+	}
+	if !fnIDs.Empty() {
+		return "expressions using user-defined functions are not supported"
+	}
+	return ""
+}
+
+// replicatedChecks returns the validated check constraints of the table that
+// were added by the user, as opposed to NOT NULL and hash-sharding checks.
+func replicatedChecks(desc catalog.TableDescriptor) []catalog.CheckConstraint {
+	var checks []catalog.CheckConstraint
+	for _, c := range desc.CheckConstraints() {
+		if c.IsConstraintValidated() && !c.IsNotNullColumnConstraint() && !c.IsHashShardingConstraint() {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// sameType compares column types. The types of the previous descriptor are
+// hydrated while those of the next one are not, so user-defined types are
+// compared by OID.
+func sameType(a, b *types.T) bool {
+	if a.UserDefined() || b.UserDefined() {
+		return a.Oid() == b.Oid()
+	}
+	return a.Identical(b)
+}
+
+func sameKeyColumns(a, b catalog.Index) bool {
+	if a.NumKeyColumns() != b.NumKeyColumns() {
+		return false
+	}
+	for i := 0; i < a.NumKeyColumns(); i++ {
+		if a.GetKeyColumnID(i) != b.GetKeyColumnID(i) ||
+			a.GetKeyColumnDirection(i) != b.GetKeyColumnDirection(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// statements returns the schema changes to apply to the destination table. The
+// statements are idempotent, since the coordinator may apply them more than
+// once if it fails before the job records the schema change.
+func (d *sourceSchemaDiff) statements(dst *tree.TableName) []string {
+	var stmts []string
+	tableName := tree.AsString(dst)
+	for _, col := range d.addColumns {
+		var b strings.Builder
+		fmt.Fprintf(&b, "ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s",
+			tableName, tree.NameString(col.GetName()), col.GetType().SQLString())
+		if !col.IsNullable() {
+			b.WriteString(" NOT NULL")
+		}
+		if col.HasDefault() {
+			fmt.Fprintf(&b, " DEFAULT (%s)", col.GetDefaultExpr())
+		}
+		stmts = append(stmts, b.String())
+	}
+	for _, idx := range d.addIndexes {
+		var b strings.Builder
+		b.WriteString("CREATE ")
+		if idx.IsUnique() {
+			b.WriteString("UNIQUE ")
+		}
+		fmt.Fprintf(&b, "INDEX IF NOT EXISTS %s ON %s (", tree.NameString(idx.GetName()), tableName)
+		for i := 0; i < idx.NumKeyColumns(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(tree.NameString(idx.GetKeyColumnName(i)))
+			if idx.GetKeyColumnDirection(i) == catenumpb.IndexColumn_DESC {
+				b.WriteString(" DESC")
+			}
+		}
+		b.WriteString(")")
+		if idx.NumSecondaryStoredColumns() > 0 {
+			b.WriteString(" STORING (")
+			for i := 0; i < idx.NumSecondaryStoredColumns(); i++ {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(tree.NameString(idx.GetStoredColumnName(i)))
+			}
+			b.WriteString(")")
+		}
+		if idx.IsPartial() {
+			fmt.Fprintf(&b, " WHERE %s", idx.GetPredicate())
+		}
+		stmts = append(stmts, b.String())
+	}
+	for _, c := range d.addChecks {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT IF NOT EXISTS %s CHECK (%s)",
+			tableName, tree.NameString(c.GetName()), c.GetExpr()))
+	}
+	for _, idx := range d.dropIndexes {
+		indexName := tree.TableIndexName{Table: *dst, Index: tree.UnrestrictedName(idx.GetName())}
+		stmts = append(stmts, fmt.Sprintf("DROP INDEX IF EXISTS %s", tree.AsString(&indexName)))
+	}
+	for _, col := range d.dropColumns {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s",
+			tableName, tree.NameString(col.GetName())))
+	}
+	return stmts
+}
+
+// applySourceSchemaChange applies a schema change of a source table to its
+// destination table and records it in the job progress, so that the job is
+// replanned as of the schema change. If the schema change cannot be
+// replicated, the returned error pauses the job.
+func (r *logicalReplicationResumer) applySourceSchemaChange(
+	ctx context.Context,
+	jobExecCtx sql.JobExecContext,
+	destTableBySrcID map[descpb.ID]dstTableMetadata,
+	change *jobspb.LogicalReplicationSchemaChange,
+) error {
+	dst, ok := destTableBySrcID[change.Descriptor.ID]
+	if !ok {
+		return errors.AssertionFailedf("schema change of unknown source table %d", change.Descriptor.ID)
+	}
+	dstName := tree.MakeTableNameWithSchema(tree.Name(dst.database), tree.Name(dst.schema), tree.Name(dst.table))
+	prev := tabledesc.NewBuilder(&change.PreviousDescriptor).BuildImmutableTable()
+	next := tabledesc.NewBuilder(&change.Descriptor).BuildImmutableTable()
+	diff := diffSourceSchema(prev, next)
+
+	applyErr := func() error {
+		if len(diff.unsupported) > 0 {
+			return errors.Newf("%s", strings.Join(diff.unsupported, "; "))
+		}
+		ie := jobExecCtx.ExecCfg().InternalDB.Executor()
+		for _, stmt := range diff.statements(&dstName) {
+			log.Infof(ctx, "replicating schema change of source table %q: %s", prev.GetName(), stmt)
+			// The session identifies the job, which allows the schema change on
+			// the destination table.
+			if _, err := ie.ExecEx(ctx, "replicate-schema-change", nil, /* txn */
+				sessiondata.InternalExecutorOverride{
+					User:                                username.NodeUserName(),
+					LogicalReplicationSchemaChangeJobID: int64(r.job.ID()),
+				}, stmt); err != nil {
+				return errors.Wrapf(err, "executing %q", stmt)
+			}
+		}
+		return nil
+	}()
+
+	// Once the schema change is recorded, the job is planned as of the
+	// schema change, so it is not detected again.
+	if err := r.job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		md.Progress.Details.(*jobspb.Progress_LogicalReplication).LogicalReplication.SchemaChangeTime = change.Timestamp
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if applyErr != nil {
+		return errors.Mark(errors.Wrapf(applyErr,
+			"schema change of source table %q at %s cannot be replicated; apply the equivalent "+
+				"schema change to %s and resume job %d", prev.GetName(), change.Timestamp, dstName.FQString(), r.job.ID()),
+			errUnsupportedSchemaChange)
+	}
+	return errReplanAfterSchemaChange
+}
+
+// schemaChangeBarrier orders the rows received by a writer processor with
+// respect to the schema changes of the source tables. It holds back the rows
+// whose timestamp is not yet covered by the resolved timestamp of the
+// descriptor spans, since a schema change could still precede them, and it
+// holds back all rows at or after the first schema change that affects
+// replication.
+type schemaChangeBarrier struct {
+	// srcDescs are the source table descriptors the processor was planned with.
+	srcDescs map[descpb.ID]catalog.TableDescriptor
+	// frontier tracks the resolved timestamps of the descriptor spans.
+	frontier span.Frontier
+	// held are the rows that cannot be applied yet.
+	held []streampb.StreamEvent_KV
+	// change is the earliest schema change that affects replication, if any.
+	change   *jobspb.LogicalReplicationSchemaChange
+	reported bool
+}
+
+// newSchemaChangeBarrier returns a barrier for the descriptor spans among the
+// given partition spans. Descriptor versions older than the plan are already
+// accounted for by the planned source descriptors, so the rows that precede
+// startTime can be applied without waiting for the descriptor spans.
+func newSchemaChangeBarrier(
+	partitionSpans []roachpb.Span,
+	startTime hlc.Timestamp,
+	checkpoint []jobspb.ResolvedSpan,
+	srcDescs map[descpb.ID]catalog.TableDescriptor,
+) (*schemaChangeBarrier, error) {
+	var descSpans []roachpb.Span
+	for _, sp := range partitionSpans {
+		if _, ok := decodeDescriptorKey(sp.Key); ok {
+			descSpans = append(descSpans, sp)
+		}
+	}
+	if len(descSpans) == 0 {
+		return nil, errors.AssertionFailedf("partition does not include the descriptor spans of the source tables")
+	}
+	frontier, err := span.MakeFrontierAt(startTime, descSpans...)
+	if err != nil {
+		return nil, err
+	}
+	for _, resolvedSpan := range checkpoint {
+		if _, err := frontier.Forward(resolvedSpan.Span, resolvedSpan.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+	return &schemaChangeBarrier{srcDescs: srcDescs, frontier: frontier}, nil
+}
+
+// decodeDescriptorKey returns the ID of the descriptor stored under a
+// system.descriptor key of the source cluster.
+func decodeDescriptorKey(key roachpb.Key) (descpb.ID, bool) {
+	_, tenantID, err := keys.DecodeTenantPrefix(key)
+	if err != nil {
+		return 0, false
+	}
+	codec := keys.MakeSQLCodec(tenantID)
+	_, tableID, err := codec.DecodeTablePrefix(key)
+	if err != nil || tableID != keys.DescriptorTableID {
+		return 0, false
+	}
+	id, err := codec.DecodeDescMetadataID(key)
+	if err != nil {
+		return 0, false
+	}
+	return descpb.ID(id), true
+}
+
+// admit consumes the descriptor KVs among the given KVs and returns the rows
+// that can be applied now. The other rows are held until a later checkpoint.
+func (b *schemaChangeBarrier) admit(
+	kvs []streampb.StreamEvent_KV,
+) ([]streampb.StreamEvent_KV, error) {
+	ready := kvs[:0:0]
+	for _, kv := range kvs {
+		if id, ok := decodeDescriptorKey(kv.KeyValue.Key); ok {
+			if err := b.observeDescriptor(id, kv.KeyValue.Value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if b.canApply(kv.KeyValue.Value.Timestamp) {
+			ready = append(ready, kv)
+		} else {
+			b.held = append(b.held, kv)
+		}
+	}
+	return ready, nil
+}
+
+func (b *schemaChangeBarrier) canApply(ts hlc.Timestamp) bool {
+	return ts.LessEq(b.frontier.Frontier()) && (b.change == nil || ts.Less(b.change.Timestamp))
+}
+
+func (b *schemaChangeBarrier) observeDescriptor(id descpb.ID, value roachpb.Value) error {
+	prev, ok := b.srcDescs[id]
+	if !ok {
+		return nil
+	}
+	ts := value.Timestamp
+	if b.change != nil && !ts.Less(b.change.Timestamp) {
+		return nil
+	}
+	var next catalog.TableDescriptor
+	if value.IsPresent() {
+		builder, err := descbuilder.FromSerializedValue(&value)
+		if err != nil {
+			return errors.Wrapf(err, "decoding descriptor %d", id)
+		}
+		desc, ok := builder.BuildImmutable().(catalog.TableDescriptor)
+		if !ok {
+			return errors.AssertionFailedf("descriptor %d is not a table", id)
+		}
+		if desc.GetVersion() <= prev.GetVersion() {
+			return nil
+		}
+		next = desc
+	} else {
+		// The descriptor was deleted, which only happens after the table was
+		// dropped.
+		dropped := prev.NewBuilder().BuildCreatedMutable().(*tabledesc.Mutable)
+		dropped.State = descpb.DescriptorState_DROP
+		next = dropped.ImmutableCopy().(catalog.TableDescriptor)
+	}
+	if diff := diffSourceSchema(prev, next); diff.empty() {
+		return nil
+	}
+	b.change = &jobspb.LogicalReplicationSchemaChange{
+		PreviousDescriptor: *prev.TableDesc(),
+		Descriptor:         *next.TableDesc(),
+		Timestamp:          ts,
+	}
+	return nil
+}
+
+// checkpoint forwards the descriptor spans with the given resolved spans. It
+// returns the held rows that can now be applied, and the resolved spans capped
+// so that they do not cover rows that are still held.
+func (b *schemaChangeBarrier) checkpoint(
+	resolvedSpans []jobspb.ResolvedSpan,
+) ([]streampb.StreamEvent_KV, []jobspb.ResolvedSpan, error) {
+	for _, sp := range resolvedSpans {
+		if _, err := b.frontier.Forward(sp.Span, sp.Timestamp); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var ready []streampb.StreamEvent_KV
+	held := b.held[:0]
+	for _, kv := range b.held {
+		if b.canApply(kv.KeyValue.Value.Timestamp) {
+			ready = append(ready, kv)
+		} else {
+			held = append(held, kv)
+		}
+	}
+	b.held = held
+
+	limit := b.frontier.Frontier()
+	if b.change != nil && b.change.Timestamp.Prev().Less(limit) {
+		limit = b.change.Timestamp.Prev()
+	}
+	capped := make([]jobspb.ResolvedSpan, len(resolvedSpans))
+	for i, sp := range resolvedSpans {
+		capped[i] = sp
+		if limit.Less(sp.Timestamp) {
+			capped[i].Timestamp = limit
+		}
+	}
+	return ready, capped, nil
+}
+
+// maybeReport returns the earliest schema change once no earlier schema change
+// can arrive. It returns it only once.
+func (b *schemaChangeBarrier) maybeReport() *jobspb.LogicalReplicationSchemaChange {
+	if b.change == nil || b.reported || b.frontier.Frontier().Less(b.change.Timestamp) {
+		return nil
+	}
+	b.reported = true
+	return b.change
+}
+
+// release releases the resources of the barrier.
+func (b *schemaChangeBarrier) release() {
+	b.frontier.Release()
+}
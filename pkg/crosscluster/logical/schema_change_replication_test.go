@@ -0,0 +1,229 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/replicationtestutils"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSourceSchema(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	getDesc := func() catalog.TableDescriptor {
+		return desctestutils.TestingGetPublicTableDescriptor(kvDB, s.Codec(), "defaultdb", "tab")
+	}
+	dst := tree.MakeTableNameWithSchema("dst_db", "public", "dst")
+
+	runner.Exec(t, `CREATE TABLE tab (id INT PRIMARY KEY, a INT, b STRING, INDEX b_idx (b))`)
+	prev := getDesc()
+
+	// A change to a table that does not affect replication.
+	runner.Exec(t, `COMMENT ON TABLE tab IS 'comment'`)
+	diff := diffSourceSchema(prev, getDesc())
+	require.True(t, diff.empty())
+
+	runner.Exec(t, `ALTER TABLE tab ADD COLUMN c INT NOT NULL DEFAULT 7`)
+	runner.Exec(t, `CREATE UNIQUE INDEX a_idx ON tab (a DESC) STORING (c) WHERE a > 0`)
+	runner.Exec(t, `ALTER TABLE tab ADD CONSTRAINT a_check CHECK (a < 100)`)
+	runner.Exec(t, `DROP INDEX tab@b_idx`)
+	runner.Exec(t, `ALTER TABLE tab DROP COLUMN b`)
+	next := getDesc()
+
+	diff = diffSourceSchema(prev, next)
+	require.False(t, diff.empty())
+	require.Empty(t, diff.unsupported)
+	require.True(t, diff.newPrimaryIndex)
+	require.Equal(t, []string{
+		`ALTER TABLE dst_db.public.dst ADD COLUMN IF NOT EXISTS c INT8 NOT NULL DEFAULT (7:::INT8)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS a_idx ON dst_db.public.dst (a DESC) STORING (c) WHERE a > 0:::INT8`,
+		`ALTER TABLE dst_db.public.dst ADD CONSTRAINT IF NOT EXISTS a_check CHECK (a < 100:::INT8)`,
+		`DROP INDEX IF EXISTS dst_db.public.dst@b_idx`,
+		`ALTER TABLE dst_db.public.dst DROP COLUMN IF EXISTS b`,
+	}, diff.statements(&dst))
+
+	prev = next
+	runner.Exec(t, `ALTER TABLE tab RENAME COLUMN a TO a2`)
+	runner.Exec(t, `ALTER TABLE tab ADD COLUMN d INT AS (c + 1) STORED`)
+	runner.Exec(t, `ALTER TABLE tab ALTER PRIMARY KEY USING COLUMNS (id, c)`)
+	diff = diffSourceSchema(prev, getDesc())
+	require.ElementsMatch(t, []string{
+		`column "a" was renamed to "a2"`,
+		`column "d" was added: computed columns are not supported`,
+		`primary key was changed`,
+	}, diff.unsupported)
+
+	// Descriptors reference user-defined types and functions by the IDs of the
+	// source descriptors, so expressions using them cannot be copied to the
+	// destination table.
+	runner.Exec(t, `CREATE TYPE color AS ENUM ('red', 'green')`)
+	runner.Exec(t, `CREATE FUNCTION f(x INT) RETURNS INT LANGUAGE SQL AS 'SELECT x'`)
+	runner.Exec(t, `ALTER TABLE tab ADD COLUMN hue color`)
+	prev = getDesc()
+	runner.Exec(t, `ALTER TABLE tab ADD COLUMN e INT DEFAULT f(1)`)
+	runner.Exec(t, `CREATE INDEX c_idx ON tab (c) WHERE hue = 'red'`)
+	runner.Exec(t, `ALTER TABLE tab ADD CONSTRAINT c_check CHECK (f(c) < 100)`)
+	diff = diffSourceSchema(prev, getDesc())
+	require.ElementsMatch(t, []string{
+		`column "e" was added: expressions using user-defined functions are not supported`,
+		`index "c_idx" was added: expressions using user-defined types are not supported`,
+		`constraint "c_check" was added: expressions using user-defined functions are not supported`,
+	}, diff.unsupported)
+}
+
+func TestSchemaChangeBarrier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	runner.Exec(t, `CREATE TABLE tab (id INT PRIMARY KEY, a INT)`)
+	prev := desctestutils.TestingGetPublicTableDescriptor(kvDB, s.Codec(), "defaultdb", "tab")
+	runner.Exec(t, `ALTER TABLE tab ADD COLUMN b INT`)
+	next := desctestutils.TestingGetPublicTableDescriptor(kvDB, s.Codec(), "defaultdb", "tab")
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	tableSpan := prev.PrimaryIndexSpan(s.Codec())
+	descKey := catalogkeys.MakeDescMetadataKey(s.Codec(), prev.GetID())
+	descSpan := roachpb.Span{Key: descKey, EndKey: descKey.PrefixEnd()}
+	row := func(wallTime int64) streampb.StreamEvent_KV {
+		return streampb.StreamEvent_KV{KeyValue: roachpb.KeyValue{
+			Key:   tableSpan.Key,
+			Value: roachpb.Value{Timestamp: ts(wallTime)},
+		}}
+	}
+	timestamps := func(kvs []streampb.StreamEvent_KV) []int64 {
+		var res []int64
+		for _, kv := range kvs {
+			res = append(res, kv.KeyValue.Value.Timestamp.WallTime)
+		}
+		return res
+	}
+
+	barrier, err := newSchemaChangeBarrier([]roachpb.Span{tableSpan, descSpan}, ts(10), nil,
+		map[descpb.ID]catalog.TableDescriptor{prev.GetID(): prev})
+	require.NoError(t, err)
+	defer barrier.release()
+
+	// Rows that precede the resolved timestamp of the descriptor span are
+	// applied right away.
+	ready, err := barrier.admit([]streampb.StreamEvent_KV{row(5), row(15)})
+	require.NoError(t, err)
+	require.Equal(t, []int64{5}, timestamps(ready))
+
+	ready, resolved, err := barrier.checkpoint([]jobspb.ResolvedSpan{
+		{Span: tableSpan, Timestamp: ts(30)},
+		{Span: descSpan, Timestamp: ts(20)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{15}, timestamps(ready))
+	require.Equal(t, ts(20), resolved[0].Timestamp)
+	require.Equal(t, ts(20), resolved[1].Timestamp)
+
+	// A schema change at 25 holds back all later rows.
+	descValue := roachpb.Value{}
+	require.NoError(t, descValue.SetProto(next.DescriptorProto()))
+	descValue.Timestamp = ts(25)
+	ready, err = barrier.admit([]streampb.StreamEvent_KV{
+		{KeyValue: roachpb.KeyValue{Key: descKey, Value: descValue}},
+		row(24),
+		row(26),
+	})
+	require.NoError(t, err)
+	require.Empty(t, ready)
+	require.Nil(t, barrier.maybeReport())
+
+	ready, resolved, err = barrier.checkpoint([]jobspb.ResolvedSpan{
+		{Span: tableSpan, Timestamp: ts(40)},
+		{Span: descSpan, Timestamp: ts(40)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{24}, timestamps(ready))
+	require.Equal(t, ts(25).Prev(), resolved[0].Timestamp)
+	require.Equal(t, ts(25).Prev(), resolved[1].Timestamp)
+
+	change := barrier.maybeReport()
+	require.NotNil(t, change)
+	require.Equal(t, ts(25), change.Timestamp)
+	require.Equal(t, next.GetVersion(), change.Descriptor.Version)
+	require.Nil(t, barrier.maybeReport())
+}
+
+func TestLogicalReplicationReplicatesSchemaChanges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	skip.UnderDeadlock(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	server, s, dbA, dbB := setupLogicalTestServer(t, ctx, testClusterBaseClusterArgs, 1)
+	defer server.Stopper().Stop(ctx)
+	dbBURL := replicationtestutils.GetExternalConnectionURI(t, s, s, serverutils.DBName("b"))
+
+	dbA.Exec(t, `CREATE TABLE tab (pk INT PRIMARY KEY, payload STRING)`)
+	dbB.Exec(t, `CREATE TABLE tab (pk INT PRIMARY KEY, payload STRING)`)
+	dbB.Exec(t, `INSERT INTO tab VALUES (1, 'hello')`)
+
+	const stmt = `CREATE LOGICAL REPLICATION STREAM FROM TABLE tab ON $1 INTO TABLE tab WITH `
+	dbA.ExpectErr(t, `unknown schema_changes option "sometimes"`,
+		stmt+`SCHEMA_CHANGES = 'sometimes'`, dbBURL.String())
+	dbA.ExpectErr(t, `SCHEMA_CHANGES = 'replicate' cannot be used with COLUMN or EXCLUDE COLUMN`,
+		stmt+`SCHEMA_CHANGES = 'replicate', EXCLUDE COLUMN payload FOR TABLE tab`, dbBURL.String())
+
+	var jobID jobspb.JobID
+	dbA.QueryRow(t, stmt+`SCHEMA_CHANGES = 'replicate'`, dbBURL.String()).Scan(&jobID)
+	WaitUntilReplicatedTime(t, s.Clock().Now(), dbA, jobID)
+
+	dbB.Exec(t, `ALTER TABLE tab ADD COLUMN extra INT NOT NULL DEFAULT 7`)
+	dbB.Exec(t, `INSERT INTO tab VALUES (2, 'world', 8)`)
+	dbB.Exec(t, `CREATE INDEX payload_idx ON tab (payload)`)
+
+	dbA.CheckQueryResultsRetry(t, `SELECT pk, payload, extra FROM tab ORDER BY pk`, [][]string{
+		{"1", "hello", "7"},
+		{"2", "world", "8"},
+	})
+	dbA.CheckQueryResultsRetry(t,
+		`SELECT DISTINCT index_name FROM [SHOW INDEXES FROM tab] WHERE index_name = 'payload_idx'`,
+		[][]string{{"payload_idx"}})
+
+	// The destination of a unidirectional stream rejects the schema changes
+	// the job replays, since they would not reach the source.
+	dbA.ExpectErr(t, `this schema change is disallowed on table tab`, `ALTER TABLE tab ADD COLUMN local INT NOT NULL DEFAULT 1`)
+	dbA.ExpectErr(t, `this schema change is disallowed on table tab`, `CREATE UNIQUE INDEX local_idx ON tab (payload)`)
+
+	// Schema changes that are not replicated are still rejected on both
+	// tables.
+	dbB.ExpectErr(t, `this schema change is disallowed on table tab`, `ALTER TABLE tab RENAME COLUMN payload TO body`)
+	dbA.ExpectErr(t, `this schema change is disallowed on table tab`, `ALTER TABLE tab RENAME COLUMN payload TO body`)
+}
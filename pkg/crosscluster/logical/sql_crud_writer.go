@@ -46,13 +46,9 @@ func newCrudSqlWriter(
 	procConfigByDestID map[descpb.ID]sqlProcessorTableConfig,
 	jobID jobspb.JobID,
 ) (BatchHandler, error) {
-	decoder, err := newEventDecoder(ctx, cfg.DB, evalCtx.Settings, procConfigByDestID)
-	if err != nil {
-		return nil, err
-	}
-
 	handlers := make(map[descpb.ID]*tableHandler)
-	for dstDescID := range procConfigByDestID {
+	mappers := make(map[descpb.ID]*columnMapper)
+	for dstDescID, tc := range procConfigByDestID {
 		handler, err := newTableHandler(
 			ctx,
 			dstDescID,
@@ -62,11 +58,19 @@ func newCrudSqlWriter(
 			jobID,
 			cfg.LeaseManager.(*lease.Manager),
 			evalCtx.Settings,
+			evalCtx,
+			tc,
 		)
 		if err != nil {
 			return nil, err
 		}
 		handlers[dstDescID] = handler
+		mappers[dstDescID] = handler.mapper
+	}
+
+	decoder, err := newEventDecoder(ctx, evalCtx.Settings, procConfigByDestID, mappers)
+	if err != nil {
+		return nil, err
 	}
 
 	return &sqlCrudWriter{
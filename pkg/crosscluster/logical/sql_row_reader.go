@@ -41,9 +41,10 @@ type priorRow struct {
 }
 
 func newSQLRowReader(
-	table catalog.TableDescriptor, sessionOverride sessiondata.InternalExecutorOverride,
+	table catalog.TableDescriptor,
+	cols []columnSchema,
+	sessionOverride sessiondata.InternalExecutorOverride,
 ) (*sqlRowReader, error) {
-	cols := getColumnSchema(table)
 	keyColumns := make([]int, 0, len(cols))
 	for i, col := range cols {
 		if col.isPrimaryKey {
@@ -51,7 +52,7 @@ func newSQLRowReader(
 		}
 	}
 
-	selectStatement, err := newBulkSelectStatement(table)
+	selectStatement, err := newBulkSelectStatement(table, cols)
 	if err != nil {
 		return nil, err
 	}
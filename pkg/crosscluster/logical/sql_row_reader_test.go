@@ -53,12 +53,12 @@ func TestSQLRowReader(t *testing.T) {
 
 	// Create sqlRowReader for source table
 	srcDesc := desctestutils.TestingGetPublicTableDescriptor(s.DB(), s.Codec(), "a", "tab")
-	srcReader, err := newSQLRowReader(srcDesc, sessiondata.InternalExecutorOverride{})
+	srcReader, err := newSQLRowReader(srcDesc, getColumnSchema(srcDesc), sessiondata.InternalExecutorOverride{})
 	require.NoError(t, err)
 
 	// Create sqlRowReader for destination table
 	dstDesc := desctestutils.TestingGetPublicTableDescriptor(s.DB(), s.Codec(), "b", "tab")
-	dstReader, err := newSQLRowReader(dstDesc, sessiondata.InternalExecutorOverride{})
+	dstReader, err := newSQLRowReader(dstDesc, getColumnSchema(dstDesc), sessiondata.InternalExecutorOverride{})
 	require.NoError(t, err)
 
 	// Create test rows to look up
@@ -124,9 +124,10 @@ func (s *sqlRowWriter) UpdateRow(
 }
 
 func newSQLRowWriter(
-	table catalog.TableDescriptor, sessionOverride sessiondata.InternalExecutorOverride,
+	table catalog.TableDescriptor,
+	columnsToDecode []columnSchema,
+	sessionOverride sessiondata.InternalExecutorOverride,
 ) (*sqlRowWriter, error) {
-	columnsToDecode := getColumnSchema(table)
 	columns := make([]string, len(columnsToDecode))
 	for i, col := range columnsToDecode {
 		columns[i] = col.column.GetName()
@@ -139,17 +140,17 @@ func newSQLRowWriter(
 	// maintain prepared statements across different instances of the internal
 	// executor.
 
-	insert, err := newInsertStatement(table)
+	insert, err := newInsertStatement(table, columnsToDecode)
 	if err != nil {
 		return nil, err
 	}
 
-	update, err := newUpdateStatement(table)
+	update, err := newUpdateStatement(table, columnsToDecode)
 	if err != nil {
 		return nil, err
 	}
 
-	delete, err := newDeleteStatement(table)
+	delete, err := newDeleteStatement(table, columnsToDecode)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ func TestSQLRowWriter(t *testing.T) {
 
 	// Create a row writer
 	desc := cdctest.GetHydratedTableDescriptor(t, s.ApplicationLayer().ExecutorConfig(), "test_table")
-	writer, err := newSQLRowWriter(desc, sessiondata.InternalExecutorOverride{})
+	writer, err := newSQLRowWriter(desc, getColumnSchema(desc), sessiondata.InternalExecutorOverride{})
 	require.NoError(t, err)
 
 	// Test InsertRow
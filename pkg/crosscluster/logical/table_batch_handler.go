@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/errors"
@@ -29,6 +30,11 @@ type tableHandler struct {
 	sqlWriter        *sqlRowWriter
 	db               descs.DB
 	tombstoneUpdater *tombstoneUpdater
+	// mapper computes the replicated columns of the table from the source
+	// table's columns.
+	mapper *columnMapper
+	// merger is set if the table has column merge rules.
+	merger *columnMerger
 }
 
 type tableBatchStats struct {
@@ -85,16 +91,29 @@ func newTableHandler(
 	jobID jobspb.JobID,
 	leaseMgr *lease.Manager,
 	settings *cluster.Settings,
+	evalCtx *eval.Context,
+	tc sqlProcessorTableConfig,
 ) (*tableHandler, error) {
 	var table catalog.TableDescriptor
+	var dbName string
 
 	// NOTE: we don't hold a lease on the table descriptor, but validation
-	// prevents users from changing the set of columns or the primary key of an
-	// LDR replicated table.
+	// prevents users from changing the primary key of an LDR replicated table
+	// and only allows adding nullable columns without a default value. Columns
+	// added after the handler is created are not replicated, so they are left
+	// NULL by inserts and keep their local value on updates.
 	err := db.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
 		var err error
 		table, err = txn.Descriptors().GetLeasedImmutableTableByID(ctx, txn.KV(), tableID)
-		return err
+		if err != nil || len(tc.mergeRules) == 0 {
+			return err
+		}
+		dbDesc, err := txn.Descriptors().ByIDWithLeased(txn.KV()).WithoutNonPublic().Get().Database(ctx, table.GetParentID())
+		if err != nil {
+			return err
+		}
+		dbName = dbDesc.GetName()
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -105,29 +124,52 @@ func newTableHandler(
 	sessionOverride := ieOverrideBase
 	sessionOverride.ApplicationName = fmt.Sprintf("%s-logical-replication-%d", sd.ApplicationName, jobID)
 
-	reader, err := newSQLRowReader(table, sessionOverride)
+	mapper, err := newColumnMapper(ctx, evalCtx, tc.srcDesc, table, tc.columnMappings)
 	if err != nil {
 		return nil, err
 	}
 
-	writer, err := newSQLRowWriter(table, sessionOverride)
+	reader, err := newSQLRowReader(table, mapper.columns, sessionOverride)
 	if err != nil {
 		return nil, err
 	}
 
+	writer, err := newSQLRowWriter(table, mapper.columns, sessionOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	var merger *columnMerger
+	if len(tc.mergeRules) != 0 {
+		appliedTable := dstTableMetadata{database: dbName, tableID: tableID}.toMergeAppliedTableName()
+		merger, err = newColumnMerger(
+			table, mapper.columns, tc.mergeRules, evalCtx, appliedTable, jobID, sessionOverride,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tombstoneUpdater := newTombstoneUpdater(codec, db.KV(), leaseMgr, tableID, sd, settings)
+	tombstoneUpdater.columns = mapper.columnIDs()
 
 	return &tableHandler{
 		sqlReader:        reader,
 		sqlWriter:        writer,
 		db:               db,
 		tombstoneUpdater: tombstoneUpdater,
+		mapper:           mapper,
+		merger:           merger,
 	}, nil
 }
 
 func (t *tableHandler) handleDecodedBatch(
 	ctx context.Context, batch []decodedEvent,
 ) (tableBatchStats, error) {
+	if t.merger != nil {
+		return t.mergeBatch(ctx, batch)
+	}
+
 	stats, err := t.attemptBatch(ctx, batch)
 	if err == nil {
 		return stats, nil
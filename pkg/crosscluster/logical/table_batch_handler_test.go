@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -29,10 +30,29 @@ import (
 )
 
 func newCrudBatchHandler(
-	t *testing.T, s serverutils.ApplicationLayerInterface, tableName string,
+	t *testing.T,
+	s serverutils.ApplicationLayerInterface,
+	tableName string,
+	mergeRules ...jobspb.LogicalReplicationDetails_ColumnMergeRule,
 ) (BatchHandler, catalog.TableDescriptor) {
-	ctx := context.Background()
 	desc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), tree.Name(tableName))
+	if len(mergeRules) != 0 {
+		require.NoError(t, createMergeAppliedTable(context.Background(), s.InternalDB().(isql.DB).Executor(),
+			dstTableMetadata{database: "defaultdb", tableID: desc.GetID()}))
+	}
+	handler := newCrudBatchHandlerForConfig(t, s, desc.GetID(), sqlProcessorTableConfig{
+		srcDesc:    desc,
+		mergeRules: mergeRules,
+	})
+	return handler, desc
+}
+
+// newCrudBatchHandlerForConfig creates a crud writer which replicates into the
+// table with the given ID.
+func newCrudBatchHandlerForConfig(
+	t *testing.T, s serverutils.ApplicationLayerInterface, dstID descpb.ID, tc sqlProcessorTableConfig,
+) BatchHandler {
+	ctx := context.Background()
 	sd := sql.NewInternalSessionData(ctx, s.ClusterSettings(), "" /* opName */)
 	handler, err := newCrudSqlWriter(
 		ctx,
@@ -49,15 +69,11 @@ func newCrudBatchHandler(
 		},
 		sd,
 		jobspb.LogicalReplicationDetails_DiscardNothing,
-		map[descpb.ID]sqlProcessorTableConfig{
-			desc.GetID(): {
-				srcDesc: desc,
-			},
-		},
+		map[descpb.ID]sqlProcessorTableConfig{dstID: tc},
 		0, // jobID
 	)
 	require.NoError(t, err)
-	return handler, desc
+	return handler
 }
 
 func TestBatchHandlerFastPath(t *testing.T) {
@@ -7,6 +7,7 @@ package logical
 
 import (
 	"context"
+	"slices"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -32,6 +33,10 @@ type tombstoneUpdater struct {
 	settings *cluster.Settings
 	descID   descpb.ID
 
+	// columns are the IDs of the columns of the rows passed to updateTombstone.
+	// If nil, rows are expected to contain every writeable column of the table.
+	columns []descpb.ColumnID
+
 	// leased holds fields whose lifetimes are tied to a leased descriptor.
 	leased struct {
 		// descriptor is a leased descriptor. Callers should use getDeleter to
@@ -40,9 +45,14 @@ type tombstoneUpdater struct {
 		// deleter is a row.Deleter that uses the leased descriptor. Callers should
 		// use getDeleter to ensure the lease is valid for the current transaction.
 		deleter row.Deleter
+		// ordinals maps each column of the deleter to the index of the column in
+		// the rows passed to updateTombstone, or -1 if the row does not contain
+		// the column. It is only set if columns is set.
+		ordinals []int
 	}
 
-	scratch []tree.Datum
+	scratch  []tree.Datum
+	expanded []tree.Datum
 }
 
 func (c *tombstoneUpdater) ReleaseLeases(ctx context.Context) {
@@ -52,6 +62,7 @@ func (c *tombstoneUpdater) ReleaseLeases(ctx context.Context) {
 		c.leased.descriptor.Release(ctx)
 		c.leased.descriptor = nil
 		c.leased.deleter = row.Deleter{}
+		c.leased.ordinals = nil
 	}
 }
 
@@ -135,6 +146,18 @@ func (tu *tombstoneUpdater) addToBatch(
 		return err
 	}
 
+	if tu.leased.ordinals != nil {
+		tu.expanded = tu.expanded[:0]
+		for _, ord := range tu.leased.ordinals {
+			if ord < 0 {
+				tu.expanded = append(tu.expanded, tree.DNull)
+				continue
+			}
+			tu.expanded = append(tu.expanded, afterRow[ord])
+		}
+		afterRow = tu.expanded
+	}
+
 	var ph row.PartialIndexUpdateHelper
 	var vh row.VectorIndexUpdateHelper
 
@@ -170,6 +193,13 @@ func (tu *tombstoneUpdater) getDeleter(ctx context.Context, txn *kv.Txn) (row.De
 		}
 
 		tu.leased.deleter = row.MakeDeleter(tu.codec, tu.leased.descriptor.Underlying().(catalog.TableDescriptor), nil /* lockedIndexes */, cols, tu.sd, &tu.settings.SV, nil /* metrics */)
+
+		if tu.columns != nil {
+			tu.leased.ordinals = make([]int, len(cols))
+			for i, col := range cols {
+				tu.leased.ordinals[i] = slices.Index(tu.columns, col.GetID())
+			}
+		}
 	}
 	if err := txn.UpdateDeadline(ctx, tu.leased.descriptor.Expiration(ctx)); err != nil {
 		return row.Deleter{}, err
@@ -0,0 +1,246 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package physical
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/crosscluster"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/replicationutils"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// maxRecordedFingerprintMismatches is the maximum number of fingerprint
+// mismatches kept in the job details. Older mismatches are dropped first.
+const maxRecordedFingerprintMismatches = 10
+
+// fingerprintVerifier periodically verifies that the data ingested by a
+// stream ingestion job matches the data on the source cluster. As of the
+// current replicated time, it fingerprints each span of the stream on the
+// source cluster, via the producer, and the corresponding span of the
+// destination tenant, and records any span whose fingerprints differ.
+//
+// Fingerprinting reads the full tenant keyspace on both clusters, so the
+// ExportRequests are sent one span at a time at a low admission priority so
+// that verification yields to foreground traffic and to ingestion itself.
+type fingerprintVerifier struct {
+	job       *jobs.Job
+	db        *kv.DB
+	idb       isql.DB
+	settings  *cluster.Settings
+	metrics   *Metrics
+	streamID  streampb.StreamID
+	stopperCh chan struct{}
+
+	srcTenantID roachpb.TenantID
+	dstTenantID roachpb.TenantID
+	// sourceSpans are the spans of the stream in the source tenant's keyspace.
+	sourceSpans roachpb.Spans
+
+	// connect opens the client used to fingerprint the source cluster. The
+	// client is only opened once verification is enabled.
+	connect func(context.Context) (streamclient.Client, error)
+	client  streamclient.Client
+}
+
+func makeFingerprintVerifier(
+	execCfg *sql.ExecutorConfig,
+	ingestionJob *jobs.Job,
+	connect func(context.Context) (streamclient.Client, error),
+	srcTenantID roachpb.TenantID,
+	sourceSpans roachpb.Spans,
+	stopperCh chan struct{},
+) *fingerprintVerifier {
+	details := ingestionJob.Details().(jobspb.StreamIngestionDetails)
+	return &fingerprintVerifier{
+		job:         ingestionJob,
+		db:          execCfg.DB,
+		idb:         execCfg.InternalDB,
+		settings:    execCfg.Settings,
+		metrics:     execCfg.JobRegistry.MetricsStruct().StreamIngest.(*Metrics),
+		connect:     connect,
+		streamID:    streampb.StreamID(details.StreamID),
+		stopperCh:   stopperCh,
+		srcTenantID: srcTenantID,
+		dstTenantID: details.DestinationTenantID,
+		sourceSpans: sourceSpans,
+	}
+}
+
+// run verifies the replicated data every
+// physical_replication.consumer.fingerprint_verification_interval until the
+// stopper channel is closed. Verification failures are logged and retried at
+// the next interval rather than failing the job.
+func (v *fingerprintVerifier) run(ctx context.Context) error {
+	defer func() {
+		if v.client != nil {
+			closeAndLog(ctx, v.client)
+		}
+	}()
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		interval := crosscluster.FingerprintVerificationInterval.Get(&v.settings.SV)
+		if interval == 0 {
+			// Re-check the setting periodically while verification is disabled.
+			interval = time.Minute
+		}
+		timer.Reset(interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-v.stopperCh:
+			return nil
+		case <-timer.C:
+			timer.Read = true
+		}
+		if crosscluster.FingerprintVerificationInterval.Get(&v.settings.SV) == 0 {
+			continue
+		}
+		if err := v.verifyOnce(ctx); err != nil {
+			log.Warningf(ctx, "fingerprint verification of replication stream %d failed: %v", v.streamID, err)
+		}
+	}
+}
+
+// verifyOnce verifies the replicated data as of the current replicated time
+// and records the result in the job details.
+func (v *fingerprintVerifier) verifyOnce(ctx context.Context) error {
+	progress, err := replicationutils.LoadIngestionProgress(ctx, v.idb, v.job.ID())
+	if err != nil {
+		return err
+	}
+	if progress == nil || progress.ReplicatedTime.IsEmpty() {
+		// Nothing to verify until the initial scan completes.
+		return nil
+	}
+	asOf := progress.ReplicatedTime
+
+	if v.client == nil {
+		v.client, err = v.connect(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof(ctx, "verifying fingerprints of %d spans as of %s", len(v.sourceSpans), asOf)
+	mismatches, err := v.verify(ctx, asOf)
+	if err != nil {
+		return err
+	}
+	return v.recordResult(ctx, asOf, mismatches)
+}
+
+// verify fingerprints each span of the stream on both clusters as of the given
+// time and returns the spans whose fingerprints differ.
+func (v *fingerprintVerifier) verify(
+	ctx context.Context, asOf hlc.Timestamp,
+) ([]jobspb.StreamIngestionDetails_FingerprintMismatch, error) {
+	var mismatches []jobspb.StreamIngestionDetails_FingerprintMismatch
+	for _, sourceSpan := range v.sourceSpans {
+		destSpan, err := rekeyTenantSpan(sourceSpan, v.srcTenantID, v.dstTenantID)
+		if err != nil {
+			return nil, err
+		}
+		sourceFingerprints, err := v.client.FingerprintSpans(ctx, v.streamID, []roachpb.Span{sourceSpan}, asOf)
+		if err != nil {
+			return nil, err
+		}
+		destFingerprint, err := sql.FingerprintSpanAt(ctx, v.db, v.settings, destSpan, asOf, admissionpb.BulkLowPri)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fingerprinting span %s", destSpan)
+		}
+		if sourceFingerprints[0] != destFingerprint {
+			mismatches = append(mismatches, jobspb.StreamIngestionDetails_FingerprintMismatch{
+				SourceSpan:             sourceSpan,
+				AsOf:                   asOf,
+				SourceFingerprint:      sourceFingerprints[0],
+				DestinationFingerprint: destFingerprint,
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+// recordResult persists the result of a verification to the job details,
+// updates the metrics and emits an event for every mismatch.
+func (v *fingerprintVerifier) recordResult(
+	ctx context.Context,
+	asOf hlc.Timestamp,
+	mismatches []jobspb.StreamIngestionDetails_FingerprintMismatch,
+) error {
+	if err := v.job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		details := md.Payload.GetStreamIngestion()
+		details.LastFingerprintVerifiedTime = asOf
+		details.FingerprintMismatches = append(details.FingerprintMismatches, mismatches...)
+		if excess := len(details.FingerprintMismatches) - maxRecordedFingerprintMismatches; excess > 0 {
+			details.FingerprintMismatches = details.FingerprintMismatches[excess:]
+		}
+		ju.UpdatePayload(md.Payload)
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "failed to record fingerprint verification")
+	}
+
+	v.metrics.FingerprintVerifications.Inc(1)
+	v.metrics.FingerprintMismatches.Inc(int64(len(mismatches)))
+	for _, m := range mismatches {
+		log.StructuredEvent(ctx, severity.ERROR, &eventpb.ReplicationFingerprintMismatch{
+			JobID:                  int64(v.job.ID()),
+			SourceSpan:             m.SourceSpan.String(),
+			AsOf:                   m.AsOf.String(),
+			SourceFingerprint:      m.SourceFingerprint,
+			DestinationFingerprint: m.DestinationFingerprint,
+		})
+	}
+	return nil
+}
+
+// rekeyTenantSpan rewrites a span of the source tenant's keyspace into the
+// corresponding span of the destination tenant's keyspace.
+func rekeyTenantSpan(
+	sp roachpb.Span, srcTenantID, dstTenantID roachpb.TenantID,
+) (roachpb.Span, error) {
+	srcPrefix := keys.MakeTenantPrefix(srcTenantID)
+	dstPrefix := keys.MakeTenantPrefix(dstTenantID)
+	rekey := func(key roachpb.Key) (roachpb.Key, error) {
+		switch {
+		case bytes.HasPrefix(key, srcPrefix):
+			return append(dstPrefix.Clone(), key[len(srcPrefix):]...), nil
+		case key.Equal(srcPrefix.PrefixEnd()):
+			return dstPrefix.PrefixEnd(), nil
+		default:
+			return nil, errors.AssertionFailedf("key %s is not in tenant %s", key, srcTenantID)
+		}
+	}
+	start, err := rekey(sp.Key)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	end, err := rekey(sp.EndKey)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	return roachpb.Span{Key: start, EndKey: end}, nil
+}
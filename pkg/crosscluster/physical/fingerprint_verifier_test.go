@@ -0,0 +1,139 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package physical
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRekeyTenantSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	src, dst := roachpb.MustMakeTenantID(10), roachpb.MustMakeTenantID(11)
+	key := func(tenantID roachpb.TenantID, suffix string) roachpb.Key {
+		return append(keys.MakeTenantPrefix(tenantID), suffix...)
+	}
+
+	rekeyed, err := rekeyTenantSpan(keys.MakeTenantSpan(src), src, dst)
+	require.NoError(t, err)
+	require.Equal(t, keys.MakeTenantSpan(dst), rekeyed)
+
+	rekeyed, err = rekeyTenantSpan(roachpb.Span{Key: key(src, "a"), EndKey: key(src, "m")}, src, dst)
+	require.NoError(t, err)
+	require.Equal(t, roachpb.Span{Key: key(dst, "a"), EndKey: key(dst, "m")}, rekeyed)
+
+	_, err = rekeyTenantSpan(roachpb.Span{Key: key(dst, "a"), EndKey: key(dst, "m")}, src, dst)
+	require.ErrorContains(t, err, "is not in tenant 10")
+}
+
+// fingerprintTestClient is a streamclient.Client that fingerprints spans with
+// the given function.
+type fingerprintTestClient struct {
+	streamclient.Client
+	fingerprint func(roachpb.Span) (uint64, error)
+}
+
+func (c *fingerprintTestClient) FingerprintSpans(
+	_ context.Context, _ streampb.StreamID, spans []roachpb.Span, _ hlc.Timestamp,
+) ([]uint64, error) {
+	fingerprints := make([]uint64, 0, len(spans))
+	for _, sp := range spans {
+		fingerprint, err := c.fingerprint(sp)
+		if err != nil {
+			return nil, err
+		}
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	return fingerprints, nil
+}
+
+func TestFingerprintVerifierVerify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, _, kvDB := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+	})
+	defer srv.Stopper().Stop(ctx)
+
+	src, dst := roachpb.MustMakeTenantID(10), roachpb.MustMakeTenantID(11)
+	key := func(tenantID roachpb.TenantID, suffix string) roachpb.Key {
+		return append(keys.MakeTenantPrefix(tenantID), suffix...)
+	}
+	for _, k := range []string{"a", "c", "n", "x"} {
+		require.NoError(t, kvDB.Put(ctx, key(dst, k), k))
+	}
+	asOf := srv.Clock().Now()
+
+	// The source cluster is simulated by fingerprinting the destination span,
+	// so the fingerprints match unless the test client tampers with them.
+	var tamper roachpb.Key
+	client := &fingerprintTestClient{
+		fingerprint: func(sp roachpb.Span) (uint64, error) {
+			destSpan, err := rekeyTenantSpan(sp, src, dst)
+			if err != nil {
+				return 0, err
+			}
+			fingerprint, err := sql.FingerprintSpanAt(ctx, kvDB, srv.ClusterSettings(), destSpan, asOf, admissionpb.BulkLowPri)
+			if err != nil {
+				return 0, err
+			}
+			if sp.ContainsKey(tamper) {
+				fingerprint++
+			}
+			return fingerprint, nil
+		},
+	}
+	sourceSpans := roachpb.Spans{
+		{Key: key(src, ""), EndKey: key(src, "m")},
+		{Key: key(src, "m"), EndKey: keys.MakeTenantSpan(src).EndKey},
+	}
+	v := &fingerprintVerifier{
+		db:          kvDB,
+		settings:    srv.ClusterSettings(),
+		client:      client,
+		streamID:    1,
+		srcTenantID: src,
+		dstTenantID: dst,
+		sourceSpans: sourceSpans,
+	}
+
+	mismatches, err := v.verify(ctx, asOf)
+	require.NoError(t, err)
+	require.Empty(t, mismatches)
+
+	// The destination fingerprints change with the data.
+	before, err := sql.FingerprintSpanAt(ctx, kvDB, srv.ClusterSettings(), keys.MakeTenantSpan(dst), asOf, admissionpb.BulkLowPri)
+	require.NoError(t, err)
+	require.NoError(t, kvDB.Put(ctx, key(dst, "b"), "b"))
+	after, err := sql.FingerprintSpanAt(ctx, kvDB, srv.ClusterSettings(), keys.MakeTenantSpan(dst), srv.Clock().Now(), admissionpb.BulkLowPri)
+	require.NoError(t, err)
+	require.NotEqual(t, before, after)
+
+	tamper = key(src, "x")
+	mismatches, err = v.verify(ctx, asOf)
+	require.NoError(t, err)
+	require.Len(t, mismatches, 1)
+	m := mismatches[0]
+	require.Equal(t, sourceSpans[1], m.SourceSpan)
+	require.Equal(t, asOf, m.AsOf)
+	require.Equal(t, m.DestinationFingerprint+1, m.SourceFingerprint)
+}
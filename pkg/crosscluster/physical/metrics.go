@@ -95,6 +95,18 @@ var (
 		Measurement: "Events",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaFingerprintVerifications = metric.Metadata{
+		Name:        "physical_replication.fingerprint_verifications",
+		Help:        "Total fingerprint verifications of replicated data completed by all replication jobs",
+		Measurement: "Verifications",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaFingerprintMismatches = metric.Metadata{
+		Name:        "physical_replication.fingerprint_mismatches",
+		Help:        "Total spans whose fingerprint did not match the source cluster during fingerprint verification",
+		Measurement: "Spans",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // Metrics are for production monitoring of stream ingestion jobs.
@@ -104,6 +116,8 @@ type Metrics struct {
 	Flushes                    *metric.Counter
 	ResolvedEvents             *metric.Counter
 	ReplanCount                *metric.Counter
+	FingerprintVerifications   *metric.Counter
+	FingerprintMismatches      *metric.Counter
 	FlushHistNanos             metric.IHistogram
 	CommitLatency              metric.IHistogram
 	AdmitLatency               metric.IHistogram
@@ -118,11 +132,13 @@ func (*Metrics) MetricStruct() {}
 // MakeMetrics makes the metrics for stream ingestion job monitoring.
 func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 	m := &Metrics{
-		IngestedEvents:       metric.NewCounter(metaReplicationEventsIngested),
-		IngestedLogicalBytes: metric.NewCounter(metaReplicationIngestedBytes),
-		Flushes:              metric.NewCounter(metaReplicationFlushes),
-		ResolvedEvents:       metric.NewCounter(metaReplicationResolvedEventsIngested),
-		ReplanCount:          metric.NewCounter(metaDistSQLReplanCount),
+		IngestedEvents:           metric.NewCounter(metaReplicationEventsIngested),
+		IngestedLogicalBytes:     metric.NewCounter(metaReplicationIngestedBytes),
+		Flushes:                  metric.NewCounter(metaReplicationFlushes),
+		ResolvedEvents:           metric.NewCounter(metaReplicationResolvedEventsIngested),
+		ReplanCount:              metric.NewCounter(metaDistSQLReplanCount),
+		FingerprintVerifications: metric.NewCounter(metaFingerprintVerifications),
+		FingerprintMismatches:    metric.NewCounter(metaFingerprintMismatches),
 		FlushHistNanos: metric.NewHistogram(metric.HistogramOptions{
 			Metadata:     metaReplicationFlushHistNanos,
 			Duration:     histogramWindow,
@@ -197,11 +197,28 @@ func startDistIngestion(
 		}
 		return ingestor.ingestSpanConfigs(ctx, details.SourceTenantName)
 	}
+	fingerprintVerifierStopper := make(chan struct{})
+	verifyFingerprints := func(ctx context.Context) error {
+		sourceTenantID, err := planner.getSrcTenantID()
+		if err != nil {
+			return err
+		}
+		// The verifier uses its own connection so that long running
+		// fingerprint requests do not block the other users of the client.
+		connect := func(ctx context.Context) (streamclient.Client, error) {
+			return connectToActiveClient(ctx, ingestionJob, execCtx.ExecCfg().InternalDB,
+				streamclient.WithStreamID(streamID))
+		}
+		verifier := makeFingerprintVerifier(execCtx.ExecCfg(), ingestionJob, connect,
+			sourceTenantID, sortSpans(planner.initialTopology.Partitions), fingerprintVerifierStopper)
+		return verifier.run(ctx)
+	}
 	execInitialPlan := func(ctx context.Context) error {
 		defer func() {
 			stopReplanner()
 			close(tracingAggCh)
 			close(spanConfigIngestStopper)
+			close(fingerprintVerifierStopper)
 		}()
 		ctx = logtags.AddTag(ctx, "stream-ingest-distsql", nil)
 
@@ -273,7 +290,8 @@ func startDistIngestion(
 		return err
 	}
 
-	err = ctxgroup.GoAndWait(ctx, execInitialPlan, replanner, tracingAggLoop, streamSpanConfigs)
+	err = ctxgroup.GoAndWait(ctx, execInitialPlan, replanner, tracingAggLoop, streamSpanConfigs,
+		verifyFingerprints)
 	if errors.Is(err, sql.ErrPlanChanged) {
 		execCtx.ExecCfg().JobRegistry.MetricsStruct().StreamIngest.(*Metrics).ReplanCount.Inc(1)
 	}
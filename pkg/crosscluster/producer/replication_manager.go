@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/externalcatalog"
@@ -130,7 +131,7 @@ func (r *replicationStreamManagerImpl) StartReplicationStreamForTables(
 		tableIDs,
 		strings.Join(req.TableNames, ","))
 
-	if err := replicationutils.LockLDRTables(ctx, r.txn, mutableTableDescs, jr.JobID); err != nil {
+	if err := replicationutils.LockLDRTables(ctx, r.txn, mutableTableDescs, jr.JobID, req.ReplicateSchemaChanges, true /* source */); err != nil {
 		return streampb.ReplicationProducerSpec{}, err
 	}
 
@@ -287,12 +288,30 @@ func (r *replicationStreamManagerImpl) PlanLogicalReplication(
 	if err != nil {
 		return nil, err
 	}
+	if req.ReplicateSchemaChanges {
+		// Every partition streams the descriptors of the tables, so that each
+		// consumer processor can order the schema changes of the tables with
+		// respect to the rows it receives.
+		for i := range spec.Partitions {
+			for _, td := range tableDescs {
+				spec.Partitions[i].SourcePartition.Spans = append(spec.Partitions[i].SourcePartition.Spans,
+					descriptorSpan(r.evalCtx.Codec, td.ID))
+			}
+		}
+	}
 	spec.TableDescriptors = tableDescs
 	spec.TableSpans = spans
 	spec.TypeDescriptors = typeDescriptors
 	return spec, nil
 }
 
+// descriptorSpan returns the span of the system.descriptor row of the given
+// descriptor.
+func descriptorSpan(codec keys.SQLCodec, id descpb.ID) roachpb.Span {
+	key := catalogkeys.MakeDescMetadataKey(codec, id)
+	return roachpb.Span{Key: key, EndKey: key.PrefixEnd()}
+}
+
 // HeartbeatReplicationStream implements streaming.ReplicationStreamManager interface.
 func (r *replicationStreamManagerImpl) HeartbeatReplicationStream(
 	ctx context.Context, streamID streampb.StreamID, frontier hlc.Timestamp,
@@ -364,6 +383,19 @@ func (r *replicationStreamManagerImpl) CompleteReplicationStream(
 	return completeReplicationStream(ctx, r.evalCtx, r.txn, streamID, successfulIngestion)
 }
 
+// FingerprintReplicationSpans implements ReplicationStreamManager interface.
+func (r *replicationStreamManagerImpl) FingerprintReplicationSpans(
+	ctx context.Context, req streampb.ReplicationFingerprintRequest,
+) (streampb.ReplicationFingerprintResponse, error) {
+	if err := r.checkLicense(); err != nil {
+		return streampb.ReplicationFingerprintResponse{}, err
+	}
+	if err := r.Authorized("FingerprintReplicationSpans"); err != nil {
+		return streampb.ReplicationFingerprintResponse{}, err
+	}
+	return fingerprintReplicationSpans(ctx, r.evalCtx, r.txn, req)
+}
+
 func (r *replicationStreamManagerImpl) SetupSpanConfigsStream(
 	ctx context.Context, tenantName roachpb.TenantName,
 ) (eval.ValueGenerator, error) {
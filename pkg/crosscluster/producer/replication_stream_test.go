@@ -611,6 +611,68 @@ func TestCompleteStreamReplication(t *testing.T) {
 	}
 }
 
+func TestFingerprintReplicationStreamSpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := replicationtestutils.NewReplicationHelper(t,
+		base.TestServerArgs{
+			Knobs: base.TestingKnobs{
+				JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+			},
+			DefaultTestTenant: base.TestControlsTenantsExplicitly,
+		})
+	defer cleanup()
+	testTenantName := roachpb.TenantName("test-tenant")
+	srcTenant, cleanupTenant := h.CreateTenant(t, serverutils.TestTenantID(), testTenantName)
+	defer cleanupTenant()
+
+	srcTenant.SQL.Exec(t, `CREATE DATABASE d; CREATE TABLE d.t (i INT PRIMARY KEY); INSERT INTO d.t VALUES (1), (2)`)
+
+	replicationProducerSpec := h.StartReplicationStream(t, testTenantName)
+	streamID := replicationProducerSpec.StreamID
+	jobutils.WaitForJobToRun(t, h.SysSQL, jobspb.JobID(streamID))
+
+	fingerprint := func(spans []roachpb.Span, asOf hlc.Timestamp) ([]uint64, error) {
+		rawReq, err := protoutil.Marshal(&streampb.ReplicationFingerprintRequest{
+			StreamID: streamID,
+			Spans:    spans,
+			AsOf:     asOf,
+		})
+		require.NoError(t, err)
+		var rawResp []byte
+		if err := h.SysSQL.DB.QueryRowContext(context.Background(),
+			`SELECT crdb_internal.fingerprint_replication_stream_spans($1)`, rawReq).Scan(&rawResp); err != nil {
+			return nil, err
+		}
+		var resp streampb.ReplicationFingerprintResponse
+		require.NoError(t, protoutil.Unmarshal(rawResp, &resp))
+		return resp.Fingerprints, nil
+	}
+
+	// The fingerprint of the tenant span matches the fingerprint computed with
+	// crdb_internal.fingerprint as of the same time.
+	asOf := h.SysServer.Clock().Now()
+	fingerprints, err := fingerprint([]roachpb.Span{keys.MakeTenantSpan(srcTenant.ID)}, asOf)
+	require.NoError(t, err)
+	require.Len(t, fingerprints, 1)
+	var expected int64
+	h.SysSQL.QueryRow(t, fmt.Sprintf(
+		`SELECT crdb_internal.fingerprint(crdb_internal.tenant_span($1), false) AS OF SYSTEM TIME %s`,
+		asOf.AsOfSystemTime()), srcTenant.ID.ToUint64()).Scan(&expected)
+	require.Equal(t, uint64(expected), fingerprints[0])
+
+	// The fingerprint changes as the data changes.
+	srcTenant.SQL.Exec(t, `INSERT INTO d.t VALUES (3)`)
+	newFingerprints, err := fingerprint([]roachpb.Span{keys.MakeTenantSpan(srcTenant.ID)}, h.SysServer.Clock().Now())
+	require.NoError(t, err)
+	require.NotEqual(t, fingerprints, newFingerprints)
+
+	// Spans outside of the stream cannot be fingerprinted.
+	_, err = fingerprint([]roachpb.Span{keys.MakeTenantSpan(roachpb.MustMakeTenantID(100))}, asOf)
+	require.ErrorContains(t, err, "is not replicated by stream")
+}
+
 func TestStreamDeleteRange(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -276,6 +277,62 @@ func (r *replicationStreamManagerImpl) getPhysicalReplicationStreamSpec(
 	return r.buildReplicationStreamSpec(ctx, evalCtx, details.TenantID, false, details.Spans, true)
 }
 
+// fingerprintReplicationSpans fingerprints the requested spans of the
+// specified physical replication stream as of the requested time.
+func fingerprintReplicationSpans(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	txn isql.Txn,
+	req streampb.ReplicationFingerprintRequest,
+) (streampb.ReplicationFingerprintResponse, error) {
+	jobExecCtx := evalCtx.JobExecContext.(sql.JobExecContext)
+	jobID := jobspb.JobID(req.StreamID)
+	j, err := jobExecCtx.ExecCfg().JobRegistry.LoadJobWithTxn(ctx, jobID, txn)
+	if err != nil {
+		return streampb.ReplicationFingerprintResponse{}, errors.Wrapf(err, "could not load job for replication stream %d", req.StreamID)
+	}
+	details, ok := j.Details().(jobspb.StreamReplicationDetails)
+	if !ok {
+		return streampb.ReplicationFingerprintResponse{}, notAReplicationJobError(jobID)
+	}
+	if j.State() != jobs.StateRunning {
+		return streampb.ReplicationFingerprintResponse{}, jobIsNotRunningError(jobID, j.State(), "fingerprint spans")
+	}
+	if req.AsOf.IsEmpty() {
+		return streampb.ReplicationFingerprintResponse{}, errors.AssertionFailedf("fingerprint request for stream %d has no timestamp", req.StreamID)
+	}
+
+	resp := streampb.ReplicationFingerprintResponse{
+		Fingerprints: make([]uint64, 0, len(req.Spans)),
+	}
+	for _, sp := range req.Spans {
+		if !spansContain(details.Spans, sp) {
+			return streampb.ReplicationFingerprintResponse{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"span %s is not replicated by stream %d", sp, req.StreamID)
+		}
+		// Fingerprinting is background verification work, so it runs at a low
+		// priority to let admission control throttle it behind foreground
+		// traffic.
+		fingerprint, err := sql.FingerprintSpanAt(ctx, jobExecCtx.ExecCfg().DB, evalCtx.Settings,
+			sp, req.AsOf, admissionpb.BulkLowPri)
+		if err != nil {
+			return streampb.ReplicationFingerprintResponse{}, errors.Wrapf(err, "fingerprinting span %s", sp)
+		}
+		resp.Fingerprints = append(resp.Fingerprints, fingerprint)
+	}
+	return resp, nil
+}
+
+// spansContain returns true if sp is contained in one of the given spans.
+func spansContain(spans []roachpb.Span, sp roachpb.Span) bool {
+	for _, s := range spans {
+		if s.Contains(sp) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *replicationStreamManagerImpl) buildReplicationStreamSpec(
 	ctx context.Context,
 	evalCtx *eval.Context,
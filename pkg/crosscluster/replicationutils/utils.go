@@ -294,12 +294,29 @@ func fingerprintClustersByTable(
 		dstFingerprints)
 }
 
+// LockLDRTables records the job as a logical replication job referencing each
+// of the tables, which blocks unsupported schema changes to the tables. If
+// replicateSchemaChanges is set, the job is also recorded as replicating
+// schema changes, which allows the job to replay the schema changes it
+// replicates. If source is also set, the tables are the source of the job, so
+// users may make the schema changes it replicates.
 func LockLDRTables(
-	ctx context.Context, txn descs.Txn, dstTableDescs []*tabledesc.Mutable, jobID jobspb.JobID,
+	ctx context.Context,
+	txn descs.Txn,
+	dstTableDescs []*tabledesc.Mutable,
+	jobID jobspb.JobID,
+	replicateSchemaChanges bool,
+	source bool,
 ) error {
 	b := txn.KV().NewBatch()
 	for _, td := range dstTableDescs {
 		td.LDRJobIDs = append(td.LDRJobIDs, jobID)
+		if replicateSchemaChanges {
+			td.LDRSchemaChangeJobIDs = append(td.LDRSchemaChangeJobIDs, jobID)
+			if source {
+				td.LDRSchemaChangeSourceJobIDs = append(td.LDRSchemaChangeSourceJobIDs, jobID)
+			}
+		}
 		if err := txn.Descriptors().WriteDescToBatch(ctx, true /* kvTrace */, td, b); err != nil {
 			return err
 		}
@@ -323,6 +340,12 @@ func UnlockLDRTables(
 			td.LDRJobIDs = slices.DeleteFunc(td.LDRJobIDs, func(thisID catpb.JobID) bool {
 				return thisID == jobID
 			})
+			td.LDRSchemaChangeJobIDs = slices.DeleteFunc(td.LDRSchemaChangeJobIDs, func(thisID catpb.JobID) bool {
+				return thisID == jobID
+			})
+			td.LDRSchemaChangeSourceJobIDs = slices.DeleteFunc(td.LDRSchemaChangeSourceJobIDs, func(thisID catpb.JobID) bool {
+				return thisID == jobID
+			})
 			if err := txn.Descriptors().WriteDescToBatch(ctx, true /* kvTrace */, td, b); err != nil {
 				return err
 			}
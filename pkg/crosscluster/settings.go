@@ -99,6 +99,16 @@ var DumpFrontierEntries = settings.RegisterDurationSetting(
 	0,
 )
 
+// FingerprintVerificationInterval controls how often the stream ingestion job
+// verifies the replicated data by fingerprinting it on both clusters.
+var FingerprintVerificationInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"physical_replication.consumer.fingerprint_verification_interval",
+	"the interval at which the consumer job fingerprints the replicated data on the source and "+
+		"destination clusters to verify that they match; if 0, disabled",
+	0,
+)
+
 // ReplicateSpanConfigsEnabled controls whether we replicate span
 // configurations from the source system tenant to the destination system
 // tenant.
@@ -66,6 +66,15 @@ type Client interface {
 	// TODO(dt): separate target argument from address argument.
 	PlanPhysicalReplication(ctx context.Context, streamID streampb.StreamID) (Topology, error)
 
+	// FingerprintSpans returns a fingerprint of each of the given spans of the
+	// stream as of the given time, computed on the source cluster.
+	FingerprintSpans(
+		ctx context.Context,
+		streamID streampb.StreamID,
+		spans []roachpb.Span,
+		asOf hlc.Timestamp,
+	) ([]uint64, error)
+
 	// Subscribe opens and returns a subscription for the specified partition from
 	// the specified remote stream. This is used by each consumer processor to
 	// open its subscription to its partition of a larger stream.
@@ -60,6 +60,13 @@ func (sc testStreamClient) CreateForTenant(
 	}, nil
 }
 
+// FingerprintSpans implements the Client interface.
+func (sc testStreamClient) FingerprintSpans(
+	_ context.Context, _ streampb.StreamID, spans []roachpb.Span, _ hlc.Timestamp,
+) ([]uint64, error) {
+	return make([]uint64, len(spans)), nil
+}
+
 // PlanForPhysicalReplication implements the Client interface.
 func (sc testStreamClient) PlanPhysicalReplication(
 	_ context.Context, _ streampb.StreamID,
@@ -60,6 +60,13 @@ func (m *MockStreamClient) PlanPhysicalReplication(
 	panic("unimplemented mock method")
 }
 
+// FingerprintSpans implements the Client interface.
+func (m *MockStreamClient) FingerprintSpans(
+	_ context.Context, _ streampb.StreamID, _ []roachpb.Span, _ hlc.Timestamp,
+) ([]uint64, error) {
+	panic("unimplemented mock method")
+}
+
 type mockSubscription struct {
 	eventsCh chan crosscluster.Event
 }
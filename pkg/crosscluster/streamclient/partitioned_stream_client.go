@@ -175,6 +175,43 @@ func (p *partitionedStreamClient) PlanPhysicalReplication(
 	return p.createTopology(spec)
 }
 
+// FingerprintSpans implements Client interface.
+func (p *partitionedStreamClient) FingerprintSpans(
+	ctx context.Context, streamID streampb.StreamID, spans []roachpb.Span, asOf hlc.Timestamp,
+) ([]uint64, error) {
+	ctx, sp := tracing.ChildSpan(ctx, "Client.FingerprintSpans")
+	defer sp.Finish()
+
+	req := streampb.ReplicationFingerprintRequest{
+		StreamID: streamID,
+		Spans:    spans,
+		AsOf:     asOf,
+	}
+	rawReq, err := protoutil.Marshal(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	row := p.mu.srcConn.QueryRow(ctx,
+		`SELECT crdb_internal.fingerprint_replication_stream_spans($1)`, rawReq)
+	var rawResp []byte
+	if err := row.Scan(&rawResp); err != nil {
+		return nil, errors.Wrapf(err, "error fingerprinting spans of replication stream %d", streamID)
+	}
+	var resp streampb.ReplicationFingerprintResponse
+	if err := protoutil.Unmarshal(rawResp, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Fingerprints) != len(spans) {
+		return nil, errors.AssertionFailedf("expected %d fingerprints, got %d",
+			len(spans), len(resp.Fingerprints))
+	}
+	return resp.Fingerprints, nil
+}
+
 func (p *partitionedStreamClient) createTopology(
 	spec streampb.ReplicationStreamSpec,
 ) (Topology, error) {
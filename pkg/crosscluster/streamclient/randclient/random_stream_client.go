@@ -424,6 +424,13 @@ func (m *RandomStreamClient) dial() error {
 	return nil
 }
 
+// FingerprintSpans implements the Client interface.
+func (m *RandomStreamClient) FingerprintSpans(
+	_ context.Context, _ streampb.StreamID, _ []roachpb.Span, _ hlc.Timestamp,
+) ([]uint64, error) {
+	return nil, errors.New("fingerprinting is not supported by the random stream client")
+}
+
 // Plan implements the Client interface.
 func (m *RandomStreamClient) PlanPhysicalReplication(
 	ctx context.Context, _ streampb.StreamID,
@@ -48,6 +48,7 @@ var (
 	_ Details = StandbyReadTSPollerDetails{}
 	_ Details = SqlActivityFlushDetails{}
 	_ Details = HotRangesLoggerDetails{}
+	_ Details = TableRevertDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -80,6 +81,7 @@ var (
 	_ ProgressDetails = StandbyReadTSPollerProgress{}
 	_ ProgressDetails = SqlActivityFlushProgress{}
 	_ ProgressDetails = HotRangesLoggerProgress{}
+	_ ProgressDetails = TableRevertProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -248,6 +250,8 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeSQLActivityFlush, nil
 	case *Payload_HotRangesLoggerDetails:
 		return TypeHotRangesLogger, nil
+	case *Payload_TableRevertDetails:
+		return TypeTableRevert, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -303,6 +307,7 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeStandbyReadTSPoller:          StandbyReadTSPollerDetails{},
 	TypeSQLActivityFlush:             SqlActivityFlushDetails{},
 	TypeHotRangesLogger:              HotRangesLoggerDetails{},
+	TypeTableRevert:                  TableRevertDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -372,6 +377,8 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_StandbyReadTsPoller{StandbyReadTsPoller: &d}
 	case SqlActivityFlushProgress:
 		return &Progress_SqlActivityFlush{SqlActivityFlush: &d}
+	case TableRevertProgress:
+		return &Progress_TableRevert{TableRevert: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -441,6 +448,8 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.SqlActivityFlushDetails
 	case *Payload_HotRangesLoggerDetails:
 		return *d.HotRangesLoggerDetails
+	case *Payload_TableRevertDetails:
+		return *d.TableRevertDetails
 	default:
 		return nil
 	}
@@ -510,6 +519,8 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.SqlActivityFlush
 	case *Progress_HotRangesLogger:
 		return *d.HotRangesLogger
+	case *Progress_TableRevert:
+		return *d.TableRevert
 	default:
 		return nil
 	}
@@ -603,6 +614,8 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_SqlActivityFlushDetails{SqlActivityFlushDetails: &d}
 	case HotRangesLoggerDetails:
 		return &Payload_HotRangesLoggerDetails{HotRangesLoggerDetails: &d}
+	case TableRevertDetails:
+		return &Payload_TableRevertDetails{TableRevertDetails: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -638,7 +651,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 33
+const NumJobTypes = 34
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
@@ -284,6 +284,8 @@ func (s *activeMuxRangeFeed) start(ctx context.Context, m *rangefeedMuxer) error
 		for !s.transport.IsExhausted() {
 			args := makeRangeFeedRequest(
 				s.Span, s.token.Desc().RangeID, m.cfg.overSystemTable, s.startAfter, m.cfg.withDiff, m.cfg.withFiltering, m.cfg.withMatchingOriginIDs, m.cfg.consumerID)
+			args.ExpirationLookback = m.cfg.expirationLookback
+			args.WithTxnIDs = m.cfg.withTxnIDs
 			args.Replica = s.transport.NextReplica()
 			args.StreamID = streamID
 			s.ReplicaDescriptor = args.Replica
@@ -67,6 +67,8 @@ type rangeFeedConfig struct {
 	withFiltering         bool
 	withMetadata          bool
 	withMatchingOriginIDs []uint32
+	expirationLookback    time.Duration
+	withTxnIDs            bool
 	rangeObserver         RangeObserver
 	consumerID            int64
 
@@ -126,6 +128,24 @@ func WithMatchingOriginIDs(originIDs ...uint32) RangeFeedOption {
 	})
 }
 
+// WithExpirations opts the rangefeed into deletions emitted when values reach
+// their MVCC expiration. lookback is the longest expire-after used to write
+// the values; values that were written longer than that before their
+// expiration may not produce deletions.
+func WithExpirations(lookback time.Duration) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.expirationLookback = lookback
+	})
+}
+
+// WithTxnIDs opts the rangefeed into the transaction ID and write ordinal of
+// values published when a transaction's intent is committed.
+func WithTxnIDs() RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.withTxnIDs = true
+	})
+}
+
 // WithRangeObserver is called when the rangefeed starts with a function that
 // can be used to iterate over all the ranges.
 func WithRangeObserver(observer RangeObserver) RangeFeedOption {
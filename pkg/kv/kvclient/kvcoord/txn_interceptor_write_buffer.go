@@ -304,6 +304,12 @@ func (twb *txnWriteBuffer) SendLocked(
 }
 
 func (twb *txnWriteBuffer) batchRequiresFlush(ctx context.Context, ba *kvpb.BatchRequest) bool {
+	if ba.WriteOptions.GetExpireAfter() != 0 {
+		// Writes with an MVCC expiration aren't buffered, as we don't store the
+		// inbound batch options in the buffer.
+		log.VEventf(ctx, 2, "batch with ExpireAfter set forcing flush of write buffer")
+		return true
+	}
 	for _, ru := range ba.Requests {
 		req := ru.GetInner()
 		switch req.(type) {
@@ -1499,6 +1505,19 @@ func (twb *txnWriteBuffer) flushBufferAndSendBatch(
 		return twb.wrapped.SendLocked(ctx, ba) // nothing to flush
 	}
 
+	if ba.WriteOptions.GetExpireAfter() != 0 {
+		// The batch's write options apply to all writes in the batch, and the
+		// buffered writes must not expire along with the batch's writes. Flush
+		// them in a batch of their own first.
+		flushBa := ba.ShallowCopy()
+		flushBa.Requests = nil
+		flushBa.WriteOptions = nil
+		if _, pErr := twb.flushBufferAndSendBatch(ctx, flushBa); pErr != nil {
+			return nil, pErr
+		}
+		return twb.wrapped.SendLocked(ctx, ba)
+	}
+
 	_, hasEndTxn := ba.GetArg(kvpb.EndTxn)
 	if !hasEndTxn {
 		// We're flushing the buffer even though the batch doesn't contain an EndTxn
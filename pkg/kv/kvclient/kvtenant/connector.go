@@ -880,6 +880,28 @@ func (c *connector) HotRangesV2(
 	return resp, nil
 }
 
+// HotKeys implements the serverpb.TenantStatusServer interface
+func (c *connector) HotKeys(
+	ctx context.Context, req *serverpb.HotKeysRequest,
+) (*serverpb.HotKeysResponse, error) {
+	var resp *serverpb.HotKeysResponse
+	r := *req
+	// Force the request to be scoped to the requesting tenant.
+	if len(req.TenantID) == 0 {
+		r.TenantID = c.tenantID.String()
+	} else if c.tenantID.String() != req.TenantID {
+		return nil, status.Error(codes.PermissionDenied, "cannot request hot keys for another tenant")
+	}
+	if err := c.withClient(ctx, func(ctx context.Context, c *client) error {
+		var err error
+		resp, err = c.HotKeys(ctx, &r)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // DownloadSpan implements the serverpb.TenantStatusServer interface
 func (c *connector) DownloadSpan(
 	ctx context.Context, req *serverpb.DownloadSpanRequest,
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
@@ -2521,6 +2522,13 @@ func (writeOptions *WriteOptions) GetOriginTimestamp() hlc.Timestamp {
 	return writeOptions.OriginTimestamp
 }
 
+func (writeOptions *WriteOptions) GetExpireAfter() time.Duration {
+	if writeOptions == nil {
+		return 0
+	}
+	return writeOptions.ExpireAfter
+}
+
 func (r *ConditionalPutRequest) Validate(_ Header) error {
 	if !r.OriginTimestamp.IsEmpty() {
 		if r.AllowIfDoesNotExist {
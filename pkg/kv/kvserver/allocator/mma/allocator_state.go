@@ -7,6 +7,7 @@ package mma
 
 import (
 	"cmp"
+	"math"
 	"slices"
 	"sync"
 
@@ -41,29 +42,240 @@ func newAllocatorState(ts timeutil.TimeSource) *allocatorState {
 	}
 }
 
+// maxChangesPerRebalancePass bounds the number of ranges a store moves
+// leases or replicas of in a single call to computeChanges.
+const maxChangesPerRebalancePass = 10
+
 // Called periodically, say every 10s.
-func (a *allocatorState) computeChanges() []*pendingReplicaChange {
-	// TODO(sumeer): rebalancing. To select which stores are overloaded, we will
-	// use a notion of overload that is based on cluster means (and of course
-	// individual store/node capacities). We do not want to loop through all
-	// ranges in the cluster, and for each range and its constraints expression
-	// decide whether any of the replica stores is overloaded, since
-	// O(num-ranges) work during each allocator pass is not scalable.
-	//
-	// If cluster mean is too low, more will be considered overloaded. This is
-	// ok, since then when we look at ranges we will have a different mean for
-	// the constraint satisfying candidates and if that mean is higher we may
-	// not do anything. There is wasted work, but we can bound it by typically
-	// only looking at a random K ranges for each store.
-	//
-	// If the cluster mean is too high, we will not rebalance across subsets
-	// that have a low mean. Seems fine, if we accept that rebalancing is not
-	// responsible for equalizing load across two nodes that have 30% and 50%
-	// cpu utilization while the cluster mean is 70% utilization (as an
-	// example).
+//
+// computeChanges sheds load from localStoreID if it is overloaded, by moving
+// the leases and replicas of the ranges for which it is the leaseholder. The
+// changes are remembered as pending changes, so that the load adjustments
+// are accounted for by subsequent decisions.
+//
+// TODO(sumeer): this is a simplified rebalancing pass. To select which
+// stores are overloaded, we will use a notion of overload that is based on
+// cluster means (and of course individual store/node capacities). We do not
+// want to loop through all ranges in the cluster, and for each range and its
+// constraints expression decide whether any of the replica stores is
+// overloaded, since O(num-ranges) work during each allocator pass is not
+// scalable.
+//
+// If cluster mean is too low, more will be considered overloaded. This is
+// ok, since then when we look at ranges we will have a different mean for
+// the constraint satisfying candidates and if that mean is higher we may
+// not do anything. There is wasted work, but we can bound it by typically
+// only looking at a random K ranges for each store.
+//
+// If the cluster mean is too high, we will not rebalance across subsets
+// that have a low mean. Seems fine, if we accept that rebalancing is not
+// responsible for equalizing load across two nodes that have 30% and 50%
+// cpu utilization while the cluster mean is 70% utilization (as an
+// example).
+func (a *allocatorState) computeChanges(localStoreID roachpb.StoreID) []*pendingReplicaChange {
+	a.meansMemo.clear()
+	ss, ok := a.cs.stores[localStoreID]
+	if !ok {
+		return nil
+	}
+	means := a.meansMemo.getMeans(nil)
+	if !a.isOverloaded(means, ss) {
+		return nil
+	}
+
+	// Consider the ranges for which the store is the leaseholder, in
+	// decreasing order of cpu.
+	var rangeIDs []roachpb.RangeID
+	for rangeID, repl := range ss.adjusted.replicas {
+		if repl.isLeaseholder {
+			rangeIDs = append(rangeIDs, rangeID)
+		}
+	}
+	slices.SortFunc(rangeIDs, func(x, y roachpb.RangeID) int {
+		if c := cmp.Compare(a.cs.ranges[y].load.load[cpu], a.cs.ranges[x].load.load[cpu]); c != 0 {
+			return c
+		}
+		return cmp.Compare(x, y)
+	})
+
+	var changes []*pendingReplicaChange
+	for _, rangeID := range rangeIDs {
+		if len(changes) >= maxChangesPerRebalancePass || !a.isOverloaded(means, ss) {
+			break
+		}
+		rs := a.cs.ranges[rangeID]
+		if len(rs.pendingChanges) > 0 {
+			// Let the existing changes for the range finish.
+			continue
+		}
+		changes = append(changes, a.tryMovingRange(means, ss, rs)...)
+	}
+	return changes
+}
+
+// isOverloaded returns whether the store, or its node, is overloaded in the
+// context of the given means.
+func (a *allocatorState) isOverloaded(means *meansForStoreSet, ss *storeState) bool {
+	sls := a.meansMemo.getStoreLoadSummary(means, ss.StoreID, ss.loadSeqNum)
+	return sls.sls <= overloadSlow || sls.nls <= overloadSlow
+}
+
+// tryMovingRange attempts to shed the load of the range from the store ss,
+// which is the range's leaseholder. It first tries to transfer the lease,
+// which moves the non-raft cpu of the range, and then tries to move the
+// replica to another store. The returned changes, if any, have been added to
+// the pending changes.
+func (a *allocatorState) tryMovingRange(
+	means *meansForStoreSet, ss *storeState, rs *rangeState,
+) []*pendingReplicaChange {
+	rac := rangeAnalyzedConstraintsPool.Get().(*rangeAnalyzedConstraints)
+	defer releaseRangeAnalyzedConstraints(rac)
+	buf := rac.stateForInit()
+	for _, repl := range rs.replicas {
+		buf.tryAddingStore(repl.StoreID, repl.replicaType.replicaType, a.cs.stores[repl.StoreID].localityTiers)
+	}
+	rac.finishInit(rs.conf, a.cs.constraintMatcher, ss.StoreID)
+
+	ns := a.cs.nodes[ss.NodeID]
+	summary := a.meansMemo.getStoreLoadSummary(means, ss.StoreID, ss.loadSeqNum)
+	if summary.nls <= overloadSlow {
+		// The node's cpu is overloaded, see if moving the lease is enough.
+		leaseCands, _ := rac.candidatesToMoveLease()
+		nonRaftCPU := rs.load.load[cpu] - rs.load.raftCPU
+		for _, cand := range leaseCands {
+			css := a.cs.stores[cand.storeID]
+			csls := a.meansMemo.getStoreLoadSummary(means, cand.storeID, css.loadSeqNum)
+			if csls.fd != fdOK || csls.nls <= loadNoChange {
+				continue
+			}
+			// Don't move the lease if the target would end up with more cpu than
+			// the source, which would just move the overload.
+			if a.cs.nodes[css.NodeID].adjustedCPU+nonRaftCPU >= ns.adjustedCPU-nonRaftCPU {
+				continue
+			}
+			changes := makeLeaseTransferChanges(rs.rangeID, rs.replicas, rs.load, cand.storeID, ss.StoreID)
+			return a.cs.createPendingChanges(rs.rangeID, changes[:]...)
+		}
+	}
+
+	// Move the replica to another store satisfying the same constraint.
+	voter := isVoter(ss.adjusted.replicas[rs.rangeID].replicaType.replicaType)
+	var conj constraintsConj
+	var err error
+	if voter {
+		conj, err = rac.candidatesToReplaceVoterForRebalance(ss.StoreID)
+	} else {
+		conj, err = rac.candidatesToReplaceNonVoterForRebalance(ss.StoreID)
+	}
+	if err != nil {
+		// The range needs up-replication or constraint repair first, which is
+		// not handled here.
+		return nil
+	}
+	var storesToExclude storeIDPostingList
+	var existing []localityTiers
+	for _, repl := range rs.replicas {
+		storesToExclude.insert(repl.StoreID)
+		// Voters are scored against the other voters, non-voters against all
+		// replicas.
+		if !voter || isVoter(repl.replicaType.replicaType) {
+			existing = append(existing, a.cs.stores[repl.StoreID].localityTiers)
+		}
+	}
+	// Don't move the replica to another store on one of the range's nodes.
+	for _, repl := range rs.replicas {
+		for _, storeID := range a.cs.nodes[a.cs.stores[repl.StoreID].NodeID].stores {
+			storesToExclude.insert(storeID)
+		}
+	}
+	cset := a.computeCandidatesForRange(constraintsDisj{conj}, storesToExclude, ss.StoreID)
+	if len(cset.candidates) == 0 {
+		return nil
+	}
+	erl := a.diversityScoringMemo.getExistingReplicaLocalities(existing)
+	for i := range cset.candidates {
+		cset.candidates[i].diversityScore = erl.getScoreChangeForRebalance(
+			ss.localityTiers, a.cs.stores[cset.candidates[i].StoreID].localityTiers)
+	}
+	// Prefer the least loaded candidates, and among those the ones which
+	// increase diversity the most.
+	slices.SortFunc(cset.candidates, func(x, y candidateInfo) int {
+		if c := cmp.Compare(min(y.sls, y.nls), min(x.sls, x.nls)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(y.diversityScore, x.diversityScore); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.StoreID, y.StoreID)
+	})
+
+	for _, cand := range cset.candidates {
+		if cand.diversityScore < 0 {
+			// Never reduce the diversity of the range to shed load.
+			continue
+		}
+		if a.leasePreferenceIndex(rs.conf, cand.StoreID) > rac.leaseholderPreferenceIndex {
+			// The lease moves along with the replica, it must not end up on a
+			// store less preferred than the current leaseholder.
+			continue
+		}
+		changes := makeRebalanceReplicaChanges(rs.rangeID, rs.replicas, rs.load, cand.StoreID, ss.StoreID)
+		if !a.rebalanceReducesOverload(means, ss, a.cs.stores[cand.StoreID], changes[0].loadDelta) {
+			continue
+		}
+		return a.cs.createPendingChanges(rs.rangeID, changes[:]...)
+	}
 	return nil
 }
 
+// leasePreferenceIndex returns the index of the first lease preference
+// matched by the store, or math.MaxInt32 if it matches none.
+func (a *allocatorState) leasePreferenceIndex(
+	conf *normalizedSpanConfig, storeID roachpb.StoreID,
+) int32 {
+	for i := range conf.leasePreferences {
+		if a.cs.constraintMatcher.storeMatches(storeID, conf.leasePreferences[i].constraints) {
+			return int32(i)
+		}
+	}
+	return math.MaxInt32
+}
+
+// isVoter returns whether the replica type is counted as a voter, consistent
+// with analyzeConstraintsBuf.tryAddingStore.
+func isVoter(rType roachpb.ReplicaType) bool {
+	return rType == roachpb.VOTER_FULL || rType == roachpb.VOTER_INCOMING
+}
+
+// rebalanceReducesOverload returns whether moving the load delta from the
+// source to the target store reduces the load of the source in every
+// dimension it is overloaded in, without making the target more loaded than
+// the source in that dimension.
+func (a *allocatorState) rebalanceReducesOverload(
+	means *meansForStoreSet, source, target *storeState, delta loadVector,
+) bool {
+	reduces := false
+	for i := range delta {
+		ls := loadSummaryForDimension(source.adjusted.load[i], source.capacity[i],
+			means.storeLoad.load[i], means.storeLoad.util[i])
+		srcLoad, targetLoad := source.adjusted.load[i], target.adjusted.load[i]
+		if loadDimension(i) == cpu {
+			sns, tns := a.cs.nodes[source.NodeID], a.cs.nodes[target.NodeID]
+			ls = loadSummaryForDimension(sns.adjustedCPU, sns.capacityCPU,
+				means.nodeLoad.loadCPU, means.nodeLoad.utilCPU)
+			srcLoad, targetLoad = sns.adjustedCPU, tns.adjustedCPU
+		}
+		if ls > overloadSlow {
+			continue
+		}
+		if delta[i] <= 0 || targetLoad+delta[i] >= srcLoad-delta[i] {
+			return false
+		}
+		reduces = true
+	}
+	return reduces
+}
+
 // TODO(sumeer): look at support methods for allocatorState.tryMovingRange in
 // the allocator kernel draft PR.
 
@@ -401,8 +613,6 @@ func (erl *existingReplicaLocalities) getScoreSum(replica localityTiers) float64
 // Avoid unused lint errors.
 
 var _ = newAllocatorState
-var _ = (&allocatorState{}).computeChanges
-var _ = (&allocatorState{}).computeCandidatesForRange
 var _ = allocatorState{}.changeRangeLimiter
 var _ = (&existingReplicaLocalities{}).clear
 var _ = replicasLocalityTiers{}.hash
@@ -410,8 +620,5 @@ var _ = replicasLocalityTiers{}.isEqual
 var _ = existingReplicaLocalitiesSlicePoolImpl{}.newEntry
 var _ = existingReplicaLocalitiesSlicePoolImpl{}.releaseEntry
 var _ = existingReplicaLocalitiesAllocator{}.ensureNonNilMapEntry
-var _ = (&diversityScoringMemo{}).getExistingReplicaLocalities
 var _ = (&existingReplicaLocalities{}).getScoreChangeForNewReplica
 var _ = (&existingReplicaLocalities{}).getScoreChangeForReplicaRemoval
-var _ = (&existingReplicaLocalities{}).getScoreChangeForRebalance
-var _ = candidateInfo{}.diversityScore
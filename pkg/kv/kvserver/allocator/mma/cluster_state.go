@@ -839,6 +839,7 @@ func (cs *clusterState) processStoreLeaseholderMsg(msg *storeLeaseholderMsg) {
 		if !ok {
 			// This is the first time we've seen this range.
 			rs = newRangeState()
+			rs.rangeID = rangeMsg.RangeID
 			cs.ranges[rangeMsg.RangeID] = rs
 		}
 		// Set the range state and store state to match the range message state
@@ -1160,7 +1161,6 @@ var _ = replicaState{}.voterIsLagging
 var _ = storeState{}.maxFractionPending
 var _ = nodeState{}.loadSummary
 var _ = rangeState{}.diversityIncreaseLastFailedAttempt
-var _ = rangeState{}.rangeID
 var _ = enactedReplicaChange{}
 var _ = storeEnactedHistory{}.changes
 var _ = storeEnactedHistory{}.totalDelta
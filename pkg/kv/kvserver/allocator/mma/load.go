@@ -398,6 +398,11 @@ func loadSummaryForDimension(
 	if capacity == parentCapacity {
 		return loadLow
 	}
+	if meanLoad <= 0 && load <= 0 {
+		// There is no load in this dimension, e.g. no writes, which must not
+		// prevent rebalancing based on the other dimensions.
+		return loadLow
+	}
 	loadSummary := loadLow
 	// Heuristics: this is all very rough and subject to revision. There are two
 	// uses for this loadSummary: to find source stores to shed load and to
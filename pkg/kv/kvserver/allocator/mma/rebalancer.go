@@ -0,0 +1,211 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package mma
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Rebalancer exposes the load based rebalancing of the allocator to callers
+// outside of this package, such as the allocation simulator, until the
+// messages consumed by the Allocator interface are protos.
+//
+// A Rebalancer is populated with SetStore, ProcessNodeLoad and
+// ProcessLeaseholderRanges before calling ComputeChanges.
+type Rebalancer struct {
+	a *allocatorState
+}
+
+// NodeLoad is the load reported by a node and its stores.
+type NodeLoad struct {
+	NodeID roachpb.NodeID
+	// CPU and CPUCapacity are in nanos per second. CPUCapacity must be
+	// positive.
+	CPU, CPUCapacity int64
+	// Suspect is set for nodes which should not receive replicas or leases,
+	// e.g. because they are decommissioning.
+	Suspect  bool
+	Stores   []StoreLoad
+	LoadTime time.Time
+}
+
+// StoreLoad is the load reported by a store.
+type StoreLoad struct {
+	StoreID roachpb.StoreID
+	// WriteBandwidth is in bytes per second, its capacity is unknown.
+	WriteBandwidth int64
+	// ByteSize and ByteSizeCapacity are in bytes.
+	ByteSize, ByteSizeCapacity int64
+	LeaseCount                 int64
+}
+
+// RangeReplica is a replica of a range reported by its leaseholder.
+type RangeReplica struct {
+	StoreID       roachpb.StoreID
+	ReplicaID     roachpb.ReplicaID
+	Type          roachpb.ReplicaType
+	IsLeaseholder bool
+}
+
+// RangeLoad is a range reported by its leaseholder.
+type RangeLoad struct {
+	RangeID  roachpb.RangeID
+	Replicas []RangeReplica
+	Conf     roachpb.SpanConfig
+	// CPU and RaftCPU are in nanos per second, RaftCPU <= CPU.
+	CPU, RaftCPU int64
+	// WriteBandwidth is in bytes per second.
+	WriteBandwidth int64
+	// ByteSize is in bytes.
+	ByteSize int64
+}
+
+// Change is a change proposed by ComputeChanges.
+type Change struct {
+	RangeID roachpb.RangeID
+	// IsLeaseTransfer is set when the lease moves from RemoveStoreID to
+	// AddStoreID. Otherwise, the replica on RemoveStoreID is moved to
+	// AddStoreID, along with the lease if it held it.
+	IsLeaseTransfer bool
+	AddStoreID      roachpb.StoreID
+	RemoveStoreID   roachpb.StoreID
+}
+
+// NewRebalancer returns a Rebalancer without any knowledge of the cluster.
+func NewRebalancer(ts timeutil.TimeSource) *Rebalancer {
+	return &Rebalancer{a: newAllocatorState(ts)}
+}
+
+// SetStore informs the rebalancer about a store. Stores must be set before
+// their load or ranges are processed.
+func (r *Rebalancer) SetStore(desc roachpb.StoreDescriptor) {
+	if _, ok := r.a.cs.stores[desc.StoreID]; ok {
+		// clusterState.setStore would add the store to its node again.
+		return
+	}
+	r.a.cs.setStore(desc)
+}
+
+// ProcessNodeLoad updates the load of a node and its stores.
+func (r *Rebalancer) ProcessNodeLoad(nl NodeLoad) error {
+	if _, ok := r.a.cs.nodes[nl.NodeID]; !ok {
+		return errors.Errorf("n%d has no known stores", nl.NodeID)
+	}
+	if nl.CPUCapacity <= 0 {
+		return errors.Errorf("n%d has no cpu capacity", nl.NodeID)
+	}
+	msg := &nodeLoadMsg{
+		nodeLoad: nodeLoad{
+			nodeID:      nl.NodeID,
+			reportedCPU: loadValue(nl.CPU),
+			capacityCPU: loadValue(nl.CPUCapacity),
+		},
+		loadTime: nl.LoadTime,
+	}
+	for _, sl := range nl.Stores {
+		ss, ok := r.a.cs.stores[sl.StoreID]
+		if !ok || ss.NodeID != nl.NodeID {
+			return errors.Errorf("s%d is not a known store of n%d", sl.StoreID, nl.NodeID)
+		}
+		storeMsg := storeLoadMsg{StoreID: sl.StoreID}
+		storeMsg.load[writeBandwidth] = loadValue(sl.WriteBandwidth)
+		storeMsg.load[byteSize] = loadValue(sl.ByteSize)
+		storeMsg.capacity[cpu] = parentCapacity
+		storeMsg.capacity[writeBandwidth] = unknownCapacity
+		storeMsg.capacity[byteSize] = loadValue(sl.ByteSizeCapacity)
+		storeMsg.secondaryLoad[leaseCount] = loadValue(sl.LeaseCount)
+		msg.stores = append(msg.stores, storeMsg)
+	}
+	r.a.cs.processNodeLoadMsg(msg)
+	fd := fdOK
+	if nl.Suspect {
+		fd = fdSuspect
+	}
+	r.a.cs.updateFailureDetectionSummary(nl.NodeID, fd)
+	return nil
+}
+
+// ProcessLeaseholderRanges provides the ranges for which the store is the
+// leaseholder.
+func (r *Rebalancer) ProcessLeaseholderRanges(storeID roachpb.StoreID, ranges []RangeLoad) error {
+	if _, ok := r.a.cs.stores[storeID]; !ok {
+		return errors.Errorf("s%d is not a known store", storeID)
+	}
+	msg := &storeLeaseholderMsg{StoreID: storeID}
+	for _, rl := range ranges {
+		rm := rangeMsg{RangeID: rl.RangeID, conf: rl.Conf}
+		// The allocator needs the number of voters to be explicit.
+		if rm.conf.NumVoters == 0 {
+			rm.conf.NumVoters = rm.conf.NumReplicas
+		}
+		if _, err := makeNormalizedSpanConfig(&rm.conf, r.a.cs.constraintMatcher.interner); err != nil {
+			return errors.Wrapf(err, "r%d", rl.RangeID)
+		}
+		var hasLeaseholder bool
+		for _, repl := range rl.Replicas {
+			if _, ok := r.a.cs.stores[repl.StoreID]; !ok {
+				return errors.Errorf("r%d has a replica on unknown store s%d", rl.RangeID, repl.StoreID)
+			}
+			hasLeaseholder = hasLeaseholder || (repl.IsLeaseholder && repl.StoreID == storeID)
+			rm.replicas = append(rm.replicas, storeIDAndReplicaState{
+				StoreID: repl.StoreID,
+				replicaState: replicaState{
+					replicaIDAndType: replicaIDAndType{
+						ReplicaID:     repl.ReplicaID,
+						replicaType:   replicaType{replicaType: repl.Type},
+						isLeaseholder: repl.IsLeaseholder,
+					},
+				},
+			})
+		}
+		if !hasLeaseholder {
+			return errors.Errorf("r%d is not led by s%d", rl.RangeID, storeID)
+		}
+		rm.rangeLoad.load[cpu] = loadValue(rl.CPU)
+		rm.rangeLoad.load[writeBandwidth] = loadValue(rl.WriteBandwidth)
+		rm.rangeLoad.load[byteSize] = loadValue(rl.ByteSize)
+		rm.rangeLoad.raftCPU = loadValue(rl.RaftCPU)
+		msg.ranges = append(msg.ranges, rm)
+	}
+	r.a.cs.processStoreLeaseholderMsg(msg)
+	return nil
+}
+
+// ComputeChanges returns the lease transfers and replica moves that shed load
+// from the store, if it is overloaded. Subsequent calls account for the load
+// moved by the changes that were returned earlier.
+func (r *Rebalancer) ComputeChanges(storeID roachpb.StoreID) []Change {
+	var changes []Change
+	pending := r.a.computeChanges(storeID)
+	// Every change proposed by computeChanges is a pair of replica changes.
+	for i := 0; i+1 < len(pending); i += 2 {
+		c := pending[i]
+		switch {
+		case c.isUpdate():
+			// Lease transfers are a pair of updates, removing the lease first.
+			changes = append(changes, Change{
+				RangeID:         c.rangeID,
+				IsLeaseTransfer: true,
+				RemoveStoreID:   c.storeID,
+				AddStoreID:      pending[i+1].storeID,
+			})
+		case c.isAddition():
+			// Replica moves are an addition followed by a removal.
+			changes = append(changes, Change{
+				RangeID:       c.rangeID,
+				AddStoreID:    c.storeID,
+				RemoveStoreID: pending[i+1].storeID,
+			})
+		default:
+			panic(errors.AssertionFailedf("unexpected change %v", c.replicaChange))
+		}
+	}
+	return changes
+}
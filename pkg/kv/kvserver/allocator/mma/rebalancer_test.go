@@ -0,0 +1,121 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package mma
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebalancer(t *testing.T) {
+	const numRanges = 10
+	const cpuCapacity = 8e9
+
+	// makeRebalancer returns a rebalancer for a cluster with one store per
+	// node, in zone z<i>, with the given node cpu and store byte size. Store 1
+	// is the leaseholder of every range, which are replicated on the first
+	// three stores.
+	makeRebalancer := func(
+		t *testing.T, nodeCPU []int64, byteSize []int64, rangeByteSize int64, conf roachpb.SpanConfig,
+	) *Rebalancer {
+		r := NewRebalancer(timeutil.NewManualTime(testingBaseTime))
+		for i := range nodeCPU {
+			id := i + 1
+			r.SetStore(roachpb.StoreDescriptor{
+				StoreID: roachpb.StoreID(id),
+				Node: roachpb.NodeDescriptor{
+					NodeID:   roachpb.NodeID(id),
+					Locality: roachpb.Locality{Tiers: []roachpb.Tier{{Key: "zone", Value: fmt.Sprintf("z%d", id)}}},
+				},
+			})
+		}
+		for i := range nodeCPU {
+			id := i + 1
+			require.NoError(t, r.ProcessNodeLoad(NodeLoad{
+				NodeID:      roachpb.NodeID(id),
+				CPU:         nodeCPU[i],
+				CPUCapacity: cpuCapacity,
+				Stores: []StoreLoad{{
+					StoreID:          roachpb.StoreID(id),
+					ByteSize:         byteSize[i],
+					ByteSizeCapacity: 100e9,
+				}},
+				LoadTime: testingBaseTime,
+			}))
+		}
+		var ranges []RangeLoad
+		for i := 0; i < numRanges; i++ {
+			rl := RangeLoad{
+				RangeID:  roachpb.RangeID(i + 1),
+				Conf:     conf,
+				CPU:      5e8,
+				ByteSize: rangeByteSize,
+			}
+			for j := 1; j <= 3; j++ {
+				rl.Replicas = append(rl.Replicas, RangeReplica{
+					StoreID:       roachpb.StoreID(j),
+					ReplicaID:     roachpb.ReplicaID(j),
+					Type:          roachpb.VOTER_FULL,
+					IsLeaseholder: j == 1,
+				})
+			}
+			ranges = append(ranges, rl)
+		}
+		require.NoError(t, r.ProcessLeaseholderRanges(1, ranges))
+		return r
+	}
+	conf := roachpb.SpanConfig{NumReplicas: 3}
+
+	t.Run("balanced", func(t *testing.T) {
+		r := makeRebalancer(t, []int64{1e9, 1e9, 1e9}, []int64{1e9, 1e9, 1e9}, 1e8, conf)
+		require.Empty(t, r.ComputeChanges(1))
+	})
+
+	t.Run("cpu overload moves leases", func(t *testing.T) {
+		r := makeRebalancer(t, []int64{6e9, 1e9, 1e9}, []int64{1e9, 1e9, 1e9}, 1e8, conf)
+		changes := r.ComputeChanges(1)
+		require.NotEmpty(t, changes)
+		for _, c := range changes {
+			require.True(t, c.IsLeaseTransfer, "%+v", c)
+			require.Equal(t, roachpb.StoreID(1), c.RemoveStoreID)
+			require.NotEqual(t, roachpb.StoreID(1), c.AddStoreID)
+		}
+		// The load moved by the pending changes is accounted for.
+		require.Empty(t, r.ComputeChanges(1))
+	})
+
+	t.Run("lease preferences pin the leases", func(t *testing.T) {
+		pinned := conf
+		pinned.LeasePreferences = []roachpb.LeasePreference{{
+			Constraints: []roachpb.Constraint{{Type: roachpb.Constraint_REQUIRED, Key: "zone", Value: "z1"}},
+		}}
+		r := makeRebalancer(t, []int64{6e9, 1e9, 1e9, 1e9}, []int64{1e9, 1e9, 1e9, 1e9}, 1e8, pinned)
+		require.Empty(t, r.ComputeChanges(1))
+	})
+
+	t.Run("disk overload moves replicas", func(t *testing.T) {
+		// Store 4 needs to be less loaded than the others in every dimension to
+		// be a target.
+		r := makeRebalancer(t, []int64{1e9, 1e9, 1e9, 5e8}, []int64{95e9, 40e9, 40e9, 0}, 10e9, conf)
+		changes := r.ComputeChanges(1)
+		require.NotEmpty(t, changes)
+		for _, c := range changes {
+			require.False(t, c.IsLeaseTransfer, "%+v", c)
+			require.Equal(t, roachpb.StoreID(1), c.RemoveStoreID)
+			require.Equal(t, roachpb.StoreID(4), c.AddStoreID)
+		}
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		r := makeRebalancer(t, []int64{1e9, 1e9, 1e9}, []int64{1e9, 1e9, 1e9}, 1e8, conf)
+		require.Error(t, r.ProcessLeaseholderRanges(7, nil))
+		require.Error(t, r.ProcessNodeLoad(NodeLoad{NodeID: 7, CPUCapacity: cpuCapacity}))
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/gossip"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/history"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/mmarebalancer"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/op"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/queue"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/scheduled"
@@ -47,6 +48,8 @@ type Simulator struct {
 	sqs map[state.StoreID]queue.RangeQueue
 	// Store rebalancers.
 	srs map[state.StoreID]storerebalancer.StoreRebalancer
+	// Store multi-metric allocator rebalancers.
+	mrs map[state.StoreID]mmarebalancer.MMARebalancer
 	// Store operation controllers.
 	controllers map[state.StoreID]op.Controller
 
@@ -103,6 +106,7 @@ func NewSimulator(
 		sqs:            sqs,
 		controllers:    controllers,
 		srs:            srs,
+		mrs:            make(map[state.StoreID]mmarebalancer.MMARebalancer),
 		pacers:         pacers,
 		gossip:         gossip.NewGossip(initialState, settings),
 		metrics:        m,
@@ -175,6 +179,12 @@ func (s *Simulator) addStore(storeID state.StoreID, tick time.Time) {
 		s.settings,
 		storerebalancer.GetStateRaftStatusFn(s.state),
 	)
+	s.mrs[storeID] = mmarebalancer.NewMMARebalancer(
+		tick,
+		storeID,
+		s.controllers[storeID],
+		s.settings,
+	)
 }
 
 // GetNextTickTime returns a simulated tick time, or an indication that the
@@ -242,6 +252,9 @@ func (s *Simulator) RunSim(ctx context.Context) {
 		// Simulate the store rebalancer logic.
 		s.tickStoreRebalancers(ctx, tick, stateForAlloc)
 
+		// Simulate the multi-metric allocator's rebalancing.
+		s.tickMMARebalancers(ctx, tick, stateForAlloc)
+
 		// Print tick metrics.
 		s.tickMetrics(ctx, tick)
 	}
@@ -342,6 +355,16 @@ func (s *Simulator) tickStoreRebalancers(ctx context.Context, tick time.Time, st
 	}
 }
 
+// tickMMARebalancers iterates over the multi-metric allocator rebalancers in
+// the cluster and ticks their control loop.
+func (s *Simulator) tickMMARebalancers(ctx context.Context, tick time.Time, state state.State) {
+	stores := s.state.Stores()
+	s.shuffler(len(stores), func(i, j int) { stores[i], stores[j] = stores[j], stores[i] })
+	for _, store := range stores {
+		s.mrs[store.StoreID()].Tick(ctx, tick, state)
+	}
+}
+
 // tickMetrics prints the metrics up to the given tick.
 func (s *Simulator) tickMetrics(ctx context.Context, tick time.Time) {
 	s.metrics.Tick(ctx, tick, s.state)
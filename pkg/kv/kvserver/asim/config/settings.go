@@ -28,6 +28,9 @@ const (
 	defaultLBRebalanceQPSThreshold = 0.1
 	defaultLBMinRequiredQPSDiff    = 200
 	defaultLBRebalancingObjective  = 0 // QPS
+	defaultMMARebalancingInterval  = 10 * time.Second
+	defaultMMACPUNanosPerQuery     = 50 * 1000 // 50µs
+	defaultMMANodeCPUCapacity      = 8 * 1e9   // 8 vCPUs
 )
 
 var (
@@ -110,6 +113,19 @@ type SimulationSettings struct {
 	// rebalancer would care to reconcile (via lease or replica rebalancing) between
 	// any two stores.
 	LBMinRequiredQPSDiff float64
+	// MMARebalancing enables the multi-metric allocator's load based
+	// rebalancing, which runs in addition to the queues and store rebalancer.
+	MMARebalancing bool
+	// MMARebalancingInterval controls how often the multi-metric allocator will
+	// consider shedding load from a store.
+	MMARebalancingInterval time.Duration
+	// MMACPUNanosPerQuery is the cpu time attributed to each query. The
+	// simulator does not model cpu, which the multi-metric allocator
+	// rebalances, so it is derived from the QPS.
+	MMACPUNanosPerQuery int64
+	// MMANodeCPUCapacity is the cpu capacity of each node, in nanos per
+	// second.
+	MMANodeCPUCapacity int64
 }
 
 // DefaultSimulationSettings returns a set of default settings for simulation.
@@ -136,6 +152,9 @@ func DefaultSimulationSettings() *SimulationSettings {
 		LBRebalancingInterval:   defaultLBRebalancingInterval,
 		LBRebalanceQPSThreshold: defaultLBRebalanceQPSThreshold,
 		LBMinRequiredQPSDiff:    defaultLBMinRequiredQPSDiff,
+		MMARebalancingInterval:  defaultMMARebalancingInterval,
+		MMACPUNanosPerQuery:     defaultMMACPUNanosPerQuery,
+		MMANodeCPUCapacity:      defaultMMANodeCPUCapacity,
 	}
 }
 
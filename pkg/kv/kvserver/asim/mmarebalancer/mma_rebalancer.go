@@ -0,0 +1,210 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package mmarebalancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/mma"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/op"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// MMARebalancer is a tickable actor which sheds load from the store
+// associated with it using the multi-metric allocator.
+type MMARebalancer interface {
+	Tick(context.Context, time.Time, state.State)
+}
+
+type mmaRebalancer struct {
+	storeID    state.StoreID
+	controller op.Controller
+	settings   *config.SimulationSettings
+
+	lastTick time.Time
+	// pending are the operations dispatched by the last rebalancing pass. The
+	// next pass waits until they are done.
+	pending []op.ControlledOperation
+}
+
+// NewMMARebalancer returns a MMARebalancer for the store, which dispatches
+// its changes to the controller given.
+func NewMMARebalancer(
+	start time.Time,
+	storeID state.StoreID,
+	controller op.Controller,
+	settings *config.SimulationSettings,
+) MMARebalancer {
+	return &mmaRebalancer{
+		storeID:    storeID,
+		controller: controller,
+		settings:   settings,
+		lastTick:   start,
+	}
+}
+
+// Tick runs a rebalancing pass when the rebalancing interval has elapsed and
+// the operations of the previous pass are done.
+func (mr *mmaRebalancer) Tick(ctx context.Context, tick time.Time, s state.State) {
+	if !mr.settings.MMARebalancing || tick.Before(mr.lastTick.Add(mr.settings.MMARebalancingInterval)) {
+		return
+	}
+	for _, pending := range mr.pending {
+		if done, _ := pending.Done(); !done {
+			return
+		}
+		if err := pending.Errors(); err != nil {
+			log.VEventf(ctx, 1, "s%d: mma operation failed: %v", mr.storeID, err)
+		}
+	}
+	mr.pending = mr.pending[:0]
+	mr.lastTick = tick
+
+	// The multi-metric allocator keeps its own view of the cluster, which is
+	// rebuilt from the simulated state on every pass.
+	rebalancer, err := mr.makeRebalancer(s)
+	if err != nil {
+		log.Warningf(ctx, "s%d: unable to initialize mma: %v", mr.storeID, err)
+		return
+	}
+	for _, change := range rebalancer.ComputeChanges(roachpb.StoreID(mr.storeID)) {
+		rng, ok := s.Range(state.RangeID(change.RangeID))
+		if !ok {
+			continue
+		}
+		var co op.ControlledOperation
+		if change.IsLeaseTransfer {
+			co = op.NewTransferLeaseOp(tick, change.RangeID, change.RemoveStoreID, change.AddStoreID,
+				s.RangeUsageInfo(rng.RangeID(), mr.storeID))
+		} else if co, ok = mr.relocateOp(tick, s, rng, change); !ok {
+			continue
+		}
+		mr.controller.Dispatch(ctx, tick, s, co)
+		mr.pending = append(mr.pending, co)
+	}
+}
+
+// makeRebalancer returns a mma.Rebalancer populated with the stores and load
+// of the cluster, and the ranges led by the store.
+func (mr *mmaRebalancer) makeRebalancer(s state.State) (*mma.Rebalancer, error) {
+	rebalancer := mma.NewRebalancer(s.Clock())
+	stores := s.Stores()
+	storeIDs := make([]state.StoreID, len(stores))
+	for i, store := range stores {
+		storeIDs[i] = store.StoreID()
+	}
+	descs := s.StoreDescriptors(false /* cached */, storeIDs...)
+	for _, desc := range descs {
+		rebalancer.SetStore(desc)
+	}
+
+	livenessFn := s.NodeLivenessFn()
+	nodeLoads := make(map[roachpb.NodeID]*mma.NodeLoad)
+	var nodeIDs []roachpb.NodeID
+	for _, desc := range descs {
+		nl, ok := nodeLoads[desc.Node.NodeID]
+		if !ok {
+			nl = &mma.NodeLoad{
+				NodeID:      desc.Node.NodeID,
+				CPUCapacity: mr.settings.MMANodeCPUCapacity,
+				Suspect:     livenessFn(desc.Node.NodeID) != livenesspb.NodeLivenessStatus_LIVE,
+				LoadTime:    s.Clock().Now(),
+			}
+			nodeLoads[desc.Node.NodeID] = nl
+			nodeIDs = append(nodeIDs, desc.Node.NodeID)
+		}
+		nl.CPU += mr.cpu(desc.Capacity.QueriesPerSecond)
+		nl.Stores = append(nl.Stores, mma.StoreLoad{
+			StoreID:          desc.StoreID,
+			ByteSize:         desc.Capacity.LogicalBytes,
+			ByteSizeCapacity: desc.Capacity.Capacity,
+			LeaseCount:       int64(desc.Capacity.LeaseCount),
+		})
+	}
+	for _, nodeID := range nodeIDs {
+		if err := rebalancer.ProcessNodeLoad(*nodeLoads[nodeID]); err != nil {
+			return nil, err
+		}
+	}
+
+	var ranges []mma.RangeLoad
+	for _, repl := range s.Replicas(mr.storeID) {
+		if !repl.HoldsLease() {
+			continue
+		}
+		rng, ok := s.Range(repl.Range())
+		if !ok {
+			continue
+		}
+		rl := mma.RangeLoad{
+			RangeID:  roachpb.RangeID(rng.RangeID()),
+			Conf:     *rng.SpanConfig(),
+			CPU:      mr.cpu(s.RangeUsageInfo(rng.RangeID(), mr.storeID).QueriesPerSecond),
+			ByteSize: rng.Size(),
+		}
+		for _, r := range rng.Replicas() {
+			desc := r.Descriptor()
+			rl.Replicas = append(rl.Replicas, mma.RangeReplica{
+				StoreID:       desc.StoreID,
+				ReplicaID:     desc.ReplicaID,
+				Type:          desc.Type,
+				IsLeaseholder: r.HoldsLease(),
+			})
+		}
+		ranges = append(ranges, rl)
+	}
+	if err := rebalancer.ProcessLeaseholderRanges(roachpb.StoreID(mr.storeID), ranges); err != nil {
+		return nil, err
+	}
+	return rebalancer, nil
+}
+
+// cpu returns the cpu attributed to the QPS given, in nanos per second.
+func (mr *mmaRebalancer) cpu(qps float64) int64 {
+	return int64(qps * float64(mr.settings.MMACPUNanosPerQuery))
+}
+
+// relocateOp returns an operation which moves the replica of the range on the
+// removed store to the added store. The lease moves along with the replica if
+// the removed store holds it.
+func (mr *mmaRebalancer) relocateOp(
+	tick time.Time, s state.State, rng state.Range, change mma.Change,
+) (op.ControlledOperation, bool) {
+	addStore, ok := s.Store(state.StoreID(change.AddStoreID))
+	if !ok {
+		return nil, false
+	}
+	leaseholder := roachpb.StoreID(mr.storeID)
+	if leaseholder == change.RemoveStoreID {
+		leaseholder = change.AddStoreID
+	}
+	var voters, nonVoters []roachpb.ReplicationTarget
+	for _, desc := range rng.Descriptor().Replicas().Descriptors() {
+		target := roachpb.ReplicationTarget{NodeID: desc.NodeID, StoreID: desc.StoreID}
+		if desc.StoreID == change.RemoveStoreID {
+			target = roachpb.ReplicationTarget{
+				NodeID:  roachpb.NodeID(addStore.NodeID()),
+				StoreID: change.AddStoreID,
+			}
+		}
+		if !desc.IsAnyVoter() {
+			nonVoters = append(nonVoters, target)
+		} else if target.StoreID == leaseholder {
+			// The lease is transferred to the first voter.
+			voters = append([]roachpb.ReplicationTarget{target}, voters...)
+		} else {
+			voters = append(voters, target)
+		}
+	}
+	return op.NewRelocateRangeOp(
+		tick, rng.Descriptor().StartKey.AsRawKey(), voters, nonVoters, true, /* transferLeaseToFirstVoter */
+	), true
+}
@@ -64,6 +64,12 @@ func (lsc loadSplitConfig) SampleResetDuration() time.Duration {
 	return 0 /* disabled */
 }
 
+// HotKeySamplingEnabled returns whether the heaviest keys should be sampled
+// when the load exceeds the threshold but no split key can be found.
+func (lsc loadSplitConfig) HotKeySamplingEnabled() bool {
+	return false
+}
+
 // SplitDecider implements the LoadSplitter interface.
 type SplitDecider struct {
 	deciders    map[RangeID]*split.Decider
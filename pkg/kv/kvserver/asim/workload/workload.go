@@ -157,6 +157,73 @@ func (rwg *RandomGenerator) Tick(maxTime time.Time) LoadBatch {
 	return ret
 }
 
+// ReplayRate is the observed per-second load against the key Key, typically
+// taken from the range statistics of a running cluster.
+type ReplayRate struct {
+	Key                 int64
+	ReadsPerSecond      float64
+	WritesPerSecond     float64
+	ReadBytesPerSecond  float64
+	WriteBytesPerSecond float64
+}
+
+// ReplayGenerator replays a fixed set of per-key load rates. Unlike the
+// RandomGenerator, there is no randomness involved: every tick generates the
+// load that each key would have observed since the last tick, carrying over
+// any fractional requests to the next tick.
+type ReplayGenerator struct {
+	lastRun time.Time
+	rates   []ReplayRate
+	// readCarry and writeCarry hold the fractional reads and writes which
+	// have not been generated yet, indexed the same as rates.
+	readCarry, writeCarry []float64
+}
+
+// NewReplayGenerator returns a generator that replays the given per-key load
+// rates, starting at start.
+func NewReplayGenerator(start time.Time, rates []ReplayRate) Generator {
+	rates = append([]ReplayRate(nil), rates...)
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Key < rates[j].Key })
+	return &ReplayGenerator{
+		lastRun:    start,
+		rates:      rates,
+		readCarry:  make([]float64, len(rates)),
+		writeCarry: make([]float64, len(rates)),
+	}
+}
+
+// Tick returns the load events up till time tick, from the last time the
+// workload generator was called.
+func (rg *ReplayGenerator) Tick(maxTime time.Time) LoadBatch {
+	elapsed := maxTime.Sub(rg.lastRun).Seconds()
+	if elapsed <= 0 {
+		return LoadBatch{}
+	}
+	rg.lastRun = maxTime
+
+	ret := make(LoadBatch, 0, len(rg.rates))
+	for i, rate := range rg.rates {
+		rg.readCarry[i] += rate.ReadsPerSecond * elapsed
+		rg.writeCarry[i] += rate.WritesPerSecond * elapsed
+		reads, writes := int64(rg.readCarry[i]), int64(rg.writeCarry[i])
+		if reads == 0 && writes == 0 {
+			continue
+		}
+		rg.readCarry[i] -= float64(reads)
+		rg.writeCarry[i] -= float64(writes)
+
+		event := LoadEvent{Key: rate.Key, Reads: reads, Writes: writes}
+		if rate.ReadsPerSecond > 0 {
+			event.ReadSize = int64(float64(reads) * rate.ReadBytesPerSecond / rate.ReadsPerSecond)
+		}
+		if rate.WritesPerSecond > 0 {
+			event.WriteSize = int64(float64(writes) * rate.WriteBytesPerSecond / rate.WritesPerSecond)
+		}
+		ret = append(ret, event)
+	}
+	return ret
+}
+
 // TODO(wenyihu6): Instead of duplicating the key generator logic in simulators,
 // we should directly reuse the code from the repo pkg/workload/(kv|ycsb) to
 // ensure consistent testing.
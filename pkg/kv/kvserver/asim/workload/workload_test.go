@@ -157,3 +157,27 @@ func TestRandWorkloadGenerator(t *testing.T) {
 		require.Equal(t, math.Round(tc.readRatio*100), math.Round((float64(stats.reads)/float64(stats.reads+stats.writes))*100))
 	}
 }
+
+// TestReplayWorkloadGenerator asserts that the replay generator reproduces the
+// given per-key rates, carrying over fractional requests between ticks.
+func TestReplayWorkloadGenerator(t *testing.T) {
+	start := time.Date(2022, 03, 21, 11, 0, 0, 0, time.UTC)
+	gen := NewReplayGenerator(start, []ReplayRate{
+		{Key: 20, ReadsPerSecond: 0.5, ReadBytesPerSecond: 50},
+		{Key: 10, ReadsPerSecond: 10, WritesPerSecond: 2, ReadBytesPerSecond: 1000, WriteBytesPerSecond: 400},
+	})
+
+	// The first second only generates load for the key with whole request
+	// rates, the other key carries over half a read.
+	require.Equal(t, LoadBatch{
+		{Key: 10, Reads: 10, ReadSize: 1000, Writes: 2, WriteSize: 400},
+	}, gen.Tick(start.Add(time.Second)))
+
+	require.Equal(t, LoadBatch{
+		{Key: 10, Reads: 10, ReadSize: 1000, Writes: 2, WriteSize: 400},
+		{Key: 20, Reads: 1, ReadSize: 100},
+	}, gen.Tick(start.Add(2*time.Second)))
+
+	// Ticking without time elapsing generates no load.
+	require.Empty(t, gen.Tick(start.Add(2*time.Second)))
+}
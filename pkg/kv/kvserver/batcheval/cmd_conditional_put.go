@@ -75,6 +75,7 @@ func ConditionalPut(
 			OmitInRangefeeds:               cArgs.OmitInRangefeeds,
 			OriginID:                       h.WriteOptions.GetOriginID(),
 			OriginTimestamp:                originTimestampForValueHeader,
+			ExpireAfter:                    h.WriteOptions.GetExpireAfter(),
 			MaxLockConflicts:               storage.MaxConflictsPerLockConflictError.Get(&cArgs.EvalCtx.ClusterSettings().SV),
 			TargetLockConflictBytes:        storage.TargetBytesPerLockConflictError.Get(&cArgs.EvalCtx.ClusterSettings().SV),
 			Category:                       fs.BatchEvalReadCategory,
@@ -95,5 +96,7 @@ func ConditionalPut(
 	if err != nil {
 		return result.Result{}, err
 	}
-	return result.WithAcquiredLocks(acq), nil
+	res := result.WithAcquiredLocks(acq)
+	res.Local.MVCCExpiration = mvccExpiration(ts, args.Value, opts.ExpireAfter)
+	return res, nil
 }
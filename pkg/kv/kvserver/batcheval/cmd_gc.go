@@ -267,13 +267,20 @@ func GC(
 	// unnecessarily GC'd with high priority again.
 	// We should only do that when we are doing actual cleanup as we want to have
 	// a hint when request is being handled.
-	if len(args.Keys) != 0 || len(args.RangeKeys) != 0 || args.ClearRange != nil {
+	cleanup := len(args.Keys) != 0 || len(args.RangeKeys) != 0 || args.ClearRange != nil
+	if cleanup || args.ScheduleGCFor.IsSet() {
 		sl := MakeStateLoader(cArgs.EvalCtx)
 		hint, err := sl.LoadGCHint(ctx, readWriter)
 		if err != nil {
 			return result.Result{}, err
 		}
-		if hint.UpdateAfterGC(gcThreshold) {
+		updated := cleanup && hint.UpdateAfterGC(gcThreshold)
+		// Schedule a GC run for values which expire later, unless they have
+		// already expired at the GC threshold.
+		if gcThreshold.Less(args.ScheduleGCFor) {
+			updated = hint.ScheduleGCFor(args.ScheduleGCFor) || updated
+		}
+		if updated {
 			// NB: Replicated.State can already contain GCThreshold from above. Make
 			// sure we don't accidentally remove it.
 			if res.Replicated.State == nil {
@@ -64,6 +64,7 @@ func Put(
 		OmitInRangefeeds:               cArgs.OmitInRangefeeds,
 		OriginID:                       h.WriteOptions.GetOriginID(),
 		OriginTimestamp:                h.WriteOptions.GetOriginTimestamp(),
+		ExpireAfter:                    h.WriteOptions.GetExpireAfter(),
 		MaxLockConflicts:               storage.MaxConflictsPerLockConflictError.Get(&cArgs.EvalCtx.ClusterSettings().SV),
 		TargetLockConflictBytes:        storage.TargetBytesPerLockConflictError.Get(&cArgs.EvalCtx.ClusterSettings().SV),
 		Category:                       fs.BatchEvalReadCategory,
@@ -79,5 +80,18 @@ func Put(
 	if err != nil {
 		return result.Result{}, err
 	}
-	return result.WithAcquiredLocks(acq), nil
+	res := result.WithAcquiredLocks(acq)
+	res.Local.MVCCExpiration = mvccExpiration(ts, args.Value, opts.ExpireAfter)
+	return res, nil
+}
+
+// mvccExpiration returns the expiration of a value written at the given
+// timestamp with the given MVCCWriteOptions.ExpireAfter, if any. It is
+// returned in the LocalResult so that the proposer schedules a GC run for the
+// value once it expired.
+func mvccExpiration(ts hlc.Timestamp, value roachpb.Value, expireAfter time.Duration) hlc.Timestamp {
+	if expireAfter <= 0 || ts.IsEmpty() || len(value.RawBytes) == 0 {
+		return hlc.Timestamp{}
+	}
+	return ts.Add(expireAfter.Nanoseconds(), 0)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 	"github.com/kr/pretty"
@@ -51,6 +52,11 @@ type LocalResult struct {
 	// commit fails, or we may accidentally make uncommitted values
 	// live.
 	EndTxns []EndTxnIntents
+	// MVCCExpiration is the earliest expiration of the values written with an
+	// MVCC expiration (see enginepb.MVCCValueHeader.Expiration). The proposer
+	// makes sure that a GC run is scheduled for expired values, which are
+	// accounted as live in the MVCC stats until they are garbage collected.
+	MVCCExpiration hlc.Timestamp
 	// PopulateBarrierResponse will populate a BarrierResponse with the lease
 	// applied index and range descriptor when applied.
 	PopulateBarrierResponse bool
@@ -86,6 +92,7 @@ func (lResult *LocalResult) IsZero() bool {
 		lResult.ResolvedLocks == nil &&
 		lResult.UpdatedTxns == nil &&
 		lResult.EndTxns == nil &&
+		lResult.MVCCExpiration.IsEmpty() &&
 		!lResult.PopulateBarrierResponse &&
 		!lResult.RepopulateSubsumeResponseLAI &&
 		!lResult.GossipFirstRange &&
@@ -454,6 +461,12 @@ func (p *Result) MergeAndDestroy(q Result) error {
 	}
 	q.Local.EndTxns = nil
 
+	if p.Local.MVCCExpiration.IsEmpty() ||
+		(q.Local.MVCCExpiration.IsSet() && q.Local.MVCCExpiration.Less(p.Local.MVCCExpiration)) {
+		p.Local.MVCCExpiration = q.Local.MVCCExpiration
+	}
+	q.Local.MVCCExpiration = hlc.Timestamp{}
+
 	if !p.Local.PopulateBarrierResponse {
 		p.Local.PopulateBarrierResponse = q.Local.PopulateBarrierResponse
 	} else {
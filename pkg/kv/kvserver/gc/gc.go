@@ -265,6 +265,12 @@ type Info struct {
 	ClearRangeSpanOperations int
 	// ClearRangeSpanFailures number of ClearRange requests GC failed to perform.
 	ClearRangeSpanFailures int
+	// PendingExpiration is the earliest expiration above the threshold of the
+	// values with an MVCC expiration (see enginepb.MVCCValueHeader.Expiration).
+	// Expired values are accounted as live in the MVCC stats until they are
+	// garbage collected, so they don't contribute to the GC score, and a GC run
+	// needs to be scheduled for when this timestamp falls below the threshold.
+	PendingExpiration hlc.Timestamp
 }
 
 // RunOptions contains collection of limits that GC run applies when performing operations
@@ -518,6 +524,10 @@ func processReplicatedKeyRange(
 				}
 			}
 
+			if exp := it.pendingExpiration; exp.IsSet() &&
+				(info.PendingExpiration.IsEmpty() || exp.Less(info.PendingExpiration)) {
+				info.PendingExpiration = exp
+			}
 			return b.flushLastBatch(ctx)
 		})
 }
@@ -1134,6 +1144,10 @@ func (b *intentBatcher) maybeFlushPendingIntents(ctx context.Context) error {
 // guaranteed as described above. However if this were the only rule, then if
 // the most recent write was a delete, it would never be removed. Thus, when a
 // deleted value is the most recent before expiration, it can be deleted.
+//
+// Values whose own MVCC expiration (see enginepb.MVCCValueHeader.Expiration)
+// is at or below the threshold are treated like deletes, since reads at or
+// above the threshold treat them as deleted.
 func isGarbage(
 	threshold hlc.Timestamp,
 	cur, next *mvccKeyValue,
@@ -1153,7 +1167,7 @@ func isGarbage(
 		}
 		return true
 	}
-	isDelete := cur.mvccValueIsTombstone
+	isDelete := cur.mvccValueIsTombstone || cur.mvccValueIsExpired
 	if isNewestPoint && !isDelete {
 		return false
 	}
@@ -24,6 +24,11 @@ type gcIterator struct {
 	// object covered by current range key.
 	cachedRangeTombstoneTS  hlc.Timestamp
 	cachedRangeTombstoneKey roachpb.Key
+
+	// pendingExpiration is the earliest expiration above the threshold of the
+	// values seen by the iterator. Such values can only be garbage collected by
+	// a later GC run.
+	pendingExpiration hlc.Timestamp
 }
 
 // TODO(sumeer): change gcIterator to use MVCCValueLenAndIsTombstone(). It
@@ -138,7 +143,7 @@ func (it *gcIterator) fillTo(targetLen int) (ok bool) {
 			}
 			key := it.it.UnsafeKey()
 			var mvccValueLen int
-			var mvccValueIsTombstone bool
+			var mvccValueIsTombstone, mvccValueIsExpired bool
 			var metaValue []byte
 			if key.IsValue() {
 				var err error
@@ -147,6 +152,28 @@ func (it *gcIterator) fillTo(targetLen int) (ok bool) {
 					it.err = err
 					return false
 				}
+				// Values which expired at or below the threshold are garbage
+				// collected like deletion tombstones. The expiration of the others
+				// is remembered to schedule the GC run that can collect them.
+				if !mvccValueIsTombstone {
+					v, err := it.it.UnsafeValue()
+					if err != nil {
+						it.err = err
+						return false
+					}
+					expiration, err := storage.EncodedMVCCValueExpiration(v)
+					if err != nil {
+						it.err = err
+						return false
+					}
+					if expiration.IsSet() {
+						if expiration.LessEq(it.threshold) {
+							mvccValueIsExpired = true
+						} else if it.pendingExpiration.IsEmpty() || expiration.Less(it.pendingExpiration) {
+							it.pendingExpiration = expiration
+						}
+					}
+				}
 			} else {
 				var err error
 				metaValue, err = it.it.UnsafeValue()
@@ -155,7 +182,7 @@ func (it *gcIterator) fillTo(targetLen int) (ok bool) {
 					return false
 				}
 			}
-			it.buf.pushBack(key, mvccValueLen, mvccValueIsTombstone, metaValue, ts)
+			it.buf.pushBack(key, mvccValueLen, mvccValueIsTombstone, mvccValueIsExpired, metaValue, ts)
 		}
 		it.it.Prev()
 	}
@@ -188,12 +215,15 @@ func (it *gcIterator) currentRangeTS() hlc.Timestamp {
 const gcIteratorRingBufSize = 3
 
 type mvccKeyValue struct {
-	// If key.IsValue(), mvccValueLen and mvccValueIsTombstone are populated,
-	// else, metaValue is populated.
+	// If key.IsValue(), mvccValueLen, mvccValueIsTombstone and
+	// mvccValueIsExpired are populated, else, metaValue is populated.
 	key                  storage.MVCCKey
 	mvccValueLen         int
 	mvccValueIsTombstone bool
-	metaValue            []byte
+	// mvccValueIsExpired is set if the value has an expiration at or below the
+	// GC threshold.
+	mvccValueIsExpired bool
+	metaValue          []byte
 }
 
 type gcIteratorRingBuf struct {
@@ -229,6 +259,7 @@ func (b *gcIteratorRingBuf) pushBack(
 	k storage.MVCCKey,
 	mvccValueLen int,
 	mvccValueIsTombstone bool,
+	mvccValueIsExpired bool,
 	metaValue []byte,
 	rangeTS hlc.Timestamp,
 ) {
@@ -245,6 +276,7 @@ func (b *gcIteratorRingBuf) pushBack(
 		key:                  k,
 		mvccValueLen:         mvccValueLen,
 		mvccValueIsTombstone: mvccValueIsTombstone,
+		mvccValueIsExpired:   mvccValueIsExpired,
 		metaValue:            metaValue,
 	}
 	b.firstRangeTombstoneAtOrBelowGCTss[i] = rangeTS
@@ -191,6 +191,54 @@ func TestLockAgeThresholdSetting(t *testing.T) {
 	require.Equal(t, 8, len(gcer.locks))
 }
 
+// TestGCExpiredValues verifies that GC collects values whose MVCC expiration
+// is at or below the threshold and reports the earliest expiration of those
+// that it couldn't collect yet.
+func TestGCExpiredValues(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+
+	value := roachpb.Value{RawBytes: []byte("0123456789")}
+	value.InitChecksum(nil)
+	ts := func(sec int64) hlc.Timestamp { return hlc.Timestamp{WallTime: sec * 1e9} }
+	for _, w := range []struct {
+		key         string
+		ts          hlc.Timestamp
+		expireAfter time.Duration
+	}{
+		{key: "a", ts: ts(1), expireAfter: 2 * time.Second},    // expires at 3
+		{key: "b", ts: ts(1), expireAfter: 20 * time.Second},   // expires at 21
+		{key: "c", ts: ts(2), expireAfter: 10 * time.Second},   // expires at 12
+		{key: "d", ts: ts(1), expireAfter: 0},                  // never expires
+		{key: "e", ts: ts(12), expireAfter: 100 * time.Second}, // expires at 112
+	} {
+		_, err := storage.MVCCPut(ctx, eng, roachpb.Key(w.key), w.ts, value,
+			storage.MVCCWriteOptions{ExpireAfter: w.expireAfter})
+		require.NoError(t, err)
+	}
+
+	desc := roachpb.RangeDescriptor{
+		StartKey: roachpb.RKey("a"),
+		EndKey:   roachpb.RKey("z"),
+	}
+	snap := eng.NewSnapshot()
+	defer snap.Close()
+	gcer := makeFakeGCer()
+	now, threshold := ts(15), ts(5)
+	info, err := Run(ctx, &desc, snap, now, threshold,
+		RunOptions{
+			LockAgeThreshold:    time.Hour,
+			TxnCleanupThreshold: txnCleanupThreshold,
+		}, 10*time.Second, &gcer, gcer.resolveIntents, gcer.resolveIntentsAsync)
+	require.NoError(t, err)
+
+	require.Equal(t, []kvpb.GCRequest_GCKey{{Key: roachpb.Key("a"), Timestamp: ts(1)}}, gcer.pointKeys())
+	require.Equal(t, ts(12), info.PendingExpiration)
+}
+
 func TestIntentCleanupBatching(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -311,6 +311,9 @@ func (n *controllerImpl) AdmitKVWork(
 		Priority:        admissionpb.WorkPriority(ba.AdmissionHeader.Priority),
 		CreateTime:      createTime,
 		BypassAdmission: bypassAdmission,
+		ResourceGroup:   ba.AdmissionHeader.ResourceGroup,
+
+		ResourceGroupDefinition: resourceGroupDefinition(ba.AdmissionHeader),
 	}
 
 	admissionEnabled := true
@@ -489,9 +492,32 @@ func (n *controllerImpl) AdmitRangefeedRequest(
 			Priority:        admissionpb.WorkPriority(request.AdmissionHeader.Priority),
 			CreateTime:      request.AdmissionHeader.CreateTime,
 			BypassAdmission: false,
+			ResourceGroup:   request.AdmissionHeader.ResourceGroup,
+
+			ResourceGroupDefinition: resourceGroupDefinition(request.AdmissionHeader),
 		})
 }
 
+// resourceGroupDefinition returns the definition of the resource group of a
+// request, as resolved by the tenant that issued it, or nil if the request
+// does not carry one.
+func resourceGroupDefinition(h kvpb.AdmissionHeader) *admission.ResourceGroup {
+	if h.ResourceGroupShares == nil {
+		return nil
+	}
+	g := admission.ResourceGroup{
+		Name:      h.ResourceGroup,
+		CPUWeight: max(h.ResourceGroupShares.CPUWeight, 1),
+		IOWeight:  max(h.ResourceGroupShares.IOWeight, 1),
+		CPUBurst:  max(h.ResourceGroupShares.CPUBurst, 0),
+		IOBurst:   max(h.ResourceGroupShares.IOBurst, 0),
+	}
+	if g.Name == "" {
+		g.Name = admission.DefaultResourceGroup
+	}
+	return &g
+}
+
 // SetTenantWeightProvider implements the Controller interface.
 func (n *controllerImpl) SetTenantWeightProvider(
 	provider TenantWeightProvider, stopper *stop.Stopper,
@@ -960,6 +960,18 @@ storage.initial_stats_complete becomes true.
 		Measurement: "Bytes",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaColdStorageBytes = metric.Metadata{
+		Name:        "storage.cold-storage.bytes",
+		Help:        "Size of the sstables placed on cold storage",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaColdStorageReadRetries = metric.Metadata{
+		Name:        "storage.cold-storage.read-retries",
+		Help:        "Number of reads from cold storage that were retried after failing",
+		Measurement: "Retries",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaBlockLoadsInProgress = metric.Metadata{
 		Name:        "storage.block-load.active",
 		Help:        "The number of sstable block loads currently in progress",
@@ -2808,6 +2820,7 @@ type StoreMetrics struct {
 	OverReplicatedRangeCount        *metric.Gauge
 	DecommissioningRangeCount       *metric.Gauge
 	RangeClosedTimestampPolicyCount [ctpb.MAX_CLOSED_TIMESTAMP_POLICY]*metric.Gauge
+	StorageTierLogicalBytes         [numStorageTiers]*metric.Gauge
 
 	// Lease request metrics for successful and failed lease requests. These
 	// count proposals (i.e. it does not matter how many replicas apply the
@@ -2897,6 +2910,8 @@ type StoreMetrics struct {
 	SingleDelIneffectualCount         *metric.Counter
 	SharedStorageBytesRead            *metric.Counter
 	SharedStorageBytesWritten         *metric.Counter
+	ColdStorageBytes                  *metric.Gauge
+	ColdStorageReadRetries            *metric.Counter
 	BlockLoadsInProgress              *metric.Gauge
 	BlockLoadsQueued                  *metric.Counter
 	SecondaryCacheSize                *metric.Gauge
@@ -3517,6 +3532,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		OverReplicatedRangeCount:        metric.NewGauge(metaOverReplicatedRangeCount),
 		DecommissioningRangeCount:       metric.NewGauge(metaDecommissioningRangeCount),
 		RangeClosedTimestampPolicyCount: makePolicyRefresherMetrics(),
+		StorageTierLogicalBytes:         makeStorageTierMetrics(),
 
 		// Lease request metrics.
 		LeaseRequestSuccessCount: metric.NewCounter(metaLeaseRequestSuccessCount),
@@ -3630,6 +3646,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		SingleDelIneffectualCount:         metric.NewCounter(metaStorageSingleDelIneffectualCount),
 		SharedStorageBytesRead:            metric.NewCounter(metaSharedStorageBytesRead),
 		SharedStorageBytesWritten:         metric.NewCounter(metaSharedStorageBytesWritten),
+		ColdStorageBytes:                  metric.NewGauge(metaColdStorageBytes),
+		ColdStorageReadRetries:            metric.NewCounter(metaColdStorageReadRetries),
 		BlockLoadsInProgress:              metric.NewGauge(metaBlockLoadsInProgress),
 		BlockLoadsQueued:                  metric.NewCounter(metaBlockLoadsQueued),
 		SecondaryCacheSize:                metric.NewGauge(metaSecondaryCacheSize),
@@ -4094,6 +4112,8 @@ func (sm *StoreMetrics) updateEngineMetrics(m storage.Metrics) {
 	sm.SingleDelIneffectualCount.Update(m.SingleDelIneffectualCount)
 	sm.SharedStorageBytesRead.Update(m.SharedStorageReadBytes)
 	sm.SharedStorageBytesWritten.Update(m.SharedStorageWriteBytes)
+	sm.ColdStorageBytes.Update(m.ColdStorageBytes)
+	sm.ColdStorageReadRetries.Update(m.ColdStorageReadRetries)
 	sm.BlockLoadsInProgress.Update(m.BlockLoadsInProgress)
 	sm.BlockLoadsQueued.Update(m.BlockLoadsQueued)
 	sm.SecondaryCacheSize.Update(m.SecondaryCacheMetrics.Size)
@@ -4327,6 +4347,24 @@ func makePolicyRefresherMetrics() [ctpb.MAX_CLOSED_TIMESTAMP_POLICY]*metric.Gaug
 	return policyGauges
 }
 
+// numStorageTiers is the number of roachpb.StorageTier values.
+const numStorageTiers = roachpb.StorageTier_COLD + 1
+
+func makeStorageTierMetrics() [numStorageTiers]*metric.Gauge {
+	var tierGauges [numStorageTiers]*metric.Gauge
+	for tier := roachpb.StorageTier_DEFAULT; tier < numStorageTiers; tier++ {
+		meta := metric.Metadata{
+			Name: fmt.Sprintf("storage.tier.%s.logical_bytes", strings.ToLower(tier.String())),
+			Help: fmt.Sprintf("Logical bytes of the replicas on the store whose "+
+				"storage_tier is %s", tier.String()),
+			Measurement: "Storage",
+			Unit:        metric.Unit_BYTES,
+		}
+		tierGauges[tier] = metric.NewGauge(meta)
+	}
+	return tierGauges
+}
+
 func storageLevelMetricMetadata(
 	name, helpTpl, measurement string, unit metric.Unit,
 ) [7]metric.Metadata {
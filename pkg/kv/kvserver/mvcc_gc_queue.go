@@ -630,6 +630,45 @@ func (r *replicaGCer) GC(
 	return r.send(ctx, req)
 }
 
+// scheduleGCFor updates the GC hint of the range to schedule a GC run for when
+// the given timestamp falls below the GC threshold.
+func (r *replicaGCer) scheduleGCFor(ctx context.Context, ts hlc.Timestamp) error {
+	req := r.template()
+	req.ScheduleGCFor = ts
+	return r.send(ctx, req)
+}
+
+// maybeScheduleGCForExpiration makes sure that a GC run is scheduled for
+// values written with an MVCC expiration (see
+// enginepb.MVCCValueHeader.Expiration). Expired values are accounted as live
+// in the MVCC stats until they are garbage collected, so they don't contribute
+// to the GC score and are instead collected by GC runs scheduled through the
+// range's GC hint. Every GC run schedules the next one for the earliest
+// expiration that it could not collect yet, so a request is only sent if the
+// hint doesn't schedule a GC run already.
+func (r *Replica) maybeScheduleGCForExpiration(ctx context.Context, expiration hlc.Timestamp) {
+	r.mu.RLock()
+	scheduled := r.shMu.state.GCHint != nil && r.shMu.state.GCHint.GCTimestamp.IsSet()
+	r.mu.RUnlock()
+	if scheduled || !r.schedulingGCForExpiration.CompareAndSwap(false, true) {
+		return
+	}
+	ctx = r.AnnotateCtx(context.Background())
+	if err := r.store.stopper.RunAsyncTask(ctx, "schedule-gc-for-expiration", func(ctx context.Context) {
+		defer r.schedulingGCForExpiration.Store(false)
+		gcer := replicaGCer{
+			repl:                r,
+			admissionController: r.store.cfg.KVAdmissionController,
+			storeID:             r.store.StoreID(),
+		}
+		if err := gcer.scheduleGCFor(ctx, expiration); err != nil {
+			log.VErrEventf(ctx, 2, "failed to schedule GC for expired values: %v", err)
+		}
+	}); err != nil {
+		r.schedulingGCForExpiration.Store(false)
+	}
+}
+
 // process first determines whether the replica can run MVCC GC given its view
 // of the protected timestamp subsystem and its current state. This check also
 // determines the most recent time which can be used for the purposes of
@@ -763,6 +802,19 @@ func (mgcq *mvccGCQueue) process(
 	if err != nil {
 		return false, err
 	}
+	if info.PendingExpiration.IsSet() {
+		// Values with an MVCC expiration that hasn't passed the GC threshold yet
+		// don't contribute to the GC score, so schedule the run that collects
+		// them.
+		gcer := replicaGCer{
+			repl:                repl,
+			admissionController: mgcq.store.cfg.KVAdmissionController,
+			storeID:             mgcq.store.StoreID(),
+		}
+		if err := gcer.scheduleGCFor(ctx, info.PendingExpiration); err != nil {
+			return false, err
+		}
+	}
 
 	scoreAfter := makeMVCCGCQueueScore(
 		ctx, repl, repl.store.Clock().Now(), lastGC, conf.TTL(), canAdvanceGCThreshold)
@@ -104,7 +104,7 @@ func runBenchmarkRangefeed(b *testing.B, opts benchmarkRangefeedOpts) {
 		const withFiltering = false
 		streams[i] = &noopStream{ctx: ctx, done: make(chan *kvpb.Error, 1)}
 		ok, _, _ := p.Register(ctx, span, hlc.MinTimestamp, nil,
-			withDiff, withFiltering, false, /* withOmitRemote */
+			withDiff, withFiltering, false /* withOmitRemote */, false /* withTxnIDs */, 0, /* expirationLookback */
 			streams[i])
 		require.True(b, ok)
 	}
@@ -78,6 +78,8 @@ func newBufferedRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	withTxnIDs bool,
+	expirationLookback time.Duration,
 	bufferSz int,
 	blockWhenFull bool,
 	metrics *Metrics,
@@ -92,6 +94,8 @@ func newBufferedRegistration(
 			withDiff:               withDiff,
 			withFiltering:          withFiltering,
 			withOmitRemote:         withOmitRemote,
+			withTxnIDs:             withTxnIDs,
+			expirationLookback:     expirationLookback,
 			removeRegFromProcessor: removeRegFromProcessor,
 		},
 		metrics:       metrics,
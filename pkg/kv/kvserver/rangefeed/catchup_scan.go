@@ -65,10 +65,20 @@ type CatchUpIterator struct {
 	startTime hlc.Timestamp // exclusive
 	pacer     *admission.Pacer
 	OnEmit    func(key, endKey roachpb.Key, ts hlc.Timestamp, vh enginepb.MVCCValueHeader)
+
+	// expirationLookback, if positive, makes the scan emit deletions for values
+	// that reached their MVCC expiration after startTime. The iterator then also
+	// visits the versions written up to expirationLookback before startTime.
+	expirationLookback time.Duration
+	// ExpirationsUpTo is the timestamp up to which the expirations of values
+	// that were not overwritten are emitted by the catch-up scan; the ones above
+	// it are left to the processor. It is set by the replica, under raftMu.
+	ExpirationsUpTo hlc.Timestamp
 }
 
 // NewCatchUpIterator returns a CatchUpIterator for the given Reader over the
-// given key/time span. startTime is exclusive.
+// given key/time span. startTime is exclusive. expirationLookback, if
+// positive, opts the scan into deletions for expired values.
 //
 // NB: startTime is exclusive, i.e. the first possible event will be emitted at
 // Timestamp.Next().
@@ -77,15 +87,24 @@ func NewCatchUpIterator(
 	reader storage.Reader,
 	span roachpb.Span,
 	startTime hlc.Timestamp,
+	expirationLookback time.Duration,
 	closer func(),
 	pacer *admission.Pacer,
 ) (*CatchUpIterator, error) {
+	iterStartTime := startTime
+	if expirationLookback > 0 {
+		// Values that expire above startTime may have been written below it.
+		iterStartTime = startTime.Add(-expirationLookback.Nanoseconds(), 0)
+		if iterStartTime.WallTime < 0 {
+			iterStartTime = hlc.Timestamp{}
+		}
+	}
 	iter, err := storage.NewMVCCIncrementalIterator(ctx, reader,
 		storage.MVCCIncrementalIterOptions{
 			KeyTypes:  storage.IterKeyTypePointsAndRanges,
 			StartKey:  span.Key,
 			EndKey:    span.EndKey,
-			StartTime: startTime,
+			StartTime: iterStartTime,
 			EndTime:   hlc.MaxTimestamp,
 			// We want to emit intents rather than error
 			// (the default behavior) so that we can skip
@@ -98,11 +117,12 @@ func NewCatchUpIterator(
 		return nil, err
 	}
 	return &CatchUpIterator{
-		simpleCatchupIter: iter,
-		close:             closer,
-		span:              span,
-		startTime:         startTime,
-		pacer:             pacer,
+		simpleCatchupIter:  iter,
+		close:              closer,
+		span:               span,
+		startTime:          startTime,
+		pacer:              pacer,
+		expirationLookback: expirationLookback,
 	}, nil
 }
 
@@ -133,6 +153,12 @@ type outputEventFn func(e *kvpb.RangeFeedEvent) error
 // keys a@6, a@4, and b@2, the emitted order is [a-f)@3,[a-f)@5,a@4,a@6,b@2 because
 // the start key "a" is ordered before all of the timestamped point keys.
 //
+// If the iterator was created with an expiration lookback, a version that
+// reached its MVCC expiration after the starting timestamp is followed by a
+// deletion at its expiration timestamp, unless it was overwritten or deleted
+// before expiring. For versions that were not overwritten, this is only done
+// up to ExpirationsUpTo.
+//
 // TODO(sumeer): ctx is not used for SeekGE and Next. Fix by adding a method
 // to SimpleMVCCIterator to replace the context.
 func (i *CatchUpIterator) CatchUpScan(
@@ -143,6 +169,7 @@ func (i *CatchUpIterator) CatchUpScan(
 	withOmitRemote bool,
 ) error {
 	var a bufalloc.ByteAllocator
+	withExpirations := i.expirationLookback > 0
 	// MVCCIterator will encounter historical values for each key in
 	// reverse-chronological order. To output in chronological order, store
 	// events for the same key until a different key is encountered, then output
@@ -165,6 +192,9 @@ func (i *CatchUpIterator) CatchUpScan(
 	// versions of each key that are after the registration's startTS, so we
 	// can't use NextKey.
 	var lastKey roachpb.Key
+	// newerTS is the timestamp of the version of lastKey that was visited
+	// before the current one, if any.
+	var newerTS hlc.Timestamp
 	var meta enginepb.MVCCMetadata
 	i.SeekGE(storage.MVCCKey{Key: i.span.Key})
 
@@ -284,7 +314,7 @@ func (i *CatchUpIterator) CatchUpScan(
 		// (exclusive) starting timestamp.
 		ts := unsafeKey.Timestamp
 		ignore := ts.LessEq(i.startTime)
-		if ignore && !withDiff {
+		if ignore && !withDiff && !withExpirations {
 			// Skip all the way to the next key.
 			// NB: fast-path to avoid value copy when !r.withDiff.
 			i.NextKey()
@@ -299,9 +329,36 @@ func (i *CatchUpIterator) CatchUpScan(
 				return err
 			}
 			a, lastKey = a.Copy(unsafeKey.Key, 0)
+			newerTS = hlc.Timestamp{}
 		}
 		key := lastKey
 
+		// If this version expired after the starting timestamp, emit a deletion
+		// at its expiration before the version itself (reorderBuf is output in
+		// reverse). The deletion is only emitted if the version was neither
+		// overwritten nor deleted by an MVCC range tombstone before expiring, and
+		// if it was not overwritten at all, only up to ExpirationsUpTo. Like the
+		// version itself, it is subject to filtering.
+		if exp := mvccVal.Expiration; withExpirations && i.startTime.Less(exp) &&
+			!(mvccVal.OmitInRangefeeds && withFiltering) && !(mvccVal.OriginID != 0 && withOmitRemote) {
+			upper, expired := newerTS, exp.Less(newerTS)
+			if newerTS.IsEmpty() {
+				upper, expired = hlc.MaxTimestamp, exp.LessEq(i.ExpirationsUpTo)
+			}
+			if expired {
+				rangeKeys := i.RangeKeysIgnoringTime()
+				if rangeKeys.IsEmpty() || !rangeKeys.HasBetween(ts, upper) {
+					var event kvpb.RangeFeedEvent
+					event.MustSetValue(&kvpb.RangeFeedValue{
+						Key:   key,
+						Value: roachpb.Value{Timestamp: exp},
+					})
+					reorderBuf = append(reorderBuf, event)
+				}
+			}
+		}
+		newerTS = ts
+
 		// INVARIANT: !ignore || withDiff
 		//
 		// Cases:
@@ -45,7 +45,7 @@ func runCatchUpBenchmark(b *testing.B, emk engineMaker, opts benchOptions) (numE
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		func() {
-			iter, err := rangefeed.NewCatchUpIterator(ctx, eng, span, opts.ts, nil, nil)
+			iter, err := rangefeed.NewCatchUpIterator(ctx, eng, span, opts.ts, 0 /* expirationLookback */, nil, nil)
 			if err != nil {
 				b.Fatal(err)
 			}
@@ -8,6 +8,7 @@ package rangefeed
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
@@ -152,7 +153,7 @@ func TestCatchupScan(t *testing.T) {
 		testutils.RunTrueAndFalse(t, "withDiff", func(t *testing.T, withDiff bool) {
 			testutils.RunTrueAndFalse(t, "withFiltering", func(t *testing.T, withFiltering bool) {
 				span := roachpb.Span{Key: testKey1, EndKey: roachpb.KeyMax}
-				iter, err := NewCatchUpIterator(ctx, eng, span, ts1, nil, nil)
+				iter, err := NewCatchUpIterator(ctx, eng, span, ts1, 0 /* expirationLookback */, nil, nil)
 				require.NoError(t, err)
 				defer iter.Close()
 				var events []kvpb.RangeFeedValue
@@ -230,7 +231,7 @@ func TestCatchupScanOriginID(t *testing.T) {
 
 	testutils.RunTrueAndFalse(t, "withOmitRmote", func(t *testing.T, omitRemote bool) {
 		span := roachpb.Span{Key: a1.Key.Key, EndKey: roachpb.KeyMax}
-		iter, err := NewCatchUpIterator(ctx, eng, span, exclusiveStartTime, nil, nil)
+		iter, err := NewCatchUpIterator(ctx, eng, span, exclusiveStartTime, 0 /* expirationLookback */, nil, nil)
 		require.NoError(t, err)
 		defer iter.Close()
 		var events []kvpb.RangeFeedValue
@@ -265,7 +266,7 @@ func TestCatchupScanInlineError(t *testing.T) {
 
 	// Run a catchup scan across the span and watch it error.
 	span := roachpb.Span{Key: keys.LocalMax, EndKey: keys.MaxKey}
-	iter, err := NewCatchUpIterator(ctx, eng, span, hlc.Timestamp{}, nil, nil)
+	iter, err := NewCatchUpIterator(ctx, eng, span, hlc.Timestamp{}, 0 /* expirationLookback */, nil, nil)
 	require.NoError(t, err)
 	defer iter.Close()
 
@@ -309,7 +310,7 @@ func TestCatchupScanSeesOldIntent(t *testing.T) {
 
 	// Run a catchup scan across the span and watch it succeed.
 	span := roachpb.Span{Key: keys.LocalMax, EndKey: keys.MaxKey}
-	iter, err := NewCatchUpIterator(ctx, eng, span, tsCutoff, nil, nil)
+	iter, err := NewCatchUpIterator(ctx, eng, span, tsCutoff, 0 /* expirationLookback */, nil, nil)
 	require.NoError(t, err)
 	defer iter.Close()
 
@@ -323,3 +324,77 @@ func TestCatchupScanSeesOldIntent(t *testing.T) {
 		"e": {},
 	}, keys)
 }
+
+// TestCatchupScanExpirations tests that a catch-up scan with an expiration
+// lookback emits deletions for the values that expired after its starting
+// timestamp, and only for those.
+func TestCatchupScanExpirations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMemForTesting(storage.If(smallEngineBlocks, storage.BlockSize(1)))
+	defer eng.Close()
+
+	ts := func(wallTime int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wallTime}
+	}
+	put := func(key string, wallTime int64, val string, expireAfter time.Duration) {
+		_, err := storage.MVCCPut(ctx, eng, roachpb.Key(key), ts(wallTime),
+			roachpb.MakeValueFromString(val), storage.MVCCWriteOptions{ExpireAfter: expireAfter})
+		require.NoError(t, err)
+	}
+
+	// a: written below the start timestamp, expires at 15.
+	put("a", 5, "a5", 10)
+	// b: expires at 17, overwritten at 20.
+	put("b", 12, "b12", 5)
+	put("b", 20, "b20", 0)
+	// c: expires at 42, above ExpirationsUpTo.
+	put("c", 12, "c12", 30)
+	// d: overwritten at 15, before it expires at 17.
+	put("d", 12, "d12", 5)
+	put("d", 15, "d15", 0)
+	// e: expires at 6, below the start timestamp.
+	put("e", 1, "e1", 5)
+	// f: deleted by an MVCC range tombstone at 12, before it expires at 15.
+	put("f", 5, "f5", 10)
+	require.NoError(t, eng.PutMVCCRangeKey(storage.MVCCRangeKey{
+		StartKey: roachpb.Key("f"), EndKey: roachpb.Key("g"), Timestamp: ts(12),
+	}, storage.MVCCValue{}))
+
+	span := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("z")}
+	iter, err := NewCatchUpIterator(ctx, eng, span, ts(10), 20 /* expirationLookback */, nil, nil)
+	require.NoError(t, err)
+	defer iter.Close()
+	iter.ExpirationsUpTo = ts(30)
+
+	type event struct {
+		key, val, prev string
+		ts             int64
+	}
+	var events []event
+	require.NoError(t, iter.CatchUpScan(ctx, func(e *kvpb.RangeFeedEvent) error {
+		if e.Val == nil {
+			return nil
+		}
+		ev := event{key: string(e.Val.Key), ts: e.Val.Value.Timestamp.WallTime}
+		if e.Val.Value.IsPresent() {
+			ev.val = string(e.Val.Value.ValueBytes())
+		}
+		if e.Val.PrevValue.IsPresent() {
+			ev.prev = string(e.Val.PrevValue.ValueBytes())
+		}
+		events = append(events, ev)
+		return nil
+	}, true /* withDiff */, false /* withFiltering */, false /* withOmitRemote */))
+	require.Equal(t, []event{
+		{key: "a", ts: 15, prev: "a5"},
+		{key: "b", ts: 12, val: "b12"},
+		{key: "b", ts: 17, prev: "b12"},
+		{key: "b", ts: 20, val: "b20"},
+		{key: "c", ts: 12, val: "c12"},
+		{key: "d", ts: 12, val: "d12"},
+		{key: "d", ts: 15, val: "d15", prev: "d12"},
+	}, events)
+}
@@ -27,6 +27,7 @@ const (
 	mvccCommitIntentOp = int64(unsafe.Sizeof(enginepb.MVCCCommitIntentOp{}))
 	mvccAbortIntentOp  = int64(unsafe.Sizeof(enginepb.MVCCAbortIntentOp{}))
 	mvccAbortTxnOp     = int64(unsafe.Sizeof(enginepb.MVCCAbortTxnOp{}))
+	mvccExpireValueOp  = int64(unsafe.Sizeof(enginepb.MVCCExpireValueOp{}))
 
 	eventOverhead = int64(unsafe.Sizeof(&event{})) + int64(unsafe.Sizeof(event{}))
 
@@ -116,6 +117,19 @@ func deleteRangeOpMemUsage(startKey, endKey roachpb.Key) int64 {
 	return currMemUsage
 }
 
+// Pointer to the MVCCExpireValueOp was already accounted in mvccLogicalOp in
+// the caller. expireValueOpMemUsage accounts for the memory usage of
+// MVCCExpireValueOp.
+func expireValueOpMemUsage(key roachpb.Key, prevValue []byte) int64 {
+	// MVCCExpireValueOp has Key, Timestamp, and PrevValue. Only Key and
+	// PrevValue has underlying memory usage in []byte. Timestamp has no
+	// underlying data and was already accounted in MVCCExpireValueOp.
+	currMemUsage := mvccExpireValueOp
+	currMemUsage += int64(cap(key))
+	currMemUsage += int64(cap(prevValue))
+	return currMemUsage
+}
+
 // Pointer to the MVCCWriteIntentOp was already accounted in mvccLogicalOp in
 // the caller. writeIntentOpMemUsage accounts for the memory usage of
 // MVCCWriteIntentOp.
@@ -194,6 +208,8 @@ func opsCurrMemUsage(ops opsEvent) int64 {
 			currMemUsage += writeValueOpMemUsage(t.Key, t.Value, t.PrevValue)
 		case *enginepb.MVCCDeleteRangeOp:
 			currMemUsage += deleteRangeOpMemUsage(t.StartKey, t.EndKey)
+		case *enginepb.MVCCExpireValueOp:
+			currMemUsage += expireValueOpMemUsage(t.Key, t.PrevValue)
 		case *enginepb.MVCCWriteIntentOp:
 			currMemUsage += writeIntentOpMemUsage(t.TxnID, t.TxnKey)
 		case *enginepb.MVCCUpdateIntentOp:
@@ -225,7 +241,8 @@ func (e *event) String() string {
 		for _, op := range e.ops {
 			switch t := op.GetValue().(type) {
 			case *enginepb.MVCCWriteValueOp, *enginepb.MVCCDeleteRangeOp, *enginepb.MVCCWriteIntentOp,
-				*enginepb.MVCCUpdateIntentOp, *enginepb.MVCCCommitIntentOp, *enginepb.MVCCAbortIntentOp, *enginepb.MVCCAbortTxnOp:
+				*enginepb.MVCCUpdateIntentOp, *enginepb.MVCCCommitIntentOp, *enginepb.MVCCAbortIntentOp, *enginepb.MVCCAbortTxnOp,
+				*enginepb.MVCCExpireValueOp:
 				str.WriteString(fmt.Sprintf("op: %T\n", t))
 			default:
 				str.WriteString("unknown logical op")
@@ -133,7 +133,7 @@ func TestEventSizeCalculation(t *testing.T) {
 			expectedCurrMemUsage: int64(241),
 			actualCurrMemUsage: eventOverhead + mvccLogicalOp + mvccWriteValueOp +
 				int64(cap(key)) + int64(cap(value)) + int64(cap(prevValue)),
-			expectedFutureMemUsage: int64(209),
+			expectedFutureMemUsage: int64(233),
 			actualFutureMemUsage: futureEventBaseOverhead + rangefeedValueOverhead +
 				int64(cap(key)) + int64(cap(value)) + int64(cap(prevValue)),
 		},
@@ -145,7 +145,7 @@ func TestEventSizeCalculation(t *testing.T) {
 			expectedCurrMemUsage: int64(202),
 			actualCurrMemUsage: eventOverhead + mvccLogicalOp + mvccDeleteRangeOp +
 				int64(cap(startKey)) + int64(cap(endKey)),
-			expectedFutureMemUsage: int64(202),
+			expectedFutureMemUsage: int64(226),
 			actualFutureMemUsage: futureEventBaseOverhead + rangefeedValueOverhead +
 				int64(cap(startKey)) + int64(cap(endKey)),
 		},
@@ -178,7 +178,7 @@ func TestEventSizeCalculation(t *testing.T) {
 			expectedCurrMemUsage: int64(273),
 			actualCurrMemUsage: eventOverhead + mvccLogicalOp + mvccCommitIntentOp +
 				int64(cap(txnID)) + int64(cap(key)) + int64(cap(value)) + int64(cap(prevValue)),
-			expectedFutureMemUsage: int64(209),
+			expectedFutureMemUsage: int64(233),
 			actualFutureMemUsage: futureEventBaseOverhead + rangefeedValueOverhead +
 				int64(cap(key)) + int64(cap(value)) + int64(cap(prevValue)),
 		},
@@ -6,6 +6,8 @@
 package rangefeed
 
 import (
+	"time"
+
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 )
@@ -15,14 +17,19 @@ import (
 // It can be used to avoid performing extra work to provide the Processor with
 // information which will be ignored.
 type Filter struct {
-	needPrevVals interval.RangeGroup
-	needVals     interval.RangeGroup
+	needPrevVals    interval.RangeGroup
+	needVals        interval.RangeGroup
+	needExpirations interval.RangeGroup
+	// expirationLookback is the largest expiration lookback of the
+	// registrations that asked for expirations.
+	expirationLookback time.Duration
 }
 
 func newFilterFromRegistry(reg *registry) *Filter {
 	f := &Filter{
-		needPrevVals: interval.NewRangeList(),
-		needVals:     interval.NewRangeList(),
+		needPrevVals:    interval.NewRangeList(),
+		needVals:        interval.NewRangeList(),
+		needExpirations: interval.NewRangeList(),
 	}
 	reg.tree.Do(func(i interval.Interface) (done bool) {
 		r := i.(registration)
@@ -30,6 +37,10 @@ func newFilterFromRegistry(reg *registry) *Filter {
 			f.needPrevVals.Add(r.Range())
 		}
 		f.needVals.Add(r.Range())
+		if lookback := r.getExpirationLookback(); lookback > 0 {
+			f.needExpirations.Add(r.Range())
+			f.expirationLookback = max(f.expirationLookback, lookback)
+		}
 		return false
 	})
 	return f
@@ -48,3 +59,16 @@ func (r *Filter) NeedPrevVal(s roachpb.Span) bool {
 func (r *Filter) NeedVal(s roachpb.Span) bool {
 	return r.needVals.Overlaps(s.AsRange())
 }
+
+// NeedExpirations returns whether the Processor requires MVCCExpireValueOp
+// operations over the specified key span.
+func (r *Filter) NeedExpirations(s roachpb.Span) bool {
+	return r.needExpirations.Overlaps(s.AsRange())
+}
+
+// ExpirationLookback returns how far below an expiration timestamp the values
+// expiring at it may have been written, for the registrations that require
+// MVCCExpireValueOp operations. It is zero if there are none.
+func (r *Filter) ExpirationLookback() time.Duration {
+	return r.expirationLookback
+}
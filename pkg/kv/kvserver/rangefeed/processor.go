@@ -193,6 +193,8 @@ type Processor interface {
 		withDiff bool,
 		withFiltering bool,
 		withOmitRemote bool,
+		withTxnIDs bool,
+		expirationLookback time.Duration,
 		stream Stream,
 	) (bool, Disconnector, *Filter)
 
@@ -300,6 +302,9 @@ type syncEvent struct {
 type logicalOpMetadata struct {
 	omitInRangefeeds bool
 	originID         uint32
+	// expiration is set for the deletions synthesized when a value reaches its
+	// MVCC expiration.
+	expiration bool
 }
 
 // IntentScannerConstructor is used to construct an IntentScanner. It
@@ -177,6 +177,17 @@ func rangeFeedValue(key roachpb.Key, val roachpb.Value) *kvpb.RangeFeedEvent {
 	return rangeFeedValueWithPrev(key, val, roachpb.Value{})
 }
 
+func rangeFeedValueWithTxn(
+	key roachpb.Key, val roachpb.Value, txnID uuid.UUID, txnWriteOrdinal int32,
+) *kvpb.RangeFeedEvent {
+	return makeRangeFeedEvent(&kvpb.RangeFeedValue{
+		Key:             key,
+		Value:           val,
+		TxnID:           txnID,
+		TxnWriteOrdinal: txnWriteOrdinal,
+	})
+}
+
 func rangeFeedCheckpoint(span roachpb.Span, ts hlc.Timestamp) *kvpb.RangeFeedEvent {
 	return makeRangeFeedEvent(&kvpb.RangeFeedCheckpoint{
 		Span:       span,
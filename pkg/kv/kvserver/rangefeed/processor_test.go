@@ -69,6 +69,8 @@ func TestProcessorBasic(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r1Stream),
 		)
 		require.True(t, r1OK)
@@ -203,6 +205,8 @@ func TestProcessorBasic(t *testing.T) {
 			true,  /* withDiff */
 			true,  /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r2Stream),
 		)
 		require.True(t, r2OK)
@@ -315,6 +319,8 @@ func TestProcessorBasic(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r3Stream),
 		)
 		require.True(t, r30K)
@@ -336,6 +342,8 @@ func TestProcessorBasic(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r4Stream),
 		)
 		require.False(t, r4OK)
@@ -361,6 +369,8 @@ func TestProcessorOmitRemote(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r1Stream),
 		)
 		require.True(t, r1OK)
@@ -386,6 +396,8 @@ func TestProcessorOmitRemote(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			true,  /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r2Stream),
 		)
 		require.True(t, r2OK)
@@ -422,6 +434,96 @@ func TestProcessorOmitRemote(t *testing.T) {
 	})
 }
 
+// TestProcessorTxnWriteOrdinals tests that committed values carry the ID of
+// their transaction and the ordinal of the write among the transaction's
+// values published by the range, to the registrations that requested them.
+func TestProcessorTxnWriteOrdinals(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutils.RunValues(t, "feed type", testTypes, func(t *testing.T, rt rangefeedTestType) {
+		p, h, stopper := newTestProcessor(t, withRangefeedTestType(rt))
+		ctx := context.Background()
+		defer stopper.Stop(ctx)
+
+		p.ForwardClosedTS(ctx, hlc.Timestamp{WallTime: 1})
+
+		r1Stream := newTestStream()
+		r1OK, _, _ := p.Register(
+			r1Stream.ctx,
+			roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("z")},
+			hlc.Timestamp{WallTime: 1},
+			nil,   /* catchUpIter */
+			false, /* withDiff */
+			false, /* withFiltering */
+			false, /* withOmitRemote */
+			true,  /* withTxnIDs */
+			0,     /* expirationLookback */
+			h.toBufferedStreamIfNeeded(r1Stream),
+		)
+		require.True(t, r1OK)
+		// The second registration does not request transaction IDs.
+		r2Stream := newTestStream()
+		r2OK, _, _ := p.Register(
+			r2Stream.ctx,
+			roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("z")},
+			hlc.Timestamp{WallTime: 1},
+			nil,   /* catchUpIter */
+			false, /* withDiff */
+			false, /* withFiltering */
+			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
+			h.toBufferedStreamIfNeeded(r2Stream),
+		)
+		require.True(t, r2OK)
+		h.syncEventAndRegistrations()
+		r1Stream.GetAndClearEvents()
+		r2Stream.GetAndClearEvents()
+
+		ts := hlc.Timestamp{WallTime: 10}
+		val := func(v string) roachpb.Value {
+			return roachpb.Value{RawBytes: []byte(v), Timestamp: ts}
+		}
+		txn1 := uuid.MakeV4()
+		p.ConsumeLogicalOps(ctx,
+			writeIntentOp(txn1, ts),
+			writeIntentOp(txn1, ts),
+			writeIntentOp(txn1, ts),
+		)
+		// Commit the first two intents together and the last one separately.
+		// The ordinal keeps counting until all of the intents are resolved.
+		p.ConsumeLogicalOps(ctx,
+			commitIntentOpWithKV(txn1, roachpb.Key("b"), ts, []byte("v1"), false /* omitInRangefeeds */, 0 /* originID */),
+			commitIntentOpWithKV(txn1, roachpb.Key("c"), ts, []byte("v2"), false /* omitInRangefeeds */, 0 /* originID */),
+		)
+		p.ConsumeLogicalOps(ctx,
+			commitIntentOpWithKV(txn1, roachpb.Key("d"), ts, []byte("v3"), false /* omitInRangefeeds */, 0 /* originID */),
+		)
+		// A non-transactional write carries neither an ID nor an ordinal.
+		p.ConsumeLogicalOps(ctx, writeValueOpWithKV(roachpb.Key("e"), ts, []byte("v4")))
+		h.syncEventAndRegistrations()
+		require.Equal(t,
+			[]*kvpb.RangeFeedEvent{
+				rangeFeedValueWithTxn(roachpb.Key("b"), val("v1"), txn1, 1 /* txnWriteOrdinal */),
+				rangeFeedValueWithTxn(roachpb.Key("c"), val("v2"), txn1, 2 /* txnWriteOrdinal */),
+				rangeFeedValueWithTxn(roachpb.Key("d"), val("v3"), txn1, 3 /* txnWriteOrdinal */),
+				rangeFeedValue(roachpb.Key("e"), val("v4")),
+			},
+			r1Stream.GetAndClearEvents(),
+		)
+		require.Equal(t,
+			[]*kvpb.RangeFeedEvent{
+				rangeFeedValue(roachpb.Key("b"), val("v1")),
+				rangeFeedValue(roachpb.Key("c"), val("v2")),
+				rangeFeedValue(roachpb.Key("d"), val("v3")),
+				rangeFeedValue(roachpb.Key("e"), val("v4")),
+			},
+			r2Stream.GetAndClearEvents(),
+		)
+		h.syncEventC()
+		require.Equal(t, 0, h.rts.intentQ.Len())
+	})
+}
+
 // TestProcessorSlowConsumer tests that buffered registration will drop events
 // and properly disconnect the stream when the buffer capacity exceeds. This
 // doesn't apply to unbuffered registrations.
@@ -443,6 +545,8 @@ func TestProcessorSlowConsumer(t *testing.T) {
 				false, /* withDiff */
 				false, /* withFiltering */
 				false, /* withOmitRemote */
+				false, /* withTxnIDs */
+				0,     /* expirationLookback */
 				h.toBufferedStreamIfNeeded(r1Stream),
 			)
 			r2Stream := newTestStream()
@@ -454,6 +558,8 @@ func TestProcessorSlowConsumer(t *testing.T) {
 				false, /* withDiff */
 				false, /* withFiltering */
 				false, /* withOmitRemote */
+				false, /* withTxnIDs */
+				0,     /* expirationLookback */
 				h.toBufferedStreamIfNeeded(r2Stream),
 			)
 			h.syncEventAndRegistrations()
@@ -549,6 +655,8 @@ func TestProcessorMemoryBudgetExceeded(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r1Stream),
 		)
 		h.syncEventAndRegistrations()
@@ -604,6 +712,8 @@ func TestProcessorMemoryBudgetReleased(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r1Stream),
 		)
 		h.syncEventAndRegistrations()
@@ -685,6 +795,8 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r1Stream),
 		)
 		h.syncEventAndRegistrations()
@@ -992,7 +1104,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 				runtime.Gosched()
 				s := newTestStream()
 				p.Register(s.ctx, h.span, hlc.Timestamp{}, nil, /* catchUpIter */
-					false /* withDiff */, false /* withFiltering */, false, /* withOmitRemote */
+					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, false /* withTxnIDs */, 0, /* expirationLookback */
 					h.toBufferedStreamIfNeeded(s))
 			}()
 			go func() {
@@ -1065,7 +1177,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 				s := newTestStream()
 				regs[s] = firstIdx
 				p.Register(s.ctx, h.span, hlc.Timestamp{}, nil, /* catchUpIter */
-					false /* withDiff */, false /* withFiltering */, false, /* withOmitRemote */
+					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, false /* withTxnIDs */, 0, /* expirationLookback */
 					h.toBufferedStreamIfNeeded(s))
 				regDone <- struct{}{}
 			}
@@ -1126,6 +1238,8 @@ func TestBudgetReleaseOnProcessorStop(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(rStream),
 		)
 		h.syncEventAndRegistrations()
@@ -1206,6 +1320,8 @@ func TestBudgetReleaseOnLastStreamError(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(rStream),
 		)
 		h.syncEventAndRegistrations()
@@ -1276,6 +1392,8 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r1Stream),
 		)
 
@@ -1289,6 +1407,8 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			false, /* withTxnIDs */
+			0,     /* expirationLookback */
 			h.toBufferedStreamIfNeeded(r2Stream),
 		)
 		h.syncEventAndRegistrations()
@@ -1457,7 +1577,7 @@ func TestProcessorBackpressure(t *testing.T) {
 		// Add a registration.
 		stream := newTestStream()
 		ok, _, _ := p.Register(stream.ctx, span, hlc.MinTimestamp, nil, /* catchUpIter */
-			false /* withDiff */, false /* withFiltering */, false, /* withOmitRemote */
+			false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, false /* withTxnIDs */, 0, /* expirationLookback */
 			h.toBufferedStreamIfNeeded(stream))
 		require.True(t, ok)
 
@@ -10,12 +10,14 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // Disconnector defines an interface for disconnecting a registration. It is
@@ -63,6 +65,11 @@ type registration interface {
 	getWithFiltering() bool
 	// getWithOmitRemote returns the withOmitRemote field of the registration.
 	getWithOmitRemote() bool
+	// getWithTxnIDs returns the withTxnIDs field of the registration.
+	getWithTxnIDs() bool
+	// getExpirationLookback returns the expirationLookback field of the
+	// registration.
+	getExpirationLookback() time.Duration
 	// Range returns the keys field of the registration.
 	Range() interval.Range
 	// ID returns the id field of the registration as a uintptr.
@@ -86,6 +93,12 @@ type baseRegistration struct {
 	withDiff       bool
 	withFiltering  bool
 	withOmitRemote bool
+	// withTxnIDs opts the registration into the transaction ID and write
+	// ordinal of values published when a transaction's intent is committed.
+	withTxnIDs bool
+	// expirationLookback, if positive, opts the registration into deletions
+	// emitted when values reach their MVCC expiration.
+	expirationLookback time.Duration
 	// removeRegFromProcessor is called to remove the registration from its
 	// processor. This is provided by the creator of the registration and called
 	// during disconnect(). Since it is called during disconnect it must be
@@ -136,6 +149,14 @@ func (r *baseRegistration) getWithOmitRemote() bool {
 	return r.withOmitRemote
 }
 
+func (r *baseRegistration) getWithTxnIDs() bool {
+	return r.withTxnIDs
+}
+
+func (r *baseRegistration) getExpirationLookback() time.Duration {
+	return r.expirationLookback
+}
+
 func (r *baseRegistration) shouldUnregister() bool {
 	return r.shouldUnreg.Load()
 }
@@ -214,6 +235,13 @@ func (r *baseRegistration) maybeStripEvent(
 			t = copyOnWrite().(*kvpb.RangeFeedValue)
 			t.PrevValue = roachpb.Value{}
 		}
+		if t.TxnWriteOrdinal != 0 && !r.withTxnIDs {
+			// Similarly, the transaction ID and write ordinal are only
+			// populated if any registration requested them.
+			t = copyOnWrite().(*kvpb.RangeFeedValue)
+			t.TxnID = uuid.UUID{}
+			t.TxnWriteOrdinal = 0
+		}
 	case *kvpb.RangeFeedCheckpoint:
 		if !t.Span.EqualValue(r.span) {
 			// Checkpoint events are always created spanning the entire Range.
@@ -276,6 +304,9 @@ type registry struct {
 	metrics *Metrics
 	tree    interval.Tree // *registration items
 	idAlloc int64
+	// numWithTxnIDs is the number of registrations that requested transaction
+	// IDs.
+	numWithTxnIDs int
 }
 
 func makeRegistry(metrics *Metrics) registry {
@@ -296,9 +327,12 @@ func (reg *registry) NewFilter() *Filter {
 	return newFilterFromRegistry(reg)
 }
 
-// updateMetricsOnUnregistration updates the metrics when a registration is
-// registered with the processor's registry.
+// updateMetricsOnUnregistration updates the metrics and numWithTxnIDs when a
+// registration is registered with the processor's registry.
 func (reg *registry) updateMetricsOnRegistration(r registration) {
+	if r.getWithTxnIDs() {
+		reg.numWithTxnIDs++
+	}
 	reg.metrics.RangeFeedRegistrations.Inc(1)
 	switch r.(type) {
 	case *bufferedRegistration:
@@ -308,9 +342,12 @@ func (reg *registry) updateMetricsOnRegistration(r registration) {
 	}
 }
 
-// updateMetricsOnUnregistration updates the metrics when a registration is
-// unregistered from the processor's registry.
+// updateMetricsOnUnregistration updates the metrics and numWithTxnIDs when a
+// registration is unregistered from the processor's registry.
 func (reg *registry) updateMetricsOnUnregistration(r registration) {
+	if r.getWithTxnIDs() {
+		reg.numWithTxnIDs--
+	}
 	reg.metrics.RangeFeedRegistrations.Dec(1)
 	switch r.(type) {
 	case *bufferedRegistration:
@@ -370,8 +407,10 @@ func (reg *registry) PublishToOverlapping(
 		// Don't publish events if they:
 		// 1. are equal to or less than the registration's starting timestamp, or
 		// 2. have OmitInRangefeeds = true and this registration has opted into filtering, or
-		// 3. have OmitRemote = true and this value is from a remote cluster.
-		if r.getCatchUpTimestamp().Less(minTS) && !(r.getWithFiltering() && valueMetadata.omitInRangefeeds) && (!r.getWithOmitRemote() || valueMetadata.originID == 0) {
+		// 3. have OmitRemote = true and this value is from a remote cluster, or
+		// 4. are expirations and this registration has not opted into them.
+		if r.getCatchUpTimestamp().Less(minTS) && !(r.getWithFiltering() && valueMetadata.omitInRangefeeds) && (!r.getWithOmitRemote() || valueMetadata.originID == 0) &&
+			(!valueMetadata.expiration || r.getExpirationLookback() > 0) {
 			r.publish(ctx, event, alloc)
 		}
 		return false, nil
@@ -294,6 +294,18 @@ func withOmitRemote(opt bool) registrationOption {
 	}
 }
 
+func withTxnIDs(opt bool) registrationOption {
+	return func(cfg *testRegistrationConfig) {
+		cfg.withTxnIDs = opt
+	}
+}
+
+func withExpirationLookback(lookback time.Duration) registrationOption {
+	return func(cfg *testRegistrationConfig) {
+		cfg.expirationLookback = lookback
+	}
+}
+
 func withRegistrationType(regType registrationType) registrationOption {
 	return func(cfg *testRegistrationConfig) {
 		cfg.withRegistrationTestTypes = regType
@@ -338,6 +350,8 @@ type testRegistrationConfig struct {
 	withDiff                  bool
 	withFiltering             bool
 	withOmitRemote            bool
+	withTxnIDs                bool
+	expirationLookback        time.Duration
 	withRegistrationTestTypes registrationType
 	metrics                   *Metrics
 }
@@ -361,6 +375,8 @@ func newTestRegistration(s *testStream, opts ...registrationOption) testRegistra
 			cfg.withDiff,
 			cfg.withFiltering,
 			cfg.withOmitRemote,
+			cfg.withTxnIDs,
+			cfg.expirationLookback,
 			5,
 			false, /* blockWhenFull */
 			cfg.metrics,
@@ -376,6 +392,8 @@ func newTestRegistration(s *testStream, opts ...registrationOption) testRegistra
 			cfg.withDiff,
 			cfg.withFiltering,
 			cfg.withOmitRemote,
+			cfg.withTxnIDs,
+			cfg.expirationLookback,
 			5,
 			cfg.metrics,
 			&testBufferedStream{Stream: s},
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	_ "github.com/cockroachdb/cockroach/pkg/keys" // hook up pretty printer
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
@@ -231,6 +232,54 @@ func TestRegistryWithOmitOrigin(t *testing.T) {
 	})
 }
 
+// TestRegistryWithExpirations verifies that the deletions emitted for expired
+// values are only published to registrations that asked for them, and that
+// such registrations are reflected in the filter.
+func TestRegistryWithExpirations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	testutils.RunValues(t, "registration type=", registrationTestTypes, func(t *testing.T, rt registrationType) {
+		val := roachpb.Value{RawBytes: []byte("val"), Timestamp: hlc.Timestamp{WallTime: 1}}
+		expired := roachpb.Value{Timestamp: hlc.Timestamp{WallTime: 2}}
+		ev1, ev2 := new(kvpb.RangeFeedEvent), new(kvpb.RangeFeedEvent)
+		ev1.MustSetValue(&kvpb.RangeFeedValue{Key: keyA, Value: val})
+		ev2.MustSetValue(&kvpb.RangeFeedValue{Key: keyA, Value: expired})
+
+		reg := makeRegistry(NewMetrics())
+
+		sAC := newTestStream()
+		rAC := newTestRegistration(sAC, withRSpan(spAC), withRegistrationType(rt))
+		expirationsStream := newTestStream()
+		expirations := newTestRegistration(expirationsStream, withRSpan(spAB),
+			withExpirationLookback(time.Hour), withRegistrationType(rt))
+
+		go rAC.runOutputLoop(ctx, 0)
+		go expirations.runOutputLoop(ctx, 0)
+
+		defer rAC.Disconnect(nil)
+		defer expirations.Disconnect(nil)
+
+		reg.Register(ctx, rAC)
+		reg.Register(ctx, expirations)
+
+		f := reg.NewFilter()
+		require.True(t, f.NeedExpirations(spAB))
+		require.False(t, f.NeedExpirations(spBC))
+		require.Equal(t, time.Hour, f.ExpirationLookback())
+
+		reg.PublishToOverlapping(ctx, spAB, ev1, logicalOpMetadata{}, nil /* alloc */)
+		reg.PublishToOverlapping(ctx, spAB, ev2, logicalOpMetadata{expiration: true}, nil /* alloc */)
+
+		require.NoError(t, reg.waitForCaughtUp(ctx, all))
+
+		require.Equal(t, []*kvpb.RangeFeedEvent{ev1}, sAC.GetAndClearEvents())
+		require.Equal(t, []*kvpb.RangeFeedEvent{ev1, ev2}, expirationsStream.GetAndClearEvents())
+		require.Nil(t, sAC.Error())
+		require.Nil(t, expirationsStream.Error())
+	})
+}
+
 func TestRegistryBasic(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ctx := context.Background()
@@ -156,6 +156,10 @@ func (rts *resolvedTimestamp) consumeLogicalOp(
 		rts.assertOpAboveRTS(ctx, op, t.Timestamp, true /* fatal */)
 		return false
 
+	case *enginepb.MVCCExpireValueOp:
+		rts.assertOpAboveRTS(ctx, op, t.Timestamp, true /* fatal */)
+		return false
+
 	case *enginepb.MVCCWriteIntentOp:
 		rts.assertOpAboveRTS(ctx, op, t.Timestamp, true /* fatal */)
 		return rts.intentQ.IncRef(t.TxnID, t.TxnKey, t.TxnIsoLevel, t.TxnMinTimestamp, t.Timestamp)
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -39,6 +40,12 @@ type ScheduledProcessor struct {
 	reg registry
 	rts resolvedTimestamp
 
+	// txnWriteOrdinals counts the committed writes published for each
+	// transaction that still has unresolved intents on the range. It is used
+	// to populate RangeFeedValue.TxnWriteOrdinal, and is only maintained while
+	// a registration requested transaction IDs.
+	txnWriteOrdinals map[uuid.UUID]int32
+
 	// processCtx is the annotated background context used for process(). It is
 	// stored here to avoid reconstructing it on every call.
 	processCtx context.Context
@@ -89,11 +96,12 @@ func NewScheduledProcessor(cfg Config) *ScheduledProcessor {
 	cfg.SetDefaults()
 	cfg.AmbientContext.AddLogTag("rangefeed", nil)
 	p := &ScheduledProcessor{
-		Config:     cfg,
-		scheduler:  cfg.Scheduler.NewClientScheduler(),
-		reg:        makeRegistry(cfg.Metrics),
-		rts:        makeResolvedTimestamp(cfg.Settings),
-		processCtx: cfg.AmbientContext.AnnotateCtx(context.Background()),
+		Config:           cfg,
+		scheduler:        cfg.Scheduler.NewClientScheduler(),
+		reg:              makeRegistry(cfg.Metrics),
+		rts:              makeResolvedTimestamp(cfg.Settings),
+		txnWriteOrdinals: make(map[uuid.UUID]int32),
+		processCtx:       cfg.AmbientContext.AnnotateCtx(context.Background()),
 
 		requestQueue: make(chan request, 20),
 		eventC:       make(chan *event, cfg.EventChanCap),
@@ -338,6 +346,8 @@ func (p *ScheduledProcessor) Register(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	withTxnIDs bool,
+	expirationLookback time.Duration,
 	stream Stream,
 ) (bool, Disconnector, *Filter) {
 	// Synchronize the event channel so that this registration doesn't see any
@@ -352,11 +362,11 @@ func (p *ScheduledProcessor) Register(
 	if isBufferedStream {
 		r = newUnbufferedRegistration(
 			streamCtx, span.AsRawSpanWithNoLocals(), startTS, catchUpIter, withDiff, withFiltering, withOmitRemote,
-			p.Config.EventChanCap, p.Metrics, bufferedStream, p.unregisterClientAsync)
+			withTxnIDs, expirationLookback, p.Config.EventChanCap, p.Metrics, bufferedStream, p.unregisterClientAsync)
 	} else {
 		r = newBufferedRegistration(
 			streamCtx, span.AsRawSpanWithNoLocals(), startTS, catchUpIter, withDiff, withFiltering, withOmitRemote,
-			p.Config.EventChanCap, blockWhenFull, p.Metrics, stream, p.unregisterClientAsync)
+			withTxnIDs, expirationLookback, p.Config.EventChanCap, blockWhenFull, p.Metrics, stream, p.unregisterClientAsync)
 	}
 
 	filter := runRequest(p, func(ctx context.Context, p *ScheduledProcessor) *Filter {
@@ -719,11 +729,16 @@ func (p *ScheduledProcessor) consumeLogicalOps(
 
 		case *enginepb.MVCCWriteValueOp:
 			// Publish the new value directly.
-			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue, logicalOpMetadata{omitInRangefeeds: t.OmitInRangefeeds, originID: t.OriginID}, alloc)
+			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue, uuid.UUID{}, 0 /* txnWriteOrdinal */, logicalOpMetadata{omitInRangefeeds: t.OmitInRangefeeds, originID: t.OriginID}, alloc)
 		case *enginepb.MVCCDeleteRangeOp:
 			// Publish the range deletion directly.
 			p.publishDeleteRange(ctx, t.StartKey, t.EndKey, t.Timestamp, alloc)
 
+		case *enginepb.MVCCExpireValueOp:
+			// Publish the expiration as a deletion, only to the registrations
+			// that asked for expirations.
+			p.publishValue(ctx, t.Key, t.Timestamp, nil /* value */, t.PrevValue, uuid.UUID{}, 0 /* txnWriteOrdinal */, logicalOpMetadata{omitInRangefeeds: t.OmitInRangefeeds, originID: t.OriginID, expiration: true}, alloc)
+
 		case *enginepb.MVCCWriteIntentOp:
 			// No updates to publish.
 
@@ -731,8 +746,16 @@ func (p *ScheduledProcessor) consumeLogicalOps(
 			// No updates to publish.
 
 		case *enginepb.MVCCCommitIntentOp:
-			// Publish the newly committed value.
-			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue, logicalOpMetadata{omitInRangefeeds: t.OmitInRangefeeds, originID: t.OriginID}, alloc)
+			// Publish the newly committed value. The transaction ID and write
+			// ordinal are only populated while a registration requests them.
+			var txnID uuid.UUID
+			var ordinal int32
+			if p.reg.numWithTxnIDs > 0 {
+				txnID = t.TxnID
+				ordinal = p.txnWriteOrdinals[t.TxnID] + 1
+				p.txnWriteOrdinals[t.TxnID] = ordinal
+			}
+			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue, txnID, ordinal, logicalOpMetadata{omitInRangefeeds: t.OmitInRangefeeds, originID: t.OriginID}, alloc)
 
 		case *enginepb.MVCCAbortIntentOp:
 			// No updates to publish.
@@ -749,6 +772,28 @@ func (p *ScheduledProcessor) consumeLogicalOps(
 		if p.rts.ConsumeLogicalOp(ctx, op) {
 			p.publishCheckpoint(ctx, nil)
 		}
+
+		// Stop counting the writes of a transaction once it no longer has
+		// unresolved intents on the range.
+		switch t := op.GetValue().(type) {
+		case *enginepb.MVCCWriteIntentOp:
+			p.maybeForgetTxnWriteOrdinal(t.TxnID)
+		case *enginepb.MVCCCommitIntentOp:
+			p.maybeForgetTxnWriteOrdinal(t.TxnID)
+		case *enginepb.MVCCAbortIntentOp:
+			p.maybeForgetTxnWriteOrdinal(t.TxnID)
+		case *enginepb.MVCCAbortTxnOp:
+			p.maybeForgetTxnWriteOrdinal(t.TxnID)
+		}
+	}
+}
+
+func (p *ScheduledProcessor) maybeForgetTxnWriteOrdinal(txnID uuid.UUID) {
+	if _, ok := p.txnWriteOrdinals[txnID]; !ok {
+		return
+	}
+	if _, unresolved := p.rts.intentQ.txns[txnID]; !unresolved {
+		delete(p.txnWriteOrdinals, txnID)
 	}
 }
 
@@ -774,6 +819,11 @@ func (p *ScheduledProcessor) initResolvedTS(ctx context.Context, alloc *SharedBu
 	if p.rts.Init(ctx) {
 		p.publishCheckpoint(ctx, alloc)
 	}
+	// Initializing the resolved timestamp discards transactions with negative
+	// reference counts, which may have had their writes counted.
+	for txnID := range p.txnWriteOrdinals {
+		p.maybeForgetTxnWriteOrdinal(txnID)
+	}
 }
 
 func (p *ScheduledProcessor) publishValue(
@@ -781,6 +831,8 @@ func (p *ScheduledProcessor) publishValue(
 	key roachpb.Key,
 	timestamp hlc.Timestamp,
 	value, prevValue []byte,
+	txnID uuid.UUID,
+	txnWriteOrdinal int32,
 	valueMetadata logicalOpMetadata,
 	alloc *SharedBudgetAllocation,
 ) {
@@ -799,7 +851,9 @@ func (p *ScheduledProcessor) publishValue(
 			RawBytes:  value,
 			Timestamp: timestamp,
 		},
-		PrevValue: prevVal,
+		PrevValue:       prevVal,
+		TxnID:           txnID,
+		TxnWriteOrdinal: txnWriteOrdinal,
 	})
 	p.reg.PublishToOverlapping(ctx, roachpb.Span{Key: key}, &event, valueMetadata, alloc)
 }
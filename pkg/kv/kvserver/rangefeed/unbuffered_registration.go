@@ -98,6 +98,8 @@ func newUnbufferedRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	withTxnIDs bool,
+	expirationLookback time.Duration,
 	bufferSz int,
 	metrics *Metrics,
 	stream BufferedStream,
@@ -111,6 +113,8 @@ func newUnbufferedRegistration(
 			withDiff:               withDiff,
 			withFiltering:          withFiltering,
 			withOmitRemote:         withOmitRemote,
+			withTxnIDs:             withTxnIDs,
+			expirationLookback:     expirationLookback,
 			removeRegFromProcessor: removeRegFromProcessor,
 		},
 		metrics: metrics,
@@ -463,6 +463,13 @@ type Replica struct {
 		// the replica and generate a signal to potentially nudge or cancel the
 		// rangefeed based on observed lag.
 		rangefeedCTLagObserver *rangeFeedCTLagObserver
+
+		// rangefeedExpiredUpTo is the timestamp up to which the expirations of
+		// values (see MVCCValueHeader.Expiration) have been handed to the
+		// rangefeed processor, for the registrations that asked for them. The
+		// closed timestamp given to the processor never exceeds it, and expired
+		// values are synthesized as deletions above it.
+		rangefeedExpiredUpTo hlc.Timestamp
 	}
 
 	// localMsgs contains StorageAppend acknowledgements to be delivered to the
@@ -1061,6 +1068,11 @@ type Replica struct {
 	// attempts.
 	lastProblemRangeReplicateEnqueueTime atomic.Value
 
+	// schedulingGCForExpiration is set while a request to schedule a GC run for
+	// values with an MVCC expiration is in flight. See
+	// maybeScheduleGCForExpiration.
+	schedulingGCForExpiration atomic.Bool
+
 	// unreachablesMu contains a set of remote ReplicaIDs that are to be reported
 	// as unreachable on the next raft tick.
 	unreachablesMu struct {
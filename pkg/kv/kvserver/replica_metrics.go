@@ -64,6 +64,7 @@ type ReplicaMetrics struct {
 	SlowRaftProposalCount    int64
 	RaftFlowStateCounts      [tracker.StateCount]int64
 	ClosedTimestampPolicy    ctpb.RangeClosedTimestampPolicy
+	StorageTier              roachpb.StorageTier
 
 	QuotaPoolPercentUsed int64 // [0,100]
 
@@ -231,6 +232,7 @@ func calcReplicaMetrics(d calcReplicaMetricsInput) ReplicaMetrics {
 		LatchMetrics:             d.latchMetrics,
 		LockTableMetrics:         d.lockTableMetrics,
 		ClosedTimestampPolicy:    d.closedTimestampPolicy,
+		StorageTier:              d.conf.StorageTier,
 	}
 }
 
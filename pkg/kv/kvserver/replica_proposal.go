@@ -887,6 +887,11 @@ func (r *Replica) handleReadWriteLocalEvalResult(ctx context.Context, lResult re
 		lResult.UpdatedTxns = nil
 	}
 
+	if lResult.MVCCExpiration.IsSet() {
+		r.maybeScheduleGCForExpiration(ctx, lResult.MVCCExpiration)
+		lResult.MVCCExpiration = hlc.Timestamp{}
+	}
+
 	if lResult.GossipFirstRange {
 		// We need to run the gossip in an async task because gossiping requires
 		// the range lease and we'll deadlock if we try to acquire it while
@@ -8,6 +8,7 @@ package kvserver
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -322,7 +323,7 @@ func (r *Replica) RangeFeed(
 		// is different.
 		catchUpIter, err = rangefeed.NewCatchUpIterator(
 			context.Background(), r.store.TODOEngine(), rSpan.AsRawSpanWithNoLocals(),
-			args.Timestamp, iterSemRelease, pacer)
+			args.Timestamp, args.ExpirationLookback, iterSemRelease, pacer)
 		if err != nil {
 			r.raftMu.Unlock()
 			iterSemRelease()
@@ -334,7 +335,8 @@ func (r *Replica) RangeFeed(
 	}
 
 	p, disconnector, err := r.registerWithRangefeedRaftMuLocked(
-		streamCtx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, args.WithFiltering, omitRemote, stream,
+		streamCtx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, args.WithFiltering, omitRemote,
+		args.WithTxnIDs, args.ExpirationLookback, stream,
 	)
 	r.raftMu.Unlock()
 
@@ -442,6 +444,8 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	withTxnIDs bool,
+	expirationLookback time.Duration,
 	stream rangefeed.Stream,
 ) (rangefeed.Processor, rangefeed.Disconnector, error) {
 	defer logSlowRangefeedRegistration(streamCtx)()
@@ -462,8 +466,13 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	p := r.rangefeedMu.proc
 
 	if p != nil {
+		if catchUpIter != nil {
+			// The catch-up scan emits the expirations up to the point where the
+			// processor takes over.
+			catchUpIter.ExpirationsUpTo = r.raftMu.rangefeedExpiredUpTo
+		}
 		reg, disconnector, filter := p.Register(streamCtx, span, startTS, catchUpIter, withDiff, withFiltering, withOmitRemote,
-			stream)
+			withTxnIDs, expirationLookback, stream)
 		if reg {
 			// Registered successfully with an existing processor.
 			// Update the rangefeed filter to avoid filtering ops
@@ -538,13 +547,24 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 		return nil, nil, err
 	}
 
+	// The new processor emits the expirations above the registration's start
+	// timestamp, and the catch-up scan has none left to emit. Without a
+	// catch-up scan, start from the current closed timestamp.
+	r.raftMu.rangefeedExpiredUpTo = startTS
+	if startTS.IsEmpty() {
+		r.raftMu.rangefeedExpiredUpTo = r.GetCurrentClosedTimestamp(streamCtx)
+	}
+	if catchUpIter != nil {
+		catchUpIter.ExpirationsUpTo = r.raftMu.rangefeedExpiredUpTo
+	}
+
 	// Register with the processor *before* we attach its reference to the
 	// Replica struct. This ensures that the registration is in place before
 	// any other goroutines are able to stop the processor. In other words,
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
 	reg, disconnector, filter := p.Register(streamCtx, span, startTS, catchUpIter, withDiff,
-		withFiltering, withOmitRemote, stream)
+		withFiltering, withOmitRemote, withTxnIDs, expirationLookback, stream)
 	if !reg {
 		select {
 		case <-r.store.Stopper().ShouldQuiesce():
@@ -629,14 +649,20 @@ func (r *Replica) numRangefeedRegistrations() int {
 // log with previous values read from the reader, which is expected to reflect
 // the state of the Replica before the operations in the logical op log are
 // applied. No-op if a rangefeed is not active. Requires raftMu to be locked.
+//
+// If the previous value of a write expired above expiredUpTo and before the
+// write, an MVCCExpireValueOp is inserted ahead of the write, for the
+// rangefeed registrations that asked for expirations.
 func populatePrevValsInLogicalOpLog(
 	ctx context.Context,
 	filter *rangefeed.Filter,
 	ops *kvserverpb.LogicalOpLog,
 	prevReader storage.Reader,
+	expiredUpTo hlc.Timestamp,
 ) error {
 	// Read from the Reader to populate the PrevValue fields.
-	for _, op := range ops.Ops {
+	for i := 0; i < len(ops.Ops); i++ {
+		op := ops.Ops[i]
 		var key []byte
 		var ts hlc.Timestamp
 		var prevValPtr *[]byte
@@ -649,7 +675,8 @@ func populatePrevValsInLogicalOpLog(
 			*enginepb.MVCCUpdateIntentOp,
 			*enginepb.MVCCAbortIntentOp,
 			*enginepb.MVCCAbortTxnOp,
-			*enginepb.MVCCDeleteRangeOp:
+			*enginepb.MVCCDeleteRangeOp,
+			*enginepb.MVCCExpireValueOp:
 			// Nothing to do.
 			continue
 		default:
@@ -658,23 +685,54 @@ func populatePrevValsInLogicalOpLog(
 
 		// Don't read previous values from the reader for operations that are
 		// not needed by any rangefeed registration.
-		if !filter.NeedPrevVal(roachpb.Span{Key: key}) {
+		needPrevVal := filter.NeedPrevVal(roachpb.Span{Key: key})
+		needExpiration := filter.NeedExpirations(roachpb.Span{Key: key})
+		if !needPrevVal && !needExpiration {
 			continue
 		}
 
 		// Read the previous value from the prev Reader. Unlike the new value
-		// (see handleLogicalOpLogRaftMuLocked), this one may be missing.
-		prevValRes, err := storage.MVCCGet(
+		// (see handleLogicalOpLogRaftMuLocked), this one may be missing. If it
+		// expired before ts, it is read as a tombstone, but its header still
+		// carries the expiration.
+		prevValRes, prevVH, err := storage.MVCCGetWithValueHeader(
 			ctx, prevReader, key, ts, storage.MVCCGetOptions{
 				Tombstones: true, Inconsistent: true, ReadCategory: fs.RangefeedReadCategory},
 		)
 		if err != nil {
 			return errors.Wrapf(err, "consuming %T for key %v @ ts %v", op, key, ts)
 		}
-		if prevValRes.Value != nil {
-			*prevValPtr = prevValRes.Value.RawBytes
-		} else {
-			*prevValPtr = nil
+		if needPrevVal {
+			if prevValRes.Value != nil {
+				*prevValPtr = prevValRes.Value.RawBytes
+			} else {
+				*prevValPtr = nil
+			}
+		}
+
+		if exp := prevVH.Expiration; needExpiration && expiredUpTo.Less(exp) && exp.Less(ts) {
+			expire := &enginepb.MVCCExpireValueOp{
+				Key:              key,
+				Timestamp:        exp,
+				OmitInRangefeeds: prevVH.OmitInRangefeeds,
+				OriginID:         prevVH.OriginID,
+			}
+			if needPrevVal {
+				expiredValRes, err := storage.MVCCGet(
+					ctx, prevReader, key, exp.Prev(), storage.MVCCGetOptions{
+						Tombstones: true, Inconsistent: true, ReadCategory: fs.RangefeedReadCategory},
+				)
+				if err != nil {
+					return errors.Wrapf(err, "consuming %T for key %v @ ts %v", op, key, exp)
+				}
+				if expiredValRes.Value != nil {
+					expire.PrevValue = expiredValRes.Value.RawBytes
+				}
+			}
+			var expireOp enginepb.MVCCLogicalOp
+			expireOp.MustSetValue(expire)
+			ops.Ops = slices.Insert(ops.Ops, i, expireOp)
+			i++
 		}
 	}
 	return nil
@@ -752,7 +810,8 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(
 		case *enginepb.MVCCWriteIntentOp,
 			*enginepb.MVCCUpdateIntentOp,
 			*enginepb.MVCCAbortIntentOp,
-			*enginepb.MVCCAbortTxnOp:
+			*enginepb.MVCCAbortTxnOp,
+			*enginepb.MVCCExpireValueOp:
 			// Nothing to do.
 			continue
 		case *enginepb.MVCCDeleteRangeOp:
@@ -935,6 +994,14 @@ func (r *Replica) handleClosedTimestampUpdateRaftMuLocked(
 	if closedTS.IsEmpty() {
 		return false
 	}
+	// Hand the values that expired at or below the closed timestamp to the
+	// processor first, and hold the closed timestamp back below the ones that
+	// can't be emitted yet.
+	closedTS, err := r.handleExpirationsRaftMuLocked(ctx, p, closedTS)
+	if err != nil {
+		r.disconnectRangefeedWithErr(p, kvpb.NewError(err))
+		return exceedsSlowLagThresh
+	}
 	if !p.ForwardClosedTS(ctx, closedTS) {
 		// Consumption failed and the rangefeed was stopped.
 		r.unsetRangefeedProcessor(p)
@@ -943,6 +1010,145 @@ func (r *Replica) handleClosedTimestampUpdateRaftMuLocked(
 	return exceedsSlowLagThresh
 }
 
+// handleExpirationsRaftMuLocked passes the values that reached their MVCC
+// expiration above r.raftMu.rangefeedExpiredUpTo and at or below closedTS to
+// the rangefeed processor as MVCCExpireValueOp, if any of its registrations
+// asked for expirations. It returns the timestamp up to which expirations were
+// handed over, which the closed timestamp given to the processor must not
+// exceed. Requires raftMu to be locked.
+//
+// The values that expired before being overwritten were already handed over
+// along with the overwriting write (see populatePrevValsInLogicalOpLog), so
+// only the newest version of each key is considered here. The expiration of a
+// version below an intent is held back until the intent is resolved, since
+// its fate decides whether the version was overwritten before it expired.
+//
+// NB: this scans the versions written up to the expiration lookback below
+// rangefeedExpiredUpTo on every closed timestamp update; the lookback should
+// be kept close to the expire-after used by the writers.
+func (r *Replica) handleExpirationsRaftMuLocked(
+	ctx context.Context, p rangefeed.Processor, closedTS hlc.Timestamp,
+) (hlc.Timestamp, error) {
+	expiredUpTo := r.raftMu.rangefeedExpiredUpTo
+	if closedTS.LessEq(expiredUpTo) {
+		return closedTS, nil
+	}
+	_, filter := r.getRangefeedProcessorAndFilter()
+	if filter == nil || filter.ExpirationLookback() <= 0 {
+		r.raftMu.rangefeedExpiredUpTo = closedTS
+		return closedTS, nil
+	}
+
+	lowerBound := expiredUpTo.Add(-filter.ExpirationLookback().Nanoseconds(), 0)
+	if lowerBound.WallTime < 0 {
+		lowerBound = hlc.Timestamp{}
+	}
+	span := r.Desc().RSpan().AsRawSpanWithNoLocals()
+	iter, err := storage.NewMVCCIncrementalIterator(ctx, r.store.TODOEngine(),
+		storage.MVCCIncrementalIterOptions{
+			KeyTypes:     storage.IterKeyTypePointsAndRanges,
+			StartKey:     span.Key,
+			EndKey:       span.EndKey,
+			StartTime:    lowerBound,
+			EndTime:      hlc.MaxTimestamp,
+			IntentPolicy: storage.MVCCIncrementalIterIntentPolicyEmit,
+			ReadCategory: fs.RangefeedReadCategory,
+		})
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	defer iter.Close()
+
+	upTo := closedTS
+	var expired []*enginepb.MVCCExpireValueOp
+	iter.SeekGE(storage.MVCCKey{Key: span.Key})
+	for {
+		if ok, err := iter.Valid(); err != nil {
+			return hlc.Timestamp{}, err
+		} else if !ok {
+			break
+		}
+		if hasPoint, _ := iter.HasPointAndRange(); !hasPoint {
+			iter.Next()
+			continue
+		}
+		unsafeKey := iter.UnsafeKey()
+		intent := !unsafeKey.IsValue()
+		if intent {
+			// Step over the intent's provisional value onto the newest committed
+			// version, if there is one.
+			key := unsafeKey.Key.Clone()
+			iter.NextIgnoringTime()
+			if ok, err := iter.Valid(); err != nil {
+				return hlc.Timestamp{}, err
+			} else if !ok {
+				return hlc.Timestamp{}, errors.Errorf("expected provisional value for intent")
+			}
+			iter.NextIgnoringTime()
+			if ok, err := iter.Valid(); err != nil {
+				return hlc.Timestamp{}, err
+			} else if !ok {
+				break
+			}
+			unsafeKey = iter.UnsafeKey()
+			if !unsafeKey.Key.Equal(key) {
+				continue
+			}
+			if unsafeKey.Timestamp.Less(lowerBound) {
+				iter.NextKey()
+				continue
+			}
+		}
+		unsafeValRaw, err := iter.UnsafeValue()
+		if err != nil {
+			return hlc.Timestamp{}, err
+		}
+		mvccVal, err := storage.DecodeMVCCValue(unsafeValRaw)
+		if err != nil {
+			return hlc.Timestamp{}, errors.Wrapf(err, "decoding mvcc value: %v", unsafeKey)
+		}
+		if exp := mvccVal.Expiration; expiredUpTo.Less(exp) && exp.LessEq(closedTS) &&
+			filter.NeedExpirations(roachpb.Span{Key: unsafeKey.Key}) {
+			rangeKeys := iter.RangeKeysIgnoringTime()
+			if intent {
+				upTo.Backward(exp.Prev())
+			} else if rangeKeys.IsEmpty() || !rangeKeys.HasBetween(unsafeKey.Timestamp, hlc.MaxTimestamp) {
+				// A version deleted by an MVCC range tombstone was already
+				// superseded by its deletion.
+				expire := &enginepb.MVCCExpireValueOp{
+					Key:              unsafeKey.Key.Clone(),
+					Timestamp:        exp,
+					OmitInRangefeeds: mvccVal.OmitInRangefeeds,
+					OriginID:         mvccVal.OriginID,
+				}
+				if filter.NeedPrevVal(roachpb.Span{Key: unsafeKey.Key}) {
+					expire.PrevValue = append([]byte(nil), mvccVal.Value.RawBytes...)
+				}
+				expired = append(expired, expire)
+			}
+		}
+		iter.NextKey()
+	}
+
+	if upTo.LessEq(expiredUpTo) {
+		return expiredUpTo, nil
+	}
+	var ops []enginepb.MVCCLogicalOp
+	for _, expire := range expired {
+		if expire.Timestamp.LessEq(upTo) {
+			var op enginepb.MVCCLogicalOp
+			op.MustSetValue(expire)
+			ops = append(ops, op)
+		}
+	}
+	if len(ops) > 0 && !p.ConsumeLogicalOps(ctx, ops...) {
+		// Consumption failed and the rangefeed was stopped.
+		r.unsetRangefeedProcessor(p)
+	}
+	r.raftMu.rangefeedExpiredUpTo = upTo
+	return upTo, nil
+}
+
 // ensureClosedTimestampStarted does its best to make sure that this node is
 // receiving closed timestamp updates for this replica's range. Note that this
 // forces a valid lease to exist on the range and so can be reasonably expensive
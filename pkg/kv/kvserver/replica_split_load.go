@@ -78,6 +78,18 @@ var SplitSampleResetDuration = settings.RegisterDurationSetting(
 	settings.DurationWithMinimumOrZeroDisable(10*time.Second),
 )
 
+// SplitByLoadHotKeySamplingEnabled wraps
+// "kv.range_split.hot_key_sampling.enabled". When enabled, ranges whose load
+// exceeds the load based split threshold, but for which no split key can be
+// found, sample the heaviest keys so that they can be surfaced to operators.
+var SplitByLoadHotKeySamplingEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.range_split.hot_key_sampling.enabled",
+	"sample the heaviest keys of ranges which exceed the load based split "+
+		"threshold without a split key being found",
+	true,
+)
+
 func (obj LBRebalancingObjective) ToSplitObjective() split.SplitObjective {
 	switch obj {
 	case LBRebalancingQueries:
@@ -141,6 +153,12 @@ func (c *replicaSplitConfig) SampleResetDuration() time.Duration {
 	return SplitSampleResetDuration.Get(&c.st.SV)
 }
 
+// HotKeySamplingEnabled returns whether the heaviest keys should be sampled
+// when the load exceeds the threshold but no split key can be found.
+func (c *replicaSplitConfig) HotKeySamplingEnabled() bool {
+	return SplitByLoadHotKeySamplingEnabled.Get(&c.st.SV)
+}
+
 // SplitByLoadEnabled returns whether load based splitting is enabled.
 // Although this is a method of *Replica, the configuration is really global,
 // shared across all stores.
@@ -149,6 +167,13 @@ func (r *Replica) SplitByLoadEnabled() bool {
 		!r.store.TestingKnobs().DisableLoadBasedSplitting
 }
 
+// HotKeys returns up to limit of the heaviest keys sampled by the replica's
+// load based splitter, or nil if the replica's load does not exceed the split
+// threshold or a split key could be found.
+func (r *Replica) HotKeys(limit int) []split.HotKey {
+	return r.loadBasedSplitter.HotKeys(limit)
+}
+
 // getResponseBoundarySpan computes the union span of the true spans that were
 // iterated over using the request span and the response's resumeSpan.
 //
@@ -78,6 +78,9 @@ type LoadSplitConfig interface {
 	// SampleResetDuration returns the duration that any sampling structure
 	// should retain data for before resetting.
 	SampleResetDuration() time.Duration
+	// HotKeySamplingEnabled returns whether the heaviest keys should be sampled
+	// when the load exceeds the threshold but no split key can be found.
+	HotKeySamplingEnabled() bool
 }
 
 type RandSource interface {
@@ -176,6 +179,11 @@ type Decider struct {
 
 		// Fields tracking logging / metrics around load-based splitter split key.
 		lastNoSplitKeyLoggingMetrics time.Time
+
+		// hotKeys is populated when the load is over the threshold but the split
+		// finder was unable to find a split key. It is discarded once the load
+		// drops below the threshold.
+		hotKeys *hotKeySampler
 	}
 }
 
@@ -260,13 +268,24 @@ func (d *Decider) recordLocked(
 			}
 		} else {
 			d.mu.splitFinder = nil
+			d.mu.hotKeys = nil
 		}
 	}
 
+	// The span is only computed once, since it may be expensive to compute
+	// on this hot path.
+	var s roachpb.Span
+	if (d.mu.hotKeys != nil || d.mu.splitFinder != nil) && n != 0 {
+		s = span()
+	}
+
+	if d.mu.hotKeys != nil && n != 0 {
+		d.mu.hotKeys.record(s.Key, float64(n))
+	}
+
 	if d.mu.splitFinder != nil && n != 0 {
-		s := span()
 		if s.Key != nil {
-			d.mu.splitFinder.Record(span(), float64(n))
+			d.mu.splitFinder.Record(s, float64(n))
 		}
 		// We don't want to check for a split key if we don't need to as it
 		// requires some computation. When the splitFinder isn't ready or we
@@ -280,6 +299,12 @@ func (d *Decider) recordLocked(
 				d.mu.suggestionsMade++
 				return true
 			} else {
+				// No split key could be found, which is often due to the load being
+				// concentrated on a single key. Start sampling the heaviest keys so
+				// that they can be surfaced to operators.
+				if d.mu.hotKeys == nil && d.config.HotKeySamplingEnabled() {
+					d.mu.hotKeys = newHotKeySampler()
+				}
 				if now.Sub(d.mu.lastNoSplitKeyLoggingMetrics) > minNoSplitKeyLoggingMetricsInterval {
 					d.mu.lastNoSplitKeyLoggingMetrics = now
 					if causeMsg := d.mu.splitFinder.NoSplitKeyCauseLogMsg(); causeMsg != "" {
@@ -410,6 +435,20 @@ func (d *Decider) SplitStatistics() *SplitStatistics {
 	return nil
 }
 
+// HotKeys returns up to limit of the heaviest keys sampled since the load on
+// the range exceeded the split threshold without a split key being found,
+// ordered by descending weight. It returns nil if hot key sampling is not
+// engaged. When limit is non-positive, all sampled keys are returned.
+func (d *Decider) HotKeys(limit int) []HotKey {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.mu.hotKeys == nil {
+		return nil
+	}
+	return d.mu.hotKeys.topK(limit)
+}
+
 // Reset deactivates any current attempt at determining a split key. The method
 // also discards any historical stat tracking information.
 func (d *Decider) Reset(now time.Time) {
@@ -429,6 +468,7 @@ func (d *Decider) resetLocked(now time.Time) {
 	d.mu.suggestionsMade = 0
 	d.mu.lastSplitSuggestion = time.Time{}
 	d.mu.lastNoSplitKeyLoggingMetrics = time.Time{}
+	d.mu.hotKeys = nil
 }
 
 // SetSplitObjective sets the decider split objective to the given value and
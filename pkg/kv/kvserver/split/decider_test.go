@@ -27,6 +27,7 @@ type testLoadSplitConfig struct {
 	statRetention       time.Duration
 	statThreshold       float64
 	sampleResetDuration time.Duration
+	hotKeySampling      bool
 }
 
 // NewLoadBasedSplitter returns a new LoadBasedSplitter that may be used to
@@ -57,6 +58,12 @@ func (t *testLoadSplitConfig) SampleResetDuration() time.Duration {
 	return t.sampleResetDuration
 }
 
+// HotKeySamplingEnabled returns whether the heaviest keys should be sampled
+// when the load exceeds the threshold but no split key can be found.
+func (t *testLoadSplitConfig) HotKeySamplingEnabled() bool {
+	return t.hotKeySampling
+}
+
 func ld(n int) func(SplitObjective) int {
 	return func(_ SplitObjective) int {
 		return n
@@ -631,3 +638,62 @@ func TestDeciderSampleReset(t *testing.T) {
 	})
 	require.NotNil(t, d.mu.splitFinder, (*lockedDecider)(&d))
 }
+
+// TestDeciderHotKeys tests that the decider samples the heaviest keys once the
+// load exceeds the threshold without a split key being found, and that the
+// sample is discarded once the load drops below the threshold.
+func TestDeciderHotKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng := rand.New(rand.NewPCG(11, 11))
+	loadSplitConfig := testLoadSplitConfig{
+		randSource:     rng,
+		useWeighted:    false,
+		statRetention:  time.Second,
+		statThreshold:  1,
+		hotKeySampling: true,
+	}
+	ctx := context.Background()
+	timeStart := 1000
+
+	var d Decider
+	Init(&d, &loadSplitConfig, newSplitterMetrics(), SplitQPS)
+
+	hotKey := keys.SystemSQLCodec.TablePrefix(uint32(0))
+	for i := 0; i < 20; i++ {
+		d.Record(ctx, ms(timeStart), ld(1), func() roachpb.Span {
+			return roachpb.Span{Key: hotKey}
+		})
+	}
+	// Nine in every ten requests target the same key, so no split key can be
+	// found.
+	for i := 1; i <= 2000; i++ {
+		key := hotKey
+		if i%10 == 0 {
+			key = keys.SystemSQLCodec.TablePrefix(uint32(i))
+		}
+		require.False(t, d.Record(ctx, ms(timeStart+i*50), ld(1), func() roachpb.Span {
+			return roachpb.Span{Key: key}
+		}))
+	}
+
+	hotKeys := d.HotKeys(3)
+	require.Len(t, hotKeys, 3)
+	require.Equal(t, hotKey, hotKeys[0].Key)
+	require.Greater(t, hotKeys[0].Frequency, 0.8)
+
+	// Once the load drops below the threshold, the sample is discarded.
+	d.Record(ctx, ms(timeStart+2000*50+10000), ld(0), nil)
+	require.Nil(t, d.HotKeys(3))
+
+	// The sampler is never engaged when hot key sampling is disabled.
+	loadSplitConfig.hotKeySampling = false
+	var dDisabled Decider
+	Init(&dDisabled, &loadSplitConfig, newSplitterMetrics(), SplitQPS)
+	for i := 1; i <= 2000; i++ {
+		dDisabled.Record(ctx, ms(timeStart+i*50), ld(1), func() roachpb.Span {
+			return roachpb.Span{Key: hotKey}
+		})
+	}
+	require.Nil(t, dDisabled.HotKeys(3))
+}
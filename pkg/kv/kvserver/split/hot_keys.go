@@ -0,0 +1,142 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package split
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/redact"
+)
+
+// Hot key sampling.
+//
+// When a range is over the load split threshold but the split finder cannot
+// find a split key, the load is usually concentrated on one or a handful of
+// keys (e.g. a counter row). Splitting cannot help in that case, however
+// operators still want to know which key is responsible. The Decider engages
+// a hotKeySampler in this situation, which tracks the heaviest keys using the
+// weighted Space-Saving algorithm (Metwally et al.). The sampler keeps a fixed
+// number of counters; a key that is not tracked replaces the counter with the
+// smallest weight and inherits its weight as an error bound. The heaviest keys
+// are guaranteed to be retained, and their weights are overestimated by at
+// most the total weight divided by the number of counters.
+
+// hotKeySampleSize is the number of distinct keys tracked by a hotKeySampler.
+const hotKeySampleSize = 32
+
+// HotKey is a key which accounts for a large fraction of the load on a range
+// that exceeded the load split threshold without a split key being found.
+type HotKey struct {
+	// Key is the sampled key. For table keys, the column family suffix is
+	// stripped so that all the column families of a row are attributed to the
+	// row.
+	Key roachpb.Key
+	// Weight is the estimated load (requests or CPU nanos, depending on the
+	// split objective) attributed to the key since sampling began.
+	Weight float64
+	// Frequency is the fraction of the sampled load attributed to the key, in
+	// the interval [0, 1].
+	Frequency float64
+}
+
+// SafeFormat implements the redact.SafeFormatter interface.
+func (hk HotKey) SafeFormat(w redact.SafePrinter, _ rune) {
+	w.Printf("%s(w=%.1f f=%.2f)", hk.Key, hk.Weight, hk.Frequency)
+}
+
+func (hk HotKey) String() string {
+	return redact.StringWithoutMarkers(hk)
+}
+
+type hotKeyCounter struct {
+	key    roachpb.Key
+	weight float64
+	// err is the maximum overestimation of weight, inherited from the counter
+	// that was evicted to make room for key.
+	err float64
+}
+
+// hotKeySampler tracks the heaviest keys recorded against a range using the
+// weighted Space-Saving algorithm.
+type hotKeySampler struct {
+	counters    []hotKeyCounter
+	totalWeight float64
+}
+
+func newHotKeySampler() *hotKeySampler {
+	return &hotKeySampler{
+		counters: make([]hotKeyCounter, 0, hotKeySampleSize),
+	}
+}
+
+// record attributes the weight to the given key.
+func (s *hotKeySampler) record(key roachpb.Key, weight float64) {
+	if len(key) == 0 || weight <= 0 {
+		return
+	}
+	if rowKey, err := keys.EnsureSafeSplitKey(key); err == nil {
+		key = rowKey
+	}
+	s.totalWeight += weight
+
+	minIdx := -1
+	for i := range s.counters {
+		if s.counters[i].key.Equal(key) {
+			s.counters[i].weight += weight
+			return
+		}
+		if minIdx == -1 || s.counters[i].weight < s.counters[minIdx].weight {
+			minIdx = i
+		}
+	}
+
+	if len(s.counters) < cap(s.counters) {
+		s.counters = append(s.counters, hotKeyCounter{
+			key:    key.Clone(),
+			weight: weight,
+		})
+		return
+	}
+
+	// Evict the lightest counter. The new key inherits its weight, which bounds
+	// the number of times the new key could have been seen before while not
+	// being tracked.
+	c := &s.counters[minIdx]
+	c.err = c.weight
+	c.key = key.Clone()
+	c.weight += weight
+}
+
+// topK returns up to k of the heaviest sampled keys, ordered by descending
+// weight. When k is non-positive, all tracked keys are returned.
+func (s *hotKeySampler) topK(k int) []HotKey {
+	if s.totalWeight == 0 {
+		return nil
+	}
+	// Order by the guaranteed lower bound on the weight, rather than the
+	// possibly inflated counter value, so that keys which were only recently
+	// admitted to the sample are not overstated.
+	sorted := slices.Clone(s.counters)
+	slices.SortFunc(sorted, func(a, b hotKeyCounter) int {
+		return cmp.Compare(b.weight-b.err, a.weight-a.err)
+	})
+	if k > 0 && k < len(sorted) {
+		sorted = sorted[:k]
+	}
+	hotKeys := make([]HotKey, len(sorted))
+	for i, c := range sorted {
+		weight := c.weight - c.err
+		hotKeys[i] = HotKey{
+			Key:       c.key,
+			Weight:    weight,
+			Frequency: weight / s.totalWeight,
+		}
+	}
+	return hotKeys
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package split
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func rowKey(pk int64) roachpb.Key {
+	return encoding.EncodeVarintAscending(keys.SystemSQLCodec.IndexPrefix(100, 1), pk)
+}
+
+func TestHotKeySampler(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	t.Run("empty", func(t *testing.T) {
+		s := newHotKeySampler()
+		require.Nil(t, s.topK(10))
+		s.record(nil, 1)
+		s.record(rowKey(1), 0)
+		require.Nil(t, s.topK(10))
+	})
+
+	t.Run("column families", func(t *testing.T) {
+		s := newHotKeySampler()
+		// All column families of the same row are attributed to the row.
+		s.record(keys.MakeFamilyKey(rowKey(1), 0), 1)
+		s.record(keys.MakeFamilyKey(rowKey(1), 1), 1)
+		s.record(keys.MakeFamilyKey(rowKey(2), 0), 2)
+		hotKeys := s.topK(0)
+		require.Len(t, hotKeys, 2)
+		for _, hk := range hotKeys {
+			require.Equal(t, 2.0, hk.Weight)
+			require.Equal(t, 0.5, hk.Frequency)
+		}
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		s := newHotKeySampler()
+		// Interleave a single heavy key with many more distinct light keys than
+		// there are counters. The heavy key must never be evicted, and must be
+		// reported first with its exact weight.
+		for i := 0; i < 100*hotKeySampleSize; i++ {
+			s.record(rowKey(0), 3)
+			s.record(rowKey(int64(i+1)), 1)
+		}
+		require.Len(t, s.counters, hotKeySampleSize)
+
+		hotKeys := s.topK(3)
+		require.Len(t, hotKeys, 3)
+		require.Equal(t, rowKey(0), hotKeys[0].Key)
+		require.Equal(t, float64(300*hotKeySampleSize), hotKeys[0].Weight)
+		require.InDelta(t, 0.75, hotKeys[0].Frequency, 1e-9)
+		for i := 1; i < len(hotKeys); i++ {
+			require.LessOrEqual(t, hotKeys[i].Weight, hotKeys[i-1].Weight)
+		}
+	})
+}
@@ -52,6 +52,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftentry"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rangefeed"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/split"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/storeliveness"
 	slpb "github.com/cockroachdb/cockroach/pkg/kv/kvserver/storeliveness/storelivenesspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/tenantrate"
@@ -2423,6 +2424,8 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 
 	s.startRangefeedTxnPushNotifier(ctx)
 
+	s.startColdStorageTiering(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings, s.rebalanceObjManager)
@@ -3360,6 +3363,7 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		slowRaftProposalCount       int64
 		raftFlowStateCounts         [tracker.StateCount]int64
 		closedTimestampPolicyCounts [ctpb.MAX_CLOSED_TIMESTAMP_POLICY]int64
+		storageTierLogicalBytes     [numStorageTiers]int64
 
 		locks                          int64
 		totalLockHoldDurationNanos     int64
@@ -3482,6 +3486,9 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 
 		totalRaftLogSize += metrics.RaftLogSize
 		maxRaftLogSize = max(maxRaftLogSize, metrics.RaftLogSize)
+		if metrics.StorageTier < numStorageTiers {
+			storageTierLogicalBytes[metrics.StorageTier] += rep.GetMVCCStats().Total()
+		}
 
 		locks += metrics.LockTableMetrics.Locks
 		totalLockHoldDurationNanos += metrics.LockTableMetrics.TotalLockHoldDurationNanos
@@ -3524,6 +3531,9 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	for policy, count := range closedTimestampPolicyCounts {
 		s.metrics.RangeClosedTimestampPolicyCount[policy].Update(count)
 	}
+	for tier, bytes := range storageTierLogicalBytes {
+		s.metrics.StorageTierLogicalBytes[tier].Update(bytes)
+	}
 	s.metrics.RaftLogTotalSize.Update(totalRaftLogSize)
 	s.metrics.RaftLogMaxSize.Update(maxRaftLogSize)
 	s.metrics.AverageQueriesPerSecond.Update(averageQueriesPerSecond)
@@ -3877,6 +3887,31 @@ func mapToHotReplicasInfo(repls []CandidateReplica) []HotReplicaInfo {
 	return hotRepls
 }
 
+// HotKeysInfo contains the heaviest keys sampled on a replica whose load
+// exceeds the load based split threshold without a split key being found.
+type HotKeysInfo struct {
+	Desc    *roachpb.RangeDescriptor
+	HotKeys []split.HotKey
+}
+
+// HotKeys returns the heaviest keys sampled on each replica of the store which
+// is engaged in hot key sampling, with up to perRangeLimit keys per replica.
+// Only contains ranges for which this store is the leaseholder.
+func (s *Store) HotKeys(ctx context.Context, perRangeLimit int) []HotKeysInfo {
+	var hotKeys []HotKeysInfo
+	now := s.Clock().NowAsClockTimestamp()
+	s.VisitReplicas(func(r *Replica) bool {
+		if hk := r.HotKeys(perRangeLimit); len(hk) > 0 && r.OwnsValidLease(ctx, now) {
+			hotKeys = append(hotKeys, HotKeysInfo{
+				Desc:    r.Desc(),
+				HotKeys: hk,
+			})
+		}
+		return true
+	})
+	return hotKeys
+}
+
 // ReplicateQueueDryRun runs the given replica through the replicate queue
 // (using the allocator) without actually carrying out any changes, returning
 // all trace messages collected along the way.
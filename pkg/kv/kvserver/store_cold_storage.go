@@ -0,0 +1,97 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// coldStorageTieringInterval is the interval at which stores configured with
+// cold storage move the sstables of spans with the cold storage tier to it.
+var coldStorageTieringInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.cold_storage.tiering_interval",
+	"the interval at which stores configured with cold storage move the sstables "+
+		"of spans with storage_tier = 'cold' to it, and the sstables of other spans "+
+		"back to local disk",
+	10*time.Minute,
+	settings.PositiveDuration,
+)
+
+// startColdStorageTiering starts a worker that periodically places the
+// bottom-level sstables of the store on cold storage or local disk according
+// to the storage tier of their spans. It is a no-op if the engine was not
+// opened with cold storage.
+func (s *Store) startColdStorageTiering(ctx context.Context) {
+	tierer, ok := s.TODOEngine().(storage.ColdStorageTierer)
+	if !ok {
+		return
+	}
+	_ /* err */ = s.stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "cold-storage-tiering",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(coldStorageTieringInterval.Get(&s.ClusterSettings().SV))
+			select {
+			case <-timer.C:
+				if err := tierer.TierColdStorage(ctx, s.isColdSpan); err != nil {
+					log.Warningf(ctx, "unable to tier cold storage: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// isColdSpan returns whether all the span configs overlapping the given span
+// have the cold storage tier.
+func (s *Store) isColdSpan(ctx context.Context, span roachpb.Span) (bool, error) {
+	// If the span configs are unavailable, the error aborts the tiering pass so
+	// that the data stays where it is.
+	confReader, err := s.GetConfReader(ctx)
+	if err != nil {
+		return false, err
+	}
+	rSpan, err := keys.SpanAddr(span)
+	if err != nil {
+		return false, err
+	}
+	for key := rSpan.Key; key.Less(rSpan.EndKey); {
+		conf, confSpan, err := confReader.GetSpanConfigForKey(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		if conf.StorageTier != roachpb.StorageTier_COLD {
+			return false, nil
+		}
+		next := roachpb.RKey(confSpan.EndKey)
+		if !next.Less(rSpan.EndKey) {
+			break
+		}
+		if !key.Less(next) {
+			// Don't loop forever on a span config that does not cover the key.
+			return false, nil
+		}
+		key = next
+	}
+	return true, nil
+}
@@ -259,9 +259,11 @@ func NewLeafTxn(
 			)
 		}
 		txn.admissionHeader = kvpb.AdmissionHeader{
-			CreateTime: header.CreateTime,
-			Priority:   header.Priority,
-			Source:     header.Source,
+			CreateTime:          header.CreateTime,
+			Priority:            header.Priority,
+			Source:              header.Source,
+			ResourceGroup:       header.ResourceGroup,
+			ResourceGroupShares: header.ResourceGroupShares,
 		}
 	}
 	return txn
@@ -1872,6 +1874,15 @@ func (txn *Txn) AdmissionHeader() kvpb.AdmissionHeader {
 	return h
 }
 
+// SetResourceGroup sets the workload resource group of the work done in the
+// context of this transaction and the group's shares, used by admission
+// control to share grants between workloads. See
+// admission.ResourceGroupsSetting.
+func (txn *Txn) SetResourceGroup(group string, shares kvpb.ResourceGroupShares) {
+	txn.admissionHeader.ResourceGroup = group
+	txn.admissionHeader.ResourceGroupShares = &shares
+}
+
 // OnePCNotAllowedError signifies that a request had the Require1PC flag set,
 // but 1PC evaluation was not possible for one reason or another.
 type OnePCNotAllowedError struct{}
@@ -0,0 +1,319 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package revert
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/asof"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	alterTableRevertOp    = "ALTER TABLE REVERT"
+	alterDatabaseRevertOp = "ALTER DATABASE REVERT"
+)
+
+// alterRevertHook plans ALTER TABLE ... REVERT TO SYSTEM TIME and ALTER
+// DATABASE ... REVERT TO SYSTEM TIME. Both create a table revert job over the
+// targeted tables and wait for it to complete.
+func alterRevertHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	var (
+		op        string
+		tsExpr    tree.Expr
+		resolveFn func(ctx context.Context) ([]catalog.TableDescriptor, error)
+	)
+	switch s := stmt.(type) {
+	case *tree.AlterTableRevert:
+		op, tsExpr = alterTableRevertOp, s.Timestamp
+		resolveFn = func(ctx context.Context) ([]catalog.TableDescriptor, error) {
+			tn := s.Name.ToTableName()
+			_, tbl, err := resolver.ResolveExistingTableObject(ctx, p, &tn, tree.ObjectLookupFlags{
+				Required:             true,
+				DesiredObjectKind:    tree.TableObject,
+				DesiredTableDescKind: tree.ResolveRequireTableDesc,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if tbl.IsVirtualTable() {
+				return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", tn.String())
+			}
+			return []catalog.TableDescriptor{tbl}, nil
+		}
+	case *tree.AlterDatabaseRevert:
+		op, tsExpr = alterDatabaseRevertOp, s.Timestamp
+		resolveFn = func(ctx context.Context) ([]catalog.TableDescriptor, error) {
+			col := p.InternalSQLTxn().Descriptors()
+			db, err := col.ByNameWithLeased(p.Txn()).Get().Database(ctx, string(s.Name))
+			if err != nil {
+				return nil, err
+			}
+			all, err := col.GetAllTablesInDatabase(ctx, p.Txn(), db)
+			if err != nil {
+				return nil, err
+			}
+			var tables []catalog.TableDescriptor
+			if err := all.ForEachDescriptor(func(desc catalog.Descriptor) error {
+				tbl, ok := desc.(catalog.TableDescriptor)
+				if !ok || !tbl.IsTable() || tbl.IsVirtualTable() || tbl.Dropped() {
+					return nil
+				}
+				tables = append(tables, tbl)
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			if len(tables) == 0 {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"database %q has no tables to revert", s.Name)
+			}
+			return tables, nil
+		}
+	default:
+		return nil, nil, false, nil
+	}
+
+	revertTo, err := asof.EvalSystemTimeExpr(ctx, &p.ExtendedEvalContext().Context, p.SemaCtx(), tsExpr,
+		op, asof.AsOf)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		if !p.ExtendedEvalContext().TxnIsSingleStmt {
+			return pgerror.Newf(pgcode.InvalidTransactionState,
+				"%s cannot be used inside a multi-statement transaction", op)
+		}
+		if now := p.ExecCfg().Clock.Now(); !revertTo.Less(now) {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"revert timestamp %s must be in the past", revertTo)
+		}
+
+		tables, err := resolveFn(ctx)
+		if err != nil {
+			return err
+		}
+		for _, tbl := range tables {
+			if err := p.CheckPrivilege(ctx, tbl, privilege.DROP); err != nil {
+				return err
+			}
+		}
+		if err := checkTablesRevertible(ctx, p.ExecCfg(), tables, revertTo); err != nil {
+			return err
+		}
+
+		sj, err := createTableRevertJob(ctx, p, stmt.String(), tables, revertTo)
+		if err != nil {
+			return err
+		}
+		// Release all descriptor leases before starting the job, since it will
+		// take the tables offline and wait for a single version of each.
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		return sj.AwaitCompletion(ctx)
+	}
+	return fn, nil, false, nil
+}
+
+// createTableRevertJob creates the revert job and a protected timestamp record
+// for the revert timestamp in the planner's transaction, and then commits it.
+// The caller is responsible for starting the returned job.
+func createTableRevertJob(
+	ctx context.Context,
+	p sql.PlanHookState,
+	description string,
+	tables []catalog.TableDescriptor,
+	revertTo hlc.Timestamp,
+) (sj *jobs.StartableJob, retErr error) {
+	defer func() {
+		if retErr == nil || sj == nil {
+			return
+		}
+		if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+			log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+		}
+	}()
+
+	execCfg := p.ExecCfg()
+	tableIDs := make(descpb.IDs, 0, len(tables))
+	spans := make([]roachpb.Span, 0, len(tables))
+	for _, tbl := range tables {
+		tableIDs = append(tableIDs, tbl.GetID())
+		spans = append(spans, tbl.TableSpan(execCfg.Codec))
+	}
+
+	ptsID := uuid.MakeV4()
+	jr := jobs.Record{
+		JobID:         execCfg.JobRegistry.MakeJobID(),
+		Description:   description,
+		Username:      p.User(),
+		DescriptorIDs: tableIDs,
+		Details: jobspb.TableRevertDetails{
+			TableIDs:                   tableIDs,
+			RevertTo:                   revertTo,
+			ProtectedTimestampRecordID: ptsID,
+		},
+		Progress: jobspb.TableRevertProgress{},
+		// Once the tables are offline the revert must run to completion for
+		// them to come back with consistent contents.
+		NonCancelable: true,
+	}
+
+	plannerTxn := p.InternalSQLTxn()
+	pts := jobsprotectedts.MakeRecord(ptsID, int64(jr.JobID), revertTo, spans,
+		jobsprotectedts.Jobs, ptpb.MakeSchemaObjectsTarget(tableIDs))
+	if err := execCfg.ProtectedTimestampProvider.WithTxn(plannerTxn).Protect(ctx, pts); err != nil {
+		return nil, errors.Wrap(err, "protecting revert timestamp")
+	}
+	if err := execCfg.JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jr.JobID, plannerTxn, jr); err != nil {
+		return nil, err
+	}
+	// We commit the transaction here so that the job can be started. This is
+	// safe because the statement is only allowed in an implicit transaction.
+	if err := plannerTxn.KV().Commit(ctx); err != nil {
+		return sj, err
+	}
+	return sj, nil
+}
+
+// checkRevertibleState returns an error unless the table is public and has no
+// schema change in progress.
+func checkRevertibleState(tbl catalog.TableDescriptor) error {
+	if !tbl.Public() {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot revert table %q: table is %s", tbl.GetName(), tbl.GetState())
+	}
+	if tbl.GetDeclarativeSchemaChangerState() != nil || len(tbl.AllMutations()) > 0 {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot revert table %q: a schema change is in progress", tbl.GetName())
+	}
+	return nil
+}
+
+// checkTablesRevertible verifies, as of the revert timestamp, that the schema
+// of each table is unchanged since then. Reverting the data of a table whose
+// indexes, columns or constraints changed since the revert timestamp would
+// leave its contents inconsistent with its current descriptor.
+//
+// Whether the revert timestamp is still above the GC threshold of the tables is
+// checked by the job, once the protected timestamp record is in place.
+func checkTablesRevertible(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	tables []catalog.TableDescriptor,
+	revertTo hlc.Timestamp,
+) error {
+	for _, tbl := range tables {
+		if err := checkRevertibleState(tbl); err != nil {
+			return err
+		}
+	}
+	return sql.DescsTxn(ctx, execCfg, func(ctx context.Context, txn isql.Txn, col *descs.Collection) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, revertTo); err != nil {
+			return err
+		}
+		for _, tbl := range tables {
+			then, err := col.ByIDWithoutLeased(txn.KV()).WithoutNonPublic().Get().Table(ctx, tbl.GetID())
+			if err != nil {
+				if errors.Is(err, catalog.ErrDescriptorNotFound) || errors.Is(err, catalog.ErrDescriptorDropped) {
+					return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+						"cannot revert table %q: table did not exist at %s", tbl.GetName(), revertTo)
+				}
+				return errors.Wrapf(err, "reading descriptor of table %q at %s", tbl.GetName(), revertTo)
+			}
+			if err := checkSchemaUnchanged(then, tbl); err != nil {
+				return pgerror.Wrapf(err, pgcode.ObjectNotInPrerequisiteState,
+					"cannot revert table %q to %s", tbl.GetName(), revertTo)
+			}
+		}
+		return nil
+	})
+}
+
+// checkSchemaUnchanged returns an error if the public columns, indexes or
+// constraints of the table differ between the two descriptors.
+func checkSchemaUnchanged(then, now catalog.TableDescriptor) error {
+	ids := func(desc catalog.TableDescriptor) (cols, idxs, constraints []uint32) {
+		for _, c := range desc.PublicColumns() {
+			cols = append(cols, uint32(c.GetID()))
+		}
+		for _, idx := range desc.ActiveIndexes() {
+			idxs = append(idxs, uint32(idx.GetID()))
+		}
+		for _, c := range desc.EnforcedConstraints() {
+			constraints = append(constraints, uint32(c.GetConstraintID()))
+		}
+		slices.Sort(cols)
+		slices.Sort(idxs)
+		slices.Sort(constraints)
+		return cols, idxs, constraints
+	}
+	thenCols, thenIdxs, thenConstraints := ids(then)
+	nowCols, nowIdxs, nowConstraints := ids(now)
+	for _, c := range []struct {
+		kind      string
+		then, now []uint32
+	}{
+		{"columns", thenCols, nowCols},
+		{"indexes", thenIdxs, nowIdxs},
+		{"constraints", thenConstraints, nowConstraints},
+	} {
+		if !slices.Equal(c.then, c.now) {
+			return errors.Newf("the %s of the table have changed since then", errors.Safe(c.kind))
+		}
+	}
+	return nil
+}
+
+func alterRevertHookTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (bool, colinfo.ResultColumns, error) {
+	var (
+		op     string
+		tsExpr tree.Expr
+	)
+	switch s := stmt.(type) {
+	case *tree.AlterTableRevert:
+		op, tsExpr = alterTableRevertOp, s.Timestamp
+	case *tree.AlterDatabaseRevert:
+		op, tsExpr = alterDatabaseRevertOp, s.Timestamp
+	default:
+		return false, nil, nil
+	}
+	if _, err := asof.TypeCheckSystemTimeExpr(ctx, p.SemaCtx(), tsExpr, op); err != nil {
+		return false, nil, err
+	}
+	return true, nil, nil
+}
+
+func init() {
+	sql.AddPlanHook("alter table revert", alterRevertHook, alterRevertHookTypeCheck)
+}
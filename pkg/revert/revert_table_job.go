@@ -0,0 +1,434 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package revert
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// tableRevertOfflineReason is the offline reason of tables that are being
+// reverted by a table revert job.
+const tableRevertOfflineReason = "reverting to an earlier system time"
+
+// tableRevertProgressInterval is the minimum interval between updates of the
+// remaining spans in the job progress.
+const tableRevertProgressInterval = 15 * time.Second
+
+// tableRevertResumer reverts the contents of a set of tables to an earlier
+// timestamp. The job:
+//
+//  1. checks that the revert timestamp is above the GC threshold of every
+//     range of the tables, which the protected timestamp record written when
+//     the job was created keeps true from then on,
+//  2. takes the tables offline, which waits for the leases on their public
+//     versions to drain,
+//  3. reverts the table spans, recording the spans that remain to be reverted
+//     in the job progress,
+//  4. brings the tables back online in a transaction that re-validates the
+//     foreign keys between reverted and non-reverted tables and releases the
+//     protected timestamp record.
+//
+// If the job fails once the tables are offline, they are brought back online.
+// Their contents may be only partially reverted, so the foreign keys involving
+// them are marked as unvalidated. Running the revert again completes it.
+type tableRevertResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &tableRevertResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *tableRevertResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.TableRevertDetails)
+	progress := r.job.Progress().Details.(*jobspb.Progress_TableRevert).TableRevert
+
+	remaining := progress.RemainingSpans
+	if !progress.TablesOffline {
+		if err := checkRevertTimestampReadable(ctx, execCfg, details.TableIDs, details.RevertTo); err != nil {
+			return jobs.MarkAsPermanentJobError(err)
+		}
+		var err error
+		if remaining, err = r.takeTablesOffline(ctx, execCfg, details.TableIDs); err != nil {
+			return err
+		}
+	}
+
+	// As with IMPORT rollback, we retry until paused: the tables only come
+	// back online once the revert completes or fails permanently.
+	retryOpts := retry.Options{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+	for re := retry.StartWithCtx(ctx, retryOpts); re.Next(); {
+		var err error
+		remaining, err = r.revertSpans(ctx, p, details.RevertTo, remaining)
+		if err == nil {
+			break
+		}
+		if errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil)) {
+			return jobs.MarkAsPermanentJobError(r.failedRevertError(err))
+		}
+		log.Warningf(ctx, "reverting tables to %s failed: %v", details.RevertTo, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "reverted %d tables to %s", len(details.TableIDs), details.RevertTo)
+	if err := r.publishTables(ctx, execCfg, details); err != nil {
+		if pgerror.GetPGCode(err) == pgcode.ForeignKeyViolation {
+			return jobs.MarkAsPermanentJobError(r.failedRevertError(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// failedRevertError annotates an error that fails the job after it took the
+// tables offline with the action the operator needs to take.
+func (r *tableRevertResumer) failedRevertError(err error) error {
+	return errors.Wrapf(err,
+		"the tables are back online but may be partially reverted, and their foreign keys are "+
+			"no longer validated; address the error and run %q again to complete the revert",
+		r.job.Payload().Description)
+}
+
+// checkRevertTimestampReadable returns an error if the revert timestamp is
+// already below the GC threshold of one of the ranges of the tables. It must
+// run after the protected timestamp record of the job was committed: a check
+// that passes before the record exists may be invalidated by GC right after.
+func checkRevertTimestampReadable(
+	ctx context.Context, execCfg *sql.ExecutorConfig, tableIDs descpb.IDs, revertTo hlc.Timestamp,
+) error {
+	// Each range has its own GC threshold, so each range of the tables is read
+	// from. Ranges split off since their descriptors were read inherit the GC
+	// threshold of the range they were split from.
+	type rangeSpan struct {
+		tableID descpb.ID
+		span    roachpb.Span
+	}
+	var rangeSpans []rangeSpan
+	for _, id := range tableIDs {
+		tableSpan := execCfg.Codec.TableSpan(uint32(id))
+		it, err := execCfg.RangeDescIteratorFactory.NewLazyIterator(ctx, tableSpan, 0 /* pageSize */)
+		if err != nil {
+			return err
+		}
+		for ; it.Valid(); it.Next() {
+			desc := it.CurRangeDescriptor()
+			if sp := desc.RSpan().AsRawSpanWithNoLocals().Intersect(tableSpan); sp.Valid() {
+				rangeSpans = append(rangeSpans, rangeSpan{tableID: id, span: sp})
+			}
+		}
+		if err := it.Error(); err != nil {
+			return errors.Wrapf(err, "fetching the ranges of table %d", id)
+		}
+	}
+	return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, revertTo); err != nil {
+			return err
+		}
+		for _, rs := range rangeSpans {
+			// Reading the range as of the revert timestamp fails if that
+			// timestamp is already below its GC threshold.
+			if _, err := txn.KV().Scan(ctx, rs.span.Key, rs.span.EndKey, 1 /* maxRows */); err != nil {
+				return errors.Wrapf(err, "cannot revert table %d to %s", rs.tableID, revertTo)
+			}
+		}
+		return nil
+	})
+}
+
+// takeTablesOffline takes the tables offline and records, in the same
+// transaction, the spans to revert in the job progress. The descriptor
+// transaction waits for the leases on the previous versions of the tables to
+// drain before returning.
+func (r *tableRevertResumer) takeTablesOffline(
+	ctx context.Context, execCfg *sql.ExecutorConfig, tableIDs descpb.IDs,
+) (roachpb.Spans, error) {
+	var spans roachpb.Spans
+	err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		spans = make([]roachpb.Span, 0, len(tableIDs))
+		b := txn.KV().NewBatch()
+		for _, id := range tableIDs {
+			desc, err := txn.Descriptors().MutableByID(txn.KV()).Table(ctx, id)
+			if err != nil {
+				return errors.Wrapf(err, "looking up descriptor %d", id)
+			}
+			if err := checkRevertibleState(desc); err != nil {
+				return err
+			}
+			log.Infof(ctx, "transitioning table %q (%d) to OFFLINE", desc.GetName(), desc.GetID())
+			desc.SetOffline(tableRevertOfflineReason)
+			if err := txn.Descriptors().WriteDescToBatch(
+				ctx, false /* kvTrace */, desc, b,
+			); err != nil {
+				return errors.Wrapf(err, "taking table %d offline", desc.ID)
+			}
+			spans = append(spans, desc.TableSpan(execCfg.Codec))
+		}
+		if err := txn.KV().Run(ctx, b); err != nil {
+			return err
+		}
+		return r.job.WithTxn(txn).Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			prog := md.Progress.Details.(*jobspb.Progress_TableRevert).TableRevert
+			prog.TablesOffline = true
+			prog.RemainingSpans = spans
+			ju.UpdateProgress(md.Progress)
+			return nil
+		})
+	})
+	return spans, err
+}
+
+// revertSpans reverts the passed spans to the revert timestamp, periodically
+// persisting the spans that remain to be reverted. It returns the spans that
+// remain to be reverted, which are empty on success.
+func (r *tableRevertResumer) revertSpans(
+	ctx context.Context, p sql.JobExecContext, revertTo hlc.Timestamp, spans roachpb.Spans,
+) (roachpb.Spans, error) {
+	var remaining roachpb.SpanGroup
+	remaining.Add(spans...)
+	if remaining.Len() == 0 {
+		return nil, nil
+	}
+
+	originalRangeCount, err := sql.NumRangesInSpans(ctx, p.ExecCfg().DB, p.DistSQLPlanner(), spans)
+	if err != nil {
+		return spans, err
+	}
+	lastUpdatedAt := timeutil.Now()
+	onCompleted := func(ctx context.Context, completed roachpb.Span) error {
+		remaining.Sub(completed)
+		if timeutil.Since(lastUpdatedAt) < tableRevertProgressInterval {
+			return nil
+		}
+		lastUpdatedAt = timeutil.Now()
+		return r.persistRemainingSpans(ctx, p, remaining.Slice(), originalRangeCount)
+	}
+
+	err = RevertSpansFanout(ctx, p.ExecCfg().DB, p, spans, revertTo,
+		false, /* ignoreGCThreshold */
+		RevertDefaultBatchSize,
+		onCompleted)
+	return remaining.Slice(), err
+}
+
+func (r *tableRevertResumer) persistRemainingSpans(
+	ctx context.Context, p sql.JobExecContext, remaining roachpb.Spans, originalRangeCount int,
+) error {
+	var fractionCompleted float32
+	if originalRangeCount > 0 {
+		nRanges, err := sql.NumRangesInSpans(ctx, p.ExecCfg().DB, p.DistSQLPlanner(), remaining)
+		if err != nil {
+			return err
+		}
+		if nRanges < originalRangeCount {
+			fractionCompleted = float32(originalRangeCount-nRanges) / float32(originalRangeCount)
+		}
+	}
+	return r.job.NoTxn().FractionProgressed(ctx, func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+		details.(*jobspb.Progress_TableRevert).TableRevert.RemainingSpans = remaining
+		return fractionCompleted
+	})
+}
+
+// publishTables brings the reverted tables back online and releases the
+// protected timestamp record of the job.
+//
+// Constraints within a reverted table, and foreign keys between two reverted
+// tables, held as of the revert timestamp and hold again after the revert.
+// Foreign keys between a reverted table and a table that was not reverted are
+// validated again, in the transaction that publishes the tables. If one of them
+// is violated, publishTables returns a foreign key violation error and the
+// tables stay offline.
+func (r *tableRevertResumer) publishTables(
+	ctx context.Context, execCfg *sql.ExecutorConfig, details jobspb.TableRevertDetails,
+) error {
+	reverted := catalog.MakeDescriptorIDSet(details.TableIDs...)
+	return execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		col := txn.Descriptors()
+		tables := make([]*tabledesc.Mutable, 0, len(details.TableIDs))
+		for _, id := range details.TableIDs {
+			desc, err := col.MutableByID(txn.KV()).Table(ctx, id)
+			if err != nil {
+				return errors.Wrapf(err, "looking up descriptor %d", id)
+			}
+			if desc.Offline() && desc.GetOfflineReason() == tableRevertOfflineReason {
+				log.Infof(ctx, "transitioning table %q (%d) to PUBLIC", desc.GetName(), desc.GetID())
+				desc.SetPublic()
+				desc.MaybeIncrementVersion()
+			}
+			tables = append(tables, desc)
+		}
+
+		validate := func(src *tabledesc.Mutable, fk *descpb.ForeignKeyConstraint) error {
+			if fk.Validity != descpb.ConstraintValidity_Validated {
+				return nil
+			}
+			err := sql.ValidateForeignKeyInTxn(ctx, txn, src, fk.Name)
+			if err == nil || pgerror.GetPGCode(err) != pgcode.ForeignKeyViolation {
+				return err
+			}
+			return pgerror.Wrapf(err, pgcode.ForeignKeyViolation,
+				"foreign key %q of table %q is violated after reverting to %s",
+				fk.Name, src.GetName(), details.RevertTo)
+		}
+		for _, desc := range tables {
+			for i := range desc.OutboundFKs {
+				if fk := &desc.OutboundFKs[i]; !reverted.Contains(fk.ReferencedTableID) {
+					if err := validate(desc, fk); err != nil {
+						return err
+					}
+				}
+			}
+			for _, inbound := range desc.InboundFKs {
+				if reverted.Contains(inbound.OriginTableID) {
+					continue
+				}
+				src, err := col.MutableByID(txn.KV()).Table(ctx, inbound.OriginTableID)
+				if err != nil {
+					return errors.Wrapf(err, "looking up descriptor %d", inbound.OriginTableID)
+				}
+				for i := range src.OutboundFKs {
+					if fk := &src.OutboundFKs[i]; fk.Name == inbound.Name && fk.ReferencedTableID == desc.GetID() {
+						if err := validate(src, fk); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+
+		b := txn.KV().NewBatch()
+		for _, desc := range tables {
+			if err := col.WriteDescToBatch(ctx, false /* kvTrace */, desc, b); err != nil {
+				return errors.Wrapf(err, "publishing table %d", desc.ID)
+			}
+		}
+		if err := txn.KV().Run(ctx, b); err != nil {
+			return err
+		}
+		return releaseProtectedTimestamp(ctx, txn, execCfg, details)
+	})
+}
+
+func releaseProtectedTimestamp(
+	ctx context.Context, txn isql.Txn, execCfg *sql.ExecutorConfig, details jobspb.TableRevertDetails,
+) error {
+	err := execCfg.ProtectedTimestampProvider.WithTxn(txn).Release(ctx, details.ProtectedTimestampRecordID)
+	if errors.Is(err, protectedts.ErrNotExists) {
+		return nil
+	}
+	return err
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The job is not
+// cancelable, but it may still fail. If the tables were taken offline they are
+// brought back online. Their contents may be only partially reverted, or
+// violate foreign keys to tables that were not reverted, so the validated
+// foreign keys involving them are marked as unvalidated.
+func (r *tableRevertResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, jobErr error,
+) error {
+	execCfg := execCtx.(sql.JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.TableRevertDetails)
+	progress := r.job.Progress().Details.(*jobspb.Progress_TableRevert).TableRevert
+	if !progress.TablesOffline {
+		return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			return releaseProtectedTimestamp(ctx, txn, execCfg, details)
+		})
+	}
+	log.Warningf(ctx, "revert of tables %v to %s failed, bringing them back online: %v",
+		details.TableIDs, details.RevertTo, jobErr)
+	return execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		col := txn.Descriptors()
+		// modified holds the descriptors to write, which include the tables
+		// referencing the reverted tables.
+		modified := make(map[descpb.ID]*tabledesc.Mutable)
+		unvalidate := func(desc *tabledesc.Mutable, fk *descpb.ForeignKeyConstraint) {
+			if fk.Validity == descpb.ConstraintValidity_Validated {
+				log.Infof(ctx, "marking foreign key %q of table %q as unvalidated", fk.Name, desc.GetName())
+				fk.Validity = descpb.ConstraintValidity_Unvalidated
+				modified[desc.GetID()] = desc
+			}
+		}
+		for _, id := range details.TableIDs {
+			desc, err := col.MutableByID(txn.KV()).Table(ctx, id)
+			if err != nil {
+				return errors.Wrapf(err, "looking up descriptor %d", id)
+			}
+			if desc.Offline() && desc.GetOfflineReason() == tableRevertOfflineReason {
+				log.Infof(ctx, "transitioning table %q (%d) to PUBLIC", desc.GetName(), desc.GetID())
+				desc.SetPublic()
+				modified[desc.GetID()] = desc
+			}
+			for i := range desc.OutboundFKs {
+				unvalidate(desc, &desc.OutboundFKs[i])
+			}
+			for _, inbound := range desc.InboundFKs {
+				src, err := col.MutableByID(txn.KV()).Table(ctx, inbound.OriginTableID)
+				if err != nil {
+					return errors.Wrapf(err, "looking up descriptor %d", inbound.OriginTableID)
+				}
+				for i := range src.OutboundFKs {
+					if fk := &src.OutboundFKs[i]; fk.Name == inbound.Name && fk.ReferencedTableID == id {
+						unvalidate(src, fk)
+					}
+				}
+			}
+		}
+		b := txn.KV().NewBatch()
+		for _, desc := range modified {
+			desc.MaybeIncrementVersion()
+			if err := col.WriteDescToBatch(ctx, false /* kvTrace */, desc, b); err != nil {
+				return errors.Wrapf(err, "bringing table %d back online", desc.ID)
+			}
+		}
+		if err := txn.KV().Run(ctx, b); err != nil {
+			return err
+		}
+		return releaseProtectedTimestamp(ctx, txn, execCfg, details)
+	})
+}
+
+// CollectProfile is part of the jobs.Resumer interface.
+func (*tableRevertResumer) CollectProfile(context.Context, interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeTableRevert,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &tableRevertResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package revert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlterTableRevert(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(sqlDB)
+
+	db.Exec(t, "CREATE TABLE t (k INT PRIMARY KEY, v INT, INDEX (v))")
+	db.Exec(t, "INSERT INTO t SELECT i, i FROM generate_series(1, 100) AS g(i)")
+	var ts string
+	db.QueryRow(t, "SELECT cluster_logical_timestamp()").Scan(&ts)
+	expected := db.QueryStr(t, "SELECT * FROM t ORDER BY k")
+	expectedIdx := db.QueryStr(t, "SELECT v FROM t@t_v_idx ORDER BY v")
+
+	db.Exec(t, "DELETE FROM t WHERE k % 3 = 0")
+	db.Exec(t, "UPDATE t SET v = -v WHERE k % 5 = 0")
+	db.Exec(t, "INSERT INTO t SELECT i, i FROM generate_series(101, 150) AS g(i)")
+
+	db.Exec(t, "ALTER TABLE t REVERT TO SYSTEM TIME "+ts)
+	require.Equal(t, expected, db.QueryStr(t, "SELECT * FROM t ORDER BY k"))
+	require.Equal(t, expectedIdx, db.QueryStr(t, "SELECT v FROM t@t_v_idx ORDER BY v"))
+
+	db.CheckQueryResults(t,
+		"SELECT status FROM [SHOW JOBS] WHERE job_type = 'TABLE REVERT'",
+		[][]string{{"succeeded"}})
+	// The protected timestamp record of the job is released on completion.
+	db.CheckQueryResults(t, `
+SELECT count(*) FROM system.protected_ts_records
+WHERE meta = (SELECT job_id::STRING::BYTES FROM [SHOW JOBS] WHERE job_type = 'TABLE REVERT')`,
+		[][]string{{"0"}})
+	// The table is writable again.
+	db.Exec(t, "INSERT INTO t VALUES (1000, 1000)")
+
+	t.Run("schema changed", func(t *testing.T) {
+		db.QueryRow(t, "SELECT cluster_logical_timestamp()").Scan(&ts)
+		db.Exec(t, "ALTER TABLE t ADD COLUMN w INT")
+		db.ExpectErr(t, "the columns of the table have changed since then",
+			"ALTER TABLE t REVERT TO SYSTEM TIME "+ts)
+	})
+
+	t.Run("created after revert time", func(t *testing.T) {
+		db.QueryRow(t, "SELECT cluster_logical_timestamp()").Scan(&ts)
+		db.Exec(t, "CREATE TABLE t2 (k INT PRIMARY KEY)")
+		db.ExpectErr(t, "table did not exist",
+			"ALTER TABLE t2 REVERT TO SYSTEM TIME "+ts)
+	})
+}
+
+func TestAlterDatabaseRevert(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(sqlDB)
+
+	db.Exec(t, "CREATE DATABASE d")
+	db.Exec(t, "CREATE TABLE d.parent (k INT PRIMARY KEY)")
+	db.Exec(t, "CREATE TABLE d.child (k INT PRIMARY KEY, p INT REFERENCES d.parent (k))")
+	db.Exec(t, "CREATE TABLE defaultdb.outside (k INT PRIMARY KEY, p INT REFERENCES d.parent (k))")
+	db.Exec(t, "INSERT INTO d.parent VALUES (1), (2)")
+	db.Exec(t, "INSERT INTO d.child VALUES (1, 1)")
+	var ts string
+	db.QueryRow(t, "SELECT cluster_logical_timestamp()").Scan(&ts)
+
+	db.Exec(t, "INSERT INTO d.parent VALUES (3)")
+	db.Exec(t, "INSERT INTO d.child VALUES (2, 2), (3, 3)")
+	db.Exec(t, "INSERT INTO defaultdb.outside VALUES (1, 3)")
+
+	// The row of the table outside of the database references a row that the
+	// revert removes, so the revert fails. The tables are brought back online
+	// with their foreign keys unvalidated.
+	db.ExpectErr(t, `foreign key "outside_p_fkey" of table "outside" is violated after reverting.*`+
+		`the tables are back online but may be partially reverted`,
+		"ALTER DATABASE d REVERT TO SYSTEM TIME "+ts)
+	db.CheckQueryResults(t,
+		"SELECT status FROM [SHOW JOBS] WHERE job_type = 'TABLE REVERT'",
+		[][]string{{"failed"}})
+	const fkValidity = `
+SELECT table_name, validated FROM [SHOW CONSTRAINTS FROM d.child] WHERE constraint_type = 'FOREIGN KEY'
+UNION ALL
+SELECT table_name, validated FROM [SHOW CONSTRAINTS FROM defaultdb.outside] WHERE constraint_type = 'FOREIGN KEY'`
+	db.CheckQueryResults(t, fkValidity, [][]string{{"child", "false"}, {"outside", "false"}})
+	db.Exec(t, "SELECT * FROM d.parent")
+	db.Exec(t, "SELECT * FROM d.child")
+
+	// Once the violating row is removed, reverting again completes the revert
+	// and the foreign keys can be validated.
+	db.Exec(t, "DELETE FROM defaultdb.outside WHERE p = 3")
+	db.Exec(t, "ALTER DATABASE d REVERT TO SYSTEM TIME "+ts)
+	db.CheckQueryResults(t, "SELECT k FROM d.parent ORDER BY k", [][]string{{"1"}, {"2"}})
+	db.CheckQueryResults(t, "SELECT k, p FROM d.child ORDER BY k", [][]string{{"1", "1"}})
+	db.Exec(t, "ALTER TABLE d.child VALIDATE CONSTRAINT child_p_fkey")
+	db.Exec(t, "ALTER TABLE defaultdb.outside VALIDATE CONSTRAINT outside_p_fkey")
+	db.CheckQueryResults(t, fkValidity, [][]string{{"child", "true"}, {"outside", "true"}})
+}
@@ -98,6 +98,9 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.ExcludeDataFromBackup {
 		return errors.AssertionFailedf("ExcludeDataFromBackup set on system span config")
 	}
+	if s.StorageTier != StorageTier_DEFAULT {
+		return errors.AssertionFailedf("StorageTier set on system span config")
+	}
 	return nil
 }
 
@@ -1161,6 +1161,8 @@ var cockroachdbMetrics = map[string]string{
 	"physical_replication_failover_progress":                      "physical_replication.failover_progress",
 	"physical_replication_distsql_replan_count":                   "physical_replication.distsql_replan_count",
 	"physical_replication_events_ingested":                        "physical_replication.events_ingested",
+	"physical_replication_fingerprint_mismatches":                 "physical_replication.fingerprint_mismatches",
+	"physical_replication_fingerprint_verifications":              "physical_replication.fingerprint_verifications",
 	"physical_replication_flush_hist_nanos":                       "physical_replication.flush_hist_nanos",
 	"physical_replication_flush_hist_nanos_bucket":                "physical_replication.flush_hist_nanos.bucket",
 	"physical_replication_flush_hist_nanos_count":                 "physical_replication.flush_hist_nanos.count",
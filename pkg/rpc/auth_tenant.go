@@ -148,6 +148,9 @@ func (a tenantAuthorizer) authorize(
 	case "/cockroach.server.serverpb.Status/HotRangesV2":
 		return a.authHotRangesV2(tenID)
 
+	case "/cockroach.server.serverpb.Status/HotKeys":
+		return a.authHotKeys(tenID)
+
 	case "/cockroach.server.serverpb.Status/Nodes":
 		return a.capabilitiesAuthorizer.HasNodeStatusCapability(ctx, tenID)
 
@@ -412,6 +415,15 @@ func (a tenantAuthorizer) authHotRangesV2(tenID roachpb.TenantID) error {
 	return nil
 }
 
+// authHotKeys authorizes the provided tenant to invoke the HotKeys RPC. It
+// requires that an authorized tenantID has been set.
+func (a tenantAuthorizer) authHotKeys(tenID roachpb.TenantID) error {
+	if !tenID.IsSet() {
+		return authErrorf("hot keys request with unspecified tenant not permitted")
+	}
+	return nil
+}
+
 // authSpanConfigConformance authorizes the provided tenant to invoke the
 // SpanConfigConformance RPC with the provided args.
 func (a tenantAuthorizer) authSpanConfigConformance(
@@ -442,6 +442,20 @@ type hotRangeInfo struct {
 	SchemaName          string           `json:"schema_name"`
 	ReplicaNodeIDs      []roachpb.NodeID `json:"replica_node_ids"`
 	StoreID             roachpb.StoreID  `json:"store_id"`
+	HotKeys             []hotKeyInfo     `json:"hot_keys,omitempty"`
+}
+
+// Hot key details struct describes a key which accounts for a large fraction
+// of the load on a hot range that could not be split.
+type hotKeyInfo struct {
+	PrettyKey      string  `json:"pretty_key"`
+	Weight         float64 `json:"weight"`
+	Frequency      float64 `json:"frequency"`
+	DatabaseName   string  `json:"database_name,omitempty"`
+	SchemaName     string  `json:"schema_name,omitempty"`
+	TableName      string  `json:"table_name,omitempty"`
+	IndexName      string  `json:"index_name,omitempty"`
+	IndexKeyValues string  `json:"index_key_values,omitempty"`
 }
 
 // # List hot ranges
@@ -523,6 +537,18 @@ func (a *apiV2Server) listHotRanges(w http.ResponseWriter, r *http.Request) {
 				SchemaName:          r.SchemaName,
 				StoreID:             r.StoreID,
 			}
+			for _, hk := range r.HotKeys {
+				hotRangeInfos[i].HotKeys = append(hotRangeInfos[i].HotKeys, hotKeyInfo{
+					PrettyKey:      hk.PrettyKey,
+					Weight:         hk.Weight,
+					Frequency:      hk.Frequency,
+					DatabaseName:   hk.DatabaseName,
+					SchemaName:     hk.SchemaName,
+					TableName:      hk.TableName,
+					IndexName:      hk.IndexName,
+					IndexKeyValues: hk.IndexKeyValues,
+				})
+			}
 		}
 		return hotRangeInfos, nil
 	}
@@ -0,0 +1,112 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package apiutil
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// DecodedKey identifies the SQL row which a key belongs to.
+type DecodedKey struct {
+	Database string
+	Schema   string
+	Table    string
+	Index    string
+	// KeyValues contains the values of the index key columns which are present
+	// in the key, formatted as "col1=val1, col2=val2".
+	KeyValues string
+}
+
+// DecodeTableKey decodes a key into the database, schema, table and index it
+// belongs to, along with the values of the index key columns. The second
+// return value is false if the key does not belong to a table in the codec's
+// keyspace or the table cannot be found, e.g. because it was dropped. Key
+// columns which cannot be decoded are omitted from KeyValues.
+func DecodeTableKey(
+	ctx context.Context, txn descs.Txn, codec keys.SQLCodec, key roachpb.Key,
+) (DecodedKey, bool) {
+	sqlKey, err := codec.StripTenantPrefix(key)
+	if err != nil {
+		return DecodedKey{}, false
+	}
+	if keys.TableDataMin.Compare(sqlKey) > 0 || keys.TableDataMax.Compare(sqlKey) < 0 {
+		return DecodedKey{}, false
+	}
+	remaining, tableID, indexID, err := keys.DecodeTableIDIndexID(sqlKey)
+	if err != nil {
+		return DecodedKey{}, false
+	}
+
+	getter := txn.Descriptors().ByIDWithoutLeased(txn.KV()).WithoutNonPublic().Get()
+	table, err := getter.Table(ctx, descpb.ID(tableID))
+	if err != nil {
+		return DecodedKey{}, false
+	}
+	var decoded DecodedKey
+	decoded.Table = table.GetName()
+	if db, err := getter.Database(ctx, table.GetParentID()); err == nil {
+		decoded.Database = db.GetName()
+	}
+	if sc, err := getter.Schema(ctx, table.GetParentSchemaID()); err == nil {
+		decoded.Schema = sc.GetName()
+	}
+	index, err := catalog.MustFindIndexByID(table, descpb.IndexID(indexID))
+	if err != nil {
+		return decoded, true
+	}
+	decoded.Index = index.GetName()
+	decoded.KeyValues = decodeIndexKeyValues(table, index, remaining)
+	return decoded, true
+}
+
+// decodeIndexKeyValues formats the index key column values encoded in key,
+// which must have had its tenant, table and index prefix stripped. Only the
+// columns present in the key are included, and decoding stops at the first
+// column which cannot be decoded.
+func decodeIndexKeyValues(table catalog.TableDescriptor, index catalog.Index, key []byte) string {
+	n := index.NumKeyColumns()
+	colTypes := make([]*types.T, n)
+	dirs := make([]catenumpb.IndexColumn_Direction, n)
+	for i := 0; i < n; i++ {
+		col, err := catalog.MustFindColumnByID(table, index.GetKeyColumnID(i))
+		if err != nil {
+			return ""
+		}
+		colTypes[i] = col.GetType()
+		dirs[i] = index.GetKeyColumnDirection(i)
+	}
+
+	vals := make([]rowenc.EncDatum, n)
+	_, numVals, err := rowenc.DecodeKeyVals(vals, dirs, key)
+	if err != nil {
+		return ""
+	}
+	var a tree.DatumAlloc
+	var b strings.Builder
+	for i := 0; i < numVals; i++ {
+		if err := vals[i].EnsureDecoded(colTypes[i], &a); err != nil {
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(index.GetKeyColumnName(i))
+		b.WriteString("=")
+		b.WriteString(tree.AsStringWithFlags(vals[i].Datum, tree.FmtBareStrings))
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package apiutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/apiutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTableKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	runner.Exec(t, `CREATE SCHEMA sc`)
+	runner.Exec(t, `CREATE TABLE sc.tab (a INT, b STRING, c INT, PRIMARY KEY (a, b), INDEX c_idx (c DESC))`)
+	desc := desctestutils.TestingGetTableDescriptor(kvDB, s.Codec(), "defaultdb", "sc", "tab")
+	codec := s.Codec()
+	tableID := uint32(desc.GetID())
+	pkPrefix := codec.IndexPrefix(tableID, uint32(desc.GetPrimaryIndexID()))
+	cIdx := desc.PublicNonPrimaryIndexes()[0]
+	cPrefix := codec.IndexPrefix(tableID, uint32(cIdx.GetID()))
+
+	for _, tc := range []struct {
+		name     string
+		key      roachpb.Key
+		ok       bool
+		expected apiutil.DecodedKey
+	}{
+		{
+			name: "primary index row",
+			key: keys.MakeFamilyKey(
+				encoding.EncodeStringAscending(encoding.EncodeVarintAscending(pkPrefix.Clone(), 1), "x"), 0),
+			ok: true,
+			expected: apiutil.DecodedKey{
+				Database: "defaultdb", Schema: "sc", Table: "tab", Index: "tab_pkey", KeyValues: "a=1, b=x",
+			},
+		},
+		{
+			name: "primary index key prefix",
+			key:  encoding.EncodeVarintAscending(pkPrefix.Clone(), 1),
+			ok:   true,
+			expected: apiutil.DecodedKey{
+				Database: "defaultdb", Schema: "sc", Table: "tab", Index: "tab_pkey", KeyValues: "a=1",
+			},
+		},
+		{
+			name: "descending secondary index",
+			key: encoding.EncodeStringAscending(
+				encoding.EncodeVarintAscending(encoding.EncodeVarintDescending(cPrefix.Clone(), 7), 1), "x"),
+			ok: true,
+			expected: apiutil.DecodedKey{
+				Database: "defaultdb", Schema: "sc", Table: "tab", Index: "c_idx", KeyValues: "c=7",
+			},
+		},
+		{
+			name: "unknown index",
+			key:  encoding.EncodeVarintAscending(codec.IndexPrefix(tableID, 100), 1),
+			ok:   true,
+			expected: apiutil.DecodedKey{
+				Database: "defaultdb", Schema: "sc", Table: "tab",
+			},
+		},
+		{
+			name: "unknown table",
+			key:  encoding.EncodeVarintAscending(codec.IndexPrefix(tableID+1000, 1), 1),
+		},
+		{
+			name: "non-table key",
+			key:  keys.Meta2Prefix,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, s.InternalDB().(descs.DB).DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+				decoded, ok := apiutil.DecodeTableKey(ctx, txn, codec, tc.key)
+				require.Equal(t, tc.ok, ok)
+				require.Equal(t, tc.expected, decoded)
+				return nil
+			}))
+		})
+	}
+}
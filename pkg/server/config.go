@@ -726,6 +726,20 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 		}
 	}
 
+	var coldStorage cloud.ExternalStorage
+	if cfg.StorageConfig.ColdStorage.URI != "" {
+		if sharedStorage != nil {
+			return nil, errors.New("cold storage cannot be used with shared storage")
+		}
+		var err error
+		coldStorage, err = cloud.ExternalStorageFromURI(ctx, cfg.StorageConfig.ColdStorage.URI,
+			base.ExternalIODirConfig{}, cfg.Settings, nil, cfg.User, nil,
+			nil, cloud.NilMetrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var physicalStores int
 	for _, spec := range cfg.Stores.Specs {
 		if !spec.InMemory {
@@ -843,6 +857,15 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 				addCfgOpt(storage.SharedStorage(sharedStorage))
 				addCfgOpt(storage.SecondaryCache(storage.SecondaryCacheBytes(cfg.StorageConfig.SharedStorage.Cache, du)))
 			}
+			if coldStorage != nil {
+				// The store places the bottom-level SSTables of spans with the cold
+				// storage tier on the cold storage. Unlike the shared storage above,
+				// the store does not advertise shared storage support, so snapshots
+				// to and from it still copy the data.
+				detail(redact.Sprintf("store %d: cold spans on cold storage", i))
+				addCfgOpt(storage.ColdStorage(coldStorage))
+				addCfgOpt(storage.SecondaryCache(storage.SecondaryCacheBytes(cfg.StorageConfig.SharedStorage.Cache, du)))
+			}
 			addCfgOpt(storage.DiskMonitor(monitor))
 			// If the spec contains Pebble options, set those too.
 			if spec.PebbleOptions != "" {
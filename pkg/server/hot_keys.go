@@ -0,0 +1,219 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/split"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/apiutil"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultHotKeysPerRangeLimit is the number of hot keys returned for each
+// range when the request does not specify a limit.
+const defaultHotKeysPerRangeLimit = 10
+
+func (t *statusServer) HotKeys(
+	ctx context.Context, req *serverpb.HotKeysRequest,
+) (*serverpb.HotKeysResponse, error) {
+	ctx = t.AnnotateCtx(ctx)
+
+	if err := t.privilegeChecker.RequireViewClusterMetadataPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.sqlServer.tenantConnect.HotKeys(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.StatsOnly {
+		if err := t.decodeHotKeys(ctx, resp); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// HotKeys returns the heaviest keys of ranges whose load exceeds the load
+// based split threshold, but for which no split key could be found. The keys
+// are collected from all stores on the requested node, or on all nodes if the
+// request doesn't include a node ID.
+func (s *systemStatusServer) HotKeys(
+	ctx context.Context, req *serverpb.HotKeysRequest,
+) (*serverpb.HotKeysResponse, error) {
+	ctx = s.AnnotateCtx(authserver.ForwardSQLIdentityThroughRPCCalls(ctx))
+
+	if err := s.privilegeChecker.RequireViewClusterMetadataPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	var tenantID roachpb.TenantID
+	if len(req.TenantID) > 0 {
+		var err error
+		tenantID, err = roachpb.TenantIDFromString(req.TenantID)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	// Secondary tenants may only request their own hot keys.
+	if clientTenantID, ok := roachpb.ClientTenantFromContext(ctx); ok &&
+		!clientTenantID.IsSystem() && clientTenantID != tenantID {
+		return nil, status.Error(codes.PermissionDenied, "cannot request hot keys for another tenant")
+	}
+
+	response := &serverpb.HotKeysResponse{
+		ErrorsByNodeID: make(map[roachpb.NodeID]string),
+	}
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if local {
+			response.Ranges = s.localHotKeys(ctx, tenantID, requestedNodeID, int(req.PerRangeLimit))
+			// The system tenant decodes its own keys. Requests from secondary
+			// tenants are decoded by the tenant's status server, which has access
+			// to the tenant's catalog.
+			if !tenantID.IsSet() && !req.StatsOnly {
+				if err := s.decodeHotKeys(ctx, response); err != nil {
+					return nil, err
+				}
+			}
+			return response, nil
+		}
+		statusClient, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+		return statusClient.HotKeys(ctx, req)
+	}
+
+	remoteRequest := *req
+	remoteRequest.NodeID = "local"
+	nodeFn := func(ctx context.Context, status serverpb.RPCStatusClient, _ roachpb.NodeID) (*serverpb.HotKeysResponse, error) {
+		return status.HotKeys(ctx, &remoteRequest)
+	}
+	responseFn := func(nodeID roachpb.NodeID, nodeResp *serverpb.HotKeysResponse) {
+		response.Ranges = append(response.Ranges, nodeResp.Ranges...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		response.ErrorsByNodeID[nodeID] = err.Error()
+	}
+	timeout := HotRangesRequestNodeTimeout.Get(&s.st.SV)
+	if err := iterateNodes(ctx, s.serverIterator, s.stopper, "hot keys",
+		timeout,
+		s.dialNode,
+		nodeFn,
+		responseFn,
+		errorFn,
+	); err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	return response, nil
+}
+
+// localHotKeys returns the hot keys of the leaseholder replicas on the node's
+// stores. If tenantID is set, only the ranges and keys of that tenant are
+// returned.
+func (s *systemStatusServer) localHotKeys(
+	ctx context.Context, tenantID roachpb.TenantID, nodeID roachpb.NodeID, perRangeLimit int,
+) []*serverpb.HotKeysResponse_HotKeyRange {
+	if perRangeLimit <= 0 {
+		perRangeLimit = defaultHotKeysPerRangeLimit
+	}
+	var tenantSpan roachpb.Span
+	if tenantID.IsSet() {
+		tenantSpan = keys.MakeTenantSpan(tenantID)
+	}
+
+	var ranges []*serverpb.HotKeysResponse_HotKeyRange
+	_ = s.stores.VisitStores(func(store *kvserver.Store) error {
+		for _, info := range store.HotKeys(ctx, perRangeLimit) {
+			hotKeys := makeHotKeysProto(info.HotKeys, tenantSpan)
+			if len(hotKeys) == 0 {
+				continue
+			}
+			ranges = append(ranges, &serverpb.HotKeysResponse_HotKeyRange{
+				RangeID: info.Desc.RangeID,
+				NodeID:  nodeID,
+				StoreID: store.StoreID(),
+				HotKeys: hotKeys,
+			})
+		}
+		return nil
+	})
+	return ranges
+}
+
+// makeHotKeysProto converts the sampled hot keys into their protobuf
+// representation. If tenantSpan is set, keys outside of it are omitted.
+func makeHotKeysProto(hotKeys []split.HotKey, tenantSpan roachpb.Span) []serverpb.HotKey {
+	var result []serverpb.HotKey
+	for _, hk := range hotKeys {
+		if tenantSpan.Valid() && !tenantSpan.ContainsKey(hk.Key) {
+			continue
+		}
+		result = append(result, serverpb.HotKey{
+			Key:       hk.Key,
+			PrettyKey: keys.PrettyPrint(nil /* valDirs */, hk.Key),
+			Weight:    hk.Weight,
+			Frequency: hk.Frequency,
+		})
+	}
+	return result
+}
+
+// decodeHotKeys decodes the hot keys in the response into the database,
+// table, index and index key values they belong to. Like
+// addDescriptorsToHotRanges, this needs to run against the catalog of the
+// tenant which owns the keys.
+func (s *statusServer) decodeHotKeys(ctx context.Context, resp *serverpb.HotKeysResponse) error {
+	var hotKeys []*serverpb.HotKey
+	for _, r := range resp.Ranges {
+		for i := range r.HotKeys {
+			hotKeys = append(hotKeys, &r.HotKeys[i])
+		}
+	}
+	return s.decodeHotKeyDescriptors(ctx, hotKeys)
+}
+
+// decodeHotKeyDescriptors populates the SQL identifiers of the given hot keys
+// using the tenant's catalog. Keys which do not belong to a table are left
+// untouched.
+func (s *statusServer) decodeHotKeyDescriptors(ctx context.Context, hotKeys []*serverpb.HotKey) error {
+	if len(hotKeys) == 0 {
+		return nil
+	}
+	codec := s.sqlServer.execCfg.Codec
+	if err := s.sqlServer.distSQLServer.DB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		for _, hk := range hotKeys {
+			decoded, ok := apiutil.DecodeTableKey(ctx, txn, codec, hk.Key)
+			if !ok {
+				continue
+			}
+			hk.DatabaseName = decoded.Database
+			hk.SchemaName = decoded.Schema
+			hk.TableName = decoded.Table
+			hk.IndexName = decoded.Index
+			hk.IndexKeyValues = decoded.KeyValues
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "decoding hot keys")
+	}
+	return nil
+}
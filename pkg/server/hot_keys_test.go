@@ -0,0 +1,93 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHotKeys generates load on a single row, which exceeds the load based
+// split threshold without a split key being found, and checks that the row
+// is reported by the HotKeys RPC and crdb_internal.hot_keys.
+func TestHotKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	// The split finder needs to record load for split.RecordDurationThreshold
+	// before it gives up on finding a split key.
+	skip.UnderShort(t)
+	skip.UnderDuress(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	kvserver.LoadBasedRebalancingObjective.Override(ctx, &st.SV, kvserver.LBRebalancingQueries)
+	kvserver.SplitByLoadQPSThreshold.Override(ctx, &st.SV, 10)
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Settings:          st,
+		DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+	})
+	defer srv.Stopper().Stop(ctx)
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	runner.Exec(t, `CREATE TABLE kv (k INT PRIMARY KEY, v INT)`)
+	runner.Exec(t, `INSERT INTO kv VALUES (1, 0), (2, 0)`)
+
+	const query = `
+		SELECT database_name, schema_name, table_name, index_name, index_key_values
+		FROM crdb_internal.hot_keys
+		WHERE table_name = 'kv'`
+	expected := [][]string{{"defaultdb", "public", "kv", "kv_pkey", "k=1"}}
+	testutils.SucceedsWithin(t, func() error {
+		for i := 0; i < 100; i++ {
+			runner.Exec(t, `UPDATE kv SET v = v + 1 WHERE k = 1`)
+		}
+		if rows := runner.QueryStr(t, query); len(rows) == 0 {
+			return errors.New("no hot keys reported for table kv")
+		}
+		return nil
+	}, 2*time.Minute)
+	// Only the row under load is reported.
+	runner.CheckQueryResults(t, query, expected)
+
+	// The RPC decodes the keys unless only the stats are requested.
+	status := srv.StatusServer().(serverpb.StatusServer)
+	for _, statsOnly := range []bool{false, true} {
+		resp, err := status.HotKeys(ctx, &serverpb.HotKeysRequest{StatsOnly: statsOnly})
+		require.NoError(t, err)
+		require.Empty(t, resp.ErrorsByNodeID)
+		var found bool
+		for _, r := range resp.Ranges {
+			for _, hk := range r.HotKeys {
+				if statsOnly {
+					require.Empty(t, hk.TableName)
+					found = found || hk.PrettyKey != ""
+					continue
+				}
+				if hk.TableName != "kv" {
+					continue
+				}
+				found = true
+				require.Equal(t, "k=1", hk.IndexKeyValues)
+				require.Positive(t, hk.Weight)
+			}
+		}
+		require.True(t, found, "stats only: %t", statsOnly)
+	}
+}
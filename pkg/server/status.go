@@ -3013,6 +3013,10 @@ func (s *systemStatusServer) localHotRanges(
 ) (*serverpb.HotRangesResponseV2, error) {
 	// Initialize response object
 	var resp serverpb.HotRangesResponseV2
+	var tenantSpan roachpb.Span
+	if tenantID.IsSet() {
+		tenantSpan = keys.MakeTenantSpan(tenantID)
+	}
 
 	// Visit each store in the node to collect hot range information
 	err := s.stores.VisitStores(func(store *kvserver.Store) error {
@@ -3026,12 +3030,14 @@ func (s *systemStatusServer) localHotRanges(
 
 		// Process each hot range and build the response
 		for _, r := range ranges {
-			// Get leaseholder information for the range
+			// Get leaseholder and hot key information for the range
 			var leaseholderNodeID roachpb.NodeID
+			var hotKeys []serverpb.HotKey
 			replica, err := store.GetReplica(r.Desc.GetRangeID())
 			if err == nil {
 				lease, _ := replica.GetLease()
 				leaseholderNodeID = lease.Replica.NodeID
+				hotKeys = makeHotKeysProto(replica.HotKeys(defaultHotKeysPerRangeLimit), tenantSpan)
 			}
 
 			// Collect node IDs for all replicas of this range
@@ -3057,6 +3063,9 @@ func (s *systemStatusServer) localHotRanges(
 				WriteBytesPerSecond: r.WriteBytesPerSecond,
 				ReadBytesPerSecond:  r.ReadBytesPerSecond,
 				CPUTimePerSecond:    r.CPUTimePerSecond,
+
+				// Keys the load is concentrated on, if no split key was found
+				HotKeys: hotKeys,
 			}
 			resp.Ranges = append(resp.Ranges, rp)
 		}
@@ -3121,11 +3130,15 @@ func (s *statusServer) addDescriptorsToHotRanges(
 	}
 
 	// Add descriptors back into hot ranges object.
+	var hotKeys []*serverpb.HotKey
 	for _, r := range hr.Ranges {
 		// Get database/table/index names for this range
 		r.Databases, r.Tables, r.Indexes = rangeIndexMappings[r.Desc.RangeID].ToOutput()
+		for i := range r.HotKeys {
+			hotKeys = append(hotKeys, &r.HotKeys[i])
+		}
 	}
-	return nil
+	return s.decodeHotKeyDescriptors(ctx, hotKeys)
 }
 
 func (s *statusServer) KeyVisSamples(
@@ -48,6 +48,7 @@ var fields = [config.NumFields]Field{
 	constraints,
 	voterConstraints,
 	leasePreferences,
+	storageTier,
 }
 
 const (
@@ -60,4 +61,5 @@ const (
 	constraints      = constraintsConjunctionField(config.Constraints)
 	voterConstraints = constraintsConjunctionField(config.VoterConstraints)
 	leasePreferences = leasePreferencesField(config.LeasePreferences)
+	storageTier      = storageTierField(config.StorageTier)
 )
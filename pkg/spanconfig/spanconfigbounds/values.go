@@ -56,6 +56,15 @@ func (l leasePreferencesValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
 	s.Printf("%v", []roachpb.LeasePreference(l))
 }
 
+type storageTierValue roachpb.StorageTier
+
+func (t storageTierValue) String() string {
+	return roachpb.StorageTier(t).String()
+}
+func (t storageTierValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
+	s.Print(redact.SafeString(roachpb.StorageTier(t).String()))
+}
+
 type boolValue bool
 
 func (b boolValue) String() string {
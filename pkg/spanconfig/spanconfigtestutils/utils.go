@@ -664,6 +664,9 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.ExcludeDataFromBackup != defaultConf.ExcludeDataFromBackup {
 		diffs = append(diffs, fmt.Sprintf("exclude_data_from_backup=%v", conf.ExcludeDataFromBackup))
 	}
+	if conf.StorageTier != defaultConf.StorageTier {
+		diffs = append(diffs, fmt.Sprintf("storage_tier=%s", conf.StorageTier))
+	}
 
 	return strings.Join(diffs, " ")
 }
@@ -10,6 +10,7 @@ import (
 	"context"
 	gojson "encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 
@@ -2417,7 +2418,16 @@ func (p *planner) checkSchemaChangeIsAllowed(
 			}
 		}
 		kvWriterEnabled := sqlclustersettings.LDRWriterType(sqlclustersettings.LDRImmediateModeWriter.Get(&p.execCfg.Settings.SV))
-		if !tree.IsAllowedLDRSchemaChange(n, virtualColNames, kvWriterEnabled == sqlclustersettings.LDRWriterTypeLegacyKV) {
+		// Jobs that replicate schema changes replay them on the destination, so
+		// those schema changes are allowed when a job replays them, or on the
+		// source of a job if every job replicates them.
+		td := desc.TableDesc()
+		replicated := tree.IsReplicatedLDRSchemaChange(n) &&
+			(slices.Contains(td.LDRSchemaChangeJobIDs, catpb.JobID(p.SessionData().LogicalReplicationSchemaChangeJobID)) ||
+				(len(td.LDRSchemaChangeSourceJobIDs) > 0 && !slices.ContainsFunc(td.LDRJobIDs, func(id catpb.JobID) bool {
+					return !slices.Contains(td.LDRSchemaChangeJobIDs, id)
+				})))
+		if !replicated && !tree.IsAllowedLDRSchemaChange(n, virtualColNames, kvWriterEnabled == sqlclustersettings.LDRWriterTypeLegacyKV) {
 			return sqlerrors.NewDisallowedSchemaChangeOnLDRTableErr(desc.GetName(), desc.TableDesc().LDRJobIDs)
 		}
 	}
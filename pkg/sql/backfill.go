@@ -2804,6 +2804,16 @@ func validateFkInTxn(
 		})
 }
 
+// ValidateForeignKeyInTxn validates the named outbound foreign key of srcTable
+// within the provided transaction. It is exported for jobs outside of the
+// schema changer that rewrite the contents of a table, such as the revert of a
+// table to an earlier timestamp.
+func ValidateForeignKeyInTxn(
+	ctx context.Context, txn descs.Txn, srcTable *tabledesc.Mutable, fkName string,
+) error {
+	return validateFkInTxn(ctx, txn, srcTable, fkName)
+}
+
 // validateUniqueWithoutIndexConstraintInTxn validates a unique constraint
 // within the provided transaction. If the provided table descriptor version
 // is newer than the cluster version, it will be used in the InternalExecutor
@@ -0,0 +1,83 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// ValidateRowFilter verifies that an expression is a valid filter over the
+// rows of a table, such as the row_filter option of a RESTORE. If the
+// expression is valid, it returns the serialized expression with the columns
+// dequalified, along with the set of columns it references.
+//
+// A row filter is valid under the same conditions as a partial index
+// predicate: it results in a boolean, refers only to columns in the table, and
+// does not include subqueries or non-immutable, aggregate, window, or set
+// returning functions.
+func ValidateRowFilter(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	e tree.Expr,
+	tn *tree.TableName,
+	semaCtx *tree.SemaContext,
+	version clusterversion.ClusterVersion,
+) (string, catalog.TableColSet, error) {
+	expr, _, cols, err := DequalifyAndValidateExpr(
+		ctx,
+		desc,
+		e,
+		types.Bool,
+		tree.RestoreRowFilterExpr,
+		semaCtx,
+		volatility.Immutable,
+		tn,
+		version,
+	)
+	if err != nil {
+		return "", catalog.TableColSet{}, err
+	}
+	return expr, cols, nil
+}
+
+// MakeRowFilterExpr turns a row filter previously validated with
+// ValidateRowFilter into a TypedExpr. The IndexedVars of the returned
+// expression index into the public columns of the table, so it can be
+// evaluated with a RowIndexedVarContainer whose Cols are table.PublicColumns().
+func MakeRowFilterExpr(
+	ctx context.Context,
+	table catalog.TableDescriptor,
+	filter string,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+) (tree.TypedExpr, error) {
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return nil, err
+	}
+	tn := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
+	nr := newNameResolver(table.GetID(), tn, table.PublicColumns())
+	nr.addIVarContainerToSemaCtx(semaCtx)
+	expr, err = nr.resolveNames(expr)
+	if err != nil {
+		return nil, err
+	}
+	typedExpr, err := tree.TypeCheck(ctx, expr, semaCtx, types.Bool)
+	if err != nil {
+		return nil, err
+	}
+	var txCtx transform.ExprTransformContext
+	return txCtx.NormalizeExpr(ctx, evalCtx, typedExpr)
+}
@@ -228,6 +228,12 @@ func checkSrcDstColsMatch(
 	return nil
 }
 
+// CheckLogicalReplicationTypesMatch verifies that values of a source column of
+// type srcTyp can be replicated into a destination column of type dstTyp.
+func CheckLogicalReplicationTypesMatch(srcTyp *types.T, dstTyp *types.T) error {
+	return checkTypesMatch(srcTyp, dstTyp)
+}
+
 // checkTypesMatch checks that the source and destination types match. Enums
 // need to be equal in both physical and logical representations.
 func checkTypesMatch(srcTyp *types.T, dstTyp *types.T) error {
@@ -2523,6 +2523,9 @@ func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 		if ttl.DisableChangefeedReplication {
 			appendStorageParam(`ttl_disable_changefeed_replication`, fmt.Sprintf("%t", ttl.DisableChangefeedReplication))
 		}
+		if ttl.MVCCExpiration {
+			appendStorageParam(`ttl_mvcc_expiration`, fmt.Sprintf("%t", ttl.MVCCExpiration))
+		}
 	}
 	if exclude := desc.GetExcludeDataFromBackup(); exclude {
 		appendStorageParam(`exclude_data_from_backup`, `true`)
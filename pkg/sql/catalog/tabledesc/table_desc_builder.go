@@ -310,6 +310,20 @@ func (tdb *tableDescriptorBuilder) StripDanglingBackReferences(
 			}
 			return false
 		})
+		tbl.LDRSchemaChangeJobIDs = slices.DeleteFunc(tbl.LDRSchemaChangeJobIDs, func(i catpb.JobID) bool {
+			if !nonTerminalJobIDMightExist(i) {
+				tdb.changes.Add(catalog.StrippedDanglingBackReferences)
+				return true
+			}
+			return false
+		})
+		tbl.LDRSchemaChangeSourceJobIDs = slices.DeleteFunc(tbl.LDRSchemaChangeSourceJobIDs, func(i catpb.JobID) bool {
+			if !nonTerminalJobIDMightExist(i) {
+				tdb.changes.Add(catalog.StrippedDanglingBackReferences)
+				return true
+			}
+			return false
+		})
 	}
 	// ... in the sequence ownership field.
 	if seq := tbl.SequenceOpts; seq != nil {
@@ -158,8 +158,10 @@ func TestStripDanglingBackReferencesAndRoles(t *testing.T) {
 					{MutationID: 1},
 					{MutationID: 2},
 				},
-				LDRJobIDs:  []catpb.JobID{1, 2, 3},
-				Privileges: goodPrivilege,
+				LDRJobIDs:                   []catpb.JobID{1, 2, 3},
+				LDRSchemaChangeJobIDs:       []catpb.JobID{2},
+				LDRSchemaChangeSourceJobIDs: []catpb.JobID{2},
+				Privileges:                  goodPrivilege,
 			},
 			expectedOutput: descpb.TableDescriptor{
 				Name: "foo",
@@ -171,8 +173,10 @@ func TestStripDanglingBackReferencesAndRoles(t *testing.T) {
 					{MutationID: 1},
 					{MutationID: 2},
 				},
-				LDRJobIDs:  []catpb.JobID{},
-				Privileges: goodPrivilege,
+				LDRJobIDs:                   []catpb.JobID{},
+				LDRSchemaChangeJobIDs:       []catpb.JobID{},
+				LDRSchemaChangeSourceJobIDs: []catpb.JobID{},
+				Privileges:                  goodPrivilege,
 			},
 			validDescIDs:                   catalog.MakeDescriptorIDSet(100, 101, 104, 105),
 			validJobIDs:                    map[jobspb.JobID]struct{}{111222333444: {}},
@@ -6,6 +6,7 @@
 package tabledesc
 
 import (
+	"math"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -14,6 +15,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/errors"
 	"github.com/robfig/cron/v3"
 )
@@ -59,9 +62,61 @@ func ValidateRowLevelTTL(ttl *catpb.RowLevelTTL) error {
 			return err
 		}
 	}
+	if ttl.MVCCExpiration {
+		if ttl.HasExpirationExpr() {
+			return pgerror.Newf(
+				pgcode.InvalidParameterValue,
+				`"ttl_mvcc_expiration" cannot be used with "ttl_expiration_expression"`,
+			)
+		}
+		if !ttl.HasDurationExpr() {
+			return pgerror.Newf(
+				pgcode.InvalidParameterValue,
+				`"ttl_mvcc_expiration" requires "ttl_expire_after" to be set`,
+			)
+		}
+		if _, err := MVCCExpireAfter(ttl); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// MVCCExpireAfter returns the duration after which the values written to a
+// table with ttl_mvcc_expiration set expire at the MVCC layer, as given by its
+// ttl_expire_after interval. Intervals with a month component are rejected, as
+// months don't have a fixed duration and the MVCC expiration would not match
+// the crdb_internal_expiration column.
+func MVCCExpireAfter(ttl *catpb.RowLevelTTL) (time.Duration, error) {
+	expr, err := parser.ParseExpr(string(ttl.DurationExpr))
+	if err != nil {
+		return 0, errors.Wrapf(err, "ttl_expire_after %q must be a valid expression", ttl.DurationExpr)
+	}
+	// The DurationExpr is a serialized interval, i.e. '1 day':::INTERVAL.
+	var str *tree.StrVal
+	if annotated, ok := expr.(*tree.AnnotateTypeExpr); ok {
+		str, _ = annotated.Expr.(*tree.StrVal)
+	}
+	if str == nil {
+		return 0, errors.AssertionFailedf("unexpected ttl_expire_after expression %q", ttl.DurationExpr)
+	}
+	d, err := tree.ParseDInterval(duration.IntervalStyle_POSTGRES, str.RawString())
+	if err != nil {
+		return 0, err
+	}
+	if d.Months != 0 {
+		return 0, pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`"ttl_mvcc_expiration" requires "ttl_expire_after" to not have a month component, found %s`,
+			ttl.DurationExpr,
+		)
+	}
+	if secs, ok := d.AsInt64(); !ok || secs > math.MaxInt64/int64(time.Second) {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue, `"ttl_expire_after" is too large`)
+	}
+	return time.Duration(d.Days)*24*time.Hour + time.Duration(d.Nanos()), nil
+}
+
 // ValidateTTLExpirationExpr validates that the ttl_expiration_expression, if
 // any, only references existing columns.
 func ValidateTTLExpirationExpr(desc catalog.TableDescriptor) error {
@@ -129,6 +184,43 @@ func ValidateTTLExpirationColumn(desc catalog.TableDescriptor) error {
 	return nil
 }
 
+// ValidateTTLMVCCExpiration validates that a table with ttl_mvcc_expiration
+// set only has a primary index with a single column family. Every KV of a row
+// must then be rewritten, and have its expiration extended, when the row is
+// updated, so that a row never partially expires. Foreign keys are rejected in
+// both directions, since rows expire in storage without running the foreign
+// key actions or checks that a SQL deletion runs.
+func ValidateTTLMVCCExpiration(desc catalog.TableDescriptor) error {
+	if !desc.HasRowLevelTTL() || !desc.GetRowLevelTTL().MVCCExpiration {
+		return nil
+	}
+	if len(desc.DeletableNonPrimaryIndexes()) > 0 {
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			`"ttl_mvcc_expiration" is not supported on tables with secondary indexes`,
+		)
+	}
+	if len(desc.GetFamilies()) > 1 {
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			`"ttl_mvcc_expiration" is not supported on tables with multiple column families`,
+		)
+	}
+	if len(desc.OutboundForeignKeys()) > 0 {
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			`"ttl_mvcc_expiration" is not supported on tables with foreign keys`,
+		)
+	}
+	if len(desc.InboundForeignKeys()) > 0 {
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			`"ttl_mvcc_expiration" is not supported on tables referenced by foreign keys`,
+		)
+	}
+	return nil
+}
+
 // ValidateTTLBatchSize validates the batch size of a TTL.
 func ValidateTTLBatchSize(key string, val int64) error {
 	if val <= 0 {
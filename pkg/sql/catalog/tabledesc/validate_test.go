@@ -139,14 +139,16 @@ var validationMap = []struct {
 			"External": {status: todoIAmKnowinglyAddingTechDebt,
 				reason: "TODO(features): add validation that TableID is sane within the same tenant"},
 			// LDRJobIDs is checked in StripDanglingBackreferences.
-			"LDRJobIDs":               {status: iSolemnlySwearThisFieldIsValidated},
-			"ReplicatedPCRVersion":    {status: thisFieldReferencesNoObjects},
-			"Triggers":                {status: iSolemnlySwearThisFieldIsValidated},
-			"NextTriggerID":           {status: thisFieldReferencesNoObjects},
-			"Policies":                {status: iSolemnlySwearThisFieldIsValidated},
-			"NextPolicyID":            {status: iSolemnlySwearThisFieldIsValidated},
-			"RowLevelSecurityEnabled": {status: thisFieldReferencesNoObjects},
-			"RowLevelSecurityForced":  {status: thisFieldReferencesNoObjects},
+			"LDRJobIDs":                   {status: iSolemnlySwearThisFieldIsValidated},
+			"LDRSchemaChangeJobIDs":       {status: iSolemnlySwearThisFieldIsValidated},
+			"LDRSchemaChangeSourceJobIDs": {status: iSolemnlySwearThisFieldIsValidated},
+			"ReplicatedPCRVersion":        {status: thisFieldReferencesNoObjects},
+			"Triggers":                    {status: iSolemnlySwearThisFieldIsValidated},
+			"NextTriggerID":               {status: thisFieldReferencesNoObjects},
+			"Policies":                    {status: iSolemnlySwearThisFieldIsValidated},
+			"NextPolicyID":                {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelSecurityEnabled":     {status: thisFieldReferencesNoObjects},
+			"RowLevelSecurityForced":      {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -130,6 +130,13 @@ func init() {
 				c.InheritedLeasePreferences = false
 			},
 		},
+		{
+			Field:        config.StorageTier,
+			RequiredType: types.String,
+			Setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.StorageTier = proto.String(string(tree.MustBeDString(d)))
+			},
+		},
 	}
 	SupportedZoneConfigOptions = make(map[tree.Name]ZoneConfigOption, len(opts))
 	ZoneOptionKeys = make([]string, len(opts))
@@ -72,6 +72,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
@@ -3667,6 +3668,19 @@ func (ex *connExecutor) QualityOfService() sessiondatapb.QoSLevel {
 	return ex.sessionData().DefaultTxnQualityOfService
 }
 
+// resourceGroup returns the workload resource group that transactions of the
+// session are admitted as: the resource_group session variable if set, else
+// the group that the application_name is assigned to, if any.
+func (ex *connExecutor) resourceGroup() string {
+	if ex.sessionData() == nil {
+		return ""
+	}
+	if rg := ex.sessionData().ResourceGroup; rg != "" {
+		return rg
+	}
+	return admission.ResourceGroupForApplication(&ex.server.cfg.Settings.SV, ex.sessionData().ApplicationName)
+}
+
 // copyQualityOfService returns the QoSLevel session setting for COPY if the
 // session settings are populated, otherwise the background QoSLevel.
 func (ex *connExecutor) copyQualityOfService() sessiondatapb.QoSLevel {
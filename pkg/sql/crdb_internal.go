@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/apiutil"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -232,6 +233,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalFullyQualifiedNamesViewID:          crdbInternalFullyQualifiedNamesView,
 		catconstants.CrdbInternalStoreLivenessSupportFrom:           crdbInternalStoreLivenessSupportFromTable,
 		catconstants.CrdbInternalStoreLivenessSupportFor:            crdbInternalStoreLivenessSupportForTable,
+		catconstants.CrdbInternalHotKeysTableID:                     crdbInternalHotKeysTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -9816,3 +9818,66 @@ func populateStoreLivenessSupportResponse(
 	}
 	return nil
 }
+
+var crdbInternalHotKeysTable = virtualSchemaTable{
+	comment: `cluster-wide heaviest keys of ranges whose load exceeds the load based
+split threshold without a split key being found. Querying this table is an
+expensive operation since it creates a cluster-wide RPC-fanout.`,
+	schema: `
+CREATE TABLE crdb_internal.hot_keys (
+  range_id          INT NOT NULL,
+  node_id           INT NOT NULL,
+  store_id          INT NOT NULL,
+  key               BYTES NOT NULL,
+  pretty_key        STRING NOT NULL,
+  weight            FLOAT NOT NULL,
+  frequency         FLOAT NOT NULL,
+  database_name     STRING,
+  schema_name       STRING,
+  table_name        STRING,
+  index_name        STRING,
+  index_key_values  STRING
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.CheckPrivilege(ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.VIEWCLUSTERMETADATA); err != nil {
+			return err
+		}
+
+		// The keys are decoded below using the planner's transaction, since the
+		// status server may not have access to this tenant's catalog.
+		resp, err := p.ExecCfg().TenantStatusServer.HotKeys(ctx, &serverpb.HotKeysRequest{StatsOnly: true})
+		if err != nil {
+			return err
+		}
+
+		codec := p.ExecCfg().Codec
+		stringOrNull := func(s string) tree.Datum {
+			if s == "" {
+				return tree.DNull
+			}
+			return tree.NewDString(s)
+		}
+		for _, r := range resp.Ranges {
+			for _, hk := range r.HotKeys {
+				decoded, _ := apiutil.DecodeTableKey(ctx, p.InternalSQLTxn(), codec, hk.Key)
+				if err := addRow(
+					tree.NewDInt(tree.DInt(r.RangeID)),        // range_id
+					tree.NewDInt(tree.DInt(r.NodeID)),         // node_id
+					tree.NewDInt(tree.DInt(r.StoreID)),        // store_id
+					tree.NewDBytes(tree.DBytes(hk.Key)),       // key
+					tree.NewDString(hk.PrettyKey),             // pretty_key
+					tree.NewDFloat(tree.DFloat(hk.Weight)),    // weight
+					tree.NewDFloat(tree.DFloat(hk.Frequency)), // frequency
+					stringOrNull(decoded.Database),            // database_name
+					stringOrNull(decoded.Schema),              // schema_name
+					stringOrNull(decoded.Table),               // table_name
+					stringOrNull(decoded.Index),               // index_name
+					stringOrNull(decoded.KeyValues),           // index_key_values
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
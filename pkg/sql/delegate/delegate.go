@@ -122,6 +122,9 @@ func TryDelegate(
 	case *tree.ShowRegions:
 		return d.delegateShowRegions(t)
 
+	case *tree.ShowResourceGroups:
+		return d.delegateShowResourceGroups()
+
 	case *tree.ShowRoleGrants:
 		return d.delegateShowRoleGrants(t)
 
@@ -176,6 +179,15 @@ func TryDelegate(
 			Command: t.Command,
 		})
 
+	case *tree.CreateResourceGroup:
+		return d.delegateCreateResourceGroup(t)
+
+	case *tree.AlterResourceGroup:
+		return d.delegateAlterResourceGroup(t)
+
+	case *tree.DropResourceGroup:
+		return d.delegateDropResourceGroup(t)
+
 	case *tree.ShowFullTableScans:
 		return d.delegateShowFullTableScans()
 
@@ -0,0 +1,170 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package delegate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/errors"
+)
+
+// Resource groups are stored in the admission.resource_groups cluster setting
+// of the virtual cluster. The CREATE, ALTER and DROP RESOURCE GROUP
+// statements are rewritten into a SET CLUSTER SETTING statement that writes
+// the modified list of groups, so they require the same privileges. The write
+// is conditional on the setting still having the value the groups were read
+// from, so that concurrent statements do not overwrite each other's changes;
+// the statement that loses fails with a retryable error. SET CLUSTER SETTING
+// waits for the new value to be visible on the gateway, so consecutive
+// statements on the same node observe each other's changes.
+
+func (d *delegator) delegateCreateResourceGroup(
+	stmt *tree.CreateResourceGroup,
+) (tree.Statement, error) {
+	name := string(stmt.Name)
+	if err := admission.ValidateResourceGroupName(name); err != nil {
+		return nil, pgerror.WithCandidateCode(err, pgcode.InvalidName)
+	}
+	spec, groups, err := d.resourceGroups()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := groups[name]; ok {
+		if !stmt.IfNotExists {
+			return nil, pgerror.Newf(pgcode.DuplicateObject, "resource group %q already exists", name)
+		}
+	} else {
+		g := admission.NewResourceGroup(name)
+		if err := setResourceGroupOptions(&g, stmt.Options); err != nil {
+			return nil, err
+		}
+		groups[name] = g
+	}
+	return setResourceGroups(spec, groups)
+}
+
+func (d *delegator) delegateAlterResourceGroup(
+	stmt *tree.AlterResourceGroup,
+) (tree.Statement, error) {
+	name := string(stmt.Name)
+	spec, groups, err := d.resourceGroups()
+	if err != nil {
+		return nil, err
+	}
+	g, ok := groups[name]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "resource group %q does not exist", name)
+	}
+	if err := setResourceGroupOptions(&g, stmt.Options); err != nil {
+		return nil, err
+	}
+	groups[name] = g
+	return setResourceGroups(spec, groups)
+}
+
+func (d *delegator) delegateDropResourceGroup(
+	stmt *tree.DropResourceGroup,
+) (tree.Statement, error) {
+	name := string(stmt.Name)
+	spec, groups, err := d.resourceGroups()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := groups[name]; !ok && !stmt.IfExists {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "resource group %q does not exist", name)
+	}
+	delete(groups, name)
+	return setResourceGroups(spec, groups)
+}
+
+func (d *delegator) delegateShowResourceGroups() (tree.Statement, error) {
+	hasModify, hasSqlModify, hasView, err := d.clusterSettingPrivileges()
+	if err != nil {
+		return nil, err
+	}
+	if !hasView && !hasModify && !hasSqlModify {
+		return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+			"only users with %s, %s or %s privileges are allowed to SHOW RESOURCE GROUPS",
+			privilege.MODIFYCLUSTERSETTING, privilege.MODIFYSQLCLUSTERSETTING, privilege.VIEWCLUSTERSETTING)
+	}
+	_, groups, err := d.resourceGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []string
+	for _, g := range groups {
+		apps := make([]string, len(g.ApplicationNames))
+		for i, app := range g.ApplicationNames {
+			apps[i] = lexbase.EscapeSQLString(app)
+		}
+		rows = append(rows, fmt.Sprintf("(%s, %d, %d, %d, %d, ARRAY[%s]::STRING[])",
+			lexbase.EscapeSQLString(g.Name), g.CPUWeight, g.IOWeight, g.CPUBurst, g.IOBurst,
+			strings.Join(apps, ", ")))
+	}
+	filter := ""
+	if len(rows) == 0 {
+		// VALUES needs at least one row to infer the column types.
+		rows = append(rows, "('', 0, 0, 0, 0, ARRAY[]::STRING[])")
+		filter = " WHERE false"
+	}
+	return d.parse(fmt.Sprintf(`
+SELECT name, cpu_weight, io_weight, cpu_burst, io_burst, applications
+FROM (VALUES %s) AS g(name, cpu_weight, io_weight, cpu_burst, io_burst, applications)%s
+ORDER BY name`, strings.Join(rows, ", "), filter))
+}
+
+// resourceGroups returns the value of the admission.resource_groups setting of
+// the current virtual cluster and the groups it defines.
+func (d *delegator) resourceGroups() (string, map[string]admission.ResourceGroup, error) {
+	spec := admission.ResourceGroupsSetting.Get(&d.evalCtx.Settings.SV)
+	groups, err := admission.ParseResourceGroups(spec)
+	return spec, groups, err
+}
+
+// setResourceGroups returns the statement that replaces the groups that were
+// read from prevSpec.
+func setResourceGroups(
+	prevSpec string, groups map[string]admission.ResourceGroup,
+) (tree.Statement, error) {
+	spec := admission.FormatResourceGroups(groups)
+	// Validate the groups as a whole, e.g. that no application is assigned to
+	// more than one group, to report the error against this statement.
+	if _, err := admission.ParseResourceGroups(spec); err != nil {
+		return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+	}
+	return &tree.SetClusterSetting{
+		Name:          string(admission.ResourceGroupsSetting.Name()),
+		Value:         tree.NewStrVal(spec),
+		ExpectedValue: &prevSpec,
+	}, nil
+}
+
+func setResourceGroupOptions(g *admission.ResourceGroup, opts tree.KVOptions) error {
+	for _, opt := range opts {
+		key := string(opt.Key)
+		val, ok := opt.Value.(*tree.StrVal)
+		if !ok {
+			if opt.Value == nil {
+				return pgerror.Newf(pgcode.InvalidParameterValue, "option %q requires a value", key)
+			}
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"placeholders are not supported for option %q", key)
+		}
+		if err := g.SetAttribute(key, val.RawString()); err != nil {
+			return pgerror.WithCandidateCode(
+				errors.Wrapf(err, "resource group %q", g.Name), pgcode.InvalidParameterValue)
+		}
+	}
+	return nil
+}
@@ -18,10 +18,35 @@ func (d *delegator) delegateShowClusterSettingList(
 	stmt *tree.ShowClusterSettingList,
 ) (tree.Statement, error) {
 
+	hasModify, hasSqlModify, hasView, err := d.clusterSettingPrivileges()
+	if err != nil {
+		return nil, err
+	}
+
+	// If user is not admin and has neither privilege, return an error.
+	if !hasView && !hasModify && !hasSqlModify {
+		return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+			"only users with %s, %s or %s privileges are allowed to SHOW CLUSTER SETTINGS",
+			privilege.MODIFYCLUSTERSETTING, privilege.MODIFYSQLCLUSTERSETTING, privilege.VIEWCLUSTERSETTING)
+	}
+
+	if stmt.All {
+		return d.parse(
+			`SELECT variable, value, type AS setting_type, public, description, default_value, origin
+       FROM   crdb_internal.cluster_settings`,
+		)
+	}
+	return d.parse(
+		`SELECT variable, value, type AS setting_type, description, default_value, origin
+     FROM   crdb_internal.cluster_settings
+     WHERE  public IS TRUE`,
+	)
+}
+
+// clusterSettingPrivileges returns whether the current user may modify all
+// cluster settings, modify SQL cluster settings and view cluster settings.
+func (d *delegator) clusterSettingPrivileges() (hasModify, hasSqlModify, hasView bool, _ error) {
 	// First check system privileges.
-	hasModify := false
-	hasSqlModify := false
-	hasView := false
 	cat := d.catalog
 	globalPrivObj := syntheticprivilege.GlobalPrivilegeObject
 	user := cat.GetCurrentUser()
@@ -30,21 +55,21 @@ func (d *delegator) delegateShowClusterSettingList(
 		hasSqlModify = true
 		hasView = true
 	} else if pgerror.GetPGCode(err) != pgcode.InsufficientPrivilege {
-		return nil, err
+		return false, false, false, err
 	}
 	if !hasSqlModify {
 		if err := cat.CheckPrivilege(d.ctx, globalPrivObj, user, privilege.MODIFYSQLCLUSTERSETTING); err == nil {
 			hasSqlModify = true
 			hasView = true
 		} else if pgerror.GetPGCode(err) != pgcode.InsufficientPrivilege {
-			return nil, err
+			return false, false, false, err
 		}
 	}
 	if !hasView {
 		if err := cat.CheckPrivilege(d.ctx, globalPrivObj, user, privilege.VIEWCLUSTERSETTING); err == nil {
 			hasView = true
 		} else if pgerror.GetPGCode(err) != pgcode.InsufficientPrivilege {
-			return nil, err
+			return false, false, false, err
 		}
 	}
 
@@ -52,7 +77,7 @@ func (d *delegator) delegateShowClusterSettingList(
 	if !hasModify {
 		ok, err := cat.HasRoleOption(d.ctx, roleoption.MODIFYCLUSTERSETTING)
 		if err != nil {
-			return nil, err
+			return false, false, false, err
 		}
 		hasModify = hasModify || ok
 		hasView = hasView || ok
@@ -61,29 +86,12 @@ func (d *delegator) delegateShowClusterSettingList(
 	if !hasView {
 		ok, err := cat.HasRoleOption(d.ctx, roleoption.VIEWCLUSTERSETTING)
 		if err != nil {
-			return nil, err
+			return false, false, false, err
 		}
 		hasView = hasView || ok
 	}
 
-	// If user is not admin and has neither privilege, return an error.
-	if !hasView && !hasModify && !hasSqlModify {
-		return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
-			"only users with %s, %s or %s privileges are allowed to SHOW CLUSTER SETTINGS",
-			privilege.MODIFYCLUSTERSETTING, privilege.MODIFYSQLCLUSTERSETTING, privilege.VIEWCLUSTERSETTING)
-	}
-
-	if stmt.All {
-		return d.parse(
-			`SELECT variable, value, type AS setting_type, public, description, default_value, origin
-       FROM   crdb_internal.cluster_settings`,
-		)
-	}
-	return d.parse(
-		`SELECT variable, value, type AS setting_type, description, default_value, origin
-     FROM   crdb_internal.cluster_settings
-     WHERE  public IS TRUE`,
-	)
+	return hasModify, hasSqlModify, hasView, nil
 }
 
 func (d *delegator) delegateShowTenantClusterSettingList(
@@ -25,7 +25,11 @@ WITH payload AS (
     crdb_internal.pb_to_json(
       'cockroach.sql.jobs.jobspb.Payload',
       payload, false, true
-    )->'changefeed' AS changefeed_details
+    )->'changefeed' AS changefeed_details,
+    crdb_internal.pb_to_json(
+      'cockroach.sql.jobs.jobspb.Progress',
+      progress, false, true
+    )->'changefeed' AS changefeed_progress
   FROM
   crdb_internal.system_jobs
   WHERE job_type = 'CHANGEFEED'%s
@@ -59,7 +63,8 @@ SELECT
       table_id = ANY (SELECT key::INT FROM json_each(changefeed_details->'tables'))
   ) AS full_table_names,
   changefeed_details->'opts'->>'topics' AS topics,
-  COALESCE(changefeed_details->'opts'->>'format','json') AS format
+  COALESCE(changefeed_details->'opts'->>'format','json') AS format,
+  COALESCE((changefeed_progress->>'dlq_row_count')::INT8, 0) AS dlq_rows
 FROM
   crdb_internal.jobs
   INNER JOIN payload ON id = job_id`
@@ -3516,6 +3516,10 @@ func (m *sessionDataMutator) SetRequireExplicitPrimaryKeys(val bool) {
 	m.data.RequireExplicitPrimaryKeys = val
 }
 
+func (m *sessionDataMutator) SetResourceGroup(val string) {
+	m.data.ResourceGroup = val
+}
+
 func (m *sessionDataMutator) SetReorderJoinsLimit(val int) {
 	m.data.ReorderJoinsLimit = int64(val)
 }
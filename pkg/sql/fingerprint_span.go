@@ -13,6 +13,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -109,7 +110,8 @@ func (p *planner) fingerprintSpanFanout(
 
 	maxWorkerCount := int(maxFingerprintNumWorkers.Get(execCfg.SV()))
 	if maxWorkerCount == 1 {
-		return fingerprintSpanImpl(ctx, txn, span, startTime, allRevisions, stripped)
+		return fingerprintSpanImpl(ctx, txn.DB().NonTransactionalSender(), txn.ReadTimestamp(),
+			admissionpb.BulkNormalPri, span, startTime, allRevisions, stripped)
 	}
 
 	planCtx, _, err := dsp.SetupAllNodesPlanning(ctx, extEvalCtx, execCfg)
@@ -154,7 +156,8 @@ func (p *planner) fingerprintSpanFanout(
 								// No more work.
 								return nil
 							}
-							localFingerprint, localSSTs, err := fingerprintSpanImpl(ctx, txn, sp, startTime, allRevisions, stripped)
+							localFingerprint, localSSTs, err := fingerprintSpanImpl(ctx, txn.DB().NonTransactionalSender(),
+								txn.ReadTimestamp(), admissionpb.BulkNormalPri, sp, startTime, allRevisions, stripped)
 							if err != nil {
 								return err
 							}
@@ -230,9 +233,48 @@ func (p *planner) fingerprintSpanFanout(
 	return rv.fingerprint, rv.ssts, nil
 }
 
+// FingerprintSpanAt computes a fingerprint of the latest revisions of the keys
+// in the given span as of the given timestamp. Unlike FingerprintSpan, it does
+// not require a planner and sends its ExportRequests sequentially at the given
+// admission priority, which makes it suitable for background verification
+// work that should yield to foreground traffic.
+//
+// Tenant prefixes are stripped from the fingerprinted keys, so fingerprints of
+// the same data in different tenants are comparable.
+//
+// The caller is responsible for authorization checks.
+func FingerprintSpanAt(
+	ctx context.Context,
+	db *kv.DB,
+	st *cluster.Settings,
+	span roachpb.Span,
+	asOf hlc.Timestamp,
+	priority admissionpb.WorkPriority,
+) (uint64, error) {
+	ctx, sp := tracing.ChildSpan(ctx, "sql.FingerprintSpanAt")
+	defer sp.Finish()
+	fingerprint, ssts, err := fingerprintSpanImpl(ctx, db.NonTransactionalSender(), asOf, priority,
+		span, hlc.Timestamp{}, false /* allRevisions */, false /* stripped */)
+	if err != nil {
+		return 0, err
+	}
+	// See FingerprintSpan for why range keys are fingerprinted on the client.
+	rangekeyFingerprint, err := storage.FingerprintRangekeys(ctx, st,
+		storage.MVCCExportFingerprintOptions{
+			StripTenantPrefix:  true,
+			StripValueChecksum: true,
+		}, ssts)
+	if err != nil {
+		return 0, err
+	}
+	return fingerprint ^ rangekeyFingerprint, nil
+}
+
 func fingerprintSpanImpl(
 	ctx context.Context,
-	txn *kv.Txn,
+	sender kv.Sender,
+	readTS hlc.Timestamp,
+	priority admissionpb.WorkPriority,
 	span roachpb.Span,
 	startTime hlc.Timestamp,
 	allRevisions, stripped bool,
@@ -243,13 +285,13 @@ func fingerprintSpanImpl(
 		filter = kvpb.MVCCFilter_All
 	}
 	header := kvpb.Header{
-		Timestamp: txn.ReadTimestamp(),
+		Timestamp: readTS,
 		// NOTE(ssd): Setting this disables async sending in
 		// DistSender.
 		ReturnElasticCPUResumeSpans: true,
 	}
 	admissionHeader := kvpb.AdmissionHeader{
-		Priority:                 int32(admissionpb.BulkNormalPri),
+		Priority:                 int32(priority),
 		CreateTime:               timeutil.Now().UnixNano(),
 		Source:                   kvpb.AdmissionHeader_FROM_SQL,
 		NoMemoryReservedAtSource: true,
@@ -276,7 +318,7 @@ func fingerprintSpanImpl(
 			5*time.Minute, func(ctx context.Context) error {
 				sp := tracing.SpanFromContext(ctx)
 				ctx, exportSpan := sp.Tracer().StartSpanCtx(ctx, "fingerprint.ExportRequest", tracing.WithParent(sp))
-				rawResp, pErr = kv.SendWrappedWithAdmission(ctx, txn.DB().NonTransactionalSender(), header, admissionHeader, req)
+				rawResp, pErr = kv.SendWrappedWithAdmission(ctx, sender, header, admissionHeader, req)
 				recording = exportSpan.FinishAndGetConfiguredRecording()
 				if pErr != nil {
 					return pErr.GoError()
@@ -338,6 +338,7 @@ func NewFlowBase(
 		h := flowCtx.Txn.AdmissionHeader()
 		admissionInfo.Priority = admissionpb.WorkPriority(h.Priority)
 		admissionInfo.CreateTime = h.CreateTime
+		admissionInfo.ResourceGroup = h.ResourceGroup
 	}
 	return &FlowBase{
 		FlowCtx:               flowCtx,
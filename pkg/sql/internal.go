@@ -474,6 +474,7 @@ func (ie *InternalExecutor) newConnExecutorWithTxn(
 		txn,
 		ex.transitionCtx,
 		ex.QualityOfService(),
+		"", /* resourceGroup */
 		isolation.Serializable,
 		txn.GetOmitInRangefeeds(),
 		// TODO(yuzefovich): re-evaluate whether we want to allow buffered
@@ -956,6 +957,9 @@ func applyOverrides(o sessiondata.InternalExecutorOverride, sd *sessiondata.Sess
 	if o.OriginTimestampForLogicalDataReplication.IsSet() {
 		sd.OriginTimestampForLogicalDataReplication = o.OriginTimestampForLogicalDataReplication
 	}
+	if o.LogicalReplicationSchemaChangeJobID != 0 {
+		sd.LogicalReplicationSchemaChangeJobID = o.LogicalReplicationSchemaChangeJobID
+	}
 	if o.PlanCacheMode != nil {
 		sd.PlanCacheMode = *o.PlanCacheMode
 	}
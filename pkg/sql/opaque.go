@@ -443,6 +443,8 @@ func init() {
 		&tree.AlterBackupSchedule{},
 		&tree.AlterTenantReplication{},
 		&tree.AlterTenantReset{},
+		&tree.AlterDatabaseRevert{},
+		&tree.AlterTableRevert{},
 		&tree.Backup{},
 		&tree.ShowBackup{},
 		&tree.Restore{},
@@ -132,6 +132,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`ALTER ROLE bleh ?? WITH NOCREATEROLE`, `ALTER ROLE`},
 
+		{`ALTER RESOURCE GROUP ??`, `ALTER RESOURCE GROUP`},
+		{`ALTER RESOURCE GROUP foo ??`, `ALTER RESOURCE GROUP`},
+
 		{`ALTER RANGE foo CONFIGURE ??`, `ALTER RANGE`},
 		{`ALTER RANGE ??`, `ALTER RANGE`},
 
@@ -179,6 +182,8 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE EXTENSION ??`, `CREATE EXTENSION`},
 
 		{`CREATE EXTERNAL CONNECTION ??`, `CREATE EXTERNAL CONNECTION`},
+		{`CREATE RESOURCE GROUP ??`, `CREATE RESOURCE GROUP`},
+		{`CREATE RESOURCE GROUP IF NOT EXISTS ??`, `CREATE RESOURCE GROUP`},
 
 		{`CREATE VIRTUAL CLUSTER ??`, `CREATE VIRTUAL CLUSTER`},
 		{`CREATE TENANT ??`, `CREATE VIRTUAL CLUSTER`},
@@ -235,6 +240,7 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP INDEX blah@blih ??`, `DROP INDEX`},
 
 		{`DROP EXTERNAL CONNECTION blah ??`, `DROP EXTERNAL CONNECTION`},
+		{`DROP RESOURCE GROUP ??`, `DROP RESOURCE GROUP`},
 
 		{`DROP USER ??`, `DROP ROLE`},
 		{`DROP USER IF ??`, `DROP ROLE`},
@@ -471,6 +477,7 @@ func TestContextualHelp(t *testing.T) {
 		{`SHOW PARTITIONS FROM ??`, `SHOW PARTITIONS`},
 
 		{`SHOW REGIONS ??`, `SHOW REGIONS`},
+		{`SHOW RESOURCE GROUPS ??`, `SHOW RESOURCE GROUPS`},
 
 		{`SHOW ROLES ??`, `SHOW ROLES`},
 
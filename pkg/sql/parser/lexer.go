@@ -82,6 +82,34 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 		if l.lastPos > 0 && l.tokens[l.lastPos-1].id == RETURNING {
 			lval.id = NOTHING_AFTER_RETURNING
 		}
+	case DATABASE:
+		// After CHANGEFEED FOR, DATABASE introduces a database target
+		// (`CHANGEFEED FOR DATABASE foo`) unless it is itself the name of
+		// the first target table (`CHANGEFEED FOR database FAMILY f`,
+		// `CHANGEFEED FOR database, foo`, `CHANGEFEED FOR database.foo`).
+		// The two are only distinguishable after looking two tokens ahead
+		// when the next token is FAMILY.
+		if l.lastPos < 2 || l.tokens[l.lastPos-1].id != FOR || l.tokens[l.lastPos-2].id != CHANGEFEED {
+			break
+		}
+		nextToken := sqlSymType{}
+		if l.lastPos+1 < len(l.tokens) {
+			nextToken = l.tokens[l.lastPos+1]
+		}
+		secondToken := sqlSymType{}
+		if l.lastPos+2 < len(l.tokens) {
+			secondToken = l.tokens[l.lastPos+2]
+		}
+		switch nextToken.id {
+		case 0, '.', ',', ';', INTO, WITH:
+		case FAMILY:
+			switch secondToken.id {
+			case 0, ';', INTO, WITH:
+				lval.id = DATABASE_LA
+			}
+		default:
+			lval.id = DATABASE_LA
+		}
 	case INDEX:
 		// The following complex logic is a consternation, really.
 		//
@@ -23,6 +23,11 @@ func TestLexer(t *testing.T) {
 		{`NOT SIMILAR`, []int{NOT_LA, SIMILAR}},
 		{`AS OF SYSTEM TIME`, []int{AS_LA, OF, SYSTEM, TIME}},
 		{`AS OF`, []int{AS, OF}},
+		{`CHANGEFEED FOR DATABASE foo`, []int{CHANGEFEED, FOR, DATABASE_LA, IDENT}},
+		{`CHANGEFEED FOR DATABASE FAMILY`, []int{CHANGEFEED, FOR, DATABASE_LA, FAMILY}},
+		{`CHANGEFEED FOR DATABASE FAMILY foo`, []int{CHANGEFEED, FOR, DATABASE, FAMILY, IDENT}},
+		{`CHANGEFEED FOR DATABASE, foo`, []int{CHANGEFEED, FOR, DATABASE, ',', IDENT}},
+		{`GRANT ALL ON DATABASE foo`, []int{GRANT, ALL, ON, DATABASE, IDENT}},
 	}
 	for i, d := range testData {
 		s := makeSQLScanner(d.sql)
@@ -503,6 +503,35 @@ func newDecimal(prec, scale int32) (*types.T, error) {
 	return types.MakeDecimal(prec, scale), nil
 }
 
+// newBackupTableSource returns the data source of a
+// [BACKUP ...].db.t table expression, which reads the rows of the table in
+// the backup through a SHOW BACKUP TABLE statement. Without an alias, the
+// source is aliased to the name of the table.
+func newBackupTableSource(
+	subdir tree.Expr,
+	collection tree.StringOrPlaceholderOptList,
+	asOf tree.AsOfClause,
+	table *tree.UnresolvedObjectName,
+	ordinality bool,
+	as tree.AliasClause,
+) *tree.AliasedTableExpr {
+	if as.Alias == "" {
+		as.Alias = tree.Name(table.Object())
+	}
+	return &tree.AliasedTableExpr{
+		Expr: &tree.StatementSource{Statement: &tree.ShowBackup{
+			From:         true,
+			Details:      tree.BackupTableDetails,
+			Table:        table,
+			Path:         subdir,
+			InCollection: collection,
+			AsOf:         asOf,
+		}},
+		Ordinality: ordinality,
+		As:         as,
+	}
+}
+
 // arrayOf creates a type alias for an array of the given element type and fixed
 // bounds. The bounds are currently ignored.
 func arrayOf(
@@ -981,7 +981,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %token <str> CHARACTER CHARACTERISTICS CHECK CHECK_FILES CLOSE
 %token <str> CLUSTER CLUSTERS COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE COMPLETIONS CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
-%token <str> CONFLICT CONNECTION CONNECTIONS CONSTRAINT CONSTRAINTS CONTAINS CONTROLCHANGEFEED CONTROLJOB
+%token <str> CONFLICT CONNECTION CONNECTIONS CONSTRAINT CONSTRAINTS CONTAINS CONTINUOUS CONTROLCHANGEFEED CONTROLJOB
 %token <str> CONVERSION CONVERT COPY COS_DISTANCE COST COVERING CREATE CREATEDB CREATELOGIN CREATEROLE
 %token <str> CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
@@ -1016,7 +1016,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
 %token <str> INDEX_BEFORE_PAREN INDEX_BEFORE_NAME_THEN_PAREN INDEX_AFTER_ORDER_BY_BEFORE_AT
 %token <str> INNER INOUT INPUT INSENSITIVE INSERT INSTEAD INT INTEGER
-%token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED INVOKER IS ISERROR ISNULL ISOLATION
+%token <str> INTERSECT INTERVAL INTO INTO_DB INTO_EXISTING_TABLE INVERTED INVOKER IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
 
@@ -1052,10 +1052,10 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFERENCING REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH REMOVE_REGIONS RENAME REPEATABLE REPLACE REPLICATED REPLICATION
-%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESTRICTIVE RESUME RETENTION RETURNING RETURN RETURNS REVISION_HISTORY
-%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
+%token <str> RELEASE RESET RESOURCE RESTART RESTORE RESTRICT RESTRICTED RESTRICTIVE RESUME RETENTION RETURNING RETURN RETURNS REVERT REVISION_HISTORY
+%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS ROW_FILTER RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB
+%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_CHANGES SCHEMA_ONLY SCHEMAS SCRUB
 %token <str> SEARCH SECOND SECONDARY SECURITY SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SERVICE SESSION SESSIONS SESSION_USER SET SETOF SETS SETTING SETTINGS
 %token <str> SHARE SHARED SHOW SIMILAR SIMPLE SIZE SKIP SKIP_LOCALITIES_CHECK SKIP_MISSING_FOREIGN_KEYS
@@ -1102,8 +1102,11 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 // references.
 // - TENANT_ALL is used to differentiate `ALTER TENANT <id>` from
 // `ALTER TENANT ALL`. Ditto `CLUSTER_ALL` and `CLUSTER ALL`.
+// - DATABASE_LA is used to differentiate `CHANGEFEED FOR DATABASE <name>`
+// from `CHANGEFEED FOR database FAMILY <family>`, where "database" is a
+// table name.
 %token NOT_LA NULLS_LA WITH_LA AS_LA GENERATED_ALWAYS GENERATED_BY_DEFAULT RESET_ALL ROLE_ALL
-%token USER_ALL ON_LA TENANT_ALL CLUSTER_ALL SET_TRACING
+%token USER_ALL ON_LA TENANT_ALL CLUSTER_ALL SET_TRACING DATABASE_LA
 
 %union {
   id    int32
@@ -1135,6 +1138,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <tree.Statement> alter_func_stmt
 %type <tree.Statement> alter_proc_stmt
 %type <tree.Statement> alter_policy_stmt
+%type <tree.Statement> alter_resource_group_stmt
 
 // ALTER RANGE
 %type <tree.Statement> alter_zone_range_stmt
@@ -1152,6 +1156,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <tree.Statement> alter_table_locality_stmt
 %type <tree.Statement> alter_table_logged_stmt
 %type <tree.Statement> alter_table_owner_stmt
+%type <tree.Statement> alter_table_revert_stmt
 
 // ALTER VIRTUAL CLUSTER
 %type <tree.Statement> alter_virtual_cluster_stmt
@@ -1191,6 +1196,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <tree.Statement> alter_database_set_secondary_region_stmt
 %type <tree.Statement> alter_database_drop_secondary_region
 %type <tree.Statement> alter_database_set_zone_config_extension_stmt
+%type <tree.Statement> alter_database_revert_stmt
 
 // ALTER INDEX
 %type <tree.Statement> alter_oneindex_stmt
@@ -1273,6 +1279,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <tree.Statement> create_proc_stmt
 %type <tree.Statement> create_trigger_stmt
 %type <tree.Statement> create_policy_stmt
+%type <tree.Statement> create_resource_group_stmt
 
 %type <tree.Statement> check_stmt
 %type <tree.Statement> check_external_connection_stmt
@@ -1303,6 +1310,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <tree.Statement> drop_sequence_stmt
 %type <tree.Statement> drop_func_stmt
 %type <tree.Statement> drop_policy_stmt
+%type <tree.Statement> drop_resource_group_stmt
 %type <tree.Statement> drop_proc_stmt
 %type <tree.Statement> drop_trigger_stmt
 %type <tree.Statement> drop_virtual_cluster_stmt
@@ -1406,6 +1414,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <tree.Statement> show_completions_stmt
 %type <tree.Statement> show_logical_replication_jobs_stmt opt_show_logical_replication_jobs_options show_logical_replication_jobs_options
 %type <tree.Statement> show_policies_stmt
+%type <tree.Statement> show_resource_groups_stmt
 
 %type <str> statements_or_queries
 
@@ -1739,6 +1748,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 
 %type <tree.GrantTargetList> grant_targets targets_roles target_types
 %type <tree.TableExpr> changefeed_target_expr
+%type <str> changefeed_lookup_join_type
 %type <*tree.GrantTargetList> opt_on_targets_roles
 %type <tree.RoleSpecList> for_grantee_clause
 %type <privilege.List> privileges
@@ -1929,6 +1939,7 @@ stmt_without_legacy_transaction:
 alter_stmt:
   alter_ddl_stmt      // help texts in sub-rule
 | alter_role_stmt     // EXTEND WITH HELP: ALTER ROLE
+| alter_resource_group_stmt // EXTEND WITH HELP: ALTER RESOURCE GROUP
 | alter_virtual_cluster_stmt   /* SKIP DOC */
 | alter_unsupported_stmt
 | ALTER error         // SHOW HELP: ALTER
@@ -1989,6 +2000,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
 //   ALTER TABLE ... {ENABLE | DISABLE | FORCE | NO FORCE} ROW LEVEL SECURITY
+//   ALTER TABLE ... REVERT TO SYSTEM TIME <time>
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -2015,6 +2027,7 @@ alter_table_stmt:
 | alter_table_locality_stmt
 | alter_table_logged_stmt
 | alter_table_owner_stmt
+| alter_table_revert_stmt
 // ALTER TABLE has its error help token here because the ALTER TABLE
 // prefix is spread over multiple non-terminals.
 | ALTER TABLE error     // SHOW HELP: ALTER TABLE
@@ -2105,6 +2118,7 @@ alter_sequence_options_stmt:
 // ALTER DATABASE <name> SET var { TO | = } { value | DEFAULT }
 // ALTER DATABASE <name> RESET { var | ALL }
 // ALTER DATABASE <name> ALTER LOCALITY { GLOBAL | REGIONAL [IN <region>] } CONFIGURE ZONE <zone config>
+// ALTER DATABASE <name> REVERT TO SYSTEM TIME <time>
 // %SeeAlso: WEBDOCS/alter-database.html
 alter_database_stmt:
   alter_rename_database_stmt
@@ -2123,6 +2137,7 @@ alter_database_stmt:
 | alter_database_set_secondary_region_stmt
 | alter_database_drop_secondary_region
 | alter_database_set_zone_config_extension_stmt
+| alter_database_revert_stmt
 
 // %Help: ALTER FUNCTION - change the definition of a function
 // %Category: DDL
@@ -2190,6 +2205,15 @@ alter_database_set_stmt:
     }
   }
 
+alter_database_revert_stmt:
+  ALTER DATABASE database_name REVERT TO SYSTEM TIME a_expr
+  {
+    $$.val = &tree.AlterDatabaseRevert{
+      Name: tree.Name($3),
+      Timestamp: $8.expr(),
+    }
+  }
+
 alter_database_placement_stmt:
   ALTER DATABASE database_name placement_clause
   {
@@ -3557,6 +3581,18 @@ backup_options:
   {
     $$.val = &tree.BackupOptions{UpdatesClusterMonitoringMetrics: $3.expr()}
   }
+| CONTINUOUS
+  {
+    $$.val = &tree.BackupOptions{Continuous: tree.MakeDBool(true)}
+  }
+| CONTINUOUS '=' TRUE
+  {
+    $$.val = &tree.BackupOptions{Continuous: tree.MakeDBool(true)}
+  }
+| CONTINUOUS '=' FALSE
+  {
+    $$.val = &tree.BackupOptions{Continuous: tree.MakeDBool(false)}
+  }
 
 include_all_clusters:
   INCLUDE_ALL_SECONDARY_TENANTS { /* SKIP DOC */ }
@@ -3908,6 +3944,58 @@ drop_external_connection_stmt:
 	}
 	| DROP EXTERNAL CONNECTION error // SHOW HELP: DROP EXTERNAL CONNECTION
 
+// %Help: CREATE RESOURCE GROUP - define a workload resource group
+// %Category: Cfg
+// %Text:
+// CREATE RESOURCE GROUP [IF NOT EXISTS] <name> [WITH <option> = <value> [, ...]]
+//
+// Options:
+//   cpu_weight, io_weight: relative share of admission control CPU and IO
+//   tokens, between 1 and 1000 (default 1).
+//   cpu_burst, io_burst: work an idle group may be admitted ahead of its
+//   share (default 0).
+//   applications: '|' separated application names assigned to the group.
+// %SeeAlso: ALTER RESOURCE GROUP, DROP RESOURCE GROUP, SHOW RESOURCE GROUPS
+create_resource_group_stmt:
+  CREATE RESOURCE GROUP name opt_with_options
+  {
+    $$.val = &tree.CreateResourceGroup{Name: tree.Name($4), Options: $5.kvOptions()}
+  }
+| CREATE RESOURCE GROUP IF NOT EXISTS name opt_with_options
+  {
+    $$.val = &tree.CreateResourceGroup{IfNotExists: true, Name: tree.Name($7), Options: $8.kvOptions()}
+  }
+| CREATE RESOURCE GROUP error // SHOW HELP: CREATE RESOURCE GROUP
+
+// %Help: ALTER RESOURCE GROUP - change the definition of a workload resource group
+// %Category: Cfg
+// %Text:
+// ALTER RESOURCE GROUP <name> WITH <option> = <value> [, ...]
+//
+// See CREATE RESOURCE GROUP for the options.
+// %SeeAlso: CREATE RESOURCE GROUP, DROP RESOURCE GROUP, SHOW RESOURCE GROUPS
+alter_resource_group_stmt:
+  ALTER RESOURCE GROUP name WITH kv_option_list
+  {
+    $$.val = &tree.AlterResourceGroup{Name: tree.Name($4), Options: $6.kvOptions()}
+  }
+| ALTER RESOURCE GROUP error // SHOW HELP: ALTER RESOURCE GROUP
+
+// %Help: DROP RESOURCE GROUP - remove a workload resource group
+// %Category: Cfg
+// %Text: DROP RESOURCE GROUP [IF EXISTS] <name>
+// %SeeAlso: CREATE RESOURCE GROUP, ALTER RESOURCE GROUP, SHOW RESOURCE GROUPS
+drop_resource_group_stmt:
+  DROP RESOURCE GROUP name
+  {
+    $$.val = &tree.DropResourceGroup{Name: tree.Name($4)}
+  }
+| DROP RESOURCE GROUP IF EXISTS name
+  {
+    $$.val = &tree.DropResourceGroup{IfExists: true, Name: tree.Name($6)}
+  }
+| DROP RESOURCE GROUP error // SHOW HELP: DROP RESOURCE GROUP
+
 // %Help: RESTORE - restore data from external storage
 // %Category: CCL
 // %Text:
@@ -3938,6 +4026,8 @@ drop_external_connection_stmt:
 //    detached: execute restore job asynchronously, without waiting for its completion
 //    skip_localities_check: ignore difference of zone configuration between restore cluster and backup cluster
 //    new_db_name: renames the restored database. only applies to database restores
+//    row_filter: only restore the rows of a single table that satisfy the given predicate
+//    into_existing_table: replace the rows matching row_filter in the existing table
 //    include_all_virtual_clusters: enable backups of all virtual clusters during a cluster backup
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
@@ -4112,6 +4202,14 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{RemoveRegions: true, SkipLocalitiesCheck: true}
   }
+| ROW_FILTER '=' string_or_placeholder
+  {
+    $$.val = &tree.RestoreOptions{RowFilter: $3.expr()}
+  }
+| INTO_EXISTING_TABLE
+  {
+    $$.val = &tree.RestoreOptions{IntoExistingTable: true}
+  }
 
 virtual_cluster_opt:
   TENANT  { /* SKIP DOC */ }
@@ -4678,6 +4776,7 @@ create_stmt:
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_external_connection_stmt // EXTEND WITH HELP: CREATE EXTERNAL CONNECTION
 | create_virtual_cluster_stmt     // EXTEND WITH HELP: CREATE VIRTUAL CLUSTER
+| create_resource_group_stmt      // EXTEND WITH HELP: CREATE RESOURCE GROUP
 | create_logical_replication_stream_stmt     // EXTEND WITH HELP: CREATE LOGICAL REPLICATION STREAM
 | create_schedule_stmt   // help texts in sub-rule
 | create_unsupported     {}
@@ -4703,7 +4802,11 @@ check_stmt:
 //  < CURSOR = start_time > |
 //  < DEFAULT FUNCTION = lww | dlq | udf
 //  < FUNCTION 'udf' FOR TABLE local_name  , ... > |
-//  < DISCARD = 'ttl-deletes' >
+//  < MERGE COLUMN column_name USING 'strategy' FOR TABLE local_name , ... > |
+//  < COLUMN column_name = < expr | DEFAULT > FOR TABLE local_name , ... > |
+//  < EXCLUDE COLUMN column_name FOR TABLE local_name , ... > |
+//  < DISCARD = 'ttl-deletes' > |
+//  < SCHEMA_CHANGES = 'replicate' >
 // ]
 create_logical_replication_stream_stmt:
   CREATE LOGICAL REPLICATION STREAM FROM logical_replication_resources ON string_or_placeholder INTO logical_replication_resources opt_logical_replication_options
@@ -4838,10 +4941,37 @@ logical_replication_options:
   {
      $$.val = &tree.LogicalReplicationOptions{UserFunctions: map[tree.UnresolvedName]tree.RoutineName{*$5.unresolvedObjectName().ToUnresolvedName():$2.unresolvedObjectName().ToRoutineName()}}
   }
+| MERGE COLUMN column_name USING string_or_placeholder FOR TABLE db_object_name
+  {
+    $$.val = &tree.LogicalReplicationOptions{MergeRules: []tree.LogicalReplicationMergeRule{{
+      Column: tree.Name($3),
+      Strategy: $5.expr(),
+      Table: *$8.unresolvedObjectName().ToUnresolvedName(),
+    }}}
+  }
+| COLUMN column_name '=' a_expr FOR TABLE db_object_name
+  {
+    $$.val = &tree.LogicalReplicationOptions{ColumnMappings: []tree.LogicalReplicationColumnMapping{{
+      Column: tree.Name($2),
+      Expr: $4.expr(),
+      Table: *$7.unresolvedObjectName().ToUnresolvedName(),
+    }}}
+  }
+| EXCLUDE COLUMN column_name FOR TABLE db_object_name
+  {
+    $$.val = &tree.LogicalReplicationOptions{ColumnMappings: []tree.LogicalReplicationColumnMapping{{
+      Column: tree.Name($3),
+      Table: *$6.unresolvedObjectName().ToUnresolvedName(),
+    }}}
+  }
  | DISCARD '=' string_or_placeholder
   {
     $$.val = &tree.LogicalReplicationOptions{Discard: $3.expr()}
   }
+| SCHEMA_CHANGES '=' string_or_placeholder
+  {
+    $$.val = &tree.LogicalReplicationOptions{SchemaChanges: $3.expr()}
+  }
 | SKIP SCHEMA CHECK
   {
     $$.val = &tree.LogicalReplicationOptions{SkipSchemaCheck: tree.MakeDBool(true)}
@@ -4866,6 +4996,10 @@ logical_replication_create_table_options:
   {
     $$.val = &tree.LogicalReplicationOptions{Discard: $3.expr()}
   }
+| SCHEMA_CHANGES '=' string_or_placeholder
+  {
+    $$.val = &tree.LogicalReplicationOptions{SchemaChanges: $3.expr()}
+  }
 | LABEL '=' string_or_placeholder
   {
     $$.val = &tree.LogicalReplicationOptions{MetricsLabel: $3.expr()}
@@ -6192,6 +6326,11 @@ create_stats_option:
 // CREATE CHANGEFEED
 // FOR <targets> [INTO sink] [WITH <options>]
 //
+// CREATE CHANGEFEED
+// FOR DATABASE <database_name> [INTO sink] [WITH <options>]
+//
+// targets: comma separated list of tables, optionally qualified with a
+//          column family, or table patterns such as db.* and db.schema.*
 // sink: data capture stream destination (Enterprise only)
 create_changefeed_stmt:
   CREATE CHANGEFEED FOR changefeed_targets opt_changefeed_sink opt_with_options
@@ -6202,6 +6341,14 @@ create_changefeed_stmt:
       Options: $6.kvOptions(),
     }
   }
+| CREATE CHANGEFEED FOR DATABASE_LA database_name opt_changefeed_sink opt_with_options
+  {
+    $$.val = &tree.CreateChangefeed{
+      Database: tree.Name($5),
+      SinkURI:  $6.expr(),
+      Options:  $7.kvOptions(),
+    }
+  }
 | CREATE CHANGEFEED /*$3=*/ opt_changefeed_sink /*$4=*/ opt_with_options
   AS SELECT /*$7=*/target_list FROM /*$9=*/changefeed_target_expr /*$10=*/opt_where_clause
   {
@@ -6229,6 +6376,14 @@ create_changefeed_stmt:
       Options: $5.kvOptions(),
     }
   }
+| EXPERIMENTAL CHANGEFEED FOR DATABASE_LA database_name opt_with_options
+  {
+    /* SKIP DOC */
+    $$.val = &tree.CreateChangefeed{
+      Database: tree.Name($5),
+      Options:  $6.kvOptions(),
+    }
+  }
 
 // %Help: CREATE SCHEDULE FOR CHANGEFEED - create changefeed periodically
 // %Category: CCL
@@ -6311,15 +6466,41 @@ changefeed_targets:
   }
 
 changefeed_target:
-  opt_table_prefix table_name opt_changefeed_family
+  opt_table_prefix table_pattern opt_changefeed_family
   {
     $$.val = tree.ChangefeedTarget{
-      TableName:  $2.unresolvedObjectName().ToUnresolvedName(),
+      TableName:  $2.unresolvedName(),
       FamilyName: tree.Name($3),
     }
   }
 
-changefeed_target_expr: insert_target
+changefeed_target_expr:
+  insert_target
+| changefeed_target_expr changefeed_lookup_join_type JOIN insert_target ON a_expr
+  {
+    $$.val = &tree.JoinTableExpr{
+      JoinType: $2,
+      Left:     $1.tblExpr(),
+      Right:    $4.tblExpr(),
+      Cond:     &tree.OnJoinCond{Expr: $6.expr()},
+    }
+  }
+
+// Changefeed queries only support lookup joins into the tables being joined,
+// so only INNER and LEFT joins are allowed.
+changefeed_lookup_join_type:
+  LEFT join_outer
+  {
+    $$ = tree.AstLeft
+  }
+| INNER
+  {
+    $$ = tree.AstInner
+  }
+| /* EMPTY */
+  {
+    $$ = ""
+  }
 
 opt_table_prefix:
   TABLE
@@ -6456,6 +6637,7 @@ drop_stmt:
 | drop_schedule_stmt            // EXTEND WITH HELP: DROP SCHEDULES
 | drop_external_connection_stmt // EXTEND WITH HELP: DROP EXTERNAL CONNECTION
 | drop_virtual_cluster_stmt     // EXTEND WITH HELP: DROP VIRTUAL CLUSTER
+| drop_resource_group_stmt      // EXTEND WITH HELP: DROP RESOURCE GROUP
 | drop_unsupported   {}
 | DROP error                    // SHOW HELP: DROP
 
@@ -8345,6 +8527,7 @@ show_stmt:
 | show_ranges_stmt           // EXTEND WITH HELP: SHOW RANGES
 | show_range_for_row_stmt
 | show_regions_stmt          // EXTEND WITH HELP: SHOW REGIONS
+| show_resource_groups_stmt  // EXTEND WITH HELP: SHOW RESOURCE GROUPS
 | show_survival_goal_stmt    // EXTEND_WITH_HELP: SHOW SURVIVAL GOAL
 | show_roles_stmt            // EXTEND WITH HELP: SHOW ROLES
 | show_savepoint_stmt        // EXTEND WITH HELP: SHOW SAVEPOINT
@@ -8717,7 +8900,9 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text:
+// SHOW BACKUP [SCHEMAS|FILES|RANGES] FROM <subdirectory> IN <collectionURI>
+// SHOW BACKUP TABLE <tablename> FROM <subdirectory> IN <collectionURI> [AS OF SYSTEM TIME <expr>] [WHERE <expr>]
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
   SHOW BACKUPS IN string_or_placeholder_opt_list
@@ -8736,6 +8921,19 @@ show_backup_stmt:
 			Options: *$8.showBackupOptions(),
 		}
 	}
+| SHOW BACKUP TABLE table_name FROM string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_where_clause opt_with_show_backup_options
+	{
+		$$.val = &tree.ShowBackup{
+			From:    true,
+			Details:    tree.BackupTableDetails,
+			Table:    $4.unresolvedObjectName(),
+			Path:    $6.expr(),
+			InCollection: $8.stringOrPlaceholderOptList(),
+			AsOf:    $9.asOfClause(),
+			Where:    tree.NewWhere(tree.AstWhere, $10.expr()),
+			Options: *$11.showBackupOptions(),
+		}
+	}
 | SHOW BACKUP string_or_placeholder IN string_or_placeholder_opt_list opt_with_show_backup_options
 	{
 		$$.val = &tree.ShowBackup{
@@ -9049,6 +9247,17 @@ show_external_connections_stmt:
  }
 | SHOW EXTERNAL CONNECTION error // SHOW HELP: SHOW EXTERNAL CONNECTIONS
 
+// %Help: SHOW RESOURCE GROUPS - list workload resource groups
+// %Category: Cfg
+// %Text: SHOW RESOURCE GROUPS
+// %SeeAlso: CREATE RESOURCE GROUP, ALTER RESOURCE GROUP, DROP RESOURCE GROUP
+show_resource_groups_stmt:
+  SHOW RESOURCE GROUPS
+  {
+    $$.val = &tree.ShowResourceGroups{}
+  }
+| SHOW RESOURCE GROUPS error // SHOW HELP: SHOW RESOURCE GROUPS
+
 // %Help: SHOW TYPES - list user defined types
 // %Category: Misc
 // %Text: SHOW TYPES [WITH_COMMENT]
@@ -12670,6 +12879,15 @@ alter_table_owner_stmt:
     }
   }
 
+alter_table_revert_stmt:
+  ALTER TABLE relation_expr REVERT TO SYSTEM TIME a_expr
+  {
+    $$.val = &tree.AlterTableRevert{
+      Name: $3.unresolvedObjectName(),
+      Timestamp: $8.expr(),
+    }
+  }
+
 alter_view_set_schema_stmt:
 	ALTER VIEW relation_expr SET SCHEMA schema_name
 	 {
@@ -14728,6 +14946,21 @@ table_ref:
   {
     $$.val = &tree.AliasedTableExpr{Expr: &tree.StatementSource{ Statement: $2.stmt() }, Ordinality: $4.bool(), As: $5.aliasClause() }
   }
+// The following syntax is a CockroachDB extension:
+//     SELECT ... FROM [BACKUP <collectionURI> [AS OF SYSTEM TIME ...]].db.t
+//     SELECT ... FROM [BACKUP <subdirectory> IN <collectionURI> [AS OF SYSTEM TIME ...]].db.t
+// It reads the rows of a table as stored in a backup, without restoring it,
+// from the latest backup of the collection if no subdirectory is given. It is
+// shorthand for [SHOW BACKUP TABLE db.t FROM <subdirectory> IN <collectionURI>],
+// aliased to the name of the table.
+| '[' BACKUP string_or_placeholder_opt_list opt_as_of_clause ']' '.' table_name opt_ordinality opt_alias_clause
+  {
+    $$.val = newBackupTableSource(tree.NewStrVal("latest"), $3.stringOrPlaceholderOptList(), $4.asOfClause(), $7.unresolvedObjectName(), $8.bool(), $9.aliasClause())
+  }
+| '[' BACKUP string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause ']' '.' table_name opt_ordinality opt_alias_clause
+  {
+    $$.val = newBackupTableSource($3.expr(), $5.stringOrPlaceholderOptList(), $6.asOfClause(), $9.unresolvedObjectName(), $10.bool(), $11.aliasClause())
+  }
 
 numeric_table_ref:
   '[' iconst64 opt_tableref_col_list alias_clause ']'
@@ -18151,6 +18384,7 @@ unreserved_keyword:
 | CONNECTION
 | CONNECTIONS
 | CONSTRAINTS
+| CONTINUOUS
 | CONTROLCHANGEFEED
 | CONTROLJOB
 | CONVERSION
@@ -18267,6 +18501,7 @@ unreserved_keyword:
 | INSERT
 | INSTEAD
 | INTO_DB
+| INTO_EXISTING_TABLE
 | INVERTED
 | INVISIBLE
 | ISOLATION
@@ -18433,6 +18668,7 @@ unreserved_keyword:
 | REPLICATED
 | REPLICATION
 | RESET
+| RESOURCE
 | RESTART
 | RESTORE
 | RESTRICT
@@ -18442,6 +18678,7 @@ unreserved_keyword:
 | RETENTION
 | RETURN
 | RETURNS
+| REVERT
 | REVISION_HISTORY
 | REVOKE
 | ROLE
@@ -18450,10 +18687,12 @@ unreserved_keyword:
 | ROLLUP
 | ROUTINES
 | ROWS
+| ROW_FILTER
 | RULE
 | RUNNING
 | SCHEDULE
 | SCHEDULES
+| SCHEMA_CHANGES
 | SCHEMA_ONLY
 | SCROLL
 | SETTING
@@ -18666,6 +18905,7 @@ bare_label_keywords:
 | CONNECTIONS
 | CONSTRAINT
 | CONSTRAINTS
+| CONTINUOUS
 | CONTROLCHANGEFEED
 | CONTROLJOB
 | CONVERSION
@@ -18824,6 +19064,7 @@ bare_label_keywords:
 | INTEGER
 | INTERVAL
 | INTO_DB
+| INTO_EXISTING_TABLE
 | INVERTED
 | INVISIBLE
 | INVOKER
@@ -19014,6 +19255,7 @@ bare_label_keywords:
 | REPLICATED
 | REPLICATION
 | RESET
+| RESOURCE
 | RESTART
 | RESTORE
 | RESTRICT
@@ -19023,6 +19265,7 @@ bare_label_keywords:
 | RETENTION
 | RETURN
 | RETURNS
+| REVERT
 | REVISION_HISTORY
 | REVOKE
 | RIGHT
@@ -19033,6 +19276,7 @@ bare_label_keywords:
 | ROUTINES
 | ROW
 | ROWS
+| ROW_FILTER
 | RULE
 | RUNNING
 | SAVEPOINT
@@ -19042,6 +19286,7 @@ bare_label_keywords:
 | SCHEDULES
 | SCHEMA
 | SCHEMAS
+| SCHEMA_CHANGES
 | SCHEMA_ONLY
 | SCROLL
 | SCRUB
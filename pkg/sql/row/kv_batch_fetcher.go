@@ -428,9 +428,10 @@ func (f *txnKVFetcher) maybeInitAdmissionPacer(
 				// nodes running colocated SQL+KV code where all SQL code is run
 				// on behalf of the one tenant. So from an AC perspective, the
 				// tenant ID we pass through here is irrelevant.
-				TenantID:   roachpb.SystemTenantID,
-				Priority:   admissionPri,
-				CreateTime: admissionHeader.CreateTime,
+				TenantID:      roachpb.SystemTenantID,
+				Priority:      admissionPri,
+				CreateTime:    admissionHeader.CreateTime,
+				ResourceGroup: admissionHeader.ResourceGroup,
 			})
 	}
 }
@@ -762,9 +763,10 @@ func (f *txnKVFetcher) maybeAdmitBatchResponse(ctx context.Context, br *kvpb.Bat
 		}
 	} else if f.responseAdmissionQ != nil {
 		responseAdmission := admission.WorkInfo{
-			TenantID:   roachpb.SystemTenantID,
-			Priority:   admissionpb.WorkPriority(f.requestAdmissionHeader.Priority),
-			CreateTime: f.requestAdmissionHeader.CreateTime,
+			TenantID:      roachpb.SystemTenantID,
+			Priority:      admissionpb.WorkPriority(f.requestAdmissionHeader.Priority),
+			CreateTime:    f.requestAdmissionHeader.CreateTime,
+			ResourceGroup: f.requestAdmissionHeader.ResourceGroup,
 		}
 		if _, err := f.responseAdmissionQ.Admit(ctx, responseAdmission); err != nil {
 			return err
@@ -7,6 +7,7 @@ package scbuildstmt
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
@@ -1206,7 +1207,15 @@ func checkTableSchemaChangePrerequisites(
 		})
 
 		kvWriterEnabled := sqlclustersettings.LDRWriterType(sqlclustersettings.LDRImmediateModeWriter.Get(&b.ClusterSettings().SV))
-		if !tree.IsAllowedLDRSchemaChange(n, virtualColNames, kvWriterEnabled == sqlclustersettings.LDRWriterTypeLegacyKV) {
+		// Jobs that replicate schema changes replay them on the destination, so
+		// those schema changes are allowed when a job replays them, or on the
+		// source of a job if every job replicates them.
+		replicated := tree.IsReplicatedLDRSchemaChange(n) &&
+			(slices.Contains(ldrJobIDs.SchemaChangeJobIDs, catpb.JobID(b.SessionData().LogicalReplicationSchemaChangeJobID)) ||
+				(len(ldrJobIDs.SchemaChangeSourceJobIDs) > 0 && !slices.ContainsFunc(ldrJobIDs.JobIDs, func(id catpb.JobID) bool {
+					return !slices.Contains(ldrJobIDs.SchemaChangeJobIDs, id)
+				})))
+		if !replicated && !tree.IsAllowedLDRSchemaChange(n, virtualColNames, kvWriterEnabled == sqlclustersettings.LDRWriterTypeLegacyKV) {
 			_, _, ns := scpb.FindNamespace(tableElements)
 			if ns == nil {
 				panic(errors.AssertionFailedf("programming error: Namespace element not found"))
@@ -482,8 +482,10 @@ func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
 	}
 	if tbl.TableDesc().LDRJobIDs != nil {
 		w.ev(scpb.Status_PUBLIC, &scpb.LDRJobIDs{
-			TableID: tbl.GetID(),
-			JobIDs:  tbl.TableDesc().LDRJobIDs,
+			TableID:                  tbl.GetID(),
+			JobIDs:                   tbl.TableDesc().LDRJobIDs,
+			SchemaChangeJobIDs:       tbl.TableDesc().LDRSchemaChangeJobIDs,
+			SchemaChangeSourceJobIDs: tbl.TableDesc().LDRSchemaChangeSourceJobIDs,
 		})
 	}
 }
@@ -299,6 +299,46 @@ var replicationBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	"crdb_internal.fingerprint_replication_stream_spans": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategoryClusterReplication,
+			Undocumented:     true,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "req", Typ: types.Bytes},
+			},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				mgr, err := evalCtx.StreamManagerFactory.GetReplicationStreamManager(ctx)
+				if err != nil {
+					return nil, err
+				}
+				reqBytes := []byte(tree.MustBeDBytes(args[0]))
+				req := streampb.ReplicationFingerprintRequest{}
+				if err := protoutil.Unmarshal(reqBytes, &req); err != nil {
+					return nil, err
+				}
+				if err := mgr.AuthorizeViaJob(ctx, req.StreamID); err != nil {
+					return nil, err
+				}
+				resp, err := mgr.FingerprintReplicationSpans(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				rawResp, err := protoutil.Marshal(&resp)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDBytes(tree.DBytes(rawResp)), err
+			},
+			Info: "This function can be used on the consumer side to fingerprint spans of a physical " +
+				"replication stream on the producer side as of a given time.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	"crdb_internal.complete_replication_stream": makeBuiltin(
 		tree.FunctionProperties{
 			Category:         builtinconstants.CategoryClusterReplication,
@@ -919,6 +919,14 @@ type ReplicationStreamManager interface {
 		successfulIngestion bool,
 	) error
 
+	// FingerprintReplicationSpans fingerprints spans of a physical replication
+	// stream on the producer side so the consumer can verify the data it has
+	// ingested.
+	FingerprintReplicationSpans(
+		ctx context.Context,
+		req streampb.ReplicationFingerprintRequest,
+	) (streampb.ReplicationFingerprintResponse, error)
+
 	DebugGetProducerStatuses(ctx context.Context) ([]streampb.DebugProducerStatus, error)
 	DebugGetLogicalConsumerStatuses(ctx context.Context) ([]*streampb.DebugLogicalConsumerStatus, error)
 
@@ -246,3 +246,20 @@ func (node *AlterDatabaseSetZoneConfigExtension) Format(ctx *FmtCtx) {
 	ctx.WriteString(" CONFIGURE ZONE ")
 	node.ZoneConfigSettings.Format(ctx)
 }
+
+// AlterDatabaseRevert represents an ALTER DATABASE ... REVERT TO SYSTEM TIME
+// statement.
+type AlterDatabaseRevert struct {
+	Name      Name
+	Timestamp Expr
+}
+
+var _ Statement = &AlterDatabaseRevert{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDatabaseRevert) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER DATABASE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" REVERT TO SYSTEM TIME ")
+	ctx.FormatNode(node.Timestamp)
+}
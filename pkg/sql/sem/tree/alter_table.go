@@ -772,6 +772,23 @@ func (node *AlterTableOwner) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Owner)
 }
 
+// AlterTableRevert represents an ALTER TABLE ... REVERT TO SYSTEM TIME
+// statement.
+type AlterTableRevert struct {
+	Name      *UnresolvedObjectName
+	Timestamp Expr
+}
+
+var _ Statement = &AlterTableRevert{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableRevert) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER TABLE ")
+	ctx.FormatNode(node.Name)
+	ctx.WriteString(" REVERT TO SYSTEM TIME ")
+	ctx.FormatNode(node.Timestamp)
+}
+
 // AlterTableAddIdentity represents commands to alter a column to an identity.
 type AlterTableAddIdentity struct {
 	Column        Name
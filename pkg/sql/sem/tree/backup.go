@@ -43,6 +43,7 @@ type BackupOptions struct {
 	IncrementalStorage              StringOrPlaceholderOptList
 	ExecutionLocality               Expr
 	UpdatesClusterMonitoringMetrics Expr
+	Continuous                      *DBool
 }
 
 var _ NodeFormatter = &BackupOptions{}
@@ -129,6 +130,8 @@ type RestoreOptions struct {
 	ExperimentalOnline               bool
 	ExperimentalCopy                 bool
 	RemoveRegions                    bool
+	RowFilter                        Expr
+	IntoExistingTable                bool
 }
 
 func (opts *RestoreOptions) OnlineImpl() bool {
@@ -302,6 +305,14 @@ func (o *BackupOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("updates_cluster_monitoring_metrics = ")
 		ctx.FormatNode(o.UpdatesClusterMonitoringMetrics)
 	}
+
+	if o.Continuous != nil {
+		maybeAddSep()
+		ctx.WriteString("continuous")
+		if o.Continuous != DBoolTrue {
+			ctx.WriteString(" = FALSE")
+		}
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -362,6 +373,14 @@ func (o *BackupOptions) CombineWith(other *BackupOptions) error {
 	} else {
 		o.UpdatesClusterMonitoringMetrics = other.UpdatesClusterMonitoringMetrics
 	}
+
+	if o.Continuous != nil {
+		if other.Continuous != nil {
+			return errors.New("continuous option specified multiple times")
+		}
+	} else {
+		o.Continuous = other.Continuous
+	}
 	return nil
 }
 
@@ -375,7 +394,8 @@ func (o BackupOptions) IsDefault() bool {
 		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.IncludeAllSecondaryTenants == options.IncludeAllSecondaryTenants &&
-		o.UpdatesClusterMonitoringMetrics == options.UpdatesClusterMonitoringMetrics
+		o.UpdatesClusterMonitoringMetrics == options.UpdatesClusterMonitoringMetrics &&
+		(o.Continuous == nil || o.Continuous == DBoolFalse)
 }
 
 // Format implements the NodeFormatter interface.
@@ -502,6 +522,17 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		maybeAddSep()
 		ctx.WriteString("remove_regions")
 	}
+
+	if o.RowFilter != nil {
+		maybeAddSep()
+		ctx.WriteString("row_filter = ")
+		ctx.FormatNode(o.RowFilter)
+	}
+
+	if o.IntoExistingTable {
+		maybeAddSep()
+		ctx.WriteString("into_existing_table")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -659,6 +690,20 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		o.RemoveRegions = other.RemoveRegions
 	}
 
+	if o.RowFilter == nil {
+		o.RowFilter = other.RowFilter
+	} else if other.RowFilter != nil {
+		return errors.New("row_filter specified multiple times")
+	}
+
+	if o.IntoExistingTable {
+		if other.IntoExistingTable {
+			return errors.New("into_existing_table specified multiple times")
+		}
+	} else {
+		o.IntoExistingTable = other.IntoExistingTable
+	}
+
 	return nil
 }
 
@@ -685,7 +730,9 @@ func (o RestoreOptions) IsDefault() bool {
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.ExperimentalOnline == options.ExperimentalOnline &&
 		o.ExperimentalCopy == options.ExperimentalCopy &&
-		o.RemoveRegions == options.RemoveRegions
+		o.RemoveRegions == options.RemoveRegions &&
+		o.RowFilter == options.RowFilter &&
+		o.IntoExistingTable == options.IntoExistingTable
 }
 
 // BackupTargetList represents a list of targets.
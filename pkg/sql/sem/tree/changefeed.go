@@ -13,9 +13,12 @@ import (
 // CreateChangefeed represents a CREATE CHANGEFEED statement.
 type CreateChangefeed struct {
 	Targets ChangefeedTargets
-	SinkURI Expr
-	Options KVOptions
-	Select  *SelectClause
+	// Database, if set, is the database whose tables are watched by the
+	// changefeed. It is mutually exclusive with Targets.
+	Database Name
+	SinkURI  Expr
+	Options  KVOptions
+	Select   *SelectClause
 }
 
 var _ Statement = &CreateChangefeed{}
@@ -36,7 +39,12 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 	}
 
 	ctx.WriteString("CHANGEFEED FOR ")
-	ctx.FormatNode(&node.Targets)
+	if node.Database != "" {
+		ctx.WriteString("DATABASE ")
+		ctx.FormatNode(&node.Database)
+	} else {
+		ctx.FormatNode(&node.Targets)
+	}
 	if node.SinkURI != nil {
 		ctx.WriteString(" INTO ")
 		ctx.FormatURI(node.SinkURI)
@@ -95,7 +103,9 @@ func (cts *ChangefeedTargets) Format(ctx *FmtCtx) {
 }
 
 // ChangefeedTargetFromTableExpr returns ChangefeedTarget for the
-// specified table expression.
+// specified table expression. For a table expression with lookup joins, the
+// target is the leftmost table; the joined tables are read when events are
+// emitted and are not watched by the changefeed.
 func ChangefeedTargetFromTableExpr(e TableExpr) (ChangefeedTarget, error) {
 	switch t := e.(type) {
 	case TablePattern:
@@ -104,6 +114,8 @@ func ChangefeedTargetFromTableExpr(e TableExpr) (ChangefeedTarget, error) {
 		if tn, ok := t.Expr.(*TableName); ok {
 			return ChangefeedTarget{TableName: tn}, nil
 		}
+	case *JoinTableExpr:
+		return ChangefeedTargetFromTableExpr(t.Left)
 	}
 	return ChangefeedTarget{}, pgerror.Newf(
 		pgcode.InvalidName, "unsupported changefeed target type")
@@ -32,10 +32,41 @@ type LogicalReplicationOptions struct {
 	Mode             Expr
 	DefaultFunction  Expr
 	Discard          Expr
+	SchemaChanges    Expr
 	SkipSchemaCheck  *DBool
 	Unidirectional   *DBool
 	BidirectionalURI Expr
 	ParentID         Expr
+	// MergeRules override last-write-wins for individual columns.
+	MergeRules []LogicalReplicationMergeRule
+	// ColumnMappings describe how the columns of a destination table are
+	// computed from the columns of its source table.
+	ColumnMappings []LogicalReplicationColumnMapping
+}
+
+// LogicalReplicationMergeRule is a MERGE COLUMN option, which configures how
+// conflicting values of a column of a replicated table are merged.
+type LogicalReplicationMergeRule struct {
+	Column   Name
+	Strategy Expr
+	Table    UnresolvedName
+}
+
+// LogicalReplicationColumnMapping is either a COLUMN option, which computes a
+// column of a destination table from an expression over the columns of the
+// source table, or an EXCLUDE COLUMN option, which excludes a column of the
+// source table from replication.
+type LogicalReplicationColumnMapping struct {
+	Column Name
+	// Expr is nil if the source column is excluded. It is DefaultVal if the
+	// destination column is not replicated and takes its default value.
+	Expr  Expr
+	Table UnresolvedName
+}
+
+// IsExclude returns true if the mapping is an EXCLUDE COLUMN option.
+func (m *LogicalReplicationColumnMapping) IsExclude() bool {
+	return m.Expr == nil
 }
 
 var _ Statement = &CreateLogicalReplicationStream{}
@@ -135,12 +166,43 @@ func (lro *LogicalReplicationOptions) Format(ctx *FmtCtx) {
 			ctx.FormatNode(&k)
 		}
 	}
+	for i := range lro.MergeRules {
+		maybeAddSep()
+		r := &lro.MergeRules[i]
+		ctx.WriteString("MERGE COLUMN ")
+		ctx.FormatNode(&r.Column)
+		ctx.WriteString(" USING ")
+		ctx.FormatNode(r.Strategy)
+		ctx.WriteString(" FOR TABLE ")
+		ctx.FormatNode(&r.Table)
+	}
+	for i := range lro.ColumnMappings {
+		maybeAddSep()
+		m := &lro.ColumnMappings[i]
+		if m.IsExclude() {
+			ctx.WriteString("EXCLUDE COLUMN ")
+			ctx.FormatNode(&m.Column)
+		} else {
+			ctx.WriteString("COLUMN ")
+			ctx.FormatNode(&m.Column)
+			ctx.WriteString(" = ")
+			ctx.FormatNode(m.Expr)
+		}
+		ctx.WriteString(" FOR TABLE ")
+		ctx.FormatNode(&m.Table)
+	}
 	if lro.Discard != nil {
 		maybeAddSep()
 		ctx.WriteString("DISCARD = ")
 		ctx.FormatNode(lro.Discard)
 	}
 
+	if lro.SchemaChanges != nil {
+		maybeAddSep()
+		ctx.WriteString("SCHEMA_CHANGES = ")
+		ctx.FormatNode(lro.SchemaChanges)
+	}
+
 	if lro.SkipSchemaCheck != nil && *lro.SkipSchemaCheck {
 		maybeAddSep()
 		ctx.WriteString("SKIP SCHEMA CHECK")
@@ -206,6 +268,27 @@ func (o *LogicalReplicationOptions) CombineWith(other *LogicalReplicationOptions
 		}
 	}
 
+	for _, rule := range other.MergeRules {
+		for _, existing := range o.MergeRules {
+			if existing.Column == rule.Column && existing.Table.String() == rule.Table.String() {
+				return errors.Newf("multiple merge rules specified for column %s of table %s",
+					rule.Column.String(), rule.Table.String())
+			}
+		}
+		o.MergeRules = append(o.MergeRules, rule)
+	}
+
+	for _, mapping := range other.ColumnMappings {
+		for _, existing := range o.ColumnMappings {
+			if existing.IsExclude() == mapping.IsExclude() && existing.Column == mapping.Column &&
+				existing.Table.String() == mapping.Table.String() {
+				return errors.Newf("multiple column mappings specified for column %s of table %s",
+					mapping.Column.String(), mapping.Table.String())
+			}
+		}
+		o.ColumnMappings = append(o.ColumnMappings, mapping)
+	}
+
 	if o.Discard != nil {
 		if other.Discard != nil {
 			return errors.New("DISCARD option specified multiple times")
@@ -213,6 +296,13 @@ func (o *LogicalReplicationOptions) CombineWith(other *LogicalReplicationOptions
 	} else {
 		o.Discard = other.Discard
 	}
+	if o.SchemaChanges != nil {
+		if other.SchemaChanges != nil {
+			return errors.New("SCHEMA_CHANGES option specified multiple times")
+		}
+	} else {
+		o.SchemaChanges = other.SchemaChanges
+	}
 	if o.SkipSchemaCheck != nil {
 		if other.SkipSchemaCheck != nil {
 			return errors.New("SKIP SCHEMA CHECK option specified multiple times")
@@ -261,7 +351,10 @@ func (o LogicalReplicationOptions) IsDefault() bool {
 		o.Mode == options.Mode &&
 		o.DefaultFunction == options.DefaultFunction &&
 		o.UserFunctions == nil &&
+		o.MergeRules == nil &&
+		o.ColumnMappings == nil &&
 		o.Discard == options.Discard &&
+		o.SchemaChanges == options.SchemaChanges &&
 		o.SkipSchemaCheck == options.SkipSchemaCheck &&
 		o.MetricsLabel == options.MetricsLabel &&
 		o.Unidirectional == options.Unidirectional &&
@@ -60,35 +60,113 @@ func IsAllowedLDRSchemaChange(n Statement, virtualColNames []string, kvWriterEna
 	case *SetZoneConfig:
 		return true
 	case *AlterTable:
-		onlySafeStorageParams := true
+		onlySafeCmds := true
 		for _, cmd := range s.Cmds {
 			switch c := cmd.(type) {
 			case *AlterTableSetVisible:
 				return true
 			case *AlterTableSetDefault:
 				return true
+			case *AlterTableAddColumn:
+				// The SQL writers only write the columns they replicate, so a new
+				// column is safe as long as adding it does not rewrite the primary
+				// index or constrain the replicated rows. The KV writer requires the
+				// source and destination columns to match exactly.
+				if kvWriterEnabled || !isLDRSafeNewColumn(c.ColumnDef) {
+					onlySafeCmds = false
+				}
 			// Allow safe storage parameter changes.
 			case *AlterTableSetStorageParams:
 				// ttl_expire_after is not safe since it creates a new column and
 				// backfills it.
 				if c.StorageParams.GetVal("ttl_expire_after") != nil {
-					onlySafeStorageParams = false
+					onlySafeCmds = false
 				}
 			case *AlterTableResetStorageParams:
 				if slices.Contains(c.Params, "ttl_expire_after") {
 					// Resetting `ttl_expire_after` is not safe since it drops a column
 					// and rebuilds the primary index.
-					onlySafeStorageParams = false
+					onlySafeCmds = false
 				} else if slices.Contains(c.Params, "ttl") {
 					// Resetting `ttl` can also result in the expiration column being
 					// dropped.
-					onlySafeStorageParams = false
+					onlySafeCmds = false
 				}
 			default:
-				onlySafeStorageParams = false
+				onlySafeCmds = false
 			}
 		}
-		return onlySafeStorageParams
+		return onlySafeCmds
 	}
 	return false
 }
+
+// IsReplicatedLDRSchemaChange returns true if the schema change statement is
+// one that logical data replication jobs which replicate schema changes replay
+// on their destination tables: adding or dropping a column, an index or a
+// constraint. Such schema changes are allowed when a job replays them, and on
+// the source tables of jobs if every job referencing the table replicates
+// schema changes.
+func IsReplicatedLDRSchemaChange(n Statement) bool {
+	switch s := n.(type) {
+	case *CreateIndex:
+		// Hash-sharded indexes create a virtual column along with the index.
+		return s.Sharded == nil
+	case *DropIndex:
+		return true
+	case *AlterTable:
+		for _, cmd := range s.Cmds {
+			switch c := cmd.(type) {
+			case *AlterTableAddColumn:
+				if !isLDRReplicatedNewColumn(c.ColumnDef) {
+					return false
+				}
+			case *AlterTableDropColumn:
+			case *AlterTableAddConstraint:
+				// Changing the primary key is not replicated, and foreign keys
+				// would reference tables that are not part of the stream.
+				switch d := c.ConstraintDef.(type) {
+				case *UniqueConstraintTableDef:
+					if d.PrimaryKey || d.Sharded != nil {
+						return false
+					}
+				case *ForeignKeyConstraintTableDef:
+					return false
+				}
+			default:
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isLDRReplicatedNewColumn returns true if a new column can be replicated by
+// a logical data replication job. Unlike isLDRSafeNewColumn, columns that are
+// backfilled are allowed, since the destination backfills the column itself.
+func isLDRReplicatedNewColumn(d *ColumnTableDef) bool {
+	return !d.IsSerial &&
+		!d.GeneratedIdentity.IsGeneratedAsIdentity &&
+		!d.PrimaryKey.IsPrimaryKey &&
+		!d.HasFKConstraint() &&
+		d.Family.Name == "" && !d.Family.Create
+}
+
+// isLDRSafeNewColumn returns true if the column can be added to a table that
+// is the destination of a logical replication job. The column must be
+// nullable and must not have a default or computed value, which ensures the
+// column is added without a backfill.
+func isLDRSafeNewColumn(d *ColumnTableDef) bool {
+	return d.Nullable.Nullability != NotNull &&
+		!d.HasDefaultExpr() &&
+		!d.HasOnUpdateExpr() &&
+		!d.IsComputed() &&
+		!d.IsSerial &&
+		!d.GeneratedIdentity.IsGeneratedAsIdentity &&
+		!d.PrimaryKey.IsPrimaryKey &&
+		!d.Unique.IsUnique &&
+		!d.HasFKConstraint() &&
+		len(d.CheckExprs) == 0 &&
+		d.Family.Name == "" && !d.Family.Create
+}
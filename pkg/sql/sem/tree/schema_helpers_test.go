@@ -124,3 +124,137 @@ func TestIsAllowedLDRSchemaChange(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAllowedLDRSchemaChangeAddColumn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		stmt      string
+		isAllowed bool
+	}{
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT",
+			isAllowed: true,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT NULL, ADD COLUMN b STRING",
+			isAllowed: true,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT DEFAULT 10",
+			isAllowed: false,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT NOT NULL",
+			isAllowed: false,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT AS (b + 1) STORED",
+			isAllowed: false,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT UNIQUE",
+			isAllowed: false,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT REFERENCES u (id)",
+			isAllowed: false,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT CREATE FAMILY f2",
+			isAllowed: false,
+		},
+		{
+			stmt:      "ALTER TABLE t ADD COLUMN a INT, DROP COLUMN b",
+			isAllowed: false,
+		},
+	} {
+		t.Run(tc.stmt, func(t *testing.T) {
+			stmt, err := parser.ParseOne(tc.stmt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tree.IsAllowedLDRSchemaChange(stmt.AST, nil /* virtualColNames */, false /* kvWriterEnabled */); got != tc.isAllowed {
+				t.Errorf("expected %v, got %v", tc.isAllowed, got)
+			}
+			// The KV writer never allows new columns.
+			if tree.IsAllowedLDRSchemaChange(stmt.AST, nil /* virtualColNames */, true /* kvWriterEnabled */) {
+				t.Errorf("expected the KV writer to disallow %s", tc.stmt)
+			}
+		})
+	}
+}
+
+func TestIsReplicatedLDRSchemaChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		stmt         string
+		isReplicated bool
+	}{
+		{
+			stmt:         "ALTER TABLE t ADD COLUMN a INT NOT NULL DEFAULT 10",
+			isReplicated: true,
+		},
+		{
+			stmt:         "ALTER TABLE t ADD COLUMN a INT, DROP COLUMN b",
+			isReplicated: true,
+		},
+		{
+			stmt:         "ALTER TABLE t ADD CONSTRAINT c CHECK (a > 0)",
+			isReplicated: true,
+		},
+		{
+			stmt:         "ALTER TABLE t ADD CONSTRAINT c UNIQUE (a)",
+			isReplicated: true,
+		},
+		{
+			stmt:         "CREATE UNIQUE INDEX idx ON t (a)",
+			isReplicated: true,
+		},
+		{
+			stmt:         "DROP INDEX t@idx",
+			isReplicated: true,
+		},
+		{
+			stmt:         "ALTER TABLE t ADD COLUMN a SERIAL",
+			isReplicated: false,
+		},
+		{
+			stmt:         "ALTER TABLE t ADD CONSTRAINT c FOREIGN KEY (a) REFERENCES u (id)",
+			isReplicated: false,
+		},
+		{
+			stmt:         "ALTER TABLE t ADD PRIMARY KEY (a)",
+			isReplicated: false,
+		},
+		{
+			stmt:         "ALTER TABLE t ALTER PRIMARY KEY USING COLUMNS (a)",
+			isReplicated: false,
+		},
+		{
+			stmt:         "ALTER TABLE t ALTER COLUMN a TYPE STRING",
+			isReplicated: false,
+		},
+		{
+			stmt:         "ALTER TABLE t RENAME COLUMN a TO b",
+			isReplicated: false,
+		},
+		{
+			stmt:         "CREATE INDEX idx ON t (a) USING HASH",
+			isReplicated: false,
+		},
+	} {
+		t.Run(tc.stmt, func(t *testing.T) {
+			stmt, err := parser.ParseOne(tc.stmt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tree.IsReplicatedLDRSchemaChange(stmt.AST); got != tc.isReplicated {
+				t.Errorf("expected %v, got %v", tc.isReplicated, got)
+			}
+		})
+	}
+}
@@ -63,6 +63,11 @@ func (node *SetVar) Format(ctx *FmtCtx) {
 type SetClusterSetting struct {
 	Name  string
 	Value Expr
+	// ExpectedValue, if set, makes the statement fail unless the encoded value
+	// currently stored for the setting is *ExpectedValue. It has no SQL syntax
+	// and is used by statements rewritten into a read-modify-write of a
+	// setting, e.g. CREATE RESOURCE GROUP, to not overwrite concurrent changes.
+	ExpectedValue *string
 }
 
 // Format implements the NodeFormatter interface.
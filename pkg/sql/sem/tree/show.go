@@ -86,6 +86,9 @@ const (
 	// BackupValidateDetails identifies a SHOW BACKUP VALIDATION
 	// statement.
 	BackupValidateDetails
+	// BackupTableDetails identifies a SHOW BACKUP TABLE statement, which shows
+	// the rows of a table in the backup.
+	BackupTableDetails
 )
 
 // TODO (msbutler): 22.2 after removing old style show backup syntax, rename
@@ -100,6 +103,11 @@ type ShowBackup struct {
 	From         bool
 	Details      ShowBackupDetails
 	Options      ShowBackupOptions
+
+	// Table, AsOf and Where are only set for a SHOW BACKUP TABLE statement.
+	Table *UnresolvedObjectName
+	AsOf  AsOfClause
+	Where *Where
 }
 
 // Format implements the NodeFormatter interface.
@@ -118,6 +126,10 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 		ctx.WriteString("FILES ")
 	case BackupSchemaDetails:
 		ctx.WriteString("SCHEMAS ")
+	case BackupTableDetails:
+		ctx.WriteString("TABLE ")
+		ctx.FormatNode(node.Table)
+		ctx.WriteString(" ")
 	}
 
 	if node.From {
@@ -128,6 +140,16 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 	ctx.WriteString(" IN ")
 	ctx.FormatURIs(node.InCollection)
 
+	if node.AsOf.Expr != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(&node.AsOf)
+	}
+
+	if node.Where != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Where)
+	}
+
 	if !node.Options.IsDefault() {
 		ctx.WriteString(" WITH OPTIONS (")
 		ctx.FormatNode(&node.Options)
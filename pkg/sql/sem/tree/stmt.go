@@ -415,6 +415,17 @@ func (*AlterDatabaseSetZoneConfigExtension) StatementTag() string {
 
 func (*AlterDatabaseSetZoneConfigExtension) hiddenFromShowQueries() {}
 
+// StatementReturnType implements the Statement interface.
+func (*AlterDatabaseRevert) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*AlterDatabaseRevert) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterDatabaseRevert) StatementTag() string { return "ALTER DATABASE REVERT" }
+
+func (*AlterDatabaseRevert) cclOnlyStatement() {}
+
 // StatementReturnType implements the Statement interface.
 func (*AlterDefaultPrivileges) StatementReturnType() StatementReturnType { return DDL }
 
@@ -493,6 +504,17 @@ func (*AlterTableOwner) StatementTag() string { return "ALTER TABLE" }
 
 func (*AlterTableOwner) hiddenFromShowQueries() {}
 
+// StatementReturnType implements the Statement interface.
+func (*AlterTableRevert) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*AlterTableRevert) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterTableRevert) StatementTag() string { return "ALTER TABLE REVERT" }
+
+func (*AlterTableRevert) cclOnlyStatement() {}
+
 // StatementType implements the Statement interface.
 func (*AlterTableSetLogged) StatementReturnType() StatementReturnType { return DDL }
 
@@ -616,6 +638,15 @@ func (*AlterSequence) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*AlterSequence) StatementTag() string { return "ALTER SEQUENCE" }
 
+// StatementReturnType implements the Statement interface.
+func (*AlterResourceGroup) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*AlterResourceGroup) StatementType() StatementType { return TypeDCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterResourceGroup) StatementTag() string { return "ALTER RESOURCE GROUP" }
+
 // StatementReturnType implements the Statement interface.
 func (*AlterRole) StatementReturnType() StatementReturnType { return DDL }
 
@@ -1075,6 +1106,15 @@ func (*CreateType) StatementTag() string { return "CREATE TYPE" }
 
 func (*CreateType) modifiesSchema() bool { return true }
 
+// StatementReturnType implements the Statement interface.
+func (*CreateResourceGroup) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*CreateResourceGroup) StatementType() StatementType { return TypeDCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateResourceGroup) StatementTag() string { return "CREATE RESOURCE GROUP" }
+
 // StatementReturnType implements the Statement interface.
 func (*CreateRole) StatementReturnType() StatementReturnType { return DDL }
 
@@ -1223,6 +1263,15 @@ func (*DropSequence) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropSequence) StatementTag() string { return DropSequenceTag }
 
+// StatementReturnType implements the Statement interface.
+func (*DropResourceGroup) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*DropResourceGroup) StatementType() StatementType { return TypeDCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropResourceGroup) StatementTag() string { return "DROP RESOURCE GROUP" }
+
 // StatementReturnType implements the Statement interface.
 func (*DropRole) StatementReturnType() StatementReturnType { return DDL }
 
@@ -1918,6 +1967,15 @@ func (*ShowChangefeedJobs) StatementType() StatementType { return TypeDML }
 // StatementTag returns a short string identifying the type of statement.
 func (*ShowChangefeedJobs) StatementTag() string { return "SHOW CHANGEFEED JOBS" }
 
+// StatementReturnType implements the Statement interface.
+func (*ShowResourceGroups) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*ShowResourceGroups) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowResourceGroups) StatementTag() string { return "SHOW RESOURCE GROUPS" }
+
 // StatementReturnType implements the Statement interface.
 func (*ShowRoleGrants) StatementReturnType() StatementReturnType { return Rows }
 
@@ -2484,6 +2542,7 @@ func (n *AlterDatabaseAlterSuperRegion) String() string       { return AsString(
 func (n *AlterDatabaseSecondaryRegion) String() string        { return AsString(n) }
 func (n *AlterDatabaseDropSecondaryRegion) String() string    { return AsString(n) }
 func (n *AlterDatabaseSetZoneConfigExtension) String() string { return AsString(n) }
+func (n *AlterDatabaseRevert) String() string                 { return AsString(n) }
 func (n *AlterDefaultPrivileges) String() string              { return AsString(n) }
 func (n *AlterFunctionOptions) String() string                { return AsString(n) }
 func (n *AlterPolicy) String() string                         { return AsString(n) }
@@ -2508,6 +2567,7 @@ func (n *AlterTableSetDefault) String() string                { return AsString(
 func (n *AlterTableSetVisible) String() string                { return AsString(n) }
 func (n *AlterTableSetNotNull) String() string                { return AsString(n) }
 func (n *AlterTableOwner) String() string                     { return AsString(n) }
+func (n *AlterTableRevert) String() string                    { return AsString(n) }
 func (n *AlterTableSetLogged) String() string                 { return AsString(n) }
 func (n *AlterTableSetSchema) String() string                 { return AsString(n) }
 func (n *AlterTenantCapability) String() string               { return AsString(n) }
@@ -2517,6 +2577,7 @@ func (n *AlterTenantRename) String() string                   { return AsString(
 func (n *AlterTenantReplication) String() string              { return AsString(n) }
 func (n *AlterTenantService) String() string                  { return AsString(n) }
 func (n *AlterType) String() string                           { return AsString(n) }
+func (n *AlterResourceGroup) String() string                  { return AsString(n) }
 func (n *AlterRole) String() string                           { return AsString(n) }
 func (n *AlterRoleSet) String() string                        { return AsString(n) }
 func (n *AlterSequence) String() string                       { return AsString(n) }
@@ -2551,6 +2612,7 @@ func (n *CreateTrigger) String() string                       { return AsString(
 func (n *CreateIndex) String() string                         { return AsString(n) }
 func (n *CreateLogicalReplicationStream) String() string      { return AsString(n) }
 func (n *CreatePolicy) String() string                        { return AsString(n) }
+func (n *CreateResourceGroup) String() string                 { return AsString(n) }
 func (n *CreateRole) String() string                          { return AsString(n) }
 func (n *CreateTable) String() string                         { return AsString(n) }
 func (n *CreateTenant) String() string                        { return AsString(n) }
@@ -2574,6 +2636,7 @@ func (n *DropSequence) String() string                        { return AsString(
 func (n *DropTable) String() string                           { return AsString(n) }
 func (n *DropType) String() string                            { return AsString(n) }
 func (n *DropView) String() string                            { return AsString(n) }
+func (n *DropResourceGroup) String() string                   { return AsString(n) }
 func (n *DropRole) String() string                            { return AsString(n) }
 func (n *DropTenant) String() string                          { return AsString(n) }
 func (n *Execute) String() string                             { return AsString(n) }
@@ -2658,6 +2721,7 @@ func (n *ShowQueries) String() string                         { return AsString(
 func (n *ShowRanges) String() string                          { return AsString(n) }
 func (n *ShowRangeForRow) String() string                     { return AsString(n) }
 func (n *ShowRegions) String() string                         { return AsString(n) }
+func (n *ShowResourceGroups) String() string                  { return AsString(n) }
 func (n *ShowRoleGrants) String() string                      { return AsString(n) }
 func (n *ShowRoles) String() string                           { return AsString(n) }
 func (n *ShowSavepointStatus) String() string                 { return AsString(n) }
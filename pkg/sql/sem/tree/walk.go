@@ -1218,6 +1218,44 @@ func (n *AlterTenantRename) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (n *AlterDatabaseRevert) copyNode() *AlterDatabaseRevert {
+	stmtCopy := *n
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (n *AlterDatabaseRevert) walkStmt(v Visitor) Statement {
+	ret := n
+	if n.Timestamp != nil {
+		e, changed := WalkExpr(v, n.Timestamp)
+		if changed {
+			ret = n.copyNode()
+			ret.Timestamp = e
+		}
+	}
+	return ret
+}
+
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (n *AlterTableRevert) copyNode() *AlterTableRevert {
+	stmtCopy := *n
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (n *AlterTableRevert) walkStmt(v Visitor) Statement {
+	ret := n
+	if n.Timestamp != nil {
+		e, changed := WalkExpr(v, n.Timestamp)
+		if changed {
+			ret = n.copyNode()
+			ret.Timestamp = e
+		}
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (n *AlterTenantReset) copyNode() *AlterTenantReset {
 	stmtCopy := *n
@@ -1638,6 +1676,16 @@ func (stmt *Restore) walkStmt(v Visitor) Statement {
 		}
 	}
 
+	if stmt.Options.RowFilter != nil {
+		rowFilter, changed := WalkExpr(v, stmt.Options.RowFilter)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Options.RowFilter = rowFilter
+		}
+	}
+
 	return ret
 }
 
@@ -2040,6 +2088,8 @@ func (stmt *BeginTransaction) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+var _ walkableStmt = &AlterDatabaseRevert{}
+var _ walkableStmt = &AlterTableRevert{}
 var _ walkableStmt = &AlterTenantCapability{}
 var _ walkableStmt = &AlterTenantRename{}
 var _ walkableStmt = &AlterTenantReplication{}
@@ -77,6 +77,9 @@ type InternalExecutorOverride struct {
 	// executor session is responsible for ensuring that every row it writes via
 	// the internal executor had this origin timestamp.
 	OriginTimestampForLogicalDataReplication hlc.Timestamp
+	// LogicalReplicationSchemaChangeJobID is the ID of the logical replication
+	// job whose replicated schema changes are replayed in this session.
+	LogicalReplicationSchemaChangeJobID int64
 	// PlanCacheMode, if set, overrides the plan_cache_mode session variable.
 	PlanCacheMode *sessiondatapb.PlanCacheMode
 	// GrowStackSize, if true, indicates that the connExecutor goroutine stack
@@ -59,6 +59,9 @@ type setClusterSettingNode struct {
 	setting settings.NonMaskedSetting
 	// If value is nil, the setting should be reset.
 	value tree.TypedExpr
+	// expectedValue, if set, is the encoded value the setting must have for
+	// the new value to be written. See tree.SetClusterSetting.
+	expectedValue *string
 }
 
 func checkPrivilegesForSetting(
@@ -237,11 +240,16 @@ func (p *planner) SetClusterSetting(
 		printTTLRateLimitNotice(ctx, p)
 	}
 
+	if n.ExpectedValue != nil && value == nil {
+		return nil, errors.AssertionFailedf("cannot reset cluster setting %s conditionally", name)
+	}
+
 	csNode := setClusterSettingNode{
-		name:    name,
-		st:      st,
-		setting: setting,
-		value:   value,
+		name:          name,
+		st:            st,
+		setting:       setting,
+		value:         value,
+		expectedValue: n.ExpectedValue,
 	}
 	return &csNode, nil
 }
@@ -344,6 +352,7 @@ func (n *setClusterSettingNode) startExec(params runParams) error {
 		params.p.User(),
 		n.st,
 		n.value,
+		n.expectedValue,
 		params.p.EvalContext(),
 		params.p.logEvent,
 		params.p.descCollection.ReleaseLeases,
@@ -428,6 +437,7 @@ func writeSettingInternal(
 	user username.SQLUsername,
 	st *cluster.Settings,
 	value tree.TypedExpr,
+	expectedValue *string,
 	evalCtx *eval.Context,
 	logFn func(context.Context, descpb.ID, logpb.EventPayload) error,
 	releaseLeases func(context.Context),
@@ -449,7 +459,7 @@ func writeSettingInternal(
 				return err
 			}
 			reportedValue, expectedEncodedValue, err = writeNonDefaultSettingValue(
-				ctx, hook, db, setting, user, st, value, releaseLeases, interlockInfo,
+				ctx, hook, db, setting, user, st, value, expectedValue, releaseLeases, interlockInfo,
 			)
 			if err != nil {
 				return err
@@ -500,6 +510,7 @@ func writeNonDefaultSettingValue(
 	user username.SQLUsername,
 	st *cluster.Settings,
 	value tree.Datum,
+	expectedValue *string,
 	releaseLeases func(context.Context),
 	interlockInfo unsafeSettingInterlockInfo,
 ) (reportedValue string, expectedEncodedValue string, err error) {
@@ -514,6 +525,10 @@ func writeNonDefaultSettingValue(
 	}
 
 	verSetting, isSetVersion := setting.(*settings.VersionSetting)
+	if isSetVersion && expectedValue != nil {
+		return reportedValue, expectedEncodedValue, errors.AssertionFailedf(
+			"cannot set cluster version conditionally")
+	}
 	if isSetVersion {
 		if err := setVersionSetting(
 			ctx, hook, verSetting, db, user, st, value, encoded, releaseLeases,
@@ -528,12 +543,37 @@ func writeNonDefaultSettingValue(
 			}
 		}
 
-		if _, err = db.Executor().ExecEx(
-			ctx, "update-setting", nil,
-			sessiondata.NodeUserSessionDataOverride,
-			`UPSERT INTO system.settings (name, value, "lastUpdated", "valueType") VALUES ($1, $2, now(), $3)`,
-			setting.InternalKey(), encoded, setting.Typ(),
-		); err != nil {
+		if err := db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			if expectedValue != nil {
+				// Lock the setting's row so that the value that is checked is the
+				// one that is overwritten.
+				datums, err := txn.QueryRowEx(
+					ctx, "retrieve-prev-setting", txn.KV(),
+					sessiondata.NodeUserSessionDataOverride,
+					"SELECT value FROM system.settings WHERE name = $1 FOR UPDATE", setting.InternalKey(),
+				)
+				if err != nil {
+					return err
+				}
+				prev := setting.EncodedDefault()
+				if len(datums) > 0 {
+					prev = string(tree.MustBeDString(datums[0]))
+				}
+				if prev != *expectedValue {
+					return errors.WithHint(
+						pgerror.Newf(pgcode.SerializationFailure,
+							"cluster setting %s was modified concurrently", setting.Name()),
+						"Retry the statement.")
+				}
+			}
+			_, err := txn.ExecEx(
+				ctx, "update-setting", txn.KV(),
+				sessiondata.NodeUserSessionDataOverride,
+				`UPSERT INTO system.settings (name, value, "lastUpdated", "valueType") VALUES ($1, $2, now(), $3)`,
+				setting.InternalKey(), encoded, setting.Typ(),
+			)
+			return err
+		}); err != nil {
 			return reportedValue, expectedEncodedValue, err
 		}
 	}
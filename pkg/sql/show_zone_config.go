@@ -217,6 +217,10 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (tree.Datu
 		maybeWriteComma(f)
 		f.Printf("\tnum_voters = %d", *zone.NumVoters)
 	}
+	if zone.StorageTier != nil {
+		maybeWriteComma(f)
+		f.Printf("\tstorage_tier = %s", lexbase.EscapeSQLString(*zone.StorageTier))
+	}
 	if !zone.InheritedConstraints {
 		maybeWriteComma(f)
 		f.Printf("\tconstraints = %s", lexbase.EscapeSQLString(constraints))
@@ -14,6 +14,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/mutations"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
@@ -86,6 +87,10 @@ type tableWriterBase struct {
 	// originally written with before being replicated via Logical Data
 	// Replication.
 	originTimestamp hlc.Timestamp
+	// expireAfter, if positive, is the duration after which the values written
+	// by the table writer expire at the MVCC layer. It is set for tables with
+	// the ttl_mvcc_expiration storage parameter.
+	expireAfter time.Duration
 }
 
 var maxBatchBytes = settings.RegisterByteSizeSetting(
@@ -108,6 +113,14 @@ func (tb *tableWriterBase) init(
 	tb.deadlockTimeout = 0
 	tb.originID = 0
 	tb.originTimestamp = hlc.Timestamp{}
+	tb.expireAfter = 0
+	if ttl := tableDesc.GetRowLevelTTL(); ttl != nil && ttl.MVCCExpiration {
+		expireAfter, err := tabledesc.MVCCExpireAfter(ttl)
+		if err != nil {
+			return err
+		}
+		tb.expireAfter = expireAfter
+	}
 	if evalCtx != nil {
 		tb.lockTimeout = evalCtx.SessionData().LockTimeout
 		tb.deadlockTimeout = evalCtx.SessionData().DeadlockTimeout
@@ -192,9 +205,10 @@ func (tb *tableWriterBase) tryDoResponseAdmission(ctx context.Context) error {
 	if responseAdmissionQ != nil {
 		requestAdmissionHeader := tb.txn.AdmissionHeader()
 		responseAdmission := admission.WorkInfo{
-			TenantID:   roachpb.SystemTenantID,
-			Priority:   admissionpb.WorkPriority(requestAdmissionHeader.Priority),
-			CreateTime: requestAdmissionHeader.CreateTime,
+			TenantID:      roachpb.SystemTenantID,
+			Priority:      admissionpb.WorkPriority(requestAdmissionHeader.Priority),
+			CreateTime:    requestAdmissionHeader.CreateTime,
+			ResourceGroup: requestAdmissionHeader.ResourceGroup,
 		}
 		if _, err := responseAdmissionQ.Admit(ctx, responseAdmission); err != nil {
 			return err
@@ -212,10 +226,11 @@ func (tb *tableWriterBase) initNewBatch() {
 	tb.putter.Batch = tb.b
 	tb.b.Header.LockTimeout = tb.lockTimeout
 	tb.b.Header.DeadlockTimeout = tb.deadlockTimeout
-	if tb.originID != 0 {
+	if tb.originID != 0 || tb.expireAfter != 0 {
 		tb.b.Header.WriteOptions = &kvpb.WriteOptions{
 			OriginID:        tb.originID,
 			OriginTimestamp: tb.originTimestamp,
+			ExpireAfter:     tb.expireAfter,
 		}
 	}
 }
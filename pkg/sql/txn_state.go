@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -19,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -184,6 +186,9 @@ const (
 //
 // qualityOfService: If txn is nil, the QoSLevel/WorkPriority to assign the new
 // transaction for use in admission queues.
+//
+// resourceGroup: If txn is nil, the workload resource group to assign the new
+// transaction for use in admission queues.
 func (ts *txnState) resetForNewSQLTxn(
 	connCtx context.Context,
 	txnType txnType,
@@ -194,6 +199,7 @@ func (ts *txnState) resetForNewSQLTxn(
 	txn *kv.Txn,
 	tranCtx transitionCtx,
 	qualityOfService sessiondatapb.QoSLevel,
+	resourceGroup string,
 	isoLevel isolation.Level,
 	omitInRangefeeds bool,
 	bufferedWritesEnabled bool,
@@ -243,6 +249,17 @@ func (ts *txnState) resetForNewSQLTxn(
 		if txn == nil {
 			ts.mu.txn = kv.NewTxnWithSteppingEnabled(ts.Ctx, tranCtx.db, tranCtx.nodeIDOrZero, qualityOfService)
 			ts.mu.txn.SetDebugName(opName)
+			if resourceGroup != "" {
+				// Resource groups are defined per tenant, so send the definition of
+				// the group along with the name.
+				g := admission.LookupResourceGroup(&tranCtx.settings.SV, resourceGroup)
+				ts.mu.txn.SetResourceGroup(g.Name, kvpb.ResourceGroupShares{
+					CPUWeight: g.CPUWeight,
+					IOWeight:  g.IOWeight,
+					CPUBurst:  g.CPUBurst,
+					IOBurst:   g.IOBurst,
+				})
+			}
 			if omitInRangefeeds {
 				ts.mu.txn.SetOmitInRangefeeds()
 			}
@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -832,6 +833,26 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`resource_group`: {
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			// Groups that are not defined are allowed, so that defaults set with
+			// ALTER ROLE do not break sessions when a group is removed. Work of
+			// such groups is admitted as part of the default group.
+			if s != "" {
+				if err := admission.ValidateResourceGroupName(s); err != nil {
+					return newVarValueError(`resource_group`, s)
+				}
+			}
+			m.SetResourceGroup(s)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return evalCtx.SessionData().ResourceGroup, nil
+		},
+		GlobalDefault: func(_ *settings.Values) string { return "" },
+	},
+
 	// CockroachDB extension.
 	`vectorize`: {
 		Set: func(_ context.Context, m sessionDataMutator, s string) error {
@@ -1241,6 +1241,12 @@ type Metrics struct {
 	SharedStorageWriteBytes int64
 	// SharedStorageReadBytes counts the number of bytes read from shared storage.
 	SharedStorageReadBytes int64
+	// ColdStorageBytes is the size of the sstables placed on cold storage, as of
+	// the last pass of TierColdStorage.
+	ColdStorageBytes int64
+	// ColdStorageReadRetries counts the number of times reads from cold storage
+	// were retried after failing.
+	ColdStorageReadRetries int64
 	// WriteStallCount counts the number of times Pebble intentionally delayed
 	// incoming writes. Currently, the only two reasons for this to happen are:
 	// - "memtable count limit reached"
@@ -29,6 +29,7 @@ func populatedMVCCValueHeader() MVCCValueHeader {
 		ImportEpoch:      1,
 		OriginID:         1,
 		OriginTimestamp:  hlc.Timestamp{WallTime: 1, Logical: 1},
+		Expiration:       hlc.Timestamp{WallTime: 2, Logical: 1},
 	}
 	allFieldsSet.KVNemesisSeq.Set(123)
 	return allFieldsSet
@@ -41,6 +42,7 @@ func defaultMVCCValueHeader() MVCCValueHeader {
 		ImportEpoch:      0,
 		OriginID:         0,
 		OriginTimestamp:  hlc.Timestamp{},
+		Expiration:       hlc.Timestamp{},
 	}
 }
 
@@ -60,6 +62,7 @@ func TestMVCCValueHeader_IsEmpty(t *testing.T) {
 	require.False(t, MVCCValueHeader{ImportEpoch: allFieldsSet.ImportEpoch}.IsEmpty())
 	require.False(t, MVCCValueHeader{OriginID: allFieldsSet.OriginID}.IsEmpty())
 	require.False(t, MVCCValueHeader{OriginTimestamp: allFieldsSet.OriginTimestamp}.IsEmpty())
+	require.False(t, MVCCValueHeader{Expiration: allFieldsSet.Expiration}.IsEmpty())
 }
 
 func TestMVCCValueHeader_MarshalUnmarshal(t *testing.T) {
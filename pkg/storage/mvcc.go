@@ -1087,6 +1087,24 @@ func updateStatsOnGC(
 	return ms
 }
 
+// updateStatsOnExpiredGC returns the stats delta for the latest, expired
+// value of a key becoming non-live at nowNanos, ahead of it being garbage
+// collected. The caller must also apply the updateStatsOnGC deltas for the
+// removal of the key and its versions, using nowNanos as the time at which
+// the latest value became non-live.
+func updateStatsOnExpiredGC(
+	key roachpb.Key, metaKeySize, metaValSize int64, meta *enginepb.MVCCMetadata, nowNanos int64,
+) enginepb.MVCCStats {
+	var ms enginepb.MVCCStats
+	if isSysLocal(key) {
+		return ms
+	}
+	ms.AgeTo(nowNanos)
+	ms.LiveBytes -= metaKeySize + metaValSize + meta.KeyBytes + meta.ValBytes
+	ms.LiveCount--
+	return ms
+}
+
 // MVCCGetProto fetches the value at the specified key and unmarshals it into
 // msg if msg is non-nil. Returns true on success or false if the key was not
 // found.
@@ -2709,6 +2727,9 @@ func mvccPutInternal(
 	if opts.OriginTimestamp.IsSet() {
 		versionValue.OriginTimestamp = opts.OriginTimestamp
 	}
+	if opts.ExpireAfter > 0 && !versionValue.IsTombstone() {
+		versionValue.Expiration = versionKey.Timestamp.Add(opts.ExpireAfter.Nanoseconds(), 0)
+	}
 
 	if buildutil.CrdbTestBuild {
 		if seq, seqOK := kvnemesisutil.FromContext(ctx); seqOK {
@@ -4626,6 +4647,15 @@ type MVCCWriteOptions struct {
 	// OriginTimestamp, when set during Logical Data Replication, will bind to the
 	// putting key's MVCCValueHeader.
 	OriginTimestamp hlc.Timestamp
+	// ExpireAfter, if positive, sets the Expiration in the MVCCValueHeader of
+	// the written value to its version timestamp plus ExpireAfter. It is ignored
+	// for deletion tombstones.
+	//
+	// Expiration is a property of readers: MVCCStats are computed when values
+	// are written, and keep counting an expired value as live until it is
+	// garbage collected. Callers relying on LiveCount and LiveBytes, such as
+	// the GC score, may therefore overestimate the live data of a range.
+	ExpireAfter time.Duration
 	// MaxLockConflicts is a maximum number of conflicting locks collected before
 	// returning LockConflictError. Even single-key writes can encounter multiple
 	// conflicting shared locks, so the limit is important to bound the number of
@@ -6420,8 +6450,29 @@ func MVCCGarbageCollect(
 			// not marked deleted. However, for inline values we allow it;
 			// they are internal and GCing them directly saves the extra
 			// deletion step.
+			//
+			// The latest value may also be GC'ed if it is not deleted but expired
+			// at or below the GC threshold. Such values are accounted for as live
+			// in the stats until they are GC'ed, so they become non-live now.
+			nonLiveNanos := meta.Timestamp.WallTime
 			if !meta.Deleted && !inlinedValue {
-				return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				expired := false
+				if implicitMeta && meta.Txn == nil {
+					v, err := iter.UnsafeValue()
+					if err != nil {
+						return err
+					}
+					if expired, err = EncodedMVCCValueIsExpiredAt(v, timestamp); err != nil {
+						return err
+					}
+				}
+				if !expired {
+					return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				}
+				if ms != nil {
+					ms.Add(updateStatsOnExpiredGC(gcKey.Key, metaKeySize, metaValSize, meta, timestamp.WallTime))
+				}
+				nonLiveNanos = timestamp.WallTime
 			}
 			if meta.Txn != nil {
 				return errors.Errorf("request to GC intent at %q", gcKey.Key)
@@ -6431,7 +6482,7 @@ func MVCCGarbageCollect(
 					updateStatsForInline(ms, gcKey.Key, metaKeySize, metaValSize, 0, 0)
 					ms.AgeTo(timestamp.WallTime)
 				} else {
-					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, true /* metaKey */, meta.Timestamp.WallTime))
+					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, true /* metaKey */, nonLiveNanos))
 				}
 			}
 			if !implicitMeta {
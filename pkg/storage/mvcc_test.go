@@ -2902,6 +2902,86 @@ func TestMVCCConditionalPutOldTimestamp(t *testing.T) {
 	}
 }
 
+// TestMVCCConditionalPutExpired verifies that conditional puts treat a value
+// that expired at or below their read timestamp as absent, both when reading
+// the latest committed value and when reading below the intent of their own
+// transaction.
+func TestMVCCConditionalPutExpired(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1e9}
+	ts2 := hlc.Timestamp{WallTime: 2e9}
+	ts4 := hlc.Timestamp{WallTime: 4e9}
+
+	// setup writes value1 at ts1, expiring at ts3.
+	setup := func(t *testing.T) Engine {
+		engine := NewDefaultInMemForTesting()
+		_, err := MVCCPut(ctx, engine, testKey1, ts1, value1, MVCCWriteOptions{ExpireAfter: 2 * time.Second})
+		require.NoError(t, err)
+		return engine
+	}
+	requireConditionFailed := func(t *testing.T, err error) {
+		var cfErr *kvpb.ConditionFailedError
+		require.ErrorAs(t, err, &cfErr)
+		require.False(t, cfErr.ActualValue.IsPresent())
+	}
+
+	t.Run("before expiration", func(t *testing.T) {
+		engine := setup(t)
+		defer engine.Close()
+		_, err := MVCCConditionalPut(ctx, engine, testKey1, ts2, value2, nil, ConditionalPutWriteOptions{})
+		require.ErrorAs(t, err, new(*kvpb.ConditionFailedError))
+		_, err = MVCCConditionalPut(ctx, engine, testKey1, ts2, value2, value1.TagAndDataBytes(),
+			ConditionalPutWriteOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("after expiration", func(t *testing.T) {
+		engine := setup(t)
+		defer engine.Close()
+		_, err := MVCCConditionalPut(ctx, engine, testKey1, ts4, value2, value1.TagAndDataBytes(),
+			ConditionalPutWriteOptions{})
+		requireConditionFailed(t, err)
+		// A missing value is allowed in place of the expected one.
+		_, err = MVCCConditionalPut(ctx, engine, testKey1, ts4, value2, value1.TagAndDataBytes(),
+			ConditionalPutWriteOptions{AllowIfDoesNotExist: CPutAllowIfMissing})
+		require.NoError(t, err)
+	})
+
+	t.Run("after expiration, absent", func(t *testing.T) {
+		engine := setup(t)
+		defer engine.Close()
+		_, err := MVCCConditionalPut(ctx, engine, testKey1, ts4, value2, nil, ConditionalPutWriteOptions{})
+		require.NoError(t, err)
+		valueRes, err := MVCCGet(ctx, engine, testKey1, ts4, MVCCGetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, value2.RawBytes, valueRes.Value.RawBytes)
+	})
+
+	t.Run("below own intent", func(t *testing.T) {
+		engine := setup(t)
+		defer engine.Close()
+		txn := makeTxn(*txn1, ts4)
+		txn.Sequence++
+		_, err := MVCCConditionalPut(ctx, engine, testKey1, txn.ReadTimestamp, value2, nil,
+			ConditionalPutWriteOptions{MVCCWriteOptions: MVCCWriteOptions{Txn: txn}})
+		require.NoError(t, err)
+
+		// Once the intent is rolled back, the conditional put reads the
+		// committed value below it, which expired.
+		txn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: txn.Sequence, End: txn.Sequence}}
+		txn.Sequence++
+		_, err = MVCCConditionalPut(ctx, engine, testKey1, txn.ReadTimestamp, value3, value1.TagAndDataBytes(),
+			ConditionalPutWriteOptions{MVCCWriteOptions: MVCCWriteOptions{Txn: txn}})
+		requireConditionFailed(t, err)
+		_, err = MVCCConditionalPut(ctx, engine, testKey1, txn.ReadTimestamp, value3, nil,
+			ConditionalPutWriteOptions{MVCCWriteOptions: MVCCWriteOptions{Txn: txn}})
+		require.NoError(t, err)
+	})
+}
+
 // TestMVCCMultiplePutOldTimestamp tests a case where multiple transactional
 // Puts occur to the same key, but with older timestamps than a pre-existing
 // key. The first should generate a WriteTooOldError and fail to write. The
@@ -5000,6 +5080,80 @@ func TestMVCCGarbageCollectNonDeleted(t *testing.T) {
 	require.NoError(t, engine.Compact(ctx))
 }
 
+// TestMVCCGarbageCollectExpired verifies that values written with an
+// expiration read as deleted at and above their expiration, and that the
+// latest value of a key can be GC'd once it expired at or below the GC
+// threshold.
+func TestMVCCGarbageCollectExpired(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := NewDefaultInMemForTesting()
+	defer engine.Close()
+
+	ms := &enginepb.MVCCStats{}
+	ts1 := hlc.Timestamp{WallTime: 1e9}
+	ts2 := hlc.Timestamp{WallTime: 2e9}
+	ts3 := hlc.Timestamp{WallTime: 3e9}
+
+	// "a" expires at ts3 and "b" at ts4.
+	opts := MVCCWriteOptions{Stats: ms, ExpireAfter: 2 * time.Second}
+	for _, w := range []struct {
+		key string
+		ts  hlc.Timestamp
+	}{{"a", ts1}, {"b", ts1}, {"b", ts2}} {
+		_, err := MVCCPut(ctx, engine, roachpb.Key(w.key), w.ts, roachpb.MakeValueFromString("v"), opts)
+		require.NoError(t, err)
+	}
+
+	res, err := MVCCGet(ctx, engine, roachpb.Key("a"), ts2, MVCCGetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, res.Value)
+	res, err = MVCCGet(ctx, engine, roachpb.Key("a"), ts3, MVCCGetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, res.Value)
+	res, err = MVCCGet(ctx, engine, roachpb.Key("a"), ts3, MVCCGetOptions{Tombstones: true})
+	require.NoError(t, err)
+	require.NotNil(t, res.Value)
+	require.False(t, res.Value.IsPresent())
+	scanRes, err := MVCCScan(ctx, engine, roachpb.Key("a"), roachpb.Key("c"), ts3, MVCCScanOptions{})
+	require.NoError(t, err)
+	require.Len(t, scanRes.KVs, 1)
+	require.Equal(t, roachpb.Key("b"), scanRes.KVs[0].Key)
+
+	// Expired values are counted as live in the stats until they are GC'd,
+	// even though readers treat them as deleted.
+	expMS, err := ComputeStats(ctx, engine, localMax, keyMax, ts3.WallTime)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), expMS.LiveCount)
+	assertEq(t, engine, "before GC", ms, &expMS)
+
+	// The latest value of "b" has not expired at the GC threshold.
+	err = MVCCGarbageCollect(ctx, engine, nil, []kvpb.GCRequest_GCKey{
+		{Key: roachpb.Key("b"), Timestamp: ts2},
+	}, ts3)
+	require.ErrorContains(t, err, `request to GC non-deleted, latest value of "b"`)
+
+	require.NoError(t, MVCCGarbageCollect(ctx, engine, ms, []kvpb.GCRequest_GCKey{
+		{Key: roachpb.Key("a"), Timestamp: ts1},
+		{Key: roachpb.Key("b"), Timestamp: ts1},
+	}, ts3))
+	kvs, err := Scan(ctx, engine, localMax, keyMax, 0)
+	require.NoError(t, err)
+	require.Len(t, kvs, 1)
+	require.Equal(t, mvccVersionKey(roachpb.Key("b"), ts2), kvs[0].Key)
+
+	// Verify aggregated stats match computed stats after GC.
+	expMS, err = ComputeStats(ctx, engine, localMax, keyMax, ts3.WallTime)
+	require.NoError(t, err)
+	assertEq(t, engine, "verification", ms, &expMS)
+
+	// Compact the engine; the ForTesting() config option will assert that all
+	// DELSIZED tombstones were appropriately sized.
+	require.NoError(t, engine.Compact(ctx))
+}
+
 // TestMVCCGarbageCollectIntent verifies that an intent cannot be GC'd.
 func TestMVCCGarbageCollectIntent(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -79,6 +79,13 @@ func (v MVCCValue) IsTombstone() bool {
 	return len(v.Value.RawBytes) == 0
 }
 
+// IsExpiredAt returns whether the MVCCValue has an expiration at or below the
+// given timestamp, in which case readers at that timestamp treat it as a
+// deletion tombstone.
+func (v MVCCValue) IsExpiredAt(ts hlc.Timestamp) bool {
+	return v.Expiration.IsSet() && v.Expiration.LessEq(ts)
+}
+
 // LocalTimestampNeeded returns whether the MVCCValue's local timestamp is
 // needed, or whether it can be implied by (i.e. set to the same value as)
 // its key's version timestamp.
@@ -130,6 +137,9 @@ func (v MVCCValue) SafeFormat(w redact.SafePrinter, _ rune) {
 		if v.OriginTimestamp.IsSet() {
 			fields = append(fields, fmt.Sprintf("originTs=%s", v.OriginTimestamp))
 		}
+		if v.Expiration.IsSet() {
+			fields = append(fields, fmt.Sprintf("expiration=%s", v.Expiration))
+		}
 		w.Print(strings.Join(fields, ", "))
 		w.Printf("}")
 	}
@@ -416,6 +426,30 @@ func EncodedMVCCValueIsTombstone(buf []byte) (bool, error) {
 	return len(buf) == int(headerSize), nil
 }
 
+// EncodedMVCCValueIsExpiredAt is equivalent to decoding a MVCCValue and then
+// calling MVCCValue.IsExpiredAt. Only values using the extended encoding can
+// carry an expiration, so the header is only decoded for those.
+func EncodedMVCCValueIsExpiredAt(buf []byte, ts hlc.Timestamp) (bool, error) {
+	expiration, err := EncodedMVCCValueExpiration(buf)
+	if err != nil {
+		return false, err
+	}
+	return expiration.IsSet() && expiration.LessEq(ts), nil
+}
+
+// EncodedMVCCValueExpiration returns the Expiration of the MVCCValueHeader of
+// an encoded MVCCValue, or an empty timestamp if the value does not expire.
+func EncodedMVCCValueExpiration(buf []byte) (hlc.Timestamp, error) {
+	if len(buf) <= tagPos || buf[tagPos] != extendedEncodingSentinel {
+		return hlc.Timestamp{}, nil
+	}
+	v, err := decodeExtendedMVCCValue(buf, true)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	return v.Expiration, nil
+}
+
 func init() {
 	// Inject the format dependency into the enginepb package.
 	enginepb.FormatBytesAsValue = func(v []byte) redact.RedactableString {
@@ -134,6 +134,8 @@ func TestMVCCValueFormat(t *testing.T) {
 
 	valHeaderWithOriginTsOnly := enginepb.MVCCValueHeader{OriginTimestamp: originTs}
 
+	valHeaderWithExpirationOnly := enginepb.MVCCValueHeader{Expiration: hlc.Timestamp{WallTime: 2}}
+
 	testcases := map[string]struct {
 		val    MVCCValue
 		expect string
@@ -156,6 +158,7 @@ func TestMVCCValueFormat(t *testing.T) {
 		"origints+tombstone":   {val: MVCCValue{MVCCValueHeader: valHeaderWithOriginTsOnly}, expect: "{originTs=0.000000001,1}/<empty>"},
 		"origints+bytes":       {val: MVCCValue{MVCCValueHeader: valHeaderWithOriginTsOnly, Value: strVal}, expect: "{originTs=0.000000001,1}/BYTES/foo"},
 		"origints+int":         {val: MVCCValue{MVCCValueHeader: valHeaderWithOriginTsOnly, Value: intVal}, expect: "{originTs=0.000000001,1}/INT/17"},
+		"expiration+bytes":     {val: MVCCValue{MVCCValueHeader: valHeaderWithExpirationOnly, Value: strVal}, expect: "{expiration=0.000000002,0}/BYTES/foo"},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -257,6 +260,36 @@ func TestEncodeDecodeMVCCValue(t *testing.T) {
 	}
 }
 
+func TestEncodedMVCCValueIsExpiredAt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var strVal roachpb.Value
+	strVal.SetString("foo")
+	expiration := hlc.Timestamp{WallTime: 10}
+
+	for _, tc := range []struct {
+		val     MVCCValue
+		ts      hlc.Timestamp
+		expired bool
+	}{
+		{val: MVCCValue{Value: strVal}, ts: expiration, expired: false},
+		{val: MVCCValue{MVCCValueHeader: enginepb.MVCCValueHeader{ImportEpoch: 1}, Value: strVal}, ts: expiration, expired: false},
+		{val: MVCCValue{MVCCValueHeader: enginepb.MVCCValueHeader{Expiration: expiration}, Value: strVal}, ts: expiration.Prev(), expired: false},
+		{val: MVCCValue{MVCCValueHeader: enginepb.MVCCValueHeader{Expiration: expiration}, Value: strVal}, ts: expiration, expired: true},
+		{val: MVCCValue{MVCCValueHeader: enginepb.MVCCValueHeader{Expiration: expiration}, Value: strVal}, ts: expiration.Next(), expired: true},
+	} {
+		enc, err := EncodeMVCCValue(tc.val)
+		require.NoError(t, err)
+		expired, err := EncodedMVCCValueIsExpiredAt(enc, tc.ts)
+		require.NoError(t, err)
+		require.Equal(t, tc.expired, expired, "%s at %s", tc.val, tc.ts)
+		require.Equal(t, tc.expired, tc.val.IsExpiredAt(tc.ts))
+		exp, err := EncodedMVCCValueExpiration(enc)
+		require.NoError(t, err)
+		require.Equal(t, tc.val.Expiration, exp)
+	}
+}
+
 func TestDecodeMVCCValueErrors(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -182,6 +182,18 @@ func SharedStorage(sharedStorage cloud.ExternalStorage) ConfigOption {
 	}
 }
 
+// ColdStorage enables placing the bottom-level sstables of cold spans on the
+// given storage. See ColdStorageTierer.
+func ColdStorage(coldStorage cloud.ExternalStorage) ConfigOption {
+	return func(cfg *engineConfig) error {
+		cfg.coldStorage = coldStorage
+		if cfg.coldStorage != nil && cfg.opts.FormatMajorVersion < pebble.FormatMinForSharedObjects {
+			cfg.opts.FormatMajorVersion = pebble.FormatMinForSharedObjects
+		}
+		return nil
+	}
+}
+
 // SecondaryCache enables use of a secondary cache to store shared objects.
 func SecondaryCache(size int64) ConfigOption {
 	return func(cfg *engineConfig) error {
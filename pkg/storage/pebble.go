@@ -574,6 +574,9 @@ type engineConfig struct {
 	// sharedStorage is a cloud.ExternalStorage that can be used by all Pebble
 	// stores on this node and on other nodes to store sstables.
 	sharedStorage cloud.ExternalStorage
+	// coldStorage is a cloud.ExternalStorage used by this store for the
+	// bottom-level sstables of cold spans. See tieredStorage.
+	coldStorage cloud.ExternalStorage
 
 	// beforeClose is a slice of functions to be invoked before the engine is closed.
 	beforeClose []func(*Pebble)
@@ -621,6 +624,8 @@ type Pebble struct {
 	singleDelIneffectualCount        int64
 	sharedBytesRead                  int64
 	sharedBytesWritten               int64
+	coldStorageBytes                 int64
+	coldStorageReadRetries           int64
 	iterStats                        struct {
 		syncutil.Mutex
 		AggregatedIteratorStats
@@ -650,6 +655,9 @@ type Pebble struct {
 	lowDiskSpaceFunc atomic.Pointer[func(pebble.LowDiskSpaceInfo)]
 
 	singleDelLogEvery log.EveryN
+
+	// tieredStorage is set if the engine was opened with cold storage.
+	tieredStorage *tieredStorage
 }
 
 // WorkloadCollector implements an workloadCollectorGetter and returns the
@@ -829,7 +837,8 @@ func newPebble(ctx context.Context, cfg engineConfig) (p *Pebble, err error) {
 		// TODO(bilal): Remove the first part of this || statement when
 		// https://github.com/cockroachdb/pebble/issues/2676 is completed, or when
 		// Pebble has better guards against this.
-		return cfg.sharedStorage != nil || !IngestAsFlushable.Get(&cfg.settings.SV)
+		return cfg.sharedStorage != nil || cfg.coldStorage != nil ||
+			!IngestAsFlushable.Get(&cfg.settings.SV)
 	}
 	cfg.opts.Experimental.IngestSplit = func() bool {
 		return IngestSplitEnabled.Get(&cfg.settings.SV)
@@ -966,6 +975,19 @@ func newPebble(ctx context.Context, cfg engineConfig) (p *Pebble, err error) {
 		if err := ConfigureForSharedStorage(cfg.opts, esWrapper); err != nil {
 			return nil, errors.Wrap(err, "error when configuring shared storage")
 		}
+	} else if cfg.coldStorage != nil {
+		if ConfigureForSharedStorage == nil {
+			return nil, errors.New("cold storage requires CCL features")
+		}
+		t, err := newTieredStorage(logCtx, p, cfg.opts.FS,
+			cfg.opts.FS.PathJoin(auxDir, tieredStorageDir), cfg.coldStorage, cfg.env.IsReadOnly())
+		if err != nil {
+			return nil, err
+		}
+		if err := ConfigureForSharedStorage(cfg.opts, t); err != nil {
+			return nil, errors.Wrap(err, "error when configuring cold storage")
+		}
+		p.tieredStorage = t
 	} else {
 		if cfg.remoteStorageFactory != nil {
 			cfg.opts.Experimental.RemoteStorage = remoteStorageAdaptor{p: p, ctx: logCtx, factory: cfg.remoteStorageFactory}
@@ -1799,6 +1821,8 @@ func (p *Pebble) GetMetrics() Metrics {
 		SingleDelIneffectualCount:        atomic.LoadInt64(&p.singleDelIneffectualCount),
 		SharedStorageReadBytes:           atomic.LoadInt64(&p.sharedBytesRead),
 		SharedStorageWriteBytes:          atomic.LoadInt64(&p.sharedBytesWritten),
+		ColdStorageBytes:                 atomic.LoadInt64(&p.coldStorageBytes),
+		ColdStorageReadRetries:           atomic.LoadInt64(&p.coldStorageReadRetries),
 	}
 	if sema := p.cfg.opts.LoadBlockSema; sema != nil {
 		semaStats := sema.Stats()
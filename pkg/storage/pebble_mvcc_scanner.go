@@ -1250,9 +1250,9 @@ func IncludeStartKeyIntoErr(startKey roachpb.Key, err error) error {
 }
 
 // Adds the specified key and value to the result set, excluding
-// tombstones unless p.tombstones is true. If p.rawMVCCValues is true,
-// then the mvccRawBytes argument will be added to the results set
-// instead.
+// tombstones and expired values unless p.tombstones is true. If
+// p.rawMVCCValues is true, then the mvccRawBytes argument will be added
+// to the results set instead.
 //
 //   - ok indicates whether the iteration should continue. This can be false
 //     because we hit an error or reached some limit.
@@ -1266,6 +1266,19 @@ func (p *pebbleMVCCScanner) add(
 	if len(rawValue) == 0 && !p.tombstones {
 		return true /* ok */, false
 	}
+	// Values that expired at or below the read timestamp are treated as
+	// deletion tombstones.
+	if len(rawValue) != 0 {
+		if expired, err := EncodedMVCCValueIsExpiredAt(mvccRawBytes, p.ts); err != nil {
+			p.err = err
+			return false, false
+		} else if expired {
+			if !p.tombstones {
+				return true /* ok */, false
+			}
+			rawValue = nil
+		}
+	}
 	if p.rawMVCCValues {
 		rawValue = mvccRawBytes
 	}
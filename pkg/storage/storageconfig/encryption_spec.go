@@ -8,6 +8,7 @@ package storageconfig
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
@@ -21,8 +22,11 @@ import (
 type EncryptionOptions struct {
 	// The store key source. Defines which fields are useful.
 	KeySource EncryptionKeySource
-	// Set if key_source == KeyFiles.
+	// Set if key_source == KeyFiles or KMS.
 	KeyFiles *EncryptionKeyFiles
+	// Set if key_source == KMS. The URI of the KMS with which the contents of
+	// the key files are wrapped.
+	KMSURI string
 	// Default data key rotation in seconds.
 	DataKeyRotationPeriod int64
 }
@@ -38,6 +42,10 @@ type EncryptionKeySource int32
 
 const (
 	EncryptionKeyFromFiles EncryptionKeySource = 0
+	// EncryptionKeyFromKMS is used when the key files contain keys wrapped by an
+	// external KMS, which must be reachable to unwrap them when the store is
+	// opened.
+	EncryptionKeyFromKMS EncryptionKeySource = 1
 )
 
 // DefaultRotationPeriod is the rotation period used if not specified.
@@ -53,12 +61,39 @@ type StoreEncryptionSpec struct {
 	Path    string
 }
 
-// String returns a fully parsable version of the encryption spec.
+// String returns a fully parsable version of the encryption spec. The
+// credentials in the KMS URI are redacted.
 func (es StoreEncryptionSpec) String() string {
-	// All fields are set.
-	return fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
+	// All fields are set, except possibly the KMS.
+	s := fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
 		es.Path, es.Options.KeyFiles.CurrentKey, es.Options.KeyFiles.OldKey, es.RotationPeriod(),
 	)
+	if es.Options.KeySource == EncryptionKeyFromKMS {
+		s += ",kms=" + redactKMSURI(es.Options.KMSURI)
+	}
+	return s
+}
+
+// redactKMSURI returns the KMS URI with the values of its query parameters and
+// its password, which may hold credentials, redacted. The parameters are kept
+// so that it remains clear which options were specified.
+func redactKMSURI(kmsURI string) string {
+	const redactionMarker = "redacted"
+	uri, err := url.Parse(kmsURI)
+	if err != nil {
+		return redactionMarker
+	}
+	if uri.User != nil {
+		if _, ok := uri.User.Password(); ok {
+			uri.User = url.UserPassword(uri.User.Username(), redactionMarker)
+		}
+	}
+	params := uri.Query()
+	for param := range params {
+		params.Set(param, redactionMarker)
+	}
+	uri.RawQuery = params.Encode()
+	return uri.String()
 }
 
 // RotationPeriod returns the rotation period as a duration.
@@ -145,6 +180,9 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 				return StoreEncryptionSpec{}, errors.Wrapf(err, "could not parse rotation-duration value: %s", value)
 			}
 			es.Options.DataKeyRotationPeriod = int64(dur / time.Second)
+		case "kms":
+			es.Options.KeySource = EncryptionKeyFromKMS
+			es.Options.KMSURI = value
 		default:
 			return StoreEncryptionSpec{}, fmt.Errorf("%s is not a valid enterprise-encryption field", field)
 		}
@@ -41,6 +41,7 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 		// The same logic applies to key and old-key, don't repeat everything.
 		{"path=data", "no key specified", StoreEncryptionSpec{}},
 		{"path=data,key=new.key", "no old-key specified", StoreEncryptionSpec{}},
+		{"path=data,key=new.key,old-key=old.key,kms=", "no value specified for kms", StoreEncryptionSpec{}},
 
 		// Rotation period.
 		{"path=data,key=new.key,old-key=old.key,rotation-period", "field not in the form <key>=<value>: rotation-period", StoreEncryptionSpec{}},
@@ -87,6 +88,18 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 			},
 		},
 
+		{
+			"path=/data,key=/new.key,old-key=plain,kms=kmip://kms.example.com/store-key?KMIP_USERNAME=u", "",
+			StoreEncryptionSpec{Path: "/data",
+				Options: EncryptionOptions{
+					KeySource:             EncryptionKeyFromKMS,
+					KeyFiles:              &EncryptionKeyFiles{CurrentKey: "/new.key", OldKey: "plain"},
+					KMSURI:                "kmip://kms.example.com/store-key?KMIP_USERNAME=u",
+					DataKeyRotationPeriod: int64(DefaultRotationPeriod / time.Second),
+				},
+			},
+		},
+
 		// One relative path to test absolutization.
 		{
 			"path=data,key=/new.key,old-key=/old.key", "",
@@ -145,3 +158,23 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 		}
 	}
 }
+
+// TestStoreEncryptionSpecStringRedactsKMSCredentials verifies that the
+// credentials in the KMS URI of a spec are not included in its string form.
+func TestStoreEncryptionSpecStringRedactsKMSCredentials(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	spec, err := NewStoreEncryptionSpec(
+		"path=/data,key=/new.key,old-key=plain,kms=kmip://kms.example.com/store-key" +
+			"?KMIP_USERNAME=u&KMIP_PASSWORD=secret-password&KMIP_CLIENT_KEY=secret-key",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = "path=/data,key=/new.key,old-key=plain,rotation-period=168h0m0s," +
+		"kms=kmip://kms.example.com/store-key" +
+		"?KMIP_CLIENT_KEY=redacted&KMIP_PASSWORD=redacted&KMIP_USERNAME=redacted"
+	if s := spec.String(); s != expected {
+		t.Errorf("expected %q, got %q", expected, s)
+	}
+}
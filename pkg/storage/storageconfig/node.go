@@ -13,6 +13,9 @@ type Node struct {
 	// SharedStorage is specified to enable disaggregated shared storage. It is
 	// enabled if the uri is set.
 	SharedStorage SharedStorage
+	// ColdStorage is specified to place the data of cold spans on slower
+	// storage. It is enabled if the uri is set.
+	ColdStorage ColdStorage
 }
 
 // SharedStorage specifies the properties of the shared storage.
@@ -23,3 +26,12 @@ type SharedStorage struct {
 	// disaggregated shared storage.
 	Cache Size
 }
+
+// ColdStorage specifies the properties of the storage used for the
+// bottom-level SSTables of spans with the cold storage tier. Each store moves
+// such SSTables to this storage, while the SSTables of other spans stay on
+// local disk. The secondary cache size of SharedStorage also applies to it.
+type ColdStorage struct {
+	// URI is the base location to read and write cold storage files.
+	URI string
+}
@@ -0,0 +1,529 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package storage
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+// tieredStorageDir is the directory, relative to the auxiliary directory of
+// the store, holding the objects of a tieredStorage that are kept on local
+// disk.
+const tieredStorageDir = "tiered-storage"
+
+// tieredStorageTmpSuffix is the suffix of the local files that objects are
+// downloaded to before they are renamed into place.
+const tieredStorageTmpSuffix = ".tmp"
+
+// coldStorageRetryOptions are the options used to retry reads from the cold
+// storage, which may be temporarily unavailable.
+var coldStorageRetryOptions = retry.Options{
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	MaxRetries:     6,
+}
+
+// tieredStorage implements remote.Storage for stores configured with cold
+// storage. Pebble treats the bottom-level SSTables of such stores as remote
+// objects, and tieredStorage keeps each of them either in a local directory
+// or on the cold storage. Objects are always created locally, so flushes and
+// compactions never depend on the availability of the cold storage;
+// Pebble.TierColdStorage moves the objects between the two according to the
+// span configs of their keys.
+//
+// A copy of an object on the cold storage is only removed when Pebble deletes
+// the object, so an object that is moved back to local disk can still be read
+// by readers that opened it while it was on the cold storage.
+type tieredStorage struct {
+	p    *Pebble
+	fs   vfs.FS
+	dir  string
+	cold cloud.ExternalStorage
+	ctx  context.Context
+
+	// mu serializes the last step of moving an object with deletions of
+	// objects, so that an object deleted while it is being moved does not
+	// leave a copy behind.
+	mu syncutil.Mutex
+}
+
+var _ remote.Storage = &tieredStorage{}
+
+func newTieredStorage(
+	ctx context.Context,
+	p *Pebble,
+	fs vfs.FS,
+	dir string,
+	cold cloud.ExternalStorage,
+	readOnly bool,
+) (*tieredStorage, error) {
+	t := &tieredStorage{p: p, fs: fs, dir: dir, cold: cold, ctx: ctx}
+	if readOnly {
+		return t, nil
+	}
+	if err := fs.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	// Remove the leftovers of downloads interrupted by a crash.
+	names, err := fs.List(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if strings.HasSuffix(name, tieredStorageTmpSuffix) {
+			if err := fs.Remove(fs.PathJoin(dir, name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+func (t *tieredStorage) localPath(objName string) string {
+	return t.fs.PathJoin(t.dir, objName)
+}
+
+// retryCold runs fn, which accesses the cold storage, until it succeeds, it
+// fails because the object does not exist, or the retries are exhausted.
+func (t *tieredStorage) retryCold(ctx context.Context, fn func() error) error {
+	var err error
+	for r := retry.StartWithCtx(ctx, coldStorageRetryOptions); r.Next(); {
+		if r.CurrentAttempt() > 0 {
+			atomic.AddInt64(&t.p.coldStorageReadRetries, 1)
+		}
+		if err = fn(); err == nil || t.IsNotExistError(err) {
+			return err
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return errors.Wrap(err, "reading from cold storage")
+}
+
+// Close implements the remote.Storage interface.
+func (t *tieredStorage) Close() error {
+	return t.cold.Close()
+}
+
+// ReadObject implements the remote.Storage interface.
+func (t *tieredStorage) ReadObject(
+	ctx context.Context, objName string,
+) (_ remote.ObjectReader, objSize int64, _ error) {
+	f, err := t.fs.Open(t.localPath(objName))
+	if err == nil {
+		stat, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, 0, err
+		}
+		return &localObjectReader{f: f}, stat.Size(), nil
+	}
+	if !oserror.IsNotExist(err) {
+		return nil, 0, err
+	}
+	// The object is only removed from local disk once it has been written to
+	// the cold storage.
+	if err := t.retryCold(ctx, func() error {
+		objSize, err = t.cold.Size(ctx, objName)
+		return err
+	}); err != nil {
+		return nil, 0, err
+	}
+	return &coldObjectReader{
+		t: t,
+		r: externalStorageReader{p: t.p, es: t.cold, objName: objName},
+	}, objSize, nil
+}
+
+// CreateObject implements the remote.Storage interface.
+func (t *tieredStorage) CreateObject(objName string) (io.WriteCloser, error) {
+	f, err := t.fs.Create(t.localPath(objName), vfs.WriteCategoryUnspecified)
+	if err != nil {
+		return nil, err
+	}
+	return &localObjectWriter{File: f}, nil
+}
+
+// List implements the remote.Storage interface.
+func (t *tieredStorage) List(prefix, delimiter string) ([]string, error) {
+	names := make(map[string]struct{})
+	add := func(name string) {
+		if delimiter != "" {
+			if i := strings.Index(name, delimiter); i >= 0 {
+				name = name[:i+len(delimiter)]
+			}
+		}
+		names[name] = struct{}{}
+	}
+	local, err := t.fs.List(t.dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range local {
+		if strings.HasPrefix(name, prefix) && !strings.HasSuffix(name, tieredStorageTmpSuffix) {
+			add(strings.TrimPrefix(name, prefix))
+		}
+	}
+	if err := t.retryCold(t.ctx, func() error {
+		return t.cold.List(t.ctx, prefix, delimiter, func(name string) error {
+			add(name)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(names))
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// Delete implements the remote.Storage interface.
+func (t *tieredStorage) Delete(objName string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.fs.Remove(t.localPath(objName)); err != nil && !oserror.IsNotExist(err) {
+		return err
+	}
+	if _, _, ok := parseTableObjectName(objName); !ok {
+		// Only tables are moved to the cold storage.
+		return nil
+	}
+	if err := t.cold.Delete(t.ctx, objName); err != nil && !t.IsNotExistError(err) {
+		return err
+	}
+	return nil
+}
+
+// Size implements the remote.Storage interface.
+func (t *tieredStorage) Size(objName string) (int64, error) {
+	stat, err := t.fs.Stat(t.localPath(objName))
+	if err == nil {
+		return stat.Size(), nil
+	}
+	if !oserror.IsNotExist(err) {
+		return 0, err
+	}
+	var size int64
+	err = t.retryCold(t.ctx, func() error {
+		size, err = t.cold.Size(t.ctx, objName)
+		return err
+	})
+	return size, err
+}
+
+// IsNotExistError implements the remote.Storage interface.
+func (t *tieredStorage) IsNotExistError(err error) bool {
+	return oserror.IsNotExist(err) || errors.Is(err, cloud.ErrFileDoesNotExist)
+}
+
+// listTables returns the names of the tables created by the given creator,
+// keyed by their file number, that are on local disk and on the cold storage.
+func (t *tieredStorage) listTables(
+	ctx context.Context, creatorID uint64,
+) (local, cold map[uint64]string, _ error) {
+	local = make(map[uint64]string)
+	names, err := t.fs.List(t.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, name := range names {
+		if creator, fileNum, ok := parseTableObjectName(name); ok && creator == creatorID {
+			local[fileNum] = name
+		}
+	}
+	cold = make(map[uint64]string)
+	if err := t.cold.List(ctx, "", "", func(name string) error {
+		if creator, fileNum, ok := parseTableObjectName(name); ok && creator == creatorID {
+			cold[fileNum] = name
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	return local, cold, nil
+}
+
+// moveToCold writes a local object to the cold storage, unless it is already
+// there, and then removes it from local disk.
+func (t *tieredStorage) moveToCold(ctx context.Context, objName string, onCold bool) error {
+	path := t.localPath(objName)
+	if !onCold {
+		f, err := t.fs.Open(path)
+		if err != nil {
+			return err
+		}
+		err = cloud.WriteFile(ctx, t.cold, objName, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		if stat, err := t.cold.Size(ctx, objName); err == nil {
+			atomic.AddInt64(&t.p.sharedBytesWritten, stat)
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.fs.Remove(path); err != nil {
+		if !oserror.IsNotExist(err) {
+			return err
+		}
+		// The object was deleted while it was being written to the cold storage.
+		return t.cold.Delete(ctx, objName)
+	}
+	return nil
+}
+
+// moveToLocal copies an object from the cold storage to local disk. The copy
+// on the cold storage is kept until the object is deleted, see tieredStorage.
+func (t *tieredStorage) moveToLocal(ctx context.Context, objName string) error {
+	tmpPath := t.localPath(objName + tieredStorageTmpSuffix)
+	if err := func() error {
+		r, _, err := t.cold.ReadFile(ctx, objName, cloud.ReadOptions{NoFileSize: true})
+		if err != nil {
+			return err
+		}
+		defer r.Close(ctx)
+		f, err := t.fs.Create(tmpPath, vfs.WriteCategoryUnspecified)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(f, ioctx.ReaderCtxAdapter(ctx, r))
+		atomic.AddInt64(&t.p.sharedBytesRead, n)
+		if err == nil {
+			err = f.Sync()
+		}
+		return errors.CombineErrors(err, f.Close())
+	}(); err != nil {
+		_ = t.fs.Remove(tmpPath)
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.cold.Size(ctx, objName); err != nil {
+		// The object may have been deleted while it was being copied.
+		_ = t.fs.Remove(tmpPath)
+		if t.IsNotExistError(err) {
+			return nil
+		}
+		return err
+	}
+	return t.fs.Rename(tmpPath, t.localPath(objName))
+}
+
+// parseTableObjectName parses the name Pebble gives to remote table objects,
+// "<hash>-<creator ID>-<file number>.sst".
+func parseTableObjectName(name string) (creatorID, fileNum uint64, ok bool) {
+	base, ok := strings.CutSuffix(name, ".sst")
+	if !ok {
+		return 0, 0, false
+	}
+	parts := strings.Split(base, "-")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+	creatorID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	fileNum, err = strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return creatorID, fileNum, true
+}
+
+// tableSpan returns the span of the keys of a table with the given bounds, or
+// false if the table contains keys outside of the global keyspace, which have
+// no span config.
+func tableSpan(smallest, largest []byte) (roachpb.Span, bool) {
+	start, ok := DecodeEngineKey(smallest)
+	if !ok || start.Key.Compare(keys.LocalMax) < 0 {
+		return roachpb.Span{}, false
+	}
+	end, ok := DecodeEngineKey(largest)
+	if !ok {
+		return roachpb.Span{}, false
+	}
+	return roachpb.Span{Key: start.Key, EndKey: end.Key.Next()}, true
+}
+
+// localObjectReader implements remote.ObjectReader for objects of a
+// tieredStorage that are on local disk.
+type localObjectReader struct {
+	f vfs.File
+}
+
+var _ remote.ObjectReader = (*localObjectReader)(nil)
+
+// ReadAt implements the remote.ObjectReader interface.
+func (r *localObjectReader) ReadAt(_ context.Context, p []byte, offset int64) error {
+	n, err := r.f.ReadAt(p, offset)
+	// As for the externalStorageReader, io.EOF is allowed if p was filled.
+	if n == len(p) {
+		return nil
+	}
+	if err == nil || errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Close implements the remote.ObjectReader interface.
+func (r *localObjectReader) Close() error {
+	return r.f.Close()
+}
+
+// coldObjectReader implements remote.ObjectReader for objects of a
+// tieredStorage that are on the cold storage. Reads are retried, so that a
+// temporarily unavailable cold storage slows down reads of cold data instead
+// of failing them.
+type coldObjectReader struct {
+	t *tieredStorage
+	r externalStorageReader
+}
+
+var _ remote.ObjectReader = (*coldObjectReader)(nil)
+
+// ReadAt implements the remote.ObjectReader interface.
+func (r *coldObjectReader) ReadAt(ctx context.Context, p []byte, offset int64) error {
+	return r.t.retryCold(ctx, func() error {
+		return r.r.ReadAt(ctx, p, offset)
+	})
+}
+
+// Close implements the remote.ObjectReader interface.
+func (r *coldObjectReader) Close() error {
+	return r.r.Close()
+}
+
+// localObjectWriter syncs objects created on local disk before closing them.
+type localObjectWriter struct {
+	vfs.File
+}
+
+// Close implements the io.Closer interface.
+func (w *localObjectWriter) Close() error {
+	return errors.CombineErrors(w.File.Sync(), w.File.Close())
+}
+
+// ColdStorageTierer is implemented by engines that can place the SSTables of
+// cold spans on cold storage.
+type ColdStorageTierer interface {
+	// TierColdStorage moves the bottom-level SSTables whose keys all belong to
+	// spans for which isCold returns true to the cold storage, and the other
+	// SSTables back to local disk. It is a no-op if the engine was not opened
+	// with cold storage.
+	TierColdStorage(ctx context.Context, isCold func(context.Context, roachpb.Span) (bool, error)) error
+}
+
+var _ ColdStorageTierer = (*Pebble)(nil)
+
+// TierColdStorage implements the ColdStorageTierer interface.
+func (p *Pebble) TierColdStorage(
+	ctx context.Context, isCold func(context.Context, roachpb.Span) (bool, error),
+) error {
+	t := p.tieredStorage
+	if t == nil {
+		return nil
+	}
+	creatorID := p.storeIDPebbleLog.Get()
+	if creatorID <= 0 {
+		// No objects are created before the store ID is known.
+		return nil
+	}
+	local, cold, err := t.listTables(ctx, uint64(creatorID))
+	if err != nil {
+		return err
+	}
+	sstInfos, err := p.db.SSTables()
+	if err != nil {
+		return err
+	}
+	// A backing table of virtual tables is only cold if all the tables backed
+	// by it are.
+	type backing struct {
+		size uint64
+		cold bool
+	}
+	backings := make(map[uint64]*backing)
+	for _, ssts := range sstInfos {
+		for _, sst := range ssts {
+			fileNum := uint64(sst.FileNum)
+			if sst.Virtual {
+				fileNum = uint64(sst.BackingSSTNum)
+			}
+			_, isLocal := local[fileNum]
+			_, isOnCold := cold[fileNum]
+			if !isLocal && !isOnCold {
+				// Not an object of the tiered storage.
+				continue
+			}
+			b, ok := backings[fileNum]
+			if !ok {
+				b = &backing{cold: true}
+				backings[fileNum] = b
+			}
+			b.size += sst.Size
+			if !b.cold {
+				continue
+			}
+			span, ok := tableSpan(sst.Smallest.UserKey, sst.Largest.UserKey)
+			if !ok {
+				b.cold = false
+				continue
+			}
+			if b.cold, err = isCold(ctx, span); err != nil {
+				return err
+			}
+		}
+	}
+
+	var coldBytes int64
+	for fileNum, b := range backings {
+		localName, isLocal := local[fileNum]
+		coldName, isOnCold := cold[fileNum]
+		switch {
+		case b.cold && isLocal:
+			if err := t.moveToCold(ctx, localName, isOnCold); err != nil {
+				p.logger.Infof("moving %s to cold storage: %v", localName, err)
+				continue
+			}
+		case !b.cold && !isLocal:
+			if err := t.moveToLocal(ctx, coldName); err != nil {
+				p.logger.Infof("moving %s from cold storage: %v", coldName, err)
+				// Still on the cold storage.
+				coldBytes += int64(b.size)
+			}
+		}
+		if b.cold {
+			coldBytes += int64(b.size)
+		}
+	}
+	atomic.StoreInt64(&p.coldStorageBytes, coldBytes)
+	return nil
+}
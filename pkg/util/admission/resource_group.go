@@ -0,0 +1,299 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package admission
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/errors"
+)
+
+// DefaultResourceGroup is the resource group used for work that is not tagged
+// with a resource group, or is tagged with a resource group that is not
+// defined. Its shares can be overridden by defining a group with this name.
+const DefaultResourceGroup = "default"
+
+// Bounds on the values in a resource group definition.
+const (
+	maxResourceGroupWeight = 1000
+	maxResourceGroupBurst  = 1 << 40
+)
+
+// ResourceGroupsSetting defines the workload resource groups that share the
+// CPU and IO admission tokens of a tenant. The value is a semicolon separated
+// list of group definitions of the form:
+//
+//	name: cpu_weight=4, io_weight=2, cpu_burst=16, io_burst=1048576, applications=app1|app2
+//
+// All attributes are optional. Weights default to 1 and determine the share of
+// admission grants a group gets when multiple groups are contending within the
+// same tenant and priority. Bursts default to 0 and are an amount of work (in
+// units of the queue, i.e. requests for the CPU queues and bytes for the IO
+// queues) that a group which was idle can be admitted ahead of its share.
+// Sessions with an application_name listed in applications are assigned to the
+// group unless the resource_group session variable says otherwise. Application
+// names cannot contain the separators of the definition (;,|:) and surrounding
+// whitespace is ignored.
+//
+// Each virtual cluster defines its own groups. The SQL layer resolves the
+// group of a transaction and sends its definition along with the KV requests
+// in the AdmissionHeader, so that KV nodes share the grants of the tenant
+// according to the tenant's definition.
+var ResourceGroupsSetting = settings.RegisterStringSetting(
+	settings.ApplicationLevel,
+	"admission.resource_groups",
+	"semicolon separated list of workload resource groups used to share admission control CPU and IO "+
+		"tokens between workloads of the same tenant, each of the form "+
+		"name: cpu_weight=N, io_weight=N, cpu_burst=N, io_burst=N, applications=app1|app2",
+	"",
+	settings.WithValidateString(func(_ *settings.Values, s string) error {
+		_, err := ParseResourceGroups(s)
+		return err
+	}),
+	settings.WithPublic)
+
+// ResourceGroup is the definition of a workload resource group. See
+// ResourceGroupsSetting.
+type ResourceGroup struct {
+	Name string
+	// CPUWeight and IOWeight are the relative shares of the group in the CPU and
+	// IO (store) work queues respectively. Must be > 0.
+	CPUWeight uint32
+	IOWeight  uint32
+	// CPUBurst and IOBurst are the amount of work a group that was idle can be
+	// admitted ahead of its share.
+	CPUBurst int64
+	IOBurst  int64
+	// ApplicationNames are the application_name values of sessions assigned to
+	// this group by default.
+	ApplicationNames []string
+}
+
+// NewResourceGroup returns the definition of a group with the given name and
+// default attributes.
+func NewResourceGroup(name string) ResourceGroup {
+	return ResourceGroup{Name: name, CPUWeight: 1, IOWeight: 1}
+}
+
+// weightAndBurst returns the weight and burst of the group for an IO queue if
+// io is true, else for a CPU queue.
+func (g ResourceGroup) weightAndBurst(io bool) (weight uint32, burst int64) {
+	if io {
+		return g.IOWeight, g.IOBurst
+	}
+	return g.CPUWeight, g.CPUBurst
+}
+
+// resourceGroupSeparators are the characters that separate the groups,
+// attributes and application names in a ResourceGroupsSetting value.
+const resourceGroupSeparators = ";,|:"
+
+var resourceGroupNameRE = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// ValidateResourceGroupName returns an error if the name is not a valid
+// resource group name. Names consist of lowercase letters, digits and
+// underscores, and do not start with a digit.
+func ValidateResourceGroupName(name string) error {
+	if !resourceGroupNameRE.MatchString(name) {
+		return errors.Newf("invalid resource group name %q", name)
+	}
+	return nil
+}
+
+// ParseResourceGroups parses the value of ResourceGroupsSetting. The returned
+// map is keyed by group name.
+func ParseResourceGroups(spec string) (map[string]ResourceGroup, error) {
+	groups := make(map[string]ResourceGroup)
+	apps := make(map[string]string)
+	for _, def := range strings.Split(spec, ";") {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		name, attrs, _ := strings.Cut(def, ":")
+		name = strings.TrimSpace(name)
+		if err := ValidateResourceGroupName(name); err != nil {
+			return nil, err
+		}
+		if _, ok := groups[name]; ok {
+			return nil, errors.Newf("resource group %q defined more than once", name)
+		}
+		g := NewResourceGroup(name)
+		for _, attr := range strings.Split(attrs, ",") {
+			attr = strings.TrimSpace(attr)
+			if attr == "" {
+				continue
+			}
+			key, val, ok := strings.Cut(attr, "=")
+			if !ok {
+				return nil, errors.Newf("resource group %q: expected key=value, found %q", name, attr)
+			}
+			if err := g.SetAttribute(strings.TrimSpace(key), strings.TrimSpace(val)); err != nil {
+				return nil, errors.Wrapf(err, "resource group %q", name)
+			}
+		}
+		for _, app := range g.ApplicationNames {
+			if other, ok := apps[app]; ok {
+				return nil, errors.Newf(
+					"application %q assigned to both resource groups %q and %q", app, other, name)
+			}
+			apps[app] = name
+		}
+		groups[name] = g
+	}
+	return groups, nil
+}
+
+// SetAttribute sets one attribute of the group definition, using the
+// attribute names and value syntax of ResourceGroupsSetting. Setting
+// applications replaces the list of application names.
+func (g *ResourceGroup) SetAttribute(key, val string) error {
+	var err error
+	switch key {
+	case "cpu_weight":
+		g.CPUWeight, err = parseResourceGroupWeight(val)
+	case "io_weight":
+		g.IOWeight, err = parseResourceGroupWeight(val)
+	case "cpu_burst":
+		g.CPUBurst, err = parseResourceGroupBurst(val)
+	case "io_burst":
+		g.IOBurst, err = parseResourceGroupBurst(val)
+	case "applications":
+		g.ApplicationNames = nil
+		for _, app := range strings.Split(val, "|") {
+			app = strings.TrimSpace(app)
+			if strings.ContainsAny(app, resourceGroupSeparators) {
+				// The definition could not be parsed back.
+				return errors.Newf("application name %q cannot contain any of %q", app, resourceGroupSeparators)
+			}
+			if app != "" && !slices.Contains(g.ApplicationNames, app) {
+				g.ApplicationNames = append(g.ApplicationNames, app)
+			}
+		}
+	default:
+		err = errors.Newf("unknown attribute %q", key)
+	}
+	return err
+}
+
+// FormatResourceGroups returns the ResourceGroupsSetting value that defines
+// the given groups, ordered by name. Attributes with default values are
+// omitted. It is the inverse of ParseResourceGroups.
+func FormatResourceGroups(groups map[string]ResourceGroup) string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var buf strings.Builder
+	for i, name := range names {
+		g := groups[name]
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(name)
+		var attrs []string
+		if g.CPUWeight != 1 {
+			attrs = append(attrs, "cpu_weight="+strconv.FormatUint(uint64(g.CPUWeight), 10))
+		}
+		if g.IOWeight != 1 {
+			attrs = append(attrs, "io_weight="+strconv.FormatUint(uint64(g.IOWeight), 10))
+		}
+		if g.CPUBurst != 0 {
+			attrs = append(attrs, "cpu_burst="+strconv.FormatInt(g.CPUBurst, 10))
+		}
+		if g.IOBurst != 0 {
+			attrs = append(attrs, "io_burst="+strconv.FormatInt(g.IOBurst, 10))
+		}
+		if len(g.ApplicationNames) > 0 {
+			attrs = append(attrs, "applications="+strings.Join(g.ApplicationNames, "|"))
+		}
+		if len(attrs) > 0 {
+			buf.WriteString(": ")
+			buf.WriteString(strings.Join(attrs, ", "))
+		}
+	}
+	return buf.String()
+}
+
+func parseResourceGroupWeight(s string) (uint32, error) {
+	w, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || w == 0 || w > maxResourceGroupWeight {
+		return 0, errors.Newf("weight must be an integer in [1, %d], found %q", maxResourceGroupWeight, s)
+	}
+	return uint32(w), nil
+}
+
+func parseResourceGroupBurst(s string) (int64, error) {
+	b, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || b < 0 || b > maxResourceGroupBurst {
+		return 0, errors.Newf("burst must be an integer in [0, %d], found %q", int64(maxResourceGroupBurst), s)
+	}
+	return b, nil
+}
+
+// resourceGroups is a parsed ResourceGroupsSetting value.
+type resourceGroups struct {
+	spec   string
+	groups map[string]ResourceGroup
+	// apps maps application names to group names.
+	apps map[string]string
+}
+
+// lastResourceGroups caches the most recently parsed setting value, so that
+// the setting is only re-parsed when it changes.
+var lastResourceGroups atomic.Pointer[resourceGroups]
+
+func getResourceGroups(sv *settings.Values) *resourceGroups {
+	spec := ResourceGroupsSetting.Get(sv)
+	if rg := lastResourceGroups.Load(); rg != nil && rg.spec == spec {
+		return rg
+	}
+	groups, err := ParseResourceGroups(spec)
+	if err != nil {
+		// The setting is validated, so this should not happen. Behave as if no
+		// groups are defined.
+		groups = nil
+	}
+	rg := &resourceGroups{spec: spec, groups: groups, apps: make(map[string]string)}
+	for _, g := range groups {
+		for _, app := range g.ApplicationNames {
+			rg.apps[app] = g.Name
+		}
+	}
+	lastResourceGroups.Store(rg)
+	return rg
+}
+
+// get returns the definition of the named group, falling back to the default
+// group if the name is empty or not defined.
+func (rg *resourceGroups) get(name string) ResourceGroup {
+	if g, ok := rg.groups[name]; ok {
+		return g
+	}
+	if g, ok := rg.groups[DefaultResourceGroup]; ok {
+		return g
+	}
+	return NewResourceGroup(DefaultResourceGroup)
+}
+
+// LookupResourceGroup returns the definition of the named resource group,
+// falling back to the default group if the name is empty or not defined.
+func LookupResourceGroup(sv *settings.Values, name string) ResourceGroup {
+	return getResourceGroups(sv).get(name)
+}
+
+// ResourceGroupForApplication returns the resource group that sessions with
+// the given application name are assigned to, or the empty string if there is
+// none.
+func ResourceGroupForApplication(sv *settings.Values, appName string) string {
+	return getResourceGroups(sv).apps[appName]
+}
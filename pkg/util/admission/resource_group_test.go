@@ -0,0 +1,167 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceGroups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	groups, err := ParseResourceGroups(`
+		oltp: cpu_weight=8, io_weight=4, applications=web|api;
+		reporting: cpu_burst=16, io_burst=1048576, applications=metabase;
+		batch`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]ResourceGroup{
+		"oltp": {
+			Name: "oltp", CPUWeight: 8, IOWeight: 4, ApplicationNames: []string{"web", "api"},
+		},
+		"reporting": {
+			Name: "reporting", CPUWeight: 1, IOWeight: 1, CPUBurst: 16, IOBurst: 1048576,
+			ApplicationNames: []string{"metabase"},
+		},
+		"batch": {Name: "batch", CPUWeight: 1, IOWeight: 1},
+	}, groups)
+
+	// FormatResourceGroups is the inverse of ParseResourceGroups.
+	spec := FormatResourceGroups(groups)
+	require.Equal(t, "batch; oltp: cpu_weight=8, io_weight=4, applications=web|api; "+
+		"reporting: cpu_burst=16, io_burst=1048576, applications=metabase", spec)
+	reparsed, err := ParseResourceGroups(spec)
+	require.NoError(t, err)
+	require.Equal(t, groups, reparsed)
+
+	// Application names set through SetAttribute, as CREATE RESOURCE GROUP
+	// does, round-trip through the setting value.
+	g := NewResourceGroup("apps")
+	require.NoError(t, g.SetAttribute("applications", "my app| $ cockroach sql|a=b|"))
+	require.Equal(t, []string{"my app", "$ cockroach sql", "a=b"}, g.ApplicationNames)
+	groups = map[string]ResourceGroup{"apps": g}
+	reparsed, err = ParseResourceGroups(FormatResourceGroups(groups))
+	require.NoError(t, err)
+	require.Equal(t, groups, reparsed)
+	for _, app := range []string{"a;b", "a,b", "a:b"} {
+		require.ErrorContains(t, g.SetAttribute("applications", app), "cannot contain any of")
+	}
+
+	groups, err = ParseResourceGroups("")
+	require.NoError(t, err)
+	require.Empty(t, groups)
+
+	for _, tc := range []struct {
+		spec string
+		err  string
+	}{
+		{spec: "Bad-Name: cpu_weight=1", err: `invalid resource group name "Bad-Name"`},
+		{spec: "a; a", err: `resource group "a" defined more than once`},
+		{spec: "a: cpu_weight", err: `expected key=value, found "cpu_weight"`},
+		{spec: "a: cpu_weight=0", err: `weight must be an integer in [1, 1000]`},
+		{spec: "a: io_weight=1001", err: `weight must be an integer in [1, 1000]`},
+		{spec: "a: cpu_burst=-1", err: `burst must be an integer in [0,`},
+		{spec: "a: memory=1", err: `unknown attribute "memory"`},
+		{spec: "a: applications=x; b: applications=x", err: `application "x" assigned to both`},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			_, err := ParseResourceGroups(tc.spec)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestResourceGroupLookup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	st := cluster.MakeTestingClusterSettings()
+	require.NoError(t, ValidateResourceGroupName("oltp_2"))
+	require.Error(t, ValidateResourceGroupName("2oltp"))
+	require.Error(t, ValidateResourceGroupName(""))
+	require.Equal(t, NewResourceGroup(DefaultResourceGroup), getResourceGroups(&st.SV).get("oltp"))
+
+	ResourceGroupsSetting.Override(context.Background(), &st.SV,
+		"oltp: cpu_weight=4, applications=web; default: cpu_weight=2")
+	require.Equal(t, "oltp", ResourceGroupForApplication(&st.SV, "web"))
+	require.Equal(t, "", ResourceGroupForApplication(&st.SV, "psql"))
+	require.Equal(t, uint32(4), getResourceGroups(&st.SV).get("oltp").CPUWeight)
+	// Unknown groups use the overridden default group.
+	require.Equal(t, uint32(2), getResourceGroups(&st.SV).get("unknown").CPUWeight)
+	require.Equal(t, uint32(2), getResourceGroups(&st.SV).get("").CPUWeight)
+}
+
+// TestResourceGroupMetrics checks that the cardinality of the resource group
+// metrics is bounded.
+func TestResourceGroupMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	st := cluster.MakeTestingClusterSettings()
+	ResourceGroupsSetting.Override(context.Background(), &st.SV, "oltp")
+	q := &WorkQueue{settings: st}
+	require.Equal(t, "", q.resourceGroupForMetrics(WorkInfo{}))
+	require.Equal(t, "oltp", q.resourceGroupForMetrics(WorkInfo{ResourceGroup: "oltp"}))
+	require.Equal(t, DefaultResourceGroup, q.resourceGroupForMetrics(WorkInfo{ResourceGroup: "unknown"}))
+	// Definitions sent by tenants are not known to the node, but their names
+	// must be valid.
+	def := NewResourceGroup("tenant_group")
+	require.Equal(t, "tenant_group", q.resourceGroupForMetrics(WorkInfo{ResourceGroupDefinition: &def}))
+	def.Name = "Not A Name"
+	require.Equal(t, DefaultResourceGroup, q.resourceGroupForMetrics(WorkInfo{ResourceGroupDefinition: &def}))
+
+	m := makeResourceGroupMetrics("test")
+	for i := 0; i < 2*maxResourceGroupMetricsChildren; i++ {
+		m.get(fmt.Sprintf("group_%d", i)).admitted.Inc(1)
+	}
+	require.Len(t, m.mu.children, maxResourceGroupMetricsChildren+1)
+	require.Equal(t, int64(maxResourceGroupMetricsChildren), m.get(DefaultResourceGroup).admitted.Value())
+	require.Equal(t, int64(2*maxResourceGroupMetricsChildren), m.Admitted.Count())
+}
+
+// TestWaitingWorkHeapResourceGroupOrdering checks that the ordering of FIFO
+// work of the same priority across resource groups is transitive.
+func TestWaitingWorkHeapResourceGroupOrdering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var wwh waitingWorkHeap
+	for _, w := range []struct {
+		group      string
+		tag        int64
+		createTime int64
+	}{
+		{"a", 0, 3},
+		{"a", 1000, 1},
+		{"b", 500, 2},
+		{"b", 1500, 0},
+		{"c", 1000, 4},
+	} {
+		wwh = append(wwh, &waitingWork{
+			arrivalTimeWorkOrdering: fifoWorkOrdering,
+			resourceGroup:           w.group,
+			groupStartTag:           w.tag,
+			createTime:              w.createTime,
+		})
+	}
+	for i := range wwh {
+		require.False(t, wwh.Less(i, i))
+		for j := range wwh {
+			for k := range wwh {
+				if wwh.Less(i, j) && wwh.Less(j, k) {
+					require.True(t, wwh.Less(i, k), "%d < %d < %d", i, j, k)
+				}
+			}
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
@@ -187,6 +188,14 @@ type WorkInfo struct {
 	// ReplicatedWorkInfo groups everything needed to admit replicated writes, done
 	// so asynchronously below-raft as part of replication admission control.
 	ReplicatedWorkInfo ReplicatedWorkInfo
+	// ResourceGroup is the workload resource group of the work, if any. Work of
+	// different resource groups at the same priority within a tenant is
+	// admitted in proportion to the group weights, see ResourceGroupsSetting.
+	ResourceGroup string
+	// ResourceGroupDefinition is the definition of ResourceGroup as resolved by
+	// the tenant that issued the work, since resource groups are defined per
+	// tenant. If nil, the group is looked up in the settings of this node.
+	ResourceGroupDefinition *ResourceGroup
 }
 
 // ReplicatedWorkInfo groups everything needed to admit replicated writes, done
@@ -265,7 +274,10 @@ type WorkQueue struct {
 	usesTokens     bool
 	tiedToRange    bool
 	usesAsyncAdmit bool
-	settings       *cluster.Settings
+	// usesIOResourceShares is true if resource groups share this queue using
+	// their IO weights, and false if they use their CPU weights.
+	usesIOResourceShares bool
+	settings             *cluster.Settings
 
 	onAdmittedReplicatedWork onAdmittedReplicatedWork
 
@@ -305,9 +317,10 @@ type WorkQueue struct {
 var _ requester = &WorkQueue{}
 
 type workQueueOptions struct {
-	usesTokens     bool
-	tiedToRange    bool
-	usesAsyncAdmit bool
+	usesTokens           bool
+	tiedToRange          bool
+	usesAsyncAdmit       bool
+	usesIOResourceShares bool
 
 	// timeSource can be set to non-nil for tests. If nil,
 	// the timeutil.DefaultTimeSource will be used.
@@ -382,6 +395,7 @@ func initWorkQueue(
 	q.usesTokens = opts.usesTokens
 	q.tiedToRange = opts.tiedToRange
 	q.usesAsyncAdmit = opts.usesAsyncAdmit
+	q.usesIOResourceShares = opts.usesIOResourceShares
 	q.settings = settings
 	q.logThreshold = log.Every(5 * time.Minute)
 	q.metrics = metrics
@@ -669,6 +683,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 				)
 			}
 			q.metrics.recordFastPathAdmission(info.Priority)
+			q.metrics.recordResourceGroupAdmission(q.resourceGroupForMetrics(info), 0)
 			return true, nil
 		}
 		// Did not get token/slot.
@@ -728,6 +743,12 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	work.replicated = info.ReplicatedWorkInfo
 
 	inTenantHeap := isInTenantHeap(tenant)
+	if !inTenantHeap {
+		// The tenant has no waiting work, so there is no contention between its
+		// resource groups to remember.
+		tenant.resetResourceGroupsLocked()
+	}
+	q.assignResourceGroupTagLocked(tenant, work, info)
 	if work.epoch <= q.mu.closedEpochThreshold || ordering == fifoWorkOrdering {
 		heap.Push(&tenant.waitingWorkHeap, work)
 	} else {
@@ -815,6 +836,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		q.metrics.incAdmitted(info.Priority)
 		waitDur := q.timeNow().Sub(startTime)
 		q.metrics.recordFinishWait(info.Priority, waitDur)
+		q.metrics.recordResourceGroupAdmission(q.resourceGroupForMetrics(info), waitDur)
 		if work.heapIndex != -1 {
 			panic(errors.AssertionFailedf("grantee should be removed from heap"))
 		}
@@ -890,6 +912,9 @@ func (q *WorkQueue) granted(grantChainID grantChainID) int64 {
 	waitDur := now.Sub(item.enqueueingTime)
 	tenant.priorityStates.updateDelayLocked(item.priority, waitDur, false /* canceled */)
 	tenant.used += uint64(item.requestedCount)
+	if item.groupStartTag > tenant.groupVirtualTime {
+		tenant.groupVirtualTime = item.groupStartTag
+	}
 	if isInTenantHeap(tenant) {
 		q.mu.tenantHeap.fix(tenant)
 	} else {
@@ -1019,11 +1044,16 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, _ rune) {
 				if sortedWaitingWorkHeap[i].arrivalTimeWorkOrdering == lifoWorkOrdering {
 					workOrdering = ", lifo-ordering"
 				}
-				s.Printf(" [%d: pri: %d, ct: %d, epoch: %d, qt: %d%s]", i,
+				var resourceGroup string
+				if rg := sortedWaitingWorkHeap[i].resourceGroup; rg != DefaultResourceGroup {
+					resourceGroup = fmt.Sprintf(", rg: %s, tag: %d", rg, sortedWaitingWorkHeap[i].groupStartTag)
+				}
+				s.Printf(" [%d: pri: %d, ct: %d, epoch: %d, qt: %d%s%s]", i,
 					sortedWaitingWorkHeap[i].priority,
 					sortedWaitingWorkHeap[i].createTime/int64(time.Millisecond),
 					sortedWaitingWorkHeap[i].epoch,
-					sortedWaitingWorkHeap[i].enqueueingTime.UnixNano()/int64(time.Millisecond), workOrdering)
+					sortedWaitingWorkHeap[i].enqueueingTime.UnixNano()/int64(time.Millisecond), workOrdering,
+					redact.SafeString(resourceGroup))
 			}
 		}
 		if len(tenant.openEpochsHeap) > 0 {
@@ -1064,6 +1094,79 @@ func (q *WorkQueue) getTenantWeightLocked(tenantID uint64) uint32 {
 	return weight
 }
 
+// resourceGroupTagScale scales the cost of work divided by the weight of its
+// resource group, so that small costs with large weights still advance the
+// virtual time.
+const resourceGroupTagScale = 1000
+
+// assignResourceGroupTagLocked assigns the resource group and start tag of
+// work that is about to be queued for the tenant. Resource groups share the
+// tenant's admission grants using start-time fair queueing: the start tag of
+// the work is the maximum of the tenant's virtual time and the finish tag of
+// the previous work of the same group, and the finish tag is the start tag
+// plus the cost of the work divided by the weight of the group. A group that
+// was idle starts slightly behind the virtual time, as allowed by its burst.
+//
+// If no resource groups are defined, all work is in the default group with a
+// start tag of 0, so that the ordering of the work is unaffected.
+func (q *WorkQueue) assignResourceGroupTagLocked(
+	tenant *tenantInfo, work *waitingWork, info WorkInfo,
+) {
+	group, ok := q.resourceGroup(info)
+	if !ok {
+		work.resourceGroup = DefaultResourceGroup
+		work.groupStartTag = 0
+		return
+	}
+	weight, burst := group.weightAndBurst(q.usesIOResourceShares)
+	start := tenant.groupVirtualTime - burst*resourceGroupTagScale/int64(weight)
+	if start < 0 {
+		start = 0
+	}
+	if finish, ok := tenant.groupFinishTags[group.Name]; ok && finish > start {
+		start = finish
+	}
+	if tenant.groupFinishTags == nil {
+		tenant.groupFinishTags = make(map[string]int64)
+	}
+	tenant.groupFinishTags[group.Name] = start + work.requestedCount*resourceGroupTagScale/int64(weight)
+	work.resourceGroup = group.Name
+	work.groupStartTag = start
+}
+
+// resourceGroup returns the definition of the resource group of the work,
+// preferring the definition carried by the work. It returns false if the work
+// carries no definition and no resource groups are defined on this node.
+func (q *WorkQueue) resourceGroup(info WorkInfo) (ResourceGroup, bool) {
+	if info.ResourceGroupDefinition != nil {
+		return *info.ResourceGroupDefinition, true
+	}
+	rg := getResourceGroups(&q.settings.SV)
+	if len(rg.groups) == 0 {
+		return ResourceGroup{}, false
+	}
+	return rg.get(info.ResourceGroup), true
+}
+
+// resourceGroupForMetrics returns the name of the resource group that the work
+// is accounted to in the metrics, or the empty string for untagged work. Tags
+// of undefined groups, and definitions with invalid names, are accounted to
+// the default group, to bound the cardinality of the metrics.
+func (q *WorkQueue) resourceGroupForMetrics(info WorkInfo) string {
+	if def := info.ResourceGroupDefinition; def != nil {
+		// The definition comes from the tenant of the work, whose groups are
+		// not known to this node.
+		if ValidateResourceGroupName(def.Name) != nil {
+			return DefaultResourceGroup
+		}
+		return def.Name
+	}
+	if info.ResourceGroup == "" {
+		return ""
+	}
+	return getResourceGroups(&q.settings.SV).get(info.ResourceGroup).Name
+}
+
 // SetTenantWeights sets the weight of tenants, using the provided tenant ID
 // => weight map. A nil map will result in all tenants having the same weight.
 func (q *WorkQueue) SetTenantWeights(tenantWeights map[uint64]uint32) {
@@ -1341,11 +1444,25 @@ type tenantInfo struct {
 	// than WorkPriority since the threshold can be > MaxPri.
 	fifoPriorityThreshold int
 
+	// groupVirtualTime and groupFinishTags are used for start-time fair
+	// queueing across the resource groups of the tenant, see
+	// assignResourceGroupTagLocked. groupVirtualTime is the largest start tag
+	// of admitted work, and groupFinishTags is the finish tag of the last
+	// queued work of each group. Both are reset when the tenant has no waiting
+	// work.
+	groupVirtualTime int64
+	groupFinishTags  map[string]int64
+
 	// The heapIndex is maintained by the heap.Interface methods, and represents
 	// the heapIndex of the item in the heap.
 	heapIndex int
 }
 
+func (ti *tenantInfo) resetResourceGroupsLocked() {
+	ti.groupVirtualTime = 0
+	clear(ti.groupFinishTags)
+}
+
 // tenantHeap is a heap of tenants with waiting work, ordered in increasing
 // order of tenantInfo.used/tenantInfo.weight (weights are an optional
 // feature, and default to 1). That is, we prefer tenants that are using less.
@@ -1367,6 +1484,7 @@ func newTenantInfo(id uint64, weight uint32) *tenantInfo {
 		waitingWorkHeap:       ti.waitingWorkHeap,
 		openEpochsHeap:        ti.openEpochsHeap,
 		priorityStates:        makePriorityStates(ti.priorityStates.ps),
+		groupFinishTags:       ti.groupFinishTags,
 		fifoPriorityThreshold: int(admissionpb.LowPri),
 		heapIndex:             -1,
 	}
@@ -1386,10 +1504,12 @@ func releaseTenantInfo(ti *tenantInfo) {
 		ti.openEpochsHeap = nil
 	}
 
+	clear(ti.groupFinishTags)
 	*ti = tenantInfo{
 		waitingWorkHeap: ti.waitingWorkHeap,
 		openEpochsHeap:  ti.openEpochsHeap,
 		priorityStates:  makePriorityStates(ti.priorityStates.ps),
+		groupFinishTags: ti.groupFinishTags,
 	}
 	tenantInfoPool.Put(ti)
 }
@@ -1467,6 +1587,10 @@ type waitingWork struct {
 	inWaitingWorkHeap bool
 	enqueueingTime    time.Time
 	replicated        ReplicatedWorkInfo
+	// resourceGroup is the resource group of the work, and groupStartTag its
+	// start tag for fair queueing across resource groups.
+	resourceGroup string
+	groupStartTag int64
 }
 
 var waitingWorkPool = sync.Pool{
@@ -1597,6 +1721,13 @@ func (wwh *waitingWorkHeap) Len() int { return len(*wwh) }
 // to LIFO we will need to wait for those old queued items to be serviced
 // first, which will delay the transition.
 //
+// Work with the same priority is first ordered by the start tag assigned for
+// fair queueing across resource groups, and only falls back to the createTime
+// ordering on equal tags. Start tags increase with the arrival of work within
+// a group, so when resource groups are defined, work within a group is
+// admitted in FIFO order. When no groups are defined all start tags are 0,
+// and the ordering is as described above.
+//
 // Less is not strict weak ordering since the transitivity property is not
 // satisfied in the presence of elements that have different values of
 // arrivalTimeWorkOrdering. This is acceptable for heap maintenance.
@@ -1608,6 +1739,9 @@ func (wwh *waitingWorkHeap) Len() int { return len(*wwh) }
 //	w1 < w3, w3 < w2, w2 < w1, which is a cycle.
 func (wwh *waitingWorkHeap) Less(i, j int) bool {
 	if (*wwh)[i].priority == (*wwh)[j].priority {
+		if (*wwh)[i].groupStartTag != (*wwh)[j].groupStartTag {
+			return (*wwh)[i].groupStartTag < (*wwh)[j].groupStartTag
+		}
 		if (*wwh)[i].arrivalTimeWorkOrdering == lifoWorkOrdering ||
 			(*wwh)[i].arrivalTimeWorkOrdering != (*wwh)[j].arrivalTimeWorkOrdering {
 			// LIFO, and the epoch is closed, so can simply use createTime.
@@ -1752,6 +1886,18 @@ var (
 		Measurement: "Requests",
 		Unit:        metric.Unit_COUNT,
 	}
+	resourceGroupAdmittedMeta = metric.Metadata{
+		Name:        "admission.resource_group.admitted.",
+		Help:        "Number of requests tagged with a resource group admitted, by resource group",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	resourceGroupWaitNanosMeta = metric.Metadata{
+		Name:        "admission.resource_group.wait_nanos.",
+		Help:        "Total wait time of admitted requests tagged with a resource group, by resource group",
+		Measurement: "Wait time Duration",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 )
 
 func addName(name string, meta metric.Metadata) metric.Metadata {
@@ -1764,10 +1910,64 @@ func addName(name string, meta metric.Metadata) metric.Metadata {
 // shared across WorkQueues, so Gauges should only be updated using deltas
 // instead of by setting values.
 type WorkQueueMetrics struct {
-	name       string
-	total      *workQueueMetricsSingle
-	byPriority syncutil.Map[admissionpb.WorkPriority, workQueueMetricsSingle]
-	registry   *metric.Registry
+	name           string
+	total          *workQueueMetricsSingle
+	byPriority     syncutil.Map[admissionpb.WorkPriority, workQueueMetricsSingle]
+	resourceGroups *resourceGroupMetrics
+	registry       *metric.Registry
+}
+
+// resourceGroupMetrics are the metrics of a WorkQueue broken down by resource
+// group. Only work tagged with a resource group is counted.
+type resourceGroupMetrics struct {
+	Admitted  *aggmetric.AggCounter
+	WaitNanos *aggmetric.AggCounter
+
+	mu struct {
+		syncutil.Mutex
+		children map[string]resourceGroupChildMetrics
+	}
+}
+
+type resourceGroupChildMetrics struct {
+	admitted  *aggmetric.Counter
+	waitNanos *aggmetric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*resourceGroupMetrics) MetricStruct() {}
+
+func makeResourceGroupMetrics(name string) *resourceGroupMetrics {
+	m := &resourceGroupMetrics{
+		Admitted:  aggmetric.NewCounter(addName(name, resourceGroupAdmittedMeta), "resource_group"),
+		WaitNanos: aggmetric.NewCounter(addName(name, resourceGroupWaitNanosMeta), "resource_group"),
+	}
+	m.mu.children = make(map[string]resourceGroupChildMetrics)
+	return m
+}
+
+// maxResourceGroupMetricsChildren bounds the number of resource groups that
+// have their own metrics in a WorkQueue. The groups are defined by each
+// tenant, the work of groups beyond the limit is accounted to the default
+// group.
+const maxResourceGroupMetricsChildren = 64
+
+func (m *resourceGroupMetrics) get(group string) resourceGroupChildMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	child, ok := m.mu.children[group]
+	if !ok && group != DefaultResourceGroup && len(m.mu.children) >= maxResourceGroupMetricsChildren {
+		group = DefaultResourceGroup
+		child, ok = m.mu.children[group]
+	}
+	if !ok {
+		child = resourceGroupChildMetrics{
+			admitted:  m.Admitted.AddChild(group),
+			waitNanos: m.WaitNanos.AddChild(group),
+		}
+		m.mu.children[group] = child
+	}
+	return child
 }
 
 // getOrCreate will return the metric if it exists or create it and then return
@@ -1830,6 +2030,17 @@ func (m *WorkQueueMetrics) recordFinishWait(priority admissionpb.WorkPriority, d
 	priorityStats.WaitDurations.RecordValue(dur.Nanoseconds())
 }
 
+// recordResourceGroupAdmission records the admission of work tagged with the
+// given resource group, after waiting for waitDur.
+func (m *WorkQueueMetrics) recordResourceGroupAdmission(group string, waitDur time.Duration) {
+	if group == "" {
+		return
+	}
+	child := m.resourceGroups.get(group)
+	child.admitted.Inc(1)
+	child.waitNanos.Inc(waitDur.Nanoseconds())
+}
+
 func (m *WorkQueueMetrics) recordBypassedAdmission(priority admissionpb.WorkPriority) {
 	// For work that either bypasses admission queues (because of the nature of
 	// the work itself or because certain queues are disabled), we'll explicit
@@ -1858,10 +2069,13 @@ func makeWorkQueueMetrics(
 ) *WorkQueueMetrics {
 	totalMetric := makeWorkQueueMetricsSingle(name)
 	registry.AddMetricStruct(totalMetric)
+	resourceGroups := makeResourceGroupMetrics(name)
+	registry.AddMetricStruct(resourceGroups)
 	wqm := &WorkQueueMetrics{
-		name:     name,
-		total:    totalMetric,
-		registry: registry,
+		name:           name,
+		total:          totalMetric,
+		resourceGroups: resourceGroups,
+		registry:       registry,
 	}
 	// TODO(abaptist): This is done to pre-register stats. Need to check that we
 	// getOrCreate "enough" of the priorities to be useful. See
@@ -2273,6 +2487,7 @@ func makeStoreWorkQueue(
 	}
 
 	opts.usesAsyncAdmit = true
+	opts.usesIOResourceShares = true
 	for i := range q.q {
 		var queueKind QueueKind
 		if i == int(admissionpb.RegularWorkClass) {
@@ -225,6 +225,16 @@ func TestWorkQueueBasic(t *testing.T) {
 					CreateTime:      int64(createTime) * int64(time.Millisecond),
 					BypassAdmission: bypass,
 				}
+				if d.HasArg("resource-group") {
+					d.ScanArgs(t, "resource-group", &workInfo.ResourceGroup)
+				}
+				if d.HasArg("resource-group-cpu-weight") {
+					// The definition of the group is carried by the work, as it is for
+					// work issued by a tenant.
+					g := ResourceGroup{Name: workInfo.ResourceGroup, IOWeight: 1}
+					d.ScanArgs(t, "resource-group-cpu-weight", &g.CPUWeight)
+					workInfo.ResourceGroupDefinition = &g
+				}
 				go func(ctx context.Context, info WorkInfo, id int) {
 					enabled, err := q.Admit(ctx, info)
 					require.True(t, enabled)
@@ -313,6 +323,12 @@ func TestWorkQueueBasic(t *testing.T) {
 				q.SetTenantWeights(weightMap)
 				return q.String()
 
+			case "set-resource-groups":
+				var groups string
+				d.ScanArgs(t, "groups", &groups)
+				ResourceGroupsSetting.Override(context.Background(), &st.SV, groups)
+				return ""
+
 			case "print":
 				// Need deterministic output, and this is racing with the goroutine
 				// whose work is canceled. Retry to let it get scheduled.